@@ -2,5 +2,5 @@ package sql
 
 import "embed"
 
-//go:embed mssql mysql oracle postgresql sqlite3
+//go:embed cassandra mssql mysql oracle postgresql sqlite3
 var FS embed.FS
@@ -18,12 +18,18 @@ package quickfix
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
+	"errors"
+	"fmt"
 	"io"
 	"net"
 	"runtime/debug"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	proxyproto "github.com/pires/go-proxyproto"
 
@@ -32,23 +38,51 @@ import (
 
 // Acceptor accepts connections from FIX clients and manages the associated sessions.
 type Acceptor struct {
-	app                   Application
-	settings              *Settings
-	logFactory            LogFactory
-	storeFactory          MessageStoreFactory
-	globalLog             Log
-	sessions              map[SessionID]*session
-	sessionGroup          sync.WaitGroup
-	listenerShutdown      sync.WaitGroup
-	dynamicSessions       bool
-	dynamicQualifier      bool
-	dynamicQualifierCount int
-	dynamicSessionChan    chan *session
-	sessionAddr           sync.Map
-	sessionHostPort       map[SessionID]int
-	listeners             map[string]net.Listener
-	connectionValidator   ConnectionValidator
-	tlsConfig             *tls.Config
+	app                      Application
+	settings                 *Settings
+	logFactory               LogFactory
+	storeFactory             MessageStoreFactory
+	globalLog                Log
+	sessions                 map[SessionID]*session
+	sessionsLock             sync.RWMutex
+	sessionDone              map[SessionID]chan struct{}
+	sessionGroup             sync.WaitGroup
+	reloadLock               sync.Mutex
+	listenerShutdown         sync.WaitGroup
+	dynamicSessions          bool
+	dynamicQualifier         bool
+	dynamicQualifierCount    int
+	dynamicSessionChan       chan *session
+	liveDynamicSessions      sync.Map
+	running                  atomic.Bool
+	qualifierTemplate        string
+	sessionQualifier         SessionQualifier
+	sessionResolver          SessionResolver
+	templates                []sessionTemplate
+	templateHandler          SessionTemplateHandler
+	sessionAddr              sync.Map
+	sessionListener          map[SessionID]string
+	listeners                map[string]net.Listener
+	connectionValidator      ConnectionValidator
+	allowedAddresses         map[SessionID][]*net.IPNet
+	dynamicAllowedAddresses  []*net.IPNet
+	listener                 Listener
+	authenticator            Authenticator
+	stateListener            SessionStateListener
+	tracer                   Tracer
+	events                   *EventBus
+	latencyObserver          LatencyObserver
+	dispatcher               *Dispatcher
+	validationPolicy         *ValidationPolicy
+	inboundMiddleware        []InboundMiddleware
+	outboundMiddleware       []OutboundMiddleware
+	clock                    Clock
+	eventLoopShards          int
+	eventLoops               *eventLoopGroup
+	sendLogoutOnReject       bool
+	maxMessageSize           int
+	maxInboundBytesPerSecond int
+	tlsConfig                *tls.Config
 	sessionFactory
 }
 
@@ -59,102 +93,412 @@ type ConnectionValidator interface {
 	Validate(netConn net.Conn, session SessionID) error
 }
 
+// Listener is an interface allowing an Acceptor to accept connections over a custom transport.
+type Listener interface {
+	// Listen returns a net.Listener accepting connections for address, from SocketAcceptHost and SocketAcceptPort.
+	// The Acceptor closes it on Stop.
+	Listen(address string) (net.Listener, error)
+}
+
+// SessionQualifier is an interface allowing an acceptor to choose the Qualifier of a dynamically created session,
+// so that several sessions with the same comp IDs can coexist.
+type SessionQualifier interface {
+	// Qualifier returns the Qualifier of the session for sessionID, which has no Qualifier, that logon is received for.
+	Qualifier(sessionID SessionID, logon *Message) string
+}
+
+// qualifierTemplateFields are the placeholders of the DynamicQualifierTemplate setting.
+var qualifierTemplateFields = []struct {
+	placeholder string
+	field       func(SessionID) string
+}{
+	{"{BeginString}", func(id SessionID) string { return id.BeginString }},
+	{"{SenderCompID}", func(id SessionID) string { return id.SenderCompID }},
+	{"{SenderSubID}", func(id SessionID) string { return id.SenderSubID }},
+	{"{SenderLocationID}", func(id SessionID) string { return id.SenderLocationID }},
+	{"{TargetCompID}", func(id SessionID) string { return id.TargetCompID }},
+	{"{TargetSubID}", func(id SessionID) string { return id.TargetSubID }},
+	{"{TargetLocationID}", func(id SessionID) string { return id.TargetLocationID }},
+}
+
+func expandQualifierTemplate(template string, sessID SessionID) string {
+	oldnew := make([]string, 0, 2*len(qualifierTemplateFields))
+	for _, f := range qualifierTemplateFields {
+		oldnew = append(oldnew, f.placeholder, f.field(sessID))
+	}
+
+	return strings.NewReplacer(oldnew...).Replace(template)
+}
+
+// qualifyDynamicSession returns sessID with the Qualifier chosen by the SessionQualifier or DynamicQualifierTemplate,
+// if either is set.
+func (a *Acceptor) qualifyDynamicSession(sessID SessionID, logon *Message) SessionID {
+	switch {
+	case a.sessionQualifier != nil:
+		sessID.Qualifier = ""
+		sessID.Qualifier = a.sessionQualifier.Qualifier(sessID, logon)
+	case a.qualifierTemplate != "":
+		sessID.Qualifier = expandQualifierTemplate(a.qualifierTemplate, sessID)
+	}
+
+	return sessID
+}
+
 // Start accepting connections.
 func (a *Acceptor) Start() (err error) {
-	socketAcceptHost := ""
-	if a.settings.GlobalSettings().HasSetting(config.SocketAcceptHost) {
-		if socketAcceptHost, err = a.settings.GlobalSettings().Setting(config.SocketAcceptHost); err != nil {
+	a.sessionsLock.Lock()
+	defer a.sessionsLock.Unlock()
+
+	var listenerSettings map[string]*SessionSettings
+	if a.sessionListener, listenerSettings, err = acceptorListeners(a.settings.SessionSettings()); err != nil {
+		return
+	}
+
+	a.listeners = make(map[string]net.Listener)
+	for address, settings := range listenerSettings {
+		if a.listeners[address], err = a.listen(address, settings); err != nil {
 			return
 		}
 	}
 
-	a.sessionHostPort = make(map[SessionID]int)
-	a.listeners = make(map[string]net.Listener)
-	for sessionID, sessionSettings := range a.settings.SessionSettings() {
-		if sessionSettings.HasSetting(config.SocketAcceptPort) {
-			if a.sessionHostPort[sessionID], err = sessionSettings.IntSetting(config.SocketAcceptPort); err != nil {
-				return
-			}
-		} else if a.sessionHostPort[sessionID], err = a.settings.GlobalSettings().IntSetting(config.SocketAcceptPort); err != nil {
+	if a.dispatcher != nil {
+		a.dispatcher.start()
+	}
+	if a.eventLoopShards > 0 {
+		a.eventLoops = newEventLoopGroup(a.eventLoopShards, a.clock)
+	}
+	for _, s := range a.sessions {
+		if err = s.reopenStore(a.storeFactory); err != nil {
+			return
+		}
+		s.stopOnce = sync.Once{}
+		a.configureSession(s)
+		a.runSession(s)
+	}
+	// Sessions created from templates, which ReloadSettings may add, run as dynamic sessions.
+	a.dynamicSessionChan = make(chan *session)
+	a.sessionGroup.Add(1)
+	go func() {
+		a.dynamicSessionsLoop()
+		a.sessionGroup.Done()
+	}()
+	for address, listener := range a.listeners {
+		a.listenerShutdown.Add(1)
+		go a.listenForConnections(address, listener)
+	}
+	a.running.Store(true)
+	return
+}
+
+// acceptorListeners returns the addresses sessions are accepted on, by SessionID without Qualifier, and the settings
+// of the listener of each address.
+func acceptorListeners(sessionSettings map[SessionID]*SessionSettings) (
+	sessionListener map[SessionID]string, listenerSettings map[string]*SessionSettings, err error) {
+	sessionListener = make(map[SessionID]string)
+	listenerSettings = make(map[string]*SessionSettings)
+	for sessionID, settings := range sessionSettings {
+		var address string
+		if address, err = acceptAddress(settings); err != nil {
 			return
 		}
-		address := net.JoinHostPort(socketAcceptHost, strconv.Itoa(a.sessionHostPort[sessionID]))
-		a.listeners[address] = nil
+
+		if shared, ok := listenerSettings[address]; !ok {
+			listenerSettings[address] = settings
+		} else if setting, ok := conflictingListenerSetting(shared, settings); !ok {
+			err = fmt.Errorf("sessions listening on %v have different values for %v", address, setting)
+			return
+		}
+
+		sessID := sessionID
+		sessID.Qualifier = ""
+		sessionListener[sessID] = address
+	}
+
+	return
+}
+
+// configureSession sets the handlers of the Acceptor on s.
+func (a *Acceptor) configureSession(s *session) {
+	s.authenticator = a.authenticator
+	s.stateListener = a.stateListener
+	s.tracer = a.tracer
+	s.events = a.events
+	s.latencyObserver = a.latencyObserver
+	s.dispatcher = a.dispatcher
+	s.validationPolicy.merge(a.validationPolicy)
+	s.useMiddleware(a.inboundMiddleware, a.outboundMiddleware)
+	if a.clock != nil {
+		s.clock = a.clock
+	}
+	s.loop = nil
+	if a.eventLoops != nil {
+		s.loop = a.eventLoops.loop(s.sessionID)
+	}
+}
+
+// runSession runs the configured session s until it is stopped. It must be called with sessionsLock held.
+func (a *Acceptor) runSession(s *session) {
+	done := make(chan struct{})
+	a.sessionDone[s.sessionID] = done
+	a.sessionGroup.Add(1)
+	if s.loop != nil {
+		s.loop.start(s, func() {
+			close(done)
+			a.sessionGroup.Done()
+		})
+		return
+	}
+	go func() {
+		s.run()
+		close(done)
+		a.sessionGroup.Done()
+	}()
+}
+
+// stopSession logs out the configured session s, waiting for it to stop if it is running, and unregisters it. If
+// drain is true, messages queued for sending are sent before the Logout. It must be called after s is removed from
+// the sessions of the Acceptor, without sessionsLock held.
+func (a *Acceptor) stopSession(s *session, drain bool) error {
+	return errors.Join(a.haltSession(s, drain), UnregisterSession(s.sessionID))
+}
+
+// haltSession logs out the configured session s and waits for it to stop if it is running, leaving it registered.
+// It must be called without sessionsLock held.
+func (a *Acceptor) haltSession(s *session, drain bool) (err error) {
+	a.sessionsLock.Lock()
+	done, running := a.sessionDone[s.sessionID]
+	delete(a.sessionDone, s.sessionID)
+	a.sessionsLock.Unlock()
+
+	if running {
+		if drain {
+			err = s.logoutAndDrain(s.LogoutTimeout)
+		}
+		s.stop()
+		<-done
+	}
+	flushLog(s.log)
+
+	return err
+}
+
+// acceptAddress returns the address a session is accepted on, from SocketAcceptHost and SocketAcceptPort.
+func acceptAddress(settings *SessionSettings) (string, error) {
+	host := ""
+	if settings.HasSetting(config.SocketAcceptHost) {
+		var err error
+		if host, err = settings.Setting(config.SocketAcceptHost); err != nil {
+			return "", err
+		}
+	}
+
+	// Unix sockets and pipes have no port, all sessions on the host share the one listener.
+	if isLocalTransport(host) {
+		return host, nil
+	}
+
+	port, err := settings.IntSetting(config.SocketAcceptPort)
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
+
+// listenerSettingNames are the settings applied to a listener rather than a session,
+// which must agree for all sessions accepted on the same address.
+var listenerSettingNames = []string{
+	config.SocketUseSSL,
+	config.SocketPrivateKeyFile,
+	config.SocketCertificateFile,
+	config.SocketPrivateKeyBytes,
+	config.SocketCertificateBytes,
+	config.SocketCAFile,
+	config.SocketCABytes,
+	config.SocketInsecureSkipVerify,
+	config.SocketMinimumTLSVersion,
+	config.SocketCipherSuites,
+	config.SocketClientAuth,
+	config.SocketCertificateReload,
+	config.SocketNoDelay,
+	config.SocketKeepAlive,
+	config.SocketKeepAliveInterval,
+	config.SocketKeepAliveCount,
+	config.SocketSendBufferSize,
+	config.SocketReceiveBufferSize,
+	config.SocketReusePort,
+	config.UseTCPProxy,
+	config.RequireTCPProxyHeader,
+	config.TCPProxyTrustedAddresses,
+}
+
+// conflictingListenerSetting returns the first listener setting with different values in s1 and s2, and false if there is one.
+func conflictingListenerSetting(s1, s2 *SessionSettings) (string, bool) {
+	for _, setting := range listenerSettingNames {
+		if s1.HasSetting(setting) != s2.HasSetting(setting) {
+			return setting, false
+		}
+
+		v1, _ := s1.Setting(setting)
+		v2, _ := s2.Setting(setting)
+		if v1 != v2 {
+			return setting, false
+		}
 	}
 
-	if a.tlsConfig == nil {
-		var tlsConfig *tls.Config
-		if tlsConfig, err = loadTLSConfig(a.settings.GlobalSettings()); err != nil {
+	return "", true
+}
+
+// listen opens the listener for address, with the TLS, TCP and PROXY protocol settings of the sessions accepted on it.
+func (a *Acceptor) listen(address string, settings *SessionSettings) (listener net.Listener, err error) {
+	tlsConfig := a.tlsConfig
+	if tlsConfig == nil {
+		if tlsConfig, err = loadTLSConfig(settings); err != nil {
 			return
 		}
-		a.tlsConfig = tlsConfig
+	}
+
+	var tcpOptions tcpOptions
+	if tcpOptions, err = loadTCPOptions(settings); err != nil {
+		return
 	}
 
 	var useTCPProxy bool
-	if a.settings.GlobalSettings().HasSetting(config.UseTCPProxy) {
-		if useTCPProxy, err = a.settings.GlobalSettings().BoolSetting(config.UseTCPProxy); err != nil {
+	if settings.HasSetting(config.UseTCPProxy) {
+		if useTCPProxy, err = settings.BoolSetting(config.UseTCPProxy); err != nil {
 			return
 		}
 	}
 
-	for address := range a.listeners {
-		if a.tlsConfig != nil {
-			if a.listeners[address], err = tls.Listen("tcp", address, a.tlsConfig); err != nil {
-				return
-			}
-		} else if a.listeners[address], err = net.Listen("tcp", address); err != nil {
+	var tcpProxyPolicy proxyproto.PolicyFunc
+	if useTCPProxy {
+		if tcpProxyPolicy, err = loadTCPProxyPolicy(settings); err != nil {
 			return
-		} else if useTCPProxy {
-			a.listeners[address] = &proxyproto.Listener{Listener: a.listeners[address]}
 		}
 	}
 
-	for _, s := range a.sessions {
-		a.sessionGroup.Add(1)
-		go func(s *session) {
-			s.run()
-			a.sessionGroup.Done()
-		}(s)
+	if a.listener != nil {
+		listener, err = a.listener.Listen(address)
+	} else {
+		listener, err = listenNetwork(address, tcpOptions)
 	}
-	if a.dynamicSessions {
-		a.dynamicSessionChan = make(chan *session)
-		a.sessionGroup.Add(1)
-		go func() {
-			a.dynamicSessionsLoop()
-			a.sessionGroup.Done()
-		}()
+	if err != nil {
+		return
 	}
-	a.listenerShutdown.Add(len(a.listeners))
-	for _, listener := range a.listeners {
-		go a.listenForConnections(listener)
+
+	// The PROXY header precedes the TLS handshake.
+	if useTCPProxy {
+		listener = &proxyproto.Listener{Listener: listener, Policy: tcpProxyPolicy}
+	}
+
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
 	}
+
 	return
 }
 
 // Stop logs out existing sessions, close their connections, and stop accepting new connections.
 func (a *Acceptor) Stop() {
+	_ = a.StopWithContext(context.Background())
+}
+
+// StopWithContext stops accepting new connections and logs out the sessions of the Acceptor. Logged on sessions send
+// the messages already queued and a Logout, and are disconnected once the counterparty confirms it, or once their
+// LogoutTimeout passes or ctx is done. The logs of the sessions are then flushed and their MessageStores closed, to be
+// created again by the MessageStoreFactory if Start is called again.
+// Returns the errors of the sessions that did not complete the logout or failed to close their store, joined.
+func (a *Acceptor) StopWithContext(ctx context.Context) (err error) {
 	defer func() {
 		_ = recover() // suppress sending on closed channel error
 	}()
+	a.running.Store(false)
 
+	a.sessionsLock.Lock()
+	listeners := make([]net.Listener, 0, len(a.listeners))
 	for _, listener := range a.listeners {
+		listeners = append(listeners, listener)
+	}
+	sessions := make(map[SessionID]*session, len(a.sessions))
+	for sessionID, session := range a.sessions {
+		sessions[sessionID] = session
+	}
+	running := make([]*session, 0, len(a.sessionDone))
+	for _, session := range a.sessions {
+		if _, ok := a.sessionDone[session.sessionID]; ok {
+			running = append(running, session)
+			delete(a.sessionDone, session.sessionID)
+		}
+	}
+	a.sessionsLock.Unlock()
+
+	for _, listener := range listeners {
 		listener.Close()
 	}
 	a.listenerShutdown.Wait()
-	if a.dynamicSessions {
+
+	errs := stopSessions(running, func(s *session) error { return s.logoutAndDrainContext(ctx) })
+
+	if a.dynamicSessionChan != nil {
 		close(a.dynamicSessionChan)
 	}
-	for _, session := range a.sessions {
+	for _, session := range sessions {
 		session.stop()
 	}
 	a.sessionGroup.Wait()
+	if a.dispatcher != nil {
+		a.dispatcher.stop()
+	}
+	a.eventLoops.stop()
 
-	for sessionID := range a.sessions {
-		err := UnregisterSession(sessionID)
-		if err != nil {
-			return
+	for _, session := range sessions {
+		flushLog(session.log)
+		if err := session.closeStore(); err != nil {
+			errs = append(errs, fmt.Errorf("session %v: %w", session.sessionID, err))
+		}
+	}
+	flushLog(a.globalLog)
+
+	for _, session := range sessions {
+		if err := UnregisterSession(session.sessionID); err != nil {
+			break
 		}
 	}
+
+	return errors.Join(errs...)
+}
+
+// Health returns the status of the Acceptor and of its sessions, including connected dynamic sessions.
+func (a *Acceptor) Health() Health {
+	a.sessionsLock.RLock()
+	sessions := make([]*session, 0, len(a.sessions))
+	for _, s := range a.sessions {
+		sessions = append(sessions, s)
+	}
+	a.sessionsLock.RUnlock()
+	a.liveDynamicSessions.Range(func(_, s any) bool {
+		sessions = append(sessions, s.(*session))
+		return true
+	})
+
+	return newHealth(a.running.Load(), sessions)
+}
+
+// Compact compacts the message stores of the sessions of the Acceptor, including connected dynamic sessions,
+// that implement Compactor.
+func (a *Acceptor) Compact() error {
+	a.sessionsLock.RLock()
+	defer a.sessionsLock.RUnlock()
+
+	sessions := make([]*session, 0, len(a.sessions))
+	for _, s := range a.sessions {
+		sessions = append(sessions, s)
+	}
+	a.liveDynamicSessions.Range(func(_, s any) bool {
+		sessions = append(sessions, s.(*session))
+		return true
+	})
+	return compactSessions(sessions)
 }
 
 // RemoteAddr gets remote IP address for a given session.
@@ -170,13 +514,15 @@ func (a *Acceptor) RemoteAddr(sessionID SessionID) (net.Addr, bool) {
 // NewAcceptor creates and initializes a new Acceptor.
 func NewAcceptor(app Application, storeFactory MessageStoreFactory, settings *Settings, logFactory LogFactory) (a *Acceptor, err error) {
 	a = &Acceptor{
-		app:             app,
-		storeFactory:    storeFactory,
-		settings:        settings,
-		logFactory:      logFactory,
-		sessions:        make(map[SessionID]*session),
-		sessionHostPort: make(map[SessionID]int),
-		listeners:       make(map[string]net.Listener),
+		app:              app,
+		storeFactory:     storeFactory,
+		settings:         settings,
+		logFactory:       logFactory,
+		sessions:         make(map[SessionID]*session),
+		sessionDone:      make(map[SessionID]chan struct{}),
+		sessionListener:  make(map[SessionID]string),
+		listeners:        make(map[string]net.Listener),
+		allowedAddresses: make(map[SessionID][]*net.IPNet),
 	}
 	if a.settings.GlobalSettings().HasSetting(config.DynamicSessions) {
 		if a.dynamicSessions, err = settings.globalSettings.BoolSetting(config.DynamicSessions); err != nil {
@@ -188,13 +534,53 @@ func NewAcceptor(app Application, storeFactory MessageStoreFactory, settings *Se
 				return
 			}
 		}
+
+		if a.settings.GlobalSettings().HasSetting(config.DynamicQualifierTemplate) {
+			if a.qualifierTemplate, err = settings.globalSettings.Setting(config.DynamicQualifierTemplate); err != nil {
+				return
+			}
+		}
+	}
+
+	if a.settings.GlobalSettings().HasSetting(config.SendLogoutBeforeDisconnectFromLogon) {
+		if a.sendLogoutOnReject, err = settings.globalSettings.BoolSetting(config.SendLogoutBeforeDisconnectFromLogon); err != nil {
+			return
+		}
+	}
+
+	// Limits the Logon, before the session is known.
+	if a.settings.GlobalSettings().HasSetting(config.MaxMessageSize) {
+		if a.maxMessageSize, err = positiveIntSetting(settings.globalSettings, config.MaxMessageSize); err != nil {
+			return
+		}
+	}
+
+	if a.settings.GlobalSettings().HasSetting(config.MaxInboundBytesPerSecond) {
+		if a.maxInboundBytesPerSecond, err = positiveIntSetting(settings.globalSettings, config.MaxInboundBytesPerSecond); err != nil {
+			return
+		}
+	}
+
+	if a.eventLoopShards, err = loadEventLoopShards(settings.GlobalSettings()); err != nil {
+		return
 	}
 
 	if a.globalLog, err = logFactory.Create(); err != nil {
 		return
 	}
 
-	for sessionID, sessionSettings := range settings.SessionSettings() {
+	var redactor redactor
+	if redactor, err = buildRedactor(settings.GlobalSettings()); err != nil {
+		return
+	}
+	a.globalLog = newSessionLog(a.globalLog, SessionID{}, redactor)
+
+	var sessionSettings map[SessionID]*SessionSettings
+	if a.templates, sessionSettings, err = splitSessionTemplates(settings.SessionSettings()); err != nil {
+		return
+	}
+
+	for sessionID, sessionSettings := range sessionSettings {
 		sessID := sessionID
 		sessID.Qualifier = ""
 
@@ -205,12 +591,25 @@ func NewAcceptor(app Application, storeFactory MessageStoreFactory, settings *Se
 		if a.sessions[sessID], err = a.createSession(sessionID, storeFactory, sessionSettings, logFactory, app); err != nil {
 			return
 		}
+
+		if sessionSettings.HasSetting(config.AllowedRemoteAddresses) {
+			if a.allowedAddresses[sessID], err = parseAddressList(sessionSettings, config.AllowedRemoteAddresses); err != nil {
+				return
+			}
+		}
+	}
+
+	// Dynamic sessions are restricted by the default section.
+	if a.dynamicSessions && settings.GlobalSettings().HasSetting(config.AllowedRemoteAddresses) {
+		if a.dynamicAllowedAddresses, err = parseAddressList(settings.GlobalSettings(), config.AllowedRemoteAddresses); err != nil {
+			return
+		}
 	}
 
 	return
 }
 
-func (a *Acceptor) listenForConnections(listener net.Listener) {
+func (a *Acceptor) listenForConnections(address string, listener net.Listener) {
 	defer a.listenerShutdown.Done()
 
 	for {
@@ -220,7 +619,7 @@ func (a *Acceptor) listenForConnections(listener net.Listener) {
 		}
 
 		go func() {
-			a.handleConnection(netConn)
+			a.handleConnection(address, netConn)
 		}()
 	}
 }
@@ -229,7 +628,7 @@ func (a *Acceptor) invalidMessage(msg *bytes.Buffer, err error) {
 	a.globalLog.OnEventf("Invalid Message: %s, %v", msg.Bytes(), err.Error())
 }
 
-func (a *Acceptor) handleConnection(netConn net.Conn) {
+func (a *Acceptor) handleConnection(address string, netConn net.Conn) {
 	defer func() {
 		if err := recover(); err != nil {
 			a.globalLog.OnEventf("Connection Terminated with Panic: %s", debug.Stack())
@@ -242,6 +641,7 @@ func (a *Acceptor) handleConnection(netConn net.Conn) {
 
 	reader := bufio.NewReader(netConn)
 	parser := newParser(reader)
+	parser.setLimits(a.maxMessageSize, a.maxInboundBytesPerSecond)
 
 	msgBytes, err := parser.ReadMessage()
 	if err != nil {
@@ -315,9 +715,38 @@ func (a *Acceptor) handleConnection(netConn net.Conn) {
 		TargetCompID: string(senderCompID), TargetSubID: string(senderSubID), TargetLocationID: string(senderLocationID),
 	}
 
-	localConnectionPort := netConn.LocalAddr().(*net.TCPAddr).Port
-	if expectedPort, ok := a.sessionHostPort[sessID]; ok && expectedPort != localConnectionPort {
+	var resolvedSettings *SessionSettings
+	if a.sessionResolver != nil {
+		if sessID, resolvedSettings, err = a.resolveSession(sessID, msg, address, netConn); err != nil {
+			a.globalLog.OnEventf("Unable to resolve session %v: %v", sessID, err)
+			a.rejectLogon(netConn, sessID, err.Error())
+			return
+		}
+	}
+
+	listenerID := sessID
+	listenerID.Qualifier = ""
+	a.sessionsLock.RLock()
+	expectedAddress, listened := a.sessionListener[listenerID]
+	if _, configured := a.configuredSession(sessID); !configured {
+		if template, matched := a.matchTemplate(sessID); matched {
+			templateID := template.sessionID
+			templateID.Qualifier = ""
+			expectedAddress, listened = a.sessionListener[templateID]
+		}
+	}
+	allowed := a.remoteAddressAllowed(sessID, netConn.RemoteAddr())
+	a.sessionsLock.RUnlock()
+
+	if listened && expectedAddress != address {
 		a.globalLog.OnEventf("Session %v not found for incoming message: %s", sessID, msgBytes)
+		a.rejectLogon(netConn, sessID, "Unknown session")
+		return
+	}
+
+	if !allowed {
+		a.globalLog.OnEventf("Connection from %v not allowed for session %v", netConn.RemoteAddr(), sessID)
+		a.rejectLogon(netConn, sessID, "Connection not authorized")
 		return
 	}
 
@@ -325,45 +754,121 @@ func (a *Acceptor) handleConnection(netConn net.Conn) {
 	if a.connectionValidator != nil {
 		if err := a.connectionValidator.Validate(netConn, sessID); err != nil {
 			a.globalLog.OnEventf("Unable to validate a connection for session %v: %v", sessID, err.Error())
+			a.rejectLogon(netConn, sessID, "Connection not authorized")
 			return
 		}
 	}
 
-	if a.dynamicQualifier {
+	if a.dynamicQualifier && sessID.Qualifier == "" {
 		a.dynamicQualifierCount++
 		sessID.Qualifier = strconv.Itoa(a.dynamicQualifierCount)
 	}
-	session, ok := a.sessions[sessID]
+	a.sessionsLock.RLock()
+	session, ok := a.configuredSession(sessID)
+	running := ok && a.sessionDone[session.sessionID] != nil
+	template, matched := a.matchTemplate(sessID)
+	globalSettings := a.settings.globalSettings.clone()
+	a.sessionsLock.RUnlock()
+
+	if ok && !running {
+		a.globalLog.OnEventf("Session %v is stopped", sessID)
+		a.rejectLogon(netConn, sessID, "Session stopped")
+		return
+	}
+
 	if !ok {
-		if !a.dynamicSessions {
+		sessionSettings := globalSettings
+		switch {
+		case resolvedSettings != nil:
+			sessionSettings.overlay(resolvedSettings)
+		case matched:
+			var err error
+			if sessionSettings, err = a.templateSessionSettings(template, sessID, msg); err != nil {
+				a.globalLog.OnEventf("Session %v from template %v rejected: %v", sessID, template.sessionID, err)
+				a.rejectLogon(netConn, sessID, err.Error())
+				return
+			}
+		case a.dynamicSessions:
+			sessID = a.qualifyDynamicSession(sessID, msg)
+		default:
 			a.globalLog.OnEventf("Session %v not found for incoming message: %s", sessID, msgBytes)
+			a.rejectLogon(netConn, sessID, "Unknown session")
 			return
 		}
-		dynamicSession, err := a.sessionFactory.createSession(sessID, a.storeFactory, a.settings.globalSettings.clone(), a.logFactory, a.app)
+		dynamicSession, err := a.sessionFactory.createSession(sessID, a.storeFactory, sessionSettings, a.logFactory, a.app)
 		if err != nil {
 			a.globalLog.OnEventf("Dynamic session %v failed to create: %v", sessID, err)
+			a.rejectLogon(netConn, sessID, "Unable to create session")
 			return
 		}
+		a.configureSession(dynamicSession)
+		if dynamicSession.loop != nil {
+			// Started before the connection is handed to it, as a run loop would receive it.
+			dynamicSession.loop.start(dynamicSession, nil)
+		}
 		a.dynamicSessionChan <- dynamicSession
 		session = dynamicSession
+		defer flushLog(session.log)
 		defer session.stop()
 	}
 
 	a.sessionAddr.Store(sessID, netConn.RemoteAddr())
+	session.log.OnEventf("Accepted connection from %v", netConn.RemoteAddr())
 	msgIn := make(chan fixIn)
 	msgOut := make(chan []byte)
 
-	if err := session.connect(msgIn, msgOut); err != nil {
+	if err := session.connect(msgIn, msgOut, netConn.RemoteAddr()); err != nil {
 		a.globalLog.OnEventf("Unable to accept session %v connection: %v", sessID, err.Error())
 		return
 	}
 
-	go func() {
-		msgIn <- fixIn{msgBytes, parser.lastRead}
-		readLoop(parser, msgIn, a.globalLog)
-	}()
+	parser.setLimits(session.MaxMessageSize, session.MaxInboundBytesPerSecond)
+	go session.readLoop(parser, msgIn, fixIn{msgBytes, parser.lastRead})
+
+	writeLoop(netConn, msgOut, a.globalLog, session.writeBatching(), session.messageWritten)
+}
+
+// remoteAddressAllowed returns true unless AllowedRemoteAddresses is set for sessID, or for the template it matches if
+// it is not configured, and does not include addr.
+// It must be called with sessionsLock held.
+func (a *Acceptor) remoteAddressAllowed(sessID SessionID, addr net.Addr) bool {
+	key := sessID
+	key.Qualifier = ""
+	allowed, ok := a.allowedAddresses[key]
+	if _, configured := a.configuredSession(sessID); !configured {
+		if template, matched := a.matchTemplate(sessID); matched {
+			allowed, ok = template.allowedAddresses, template.allowedAddresses != nil
+		} else {
+			allowed, ok = a.dynamicAllowedAddresses, a.dynamicAllowedAddresses != nil
+		}
+	}
+
+	return !ok || addressInList(addr, allowed)
+}
+
+// rejectLogon sends a Logout with reason in Text before the connection for sessID is closed,
+// if SendLogoutBeforeDisconnectFromLogon is enabled. There is no session, so the Logout uses MsgSeqNum 1.
+func (a *Acceptor) rejectLogon(netConn net.Conn, sessID SessionID, reason string) {
+	if !a.sendLogoutOnReject {
+		return
+	}
+
+	logout := NewMessage()
+	logout.Header.SetField(tagMsgType, FIXString("5"))
+	logout.Header.SetField(tagBeginString, FIXString(sessID.BeginString))
+	logout.Header.SetField(tagSenderCompID, FIXString(sessID.SenderCompID))
+	optionallySetID(logout, tagSenderSubID, sessID.SenderSubID)
+	optionallySetID(logout, tagSenderLocationID, sessID.SenderLocationID)
+	logout.Header.SetField(tagTargetCompID, FIXString(sessID.TargetCompID))
+	optionallySetID(logout, tagTargetSubID, sessID.TargetSubID)
+	optionallySetID(logout, tagTargetLocationID, sessID.TargetLocationID)
+	logout.Header.SetField(tagMsgSeqNum, FIXInt(1))
+	logout.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: time.Now().UTC(), Precision: Millis})
+	logout.Body.SetField(tagText, FIXString(reason))
 
-	writeLoop(netConn, msgOut, a.globalLog)
+	if _, err := netConn.Write(logout.build()); err != nil {
+		a.globalLog.OnEventf("Unable to send logout to %v: %v", sessID, err)
+	}
 }
 
 func (a *Acceptor) dynamicSessionsLoop() {
@@ -384,19 +889,28 @@ LOOP:
 			id++
 			sessionID := id
 			sessions[sessionID] = session
-			go func() {
-				session.run()
+			a.liveDynamicSessions.Store(session.sessionID, session)
+			stopped := func() {
 				err := UnregisterSession(session.sessionID)
 				if err != nil {
 					a.globalLog.OnEventf("Unregister dynamic session %v failed: %v", session.sessionID, err)
 					return
 				}
 				complete <- sessionID
+			}
+			if session.loop != nil {
+				session.loop.onStop(session, func() { go stopped() })
+				continue
+			}
+			go func() {
+				session.run()
+				stopped()
 			}()
 		case id := <-complete:
 			session, ok := sessions[id]
 			if ok {
 				a.sessionAddr.Delete(session.sessionID)
+				a.liveDynamicSessions.Delete(session.sessionID)
 				delete(sessions, id)
 			} else {
 				a.globalLog.OnEventf("Missing dynamic session %v!", id)
@@ -426,6 +940,79 @@ func (a *Acceptor) SetConnectionValidator(validator ConnectionValidator) {
 	a.connectionValidator = validator
 }
 
+// SetAuthenticator sets an Authenticator to accept or reject incoming Logons
+// based on their Username, Password and RawData. It must be called before Start.
+// To remove a previously set authenticator call it with a nil value:
+//
+//	a.SetAuthenticator(nil)
+func (a *Acceptor) SetAuthenticator(authenticator Authenticator) {
+	a.authenticator = authenticator
+}
+
+// SetSessionStateListener sets a SessionStateListener to be notified of state transitions of all
+// sessions of the Acceptor. It must be called before Start.
+func (a *Acceptor) SetSessionStateListener(listener SessionStateListener) {
+	a.stateListener = listener
+}
+
+// SetTracer sets a Tracer to trace the messages of all sessions of the Acceptor. It must be called before Start.
+func (a *Acceptor) SetTracer(tracer Tracer) {
+	a.tracer = tracer
+}
+
+// SetEventBus sets an EventBus to publish the Events of all sessions of the Acceptor. It must be called before Start.
+func (a *Acceptor) SetEventBus(bus *EventBus) {
+	a.events = bus
+}
+
+// SetLatencyObserver sets a LatencyObserver to receive the latencies of application messages of all sessions of
+// the Acceptor. It must be called before Start.
+func (a *Acceptor) SetLatencyObserver(observer LatencyObserver) {
+	a.latencyObserver = observer
+}
+
+// SetDispatcher sets a Dispatcher to pass the application messages of all sessions of the Acceptor to FromApp.
+// It must be called before Start.
+func (a *Acceptor) SetDispatcher(dispatcher *Dispatcher) {
+	a.dispatcher = dispatcher
+}
+
+// SetValidationPolicy sets a ValidationPolicy disabling validation checks for all sessions of the Acceptor, in
+// addition to the checks disabled by their settings. It must be called before Start.
+func (a *Acceptor) SetValidationPolicy(policy *ValidationPolicy) {
+	a.validationPolicy = policy
+}
+
+// UseInbound adds InboundMiddleware intercepting the messages received by all sessions of the Acceptor. Middleware
+// is called in the order it is added. It must be called before Start.
+func (a *Acceptor) UseInbound(middleware ...InboundMiddleware) {
+	a.inboundMiddleware = append(a.inboundMiddleware, middleware...)
+}
+
+// UseOutbound adds OutboundMiddleware intercepting the messages sent by all sessions of the Acceptor. Middleware
+// is called in the order it is added. It must be called before Start.
+func (a *Acceptor) UseOutbound(middleware ...OutboundMiddleware) {
+	a.outboundMiddleware = append(a.outboundMiddleware, middleware...)
+}
+
+// SetListener sets a Listener to accept connections with in place of listening on a TCP port, unix socket or pipe.
+// Connections are still wrapped with TLS or a TCP proxy as configured. It must be called before Start.
+func (a *Acceptor) SetListener(listener Listener) {
+	a.listener = listener
+}
+
+// SetSessionQualifier sets a SessionQualifier to choose the Qualifier of dynamically created sessions,
+// in place of DynamicQualifier and DynamicQualifierTemplate. It must be called before Start.
+func (a *Acceptor) SetSessionQualifier(qualifier SessionQualifier) {
+	a.sessionQualifier = qualifier
+}
+
+// SetClock sets the Clock used by all sessions of the Acceptor in place of the SystemClock.
+// It must be called before Start.
+func (a *Acceptor) SetClock(clock Clock) {
+	a.clock = clock
+}
+
 // SetTLSConfig allows the creator of the Acceptor to specify a fully customizable tls.Config of their choice,
 // which will be used in the Start() method.
 //
@@ -0,0 +1,166 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"net"
+	"sort"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// templateWildcard matches any value of a SessionID field of a session template.
+const templateWildcard = "*"
+
+// SessionTemplateHandler is an interface allowing an acceptor to approve and customize the sessions it creates from
+// session templates, see config.AcceptorTemplate.
+type SessionTemplateHandler interface {
+	// OnTemplateSession is called when a Logon for sessionID, which is not configured, matches the template
+	// templateID. settings are a copy of the template settings the session is created with, and may be changed.
+	// A non-nil error rejects the logon, and its text is sent to the counterparty in Text(58) of the Logout.
+	OnTemplateSession(sessionID, templateID SessionID, settings *SessionSettings, logon *Message) error
+}
+
+// sessionTemplate is a session of the Acceptor settings with AcceptorTemplate=Y.
+type sessionTemplate struct {
+	sessionID        SessionID
+	settings         *SessionSettings
+	allowedAddresses []*net.IPNet
+}
+
+// isSessionTemplate returns true if sessionSettings are the settings of a session template.
+func isSessionTemplate(sessionSettings *SessionSettings) bool {
+	if !sessionSettings.HasSetting(config.AcceptorTemplate) {
+		return false
+	}
+
+	template, err := sessionSettings.BoolSetting(config.AcceptorTemplate)
+	return err == nil && template
+}
+
+// splitSessionTemplates returns the session templates of sessionSettings, most specific first, and the settings of
+// the other sessions.
+func splitSessionTemplates(sessionSettings map[SessionID]*SessionSettings) (
+	templates []sessionTemplate, sessions map[SessionID]*SessionSettings, err error) {
+	sessions = make(map[SessionID]*SessionSettings, len(sessionSettings))
+	for sessionID, settings := range sessionSettings {
+		if !isSessionTemplate(settings) {
+			sessions[sessionID] = settings
+			continue
+		}
+
+		template := sessionTemplate{sessionID: sessionID, settings: settings}
+		if settings.HasSetting(config.AllowedRemoteAddresses) {
+			if template.allowedAddresses, err = parseAddressList(settings, config.AllowedRemoteAddresses); err != nil {
+				return
+			}
+		}
+		templates = append(templates, template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		wi, wj := templates[i].wildcards(), templates[j].wildcards()
+		if wi != wj {
+			return wi < wj
+		}
+		return templates[i].sessionID.String() < templates[j].sessionID.String()
+	})
+
+	return
+}
+
+// templateFields returns the fields of sessionID a template matches.
+func templateFields(sessionID SessionID) []string {
+	return []string{
+		sessionID.BeginString,
+		sessionID.SenderCompID, sessionID.SenderSubID, sessionID.SenderLocationID,
+		sessionID.TargetCompID, sessionID.TargetSubID, sessionID.TargetLocationID,
+	}
+}
+
+func (t sessionTemplate) wildcards() (n int) {
+	for _, field := range templateFields(t.sessionID) {
+		if field == templateWildcard {
+			n++
+		}
+	}
+
+	return
+}
+
+// matches returns true if each field of sessionID is the field of the template or the template field is a wildcard.
+func (t sessionTemplate) matches(sessionID SessionID) bool {
+	fields := templateFields(sessionID)
+	for i, field := range templateFields(t.sessionID) {
+		if field != templateWildcard && field != fields[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sessionSettings returns a copy of the settings of the template with the SessionID fields of sessionID.
+func (t sessionTemplate) sessionSettings(sessionID SessionID) *SessionSettings {
+	settings := t.settings.clone()
+	settings.Set(config.AcceptorTemplate, "N")
+	for setting, value := range map[string]string{
+		config.BeginString:      sessionID.BeginString,
+		config.SenderCompID:     sessionID.SenderCompID,
+		config.SenderSubID:      sessionID.SenderSubID,
+		config.SenderLocationID: sessionID.SenderLocationID,
+		config.TargetCompID:     sessionID.TargetCompID,
+		config.TargetSubID:      sessionID.TargetSubID,
+		config.TargetLocationID: sessionID.TargetLocationID,
+	} {
+		if value != "" || settings.HasSetting(setting) {
+			settings.Set(setting, value)
+		}
+	}
+
+	return settings
+}
+
+// matchTemplate returns the most specific session template matching sessID. It must be called with sessionsLock
+// held.
+func (a *Acceptor) matchTemplate(sessID SessionID) (sessionTemplate, bool) {
+	for _, template := range a.templates {
+		if template.matches(sessID) {
+			return template, true
+		}
+	}
+
+	return sessionTemplate{}, false
+}
+
+// templateSessionSettings returns the settings of the session for sessID created from template for logon, or an
+// error rejecting the logon.
+func (a *Acceptor) templateSessionSettings(template sessionTemplate, sessID SessionID, logon *Message) (*SessionSettings, error) {
+	settings := template.sessionSettings(sessID)
+	if a.templateHandler != nil {
+		if err := a.templateHandler.OnTemplateSession(sessID, template.sessionID, settings, logon); err != nil {
+			return nil, err
+		}
+	}
+
+	return settings, nil
+}
+
+// SetSessionTemplateHandler sets a SessionTemplateHandler to approve and customize the sessions created from session
+// templates. It must be called before Start.
+func (a *Acceptor) SetSessionTemplateHandler(handler SessionTemplateHandler) {
+	a.templateHandler = handler
+}
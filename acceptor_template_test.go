@@ -0,0 +1,159 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestSessionTemplate_Matches(t *testing.T) {
+	var tests = []struct {
+		template SessionID
+		session  SessionID
+		expected bool
+	}{
+		{
+			SessionID{BeginString: "*", SenderCompID: "BROKER", TargetCompID: "*"},
+			SessionID{BeginString: BeginStringFIX44, SenderCompID: "BROKER", TargetCompID: "CLIENT"},
+			true,
+		},
+		{
+			SessionID{BeginString: BeginStringFIX42, SenderCompID: "BROKER", TargetCompID: "*"},
+			SessionID{BeginString: BeginStringFIX44, SenderCompID: "BROKER", TargetCompID: "CLIENT"},
+			false,
+		},
+		{
+			SessionID{BeginString: BeginStringFIX42, SenderCompID: "BROKER", TargetCompID: "*"},
+			SessionID{BeginString: BeginStringFIX42, SenderCompID: "OTHER", TargetCompID: "CLIENT"},
+			false,
+		},
+		{
+			SessionID{BeginString: BeginStringFIX42, SenderCompID: "BROKER", TargetCompID: "*"},
+			SessionID{BeginString: BeginStringFIX42, SenderCompID: "BROKER", TargetCompID: "CLIENT", TargetSubID: "DESK"},
+			false,
+		},
+		{
+			SessionID{BeginString: BeginStringFIX42, SenderCompID: "BROKER", TargetCompID: "*", TargetSubID: "*"},
+			SessionID{BeginString: BeginStringFIX42, SenderCompID: "BROKER", TargetCompID: "CLIENT", TargetSubID: "DESK"},
+			true,
+		},
+		{
+			SessionID{BeginString: BeginStringFIX42, SenderCompID: "BROKER", TargetCompID: "*", TargetSubID: "*"},
+			SessionID{BeginString: BeginStringFIX42, SenderCompID: "BROKER", TargetCompID: "CLIENT"},
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, sessionTemplate{sessionID: test.template}.matches(test.session), "%v %v", test.template, test.session)
+	}
+}
+
+func TestSplitSessionTemplates(t *testing.T) {
+	session := SessionID{BeginString: BeginStringFIX42, SenderCompID: "BROKER", TargetCompID: "CLIENT"}
+	broad := SessionID{BeginString: "*", SenderCompID: "BROKER", TargetCompID: "*"}
+	specific := SessionID{BeginString: BeginStringFIX42, SenderCompID: "BROKER", TargetCompID: "*"}
+
+	settings := reloadSettings(t, nil,
+		reloadSessionSettings("BROKER", "CLIENT", nil),
+		reloadSessionSettings("BROKER", "*", map[string]string{config.BeginString: "*", config.AcceptorTemplate: "Y"}),
+		reloadSessionSettings("BROKER", "*", map[string]string{config.AcceptorTemplate: "Y", config.AllowedRemoteAddresses: "127.0.0.1"}),
+	)
+
+	templates, sessions, err := splitSessionTemplates(settings.SessionSettings())
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Contains(t, sessions, session)
+	require.Len(t, templates, 2)
+	assert.Equal(t, specific, templates[0].sessionID, "the most specific template is first")
+	assert.Len(t, templates[0].allowedAddresses, 1)
+	assert.Equal(t, broad, templates[1].sessionID)
+
+	sessionSettings := templates[1].sessionSettings(SessionID{BeginString: BeginStringFIX44, SenderCompID: "BROKER", TargetCompID: "CLIENT", TargetSubID: "DESK"})
+	assert.Equal(t, SessionID{BeginString: BeginStringFIX44, SenderCompID: "BROKER", TargetCompID: "CLIENT", TargetSubID: "DESK"},
+		sessionIDFromSessionSettings(NewSessionSettings(), sessionSettings))
+	assert.False(t, isSessionTemplate(sessionSettings))
+
+	_, err = settings.AddSession(reloadSessionSettings("BROKER", "OTHER", map[string]string{config.BeginString: "*"}))
+	assert.Error(t, err, "only templates may have a wildcard BeginString")
+}
+
+type templateHandler struct {
+	sync.Mutex
+	templateIDs map[SessionID]SessionID
+}
+
+func (h *templateHandler) OnTemplateSession(sessionID, templateID SessionID, settings *SessionSettings, _ *Message) error {
+	h.Lock()
+	defer h.Unlock()
+
+	h.templateIDs[sessionID] = templateID
+	if sessionID.TargetCompID == "BLOCKED" {
+		return errors.New("Not approved")
+	}
+
+	settings.Set(config.ResetOnLogon, "Y")
+	return nil
+}
+
+func TestAcceptor_SessionTemplate(t *testing.T) {
+	host := "pipe://acceptor_template"
+	template := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "*"}
+
+	acceptorApp := newReloadApp()
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketAcceptHost: host},
+			reloadSessionSettings("ACCEPTOR", "*", map[string]string{config.AcceptorTemplate: "Y"})),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	handler := &templateHandler{templateIDs: make(map[SessionID]SessionID)}
+	acceptor.SetSessionTemplateHandler(handler)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+	assert.Empty(t, acceptor.Health().Sessions, "templates are not sessions")
+
+	initiatorApp := newReloadApp()
+	initiator, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5018", config.HeartBtInt: "30"},
+			reloadSessionSettings("CLIENT", "ACCEPTOR", nil), reloadSessionSettings("BLOCKED", "ACCEPTOR", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	client := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "CLIENT"}
+	waitForSession(t, initiatorApp.loggedOn, SessionID{BeginString: BeginStringFIX42, SenderCompID: "CLIENT", TargetCompID: "ACCEPTOR"})
+	waitForSession(t, acceptorApp.loggedOn, client)
+	assertNoSession(t, acceptorApp.loggedOn)
+
+	handler.Lock()
+	assert.Equal(t, map[SessionID]SessionID{
+		client: template,
+		{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "BLOCKED"}: template,
+	}, handler.templateIDs)
+	handler.Unlock()
+
+	session, ok := lookupSession(client)
+	require.True(t, ok)
+	assert.True(t, session.ResetOnLogon, "the handler customizes the settings")
+}
@@ -16,9 +16,12 @@
 package quickfix
 
 import (
+	"bufio"
 	"crypto/tls"
 	"net"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/quickfixgo/quickfix/config"
 
@@ -126,3 +129,238 @@ func TestAcceptor_SetTLSConfig(t *testing.T) {
 	assert.NotNil(t, conn)
 	defer conn.Close()
 }
+
+func TestAcceptor_SendLogoutBeforeDisconnectFromLogon(t *testing.T) {
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, "sender")
+	sessionSettings.Set(config.TargetCompID, "target")
+
+	settings := NewSettings()
+	settings.GlobalSettings().Set(config.SocketAcceptPort, "5002")
+	settings.GlobalSettings().Set(config.SendLogoutBeforeDisconnectFromLogon, "Y")
+	_, err := settings.AddSession(sessionSettings)
+	require.NoError(t, err)
+
+	acceptor, err := NewAcceptor(&MockApp{}, NewMemoryStoreFactory(), settings, NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	conn, err := net.Dial("tcp", "localhost:5002")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	logon := NewMessage()
+	logon.Header.SetField(tagMsgType, FIXString("A"))
+	logon.Header.SetField(tagBeginString, FIXString(BeginStringFIX42))
+	logon.Header.SetField(tagSenderCompID, FIXString("unknown"))
+	logon.Header.SetField(tagTargetCompID, FIXString("sender"))
+	logon.Header.SetField(tagMsgSeqNum, FIXInt(1))
+	logon.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: time.Now().UTC()})
+	logon.Body.SetField(tagEncryptMethod, FIXString("0"))
+	logon.Body.SetField(tagHeartBtInt, FIXInt(30))
+	_, err = conn.Write(logon.build())
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	msgBytes, err := newParser(bufio.NewReader(conn)).ReadMessage()
+	require.NoError(t, err)
+
+	logout := NewMessage()
+	require.NoError(t, ParseMessage(logout, msgBytes))
+	msgType, err := logout.MsgType()
+	require.NoError(t, err)
+	assert.Equal(t, "5", msgType)
+	text, err := logout.Body.GetString(tagText)
+	require.NoError(t, err)
+	assert.Equal(t, "Unknown session", text)
+	targetCompID, err := logout.Header.GetString(tagTargetCompID)
+	require.NoError(t, err)
+	assert.Equal(t, "unknown", targetCompID)
+}
+
+type subIDQualifier struct{}
+
+func (subIDQualifier) Qualifier(sessionID SessionID, logon *Message) string {
+	return "sub-" + sessionID.TargetSubID
+}
+
+func TestAcceptor_QualifyDynamicSession(t *testing.T) {
+	sessID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "acceptor", TargetCompID: "client", TargetSubID: "desk1", Qualifier: "1"}
+
+	a := &Acceptor{}
+	assert.Equal(t, sessID, a.qualifyDynamicSession(sessID, NewMessage()))
+
+	a.qualifierTemplate = "{TargetCompID}-{TargetSubID}"
+	assert.Equal(t, "client-desk1", a.qualifyDynamicSession(sessID, NewMessage()).Qualifier)
+
+	a.SetSessionQualifier(subIDQualifier{})
+	assert.Equal(t, "sub-desk1", a.qualifyDynamicSession(sessID, NewMessage()).Qualifier)
+}
+
+func TestAcceptor_DynamicQualifierTemplate(t *testing.T) {
+	settings := NewSettings()
+	settings.GlobalSettings().Set(config.DynamicSessions, "Y")
+	settings.GlobalSettings().Set(config.DynamicQualifierTemplate, "{TargetSubID}")
+
+	acceptor, err := NewAcceptor(&MockApp{}, NewMemoryStoreFactory(), settings, NewNullLogFactory())
+	require.NoError(t, err)
+	assert.Equal(t, "{TargetSubID}", acceptor.qualifierTemplate)
+}
+
+func TestAcceptor_AllowedRemoteAddresses(t *testing.T) {
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, "sender")
+	sessionSettings.Set(config.TargetCompID, "target")
+	sessionSettings.Set(config.AllowedRemoteAddresses, "10.0.0.0/8, 192.168.1.10")
+
+	settings := NewSettings()
+	settings.GlobalSettings().Set(config.SocketAcceptPort, "5003")
+	settings.GlobalSettings().Set(config.DynamicSessions, "Y")
+	settings.GlobalSettings().Set(config.AllowedRemoteAddresses, "127.0.0.1")
+	sessionID, err := settings.AddSession(sessionSettings)
+	require.NoError(t, err)
+
+	acceptor, err := NewAcceptor(&MockApp{}, NewMemoryStoreFactory(), settings, NewNullLogFactory())
+	require.NoError(t, err)
+
+	tcpAddr := func(ip string) net.Addr { return &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234} }
+	assert.True(t, acceptor.remoteAddressAllowed(sessionID, tcpAddr("10.1.2.3")))
+	assert.True(t, acceptor.remoteAddressAllowed(sessionID, tcpAddr("192.168.1.10")))
+	assert.False(t, acceptor.remoteAddressAllowed(sessionID, tcpAddr("192.168.1.11")))
+	assert.False(t, acceptor.remoteAddressAllowed(sessionID, &net.UnixAddr{Name: "/tmp/fix.sock", Net: "unix"}))
+
+	dynamicID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "sender", TargetCompID: "other"}
+	assert.True(t, acceptor.remoteAddressAllowed(dynamicID, tcpAddr("127.0.0.1")))
+	assert.False(t, acceptor.remoteAddressAllowed(dynamicID, tcpAddr("10.1.2.3")))
+
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	conn, err := net.Dial("tcp", "localhost:5003")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	logon := NewMessage()
+	logon.Header.SetField(tagMsgType, FIXString("A"))
+	logon.Header.SetField(tagBeginString, FIXString(BeginStringFIX42))
+	logon.Header.SetField(tagSenderCompID, FIXString("target"))
+	logon.Header.SetField(tagTargetCompID, FIXString("sender"))
+	logon.Header.SetField(tagMsgSeqNum, FIXInt(1))
+	logon.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: time.Now().UTC()})
+	logon.Body.SetField(tagEncryptMethod, FIXString("0"))
+	logon.Body.SetField(tagHeartBtInt, FIXInt(30))
+	_, err = conn.Write(logon.build())
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = newParser(bufio.NewReader(conn)).ReadMessage()
+	assert.Error(t, err, "connection from an address not in AllowedRemoteAddresses should be closed")
+}
+
+func TestAcceptor_AllowedRemoteAddressesInvalid(t *testing.T) {
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, "sender")
+	sessionSettings.Set(config.TargetCompID, "target")
+	sessionSettings.Set(config.AllowedRemoteAddresses, "not-an-address")
+
+	settings := NewSettings()
+	settings.GlobalSettings().Set(config.SocketAcceptPort, "5003")
+	_, err := settings.AddSession(sessionSettings)
+	require.NoError(t, err)
+
+	_, err = NewAcceptor(&MockApp{}, NewMemoryStoreFactory(), settings, NewNullLogFactory())
+	assert.Error(t, err)
+}
+
+func TestAcceptor_MultipleListeners(t *testing.T) {
+	plainSettings := NewSessionSettings()
+	plainSettings.Set(config.BeginString, BeginStringFIX42)
+	plainSettings.Set(config.SenderCompID, "sender")
+	plainSettings.Set(config.TargetCompID, "plain")
+
+	tlsSettings := NewSessionSettings()
+	tlsSettings.Set(config.BeginString, BeginStringFIX42)
+	tlsSettings.Set(config.SenderCompID, "sender")
+	tlsSettings.Set(config.TargetCompID, "tls")
+	tlsSettings.Set(config.SocketAcceptHost, "127.0.0.1")
+	tlsSettings.Set(config.SocketAcceptPort, "5005")
+	tlsSettings.Set(config.SocketPrivateKeyFile, "_test_data/localhost.key")
+	tlsSettings.Set(config.SocketCertificateFile, "_test_data/localhost.crt")
+
+	settings := NewSettings()
+	settings.GlobalSettings().Set(config.SocketAcceptPort, "5004")
+	_, err := settings.AddSession(plainSettings)
+	require.NoError(t, err)
+	_, err = settings.AddSession(tlsSettings)
+	require.NoError(t, err)
+
+	acceptor, err := NewAcceptor(&MockApp{}, NewMemoryStoreFactory(), settings, NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	require.Len(t, acceptor.listeners, 2)
+	_, ok := acceptor.listeners[":5004"].(*net.TCPListener)
+	assert.True(t, ok, "plaintext listener on the default port")
+	_, ok = acceptor.listeners["127.0.0.1:5005"].(*net.TCPListener)
+	assert.False(t, ok, "TLS listener on the session port")
+
+	conn, err := tls.Dial("tcp", "127.0.0.1:5005", &tls.Config{InsecureSkipVerify: true})
+	require.NoError(t, err)
+	assert.NoError(t, conn.Handshake())
+	conn.Close()
+
+	// Sessions are only accepted on the address they are configured for.
+	plainID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "sender", TargetCompID: "plain"}
+	assert.Equal(t, ":5004", acceptor.sessionListener[plainID])
+}
+
+func TestAcceptor_ConflictingListenerSettings(t *testing.T) {
+	settings := NewSettings()
+	settings.GlobalSettings().Set(config.SocketAcceptPort, "5006")
+	for i, useTCPProxy := range []string{"Y", "N"} {
+		sessionSettings := NewSessionSettings()
+		sessionSettings.Set(config.BeginString, BeginStringFIX42)
+		sessionSettings.Set(config.SenderCompID, "sender")
+		sessionSettings.Set(config.TargetCompID, "target"+strconv.Itoa(i))
+		sessionSettings.Set(config.UseTCPProxy, useTCPProxy)
+		_, err := settings.AddSession(sessionSettings)
+		require.NoError(t, err)
+	}
+
+	acceptor, err := NewAcceptor(&MockApp{}, NewMemoryStoreFactory(), settings, NewNullLogFactory())
+	require.NoError(t, err)
+	err = acceptor.Start()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), config.UseTCPProxy)
+}
+
+func TestAcceptor_Health(t *testing.T) {
+	settings := NewSettings()
+	settings.GlobalSettings().Set(config.SocketAcceptPort, "5007")
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, "health_sender")
+	sessionSettings.Set(config.TargetCompID, "health_target")
+	sessionID, err := settings.AddSession(sessionSettings)
+	require.NoError(t, err)
+
+	acceptor, err := NewAcceptor(&MockApp{}, NewMemoryStoreFactory(), settings, NewNullLogFactory())
+	require.NoError(t, err)
+	assert.False(t, acceptor.Health().Running)
+
+	require.NoError(t, acceptor.Start())
+	health := acceptor.Health()
+	assert.True(t, health.Running)
+	assert.False(t, health.Ready())
+	require.Len(t, health.Sessions, 1)
+	assert.Equal(t, sessionID, health.Sessions[0].SessionID)
+	assert.False(t, health.Sessions[0].LoggedOn())
+
+	acceptor.Stop()
+	assert.False(t, acceptor.Health().Running)
+}
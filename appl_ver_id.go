@@ -0,0 +1,84 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// normalizeApplVerID returns the ApplVerID enum value of an ApplVerID or of the BeginString of a FIX version.
+func normalizeApplVerID(applVerID string) string {
+	if enum, ok := applVerIDLookup[applVerID]; ok {
+		return enum
+	}
+
+	return applVerID
+}
+
+// inboundApplVerID returns the ApplVerID of messages received without ApplVerID(1128): the DefaultApplVerID sent
+// by the counterparty on Logon, or else the DefaultApplVerID of the session.
+func (s *session) inboundApplVerID() string {
+	if s.targetDefaultApplVerID != "" {
+		return s.targetDefaultApplVerID
+	}
+
+	return s.DefaultApplVerID
+}
+
+// appDataDictionaryFor returns the application data dictionary for messages of applVerID, falling back to the
+// AppDataDictionary of the session.
+func (s *session) appDataDictionaryFor(applVerID string) *datadictionary.DataDictionary {
+	if dict, ok := s.appDataDictionaries[applVerID]; ok {
+		return dict
+	}
+
+	return s.appDataDictionary
+}
+
+// validatorFor returns the Validator for msg, selected by its ApplVerID(1128) or the DefaultApplVerID sent by the
+// counterparty.
+func (s *session) validatorFor(msg *Message) Validator {
+	if len(s.appValidators) == 0 {
+		return s.Validator
+	}
+
+	applVerID, err := msg.Header.GetString(tagApplVerID)
+	if err != nil {
+		applVerID = s.inboundApplVerID()
+	}
+
+	if validator, ok := s.appValidators[normalizeApplVerID(applVerID)]; ok {
+		return validator
+	}
+
+	return s.Validator
+}
+
+// checkTargetDefaultApplVerID rejects a Logon with a DefaultApplVerID there is no application data dictionary for,
+// if application data dictionaries are configured for more than one ApplVerID.
+func (s *session) checkTargetDefaultApplVerID() error {
+	if len(s.appDataDictionaries) == 0 {
+		return nil
+	}
+
+	if _, ok := s.appDataDictionaries[s.targetDefaultApplVerID]; !ok {
+		return RejectLogon{Text: fmt.Sprintf("DefaultApplVerID %v is not supported", s.targetDefaultApplVerID)}
+	}
+
+	return nil
+}
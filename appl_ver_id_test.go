@@ -0,0 +1,100 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// applVerIDTestMsg returns a FIXT.1.1 MarketDataIncrementalRefresh with the header field applVerID.
+func applVerIDTestMsg(applVerID string) string {
+	body := "35=X\x0149=A\x0156=B\x0134=1\x0152=20240101-00:00:00\x01" + applVerID +
+		"268=2\x01279=0\x01269=0\x01279=1\x01269=1\x01"
+
+	return fmt.Sprintf("8=FIXT.1.1\x019=%v\x01%v10=000\x01", len(body), body)
+}
+
+func TestParseMessageSelectsAppDataDictionaryByApplVerID(t *testing.T) {
+	dict, err := datadictionary.Parse("spec/FIX44.xml")
+	require.Nil(t, err)
+	dictionaries := map[string]*datadictionary.DataDictionary{"6": dict}
+
+	var tests = []struct {
+		applVerID   string
+		parsedGroup bool
+	}{
+		{"1128=6\x01", true},
+		{"1128=FIX.4.4\x01", true},
+		{"1128=9\x01", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		msg := NewMessage()
+		require.Nil(t, parseMessage(msg, bytes.NewBufferString(applVerIDTestMsg(test.applVerID)), nil, nil, dictionaries, false))
+
+		// The fields of a parsed group are not fields of the body.
+		assert.Equal(t, test.parsedGroup, !msg.Body.Has(Tag(279)), test.applVerID)
+		if test.parsedGroup {
+			group := NewRepeatingGroup(Tag(268), GroupTemplate{GroupElement(Tag(279)), GroupElement(Tag(269))})
+			require.Nil(t, msg.Body.GetGroup(group))
+			assert.Equal(t, 2, group.Len())
+		}
+	}
+}
+
+func TestValidatorFor(t *testing.T) {
+	fix50sp2Validator := NewValidator(defaultValidatorSettings, nil, nil)
+	fix44Validator := NewValidator(defaultValidatorSettings, nil, nil)
+	s := &session{Validator: fix50sp2Validator}
+	s.DefaultApplVerID = "9"
+
+	msg := NewMessage()
+	assert.True(t, s.validatorFor(msg) == fix50sp2Validator)
+
+	s.appValidators = map[string]Validator{"9": fix50sp2Validator, "6": fix44Validator}
+	assert.True(t, s.validatorFor(msg) == fix50sp2Validator)
+
+	msg.Header.SetString(tagApplVerID, "6")
+	assert.True(t, s.validatorFor(msg) == fix44Validator)
+
+	msg.Header.SetString(tagApplVerID, "7")
+	assert.True(t, s.validatorFor(msg) == fix50sp2Validator)
+
+	msg.Header.Remove(tagApplVerID)
+	s.targetDefaultApplVerID = "6"
+	assert.True(t, s.validatorFor(msg) == fix44Validator)
+}
+
+func TestCheckTargetDefaultApplVerID(t *testing.T) {
+	s := &session{targetDefaultApplVerID: "7"}
+	assert.Nil(t, s.checkTargetDefaultApplVerID())
+
+	s.appDataDictionaries = map[string]*datadictionary.DataDictionary{"9": nil, "6": nil}
+	err := s.checkTargetDefaultApplVerID()
+	require.NotNil(t, err)
+	assert.IsType(t, RejectLogon{}, err)
+
+	s.targetDefaultApplVerID = "6"
+	assert.Nil(t, s.checkTargetDefaultApplVerID())
+}
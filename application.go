@@ -39,3 +39,11 @@ type Application interface {
 	// FromApp notification of app message being received from target.
 	FromApp(message *Message, sessionID SessionID) MessageRejectError
 }
+
+// PossDupApplication may be implemented by an Application to receive app messages with PossDupFlag set
+// separately from FromApp, so that replayed messages can be deduplicated.
+type PossDupApplication interface {
+	// FromAppPossDup notification of app message with PossDupFlag=Y being received from target.
+	// It is called instead of FromApp.
+	FromAppPossDup(message *Message, sessionID SessionID) MessageRejectError
+}
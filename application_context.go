@@ -0,0 +1,176 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// ApplicationWithContext is an Application whose OnLogon, ToApp and FromApp receive a context.Context.
+// The context carries the MessageMetadata of the message, the spans of a Tracer, and a deadline if
+// ApplicationCallbackTimeout is set. The context of FromApp and OnLogon is cancelled when the session disconnects,
+// the context of ToApp is derived from the context set on the message with SetContext.
+//
+// Pass it to NewAcceptor or NewInitiator with NewContextApplication.
+type ApplicationWithContext interface {
+	// OnCreate notification of a session begin created.
+	OnCreate(sessionID SessionID)
+
+	// OnLogon notification of a session successfully logging on.
+	OnLogon(ctx context.Context, sessionID SessionID)
+
+	// OnLogout notification of a session logging off or disconnecting.
+	OnLogout(sessionID SessionID)
+
+	// ToAdmin notification of admin message being sent to target.
+	ToAdmin(message *Message, sessionID SessionID)
+
+	// ToApp notification of app message being sent to target.
+	ToApp(ctx context.Context, message *Message, sessionID SessionID) error
+
+	// FromAdmin notification of admin message being received from target.
+	FromAdmin(message *Message, sessionID SessionID) MessageRejectError
+
+	// FromApp notification of app message being received from target.
+	FromApp(ctx context.Context, message *Message, sessionID SessionID) MessageRejectError
+}
+
+// MessageMetadata describes the message an ApplicationWithContext callback is called for.
+type MessageMetadata struct {
+	// ReceiveTime is when an inbound message was read from the connection, and is zero for outbound messages.
+	ReceiveTime time.Time
+
+	// RemoteAddr is the address of the counterparty, and is nil if the session is not connected.
+	RemoteAddr net.Addr
+
+	// RawMessage is the message as received, and is nil for outbound messages.
+	RawMessage []byte
+}
+
+type messageMetadataKey struct{}
+
+// MessageMetadataFromContext returns the MessageMetadata of the context of an ApplicationWithContext callback.
+func MessageMetadataFromContext(ctx context.Context) (MessageMetadata, bool) {
+	metadata, ok := ctx.Value(messageMetadataKey{}).(MessageMetadata)
+	return metadata, ok
+}
+
+// contextApplication is an Application calling an ApplicationWithContext, sessions call it with contexts.
+type contextApplication struct {
+	app ApplicationWithContext
+}
+
+// NewContextApplication returns an Application for NewAcceptor and NewInitiator that calls app.
+// Optional interfaces such as PossDupApplication are not available to an ApplicationWithContext.
+func NewContextApplication(app ApplicationWithContext) Application {
+	if legacy, ok := app.(legacyApplication); ok {
+		return legacy.app
+	}
+	return contextApplication{app: app}
+}
+
+func (a contextApplication) OnCreate(sessionID SessionID) { a.app.OnCreate(sessionID) }
+func (a contextApplication) OnLogon(sessionID SessionID) {
+	a.app.OnLogon(context.Background(), sessionID)
+}
+func (a contextApplication) OnLogout(sessionID SessionID) { a.app.OnLogout(sessionID) }
+func (a contextApplication) ToAdmin(message *Message, sessionID SessionID) {
+	a.app.ToAdmin(message, sessionID)
+}
+func (a contextApplication) ToApp(message *Message, sessionID SessionID) error {
+	return a.app.ToApp(context.Background(), message, sessionID)
+}
+func (a contextApplication) FromAdmin(message *Message, sessionID SessionID) MessageRejectError {
+	return a.app.FromAdmin(message, sessionID)
+}
+func (a contextApplication) FromApp(message *Message, sessionID SessionID) MessageRejectError {
+	return a.app.FromApp(context.Background(), message, sessionID)
+}
+
+// legacyApplication is an ApplicationWithContext calling an Application, ignoring the contexts.
+type legacyApplication struct {
+	app Application
+}
+
+// NewLegacyApplicationAdapter returns an ApplicationWithContext calling app without the contexts,
+// e.g. to wrap an existing Application in middleware written for ApplicationWithContext.
+func NewLegacyApplicationAdapter(app Application) ApplicationWithContext {
+	if ctxApp, ok := app.(contextApplication); ok {
+		return ctxApp.app
+	}
+	return legacyApplication{app: app}
+}
+
+func (a legacyApplication) OnCreate(sessionID SessionID)                   { a.app.OnCreate(sessionID) }
+func (a legacyApplication) OnLogon(_ context.Context, sessionID SessionID) { a.app.OnLogon(sessionID) }
+func (a legacyApplication) OnLogout(sessionID SessionID)                   { a.app.OnLogout(sessionID) }
+func (a legacyApplication) ToAdmin(message *Message, sessionID SessionID) {
+	a.app.ToAdmin(message, sessionID)
+}
+func (a legacyApplication) ToApp(_ context.Context, message *Message, sessionID SessionID) error {
+	return a.app.ToApp(message, sessionID)
+}
+func (a legacyApplication) FromAdmin(message *Message, sessionID SessionID) MessageRejectError {
+	return a.app.FromAdmin(message, sessionID)
+}
+func (a legacyApplication) FromApp(_ context.Context, message *Message, sessionID SessionID) MessageRejectError {
+	return a.app.FromApp(message, sessionID)
+}
+
+// callbackContext returns the context of an ApplicationWithContext callback for msg.
+func (s *session) callbackContext(msg *Message, inbound bool) (context.Context, context.CancelFunc) {
+	metadata := MessageMetadata{}
+	if addr := s.remoteAddr.Load(); addr != nil {
+		metadata.RemoteAddr = *addr
+	}
+	if inbound {
+		metadata.ReceiveTime = msg.ReceiveTime
+		if msg.rawMessage != nil {
+			metadata.RawMessage = msg.rawMessage.Bytes()
+		}
+	}
+
+	ctx := context.WithValue(msg.Context(), messageMetadataKey{}, metadata)
+	if s.ApplicationCallbackTimeout > 0 {
+		return context.WithTimeout(ctx, s.ApplicationCallbackTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func (s *session) toApp(msg *Message) error {
+	app, ok := s.application.(contextApplication)
+	if !ok {
+		return s.application.ToApp(msg, s.sessionID)
+	}
+
+	ctx, cancel := s.callbackContext(msg, false)
+	defer cancel()
+	return app.app.ToApp(ctx, msg, s.sessionID)
+}
+
+func (s *session) onLogon(msg *Message) {
+	app, ok := s.application.(contextApplication)
+	if !ok {
+		s.application.OnLogon(s.sessionID)
+		return
+	}
+
+	ctx, cancel := s.callbackContext(msg, true)
+	defer cancel()
+	app.app.OnLogon(ctx, s.sessionID)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// recordingContextApp records the contexts passed to an ApplicationWithContext.
+type recordingContextApp struct {
+	ApplicationWithContext
+	onLogonCtx, toAppCtx, fromAppCtx context.Context
+	fromAppDone                      bool
+}
+
+func (a *recordingContextApp) OnLogon(ctx context.Context, sessionID SessionID) {
+	a.onLogonCtx = ctx
+	a.ApplicationWithContext.OnLogon(ctx, sessionID)
+}
+
+func (a *recordingContextApp) ToApp(ctx context.Context, msg *Message, sessionID SessionID) error {
+	a.toAppCtx = ctx
+	return a.ApplicationWithContext.ToApp(ctx, msg, sessionID)
+}
+
+func (a *recordingContextApp) FromApp(ctx context.Context, msg *Message, sessionID SessionID) MessageRejectError {
+	a.fromAppCtx = ctx
+	a.fromAppDone = ctx.Err() != nil
+	return a.ApplicationWithContext.FromApp(ctx, msg, sessionID)
+}
+
+type ApplicationContextTestSuite struct {
+	SessionSuiteRig
+	app *recordingContextApp
+}
+
+func TestApplicationContextTestSuite(t *testing.T) {
+	suite.Run(t, new(ApplicationContextTestSuite))
+}
+
+func (s *ApplicationContextTestSuite) SetupTest() {
+	s.Init()
+	s.app = &recordingContextApp{ApplicationWithContext: NewLegacyApplicationAdapter(&s.MockApp)}
+	s.session.application = NewContextApplication(s.app)
+	s.session.State = inSession{}
+}
+
+func (s *ApplicationContextTestSuite) TestFromAppMetadata() {
+	var addr net.Addr = &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5001}
+	s.session.remoteAddr.Store(&addr)
+	s.MockApp.On("FromApp").Return(nil)
+
+	raw := s.NewOrderSingle().build()
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(raw), receiveTime: time.Now()})
+	s.MockApp.AssertExpectations(s.T())
+
+	s.Require().NotNil(s.app.fromAppCtx)
+	s.False(s.app.fromAppDone)
+	metadata, ok := MessageMetadataFromContext(s.app.fromAppCtx)
+	s.Require().True(ok)
+	s.Equal(addr, metadata.RemoteAddr)
+	s.Equal(raw, metadata.RawMessage)
+	s.False(metadata.ReceiveTime.IsZero())
+
+	_, hasDeadline := s.app.fromAppCtx.Deadline()
+	s.False(hasDeadline)
+	s.NotNil(s.app.fromAppCtx.Err(), "context should be cancelled once FromApp returns")
+}
+
+func (s *ApplicationContextTestSuite) TestFromAppCallbackTimeout() {
+	s.session.ApplicationCallbackTimeout = time.Minute
+	s.MockApp.On("FromApp").Return(nil)
+
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(s.NewOrderSingle().build())})
+	s.MockApp.AssertExpectations(s.T())
+
+	deadline, ok := s.app.fromAppCtx.Deadline()
+	s.Require().True(ok)
+	s.WithinDuration(time.Now().Add(time.Minute), deadline, 5*time.Second)
+}
+
+func (s *ApplicationContextTestSuite) TestFromAppCancelledOnDisconnect() {
+	s.session.connCtx, s.session.connCancel = context.WithCancel(context.Background())
+	connCtx := s.session.connContext()
+
+	s.session.onDisconnect()
+
+	s.NotNil(connCtx.Err())
+	s.Nil(s.session.connContext().Err())
+	s.Nil(s.session.remoteAddr.Load())
+}
+
+func (s *ApplicationContextTestSuite) TestToAppContext() {
+	s.MockApp.On("ToApp").Return(nil)
+
+	type key struct{}
+	msg := s.NewOrderSingle()
+	msg.SetContext(context.WithValue(context.Background(), key{}, "order"))
+	s.Require().Nil(s.session.send(msg))
+	s.MockApp.AssertExpectations(s.T())
+	s.LastToAppMessageSent()
+
+	s.Require().NotNil(s.app.toAppCtx)
+	s.Equal("order", s.app.toAppCtx.Value(key{}))
+	metadata, ok := MessageMetadataFromContext(s.app.toAppCtx)
+	s.Require().True(ok)
+	s.True(metadata.ReceiveTime.IsZero())
+	s.Nil(metadata.RawMessage)
+}
+
+func (s *ApplicationContextTestSuite) TestOnLogonContext() {
+	s.MockApp.On("OnLogon")
+
+	s.session.onLogon(s.Logon())
+	s.MockApp.AssertExpectations(s.T())
+
+	s.Require().NotNil(s.app.onLogonCtx)
+	_, ok := MessageMetadataFromContext(s.app.onLogonCtx)
+	s.True(ok)
+}
+
+func (s *ApplicationContextTestSuite) TestAdaptersUnwrap() {
+	s.Equal(&s.MockApp, NewContextApplication(NewLegacyApplicationAdapter(&s.MockApp)))
+	s.Equal(s.app, NewLegacyApplicationAdapter(NewContextApplication(s.app)))
+}
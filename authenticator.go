@@ -0,0 +1,67 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+// LogonCredentials are the authentication fields of a Logon received by an acceptor.
+type LogonCredentials struct {
+	SessionID SessionID
+
+	// Username is Username(553), if present.
+	Username string
+
+	// Password is Password(554), if present.
+	Password string
+
+	// RawData is RawData(96), if present.
+	RawData []byte
+}
+
+// Authenticator is an interface allowing an acceptor to authenticate a counterparty from the contents of its Logon,
+// before the session is logged on and OnLogon is called.
+type Authenticator interface {
+	// Authenticate returns nil to accept the logon. A non-nil error rejects the logon,
+	// and its text is sent to the counterparty in Text(58) of the Logout.
+	Authenticate(credentials LogonCredentials) error
+}
+
+func (s *session) authenticate(msg *Message) error {
+	credentials := LogonCredentials{SessionID: s.sessionID}
+
+	var err error
+	if msg.Body.Has(tagUsername) {
+		if credentials.Username, err = msg.Body.GetString(tagUsername); err != nil {
+			return err
+		}
+	}
+
+	if msg.Body.Has(tagPassword) {
+		if credentials.Password, err = msg.Body.GetString(tagPassword); err != nil {
+			return err
+		}
+	}
+
+	if msg.Body.Has(tagRawData) {
+		if credentials.RawData, err = msg.Body.GetBytes(tagRawData); err != nil {
+			return err
+		}
+	}
+
+	if err = s.authenticator.Authenticate(credentials); err != nil {
+		return RejectLogon{Text: err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"time"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+// Clock is the source of time for sessions, used for SendingTime, heartbeats, logon and logout timeouts
+// and session schedules. It can be replaced with Acceptor.SetClock or Initiator.SetClock, e.g. to drive
+// sessions deterministically in tests. A Clock must be safe for concurrent use.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a single event timer created by a Clock, with the semantics of a time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker is a periodic timer created by a Clock, with the semantics of a time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// SystemClock is the Clock of the host system, used by default.
+type SystemClock struct{}
+
+// Now returns time.Now().
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// NewTimer returns a Timer wrapping time.NewTimer.
+func (SystemClock) NewTimer(d time.Duration) Timer { return systemTimer{time.NewTimer(d)} }
+
+// NewTicker returns a Ticker wrapping time.NewTicker.
+func (SystemClock) NewTicker(d time.Duration) Ticker { return systemTicker{time.NewTicker(d)} }
+
+type systemTimer struct{ t *time.Timer }
+
+func (t systemTimer) C() <-chan time.Time        { return t.t.C }
+func (t systemTimer) Stop() bool                 { return t.t.Stop() }
+func (t systemTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+type systemTicker struct{ t *time.Ticker }
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+func (t systemTicker) Stop()               { t.t.Stop() }
+
+// afterFunc calls f in its own goroutine once d has elapsed on the session's clock, or on the event loop of the
+// session in shared mode.
+func (s *session) afterFunc(d time.Duration, f func()) {
+	if s.loop != nil {
+		s.loop.wheel.AfterFunc(d, f)
+		return
+	}
+
+	timer := s.clock.NewTimer(d)
+	go func() {
+		<-timer.C()
+		f()
+	}()
+}
+
+// afterEvent raises evt in the session's run loop once d has elapsed.
+func (s *session) afterEvent(d time.Duration, evt internal.Event) {
+	if loop := s.loop; loop != nil {
+		loop.wheel.AfterFunc(d, func() {
+			loop.handle(s, func() { s.Timeout(s, evt) })
+		})
+		return
+	}
+
+	s.afterFunc(d, func() { s.sessionEvent <- evt })
+}
@@ -0,0 +1,107 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+type fakeTimer struct {
+	d time.Duration
+	c chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time        { return t.c }
+func (t *fakeTimer) Stop() bool                 { return true }
+func (t *fakeTimer) Reset(d time.Duration) bool { t.d = d; return true }
+
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{d: d, c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) Ticker { return SystemClock{}.NewTicker(d) }
+
+type ClockTestSuite struct {
+	SessionSuiteRig
+	clock *fakeClock
+}
+
+func TestClockTestSuite(t *testing.T) {
+	suite.Run(t, new(ClockTestSuite))
+}
+
+func (s *ClockTestSuite) SetupTest() {
+	s.Init()
+	s.clock = &fakeClock{now: time.Date(2024, time.March, 4, 9, 30, 0, 0, time.UTC)}
+	s.session.clock = s.clock
+}
+
+func (s *ClockTestSuite) TestSendingTime() {
+	s.session.State = inSession{}
+	s.MockApp.On("ToAdmin")
+	s.session.Timeout(s.session, internal.NeedHeartbeat)
+
+	s.LastToAdminMessageSent()
+	sendingTime, err := s.MockApp.lastToAdmin.Header.GetTime(tagSendingTime)
+	s.Nil(err)
+	s.Equal(s.clock.now, sendingTime)
+}
+
+func (s *ClockTestSuite) TestCheckSendingTime() {
+	msg := s.NewOrderSingle()
+	msg.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: s.clock.now.Add(-time.Minute)})
+	s.Nil(s.session.checkSendingTime(msg))
+
+	msg.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: time.Now()})
+	s.NotNil(s.session.checkSendingTime(msg), "SendingTime is checked against the session clock")
+}
+
+func (s *ClockTestSuite) TestLogonTimeout() {
+	s.session.State = latentState{}
+	s.session.InitiateLogon = true
+	s.session.LogonTimeout = 10 * time.Second
+	s.MockApp.On("ToAdmin")
+	s.session.onAdmin(connect{messageOut: s.Receiver.sendChannel})
+	s.State(logonState{})
+
+	s.Require().Len(s.clock.timers, 1)
+	s.Equal(10*time.Second, s.clock.timers[0].d)
+
+	s.clock.timers[0].c <- s.clock.now.Add(10 * time.Second)
+	s.Equal(internal.LogonTimeout, <-s.session.sessionEvent)
+}
@@ -1,24 +1,25 @@
 package main
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
 	"log"
 	"os"
-	"path"
-	"strconv"
+	"path/filepath"
 	"strings"
-	"sync"
-	"text/template"
 
-	"github.com/quickfixgo/quickfix/cmd/generate-fix/internal"
 	"github.com/quickfixgo/quickfix/datadictionary"
+	"github.com/quickfixgo/quickfix/datadictionary/gen"
 )
 
 var (
-	waitGroup sync.WaitGroup
-	errors    = make(chan error)
+	useFloat       = flag.Bool("use-float", false, "By default, FIX float fields are represented as arbitrary-precision fixed-point decimal numbers.  Set to 'true' to instead generate FIX float fields as float64 values.")
+	useUDecimal    = flag.Bool("use-udecimal", false, "By default, FIX uses the shopspring/decimal library for fixed-point decimal numbers.  Set to 'true' to instead use the quagmt/udecimal library.")
+	pkgRoot        = flag.String("pkg-root", gen.DefaultPackageRoot, "Set a string here to provide a custom import path for generated packages.")
+	noEnums        = flag.Bool("no-enums", false, "Set to 'true' to generate fields with enumerated values as fields of their FIX type, without the enum package.")
+	fieldGetters   = flag.Bool("field-getters", false, "Set to 'true' to generate getters returning fields instead of their values.")
+	pointerGetters = flag.Bool("pointer-getters", false, "Set to 'true' to generate getters returning a pointer, nil if the field is not present.")
+	patch          = flag.String("patch", "", "comma separated paths of data dictionary patches to merge into the data dictionaries of their version")
 )
 
 func usage() {
@@ -27,91 +28,54 @@ func usage() {
 	os.Exit(2)
 }
 
-func getPackageName(fixSpec *datadictionary.DataDictionary) string {
-	pkg := strings.ToLower(fixSpec.FIXType) + strconv.Itoa(fixSpec.Major) + strconv.Itoa(fixSpec.Minor)
-
-	if fixSpec.ServicePack != 0 {
-		pkg += "sp" + strconv.Itoa(fixSpec.ServicePack)
+// parsePatches parses the data dictionary patches of the patch flag.
+func parsePatches() ([]*datadictionary.XMLDoc, error) {
+	if *patch == "" {
+		return nil, nil
 	}
 
-	return pkg
-}
-
-func getTransportPackageName(fixSpec *datadictionary.DataDictionary) string {
-	if fixSpec.Major >= 5 {
-		return "fixt11"
+	var patches []*datadictionary.XMLDoc
+	for _, patchPath := range strings.Split(*patch, ",") {
+		doc, err := parseXMLDoc(patchPath)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %v: %v", patchPath, err)
+		}
+		patches = append(patches, doc)
 	}
-	return getPackageName(fixSpec)
-}
 
-type component struct {
-	Package          string
-	FIXPackage       string
-	TransportPackage string
-	FIXSpec          *datadictionary.DataDictionary
-	Name             string
-	*datadictionary.MessageDef
+	return patches, nil
 }
 
-func genHeader(pkg string, spec *datadictionary.DataDictionary) {
-	c := component{
-		Package:    pkg,
-		Name:       "Header",
-		MessageDef: spec.Header,
-		FIXSpec:    spec,
+// parseSpec parses the data dictionary at dataDictPath with the patches of its version merged into it. Patches that
+// do not set a version are merged into all data dictionaries.
+func parseSpec(dataDictPath string, patches []*datadictionary.XMLDoc) (*datadictionary.DataDictionary, error) {
+	doc, err := parseXMLDoc(dataDictPath)
+	if err != nil {
+		return nil, err
 	}
-	gen(internal.HeaderTemplate, path.Join(pkg, "header.generated.go"), c)
-}
 
-func genTrailer(pkg string, spec *datadictionary.DataDictionary) {
-	c := component{
-		Package:    pkg,
-		Name:       "Trailer",
-		MessageDef: spec.Trailer,
-	}
-	gen(internal.TrailerTemplate, path.Join(pkg, "trailer.generated.go"), c)
-}
+	for _, p := range patches {
+		if (p.Type != "" && p.Type != doc.Type) || (p.Major != "" && p.Major != doc.Major) ||
+			(p.Minor != "" && p.Minor != doc.Minor) || (p.ServicePack != 0 && p.ServicePack != doc.ServicePack) {
+			continue
+		}
 
-func genMessage(fixPkg string, spec *datadictionary.DataDictionary, msg *datadictionary.MessageDef) {
-	pkgName := strings.ToLower(msg.Name)
-	transportPkg := getTransportPackageName(spec)
-
-	c := component{
-		Package:          pkgName,
-		FIXPackage:       fixPkg,
-		TransportPackage: transportPkg,
-		FIXSpec:          spec,
-		Name:             msg.Name,
-		MessageDef:       msg,
+		if err := doc.Merge(p); err != nil {
+			return nil, err
+		}
 	}
 
-	gen(internal.MessageTemplate, path.Join(fixPkg, pkgName, msg.Name+".generated.go"), c)
-}
-
-func genTags() {
-	gen(internal.TagTemplate, "tag/tag_numbers.generated.go", internal.GlobalFieldTypes)
-}
-
-func genFields() {
-	gen(internal.FieldTemplate, "field/fields.generated.go", internal.GlobalFieldTypes)
-}
-
-func genEnums() {
-	gen(internal.EnumTemplate, "enum/enums.generated.go", internal.GlobalFieldTypes)
+	return datadictionary.Build(doc)
 }
 
-func gen(t *template.Template, fileOut string, data interface{}) {
-	defer waitGroup.Done()
-	writer := new(bytes.Buffer)
-
-	if err := t.Execute(writer, data); err != nil {
-		errors <- err
-		return
+func parseXMLDoc(path string) (*datadictionary.XMLDoc, error) {
+	xmlFile, err := os.Open(path)
+	if err != nil {
+		return nil, err
 	}
+	defer xmlFile.Close()
 
-	if err := internal.WriteFile(fileOut, writer.String()); err != nil {
-		errors <- err
-	}
+	return datadictionary.ParseXMLDoc(xmlFile)
 }
 
 func main() {
@@ -133,62 +97,49 @@ func main() {
 			args = append(args, strings.Replace(dictpath, "FIX50", "FIXT11", -1))
 		}
 	}
+
+	patches, err := parsePatches()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	specs := []*datadictionary.DataDictionary{}
 
 	for _, dataDictPath := range args {
-		spec, err := datadictionary.Parse(dataDictPath)
+		spec, err := parseSpec(dataDictPath, patches)
 		if err != nil {
 			log.Fatalf("Error Parsing %v: %v", dataDictPath, err)
 		}
 		specs = append(specs, spec)
 	}
 
-	internal.BuildGlobalFieldTypes(specs)
-
-	waitGroup.Add(1)
-	go genTags()
-	waitGroup.Add(1)
-	go genFields()
-	waitGroup.Add(1)
-	go genEnums()
-
-	for _, spec := range specs {
-		pkg := getPackageName(spec)
-
-		if fi, err := os.Stat(pkg); os.IsNotExist(err) {
-			if err := os.Mkdir(pkg, os.ModePerm); err != nil {
-				log.Fatal(err)
-			}
-		} else if !fi.IsDir() {
-			log.Fatalf("%v/ is not a directory", pkg)
-		}
+	opts := gen.Options{
+		PackageRoot: *pkgRoot,
+		NoEnums:     *noEnums,
+		Pointers:    *pointerGetters,
+	}
+	if *useFloat {
+		opts.Float = gen.Float64
+	} else if *useUDecimal {
+		opts.Float = gen.UDecimal
+	}
+	if *fieldGetters {
+		opts.Getters = gen.FieldGetters
+	}
 
-		switch pkg {
-		// Uses fixt11 header/trailer.
-		case "fix50", "fix50sp1", "fix50sp2":
-		default:
-			waitGroup.Add(1)
-			go genHeader(pkg, spec)
+	files, err := gen.Generate(specs, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-			waitGroup.Add(1)
-			go genTrailer(pkg, spec)
+	for _, f := range files {
+		filePath := filepath.FromSlash(f.Path)
+		if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+			log.Fatal(err)
 		}
 
-		for _, m := range spec.Messages {
-			waitGroup.Add(1)
-			go genMessage(pkg, spec, m)
+		if err := os.WriteFile(filePath, f.Content, 0o644); err != nil {
+			log.Fatal(err)
 		}
 	}
-
-	go func() {
-		waitGroup.Wait()
-		close(errors)
-	}()
-
-	var h internal.ErrorHandler
-	for err := range errors {
-		h.Handle(err)
-	}
-
-	os.Exit(h.ReturnCode)
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+	"github.com/quickfixgo/quickfix/encoding/fixproto"
+)
+
+var (
+	pkgFlag       = flag.String("pkg", "", "protobuf package of the messages, defaults to the FIX version, e.g. fix44")
+	goPackageFlag = flag.String("go_package", "", "go_package option of the generated .proto file")
+	transportFlag = flag.String("transport", "", "path to the transport data dictionary of FIXT.1.1 application dictionaries")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %v [flags] <path to data dictionary> ... \n", os.Args[0])
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+	}
+
+	var transport *datadictionary.DataDictionary
+	if *transportFlag != "" {
+		var err error
+		if transport, err = datadictionary.Parse(*transportFlag); err != nil {
+			log.Fatalf("Error Parsing %v: %v", *transportFlag, err)
+		}
+	}
+
+	for _, dictPath := range flag.Args() {
+		dict, err := datadictionary.Parse(dictPath)
+		if err != nil {
+			log.Fatalf("Error Parsing %v: %v", dictPath, err)
+		}
+
+		pkg := *pkgFlag
+		if pkg == "" {
+			pkg = fixproto.DefaultPackage(dict)
+		}
+
+		file, err := fixproto.Descriptor(transport, dict, pkg)
+		if err != nil {
+			log.Fatalf("Error Generating %v: %v", dictPath, err)
+		}
+		if *goPackageFlag != "" {
+			file.Options = &descriptorpb.FileOptions{GoPackage: proto.String(*goPackageFlag)}
+		}
+
+		if err := os.WriteFile(file.GetName(), fixproto.Proto(file), 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path"
+
+	"github.com/quickfixgo/quickfix/encoding/sbe"
+)
+
+var pkgFlag = flag.String("pkg", "", "package name of the generated codecs, defaults to the package of the schema")
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %v [flags] <path to SBE message schema> ... \n", os.Args[0])
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+	}
+
+	for _, schemaPath := range flag.Args() {
+		schema, err := sbe.ParseFile(schemaPath)
+		if err != nil {
+			log.Fatalf("Error Parsing %v: %v", schemaPath, err)
+		}
+
+		pkg := *pkgFlag
+		if pkg == "" {
+			pkg = path.Base(schema.Package)
+		}
+
+		source, err := sbe.Generate(schema, pkg)
+		if err != nil {
+			log.Fatalf("Error Generating %v: %v", schemaPath, err)
+		}
+
+		if err := os.MkdirAll(pkg, os.ModePerm); err != nil {
+			log.Fatal(err)
+		}
+
+		if err := os.WriteFile(path.Join(pkg, pkg+".generated.go"), source, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
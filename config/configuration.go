@@ -10,6 +10,9 @@ used in rare cases to disambiguate otherwise identical sessions.
 
 The quickfix.ParseSettings(reader io.Reader) func will pull settings
 out of any stream, most commonly, a file stream.
+quickfix.ParseYAMLSettings and quickfix.ParseJSONSettings read the same
+settings from YAML and JSON documents, with a default mapping and a
+sequence of sessions in place of the headings below.
 If you decide to write your own components,
 (storage for a particular database, a new kind of connector
 etc...), you may also use the session settings to store settings
@@ -258,8 +261,33 @@ const (
 	//  - Local (The zone on host)
 	TimeZone string = "TimeZone"
 
+	// Holidays lists dates on which the session is not active,
+	// for example exchange holidays within a week-long session.
+	// A session that spans a holiday is reset when it becomes active again.
+	// Use in combination with StartTime and EndTime.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - Comma delimited list of dates in the format YYYY-MM-DD, in the time zone configured by TimeZone (e.g. "2024-12-25,2025-01-01").
+	Holidays string = "Holidays"
+
+	// HolidayFile is the path to a file listing dates on which the session is not active, in addition to any configured by Holidays.
+	// Use in combination with StartTime and EndTime.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A valid path to a file with one date in the format YYYY-MM-DD per line. Blank lines and lines starting with # are ignored.
+	HolidayFile string = "HolidayFile"
+
 	// TimeStampPrecision determines precision for timestamps in (Orig)SendingTime fields in outbound messages.
 	// Only available for FIX.4.2 and greater, FIX versions earlier than FIX.4.2 will use timestamp resolution in seconds.
+	// Use MICROS or NANOS where clock synchronisation rules such as MiFID II RTS 25 require sub-millisecond precision.
 	//
 	// Required: No
 	//
@@ -319,6 +347,7 @@ const (
 	ResetOnDisconnect string = "ResetOnDisconnect"
 
 	// ResetSeqTime determines a time which a logon with a seqnum reset will be sent while keeping the session connected.
+	// The logon is only sent if the session is logged on at that time.
 	//
 	// Required: No
 	//
@@ -351,6 +380,9 @@ const (
 	//
 	// Default: No dictionary, and QuickFIX/Go does not attempt to load any standard dictionaries
 	//
+	// The path is opened in the file system set with SessionSettings.SetDataDictionaryFS, such as an embed.FS, if
+	// there is one. A session's data dictionaries may be replaced while it runs with quickfix.ReloadDictionary.
+	//
 	// Valid Values:
 	//  - A filepath to a XML file with read access.
 	DataDictionary string = "DataDictionary"
@@ -387,6 +419,10 @@ const (
 	//  # Use BeginString suffix for app version
 	//  AppDataDictionary.FIX.4.4=FIX44.xml
 	//
+	// Application messages are parsed and validated with the dictionary of their ApplVerID(1128), or of the
+	// DefaultApplVerID sent by the counterparty on Logon if they have none. If dictionaries are set for more than one
+	// application version, a Logon with a DefaultApplVerID that has no dictionary is rejected.
+	//
 	// QuickFIX/Go repo contains the following standard dictionaries in the spec/ directory
 	//  - FIX50SP2.xml
 	//  - FIX50SP1.xml
@@ -454,6 +490,78 @@ const (
 	//  - N
 	ValidateFieldsOutOfOrder string = "ValidateFieldsOutOfOrder"
 
+	// ValidateRequiredFields if set to N, messages missing fields required by the data dictionary will not be rejected.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	ValidateRequiredFields string = "ValidateRequiredFields"
+
+	// ValidateFieldValues if set to N, values that are not among the enumerated values of a field in the data dictionary
+	// will not be rejected.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	ValidateFieldValues string = "ValidateFieldValues"
+
+	// ValidationMsgTypeOverrides disables validation checks for messages of the listed MsgTypes.
+	// Each override is a MsgType and a comma separated list of checks, and overrides are separated by semicolons,
+	// e.g. D=Required,Values;8=Unknown.
+	//
+	// Required: No
+	//
+	// Default: No overrides
+	//
+	// Valid Values:
+	//  - Semicolon separated MsgType=Check,Check overrides, where each Check is one of Required, Order, Values or Unknown.
+	ValidationMsgTypeOverrides string = "ValidationMsgTypeOverrides"
+
+	// ValidationTagOverrides disables validation checks of the listed fields in all messages.
+	// Each override is a tag and a comma separated list of checks, and overrides are separated by semicolons,
+	// e.g. 9001=Unknown;58=Values.
+	//
+	// Required: No
+	//
+	// Default: No overrides
+	//
+	// Valid Values:
+	//  - Semicolon separated Tag=Check,Check overrides, where each Check is one of Required, Order, Values or Unknown.
+	ValidationTagOverrides string = "ValidationTagOverrides"
+
+	// EnumValidation sets how values that are not among the enumerated values of a field in the data dictionary are
+	// treated, if ValidateFieldValues is Y. Warn accepts the message and logs a warning event.
+	//
+	// Required: No
+	//
+	// Default: Strict
+	//
+	// Valid Values:
+	//  - Strict
+	//  - Warn
+	//  - Lenient
+	EnumValidation string = "EnumValidation"
+
+	// AdditionalEnumValues adds valid values to the enumerated values of fields in the data dictionary, such as the
+	// values a venue adds to the standard ones. Each entry is a tag and a comma separated list of values, and entries
+	// are separated by semicolons, e.g. 40=X,Y;59=Z.
+	//
+	// Required: No
+	//
+	// Default: No values
+	//
+	// Valid Values:
+	//  - Semicolon separated Tag=Value,Value entries.
+	AdditionalEnumValues string = "AdditionalEnumValues"
+
 	// CheckLatency if set to Y, messages must be received from the counter-party within a defined number of seconds.
 	// It is useful to turn this off if a system uses localtime for it's timestamps instead of GMT.
 	//
@@ -493,6 +601,53 @@ const (
 	//  - Any positive integer
 	ReconnectInterval string = "ReconnectInterval"
 
+	// ReconnectBackoffMultiplier multiplies the time between reconnection attempts after each consecutive failed attempt,
+	// starting from ReconnectInterval. The time is reset to ReconnectInterval once a connection succeeds.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: 1 (a fixed ReconnectInterval)
+	//
+	// Valid Values:
+	//  - A number greater than or equal to 1, e.g. 2 to double the time after each attempt
+	ReconnectBackoffMultiplier string = "ReconnectBackoffMultiplier"
+
+	// MaxReconnectInterval caps the time between reconnection attempts when ReconnectBackoffMultiplier is set.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: 0 (no cap)
+	//
+	// Valid Values:
+	//  - A valid go time.Duration or an integer of seconds, not less than ReconnectInterval
+	MaxReconnectInterval string = "MaxReconnectInterval"
+
+	// ReconnectJitter randomizes the time between reconnection attempts by up to this fraction either way,
+	// so that initiators disconnected at the same time do not reconnect at the same time.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: 0 (no jitter)
+	//
+	// Valid Values:
+	//  - A number from 0 up to but excluding 1, e.g. 0.2 for up to 20% earlier or later
+	ReconnectJitter string = "ReconnectJitter"
+
+	// MaxReconnectAttempts sets the number of consecutive failed connection attempts after which the Initiator's
+	// ReconnectListener is alerted. The Initiator keeps reconnecting.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: 0 (no alert)
+	//
+	// Valid Values:
+	//  - A positive integer
+	MaxReconnectAttempts string = "MaxReconnectAttempts"
+
 	// LogoutTimeout defines the number of seconds to wait for a logout response before disconnecting.
 	// Only used for initiators.
 	// Value must be positive integer.
@@ -529,6 +684,28 @@ const (
 	//  - Any positive integer
 	HeartBtInt string = "HeartBtInt"
 
+	// TestRequestDelayMultiplier sets how long, as a fraction of HeartBtInt, to wait past a missed heartbeat
+	// before sending a TestRequest, and after the TestRequest before considering the session timed out.
+	//
+	// Required: No
+	//
+	// Default: 0.2
+	//
+	// Valid Values:
+	//  - A positive number
+	TestRequestDelayMultiplier string = "TestRequestDelayMultiplier"
+
+	// ApplicationCallbackTimeout sets the deadline of the contexts passed to the OnLogon, ToApp and FromApp callbacks
+	// of an ApplicationWithContext.
+	//
+	// Required: No
+	//
+	// Default: No deadline
+	//
+	// Valid Values:
+	//  - A valid go time.Duration
+	ApplicationCallbackTimeout string = "ApplicationCallbackTimeout"
+
 	// SocketConnectHost sets the host to attempt to connect to.
 	// In config files you can also set SocketConnectHost<n> where n is a positive integer.
 	// This allows for alternate socket hosts for connecting to a session for failover.
@@ -540,6 +717,9 @@ const (
 	//
 	// Valid Values:
 	//  - A valid IPv4 or IPv6 address or a domain name
+	//  - unix:///path.sock to connect to a unix domain socket, in which case SocketConnectPort is not used
+	//  - pipe://name to connect to an acceptor in the same process with SocketAcceptHost=pipe://name,
+	//    in which case SocketConnectPort is not used
 	SocketConnectHost string = "SocketConnectHost"
 
 	// SocketConnectPort sets the socket port for connecting to a session.
@@ -555,6 +735,36 @@ const (
 	//  - A positive integer
 	SocketConnectPort string = "SocketConnectPort"
 
+	// SocketConnectTimeout sets the timeout for connecting to SocketConnectHost and SocketConnectPort.
+	// In config files you can also set SocketConnectTimeout<n> for the matching SocketConnectHost<n> and SocketConnectPort<n>,
+	// which otherwise use SocketConnectTimeout.
+	// Only used for initiators.
+	//
+	// Example Values:
+	//  - SocketConnectTimeout=5s # 5 seconds
+	//  - SocketConnectTimeout=10 # 10 seconds
+	//
+	// Required: No
+	//
+	// Default: 0 (no timeout besides SocketTimeout)
+	//
+	// Valid Values:
+	//  - A valid go time.Duration or a non-negative integer of seconds
+	SocketConnectTimeout string = "SocketConnectTimeout"
+
+	// SocketConnectRotation determines which of the SocketConnectHost<n> and SocketConnectPort<n> endpoints
+	// an initiator connects to when reconnecting.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: RoundRobin
+	//
+	// Valid Values:
+	//  - RoundRobin (each reconnect is to the next endpoint)
+	//  - Sticky (reconnects are to the same endpoint until connecting to it fails, then to the next endpoint)
+	SocketConnectRotation string = "SocketConnectRotation"
+
 	// SocketTimeout sets the duration of timeout for TLS handshake.
 	// Only used for initiators.
 	//
@@ -570,6 +780,110 @@ const (
 	//  - A valid go time.Duration
 	SocketTimeout string = "SocketTimeout"
 
+	// SocketNoDelay sets TCP_NODELAY on connections, disabling Nagle's algorithm if set to Y.
+	// For acceptors it is set on all connections accepted, from the default section.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	SocketNoDelay string = "SocketNoDelay"
+
+	// SocketKeepAlive enables TCP keep-alive probes on connections.
+	// For acceptors it is set on all connections accepted, from the default section.
+	//
+	// Required: No
+	//
+	// Default: Y, with the operating system's probe interval and count unless SocketKeepAliveInterval or SocketKeepAliveCount are set
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	SocketKeepAlive string = "SocketKeepAlive"
+
+	// SocketKeepAliveInterval sets the idle time before the first TCP keep-alive probe, and the time between probes.
+	// For acceptors it is set on all connections accepted, from the default section.
+	//
+	// Required: No
+	//
+	// Default: The operating system default
+	//
+	// Valid Values:
+	//  - A positive go time.Duration, e.g. 15s
+	SocketKeepAliveInterval string = "SocketKeepAliveInterval"
+
+	// SocketKeepAliveCount sets the number of unanswered TCP keep-alive probes after which the connection is dropped.
+	// For acceptors it is set on all connections accepted, from the default section.
+	//
+	// Required: No
+	//
+	// Default: The operating system default
+	//
+	// Valid Values:
+	//  - A positive integer
+	SocketKeepAliveCount string = "SocketKeepAliveCount"
+
+	// SocketSendBufferSize sets the size in bytes of the socket send buffer, SO_SNDBUF, of connections.
+	// For acceptors it is set on all connections accepted, from the default section.
+	//
+	// Required: No
+	//
+	// Default: The operating system default
+	//
+	// Valid Values:
+	//  - A positive integer
+	SocketSendBufferSize string = "SocketSendBufferSize"
+
+	// SocketReceiveBufferSize sets the size in bytes of the socket receive buffer, SO_RCVBUF, of connections.
+	// For acceptors it is set on all connections accepted, from the default section.
+	//
+	// Required: No
+	//
+	// Default: The operating system default
+	//
+	// Valid Values:
+	//  - A positive integer
+	SocketReceiveBufferSize string = "SocketReceiveBufferSize"
+
+	// OutboundBatchMaxBytes enables coalescing outbound messages queued for a connection into a single write, and
+	// sets the size in bytes at which a batch is written. Batches are written with a vectored write, writev, to TCP
+	// connections.
+	//
+	// Required: No
+	//
+	// Default: 0, each message is written on its own
+	//
+	// Valid Values:
+	//  - A non-negative integer
+	OutboundBatchMaxBytes string = "OutboundBatchMaxBytes"
+
+	// OutboundBatchFlushLatency sets the longest a batch waits for more messages after its first message, if
+	// OutboundBatchMaxBytes is set. If 0, a batch is written as soon as no more messages are queued.
+	//
+	// Required: No
+	//
+	// Default: 0
+	//
+	// Valid Values:
+	//  - A duration, e.g. 500us
+	OutboundBatchFlushLatency string = "OutboundBatchFlushLatency"
+
+	// SocketReusePort sets SO_REUSEPORT on the listeners of an acceptor, so that several processes can accept
+	// connections on the same port. Not supported on Windows.
+	// Only used for acceptors, from the default section.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	SocketReusePort string = "SocketReusePort"
+
 	// ProxyType sets the type of proxy server to connect to.
 	// Only used for initiators.
 	//
@@ -630,6 +944,7 @@ const (
 	// Acceptor-only settings.
 
 	// SocketAcceptHost sets the address for listening on incoming connections.
+	// May be set per session, with SocketAcceptPort, to accept sessions on different interfaces.
 	// Used for acceptors only.
 	//
 	// Common examples:
@@ -642,9 +957,15 @@ const (
 	//
 	// Valid Values:
 	//  - A valid IPv4 or IPv6 address or a domain name
+	//  - unix:///path.sock to listen on a unix domain socket, in which case SocketAcceptPort is not used
+	//  - pipe://name to accept in-process connections from initiators with SocketConnectHost=pipe://name,
+	//    in which case SocketAcceptPort is not used
 	SocketAcceptHost string = "SocketAcceptHost"
 
 	// SocketAcceptPort sets the socket port for listening to incoming connections.
+	// May be set per session, the acceptor listens on each distinct SocketAcceptHost and SocketAcceptPort
+	// and only accepts a session on the address it is configured for. Sessions sharing an address must agree on
+	// the TLS, TCP and PROXY protocol settings of the listener, so TLS and plaintext sessions need different ports.
 	// Used for acceptors only.
 	//
 	// Required: Yes for acceptors
@@ -680,6 +1001,68 @@ const (
 	//  - N
 	UseTCPProxy string = "UseTCPProxy"
 
+	// RequireTCPProxyHeader if set to Y with UseTCPProxy, rejects connections without a PROXY protocol header.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Default: N (connections without a PROXY protocol header are accepted with their own address)
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	RequireTCPProxyHeader string = "RequireTCPProxyHeader"
+
+	// TCPProxyTrustedAddresses restricts the load balancers allowed to send a PROXY protocol header with UseTCPProxy.
+	// Connections from other addresses that send a header are rejected, so clients cannot supply an address of their choosing.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Default: Any address is trusted
+	//
+	// Valid Values:
+	//  - A comma separated list of IP addresses and CIDR blocks, e.g. 10.0.0.0/8,192.168.1.10
+	TCPProxyTrustedAddresses string = "TCPProxyTrustedAddresses"
+
+	// AllowedRemoteAddresses restricts the addresses a session may connect to an acceptor from.
+	// Connections from other addresses are rejected before the Logon is processed, with UseTCPProxy the client
+	// address from the PROXY protocol header is checked. Set in the default section it also restricts dynamic sessions.
+	// Connections over unix sockets and pipes have no IP address, so are always rejected.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Default: Any address is allowed
+	//
+	// Valid Values:
+	//  - A comma separated list of IP addresses and CIDR blocks, e.g. 10.0.0.0/8,192.168.1.10
+	AllowedRemoteAddresses string = "AllowedRemoteAddresses"
+
+	// MaxMessageSize sets the largest inbound message in bytes.
+	// The connection is closed when a peer sends a larger message, since the stream cannot be reliably resynchronized.
+	// Set in the default section it also limits the Logon an acceptor reads before the session is known.
+	//
+	// Required: No
+	//
+	// Default: No limit
+	//
+	// Valid Values:
+	//  - A positive integer
+	MaxMessageSize string = "MaxMessageSize"
+
+	// MaxInboundBytesPerSecond limits the rate a peer may send at.
+	// The connection is closed when more bytes than this are read in a second.
+	// Set in the default section it also limits an acceptor connection before the session is known.
+	//
+	// Required: No
+	//
+	// Default: No limit
+	//
+	// Valid Values:
+	//  - A positive integer
+	MaxInboundBytesPerSecond string = "MaxInboundBytesPerSecond"
+
 	// DynamicSessions if set to Y, allows sessions to connect to this acceptor
 	// without explicitly stating SenderCompID/TargetCompID in the config file for the acceptor.
 	// Used for acceptors only.
@@ -708,6 +1091,65 @@ const (
 	//  - Y
 	//  - N
 	DynamicQualifier string = "DynamicQualifier"
+
+	// DynamicQualifierTemplate is used in conjunction with DynamicSessions.
+	// It sets the Qualifier of dynamically created sessions, so that sessions with the same comp IDs
+	// but e.g. different TargetSubIDs are registered separately. DynamicQualifierTemplate takes precedence over DynamicQualifier.
+	// The placeholders {BeginString}, {SenderCompID}, {SenderSubID}, {SenderLocationID}, {TargetCompID}, {TargetSubID}
+	// and {TargetLocationID} are replaced with the fields of the acceptor's SessionID,
+	// so {TargetSubID} is the counterparty's SenderSubID.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Default: (no Qualifier)
+	//
+	// Valid Values:
+	//  - A template string, e.g. {TargetSubID}
+	DynamicQualifierTemplate string = "DynamicQualifierTemplate"
+
+	// AcceptorTemplate if set to Y, the session is a template for the sessions of an acceptor rather than a session.
+	// Any of BeginString, SenderCompID, SenderSubID, SenderLocationID, TargetCompID, TargetSubID and TargetLocationID
+	// may be *, which matches any value. A Logon for a session that is not configured, matching the template, creates a
+	// session with the settings of the template, e.g. SenderCompID=BROKER and TargetCompID=* for the clients of BROKER.
+	// The most specific template matching the Logon is used.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	AcceptorTemplate string = "AcceptorTemplate"
+
+	// EventLoopMode chooses how the sessions of an acceptor or initiator run. In dedicated mode each session runs its
+	// own goroutine, with a goroutine per timer. In shared mode the sessions are spread across EventLoopShards event
+	// loops, each session always running on the same one, whose goroutine processes the messages, timers and
+	// administrative requests of all its sessions on a timer wheel. This reduces the scheduler overhead of many mostly
+	// idle sessions. Connections still read and write on goroutines of their own, parked by the Go netpoller until
+	// they are ready. Only read from the default section.
+	//
+	// Required: No
+	//
+	// Default: dedicated
+	//
+	// Valid Values:
+	//  - dedicated
+	//  - shared
+	EventLoopMode string = "EventLoopMode"
+
+	// EventLoopShards is the number of event loops sessions are spread across when EventLoopMode=shared.
+	// Only read from the default section.
+	//
+	// Required: No
+	//
+	// Default: The number of CPUs usable by the process, GOMAXPROCS
+	//
+	// Valid Values:
+	//  - A positive integer
+	EventLoopShards string = "EventLoopShards"
 )
 
 const (
@@ -827,17 +1269,58 @@ const (
 	//  - TLS10
 	//  - TLS11
 	//  - TLS12
+	//  - TLS13
 	SocketMinimumTLSVersion string = "SocketMinimumTLSVersion"
 
-	// SocketUseSSL if set to Y, an initiator will use TLS even if client certificates are not present.
-	// It is set to N by default, meaning TLS will not be used if SocketPrivateKeyFile or SocketCertificateFile are not supplied.
-	//
-	// This is used for initiators in the case where you do not need to provide client certificates, but still need to use
-	// a TLS connection and should verify the server certificate(s).
+	// SocketCipherSuites restricts the TLS 1.0-1.2 cipher suites used for secure connections.
+	// TLS 1.3 cipher suites are not configurable.
 	//
 	// Required: No
 	//
-	// Default: N
+	// Default: The crypto/tls defaults
+	//
+	// Valid Values:
+	//  - A comma separated list of cipher suite names, as returned by crypto/tls.CipherSuites, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+	SocketCipherSuites string = "SocketCipherSuites"
+
+	// SocketClientAuth sets the policy of an acceptor for TLS client certificates.
+	// If SocketClientAuth is not set, client certificates are required and verified unless SocketUseSSL is set to Y.
+	// Only used for acceptors.
+	//
+	// Required: No
+	//
+	// Default: RequireAndVerifyClientCert
+	//
+	// Valid Values:
+	//  - NoClientCert
+	//  - RequestClientCert
+	//  - RequireAnyClientCert
+	//  - VerifyClientCertIfGiven
+	//  - RequireAndVerifyClientCert
+	SocketClientAuth string = "SocketClientAuth"
+
+	// SocketCertificateReload if set to Y, the key pair of SocketPrivateKeyFile and SocketCertificateFile is loaded again
+	// for new connections once either file is modified, so that certificates can be renewed without a restart.
+	// Existing connections are not affected.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	SocketCertificateReload string = "SocketCertificateReload"
+
+	// SocketUseSSL if set to Y, an initiator will use TLS even if client certificates are not present.
+	// It is set to N by default, meaning TLS will not be used if SocketPrivateKeyFile or SocketCertificateFile are not supplied.
+	//
+	// This is used for initiators in the case where you do not need to provide client certificates, but still need to use
+	// a TLS connection and should verify the server certificate(s).
+	//
+	// Required: No
+	//
+	// Default: N
 	//
 	// Valid Values:
 	//  - Y
@@ -848,6 +1331,29 @@ const (
 const (
 	// Logging settings.
 
+	// LogRedact determines if the values of sensitive fields are masked in message and event logs.
+	// Password(554), NewPassword(925) and RawData(96) are masked, along with the tags of LogRedactTags and
+	// those registered with quickfix.RegisterRedactedTags.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	LogRedact string = "LogRedact"
+
+	// LogRedactTags sets additional tags whose values are masked in message and event logs.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A comma separated list of tags, e.g. 553,1402
+	LogRedactTags string = "LogRedactTags"
+
 	// FileLogPath sets the directory path in which to write log files to.
 	// This will create the directory path if it does not already exist.
 	// FileLogPath is only relevant if also using quickfix.NewFileLogFactory(..) in code
@@ -861,13 +1367,60 @@ const (
 	//  - A valid path
 	FileLogPath string = "FileLogPath"
 
+	// FileLogMaxSize sets the size in bytes at which a log file is rotated.
+	// The current log file is renamed with the UTC time of rotation, e.g. FIX.4.2-SENDER-TARGET.messages.20240102-150405.000000.log,
+	// and a new current log file is started.
+	// FileLogMaxSize is only relevant if also using file.NewLogFactory(..) in code.
+	//
+	// Required: No
+	//
+	// Default: Log files are not rotated by size
+	//
+	// Valid Values:
+	//  - A positive integer
+	FileLogMaxSize string = "FileLogMaxSize"
+
+	// FileLogMaxAge sets how long a log file is written to before it is rotated.
+	// FileLogMaxAge is only relevant if also using file.NewLogFactory(..) in code.
+	//
+	// Required: No
+	//
+	// Default: Log files are not rotated by age
+	//
+	// Valid Values:
+	//  - A duration, e.g. 24h
+	FileLogMaxAge string = "FileLogMaxAge"
+
+	// FileLogMaxBackups sets how many rotated log files are kept, the oldest are removed on rotation.
+	// FileLogMaxBackups is only relevant if also using file.NewLogFactory(..) in code.
+	//
+	// Required: No
+	//
+	// Default: 0, all rotated log files are kept
+	//
+	// Valid Values:
+	//  - A non-negative integer
+	FileLogMaxBackups string = "FileLogMaxBackups"
+
+	// FileLogCompress determines if rotated log files are compressed with gzip, in the background.
+	// FileLogCompress is only relevant if also using file.NewLogFactory(..) in code.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	FileLogCompress string = "FileLogCompress"
+
 	// SQLLogDriver sets the name of the database driver to use for application logs (see https://go.dev/wiki/SQLDrivers for the list of available drivers).
 	// SQLLogDriver is only relevant if also using sql.NewLogFactory(..) in code
 	// when creating your LogFactory for your initiator or acceptor.
 	//
 	// Required: Only if using a sql db as your Log
 	//
-	// Default: N/A
+	// Default: SQLStoreDriver
 	//
 	// Valid Values:
 	//  - See https://go.dev/wiki/SQLDrivers
@@ -882,7 +1435,7 @@ const (
 	//
 	// Required: Only if using a sql db as your Log.
 	//
-	// Default: N/A
+	// Default: SQLStoreDataSourceName
 	//
 	// Valid Values:
 	//  - A string correspondinng to a datasource
@@ -903,12 +1456,36 @@ const (
 	//
 	// Required: No
 	//
-	// Default: 0 (forever)
+	// Default: SQLStoreConnMaxLifetime, or 0 (forever)
 	//
 	// Valid Values:
 	//  - A valid go time.Duration
 	SQLLogConnMaxLifetime string = "SQLLogConnMaxLifetime"
 
+	// SQLLogBatchSize sets how many messages or events are inserted into the log tables in one statement.
+	// Rows are held in memory until the batch is full, SQLLogBatchInterval has passed, or the initiator or acceptor stops,
+	// so are lost if the process exits before then.
+	// SQLLogBatchSize is only relevant if also using sql.NewLogFactory(..) in code.
+	//
+	// Required: No
+	//
+	// Default: 1, each message and event is inserted as it is logged
+	//
+	// Valid Values:
+	//  - A positive integer
+	SQLLogBatchSize string = "SQLLogBatchSize"
+
+	// SQLLogBatchInterval sets the longest time a message or event is held before inserting a batch that is not full.
+	// SQLLogBatchInterval is only relevant if also using sql.NewLogFactory(..) in code with SQLLogBatchSize.
+	//
+	// Required: No
+	//
+	// Default: 1s
+	//
+	// Valid Values:
+	//  - A valid go time.Duration
+	SQLLogBatchInterval string = "SQLLogBatchInterval"
+
 	// MongoLogConnection sets the MongoDB connection URL to use for application logs.
 	//
 	// See https://pkg.go.dev/go.mongodb.org/mongo-driver/mongo#Connect for more information.
@@ -954,209 +1531,1141 @@ const (
 	// Valid Values:
 	//  - A string corresponding to a MongoDB replica set
 	MongoLogReplicaSet string = "MongoLogReplicaSet"
-)
 
-const (
-	// Storage settings.
+	// KafkaLogBrokers sets the Kafka brokers to publish application logs to.
+	// KafkaLogBrokers is only relevant if also using kafka.NewLogFactory(..) in code
+	// when creating your LogFactory for your initiator or acceptor.
+	//
+	// Required: Only if using Kafka as your Log.
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A comma separated list of host:port addresses
+	KafkaLogBrokers string = "KafkaLogBrokers"
 
-	// PersistMessages if set to N, no messages will be persisted.
-	// This will force QuickFIX/Go to always send GapFills instead of resending messages.
-	// Use this if you know you never want to resend a message.
-	// This is useful for market data streams when logging all incoming messages is not important.
+	// KafkaLogMessageTopic sets the topic incoming and outgoing messages are published to, keyed by the SessionID.
+	// The placeholders {BeginString}, {SenderCompID}, {SenderSubID}, {SenderLocationID}, {TargetCompID}, {TargetSubID},
+	// {TargetLocationID} and {Qualifier} are replaced with the fields of the SessionID, and are empty for the global log.
+	// KafkaLogMessageTopic is only relevant if also using kafka.NewLogFactory(..) in code.
 	//
 	// Required: No
 	//
-	// Default: Y
+	// Default: quickfix.messages
 	//
 	// Valid Values:
-	//  - Y
-	//  - N
-	PersistMessages string = "PersistMessages"
+	//  - A topic name or pattern, e.g. fix.{SenderCompID}.{TargetCompID}.messages
+	KafkaLogMessageTopic string = "KafkaLogMessageTopic"
 
-	// FileStorePath sets the directory path in which to write sequence number and message files.
-	// This will create the directory path if it does not already exist.
-	// FileStorePath is only relevant if also using file.NewStoreFactory(..) in code
-	// when creating your MessageStoreFactory for your initiator or acceptor.
+	// KafkaLogEventTopic sets the topic events are published to, keyed by the SessionID.
+	// Takes the same placeholders as KafkaLogMessageTopic.
+	// KafkaLogEventTopic is only relevant if also using kafka.NewLogFactory(..) in code.
 	//
-	// Required: Only if using files as your MessageStore
+	// Required: No
 	//
-	// Default: N/A
+	// Default: quickfix.events
 	//
 	// Valid Values:
-	//  - A valid path
-	FileStorePath string = "FileStorePath"
+	//  - A topic name or pattern
+	KafkaLogEventTopic string = "KafkaLogEventTopic"
 
-	// FileStoreSync controls whether the FileStore syncs to the hard drive on every write.
-	// It's safer to sync, but it's also much slower.
-	// FileStoreSync is only relevant if also using file.NewStoreFactory(..) in code
-	// when creating your MessageStoreFactory for your initiator or acceptor.
+	// KafkaLogBufferSize sets how many messages and events are buffered for publishing,
+	// logging blocks while the buffer is full.
+	// KafkaLogBufferSize is only relevant if also using kafka.NewLogFactory(..) in code.
 	//
 	// Required: No
 	//
-	// Default: Y
+	// Default: 10000
 	//
 	// Valid Values:
-	//  - Y
-	//  - N
-	FileStoreSync string = "FileStoreSync"
+	//  - A positive integer
+	KafkaLogBufferSize string = "KafkaLogBufferSize"
 
-	// SQLStoreDriver sets the name of the database driver to use for message storage (see https://go.dev/wiki/SQLDrivers for the list of available drivers).
-	// SQLStoreDriver is only relevant if also using sql.NewStoreFactory(..) in code
-	// when creating your MessageStoreFactory for your initiator or acceptor.
+	// KafkaLogBatchSize sets the largest number of messages and events published to Kafka in one request.
+	// KafkaLogBatchSize is only relevant if also using kafka.NewLogFactory(..) in code.
 	//
-	// Required: Only if using a sql db as your MessageStore
+	// Required: No
 	//
-	// Default: N/A
+	// Default: 100
 	//
 	// Valid Values:
-	//  - See https://go.dev/wiki/SQLDrivers
-	SQLStoreDriver string = "SQLStoreDriver"
+	//  - A positive integer
+	KafkaLogBatchSize string = "KafkaLogBatchSize"
 
-	// SQLStoreDataSourceName sets the driver-specific data source name of the database to use for messagge storage.
-	// This usually consists of at least a database name and connection information.
-	// SQLStoreDataSourceName is only relevant if also using sql.NewStoreFactory(..) in code
-	// when creating your MessageStoreFactory for your initiator or acceptor.
+	// KafkaLogBatchTimeout sets how long the Kafka writer waits to fill a batch before publishing it.
+	// KafkaLogBatchTimeout is only relevant if also using kafka.NewLogFactory(..) in code.
 	//
-	// See https://pkg.go.dev/database/sql#Open for more information.
+	// Required: No
 	//
-	// Required: Only if using a sql db as your MessageStore
+	// Default: 10ms
+	//
+	// Valid Values:
+	//  - A valid go time.Duration
+	KafkaLogBatchTimeout string = "KafkaLogBatchTimeout"
+
+	// KafkaGatewayBrokers sets the Kafka brokers a gateway bridges sessions to.
+	// KafkaGatewayBrokers is only relevant if also using the Application of gateway/kafka.NewGateway(..) in code
+	// for your initiator or acceptor.
+	//
+	// Required: Only if using the Kafka gateway.
 	//
 	// Default: N/A
 	//
 	// Valid Values:
-	//  - A string correspondinng to a datasource
-	SQLStoreDataSourceName string = "SQLStoreDataSourceName"
+	//  - A comma separated list of host:port addresses
+	KafkaGatewayBrokers string = "KafkaGatewayBrokers"
 
-	// SQLStoreConnMaxLifetime sets the maximum duration of time that a database connection may be reused.
-	// See https://pkg.go.dev/database/sql#DB.SetConnMaxLifetime for more information.
+	// KafkaGatewayPublishTopic sets the topic application messages received from the session are published to.
+	// Takes the placeholders of KafkaLogMessageTopic, and {MsgType}, replaced with the MsgType of the message.
+	// KafkaGatewayPublishTopic is only relevant if also using gateway/kafka.NewGateway(..) in code.
 	//
-	// If your database server has a config option to close inactive connections after some duration (e.g. MySQL "wait_timeout"),
-	// set SQLStoreConnMaxLifetime to a value less than that duration.
+	// Required: No
 	//
-	// Example Values:
-	//  - SQLStoreConnMaxLifetime=14400s # 14400 seconds
-	//  - SQLStoreConnMaxLifetime=2h45m  # 2 hours and 45 minutes
+	// Default: fix.{SenderCompID}.{TargetCompID}.in
 	//
-	// SQLStoreConnMaxLifetime is only relevant if also using sql.NewStoreFactory(..) in code
-	// when creating your MessageStoreFactory for your initiator or acceptor.
+	// Valid Values:
+	//  - A topic name or pattern, e.g. fix.{TargetCompID}.{MsgType}
+	KafkaGatewayPublishTopic string = "KafkaGatewayPublishTopic"
+
+	// KafkaGatewaySubscribeTopic sets the topic whose messages are sent on the session, while it is logged on.
+	// Offsets are committed once a message is written to the connection, or given up on. Takes the placeholders of
+	// KafkaLogMessageTopic. KafkaGatewaySubscribeTopic is only relevant if also using gateway/kafka.NewGateway(..) in code.
 	//
 	// Required: No
 	//
-	// Default: 0 (forever)
+	// Default: N/A, no messages are sent from Kafka
 	//
 	// Valid Values:
-	//  - A valid go time.Duration
-	SQLStoreConnMaxLifetime string = "SQLStoreConnMaxLifetime"
+	//  - A topic name or pattern, e.g. fix.{SenderCompID}.{TargetCompID}.out
+	KafkaGatewaySubscribeTopic string = "KafkaGatewaySubscribeTopic"
 
-	// SQLStoreMessagesTableName defines the table name for the messages table. Default is "messages".
-	// If you use a different table name, you must set up your database accordingly.
+	// KafkaGatewayGroupID sets the consumer group reading KafkaGatewaySubscribeTopic.
+	// KafkaGatewayGroupID is only relevant if also using gateway/kafka.NewGateway(..) in code.
 	//
 	// Required: No
 	//
-	// Default: messages
+	// Default: quickfix
 	//
 	// Valid Values:
-	//	- A valid string
-	SQLStoreMessagesTableName = "SQLStoreMessagesTableName"
+	//  - A consumer group ID
+	KafkaGatewayGroupID string = "KafkaGatewayGroupID"
 
-	// SQLStoreSessionsTableName defines the table name for the messages table. Default is "sessions".
-	// If you use a different table name, you must set up your database accordingly.
+	// KafkaGatewayFormat sets the payload of the messages on Kafka.
+	// KafkaGatewayFormat is only relevant if also using gateway/kafka.NewGateway(..) in code.
 	//
 	// Required: No
 	//
-	// Default: sessions
+	// Default: FIX
 	//
 	// Valid Values:
-	//	- A valid string
-	SQLStoreSessionsTableName = "SQLStoreSessionsTableName"
+	//  - FIX (the raw FIX message)
+	//  - JSON (the FIX JSON encoding, with fields named by their tags)
+	KafkaGatewayFormat string = "KafkaGatewayFormat"
 
-	// MongoStoreConnection sets the MongoDB connection URL to use for message storage.
+	// KafkaGatewayKeyTag sets the tag whose value keys the messages published to Kafka, so that messages with the same
+	// value, e.g. of an order or instrument, keep their order within a partition. Messages without the tag, or all
+	// messages if it is not set, are keyed by the SessionID.
+	// KafkaGatewayKeyTag is only relevant if also using gateway/kafka.NewGateway(..) in code.
 	//
-	// See https://pkg.go.dev/go.mongodb.org/mongo-driver/mongo#Connect for more information.
+	// Required: No
 	//
-	// MongoStoreConnection is only relevant if also using mongo.NewStoreFactory(..) in code
-	// when creating your MessageStoreFactory for your initiator or acceptor.
+	// Default: N/A
 	//
-	// Required: Only if using MongoDB as your MessageStore
+	// Valid Values:
+	//  - A positive integer, e.g. 11 for ClOrdID or 55 for Symbol
+	KafkaGatewayKeyTag string = "KafkaGatewayKeyTag"
+
+	// KafkaGatewayMaxRetries sets how many times publishing a message to Kafka, or sending a message from Kafka on the
+	// session, is retried before it is given up on. A message received from the session that can not be published is
+	// rejected with a BusinessMessageReject. KafkaGatewayMaxRetries is only relevant if also using
+	// gateway/kafka.NewGateway(..) in code.
 	//
-	// Default: N/A
+	// Required: No
+	//
+	// Default: 3
 	//
 	// Valid Values:
-	//  - A string representing a MongoDB connection
-	MongoStoreConnection string = "MongoStoreConnection"
+	//  - A non-negative integer
+	KafkaGatewayMaxRetries string = "KafkaGatewayMaxRetries"
 
-	// MongoStoreDatabase sets the MongoDB-specific name of the database to use for message storage.
+	// KafkaGatewayRetryBackoff sets how long the gateway waits before the first retry, doubling for each retry after.
+	// KafkaGatewayRetryBackoff is only relevant if also using gateway/kafka.NewGateway(..) in code.
 	//
-	// See https://pkg.go.dev/go.mongodb.org/mongo-driver/mongo#Connect for more information.
+	// Required: No
 	//
-	// MongoStoreDatabase is only relevant if also using mongo.NewStoreFactory(..) in code
-	// when creating your MessageStoreFactory for your initiator or acceptor.
+	// Default: 100ms
 	//
-	// Required: Only if using MongoDB as your MessageStore
+	// Valid Values:
+	//  - A valid go time.Duration
+	KafkaGatewayRetryBackoff string = "KafkaGatewayRetryBackoff"
+
+	// KafkaGatewayPublishTimeout sets how long publishing a message received from the session may take, retries
+	// included, before it is rejected with a BusinessMessageReject. The session does not process other messages while
+	// publishing, so keep it well below HeartBtInt. KafkaGatewayPublishTimeout is only relevant if also using
+	// gateway/kafka.NewGateway(..) in code.
 	//
-	// Default: N/A
+	// Required: No
+	//
+	// Default: 1s
 	//
 	// Valid Values:
-	//  - A string corresponding to a MongoDB database
-	MongoStoreDatabase string = "MongoStoreDatabase"
+	//  - A positive go time.Duration
+	KafkaGatewayPublishTimeout string = "KafkaGatewayPublishTimeout"
 
-	// MongoStoreReplicaSet sets the MongoDB replica set to use for message storage.
-	// This is optional.
+	// KafkaGatewayDeadLetterTopic sets the topic messages from KafkaGatewaySubscribeTopic that could not be sent on the
+	// session are published to, with the error in their error header. Takes the placeholders of KafkaLogMessageTopic.
+	// KafkaGatewayDeadLetterTopic is only relevant if also using gateway/kafka.NewGateway(..) in code.
 	//
-	// See https://pkg.go.dev/go.mongodb.org/mongo-driver/mongo#Connect for more information.
+	// Required: No
 	//
-	// MongoStoreReplicaSet is only relevant if also using mongo.NewStoreFactory(..) in code
-	// when creating your MessageStoreFactory for your initiator or acceptor.
+	// Default: N/A, the messages are passed to the ErrorHandler of the gateway
+	//
+	// Valid Values:
+	//  - A topic name or pattern
+	KafkaGatewayDeadLetterTopic string = "KafkaGatewayDeadLetterTopic"
+
+	// HTTPGatewaySessionName sets the name of the session in the paths of the order entry endpoints,
+	// /sessions/{name}/orders. HTTPGatewaySessionName is only relevant if also using the Application of
+	// gateway/rest.NewGateway(..) in code for your initiator or acceptor.
 	//
 	// Required: No
 	//
-	// Default: N/A
+	// Default: The TargetCompID of the session
 	//
 	// Valid Values:
-	//  - A string corresponding to a MongoDB replica set
-	MongoStoreReplicaSet string = "MongoStoreReplicaSet"
-)
+	//  - A name unique among the sessions of the gateway, without /
+	HTTPGatewaySessionName string = "HTTPGatewaySessionName"
 
-const (
-	// Misc settings.
+	// HTTPGatewayResponseTimeout sets how long an order entry request waits for the response of the counterparty,
+	// an ExecutionReport or a reject, before answering 202 Accepted with the order still pending.
+	// HTTPGatewayResponseTimeout is only relevant if also using gateway/rest.NewGateway(..) in code.
+	//
+	// Required: No
+	//
+	// Default: 5s
+	//
+	// Valid Values:
+	//  - A valid go time.Duration
+	HTTPGatewayResponseTimeout string = "HTTPGatewayResponseTimeout"
 
-	// ResendRequestChunkSize is a setting to limit the size of a resend request in case of missing messages.
-	// This is useful when the remote FIX engine does not allow to ask for more than <n> messages for a ResendRequest.
+	// HTTPGatewayWebhookURL sets the URL every update of an order entered on the session is posted to, as JSON.
+	// HTTPGatewayWebhookURL is only relevant if also using gateway/rest.NewGateway(..) in code.
 	//
-	// I.e. if the ResendRequestChunkSize is set to 5 and a gap of 7 messages is detected, a first resend request will be sent for 5 messages.
-	// When this gap has been filled, another resend request for 2 messages will be sent.
-	// If the ResendRequestChunkSize is set to 0, only one ResendRequest for all the missing messages will be sent.
+	// Required: No
+	//
+	// Default: N/A, updates are only returned by the endpoints
+	//
+	// Valid Values:
+	//  - An http or https URL
+	HTTPGatewayWebhookURL string = "HTTPGatewayWebhookURL"
+
+	// HTTPGatewayOrderTTL sets how long an order entered on the session is kept once it is filled, cancelled, rejected,
+	// expired or done for the day. Its status is no longer answered, nor its updates correlated, after it is evicted.
+	// HTTPGatewayOrderTTL is only relevant if also using gateway/rest.NewGateway(..) in code.
 	//
 	// Required: No
 	//
-	// Default: 0 (do not chunk resends)
+	// Default: 1h
 	//
 	// Valid Values:
-	//  - A positive integer
-	ResendRequestChunkSize string = "ResendRequestChunkSize"
+	//  - A positive go time.Duration
+	HTTPGatewayOrderTTL string = "HTTPGatewayOrderTTL"
 
-	// EnableLastMsgSeqNumProcessed tells the FIX engine to add the last message sequence number processed
-	// to outgoing message headers (using optional tag 369).
+	// SyslogLogNetwork sets the network used to reach the syslog server.
+	// SyslogLogNetwork is only relevant if also using syslog.NewLogFactory(..) in code
+	// when creating your LogFactory for your initiator or acceptor.
 	//
 	// Required: No
 	//
-	// Default: N
+	// Default: udp
 	//
 	// Valid Values:
-	//  - Y
-	//  - N
-	EnableLastMsgSeqNumProcessed string = "EnableLastMsgSeqNumProcessed"
+	//  - tcp
+	//  - udp
+	//  - unix (a local stream socket)
+	//  - unixgram (a local datagram socket, e.g. /dev/log)
+	SyslogLogNetwork string = "SyslogLogNetwork"
 
-	// EnableNextExpectedMsgSeqNum tells the FIX engine to add tag NextExpectedMsgSeqNum (optional tag 789) on the
-	// sent Logon message and use value of tag 789 on received Logon message to synchronize session.
-	// This should not be enabled for FIX versions less than FIX.4.4.
+	// SyslogLogAddress sets the address of the syslog server.
+	// SyslogLogAddress is only relevant if also using syslog.NewLogFactory(..) in code.
+	//
+	// Required: Only if using syslog as your Log.
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A host:port address for tcp and udp, or a socket path for unix and unixgram
+	SyslogLogAddress string = "SyslogLogAddress"
+
+	// SyslogLogAppName sets the APP-NAME of the syslog messages.
+	// SyslogLogAppName is only relevant if also using syslog.NewLogFactory(..) in code.
 	//
 	// Required: No
 	//
-	// Default: N
+	// Default: quickfix
 	//
 	// Valid Values:
-	//  - Y
-	//  - N
-	EnableNextExpectedMsgSeqNum string = "EnableNextExpectedMsgSeqNum"
+	//  - A string of printable ASCII without spaces, at most 48 characters
+	SyslogLogAppName string = "SyslogLogAppName"
+
+	// SyslogLogFacility sets the facility of the syslog messages.
+	// SyslogLogFacility is only relevant if also using syslog.NewLogFactory(..) in code.
+	//
+	// Required: No
+	//
+	// Default: local0
+	//
+	// Valid Values:
+	//  - kern, user, mail, daemon, auth, syslog, lpr, news, uucp, cron, authpriv, ftp or local0 to local7
+	SyslogLogFacility string = "SyslogLogFacility"
+
+	// SyslogLogMessages determines if incoming and outgoing messages are sent to syslog along with events.
+	// SyslogLogMessages is only relevant if also using syslog.NewLogFactory(..) in code.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	SyslogLogMessages string = "SyslogLogMessages"
+
+	// JournaldLogIdentifier sets the SYSLOG_IDENTIFIER of the journal entries.
+	// JournaldLogIdentifier is only relevant if also using journald.NewLogFactory(..) in code
+	// when creating your LogFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: quickfix
+	//
+	// Valid Values:
+	//  - A string
+	JournaldLogIdentifier string = "JournaldLogIdentifier"
+
+	// JournaldLogMessages determines if incoming and outgoing messages are sent to the journal along with events.
+	// JournaldLogMessages is only relevant if also using journald.NewLogFactory(..) in code.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	JournaldLogMessages string = "JournaldLogMessages"
+)
+
+const (
+	// Storage settings.
+
+	// PersistMessages if set to N, no messages will be persisted.
+	// This will force QuickFIX/Go to always send GapFills instead of resending messages.
+	// Use this if you know you never want to resend a message.
+	// This is useful for market data streams when logging all incoming messages is not important.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	PersistMessages string = "PersistMessages"
+
+	// TransientMessageTypes sets the outbound message types that are sent and counted in sequence numbers but not
+	// saved to the message store, as if PersistMessages were N for them. They are gap filled if a resend is requested.
+	// Single messages can be made transient with Message.SetTransient.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A comma separated list of message types, e.g. W,S,i
+	TransientMessageTypes string = "TransientMessageTypes"
+
+	// HighResolutionLatency determines if the latency percentiles of GetSessionStats are counted in histograms of
+	// 32 buckets per power of two, for an error of about 3%, instead of one bucket per power of two.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	HighResolutionLatency string = "HighResolutionLatency"
+
+	// MessageStoreCompression sets the algorithm used to compress message bodies saved by the file and sql stores.
+	// Messages are decompressed transparently when resent, whatever algorithm they were saved with,
+	// so the setting can be changed on an existing store.
+	// The sql store base64 encodes compressed messages to keep them valid text.
+	//
+	// MessageStoreCompression is only relevant if also using file.NewStoreFactory(..) or sql.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: none
+	//
+	// Valid Values:
+	//  - none
+	//  - gzip
+	//  - zstd
+	MessageStoreCompression string = "MessageStoreCompression"
+
+	// MessageStoreCacheSize sets the number of most recently saved messages the sql store keeps in memory.
+	// Resend requests covered by the cache are served without querying the database, older ones fall back to it.
+	// The least recently used messages are evicted once the cache is full.
+	//
+	// MessageStoreCacheSize is only relevant if also using sql.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 0 (no messages are cached)
+	//
+	// Valid Values:
+	//  - An integer greater than or equal to 0
+	MessageStoreCacheSize string = "MessageStoreCacheSize"
+
+	// FileStorePath sets the directory path in which to write sequence number and message files.
+	// This will create the directory path if it does not already exist.
+	// FileStorePath is only relevant if also using file.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: Only if using files as your MessageStore
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A valid path
+	FileStorePath string = "FileStorePath"
+
+	// FileStoreSync controls whether the FileStore syncs to the hard drive on every write.
+	// It's safer to sync, but it's also much slower.
+	// FileStoreSync is only relevant if also using file.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	FileStoreSync string = "FileStoreSync"
+
+	// FileStoreMaxSegmentSize sets the size in bytes past which the FileStore starts writing messages to a new
+	// pair of body and header files. Segments let old messages be pruned, or compacted by Acceptor.Compact and
+	// Initiator.Compact, without rewriting a single large body file.
+	// FileStoreMaxSegmentSize is only relevant if also using file.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 0 (all messages are written to a single body file)
+	//
+	// Valid Values:
+	//  - An integer greater than or equal to 0, e.g. 134217728 for 128MB segments
+	FileStoreMaxSegmentSize string = "FileStoreMaxSegmentSize"
+
+	// SQLStoreDriver sets the name of the database driver to use for message storage (see https://go.dev/wiki/SQLDrivers for the list of available drivers).
+	// SQLStoreDriver is only relevant if also using sql.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Dialects for sqlite3, mysql, postgres and pgx are built in. Other drivers may need a dialect
+	// registered with sql.RegisterDialect(..) for their placeholder syntax and upsert statements.
+	//
+	// Required: Only if using a sql db as your MessageStore
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - See https://go.dev/wiki/SQLDrivers
+	SQLStoreDriver string = "SQLStoreDriver"
+
+	// SQLStoreDataSourceName sets the driver-specific data source name of the database to use for messagge storage.
+	// This usually consists of at least a database name and connection information.
+	// SQLStoreDataSourceName is only relevant if also using sql.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// See https://pkg.go.dev/database/sql#Open for more information.
+	//
+	// Required: Only if using a sql db as your MessageStore
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A string correspondinng to a datasource
+	SQLStoreDataSourceName string = "SQLStoreDataSourceName"
+
+	// SQLStoreConnMaxLifetime sets the maximum duration of time that a database connection may be reused.
+	// See https://pkg.go.dev/database/sql#DB.SetConnMaxLifetime for more information.
+	//
+	// If your database server has a config option to close inactive connections after some duration (e.g. MySQL "wait_timeout"),
+	// set SQLStoreConnMaxLifetime to a value less than that duration.
+	//
+	// Example Values:
+	//  - SQLStoreConnMaxLifetime=14400s # 14400 seconds
+	//  - SQLStoreConnMaxLifetime=2h45m  # 2 hours and 45 minutes
+	//
+	// SQLStoreConnMaxLifetime is only relevant if also using sql.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 0 (forever)
+	//
+	// Valid Values:
+	//  - A valid go time.Duration
+	SQLStoreConnMaxLifetime string = "SQLStoreConnMaxLifetime"
+
+	// SQLStoreConnMaxIdleTime sets the maximum duration of time that a database connection may be idle before it is closed.
+	// See https://pkg.go.dev/database/sql#DB.SetConnMaxIdleTime for more information.
+	//
+	// SQLStoreConnMaxIdleTime is only relevant if also using sql.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 0 (forever)
+	//
+	// Valid Values:
+	//  - A valid go time.Duration
+	SQLStoreConnMaxIdleTime string = "SQLStoreConnMaxIdleTime"
+
+	// SQLStoreMaxOpenConns sets the maximum number of open connections to the database for each session.
+	// Limit it when many sessions share one database to avoid opening more connections than the server accepts.
+	// See https://pkg.go.dev/database/sql#DB.SetMaxOpenConns for more information.
+	//
+	// SQLStoreMaxOpenConns is only relevant if also using sql.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 0 (unlimited)
+	//
+	// Valid Values:
+	//  - An integer greater than or equal to 0
+	SQLStoreMaxOpenConns string = "SQLStoreMaxOpenConns"
+
+	// SQLStoreMaxIdleConns sets the maximum number of idle connections kept open to the database for each session.
+	// See https://pkg.go.dev/database/sql#DB.SetMaxIdleConns for more information.
+	//
+	// SQLStoreMaxIdleConns is only relevant if also using sql.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 2
+	//
+	// Valid Values:
+	//  - An integer greater than or equal to 0, where 0 keeps no idle connections
+	SQLStoreMaxIdleConns string = "SQLStoreMaxIdleConns"
+
+	// SQLStoreMessagesTableName defines the table name for the messages table. Default is "messages".
+	// If you use a different table name, you must set up your database accordingly.
+	//
+	// Required: No
+	//
+	// Default: messages
+	//
+	// Valid Values:
+	//	- A valid string
+	SQLStoreMessagesTableName = "SQLStoreMessagesTableName"
+
+	// SQLStoreSessionsTableName defines the table name for the messages table. Default is "sessions".
+	// If you use a different table name, you must set up your database accordingly.
+	//
+	// Required: No
+	//
+	// Default: sessions
+	//
+	// Valid Values:
+	//	- A valid string
+	SQLStoreSessionsTableName = "SQLStoreSessionsTableName"
+
+	// SQLStoreAutoMigrate determines if the messages and sessions tables are created, or upgraded to the schema this
+	// version of QuickFIX/Go expects, when the store is created. Applied schema versions are recorded per pair of table names in a
+	// quickfix_migrations table.
+	// The DDL comes from the dialect of SQLStoreDriver, see sql.RegisterDialect(..) for drivers without a built-in dialect.
+	//
+	// SQLStoreAutoMigrate is only relevant if also using sql.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	SQLStoreAutoMigrate string = "SQLStoreAutoMigrate"
+
+	// SQLStoreWriteBehind determines if message and sequence number writes are queued and flushed to the database in batches
+	// instead of one transaction per write. Reads, Reset and Refresh flush the queue first, and Close flushes any remaining writes.
+	// Writes queued since the last flush are lost if the process exits without closing the store. A batch failing to flush is
+	// retried by the next flushes and dropped after 3 attempts; sql.Flusher reports dropped writes.
+	//
+	// SQLStoreWriteBehind is only relevant if also using sql.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	SQLStoreWriteBehind string = "SQLStoreWriteBehind"
+
+	// SQLStoreFlushInterval sets how often queued writes are flushed when SQLStoreWriteBehind is enabled.
+	//
+	// Required: No
+	//
+	// Default: 100ms
+	//
+	// Valid Values:
+	//  - A positive go time.Duration
+	SQLStoreFlushInterval string = "SQLStoreFlushInterval"
+
+	// SQLStoreFlushBatchSize sets the number of queued statements that triggers an early flush when SQLStoreWriteBehind is enabled.
+	//
+	// Required: No
+	//
+	// Default: 100
+	//
+	// Valid Values:
+	//  - A positive integer
+	SQLStoreFlushBatchSize string = "SQLStoreFlushBatchSize"
+
+	// MongoStoreConnection sets the MongoDB connection URL to use for message storage.
+	//
+	// See https://pkg.go.dev/go.mongodb.org/mongo-driver/mongo#Connect for more information.
+	//
+	// MongoStoreConnection is only relevant if also using mongo.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: Only if using MongoDB as your MessageStore
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A string representing a MongoDB connection
+	MongoStoreConnection string = "MongoStoreConnection"
+
+	// MongoStoreDatabase sets the MongoDB-specific name of the database to use for message storage.
+	//
+	// See https://pkg.go.dev/go.mongodb.org/mongo-driver/mongo#Connect for more information.
+	//
+	// MongoStoreDatabase is only relevant if also using mongo.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: Only if using MongoDB as your MessageStore
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A string corresponding to a MongoDB database
+	MongoStoreDatabase string = "MongoStoreDatabase"
+
+	// MongoStoreReplicaSet sets the MongoDB replica set to use for message storage.
+	// This is optional.
+	//
+	// See https://pkg.go.dev/go.mongodb.org/mongo-driver/mongo#Connect for more information.
+	//
+	// MongoStoreReplicaSet is only relevant if also using mongo.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A string corresponding to a MongoDB replica set
+	MongoStoreReplicaSet string = "MongoStoreReplicaSet"
+
+	// MongoStoreWriteConcern sets the write concern used for message storage writes.
+	// Overrides any write concern given in MongoStoreConnection.
+	//
+	// See https://www.mongodb.com/docs/manual/reference/write-concern/ for more information.
+	//
+	// MongoStoreWriteConcern is only relevant if also using mongo.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: The MongoDB driver default
+	//
+	// Valid Values:
+	//  - majority
+	//  - A non-negative integer number of acknowledging nodes
+	//  - A custom write concern tag name
+	MongoStoreWriteConcern string = "MongoStoreWriteConcern"
+
+	// MongoStoreReadPreference sets the read preference used when loading sequence numbers and messages.
+	// Reading from secondaries may return stale sequence numbers after a failover, so primary is recommended.
+	//
+	// See https://www.mongodb.com/docs/manual/core/read-preference/ for more information.
+	//
+	// MongoStoreReadPreference is only relevant if also using mongo.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: primary
+	//
+	// Valid Values:
+	//  - primary
+	//  - primaryPreferred
+	//  - secondary
+	//  - secondaryPreferred
+	//  - nearest
+	MongoStoreReadPreference string = "MongoStoreReadPreference"
+
+	// RedisStoreConnection sets the Redis connection URL to use for message storage.
+	// Additional Redis Cluster or Sentinel nodes may be listed with repeated addr query parameters,
+	// e.g. redis://node1:6379?addr=node2:6379&addr=node3:6379.
+	//
+	// See https://pkg.go.dev/github.com/redis/go-redis/v9#ParseURL for more information.
+	//
+	// RedisStoreConnection is only relevant if also using redis.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: Only if using a Redis store.
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A string corresponding to a Redis connection URL
+	RedisStoreConnection string = "RedisStoreConnection"
+
+	// RedisStoreKeyPrefix sets the prefix prepended to every key written by the Redis store.
+	//
+	// RedisStoreKeyPrefix is only relevant if also using redis.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: quickfix:
+	//
+	// Valid Values:
+	//  - Any string
+	RedisStoreKeyPrefix string = "RedisStoreKeyPrefix"
+
+	// RedisStoreTTL sets how long the keys of an idle session are kept by Redis.
+	// The expiry is refreshed on every write, so records only expire once a session stops storing updates.
+	//
+	// RedisStoreTTL is only relevant if also using redis.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 0 (keys do not expire)
+	//
+	// Valid Values:
+	//  - A valid go time.Duration
+	RedisStoreTTL string = "RedisStoreTTL"
+
+	// RedisStoreSentinelMasterName sets the name of the Sentinel-monitored master to connect to.
+	// When set, the hosts in RedisStoreConnection are treated as Sentinel nodes.
+	//
+	// RedisStoreSentinelMasterName is only relevant if also using redis.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A string corresponding to a Redis Sentinel master name
+	RedisStoreSentinelMasterName string = "RedisStoreSentinelMasterName"
+
+	// DynamoDBStoreRegion sets the AWS region of the DynamoDB tables used for message storage.
+	// Credentials are loaded from the default AWS credential chain.
+	//
+	// DynamoDBStoreRegion is only relevant if also using dynamodb.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: Only if using DynamoDB as your MessageStore
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - An AWS region, e.g. us-east-1
+	DynamoDBStoreRegion string = "DynamoDBStoreRegion"
+
+	// DynamoDBStoreEndpoint overrides the DynamoDB endpoint URL, e.g. to use DynamoDB Local for testing.
+	//
+	// DynamoDBStoreEndpoint is only relevant if also using dynamodb.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: The AWS endpoint for DynamoDBStoreRegion
+	//
+	// Valid Values:
+	//  - A URL, e.g. http://localhost:8000
+	DynamoDBStoreEndpoint string = "DynamoDBStoreEndpoint"
+
+	// DynamoDBStoreSessionsTableName sets the DynamoDB table holding session sequence numbers.
+	// The table must have a string partition key named session_id.
+	//
+	// DynamoDBStoreSessionsTableName is only relevant if also using dynamodb.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: quickfix_sessions
+	//
+	// Valid Values:
+	//  - A valid DynamoDB table name
+	DynamoDBStoreSessionsTableName string = "DynamoDBStoreSessionsTableName"
+
+	// DynamoDBStoreMessagesTableName sets the DynamoDB table holding saved messages.
+	// The table must have a string partition key named session_id and a number sort key named msgseq.
+	//
+	// DynamoDBStoreMessagesTableName is only relevant if also using dynamodb.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: quickfix_messages
+	//
+	// Valid Values:
+	//  - A valid DynamoDB table name
+	DynamoDBStoreMessagesTableName string = "DynamoDBStoreMessagesTableName"
+
+	// CassandraStoreHosts sets the Cassandra or ScyllaDB hosts to connect to for message storage.
+	// See _sql/cassandra for the expected table definitions.
+	//
+	// CassandraStoreHosts is only relevant if also using cassandra.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: Only if using Cassandra as your MessageStore
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - Comma delimited list of host or host:port addresses
+	CassandraStoreHosts string = "CassandraStoreHosts"
+
+	// CassandraStoreKeyspace sets the keyspace holding the message storage tables.
+	//
+	// CassandraStoreKeyspace is only relevant if also using cassandra.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: Only if using Cassandra as your MessageStore
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A valid keyspace name
+	CassandraStoreKeyspace string = "CassandraStoreKeyspace"
+
+	// CassandraStoreConsistency sets the consistency level of message storage reads and writes.
+	//
+	// CassandraStoreConsistency is only relevant if also using cassandra.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: QUORUM
+	//
+	// Valid Values:
+	//  - ANY, ONE, TWO, THREE, QUORUM, ALL, LOCAL_QUORUM, EACH_QUORUM, LOCAL_ONE
+	CassandraStoreConsistency string = "CassandraStoreConsistency"
+
+	// CassandraStoreUsername sets the username used to authenticate with Cassandra.
+	//
+	// CassandraStoreUsername is only relevant if also using cassandra.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A string corresponding to a Cassandra user
+	CassandraStoreUsername string = "CassandraStoreUsername"
+
+	// CassandraStorePassword sets the password used to authenticate CassandraStoreUsername.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A string corresponding to the password of CassandraStoreUsername
+	CassandraStorePassword string = "CassandraStorePassword"
+
+	// CassandraStoreMessageTTL sets how long saved messages are kept before Cassandra expires them.
+	// Sequence numbers do not expire.
+	//
+	// CassandraStoreMessageTTL is only relevant if also using cassandra.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 0 (forever)
+	//
+	// Valid Values:
+	//  - A valid go time.Duration of whole seconds
+	CassandraStoreMessageTTL string = "CassandraStoreMessageTTL"
+
+	// CassandraStoreMessagesTableName defines the table name for the messages table.
+	//
+	// Required: No
+	//
+	// Default: messages
+	//
+	// Valid Values:
+	//  - A valid table name
+	CassandraStoreMessagesTableName string = "CassandraStoreMessagesTableName"
+
+	// CassandraStoreSessionsTableName defines the table name for the sessions table.
+	//
+	// Required: No
+	//
+	// Default: sessions
+	//
+	// Valid Values:
+	//  - A valid table name
+	CassandraStoreSessionsTableName string = "CassandraStoreSessionsTableName"
+
+	// BoltStorePath sets the directory in which the bbolt store writes one database file per session.
+	// This will create the directory path if it does not already exist.
+	// BoltStorePath is only relevant if also using bbolt.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: Only if using bbolt as your MessageStore
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A valid path
+	BoltStorePath string = "BoltStorePath"
+
+	// BoltStoreSync controls whether the bbolt store syncs its database file to the hard drive on every write.
+	// It's safer to sync, but it's also much slower.
+	// BoltStoreSync is only relevant if also using bbolt.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	BoltStoreSync string = "BoltStoreSync"
+)
+
+const (
+	// Misc settings.
+
+	// ResendRequestChunkSize is a setting to limit the size of a resend request in case of missing messages.
+	// This is useful when the remote FIX engine does not allow to ask for more than <n> messages for a ResendRequest.
+	//
+	// I.e. if the ResendRequestChunkSize is set to 5 and a gap of 7 messages is detected, a first resend request will be sent for 5 messages.
+	// When this gap has been filled, another resend request for 2 messages will be sent.
+	// If the ResendRequestChunkSize is set to 0, only one ResendRequest for all the missing messages will be sent.
+	//
+	// Required: No
+	//
+	// Default: 0 (do not chunk resends)
+	//
+	// Valid Values:
+	//  - A positive integer
+	ResendRequestChunkSize string = "ResendRequestChunkSize"
+
+	// EnableLastMsgSeqNumProcessed tells the FIX engine to add the last message sequence number processed
+	// to outgoing message headers (using optional tag 369).
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	EnableLastMsgSeqNumProcessed string = "EnableLastMsgSeqNumProcessed"
+
+	// EnableNextExpectedMsgSeqNum tells the FIX engine to add tag NextExpectedMsgSeqNum (optional tag 789) on the
+	// sent Logon message and use value of tag 789 on received Logon message to synchronize session.
+	// Messages sent before the Logon that the counterparty has not received are resent as if requested by a ResendRequest.
+	// This should not be enabled for FIX versions less than FIX.4.4.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	EnableNextExpectedMsgSeqNum string = "EnableNextExpectedMsgSeqNum"
+
+	// SendLogoutBeforeDisconnectFromLogon tells the FIX engine to send a Logout with the reason in Text (tag 58)
+	// before disconnecting a counterparty whose Logon is rejected, e.g. for an unknown session or invalid header,
+	// instead of closing the connection silently.
+	// For acceptors, rejections of Logons for unknown sessions use the value from the [DEFAULT] section.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	SendLogoutBeforeDisconnectFromLogon string = "SendLogoutBeforeDisconnectFromLogon"
+
+	// ThrottleMessagesPerSecond limits the sustained rate of application messages sent through the session,
+	// for counterparties that enforce message rate limits. Session level messages are not throttled.
+	//
+	// Required: No
+	//
+	// Default: 0 (do not throttle)
+	//
+	// Valid Values:
+	//  - A positive integer
+	ThrottleMessagesPerSecond string = "ThrottleMessagesPerSecond"
+
+	// ThrottleBurst is the number of application messages that may be sent at once before ThrottleMessagesPerSecond applies.
+	// ThrottleBurst is only relevant if ThrottleMessagesPerSecond is set.
+	//
+	// Required: No
+	//
+	// Default: The value of ThrottleMessagesPerSecond
+	//
+	// Valid Values:
+	//  - A positive integer
+	ThrottleBurst string = "ThrottleBurst"
+
+	// ThrottleMode determines what happens to application messages sent faster than ThrottleMessagesPerSecond allows.
+	// ThrottleMode is only relevant if ThrottleMessagesPerSecond is set.
+	//
+	// Required: No
+	//
+	// Default: Block
+	//
+	// Valid Values:
+	//  - Block (the sender is blocked until the message can be sent)
+	//  - Queue (the message is queued and sent by the session when the throttle allows)
+	ThrottleMode string = "ThrottleMode"
+
+	// MaxQueuedMessages limits the number of application messages queued for sending by the session,
+	// e.g. while the counterparty is slow to read. Messages held by ThrottleMode=Queue count towards the limit.
+	//
+	// Required: No
+	//
+	// Default: 0 (unlimited)
+	//
+	// Valid Values:
+	//  - A positive integer
+	MaxQueuedMessages string = "MaxQueuedMessages"
+
+	// QueueFullPolicy determines what happens to application messages sent while MaxQueuedMessages messages are queued.
+	// QueueFullPolicy is only relevant if MaxQueuedMessages is set.
+	// With Block, messages sent from the session's Application callbacks fail with ErrSessionQueueFull rather than
+	// wait, as the session would wait on itself.
+	//
+	// Required: No
+	//
+	// Default: Block
+	//
+	// Valid Values:
+	//  - Block (the sender is blocked until the session has sent queued messages, for at most QueueFullTimeout)
+	//  - Error (the send fails with ErrSessionQueueFull)
+	//  - DropOldestQuote (the oldest queued Quote or MassQuote is dropped, otherwise the send fails with ErrSessionQueueFull)
+	QueueFullPolicy string = "QueueFullPolicy"
+
+	// QueueFullTimeout sets how long a sender blocked by QueueFullPolicy=Block waits for space in the send queue,
+	// after which the send fails with ErrSessionQueueFull.
+	//
+	// Required: No
+	//
+	// Default: 10s
+	//
+	// Valid Values:
+	//  - A positive go time.Duration
+	QueueFullTimeout string = "QueueFullTimeout"
+
+	// PersistOutboundQueue determines if application messages sent while the session is not logged on are saved in
+	// the MessageStore, and sent once the session logs on, also after a restart. Otherwise they are only assigned a
+	// MsgSeqNum, so the counterparty may request them with a ResendRequest. The MessageStore must implement
+	// OutboundQueueStore, and its queue is kept when the sequence numbers are reset.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	PersistOutboundQueue string = "PersistOutboundQueue"
+
+	// OutboundQueueMaxAge drops messages of the queue of PersistOutboundQueue that were queued longer ago than this
+	// when the session logs on, instead of sending them. If 0, messages are sent however old they are.
+	//
+	// Required: No
+	//
+	// Default: 0
+	//
+	// Valid Values:
+	//  - A duration, e.g. 30m
+	OutboundQueueMaxAge string = "OutboundQueueMaxAge"
+
+	// DuplicateWindow enables detection of duplicate inbound application messages, e.g. replayed after a resend
+	// or reconnect, remembering the keys of this many recently received messages. Messages are keyed on MsgType
+	// and ExecID, or ClOrdID if there is no ExecID, unless the Application implements DuplicateKeyExtractor.
+	// Keys are held in memory, so are not remembered across restarts.
+	//
+	// Required: No
+	//
+	// Default: 0 (disabled)
+	//
+	// Valid Values:
+	//  - A positive integer
+	DuplicateWindow string = "DuplicateWindow"
+
+	// DuplicatePolicy determines what happens to inbound application messages detected as duplicates.
+	// DuplicatePolicy is only relevant if DuplicateWindow is set.
+	//
+	// Required: No
+	//
+	// Default: Flag
+	//
+	// Valid Values:
+	//  - Flag (duplicates are passed to FromAppDuplicate if the Application implements DuplicateApplication, otherwise FromApp)
+	//  - Suppress (duplicates are logged and not passed to the Application)
+	DuplicatePolicy string = "DuplicatePolicy"
+
+	// PreserveMessageFieldsOrder keeps the fields of inbound messages in the order they were received when they are
+	// serialized again, e.g. to be forwarded, rather than in tag order. Fields not in the DataDictionary are kept,
+	// including those inside repeating groups, as are repeated tags of groups parsed without a DataDictionary.
+	// Fields set after the message is parsed are written after the received fields.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	PreserveMessageFieldsOrder string = "PreserveMessageFieldsOrder"
+
+	// ZeroAllocParser parses inbound messages into pooled Messages that are released once processed, unless they are
+	// stashed or handed to a Dispatcher, so that steady state parsing does not allocate. Messages passed to the
+	// Application are only valid until the callback returns, use Message.CopyInto to retain one, or set N.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	ZeroAllocParser string = "ZeroAllocParser"
 )
@@ -15,19 +15,135 @@
 
 package quickfix
 
-import "io"
+import (
+	"io"
+	"net"
+	"time"
+)
+
+// writeBatching coalesces messages queued for a connection into a single write, and is disabled if maxBytes is 0.
+type writeBatching struct {
+	// maxBytes is the size at which a batch is written without waiting for more messages.
+	maxBytes int
+
+	// flushLatency is the longest a batch waits for more messages after its first message. If 0, a batch is
+	// written as soon as no more messages are queued.
+	flushLatency time.Duration
+}
+
+func (s *session) writeBatching() writeBatching {
+	return writeBatching{maxBytes: s.OutboundBatchMaxBytes, flushLatency: s.OutboundBatchFlushLatency}
+}
+
+// writeLoop writes the messages of messageOut to connection, calling written, if not nil, after each.
+func writeLoop(connection io.Writer, messageOut chan []byte, log Log, batching writeBatching, written func(msg []byte, err error)) {
+	var batch [][]byte
+	var buffer []byte
+	var flushTimer *time.Timer
+	if batching.flushLatency > 0 {
+		flushTimer = time.NewTimer(batching.flushLatency)
+		flushTimer.Stop()
+		defer flushTimer.Stop()
+	}
 
-func writeLoop(connection io.Writer, messageOut chan []byte, log Log) {
 	for {
 		msg, ok := <-messageOut
 		if !ok {
 			return
 		}
 
-		if _, err := connection.Write(msg); err != nil {
+		if batching.maxBytes <= 0 {
+			_, err := connection.Write(msg)
+			if err != nil {
+				log.OnEvent(err.Error())
+			}
+			if written != nil {
+				written(msg, err)
+			}
+			continue
+		}
+
+		batch = append(batch[:0], msg)
+		size := len(msg)
+		closed := false
+		if flushTimer != nil {
+			flushTimer.Reset(batching.flushLatency)
+		}
+
+	collect:
+		for size < batching.maxBytes {
+			select {
+			case msg, ok = <-messageOut:
+				if !ok {
+					closed = true
+					break collect
+				}
+				batch = append(batch, msg)
+				size += len(msg)
+				continue
+			default:
+			}
+
+			if flushTimer == nil {
+				break
+			}
+
+			select {
+			case msg, ok = <-messageOut:
+				if !ok {
+					closed = true
+					break collect
+				}
+				batch = append(batch, msg)
+				size += len(msg)
+			case <-flushTimer.C:
+				break collect
+			}
+		}
+		if flushTimer != nil && !flushTimer.Stop() {
+			select {
+			case <-flushTimer.C:
+			default:
+			}
+		}
+
+		var err error
+		buffer, err = writeBatch(connection, batch, buffer[:0])
+		if err != nil {
 			log.OnEvent(err.Error())
 		}
+		if written != nil {
+			for _, msg := range batch {
+				written(msg, err)
+			}
+		}
+		clear(batch)
+
+		if closed {
+			return
+		}
+	}
+}
+
+// writeBatch writes the batch with a vectored write to TCP connections, and copied into buffer otherwise.
+func writeBatch(connection io.Writer, batch [][]byte, buffer []byte) ([]byte, error) {
+	if len(batch) == 1 {
+		_, err := connection.Write(batch[0])
+		return buffer, err
+	}
+
+	if _, ok := connection.(*net.TCPConn); ok {
+		buffers := make(net.Buffers, len(batch))
+		copy(buffers, batch)
+		_, err := buffers.WriteTo(connection)
+		return buffer, err
+	}
+
+	for _, msg := range batch {
+		buffer = append(buffer, msg...)
 	}
+	_, err := connection.Write(buffer)
+	return buffer, err
 }
 
 func readLoop(parser *parser, msgIn chan fixIn, log Log) {
@@ -42,3 +158,17 @@ func readLoop(parser *parser, msgIn chan fixIn, log Log) {
 		msgIn <- fixIn{msg, parser.lastRead}
 	}
 }
+
+// readLoop delivers first, then the messages read by parser, to the session for the connection of msgIn, through msgIn
+// in dedicated mode or the event loop of the session in shared mode.
+func (s *session) readLoop(parser *parser, msgIn chan fixIn, first ...fixIn) {
+	if s.loop != nil {
+		s.loop.read(s, parser, msgIn, first...)
+		return
+	}
+
+	for _, in := range first {
+		msgIn <- in
+	}
+	readLoop(parser, msgIn, s.log)
+}
@@ -17,8 +17,14 @@ package quickfix
 
 import (
 	"bytes"
+	"io"
+	"net"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWriteLoop(t *testing.T) {
@@ -31,7 +37,7 @@ func TestWriteLoop(t *testing.T) {
 		msgOut <- []byte("test msg 3")
 		close(msgOut)
 	}()
-	writeLoop(writer, msgOut, nullLog{})
+	writeLoop(writer, msgOut, nullLog{}, writeBatching{}, nil)
 
 	expected := "test msg 1 test msg 2 test msg 3"
 
@@ -40,6 +46,81 @@ func TestWriteLoop(t *testing.T) {
 	}
 }
 
+// recordingWriter records the bytes of each call to Write.
+type recordingWriter struct {
+	writes []string
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestWriteLoopBatching(t *testing.T) {
+	msgOut := make(chan []byte, 4)
+	msgOut <- []byte("msg 1 ")
+	msgOut <- []byte("msg 2 ")
+	msgOut <- []byte("msg 3 ")
+	msgOut <- []byte("msg 4")
+	close(msgOut)
+
+	writer := &recordingWriter{}
+	var written []string
+	writeLoop(writer, msgOut, nullLog{}, writeBatching{maxBytes: 12}, func(msg []byte, err error) {
+		assert.Nil(t, err)
+		written = append(written, string(msg))
+	})
+
+	assert.Equal(t, []string{"msg 1 msg 2 ", "msg 3 msg 4"}, writer.writes)
+	assert.Equal(t, []string{"msg 1 ", "msg 2 ", "msg 3 ", "msg 4"}, written)
+}
+
+func TestWriteLoopBatchingFlushLatency(t *testing.T) {
+	msgOut := make(chan []byte)
+	go func() {
+		msgOut <- []byte("msg 1 ")
+		time.Sleep(10 * time.Millisecond)
+		msgOut <- []byte("msg 2")
+		close(msgOut)
+	}()
+
+	writer := &recordingWriter{}
+	writeLoop(writer, msgOut, nullLog{}, writeBatching{maxBytes: 1024, flushLatency: time.Second}, nil)
+
+	assert.Equal(t, []string{"msg 1 msg 2"}, writer.writes)
+}
+
+func TestWriteLoopBatchingTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+
+	received := make(chan string)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer conn.Close()
+		b, _ := io.ReadAll(conn)
+		received <- string(b)
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.Nil(t, err)
+
+	msgOut := make(chan []byte, 3)
+	msgOut <- []byte("msg 1 ")
+	msgOut <- []byte("msg 2 ")
+	msgOut <- []byte("msg 3")
+	close(msgOut)
+	writeLoop(conn, msgOut, nullLog{}, writeBatching{maxBytes: 1024}, nil)
+	conn.Close()
+
+	assert.Equal(t, "msg 1 msg 2 msg 3", <-received)
+}
+
 func TestReadLoop(t *testing.T) {
 	msgIn := make(chan fixIn)
 	stream := "hello8=FIX.4.09=5blah10=103garbage8=FIX.4.09=4foo10=103"
@@ -0,0 +1,151 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// ReloadDictionary replaces a data dictionary of the session matching the session id with the data dictionary read
+// from r, without restarting the session. A FIXT data dictionary replaces the TransportDataDictionary of a FIXT
+// session, and an application data dictionary replaces the DataDictionary or the AppDataDictionary of its version.
+//
+// To keep messages in flight valid, the new data dictionary must be of the same version as the one it replaces and
+// define all of its messages and header fields. The session must have been configured with a data dictionary.
+func ReloadDictionary(sessionID SessionID, r io.Reader) error {
+	dict, err := datadictionary.ParseSrc(r)
+	if err != nil {
+		return err
+	}
+
+	return reloadDataDictionary(sessionID, dict)
+}
+
+// ReloadDictionaryFile replaces a data dictionary of the session matching the session id with the data dictionary at
+// path. See ReloadDictionary.
+func ReloadDictionaryFile(sessionID SessionID, path string) error {
+	dict, err := datadictionary.Parse(path)
+	if err != nil {
+		return err
+	}
+
+	return reloadDataDictionary(sessionID, dict)
+}
+
+func reloadDataDictionary(sessionID SessionID, dict *datadictionary.DataDictionary) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return errUnknownSession
+	}
+
+	rep := make(chan error, 1)
+	return session.doAdmin(reloadDataDictionaryReq{dict: dict, rep: rep}, rep)
+}
+
+type reloadDataDictionaryReq struct {
+	dict *datadictionary.DataDictionary
+	rep  chan<- error
+}
+
+func (s *session) onReloadDataDictionary(dict *datadictionary.DataDictionary) error {
+	if s.Validator == nil {
+		return errors.New("session has no data dictionary")
+	}
+
+	version := dict.Version()
+	switch {
+	case dict.FIXType == "FIXT":
+		if !s.sessionID.IsFIXT() {
+			return fmt.Errorf("%v data dictionary for a %v session", version, s.sessionID.BeginString)
+		}
+		if err := checkDataDictionaryCompatible(s.transportDataDictionary, dict); err != nil {
+			return err
+		}
+		s.transportDataDictionary = dict
+
+	case s.sessionID.IsFIXT():
+		applVerID := normalizeApplVerID(version)
+		old, ok := s.appDataDictionaries[applVerID]
+		if !ok {
+			if applVerID != s.DefaultApplVerID {
+				return fmt.Errorf("session has no data dictionary for %v", version)
+			}
+			old = s.appDataDictionary
+		}
+		if err := checkDataDictionaryCompatible(old, dict); err != nil {
+			return err
+		}
+
+		if applVerID == s.DefaultApplVerID {
+			s.appDataDictionary = dict
+		}
+		if s.appDataDictionaries != nil {
+			s.appDataDictionaries[applVerID] = dict
+		}
+
+	default:
+		if version != s.sessionID.BeginString {
+			return fmt.Errorf("%v data dictionary for a %v session", version, s.sessionID.BeginString)
+		}
+		if err := checkDataDictionaryCompatible(s.appDataDictionary, dict); err != nil {
+			return err
+		}
+		s.appDataDictionary = dict
+	}
+
+	s.Validator = NewValidator(s.validatorSettings, s.appDataDictionary, s.transportDataDictionary)
+	for applVerID, appDataDictionary := range s.appDataDictionaries {
+		s.appValidators[applVerID] = NewValidator(s.validatorSettings, appDataDictionary, s.transportDataDictionary)
+	}
+
+	s.log.OnEventf("Reloaded %v data dictionary", version)
+	return nil
+}
+
+// checkDataDictionaryCompatible returns an error unless dict may replace old while messages are in flight: it must be
+// of the same version and define all messages and header fields of old.
+func checkDataDictionaryCompatible(old, dict *datadictionary.DataDictionary) error {
+	if old == nil {
+		return errors.New("session has no data dictionary to replace")
+	}
+
+	if old.Version() != dict.Version() {
+		return fmt.Errorf("%v data dictionary cannot replace %v data dictionary", dict.Version(), old.Version())
+	}
+
+	for msgType, def := range old.Messages {
+		if _, ok := dict.Messages[msgType]; !ok {
+			return fmt.Errorf("data dictionary does not define message %v (%v)", def.Name, msgType)
+		}
+	}
+
+	if old.Header != nil {
+		if dict.Header == nil {
+			return errors.New("data dictionary does not define the header")
+		}
+		for tag := range old.Header.Tags {
+			if _, ok := dict.Header.Tags[tag]; !ok {
+				return fmt.Errorf("data dictionary does not define header field %v", tag)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func newReloadTestSession(t *testing.T, sessionID SessionID, settings map[string]string) *session {
+	sessionSettings := NewSessionSettings()
+	for setting, value := range settings {
+		sessionSettings.Set(setting, value)
+	}
+
+	s, err := sessionFactory{}.newSession(sessionID, NewMemoryStoreFactory(), sessionSettings, nullLogFactory{}, new(MockApp))
+	require.Nil(t, err)
+	require.Nil(t, registerSession(s))
+	t.Cleanup(func() { _ = UnregisterSession(sessionID) })
+
+	return s
+}
+
+func readSpec(t *testing.T, name string) string {
+	b, err := os.ReadFile("spec/" + name)
+	require.Nil(t, err)
+
+	return string(b)
+}
+
+func TestReloadDictionary(t *testing.T) {
+	sessionID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ISLD", TargetCompID: "TW"}
+	s := newReloadTestSession(t, sessionID, map[string]string{config.DataDictionary: "spec/FIX42.xml"})
+	old, oldValidator := s.appDataDictionary, s.Validator
+
+	// An added message is valid after the reload.
+	spec := strings.Replace(readSpec(t, "FIX42.xml"), "<messages>",
+		"<messages>\n  <message name='Custom' msgcat='app' msgtype='U1'>\n   <field name='Text' required='Y' />\n  </message>", 1)
+	require.Nil(t, ReloadDictionary(sessionID, strings.NewReader(spec)))
+
+	assert.NotSame(t, old, s.appDataDictionary)
+	assert.NotEqual(t, oldValidator, s.Validator)
+	assert.Contains(t, s.appDataDictionary.Messages, "U1")
+
+	// The added message may not be removed again.
+	assert.NotNil(t, ReloadDictionaryFile(sessionID, "spec/FIX42.xml"))
+	assert.Contains(t, s.appDataDictionary.Messages, "U1")
+}
+
+func TestReloadDictionaryIncompatible(t *testing.T) {
+	sessionID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ISLD", TargetCompID: "TW"}
+	s := newReloadTestSession(t, sessionID, map[string]string{config.DataDictionary: "spec/FIX42.xml"})
+	old := s.appDataDictionary
+
+	withoutHeartbeat := regexp.MustCompile(`(?s)<message name='Heartbeat'.*?</message>`).ReplaceAllString(readSpec(t, "FIX42.xml"), "")
+
+	var tests = []struct {
+		name string
+		spec string
+	}{
+		{"other version", readSpec(t, "FIX44.xml")},
+		{"transport", readSpec(t, "FIXT11.xml")},
+		{"message removed", withoutHeartbeat},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.NotNil(t, ReloadDictionary(sessionID, strings.NewReader(test.spec)))
+			assert.Same(t, old, s.appDataDictionary)
+		})
+	}
+
+	assert.NotNil(t, ReloadDictionary(sessionID, strings.NewReader("<fix")))
+}
+
+func TestReloadDictionaryFIXT(t *testing.T) {
+	sessionID := SessionID{BeginString: BeginStringFIXT11, SenderCompID: "ISLD", TargetCompID: "TW"}
+	s := newReloadTestSession(t, sessionID, map[string]string{
+		config.DefaultApplVerID:               "FIX.5.0SP2",
+		config.TransportDataDictionary:        "spec/FIXT11.xml",
+		config.AppDataDictionary:              "spec/FIX50SP2.xml",
+		config.AppDataDictionary + ".FIX.4.4": "spec/FIX44.xml",
+	})
+	transport, app, fix44 := s.transportDataDictionary, s.appDataDictionary, s.appDataDictionaries["6"]
+
+	require.Nil(t, ReloadDictionaryFile(sessionID, "spec/FIXT11.xml"))
+	assert.NotSame(t, transport, s.transportDataDictionary)
+	assert.Same(t, app, s.appDataDictionary)
+
+	require.Nil(t, ReloadDictionaryFile(sessionID, "spec/FIX44.xml"))
+	assert.NotSame(t, fix44, s.appDataDictionaries["6"])
+	assert.Same(t, app, s.appDataDictionary)
+
+	require.Nil(t, ReloadDictionaryFile(sessionID, "spec/FIX50SP2.xml"))
+	assert.NotSame(t, app, s.appDataDictionary)
+	assert.Same(t, s.appDataDictionary, s.appDataDictionaries["9"])
+	assert.Equal(t, s.Validator, s.appValidators["9"])
+
+	assert.NotNil(t, ReloadDictionaryFile(sessionID, "spec/FIX42.xml"))
+}
+
+func TestReloadDictionaryErrors(t *testing.T) {
+	sessionID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ISLD", TargetCompID: "TW"}
+	assert.Equal(t, errUnknownSession, ReloadDictionaryFile(sessionID, "spec/FIX42.xml"))
+
+	newReloadTestSession(t, sessionID, nil)
+	assert.NotNil(t, ReloadDictionaryFile(sessionID, "spec/FIX42.xml"))
+	assert.NotNil(t, ReloadDictionaryFile(sessionID, "spec/missing.xml"))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "bytes"
+
+// dataFieldLengths are the Length fields of the standard Length/Data field pairs, keyed by the tag of the Data field.
+var dataFieldLengths = map[Tag]Tag{
+	tagSignature:  tagSignatureLength,
+	tagSecureData: tagSecureDataLen,
+	tagRawData:    tagRawDataLength,
+	tagXMLData:    tagXMLDataLen,
+	349:           348,  // EncodedIssuer.
+	351:           350,  // EncodedSecurityDesc.
+	353:           352,  // EncodedListExecInst.
+	355:           354,  // EncodedText.
+	357:           356,  // EncodedSubject.
+	359:           358,  // EncodedHeadline.
+	361:           360,  // EncodedAllocText.
+	363:           362,  // EncodedUnderlyingIssuer.
+	365:           364,  // EncodedUnderlyingSecurityDesc.
+	446:           445,  // EncodedListStatusText.
+	619:           618,  // EncodedLegIssuer.
+	622:           621,  // EncodedLegSecurityDesc.
+	1185:          1184, // SecurityXML.
+	1278:          1277, // DerivativeEncodedIssuer.
+	1281:          1280, // DerivativeEncodedSecurityDesc.
+	1283:          1282, // DerivativeSecurityXML.
+	1360:          1359, // EncodedSymbol.
+	1398:          1397, // EncodedMktSegmDesc.
+	1402:          1401, // EncryptedPassword.
+	1404:          1403, // EncryptedNewPassword.
+	1469:          1468, // EncodedSecurityListDesc.
+	1619:          1618, // RelationshipRiskEncodedSecurityDesc.
+	1621:          1620, // RiskEncodedSecurityDesc.
+}
+
+// dataFields are the Data fields of the standard Length/Data field pairs, keyed by the tag of the Length field.
+var dataFields = make(map[Tag]Tag, len(dataFieldLengths))
+
+func init() {
+	for dataTag, lengthTag := range dataFieldLengths {
+		dataFields[lengthTag] = dataTag
+	}
+}
+
+// extractNextField extracts the next field of the message into parsedFieldBytes. The value of a Data field following
+// its Length field is extracted by that length, as it may contain SOH.
+func (mp *msgParser) extractNextField() (err error) {
+	if mp.dataTag != 0 {
+		mp.rawBytes, err = extractDataField(mp.parsedFieldBytes, mp.rawBytes, mp.dataTag, mp.dataLength)
+	} else {
+		mp.rawBytes, err = extractField(mp.parsedFieldBytes, mp.rawBytes)
+	}
+
+	mp.dataTag = 0
+	if err != nil {
+		return
+	}
+
+	if dataTag, ok := dataFields[mp.parsedFieldBytes.tag]; ok {
+		if length, convErr := atoi(mp.parsedFieldBytes.value); convErr == nil && length >= 0 {
+			mp.dataTag, mp.dataLength = dataTag, length
+		}
+	}
+
+	return
+}
+
+// extractDataField extracts the field at the start of buffer, taking dataLength bytes as its value if it is the Data
+// field dataTag, and up to the next SOH otherwise.
+func extractDataField(parsedFieldBytes *TagValue, buffer []byte, dataTag Tag, dataLength int) (remBytes []byte, err error) {
+	sepIndex := bytes.IndexByte(buffer, '=')
+	if sepIndex == -1 {
+		return extractField(parsedFieldBytes, buffer)
+	}
+
+	endIndex := sepIndex + 1 + dataLength
+	if tag, atoiErr := atoi(buffer[:sepIndex]); atoiErr != nil || Tag(tag) != dataTag ||
+		endIndex >= len(buffer) || buffer[endIndex] != '\001' {
+		return extractField(parsedFieldBytes, buffer)
+	}
+
+	err = parsedFieldBytes.parse(buffer[:endIndex+1])
+	return buffer[(endIndex + 1):], err
+}
+
+// SetData sets the Data field dataTag to value, and its Length field lengthTag to the length of value.
+func (m *FieldMap) SetData(lengthTag, dataTag Tag, value []byte) *FieldMap {
+	return m.SetInt(lengthTag, len(value)).SetBytes(dataTag, value)
+}
+
+// GetData is a zero-copy GetField wrapper for the Data field dataTag, whose length must be the value of its Length
+// field lengthTag.
+func (m FieldMap) GetData(lengthTag, dataTag Tag) ([]byte, MessageRejectError) {
+	length, err := m.GetInt(lengthTag)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := m.GetBytes(dataTag)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) != length {
+		return nil, IncorrectDataFormatForValue(dataTag)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dataFieldTestMsg returns a FIX.4.4 News message with the fields header, body and trailer.
+func dataFieldTestMsg(header, body, trailer string) string {
+	fields := "35=B\x0149=A\x0156=B\x0134=1\x0152=20240101-00:00:00\x01" + header + "148=Headline\x01" + body + trailer
+
+	return fmt.Sprintf("8=FIX.4.4\x019=%v\x01%v10=000\x01", len(fields), fields)
+}
+
+func TestParseDataFieldsWithSOH(t *testing.T) {
+	xmlData := "<a>\x0110=123\x01</a>"
+	encodedText := "\x01\x0158=text\x01"
+	signature := "sig\x01nature"
+	raw := dataFieldTestMsg(
+		fmt.Sprintf("212=%v\x01213=%v\x01", len(xmlData), xmlData),
+		fmt.Sprintf("354=%v\x01355=%v\x01", len(encodedText), encodedText),
+		fmt.Sprintf("93=%v\x0189=%v\x01", len(signature), signature),
+	)
+
+	msg := NewMessage()
+	require.Nil(t, ParseMessage(msg, bytes.NewBufferString(raw)))
+
+	value, err := msg.Header.GetData(tagXMLDataLen, tagXMLData)
+	require.Nil(t, err)
+	assert.Equal(t, xmlData, string(value))
+
+	value, err = msg.Body.GetData(Tag(354), Tag(355))
+	require.Nil(t, err)
+	assert.Equal(t, encodedText, string(value))
+	assert.False(t, msg.Body.Has(Tag(58)))
+
+	value, err = msg.Trailer.GetData(tagSignatureLength, tagSignature)
+	require.Nil(t, err)
+	assert.Equal(t, signature, string(value))
+
+	checkSum, err := msg.Trailer.GetString(tagCheckSum)
+	require.Nil(t, err)
+	assert.Equal(t, "000", checkSum)
+}
+
+func TestParseDataFieldWithoutLength(t *testing.T) {
+	var tests = []struct {
+		body     string
+		expected string
+	}{
+		{"354=3\x01355=abc\x01", "abc"},
+		{"354=5\x01355=abc\x01", "abc"},
+		{"354=x\x01355=abc\x01", "abc"},
+		{"354=3\x0158=abc\x01355=abc\x01", "abc"},
+		{"355=abc\x01", "abc"},
+	}
+
+	for _, test := range tests {
+		msg := NewMessage()
+		require.Nil(t, ParseMessage(msg, bytes.NewBufferString(dataFieldTestMsg("", test.body, ""))), test.body)
+
+		value, err := msg.Body.GetString(Tag(355))
+		require.Nil(t, err, test.body)
+		assert.Equal(t, test.expected, value, test.body)
+	}
+}
+
+func TestSetData(t *testing.T) {
+	xmlData := []byte("<a>\x01</a>")
+	msg := NewMessage()
+	msg.Header.SetField(tagBeginString, FIXString(BeginStringFIX44)).
+		SetField(tagMsgType, FIXString("B")).
+		SetData(tagXMLDataLen, tagXMLData, xmlData)
+	msg.Body.SetField(Tag(148), FIXString("Headline"))
+
+	length, err := msg.Header.GetInt(tagXMLDataLen)
+	require.Nil(t, err)
+	assert.Equal(t, len(xmlData), length)
+
+	parsed := NewMessage()
+	require.Nil(t, ParseMessage(parsed, bytes.NewBuffer(msg.build())))
+
+	value, err := parsed.Header.GetData(tagXMLDataLen, tagXMLData)
+	require.Nil(t, err)
+	assert.Equal(t, xmlData, value)
+}
+
+func TestGetDataIncorrectLength(t *testing.T) {
+	var fieldMap FieldMap
+	fieldMap.init()
+
+	_, err := fieldMap.GetData(Tag(354), Tag(355))
+	require.NotNil(t, err)
+	assert.Equal(t, rejectReasonConditionallyRequiredFieldMissing, err.RejectReason())
+
+	fieldMap.SetInt(Tag(354), 5).SetString(Tag(355), "abc")
+	_, err = fieldMap.GetData(Tag(354), Tag(355))
+	require.NotNil(t, err)
+	assert.Equal(t, rejectReasonIncorrectDataFormatForValue, err.RejectReason())
+}
@@ -4,6 +4,7 @@ package datadictionary
 import (
 	"encoding/xml"
 	"io"
+	"io/fs"
 	"os"
 
 	"github.com/pkg/errors"
@@ -311,8 +312,30 @@ func Parse(path string) (*DataDictionary, error) {
 	return ParseSrc(xmlFile)
 }
 
+// ParseFS loads and build a datadictionary instance from the xml file name in fsys, such as an embed.FS.
+func ParseFS(fsys fs.FS, name string) (*DataDictionary, error) {
+	xmlFile, err := fsys.Open(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem opening file: %v", name)
+	}
+	defer xmlFile.Close()
+
+	return ParseSrc(xmlFile)
+}
+
 // ParseSrc loads and build a datadictionary instance from an xml source.
 func ParseSrc(xmlSrc io.Reader) (*DataDictionary, error) {
+	doc, err := ParseXMLDoc(xmlSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	return Build(doc)
+}
+
+// ParseXMLDoc unmarshals the xml document of a dictionary from an xml source, without building it, so that it may
+// be merged with others.
+func ParseXMLDoc(xmlSrc io.Reader) (*XMLDoc, error) {
 	doc := new(XMLDoc)
 	decoder := xml.NewDecoder(xmlSrc)
 	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
@@ -323,11 +346,11 @@ func ParseSrc(xmlSrc io.Reader) (*DataDictionary, error) {
 		return nil, errors.Wrapf(err, "problem parsing XML file")
 	}
 
-	b := new(builder)
-	dict, err := b.build(doc)
-	if err != nil {
-		return nil, err
-	}
+	return doc, nil
+}
 
-	return dict, nil
+// Build builds a datadictionary instance from the xml document of a dictionary.
+func Build(doc *XMLDoc) (*DataDictionary, error) {
+	b := new(builder)
+	return b.build(doc)
 }
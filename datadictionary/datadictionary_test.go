@@ -1,6 +1,7 @@
 package datadictionary
 
 import (
+	"os"
 	"testing"
 )
 
@@ -22,6 +23,21 @@ func TestParseRecursiveComponents(t *testing.T) {
 	}
 }
 
+func TestParseFS(t *testing.T) {
+	dict, err := ParseFS(os.DirFS("../spec"), "FIX44.xml")
+	if err != nil {
+		t.Fatalf("Unexpected err: %v", err)
+	}
+
+	if dict.Major != 4 || dict.Minor != 4 {
+		t.Errorf("Expected FIX.4.4, got FIX.%v.%v", dict.Major, dict.Minor)
+	}
+
+	if _, err := ParseFS(os.DirFS("../spec"), "bogus.xml"); err == nil {
+		t.Error("Expected err")
+	}
+}
+
 var cachedDataDictionary *DataDictionary
 
 func dict() (*DataDictionary, error) {
@@ -0,0 +1,91 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package gen
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// Sort fieldtypes by name.
+type byFieldName []*datadictionary.FieldType
+
+func (n byFieldName) Len() int           { return len(n) }
+func (n byFieldName) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n byFieldName) Less(i, j int) bool { return n[i].Name() < n[j].Name() }
+
+func (g *generator) getGlobalFieldType(f *datadictionary.FieldDef) (t *datadictionary.FieldType, err error) {
+	var ok bool
+	t, ok = g.fieldTypesLookup[f.Name()]
+	if !ok {
+		err = fmt.Errorf("Unknown global type for %v", f.Name())
+	}
+
+	return
+}
+
+// buildFieldTypes collects the field types of all specs by name, with the enums of a field in all specs. The field
+// types of the specs are copied rather than merged into.
+func (g *generator) buildFieldTypes(specs []*datadictionary.DataDictionary) {
+	g.fieldTypesLookup = make(map[string]*datadictionary.FieldType)
+	for _, spec := range specs {
+		for _, specField := range spec.FieldTypeByTag {
+			field := new(datadictionary.FieldType)
+			*field = *specField
+			if specField.Enums != nil {
+				field.Enums = make(map[string]datadictionary.Enum, len(specField.Enums))
+				for enumVal, enum := range specField.Enums {
+					field.Enums[enumVal] = enum
+				}
+			}
+
+			if oldField, ok := g.fieldTypesLookup[field.Name()]; ok {
+				// Merge old enums with new.
+				if len(oldField.Enums) > 0 && field.Enums == nil {
+					field.Enums = make(map[string]datadictionary.Enum)
+				}
+
+				for enumVal, enum := range oldField.Enums {
+					if _, ok := field.Enums[enumVal]; !ok {
+						// Verify an existing enum doesn't have the same description. Keep newer enum.
+						okToKeepEnum := true
+						for _, newEnum := range field.Enums {
+							if newEnum.Description == enum.Description {
+								okToKeepEnum = false
+								break
+							}
+						}
+
+						if okToKeepEnum {
+							field.Enums[enumVal] = enum
+						}
+					}
+				}
+			}
+
+			g.fieldTypesLookup[field.Name()] = field
+		}
+	}
+
+	g.fieldTypes = make([]*datadictionary.FieldType, 0, len(g.fieldTypesLookup))
+	for _, fieldType := range g.fieldTypesLookup {
+		g.fieldTypes = append(g.fieldTypes, fieldType)
+	}
+
+	sort.Sort(byFieldName(g.fieldTypes))
+}
@@ -0,0 +1,234 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package gen generates the typed message, field, tag and enum packages of data dictionaries, as the generate-fix
+// command does, for build pipelines generating packages of custom dictionaries.
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+const (
+	tabWidth    = 8
+	printerMode = printer.UseSpaces | printer.TabIndent
+)
+
+// DefaultPackageRoot is the import path of the packages generated from the dictionaries in spec/.
+const DefaultPackageRoot = "github.com/quickfixgo"
+
+// FloatType is the Go type of generated float fields, the PRICE, QTY, AMT and other FIX float types.
+type FloatType int
+
+const (
+	// Decimal generates float fields as arbitrary-precision fixed-point decimals of the shopspring/decimal library.
+	Decimal FloatType = iota
+
+	// UDecimal generates float fields as fixed-point decimals of the quagmt/udecimal library.
+	UDecimal
+
+	// Float64 generates float fields as float64 values.
+	Float64
+)
+
+// GetterStyle is the style of the getters generated for the fields of messages, components and groups.
+type GetterStyle int
+
+const (
+	// ValueGetters generate getters returning the value of a field, e.g. GetPrice() (decimal.Decimal, error).
+	ValueGetters GetterStyle = iota
+
+	// FieldGetters generate getters returning the field, e.g. GetPrice() (field.PriceField, error).
+	FieldGetters
+)
+
+// Options configure the generated packages. The zero value generates the packages of the dictionaries in spec/.
+type Options struct {
+	// PackageRoot is the import path of the directory of the generated packages. Defaults to DefaultPackageRoot.
+	PackageRoot string
+
+	// PackageName returns the name and directory of the package of the messages of a data dictionary. Defaults to
+	// the lower case type and version of the dictionary, e.g. fix44 or fix50sp2.
+	PackageName func(dict *datadictionary.DataDictionary) string
+
+	// Float is the Go type of float fields.
+	Float FloatType
+
+	// NoEnums generates fields with enumerated values as fields of their FIX type, instead of fields of the enum
+	// types of the enum package, and no enum package.
+	NoEnums bool
+
+	// Getters is the style of the getters of fields.
+	Getters GetterStyle
+
+	// Pointers makes the getters of fields return a pointer that is nil if the field is not present, instead of the
+	// zero value and a ConditionallyRequiredFieldMissing error.
+	Pointers bool
+}
+
+// File is a generated Go source file.
+type File struct {
+	// Path is the slash separated path of the file, relative to the directory of the PackageRoot.
+	Path    string
+	Content []byte
+}
+
+// DefaultPackageName returns the lower case type and version of dict, e.g. fix44 or fix50sp2.
+func DefaultPackageName(dict *datadictionary.DataDictionary) string {
+	pkg := strings.ToLower(dict.FIXType) + strconv.Itoa(dict.Major) + strconv.Itoa(dict.Minor)
+
+	if dict.ServicePack != 0 {
+		pkg += "sp" + strconv.Itoa(dict.ServicePack)
+	}
+
+	return pkg
+}
+
+// Generate returns the Go source files of the packages of dicts: a package of the messages of each data dictionary
+// with a subpackage per message, and the tag, field and enum packages shared by all of them. FIX.5.0 dictionaries use
+// the header and trailer of the package of the FIXT.1.1 dictionary, which is generated if dicts includes it.
+func Generate(dicts []*datadictionary.DataDictionary, opts Options) ([]File, error) {
+	if opts.PackageRoot == "" {
+		opts.PackageRoot = DefaultPackageRoot
+	}
+	if opts.PackageName == nil {
+		opts.PackageName = DefaultPackageName
+	}
+
+	g := &generator{opts: opts}
+	g.buildFieldTypes(dicts)
+	g.templates = newTemplates(g.funcs())
+
+	if err := g.gen(g.templates.tag, "tag/tag_numbers.generated.go", g.fieldTypes); err != nil {
+		return nil, err
+	}
+	if err := g.gen(g.templates.field, "field/fields.generated.go", g.fieldTypes); err != nil {
+		return nil, err
+	}
+	if !opts.NoEnums {
+		if err := g.gen(g.templates.enum, "enum/enums.generated.go", g.fieldTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, dict := range dicts {
+		if err := g.genPackage(dict); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.files, nil
+}
+
+type generator struct {
+	opts      Options
+	templates templates
+	files     []File
+
+	fieldTypesLookup map[string]*datadictionary.FieldType
+	fieldTypes       []*datadictionary.FieldType
+}
+
+type component struct {
+	Package          string
+	FIXPackage       string
+	TransportPackage string
+	FIXSpec          *datadictionary.DataDictionary
+	Name             string
+	*datadictionary.MessageDef
+}
+
+func (g *generator) genPackage(dict *datadictionary.DataDictionary) error {
+	pkg := g.opts.PackageName(dict)
+
+	// FIX.5.0 messages use the FIXT.1.1 header and trailer.
+	if dict.FIXType == "FIXT" || dict.Major < 5 {
+		header := component{Package: pkg, Name: "Header", MessageDef: dict.Header, FIXSpec: dict}
+		if err := g.gen(g.templates.header, path.Join(pkg, "header.generated.go"), header); err != nil {
+			return err
+		}
+
+		trailer := component{Package: pkg, Name: "Trailer", MessageDef: dict.Trailer}
+		if err := g.gen(g.templates.trailer, path.Join(pkg, "trailer.generated.go"), trailer); err != nil {
+			return err
+		}
+	}
+
+	transportPkg := pkg
+	if dict.Major >= 5 {
+		transportPkg = g.opts.PackageName(&datadictionary.DataDictionary{FIXType: "FIXT", Major: 1, Minor: 1})
+	}
+
+	for _, msg := range dict.Messages {
+		c := component{
+			Package:          strings.ToLower(msg.Name),
+			FIXPackage:       pkg,
+			TransportPackage: transportPkg,
+			FIXSpec:          dict,
+			Name:             msg.Name,
+			MessageDef:       msg,
+		}
+
+		if err := g.gen(g.templates.message, path.Join(pkg, c.Package, msg.Name+".generated.go"), c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) gen(t *template.Template, filePath string, data interface{}) error {
+	var source bytes.Buffer
+	if err := t.Execute(&source, data); err != nil {
+		return fmt.Errorf("error generating %v: %w", filePath, err)
+	}
+
+	content, err := format(source.Bytes())
+	if err != nil {
+		return fmt.Errorf("error parsing %v: %w", filePath, err)
+	}
+
+	g.files = append(g.files, File{Path: filePath, Content: content})
+	return nil
+}
+
+// format sorts the imports of the generated source and gofmts it.
+func format(source []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	ast.SortImports(fset, f)
+
+	var b bytes.Buffer
+	if err := (&printer.Config{Mode: printerMode, Tabwidth: tabWidth}).Fprint(&b, fset, f); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,264 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package gen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+const testPackageRoot = "example.com/fix"
+
+const testDictionary = `
+<fix type='FIX' major='4' minor='2'>
+ <header>
+  <field name='BeginString' required='Y' />
+  <field name='BodyLength' required='Y' />
+  <field name='MsgType' required='Y' />
+ </header>
+ <trailer>
+  <field name='CheckSum' required='Y' />
+ </trailer>
+ <messages>
+  <message name='NewOrderSingle' msgcat='app' msgtype='D'>
+   <field name='ClOrdID' required='Y' />
+   <field name='Side' required='Y' />
+   <field name='Price' required='N' />
+   <field name='TransactTime' required='N' />
+   <group name='NoAllocs' required='N'>
+    <field name='AllocAccount' required='N' />
+    <field name='AllocShares' required='N' />
+   </group>
+  </message>
+ </messages>
+ <fields>
+  <field number='8' name='BeginString' type='STRING' />
+  <field number='9' name='BodyLength' type='INT' />
+  <field number='10' name='CheckSum' type='STRING' />
+  <field number='11' name='ClOrdID' type='STRING' />
+  <field number='35' name='MsgType' type='STRING'>
+   <value enum='D' description='ORDER_SINGLE' />
+  </field>
+  <field number='44' name='Price' type='PRICE' />
+  <field number='54' name='Side' type='CHAR'>
+   <value enum='1' description='BUY' />
+   <value enum='2' description='SELL' />
+  </field>
+  <field number='60' name='TransactTime' type='UTCTIMESTAMP' />
+  <field number='78' name='NoAllocs' type='NUMINGROUP' />
+  <field number='79' name='AllocAccount' type='STRING' />
+  <field number='80' name='AllocShares' type='QTY' />
+ </fields>
+</fix>`
+
+func testDict(t *testing.T) *datadictionary.DataDictionary {
+	dict, err := datadictionary.ParseSrc(strings.NewReader(testDictionary))
+	require.Nil(t, err)
+
+	return dict
+}
+
+// sourceImporter imports the packages that are not generated. It is shared by the tests to import each of them once.
+var (
+	fset           = token.NewFileSet()
+	sourceImporter = importer.ForCompiler(fset, "source", nil)
+)
+
+// fileImporter type checks the generated packages, and imports all others from source.
+type fileImporter struct {
+	files    map[string][]*ast.File
+	packages map[string]*types.Package
+}
+
+func newFileImporter(t *testing.T, files []File) *fileImporter {
+	imp := &fileImporter{
+		files:    make(map[string][]*ast.File),
+		packages: make(map[string]*types.Package),
+	}
+
+	for _, f := range files {
+		file, err := parser.ParseFile(fset, f.Path, f.Content, 0)
+		require.Nil(t, err, f.Path)
+
+		importPath := path.Join(testPackageRoot, path.Dir(f.Path))
+		imp.files[importPath] = append(imp.files[importPath], file)
+	}
+
+	return imp
+}
+
+func (imp *fileImporter) Import(importPath string) (*types.Package, error) {
+	if pkg, ok := imp.packages[importPath]; ok {
+		return pkg, nil
+	}
+
+	files, ok := imp.files[importPath]
+	if !ok {
+		return sourceImporter.Import(importPath)
+	}
+
+	conf := types.Config{Importer: imp}
+	pkg, err := conf.Check(importPath, fset, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	imp.packages[importPath] = pkg
+
+	return pkg, nil
+}
+
+// getterResults returns the results of the getter of the NewOrderSingle field name.
+func getterResults(t *testing.T, imp *fileImporter, name string) string {
+	pkg, err := imp.Import(testPackageRoot + "/fix42/newordersingle")
+	require.Nil(t, err)
+
+	newOrderSingle := pkg.Scope().Lookup("NewOrderSingle")
+	require.NotNil(t, newOrderSingle)
+
+	getter, _, _ := types.LookupFieldOrMethod(newOrderSingle.Type(), false, pkg, "Get"+name)
+	require.NotNil(t, getter, name)
+
+	return types.TypeString(getter.Type().(*types.Signature).Results(), func(p *types.Package) string { return p.Name() })
+}
+
+func TestGenerate(t *testing.T) {
+	var tests = []struct {
+		name       string
+		opts       Options
+		enums      bool
+		price      string
+		side       string
+		allocShare string
+	}{
+		{
+			name:       "default",
+			enums:      true,
+			price:      "(v decimal.Decimal, err quickfix.MessageRejectError)",
+			side:       "(v enum.Side, err quickfix.MessageRejectError)",
+			allocShare: "(v decimal.Decimal, err quickfix.MessageRejectError)",
+		},
+		{
+			name:       "udecimal pointers",
+			opts:       Options{Float: UDecimal, Pointers: true},
+			enums:      true,
+			price:      "(*udecimal.Decimal, quickfix.MessageRejectError)",
+			side:       "(*enum.Side, quickfix.MessageRejectError)",
+			allocShare: "(*udecimal.Decimal, quickfix.MessageRejectError)",
+		},
+		{
+			name:       "float without enums",
+			opts:       Options{Float: Float64, NoEnums: true},
+			price:      "(v float64, err quickfix.MessageRejectError)",
+			side:       "(v string, err quickfix.MessageRejectError)",
+			allocShare: "(v float64, err quickfix.MessageRejectError)",
+		},
+		{
+			name:       "field getters",
+			opts:       Options{Getters: FieldGetters},
+			enums:      true,
+			price:      "(f field.PriceField, err quickfix.MessageRejectError)",
+			side:       "(f field.SideField, err quickfix.MessageRejectError)",
+			allocShare: "(f field.AllocSharesField, err quickfix.MessageRejectError)",
+		},
+		{
+			name:       "field pointer getters",
+			opts:       Options{Getters: FieldGetters, Pointers: true, NoEnums: true},
+			price:      "(*field.PriceField, quickfix.MessageRejectError)",
+			side:       "(*field.SideField, quickfix.MessageRejectError)",
+			allocShare: "(*field.AllocSharesField, quickfix.MessageRejectError)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.opts.PackageRoot = testPackageRoot
+			files, err := Generate([]*datadictionary.DataDictionary{testDict(t)}, test.opts)
+			require.Nil(t, err)
+
+			var paths []string
+			for _, f := range files {
+				paths = append(paths, f.Path)
+			}
+			assert.Contains(t, paths, "tag/tag_numbers.generated.go")
+			assert.Contains(t, paths, "field/fields.generated.go")
+			assert.Contains(t, paths, "fix42/header.generated.go")
+			assert.Contains(t, paths, "fix42/trailer.generated.go")
+			assert.Contains(t, paths, "fix42/newordersingle/NewOrderSingle.generated.go")
+			if test.enums {
+				assert.Contains(t, paths, "enum/enums.generated.go")
+			} else {
+				assert.NotContains(t, paths, "enum/enums.generated.go")
+			}
+
+			imp := newFileImporter(t, files)
+			assert.Equal(t, test.price, getterResults(t, imp, "Price"))
+			assert.Equal(t, test.side, getterResults(t, imp, "Side"))
+
+			pkg, err := imp.Import(testPackageRoot + "/fix42/newordersingle")
+			require.Nil(t, err)
+			noAllocs := pkg.Scope().Lookup("NoAllocs")
+			require.NotNil(t, noAllocs)
+			getter, _, _ := types.LookupFieldOrMethod(noAllocs.Type(), false, pkg, "GetAllocShares")
+			require.NotNil(t, getter)
+			assert.Equal(t, test.allocShare, types.TypeString(getter.Type().(*types.Signature).Results(), func(p *types.Package) string { return p.Name() }))
+		})
+	}
+}
+
+func TestGeneratePackageName(t *testing.T) {
+	files, err := Generate([]*datadictionary.DataDictionary{testDict(t)}, Options{
+		PackageName: func(dict *datadictionary.DataDictionary) string { return "custom" + DefaultPackageName(dict) },
+	})
+	require.Nil(t, err)
+
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	assert.Contains(t, paths, "customfix42/newordersingle/NewOrderSingle.generated.go")
+
+	for _, f := range files {
+		if f.Path == "customfix42/newordersingle/NewOrderSingle.generated.go" {
+			assert.Contains(t, string(f.Content), `"github.com/quickfixgo/customfix42"`)
+		}
+	}
+}
+
+func TestDefaultPackageName(t *testing.T) {
+	var tests = []struct {
+		dict     datadictionary.DataDictionary
+		expected string
+	}{
+		{datadictionary.DataDictionary{FIXType: "FIX", Major: 4, Minor: 4}, "fix44"},
+		{datadictionary.DataDictionary{FIXType: "FIX", Major: 5, Minor: 0, ServicePack: 2}, "fix50sp2"},
+		{datadictionary.DataDictionary{FIXType: "FIXT", Major: 1, Minor: 1}, "fixt11"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, DefaultPackageName(&test.dict))
+	}
+}
@@ -1,4 +1,21 @@
-package internal
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package gen generates the typed message, field, tag and enum packages of data dictionaries, as the generate-fix
+// command does, for build pipelines generating packages of custom dictionaries.
+package gen
 
 import (
 	"fmt"
@@ -15,10 +32,10 @@ func isDecimalType(quickfixType string) bool {
 	}
 }
 
-func checkIfDecimalImportRequiredForFields(fTypes []*datadictionary.FieldType) (ok bool, err error) {
+func (g *generator) checkIfDecimalImportRequiredForFields(fTypes []*datadictionary.FieldType) (ok bool, err error) {
 	var t string
 	for _, fType := range fTypes {
-		t, err = quickfixType(fType)
+		t, err = g.quickfixType(fType)
 		if err != nil {
 			return
 		}
@@ -31,10 +48,10 @@ func checkIfDecimalImportRequiredForFields(fTypes []*datadictionary.FieldType) (
 	return
 }
 
-func checkIfTimeImportRequiredForFields(fTypes []*datadictionary.FieldType) (ok bool, err error) {
+func (g *generator) checkIfTimeImportRequiredForFields(fTypes []*datadictionary.FieldType) (ok bool, err error) {
 	var t string
 	for _, fType := range fTypes {
-		t, err = quickfixType(fType)
+		t, err = g.quickfixType(fType)
 		if err != nil {
 			return
 		}
@@ -52,14 +69,14 @@ func checkIfTimeImportRequiredForFields(fTypes []*datadictionary.FieldType) (ok
 	return
 }
 
-func checkFieldDecimalRequired(f *datadictionary.FieldDef) (required bool, err error) {
+func (g *generator) checkFieldDecimalRequired(f *datadictionary.FieldDef) (required bool, err error) {
 	var globalType *datadictionary.FieldType
-	if globalType, err = getGlobalFieldType(f); err != nil {
+	if globalType, err = g.getGlobalFieldType(f); err != nil {
 		return
 	}
 
 	var t string
-	if t, err = quickfixType(globalType); err != nil {
+	if t, err = g.quickfixType(globalType); err != nil {
 		return
 	}
 
@@ -69,7 +86,7 @@ func checkFieldDecimalRequired(f *datadictionary.FieldDef) (required bool, err e
 	}
 
 	for _, groupField := range f.Fields {
-		if required, err = checkFieldDecimalRequired(groupField); required || err != nil {
+		if required, err = g.checkFieldDecimalRequired(groupField); required || err != nil {
 			return
 		}
 	}
@@ -77,14 +94,14 @@ func checkFieldDecimalRequired(f *datadictionary.FieldDef) (required bool, err e
 	return
 }
 
-func checkFieldTimeRequired(f *datadictionary.FieldDef) (required bool, err error) {
+func (g *generator) checkFieldTimeRequired(f *datadictionary.FieldDef) (required bool, err error) {
 	var globalType *datadictionary.FieldType
-	if globalType, err = getGlobalFieldType(f); err != nil {
+	if globalType, err = g.getGlobalFieldType(f); err != nil {
 		return
 	}
 
 	var t string
-	if t, err = quickfixType(globalType); err != nil {
+	if t, err = g.quickfixType(globalType); err != nil {
 		return
 	}
 
@@ -99,7 +116,7 @@ func checkFieldTimeRequired(f *datadictionary.FieldDef) (required bool, err erro
 	}
 
 	for _, groupField := range f.Fields {
-		if required, err = checkFieldTimeRequired(groupField); required || err != nil {
+		if required, err = g.checkFieldTimeRequired(groupField); required || err != nil {
 			return
 		}
 	}
@@ -107,11 +124,11 @@ func checkFieldTimeRequired(f *datadictionary.FieldDef) (required bool, err erro
 	return
 }
 
-func collectStandardImports(m *datadictionary.MessageDef) (imports []string, err error) {
+func (g *generator) collectStandardImports(m *datadictionary.MessageDef) (imports []string, err error) {
 	var timeRequired bool
 	for _, f := range m.Fields {
 		if !timeRequired {
-			if timeRequired, err = checkFieldTimeRequired(f); err != nil {
+			if timeRequired, err = g.checkFieldTimeRequired(f); err != nil {
 				return
 			}
 		}
@@ -128,15 +145,12 @@ func collectStandardImports(m *datadictionary.MessageDef) (imports []string, err
 	return
 }
 
-func collectExtraImports(m *datadictionary.MessageDef) (imports []string, err error) {
+func (g *generator) collectExtraImports(m *datadictionary.MessageDef) (imports []string, err error) {
 	var decimalRequired bool
-	importPath := "github.com/shopspring/decimal"
-	if *useUDecimal {
-		importPath = "github.com/quagmt/udecimal"
-	}
+	importPath := g.decimalImport()
 	for _, f := range m.Fields {
 		if !decimalRequired {
-			if decimalRequired, err = checkFieldDecimalRequired(f); err != nil {
+			if decimalRequired, err = g.checkFieldDecimalRequired(f); err != nil {
 				return
 			}
 		}
@@ -153,9 +167,9 @@ func collectExtraImports(m *datadictionary.MessageDef) (imports []string, err er
 	return
 }
 
-func checkIfEnumImportRequired(m *datadictionary.MessageDef) (required bool, err error) {
+func (g *generator) checkIfEnumImportRequired(m *datadictionary.MessageDef) (required bool, err error) {
 	for _, f := range m.Fields {
-		required, err = checkFieldEnumRequired(f)
+		required, err = g.checkFieldEnumRequired(f)
 		if err != nil || required {
 			return
 		}
@@ -164,25 +178,28 @@ func checkIfEnumImportRequired(m *datadictionary.MessageDef) (required bool, err
 	return
 }
 
-func checkFieldEnumRequired(f *datadictionary.FieldDef) (required bool, err error) {
+func (g *generator) checkFieldEnumRequired(f *datadictionary.FieldDef) (required bool, err error) {
 	var globalType *datadictionary.FieldType
-	if globalType, err = getGlobalFieldType(f); err != nil {
+	if globalType, err = g.getGlobalFieldType(f); err != nil {
 		return
 	}
 
-	if globalType.Enums != nil && 0 < len(globalType.Enums) {
-		var t string
-		if t, err = quickfixType(globalType); err != nil {
-			return
-		}
-		if t != "FIXBoolean" {
-			required = true
+	if required, err = g.isEnum(globalType); required || err != nil {
+		return
+	}
+
+	for _, groupField := range f.Fields {
+		if required, err = g.checkFieldEnumRequired(groupField); required || err != nil {
 			return
 		}
 	}
 
-	for _, groupField := range f.Fields {
-		if required, err = checkFieldEnumRequired(groupField); required || err != nil {
+	return
+}
+
+func (g *generator) checkIfEnumImportRequiredForFields(fTypes []*datadictionary.FieldType) (required bool, err error) {
+	for _, fType := range fTypes {
+		if required, err = g.isEnum(fType); required || err != nil {
 			return
 		}
 	}
@@ -190,6 +207,42 @@ func checkFieldEnumRequired(f *datadictionary.FieldDef) (required bool, err erro
 	return
 }
 
+// isEnum returns true if the field is generated as a field of its enum type.
+func (g *generator) isEnum(field *datadictionary.FieldType) (bool, error) {
+	if g.opts.NoEnums || len(field.Enums) == 0 {
+		return false, nil
+	}
+
+	t, err := g.quickfixType(field)
+	return t != "FIXBoolean", err
+}
+
+// valueType returns the Go type of the values of the field.
+func (g *generator) valueType(f *datadictionary.FieldDef) (string, error) {
+	globalType, err := g.getGlobalFieldType(f)
+	if err != nil {
+		return "", err
+	}
+
+	if enum, err := g.isEnum(globalType); err != nil || enum {
+		return "enum." + f.Name(), err
+	}
+
+	t, err := g.quickfixType(globalType)
+	if err != nil {
+		return "", err
+	}
+
+	return quickfixValueType(t)
+}
+
+func (g *generator) decimalImport() string {
+	if g.opts.Float == UDecimal {
+		return "github.com/quagmt/udecimal"
+	}
+	return "github.com/shopspring/decimal"
+}
+
 func quickfixValueType(quickfixType string) (goType string, err error) {
 	switch quickfixType {
 	case "FIXString":
@@ -213,7 +266,7 @@ func quickfixValueType(quickfixType string) (goType string, err error) {
 	return
 }
 
-func quickfixType(field *datadictionary.FieldType) (quickfixType string, err error) {
+func (g *generator) quickfixType(field *datadictionary.FieldType) (quickfixType string, err error) {
 	switch field.Type {
 	case "MULTIPLESTRINGVALUE", "MULTIPLEVALUESTRING":
 		fallthrough
@@ -288,11 +341,12 @@ func quickfixType(field *datadictionary.FieldType) (quickfixType string, err err
 	case "PERCENTAGE":
 		fallthrough
 	case "FLOAT":
-		if *useFloat {
+		switch g.opts.Float {
+		case Float64:
 			quickfixType = "FIXFloat"
-		} else if *useUDecimal {
+		case UDecimal:
 			quickfixType = "FIXUDecimal"
-		} else {
+		default:
 			quickfixType = "FIXDecimal"
 		}
 
@@ -324,6 +378,22 @@ func requiredFields(m *datadictionary.MessageDef) (required []*datadictionary.Fi
 	return
 }
 
+// requiredTags returns the required fields of the message, including the count fields of required groups.
+func requiredTags(m *datadictionary.MessageDef) (required []*datadictionary.FieldDef) {
+	for _, part := range m.RequiredParts() {
+		if part.Required() {
+			switch pType := part.(type) {
+			case *datadictionary.FieldDef:
+				required = append(required, pType)
+			case *datadictionary.Component:
+				required = append(required, pType.RequiredFields()...)
+			}
+		}
+	}
+
+	return
+}
+
 func beginString(spec *datadictionary.DataDictionary) string {
 	if spec.FIXType == "FIXT" || spec.Major == 5 {
 		return "FIXT.1.1"
@@ -1,41 +1,79 @@
-package internal
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package gen generates the typed message, field, tag and enum packages of data dictionaries, as the generate-fix
+// command does, for build pipelines generating packages of custom dictionaries.
+package gen
 
 import (
 	"strings"
 	"text/template"
 )
 
-var (
-	HeaderTemplate  *template.Template
-	TrailerTemplate *template.Template
-	MessageTemplate *template.Template
-	TagTemplate     *template.Template
-	FieldTemplate   *template.Template
-	EnumTemplate    *template.Template
-)
+// templates are the templates of the generated files.
+type templates struct {
+	header, trailer, message, tag, field, enum *template.Template
+}
 
-func init() {
-	tmplFuncs := template.FuncMap{
+// parsedTemplates are parsed once with the names of the functions of generators, and cloned with the functions of
+// each generator by newTemplates.
+var parsedTemplates templates
+
+func newTemplates(funcs template.FuncMap) templates {
+	clone := func(t *template.Template) *template.Template {
+		return template.Must(t.Clone()).Funcs(funcs)
+	}
+
+	return templates{
+		header:  clone(parsedTemplates.header),
+		trailer: clone(parsedTemplates.trailer),
+		message: clone(parsedTemplates.message),
+		tag:     clone(parsedTemplates.tag),
+		field:   clone(parsedTemplates.field),
+		enum:    clone(parsedTemplates.enum),
+	}
+}
+
+func (g *generator) funcs() template.FuncMap {
+	return template.FuncMap{
 		"toLower":                               strings.ToLower,
 		"requiredFields":                        requiredFields,
+		"requiredTags":                          requiredTags,
 		"beginString":                           beginString,
 		"routerBeginString":                     routerBeginString,
-		"importRootPath":                        getImportPathRoot,
-		"quickfixType":                          quickfixType,
+		"importRootPath":                        func() string { return g.opts.PackageRoot },
+		"quickfixType":                          g.quickfixType,
 		"quickfixValueType":                     quickfixValueType,
-		"getGlobalFieldType":                    getGlobalFieldType,
-		"collectStandardImports":                collectStandardImports,
-		"collectExtraImports":                   collectExtraImports,
-		"checkIfDecimalImportRequiredForFields": checkIfDecimalImportRequiredForFields,
-		"decimalImport": func() string {
-			if *useUDecimal {
-				return "github.com/quagmt/udecimal"
-			}
-			return "github.com/shopspring/decimal"
-		},
-		"checkIfTimeImportRequiredForFields": checkIfTimeImportRequiredForFields,
-		"checkIfEnumImportRequired":          checkIfEnumImportRequired,
+		"valueType":                             g.valueType,
+		"isEnum":                                g.isEnum,
+		"fieldGetters":                          func() bool { return g.opts.Getters == FieldGetters },
+		"pointers":                              func() bool { return g.opts.Pointers },
+		"getGlobalFieldType":                    g.getGlobalFieldType,
+		"collectStandardImports":                g.collectStandardImports,
+		"collectExtraImports":                   g.collectExtraImports,
+		"checkIfDecimalImportRequiredForFields": g.checkIfDecimalImportRequiredForFields,
+		"decimalImport":                         g.decimalImport,
+		"checkIfTimeImportRequiredForFields":    g.checkIfTimeImportRequiredForFields,
+		"checkIfEnumImportRequired":             g.checkIfEnumImportRequired,
+		"checkIfEnumImportRequiredForFields":    g.checkIfEnumImportRequiredForFields,
 	}
+}
+
+func init() {
+	// Method values of a nil generator are only bound to names here, and never called.
+	tmplFuncs := (*generator)(nil).funcs()
 
 	baseTemplate := template.Must(template.New("Base").Funcs(tmplFuncs).Parse(`
 {{ define "receiver" }}RECEIVER{{ end }}
@@ -43,7 +81,7 @@ func init() {
 {{ define "fieldsetter" -}}
 {{- $field_type := getGlobalFieldType . -}}
 {{- $qfix_type := quickfixType $field_type -}}
-{{- if and ($field_type.Enums) (ne $qfix_type "FIXBoolean") -}}
+{{- if isEnum $field_type -}}
 Set{{ .Name }}(v enum.{{ .Name }}) {
 	{{ template "receiver" }}.Set(field.New{{ .Name }}(v))
 }
@@ -74,31 +112,46 @@ func ({{ template "receiver" }} {{ $.Name }}) {{ if .IsGroup }}{{ template "grou
 {{ end }}{{ end }}
 
 {{ define "fieldgetter" -}}
+{{- if pointers -}}
+Get{{ .Name }}() (*field.{{ .Name }}Field, quickfix.MessageRejectError) {
+	if !{{ template "receiver" }}.Has(tag.{{ .Name }}) {
+		return nil, nil
+	}
+	var f field.{{ .Name }}Field
+	if err := {{ template "receiver" }}.Get(&f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+{{- else -}}
 Get{{ .Name }}() (f field.{{ .Name }}Field, err quickfix.MessageRejectError) {
 	err = {{ template "receiver" }}.Get(&f)
 	return
 }
-{{- end }}
+{{- end }}{{ end }}
 
 {{ define "fieldvaluegetter" -}}
-{{- $ft := getGlobalFieldType . -}}
-{{- $bt := quickfixType $ft -}}
-{{- if and $ft.Enums (ne $bt "FIXBoolean") -}}
-Get{{ .Name }}() (v enum.{{ .Name }}, err quickfix.MessageRejectError) {
-{{- else if eq $bt "FIXDecimal" -}}
-Get{{ .Name }}() (v decimal.Decimal, err quickfix.MessageRejectError) {
-{{- else if eq $bt "FIXUDecimal" -}}
-Get{{ .Name }}() (v udecimal.Decimal, err quickfix.MessageRejectError) {
+{{- if pointers -}}
+Get{{ .Name }}() (*{{ valueType . }}, quickfix.MessageRejectError) {
+	if !{{ template "receiver" }}.Has(tag.{{ .Name }}) {
+		return nil, nil
+	}
+	var f field.{{ .Name }}Field
+	if err := {{ template "receiver" }}.Get(&f); err != nil {
+		return nil, err
+	}
+	v := f.Value()
+	return &v, nil
+}
 {{- else -}}
-Get{{ .Name }}() (v {{ quickfixValueType $bt }}, err quickfix.MessageRejectError) {
-{{- end }}
+Get{{ .Name }}() (v {{ valueType . }}, err quickfix.MessageRejectError) {
 	var f field.{{ .Name }}Field
 	if err = {{ template "receiver" }}.Get(&f); err == nil {
 		v = f.Value()
 	}
 	return
 }
-{{- end }}
+{{- end }}{{ end }}
 
 {{ define "groupgetter" -}}
 Get{{ .Name }}() ({{ .Name }}RepeatingGroup, quickfix.MessageRejectError) {
@@ -112,7 +165,7 @@ Get{{ .Name }}() ({{ .Name }}RepeatingGroup, quickfix.MessageRejectError) {
 {{ define "getters" }}
 {{ range .Fields }}
 // Get{{ .Name }} gets {{ .Name }}, Tag {{ .Tag }}.
-func ({{ template "receiver" }} {{ $.Name }}) {{if .IsGroup}}{{ template "groupgetter" . }}{{ else }}{{ template "fieldvaluegetter" .}}{{ end }}
+func ({{ template "receiver" }} {{ $.Name }}) {{ if .IsGroup }}{{ template "groupgetter" . }}{{ else if fieldGetters }}{{ template "fieldgetter" . }}{{ else }}{{ template "fieldvaluegetter" . }}{{ end }}
 {{ end }}{{ end }}
 
 {{ define "hasers" }}
@@ -181,7 +234,7 @@ func ({{ template "receiver" }} {{ .Name}}RepeatingGroup) Get(i int) {{ .Name }}
 {{ end }}{{ end }}{{ end }}
 `))
 
-	HeaderTemplate = template.Must(template.Must(baseTemplate.Clone()).Parse(`
+	parsedTemplates.header = template.Must(template.Must(baseTemplate.Clone()).Parse(`
 {{ define "receiver" }}h{{ end }}
 // Code generated by quickfix. DO NOT EDIT.
 package {{ .Package }}
@@ -223,7 +276,7 @@ func NewHeader(header *quickfix.Header) (h Header) {
 {{ template "groups" . }}
 	`))
 
-	TrailerTemplate = template.Must(template.Must(baseTemplate.Clone()).Parse(`
+	parsedTemplates.trailer = template.Must(template.Must(baseTemplate.Clone()).Parse(`
 {{ define "receiver" }}t{{ end }}
 // Code generated by quickfix. DO NOT EDIT.
 package {{ .Package }}
@@ -258,7 +311,7 @@ type Trailer struct {
 {{ template "groups" . }}
 `))
 
-	MessageTemplate = template.Must(baseTemplate.Parse(`
+	parsedTemplates.message = template.Must(baseTemplate.Parse(`
 {{ define "receiver" }}m{{ end }}
 // Code generated by quickfix. DO NOT EDIT.
 package {{ .Package }}
@@ -322,6 +375,12 @@ func New({{template "field_args" $required_fields }}) (m {{ .Name }}) {
 	return
 }
 
+// Validate returns a *quickfix.MissingFieldsError listing the required fields of {{ .Name }} that are not set.
+func (m {{ .Name }}) Validate() error {
+	return quickfix.ValidateRequiredFields(m.Message
+	{{- range requiredTags .MessageDef }}, tag.{{ .Name }}{{ end }})
+}
+
 // A RouteOut is the callback type that should be implemented for routing Message.
 type RouteOut func(msg {{ .Name }}, sessionID quickfix.SessionID) quickfix.MessageRejectError
 
@@ -339,7 +398,7 @@ func Route(router RouteOut) (string, string, quickfix.MessageRoute) {
 {{ template "groups" . }}
 	`))
 
-	TagTemplate = template.Must(template.New("Tag").Parse(`
+	parsedTemplates.tag = template.Must(template.New("Tag").Parse(`
 // Code generated by quickfix. DO NOT EDIT.
 package tag
 import "github.com/quickfixgo/quickfix"
@@ -351,7 +410,7 @@ const (
 )
 	`))
 
-	FieldTemplate = template.Must(template.New("Field").Funcs(tmplFuncs).Parse(`
+	parsedTemplates.field = template.Must(template.New("Field").Funcs(tmplFuncs).Parse(`
 // Code generated by quickfix. DO NOT EDIT.
 package field
 import (
@@ -360,14 +419,14 @@ import (
 	{{ if checkIfDecimalImportRequiredForFields . }}"{{ decimalImport }}"{{ end }}
 
 	"github.com/quickfixgo/quickfix"
-	"{{ importRootPath }}/enum"
+	{{ if checkIfEnumImportRequiredForFields . }}"{{ importRootPath }}/enum"{{ end }}
 	"{{ importRootPath }}/tag"
 )
 
 {{ range . }}
 {{- $base_type := quickfixType . -}}
 
-{{ if and .Enums (ne $base_type "FIXBoolean") }}
+{{ if isEnum . }}
 // {{ .Name }}Field is a enum.{{ .Name }} field.
 type {{ .Name }}Field struct { quickfix.FIXString }
 {{ else }}
@@ -394,7 +453,7 @@ func New{{ .Name }}WithPrecision(val time.Time, precision quickfix.TimestampPrec
 	return {{ .Name }}Field{ quickfix.FIXUTCTimestamp{ Time: val, Precision: precision } }
 }
 
-{{ else if and  .Enums (ne $base_type "FIXBoolean") }}
+{{ else if isEnum . }}
 func New{{ .Name }}(val enum.{{ .Name }}) {{ .Name }}Field {
 	return {{ .Name }}Field{ quickfix.FIXString(val) }
 }
@@ -415,7 +474,7 @@ func New{{ .Name }}(val {{ quickfixValueType $base_type }}) {{ .Name }}Field {
 }
 {{ end }}
 
-{{ if and  .Enums (ne $base_type "FIXBoolean") }}
+{{ if isEnum . }}
 func (f {{ .Name }}Field) Value() enum.{{ .Name }} { return enum.{{ .Name }}(f.String()) }
 {{ else if eq $base_type "FIXDecimal" }}
 func (f {{ .Name }}Field) Value() (val decimal.Decimal) { return f.Decimal }
@@ -432,13 +491,13 @@ func (f {{ .Name }}Field) Value() ({{ quickfixValueType $base_type }}) {
 {{- else if eq $base_type "FIXUTCTimestamp" -}}
  return f.Time }
 {{- else if eq $base_type "FIXFloat" -}}
- return f.Float() }
+ return f.Float64() }
 {{- else -}}
  TEMPLATE ERROR: Value() for {{ $base_type }}
 {{ end }}{{ end }}{{ end }}
 `))
 
-	EnumTemplate = template.Must(template.New("Enum").Parse(`
+	parsedTemplates.enum = template.Must(template.New("Enum").Parse(`
 // Code generated by quickfix. DO NOT EDIT.
 package enum
 {{ range $ft := . }}
@@ -0,0 +1,174 @@
+package datadictionary
+
+import (
+	"fmt"
+)
+
+// Merge overlays patch, the xml document of a dictionary fragment, onto doc. Fields of the patch are added, or
+// extend the fields of doc with the same number or name with their enum values and replace their type if the patch
+// sets one. Messages, matched by msgtype, components, matched by name, and the header and trailer are added, or gain
+// the members of the patch they do not have, members of nested groups included, with the required attribute of the
+// patch members replacing theirs.
+//
+// The patch usually only has the fields, messages and components it changes, and may leave out the type, major and
+// minor attributes; if it sets them they must match doc.
+func (doc *XMLDoc) Merge(patch *XMLDoc) error {
+	if err := doc.checkPatchVersion(patch); err != nil {
+		return err
+	}
+
+	if err := doc.mergeFields(patch.Fields); err != nil {
+		return err
+	}
+
+	doc.Header = mergeComponent(doc.Header, patch.Header)
+	doc.Trailer = mergeComponent(doc.Trailer, patch.Trailer)
+
+	for _, component := range patch.Components {
+		merged := false
+		for i, c := range doc.Components {
+			if c.Name == component.Name {
+				doc.Components[i] = mergeComponent(c, component)
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			doc.Components = append(doc.Components, component)
+		}
+	}
+
+	for _, msg := range patch.Messages {
+		merged := false
+		for i, m := range doc.Messages {
+			if m.MsgType == msg.MsgType {
+				doc.Messages[i] = mergeComponent(m, msg)
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			if msg.MsgType == "" {
+				return fmt.Errorf("message %v has no msgtype", msg.Name)
+			}
+			doc.Messages = append(doc.Messages, msg)
+		}
+	}
+
+	return nil
+}
+
+func (doc *XMLDoc) checkPatchVersion(patch *XMLDoc) error {
+	for _, attr := range []struct{ name, doc, patch string }{
+		{"type", doc.Type, patch.Type},
+		{"major", doc.Major, patch.Major},
+		{"minor", doc.Minor, patch.Minor},
+	} {
+		if attr.patch != "" && attr.patch != attr.doc {
+			return fmt.Errorf("patch %v attribute %v does not match %v", attr.name, attr.patch, attr.doc)
+		}
+	}
+
+	if patch.ServicePack != 0 && patch.ServicePack != doc.ServicePack {
+		return fmt.Errorf("patch servicepack attribute %v does not match %v", patch.ServicePack, doc.ServicePack)
+	}
+
+	return nil
+}
+
+func (doc *XMLDoc) mergeFields(fields []*XMLField) error {
+	byNumber := make(map[int]*XMLField, len(doc.Fields))
+	byName := make(map[string]*XMLField, len(doc.Fields))
+	for _, f := range doc.Fields {
+		byNumber[f.Number] = f
+		byName[f.Name] = f
+	}
+
+	for _, f := range fields {
+		base, ok := byNumber[f.Number]
+		named, hasName := byName[f.Name]
+		switch {
+		case f.Number == 0 && !hasName:
+			return fmt.Errorf("field %v has no number", f.Name)
+		case f.Number == 0:
+			base, ok = named, true
+		case ok && base.Name != f.Name:
+			return fmt.Errorf("field %v is named %v", f.Number, base.Name)
+		case !ok && hasName:
+			return fmt.Errorf("field %v is number %v, not %v", f.Name, named.Number, f.Number)
+		}
+
+		if !ok {
+			if f.Type == "" {
+				return fmt.Errorf("field %v has no type", f.Name)
+			}
+			doc.Fields = append(doc.Fields, f)
+			byNumber[f.Number], byName[f.Name] = f, f
+			continue
+		}
+
+		if f.Type != "" {
+			base.Type = f.Type
+		}
+		base.Values = mergeValues(base.Values, f.Values)
+	}
+
+	return nil
+}
+
+func mergeValues(values, patch []*XMLValue) []*XMLValue {
+	for _, v := range patch {
+		merged := false
+		for _, value := range values {
+			if value.Enum == v.Enum {
+				if v.Description != "" {
+					value.Description = v.Description
+				}
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+func mergeComponent(component, patch *XMLComponent) *XMLComponent {
+	switch {
+	case patch == nil:
+		return component
+	case component == nil:
+		return patch
+	}
+
+	component.Members = mergeMembers(component.Members, patch.Members)
+	return component
+}
+
+func mergeMembers(members, patch []*XMLComponentMember) []*XMLComponentMember {
+	for _, p := range patch {
+		merged := false
+		for _, m := range members {
+			if m.XMLName.Local == p.XMLName.Local && m.Name == p.Name {
+				if p.Required != "" {
+					m.Required = p.Required
+				}
+				m.Members = mergeMembers(m.Members, p.Members)
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			members = append(members, p)
+		}
+	}
+
+	return members
+}
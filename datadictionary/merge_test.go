@@ -0,0 +1,116 @@
+package datadictionary
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fix44XMLDoc(t *testing.T) *XMLDoc {
+	xmlFile, err := os.Open("../spec/FIX44.xml")
+	require.Nil(t, err)
+	defer xmlFile.Close()
+
+	doc, err := ParseXMLDoc(xmlFile)
+	require.Nil(t, err)
+
+	return doc
+}
+
+func patchXMLDoc(t *testing.T, patch string) *XMLDoc {
+	doc, err := ParseXMLDoc(strings.NewReader(patch))
+	require.Nil(t, err)
+
+	return doc
+}
+
+const customFieldsPatch = `
+<fix type='FIX' major='4' minor='4'>
+ <messages>
+  <message name='NewOrderSingle' msgtype='D'>
+   <field name='CustomStrategy' required='N' />
+   <group name='NoPartyIDs'>
+    <field name='CustomPartyFlag' required='N' />
+   </group>
+  </message>
+  <message name='CustomReport' msgcat='app' msgtype='U1'>
+   <field name='CustomStrategy' required='Y' />
+  </message>
+ </messages>
+ <components>
+  <component name='Parties'>
+   <group name='NoPartyIDs'>
+    <field name='CustomPartyFlag' required='N' />
+   </group>
+  </component>
+ </components>
+ <fields>
+  <field number='5001' name='CustomStrategy' type='STRING'>
+   <value enum='A' description='AGGRESSIVE' />
+  </field>
+  <field number='5002' name='CustomPartyFlag' type='BOOLEAN' />
+  <field name='Side'>
+   <value enum='Z' description='CUSTOM_SIDE' />
+  </field>
+ </fields>
+</fix>`
+
+func TestMerge(t *testing.T) {
+	doc := fix44XMLDoc(t)
+	require.Nil(t, doc.Merge(patchXMLDoc(t, customFieldsPatch)))
+
+	dict, err := Build(doc)
+	require.Nil(t, err)
+
+	require.Contains(t, dict.FieldTypeByTag, 5001)
+	assert.Equal(t, "CustomStrategy", dict.FieldTypeByTag[5001].Name())
+	assert.Contains(t, dict.FieldTypeByTag[5001].Enums, "A")
+	assert.Contains(t, dict.FieldTypeByTag[54].Enums, "Z")
+	assert.Contains(t, dict.FieldTypeByTag[54].Enums, "1")
+
+	require.Contains(t, dict.Messages, "U1")
+	assert.Contains(t, dict.Messages["U1"].RequiredTags, 5001)
+
+	newOrderSingle := dict.Messages["D"]
+	assert.Contains(t, newOrderSingle.Tags, 5001)
+	assert.NotContains(t, newOrderSingle.RequiredTags, 5001)
+	assert.Contains(t, newOrderSingle.Tags, 5002)
+	assert.Contains(t, newOrderSingle.Tags, 11)
+}
+
+func TestMergeErrors(t *testing.T) {
+	var tests = []struct {
+		name  string
+		patch string
+	}{
+		{"other version", `<fix type='FIX' major='4' minor='2'/>`},
+		{"other service pack", `<fix servicepack='1'/>`},
+		{"field renamed", `<fix><fields><field number='11' name='OrderRef' type='STRING'/></fields></fix>`},
+		{"field renumbered", `<fix><fields><field number='5001' name='ClOrdID' type='STRING'/></fields></fix>`},
+		{"field without number", `<fix><fields><field name='CustomStrategy' type='STRING'/></fields></fix>`},
+		{"field without type", `<fix><fields><field number='5001' name='CustomStrategy'/></fields></fix>`},
+		{"message without msgtype", `<fix><messages><message name='CustomReport'/></messages></fix>`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.NotNil(t, fix44XMLDoc(t).Merge(patchXMLDoc(t, test.patch)))
+		})
+	}
+}
+
+func TestXMLDocWrite(t *testing.T) {
+	doc := fix44XMLDoc(t)
+	require.Nil(t, doc.Merge(patchXMLDoc(t, customFieldsPatch)))
+
+	var b bytes.Buffer
+	require.Nil(t, doc.Write(&b))
+
+	written, err := ParseXMLDoc(&b)
+	require.Nil(t, err)
+	assert.Equal(t, doc, written)
+}
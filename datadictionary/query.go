@@ -0,0 +1,129 @@
+package datadictionary
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Version returns the BeginString of the version of the data dictionary, with the service pack of FIX.5.0 versions,
+// e.g. FIX.4.4 or FIX.5.0SP2.
+func (d *DataDictionary) Version() string {
+	version := fmt.Sprintf("%v.%v.%v", d.FIXType, d.Major, d.Minor)
+	if d.ServicePack > 0 {
+		version += fmt.Sprintf("SP%v", d.ServicePack)
+	}
+
+	return version
+}
+
+// MessageDefs returns the definitions of the messages of the data dictionary, ordered by MsgType.
+func (d *DataDictionary) MessageDefs() []*MessageDef {
+	defs := make([]*MessageDef, 0, len(d.Messages))
+	for _, def := range d.Messages {
+		defs = append(defs, def)
+	}
+
+	sort.Slice(defs, func(i, j int) bool { return defs[i].MsgType < defs[j].MsgType })
+	return defs
+}
+
+// MessageDefByName returns the definition of the message named name, e.g. NewOrderSingle.
+func (d *DataDictionary) MessageDefByName(name string) (*MessageDef, bool) {
+	for _, def := range d.Messages {
+		if def.Name == name {
+			return def, true
+		}
+	}
+
+	return nil, false
+}
+
+// FieldTypes returns the types of the fields of the data dictionary, ordered by tag.
+func (d *DataDictionary) FieldTypes() []*FieldType {
+	types := make([]*FieldType, 0, len(d.FieldTypeByTag))
+	for _, fieldType := range d.FieldTypeByTag {
+		types = append(types, fieldType)
+	}
+
+	sort.Slice(types, func(i, j int) bool { return types[i].tag < types[j].tag })
+	return types
+}
+
+// EnumValues returns the enumerated values of the field, ordered by value, or nil if any value is valid.
+func (f FieldType) EnumValues() []Enum {
+	if len(f.Enums) == 0 {
+		return nil
+	}
+
+	values := make([]Enum, 0, len(f.Enums))
+	for _, enum := range f.Enums {
+		values = append(values, enum)
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i].Value < values[j].Value })
+	return values
+}
+
+// FieldDefs returns the fields and repeating groups of the message in declaration order, with the fields of its
+// components in place of the components. The fields of repeating groups are the Fields of their FieldDef.
+func (m MessageDef) FieldDefs() []*FieldDef {
+	var defs []*FieldDef
+	for _, part := range m.Parts {
+		switch p := part.(type) {
+		case messagePartWithFields:
+			defs = append(defs, p.Fields()...)
+		case *FieldDef:
+			defs = append(defs, p)
+		}
+	}
+
+	return defs
+}
+
+// RequiredFields returns the fields and repeating groups the message requires, in declaration order. Fields of
+// components are only required if the component is.
+func (m MessageDef) RequiredFields() []*FieldDef {
+	var required []*FieldDef
+	for _, def := range m.FieldDefs() {
+		if _, ok := m.RequiredTags[def.Tag()]; ok {
+			required = append(required, def)
+		}
+	}
+
+	return required
+}
+
+// OptionalFields returns the fields and repeating groups of the message it does not require, in declaration order.
+func (m MessageDef) OptionalFields() []*FieldDef {
+	var optional []*FieldDef
+	for _, def := range m.FieldDefs() {
+		if _, ok := m.RequiredTags[def.Tag()]; !ok {
+			optional = append(optional, def)
+		}
+	}
+
+	return optional
+}
+
+// Group returns the repeating group of the message whose NumInGroup field is tag, nested in other groups or not.
+func (m MessageDef) Group(tag int) (*FieldDef, bool) {
+	return findGroup(m.FieldDefs(), tag)
+}
+
+func findGroup(defs []*FieldDef, tag int) (*FieldDef, bool) {
+	for _, def := range defs {
+		if !def.IsGroup() {
+			continue
+		}
+
+		if def.Tag() == tag {
+			return def, true
+		}
+
+		if group, ok := findGroup(def.Fields, tag); ok {
+			return group, true
+		}
+	}
+
+	return nil, false
+}
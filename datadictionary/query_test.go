@@ -0,0 +1,107 @@
+package datadictionary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersion(t *testing.T) {
+	var tests = []struct {
+		dict     DataDictionary
+		expected string
+	}{
+		{DataDictionary{FIXType: "FIX", Major: 4, Minor: 4}, "FIX.4.4"},
+		{DataDictionary{FIXType: "FIX", Major: 5, Minor: 0, ServicePack: 2}, "FIX.5.0SP2"},
+		{DataDictionary{FIXType: "FIXT", Major: 1, Minor: 1}, "FIXT.1.1"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.dict.Version())
+	}
+}
+
+func TestMessageDefs(t *testing.T) {
+	d, err := Parse("../spec/FIX44.xml")
+	require.Nil(t, err)
+
+	defs := d.MessageDefs()
+	require.Len(t, defs, len(d.Messages))
+	assert.Equal(t, "0", defs[0].MsgType)
+	for i := 1; i < len(defs); i++ {
+		assert.Less(t, defs[i-1].MsgType, defs[i].MsgType)
+	}
+
+	def, ok := d.MessageDefByName("NewOrderSingle")
+	require.True(t, ok)
+	assert.Equal(t, "D", def.MsgType)
+
+	_, ok = d.MessageDefByName("Bogus")
+	assert.False(t, ok)
+}
+
+func TestFieldTypes(t *testing.T) {
+	d, err := Parse("../spec/FIX44.xml")
+	require.Nil(t, err)
+
+	types := d.FieldTypes()
+	require.Len(t, types, len(d.FieldTypeByTag))
+	assert.Equal(t, 1, types[0].Tag())
+	for i := 1; i < len(types); i++ {
+		assert.Less(t, types[i-1].Tag(), types[i].Tag())
+	}
+
+	side := d.FieldTypeByTag[54]
+	values := side.EnumValues()
+	require.Len(t, values, len(side.Enums))
+	assert.Equal(t, Enum{Value: "1", Description: "BUY"}, values[0])
+
+	assert.Nil(t, d.FieldTypeByTag[11].EnumValues())
+}
+
+func TestMessageDefFields(t *testing.T) {
+	d, err := Parse("../spec/FIX44.xml")
+	require.Nil(t, err)
+
+	def := d.Messages["D"]
+	tags := func(defs []*FieldDef) []int {
+		var tags []int
+		for _, def := range defs {
+			tags = append(tags, def.Tag())
+		}
+		return tags
+	}
+
+	all := tags(def.FieldDefs())
+	required := tags(def.RequiredFields())
+	optional := tags(def.OptionalFields())
+
+	assert.Equal(t, []int{11, 54, 60, 40}, required)
+	assert.Len(t, all, len(required)+len(optional))
+	assert.Equal(t, 11, all[0])
+	// Symbol(55) of the Instrument component is a field of the message, PartyID(448) of the NoPartyIDs group is not.
+	assert.Contains(t, optional, 55)
+	assert.NotContains(t, all, 448)
+	assert.Contains(t, optional, 453)
+}
+
+func TestMessageDefGroup(t *testing.T) {
+	d, err := Parse("../spec/FIX44.xml")
+	require.Nil(t, err)
+
+	def := d.Messages["D"]
+
+	parties, ok := def.Group(453)
+	require.True(t, ok)
+	assert.Equal(t, "NoPartyIDs", parties.Name())
+	assert.Equal(t, 448, parties.Fields[0].Tag())
+
+	// NoPartySubIDs(802) is nested in NoPartyIDs.
+	subIDs, ok := def.Group(802)
+	require.True(t, ok)
+	assert.Equal(t, "NoPartySubIDs", subIDs.Name())
+
+	_, ok = def.Group(11)
+	assert.False(t, ok)
+}
@@ -2,6 +2,7 @@ package datadictionary
 
 import (
 	"encoding/xml"
+	"io"
 )
 
 // XMLDoc is the unmarshalled root of a FIX Dictionary.
@@ -9,7 +10,7 @@ type XMLDoc struct {
 	Type        string `xml:"type,attr"`
 	Major       string `xml:"major,attr"`
 	Minor       string `xml:"minor,attr"`
-	ServicePack int    `xml:"servicepack,attr"`
+	ServicePack int    `xml:"servicepack,attr,omitempty"`
 
 	Header     *XMLComponent   `xml:"header"`
 	Trailer    *XMLComponent   `xml:"trailer"`
@@ -18,34 +19,50 @@ type XMLDoc struct {
 	Fields     []*XMLField     `xml:"fields>field"`
 }
 
+// Write writes the document as the xml source of a dictionary.
+func (doc *XMLDoc) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", " ")
+	if err := encoder.EncodeElement(doc, xml.StartElement{Name: xml.Name{Local: "fix"}}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
 // XMLComponent can represent header, trailer, messages/message, or components/component xml elements.
 type XMLComponent struct {
-	Name    string `xml:"name,attr"`
-	MsgCat  string `xml:"msgcat,attr"`
-	MsgType string `xml:"msgtype,attr"`
+	Name    string `xml:"name,attr,omitempty"`
+	MsgCat  string `xml:"msgcat,attr,omitempty"`
+	MsgType string `xml:"msgtype,attr,omitempty"`
 
 	Members []*XMLComponentMember `xml:",any"`
 }
 
 // XMLField represents the fields/field xml element.
 type XMLField struct {
-	Number int         `xml:"number,attr"`
+	Number int         `xml:"number,attr,omitempty"`
 	Name   string      `xml:"name,attr"`
-	Type   string      `xml:"type,attr"`
+	Type   string      `xml:"type,attr,omitempty"`
 	Values []*XMLValue `xml:"value"`
 }
 
 // XMLValue represents the fields/field/value xml element.
 type XMLValue struct {
 	Enum        string `xml:"enum,attr"`
-	Description string `xml:"description,attr"`
+	Description string `xml:"description,attr,omitempty"`
 }
 
 // XMLComponentMember represents child elements of header, trailer, messages/message, and components/component elements.
 type XMLComponentMember struct {
 	XMLName  xml.Name
 	Name     string `xml:"name,attr"`
-	Required string `xml:"required,attr"`
+	Required string `xml:"required,attr,omitempty"`
 
 	Members []*XMLComponentMember `xml:",any"`
 }
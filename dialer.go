@@ -16,6 +16,7 @@
 package quickfix
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"time"
@@ -93,3 +94,23 @@ func loadDialerConfig(settings *SessionSettings) (dialer proxy.ContextDialer, er
 
 	return
 }
+
+// DialFunc connects an initiator session over a custom transport. ctx is cancelled if the Initiator is stopped,
+// or after the SocketConnectTimeout of the endpoint the session would otherwise connect to.
+type DialFunc func(ctx context.Context) (net.Conn, error)
+
+// dialEndpoint connects to the session's DialFunc if set, otherwise to the SocketConnectAddress of endpoint,
+// a TCP address or a unix socket or pipe. It gives up after the endpoint's SocketConnectTimeout unless it is zero.
+func (s *session) dialEndpoint(ctx context.Context, dialer proxy.ContextDialer, endpoint int) (net.Conn, error) {
+	if timeout := s.SocketConnectTimeout[endpoint]; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if dial := s.dial.Load(); dial != nil {
+		return (*dial)(ctx)
+	}
+
+	return dialNetwork(ctx, dialer, s.SocketConnectAddress[endpoint])
+}
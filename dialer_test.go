@@ -16,6 +16,7 @@
 package quickfix
 
 import (
+	"context"
 	"net"
 	"testing"
 	"time"
@@ -90,3 +91,50 @@ func (s *DialerTestSuite) TestLoadDialerSocksProxyInvalidPort() {
 	_, err := loadDialerConfig(s.settings.GlobalSettings())
 	s.Require().NotNil(err)
 }
+
+type deadlineDialer struct {
+	deadline time.Time
+	ok       bool
+}
+
+func (d *deadlineDialer) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	d.deadline, d.ok = ctx.Deadline()
+	return nil, ctx.Err()
+}
+
+func (s *DialerTestSuite) TestDialEndpointTimeout() {
+	session := &session{}
+	session.SocketConnectAddress = []string{"127.0.0.1:5000", "127.0.0.1:5001"}
+	session.SocketConnectTimeout = []time.Duration{0, time.Minute}
+
+	dialer := new(deadlineDialer)
+	_, err := session.dialEndpoint(context.Background(), dialer, 0)
+	s.Nil(err)
+	s.False(dialer.ok)
+
+	_, err = session.dialEndpoint(context.Background(), dialer, 1)
+	s.Nil(err)
+	s.True(dialer.ok)
+	s.WithinDuration(time.Now().Add(time.Minute), dialer.deadline, time.Second)
+}
+
+func (s *DialerTestSuite) TestDialEndpointDialFunc() {
+	session := &session{}
+	session.SocketConnectAddress = []string{"127.0.0.1:5000"}
+	session.SocketConnectTimeout = []time.Duration{time.Minute}
+
+	client, server := net.Pipe()
+	defer server.Close()
+	var dial DialFunc = func(ctx context.Context) (net.Conn, error) {
+		_, ok := ctx.Deadline()
+		s.True(ok)
+		return client, nil
+	}
+	session.dial.Store(&dial)
+
+	dialer := new(deadlineDialer)
+	conn, err := session.dialEndpoint(context.Background(), dialer, 0)
+	s.Nil(err)
+	s.Equal(client, conn)
+	s.False(dialer.ok, "the configured dialer is not used")
+}
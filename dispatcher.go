@@ -0,0 +1,148 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// DispatchKeyFunc returns the key of an application message received by a session. Messages with the same key are
+// passed to FromApp in the order they were received.
+type DispatchKeyFunc func(msg *Message, sessionID SessionID) string
+
+// DispatchBySession keys messages by their session, preserving the order of all messages of a session.
+func DispatchBySession(_ *Message, sessionID SessionID) string {
+	return sessionID.String()
+}
+
+// DispatchByField returns a DispatchKeyFunc keying messages by their session and the value of the body field tag,
+// e.g. ClOrdID, preserving the order of the messages of a session with the same value. Messages without the field
+// are keyed by their session.
+func DispatchByField(tag Tag) DispatchKeyFunc {
+	return func(msg *Message, sessionID SessionID) string {
+		value, err := msg.Body.GetString(tag)
+		if err != nil {
+			return sessionID.String()
+		}
+		return sessionID.String() + "|" + value
+	}
+}
+
+type dispatchedMessage struct {
+	session *session
+	msg     *Message
+}
+
+// Dispatcher passes the application messages received by sessions to FromApp on a pool of workers instead of the
+// session goroutine, so that a slow Application does not delay heartbeats and resend processing. Messages are
+// assigned to workers by their key, and each worker handles its messages in order.
+//
+// A dispatched message is accepted when it is queued, and a MessageRejectError returned from FromApp is sent as a
+// reject afterwards. A session waits for its message to be queued if the queue of the worker is full, and FromApp
+// may be called for a queued message after the session disconnected.
+//
+// Set it on a single Acceptor or Initiator with SetDispatcher, which starts and stops the workers.
+type Dispatcher struct {
+	key       DispatchKeyFunc
+	workers   int
+	queueSize int
+
+	queues []chan dispatchedMessage
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher returns a Dispatcher with the number of workers, each queueing up to queueSize messages. If key is
+// nil messages are keyed with DispatchBySession.
+func NewDispatcher(workers, queueSize int, key DispatchKeyFunc) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	if key == nil {
+		key = DispatchBySession
+	}
+
+	return &Dispatcher{key: key, workers: workers, queueSize: queueSize}
+}
+
+func (d *Dispatcher) start() {
+	d.queues = make([]chan dispatchedMessage, d.workers)
+	for i := range d.queues {
+		d.queues[i] = make(chan dispatchedMessage, d.queueSize)
+		d.wg.Add(1)
+		go d.work(d.queues[i])
+	}
+}
+
+// stop waits for the queued messages to be handled and stops the workers.
+func (d *Dispatcher) stop() {
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.wg.Wait()
+	d.queues = nil
+}
+
+func (d *Dispatcher) work(queue <-chan dispatchedMessage) {
+	defer d.wg.Done()
+
+	for dispatched := range queue {
+		if reject := dispatched.session.deliverApp(dispatched.msg); reject != nil {
+			dispatched.session.rejectDispatched(dispatched.msg, reject)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(s *session, msg *Message) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(d.key(msg, s.sessionID)))
+	d.queues[h.Sum32()%uint32(len(d.queues))] <- dispatchedMessage{session: s, msg: msg}
+}
+
+type dispatchedReject struct {
+	msg    *Message
+	reject MessageRejectError
+}
+
+// rejectDispatched queues a reject of a dispatched message, to be sent by the session goroutine.
+func (s *session) rejectDispatched(msg *Message, reject MessageRejectError) {
+	s.dispatchedRejectsMu.Lock()
+	s.dispatchedRejects = append(s.dispatchedRejects, dispatchedReject{msg: msg, reject: reject})
+	s.dispatchedRejectsMu.Unlock()
+
+	s.notifyMessageOut()
+}
+
+// sendDispatchedRejects sends the queued rejects of dispatched messages while logged on, dropping them otherwise.
+func (s *session) sendDispatchedRejects() {
+	s.dispatchedRejectsMu.Lock()
+	rejects := s.dispatchedRejects
+	s.dispatchedRejects = nil
+	s.dispatchedRejectsMu.Unlock()
+
+	for _, r := range rejects {
+		if !s.IsLoggedOn() {
+			s.log.OnEventf("Dropped reject of dispatched message: %v", r.reject.Error())
+			continue
+		}
+		if err := s.doReject(r.msg, r.reject); err != nil {
+			s.logError(err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// dispatchApp records the ClOrdIDs of the messages passed to FromApp by key.
+type dispatchApp struct {
+	*MockApp
+	mu     sync.Mutex
+	orders map[string][]int
+	block  chan struct{}
+	reject MessageRejectError
+}
+
+func (a *dispatchApp) FromApp(msg *Message, _ SessionID) MessageRejectError {
+	if a.block != nil {
+		<-a.block
+	}
+
+	account, _ := msg.Body.GetString(Tag(1))
+	clOrdID, _ := msg.Body.GetInt(Tag(11))
+	a.mu.Lock()
+	a.orders[account] = append(a.orders[account], clOrdID)
+	a.mu.Unlock()
+	return a.reject
+}
+
+type DispatcherTestSuite struct {
+	SessionSuiteRig
+	app        *dispatchApp
+	dispatcher *Dispatcher
+}
+
+func TestDispatcherTestSuite(t *testing.T) {
+	suite.Run(t, new(DispatcherTestSuite))
+}
+
+func (s *DispatcherTestSuite) SetupTest() {
+	s.Init()
+	s.app = &dispatchApp{MockApp: &s.MockApp, orders: make(map[string][]int)}
+	s.session.application = s.app
+	s.session.messageEvent = make(chan bool, 1)
+	s.session.State = inSession{}
+	s.dispatcher = NewDispatcher(4, 16, DispatchByField(Tag(1)))
+	s.dispatcher.start()
+	s.session.dispatcher = s.dispatcher
+}
+
+func (s *DispatcherTestSuite) TearDownTest() {
+	s.dispatcher.stop()
+}
+
+func (s *DispatcherTestSuite) order(account string, clOrdID int) []byte {
+	msg := s.NewOrderSingle()
+	msg.Body.SetField(Tag(1), FIXString(account))
+	msg.Body.SetField(Tag(11), FIXString(strconv.Itoa(clOrdID)))
+	return msg.build()
+}
+
+func (s *DispatcherTestSuite) TestOrderPerKey() {
+	accounts := []string{"A", "B", "C", "D", "E"}
+	for i := 1; i <= 20; i++ {
+		for _, account := range accounts {
+			s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(s.order(account, i))})
+		}
+	}
+	s.dispatcher.stop()
+
+	s.NextTargetMsgSeqNum(101)
+	for _, account := range accounts {
+		s.Len(s.app.orders[account], 20, account)
+		for i, clOrdID := range s.app.orders[account] {
+			s.Equal(i+1, clOrdID, account)
+		}
+	}
+}
+
+func (s *DispatcherTestSuite) TestDoesNotBlockSession() {
+	s.app.block = make(chan struct{})
+
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(s.order("A", 1))})
+	s.NextTargetMsgSeqNum(2)
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+	testRequest := s.buildMessage(string(msgTypeTestRequest))
+	testRequest.Body.SetField(tagTestReqID, FIXString("ping"))
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(testRequest.build())})
+	s.MockApp.AssertExpectations(s.T())
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeHeartbeat), s.MockApp.lastToAdmin)
+
+	close(s.app.block)
+	s.dispatcher.stop()
+	s.Equal([]int{1}, s.app.orders["A"])
+}
+
+func (s *DispatcherTestSuite) TestReject() {
+	s.app.reject = ConditionallyRequiredFieldMissing(Tag(11))
+
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(s.order("A", 1))})
+	s.dispatcher.stop()
+	s.NoMessageSent()
+	s.Len(s.session.messageEvent, 1)
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.session.sendDispatchedRejects()
+	s.MockApp.AssertExpectations(s.T())
+	s.LastToAppMessageSent()
+	s.MessageType(string(msgTypeBusinessMessageReject), s.MockApp.lastToApp)
+	s.FieldEquals(tagRefSeqNum, 1, s.MockApp.lastToApp.Body)
+}
@@ -0,0 +1,132 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+// DuplicatePolicy determines what happens to inbound application messages detected as duplicates.
+type DuplicatePolicy int
+
+const (
+	// DuplicateFlag delivers duplicates to FromAppDuplicate if the Application is a DuplicateApplication,
+	// otherwise to FromApp.
+	DuplicateFlag DuplicatePolicy = iota
+
+	// DuplicateSuppress drops duplicates without passing them to the Application.
+	DuplicateSuppress
+)
+
+// DuplicateKeyExtractor may be implemented by an Application to choose the key used to detect duplicate
+// inbound application messages. By default messages are keyed on MsgType and ExecID, or ClOrdID if the
+// message has no ExecID.
+type DuplicateKeyExtractor interface {
+	// DuplicateKey returns the key identifying msg, or false if msg should not be checked for duplicates.
+	DuplicateKey(msg *Message) (key string, ok bool)
+}
+
+// DuplicateApplication may be implemented by an Application to receive duplicate application messages
+// separately when DuplicatePolicy is DuplicateFlag.
+type DuplicateApplication interface {
+	// FromAppDuplicate is called instead of FromApp for an application message with the same key as one
+	// of the messages recently received from the counterparty.
+	FromAppDuplicate(message *Message, sessionID SessionID) MessageRejectError
+}
+
+// duplicateWindow remembers the keys of the most recently received application messages.
+type duplicateWindow struct {
+	policy DuplicatePolicy
+	keys   map[string]struct{}
+
+	// order is a ring buffer of the remembered keys, oldest at next once full.
+	order []string
+	next  int
+}
+
+func newDuplicateWindow(size int, policy DuplicatePolicy) *duplicateWindow {
+	return &duplicateWindow{
+		policy: policy,
+		keys:   make(map[string]struct{}, size),
+		order:  make([]string, 0, size),
+	}
+}
+
+func (w *duplicateWindow) contains(key string) bool {
+	_, ok := w.keys[key]
+	return ok
+}
+
+func (w *duplicateWindow) add(key string) {
+	if w.contains(key) {
+		return
+	}
+
+	if len(w.order) < cap(w.order) {
+		w.order = append(w.order, key)
+	} else {
+		delete(w.keys, w.order[w.next])
+		w.order[w.next] = key
+		w.next = (w.next + 1) % len(w.order)
+	}
+	w.keys[key] = struct{}{}
+}
+
+func defaultDuplicateKey(msg *Message, msgType []byte) (string, bool) {
+	for _, tag := range []Tag{tagExecID, tagClOrdID} {
+		if id, err := msg.Body.GetBytes(tag); err == nil {
+			return string(msgType) + "|" + string(id), true
+		}
+	}
+
+	return "", false
+}
+
+func (s *session) duplicateKey(msg *Message, msgType []byte) (string, bool) {
+	if extractor, ok := s.application.(DuplicateKeyExtractor); ok {
+		return extractor.DuplicateKey(msg)
+	}
+
+	return defaultDuplicateKey(msg, msgType)
+}
+
+// fromAppChecked passes an application message to the Application, first checking it against the duplicate window.
+func (s *session) fromAppChecked(msg *Message, msgType []byte) MessageRejectError {
+	if s.duplicates == nil {
+		return s.fromApp(msg)
+	}
+
+	key, ok := s.duplicateKey(msg, msgType)
+	if !ok {
+		return s.fromApp(msg)
+	}
+
+	if !s.duplicates.contains(key) {
+		err := s.fromApp(msg)
+		if err == nil {
+			s.duplicates.add(key)
+		}
+		return err
+	}
+
+	if s.duplicates.policy == DuplicateSuppress {
+		s.log.OnEventf("Suppressed duplicate message %v", key)
+		return nil
+	}
+
+	s.log.OnEventf("Received duplicate message %v", key)
+	if app, ok := s.application.(DuplicateApplication); ok {
+		return app.FromAppDuplicate(msg, s.sessionID)
+	}
+
+	return s.fromApp(msg)
+}
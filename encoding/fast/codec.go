@@ -0,0 +1,684 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fast
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// templateIDKey is the key of the previous template id in the dictionary, which no field key can be equal to.
+const templateIDKey = "\x00templateID"
+
+// entry is the previous value of a field. An entry that is not in the dictionary is undefined.
+type entry struct {
+	empty bool
+	value Value
+}
+
+// dictionary holds the previous values of the fields of a stream.
+type dictionary map[string]entry
+
+// base returns the base value of the delta operator of i.
+func (d dictionary) base(i *Instruction) (Value, error) {
+	e, ok := d[i.Key]
+	switch {
+	case !ok && i.Initial != nil:
+		return *i.Initial, nil
+	case !ok:
+		return Value{}, nil
+	case e.empty:
+		return Value{}, fmt.Errorf("%v: delta of an empty previous value", i.Name)
+	}
+
+	return e.value, nil
+}
+
+// implicit returns the value of a copy or increment operator of i not present in the stream, and false if the value
+// is absent.
+func (d dictionary) implicit(i *Instruction) (Value, bool, error) {
+	e, ok := d[i.Key]
+	switch {
+	case !ok && i.Initial != nil:
+		return *i.Initial, true, nil
+	case !ok || e.empty:
+		if !i.Optional {
+			return Value{}, false, fmt.Errorf("%v: mandatory field without previous value", i.Name)
+		}
+		return Value{}, false, nil
+	case i.Operator == OpIncrement:
+		return increment(i, e.value)
+	}
+
+	return e.value, true, nil
+}
+
+func (d dictionary) set(i *Instruction, v Value, present bool) {
+	d[i.Key] = entry{empty: !present, value: v}
+}
+
+func increment(i *Instruction, v Value) (Value, bool, error) {
+	switch i.Kind {
+	case KindUInt32, KindUInt64:
+		v.Uint++
+	default:
+		v.Int++
+	}
+
+	if err := checkRange(i, v); err != nil {
+		return Value{}, false, err
+	}
+
+	return v, true, nil
+}
+
+// checkRange checks that the value of a 32 bit integer or the exponent of a decimal is in range.
+func checkRange(i *Instruction, v Value) error {
+	switch {
+	case i.Kind == KindUInt32 && v.Uint > math.MaxUint32,
+		i.Kind == KindInt32 && (v.Int < math.MinInt32 || v.Int > math.MaxInt32),
+		i.Kind == KindDecimal && (v.Exponent < -63 || v.Exponent > 63):
+		return fmt.Errorf("%v: %w", i.Name, errOverflow)
+	}
+
+	return nil
+}
+
+// needsPMap returns true if a segment of the instructions has a presence map.
+func needsPMap(instructions []*Instruction) bool {
+	for _, i := range instructions {
+		if i.usesPMap() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GroupTemplate returns the GroupTemplate of the elements of a sequence, with the fields of groups in the sequence
+// flattened into it.
+func GroupTemplate(sequence *Instruction) quickfix.GroupTemplate {
+	return appendGroupItems(nil, sequence.Instructions)
+}
+
+func appendGroupItems(template quickfix.GroupTemplate, instructions []*Instruction) quickfix.GroupTemplate {
+	for _, i := range instructions {
+		switch {
+		case i.Kind == KindGroup:
+			template = appendGroupItems(template, i.Instructions)
+		case i.Kind == KindSequence && i.Length.ID > 0:
+			template = append(template, quickfix.NewRepeatingGroup(quickfix.Tag(i.Length.ID), GroupTemplate(i)))
+		case i.Kind != KindSequence && i.ID > 0:
+			template = append(template, quickfix.GroupElement(quickfix.Tag(i.ID)))
+		}
+	}
+
+	return template
+}
+
+// Decoder decodes the messages of a FAST stream, keeping the previous values of fields across messages.
+type Decoder struct {
+	templates *Templates
+	dict      dictionary
+}
+
+// NewDecoder returns a Decoder of messages of templates.
+func NewDecoder(templates *Templates) *Decoder {
+	return &Decoder{templates: templates, dict: make(dictionary)}
+}
+
+// Reset resets the previous values of all fields, as done by a FAST reset message.
+func (d *Decoder) Reset() {
+	d.dict = make(dictionary)
+}
+
+// Decode decodes the FAST message at the start of data into msg, returning the number of bytes of the message.
+// Fields are set by their ids as tags, the fields of the FIX standard header in the Header of msg. Sequences are set
+// as repeating groups with the id of their length field as the NumInGroup tag, and the fields of groups are set in
+// the enclosing message or sequence.
+func (d *Decoder) Decode(data []byte, msg *quickfix.Message) (int, error) {
+	r := &reader{data: data}
+	pm, err := r.pmap()
+	if err != nil {
+		return 0, err
+	}
+
+	tid := &Instruction{Kind: KindUInt32, Name: "TemplateID", Operator: OpCopy, Key: templateIDKey}
+	v, _, err := d.field(r, pm, tid)
+	if err != nil {
+		return 0, fmt.Errorf("fast: %w", err)
+	}
+
+	tmpl, ok := d.templates.Template(uint32(v.Uint))
+	if !ok {
+		return 0, fmt.Errorf("fast: unknown template id %v", v.Uint)
+	}
+
+	msg.Header.Clear()
+	msg.Body.Clear()
+	msg.Trailer.Clear()
+	if err := d.instructions(r, pm, tmpl.Instructions, &msg.Body.FieldMap, msg); err != nil {
+		return 0, fmt.Errorf("fast: %v: %w", tmpl.Name, err)
+	}
+
+	return len(data) - len(r.data), nil
+}
+
+// instructions decodes instructions into fieldMap, or the header fields of msg into its Header if not nil.
+func (d *Decoder) instructions(
+	r *reader, pm *pmap, instructions []*Instruction, fieldMap *quickfix.FieldMap, msg *quickfix.Message,
+) error {
+	for _, i := range instructions {
+		switch i.Kind {
+		case KindSequence:
+			length, present, err := d.field(r, pm, i.Length)
+			if err != nil {
+				return err
+			}
+			if !present {
+				continue
+			}
+			// Each element starts with a presence map of at least one byte.
+			if needsPMap(i.Instructions) && length.Uint > uint64(len(r.data)) {
+				return fmt.Errorf("%v: %w", i.Name, ErrShortBuffer)
+			}
+
+			target := fieldMap
+			if i.Length.ID == 0 {
+				// Sequences without a NumInGroup tag are decoded but not set.
+				target = &quickfix.NewMessage().Body.FieldMap
+			}
+			rg, rejErr := target.RepeatingGroup(quickfix.Tag(i.Length.ID), GroupTemplate(i))
+			if rejErr != nil {
+				return fmt.Errorf("%v: %w", i.Name, rejErr)
+			}
+
+			for n := uint64(0); n < length.Uint; n++ {
+				elementPMap, err := d.segmentPMap(r, i.Instructions)
+				if err != nil {
+					return fmt.Errorf("%v: %w", i.Name, err)
+				}
+				if err := d.instructions(r, elementPMap, i.Instructions, &rg.Add().FieldMap, nil); err != nil {
+					return fmt.Errorf("%v: %w", i.Name, err)
+				}
+			}
+
+		case KindGroup:
+			if i.Optional && !pm.next() {
+				continue
+			}
+
+			groupPMap, err := d.segmentPMap(r, i.Instructions)
+			if err != nil {
+				return fmt.Errorf("%v: %w", i.Name, err)
+			}
+			if err := d.instructions(r, groupPMap, i.Instructions, fieldMap, msg); err != nil {
+				return fmt.Errorf("%v: %w", i.Name, err)
+			}
+
+		default:
+			v, present, err := d.field(r, pm, i)
+			if err != nil {
+				return err
+			}
+			if !present || i.ID == 0 {
+				continue
+			}
+
+			target := fieldMap
+			if msg != nil && quickfix.Tag(i.ID).IsHeader() {
+				target = &msg.Header.FieldMap
+			}
+			target.SetString(quickfix.Tag(i.ID), v.format(i.Kind))
+		}
+	}
+
+	return nil
+}
+
+func (d *Decoder) segmentPMap(r *reader, instructions []*Instruction) (*pmap, error) {
+	if !needsPMap(instructions) {
+		return &pmap{}, nil
+	}
+
+	return r.pmap()
+}
+
+// field decodes the value of a field by its operator, returning false if the value is absent.
+func (d *Decoder) field(r *reader, pm *pmap, i *Instruction) (Value, bool, error) {
+	switch i.Operator {
+	case OpConstant:
+		if !i.Optional || pm.next() {
+			return *i.Initial, true, nil
+		}
+		return Value{}, false, nil
+
+	case OpDefault:
+		if pm.next() {
+			return d.read(r, i)
+		}
+		if i.Initial != nil {
+			return *i.Initial, true, nil
+		}
+		if !i.Optional {
+			return Value{}, false, fmt.Errorf("%v: mandatory field without default value", i.Name)
+		}
+		return Value{}, false, nil
+
+	case OpCopy, OpIncrement:
+		var v Value
+		var present bool
+		var err error
+		if pm.next() {
+			v, present, err = d.read(r, i)
+		} else {
+			v, present, err = d.dict.implicit(i)
+		}
+		if err != nil {
+			return Value{}, false, err
+		}
+
+		d.dict.set(i, v, present)
+		return v, present, nil
+
+	case OpDelta:
+		return d.delta(r, i)
+	}
+
+	return d.read(r, i)
+}
+
+// read reads a value of i from the stream.
+func (d *Decoder) read(r *reader, i *Instruction) (v Value, present bool, err error) {
+	nullable := i.nullable()
+	var null bool
+	switch i.Kind {
+	case KindUInt32, KindUInt64:
+		v.Uint, null, err = r.uint(nullable)
+	case KindInt32, KindInt64:
+		v.Int, null, err = r.int(nullable)
+	case KindDecimal:
+		var exponent int64
+		if exponent, null, err = r.int(nullable); err == nil && !null {
+			v.Exponent = int32(exponent)
+			if exponent < -63 || exponent > 63 {
+				err = errOverflow
+			} else {
+				v.Int, _, err = r.int(false)
+			}
+		}
+	case KindASCII:
+		v.Bytes, null, err = r.ascii(nullable)
+	default:
+		v.Bytes, null, err = r.byteVector(nullable)
+	}
+
+	if err == nil && !null {
+		err = checkRange(i, v)
+	}
+	if err != nil {
+		return Value{}, false, fmt.Errorf("%v: %w", i.Name, err)
+	}
+
+	return v, !null, nil
+}
+
+func (d *Decoder) delta(r *reader, i *Instruction) (Value, bool, error) {
+	v, err := d.dict.base(i)
+	if err != nil {
+		return Value{}, false, err
+	}
+
+	switch i.Kind {
+	case KindUInt32, KindUInt64, KindInt32, KindInt64:
+		delta, null, err := r.int(i.nullable())
+		if err != nil || null {
+			return Value{}, false, wrapErr(i, err)
+		}
+		if i.Kind == KindUInt32 || i.Kind == KindUInt64 {
+			v.Uint += uint64(delta)
+		} else {
+			v.Int += delta
+		}
+
+	case KindDecimal:
+		exponent, null, err := r.int(i.nullable())
+		if err != nil || null {
+			return Value{}, false, wrapErr(i, err)
+		}
+		mantissa, _, err := r.int(false)
+		if err != nil {
+			return Value{}, false, wrapErr(i, err)
+		}
+		v.Exponent += int32(exponent)
+		v.Int += mantissa
+
+	default:
+		subtraction, null, err := r.int(i.nullable())
+		if err != nil || null {
+			return Value{}, false, wrapErr(i, err)
+		}
+
+		var diff []byte
+		if i.Kind == KindASCII {
+			diff, _, err = r.ascii(false)
+		} else {
+			diff, _, err = r.byteVector(false)
+		}
+		if err != nil {
+			return Value{}, false, wrapErr(i, err)
+		}
+
+		// A negative subtraction length removes from the front of the base value.
+		front := subtraction < 0
+		if front {
+			subtraction = -subtraction - 1
+		}
+		if subtraction > int64(len(v.Bytes)) {
+			return Value{}, false, fmt.Errorf("%v: subtraction length %v of a value of length %v", i.Name,
+				subtraction, len(v.Bytes))
+		}
+
+		if front {
+			v.Bytes = append(diff, v.Bytes[subtraction:]...)
+		} else {
+			v.Bytes = append(append([]byte(nil), v.Bytes[:int64(len(v.Bytes))-subtraction]...), diff...)
+		}
+	}
+
+	if err := checkRange(i, v); err != nil {
+		return Value{}, false, err
+	}
+
+	d.dict.set(i, v, true)
+	return v, true, nil
+}
+
+func wrapErr(i *Instruction, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%v: %w", i.Name, err)
+}
+
+// Encoder encodes the messages of a FAST stream, keeping the previous values of fields across messages.
+type Encoder struct {
+	templates *Templates
+	dict      dictionary
+}
+
+// NewEncoder returns an Encoder of messages of templates.
+func NewEncoder(templates *Templates) *Encoder {
+	return &Encoder{templates: templates, dict: make(dictionary)}
+}
+
+// Reset resets the previous values of all fields, as done by a FAST reset message.
+func (e *Encoder) Reset() {
+	e.dict = make(dictionary)
+}
+
+// Encode encodes msg as a message of the template with templateID, reading fields as set by Decoder.Decode.
+func (e *Encoder) Encode(templateID uint32, msg *quickfix.Message) ([]byte, error) {
+	tmpl, ok := e.templates.Template(templateID)
+	if !ok {
+		return nil, fmt.Errorf("fast: unknown template id %v", templateID)
+	}
+
+	pm := &pmap{}
+	tid := &Instruction{Kind: KindUInt32, Name: "TemplateID", Operator: OpCopy, Key: templateIDKey}
+	body, err := e.field(nil, pm, tid, Value{Uint: uint64(templateID)}, true)
+	if err != nil {
+		return nil, fmt.Errorf("fast: %w", err)
+	}
+
+	if body, err = e.instructions(body, pm, tmpl.Instructions, &msg.Body.FieldMap, msg); err != nil {
+		return nil, fmt.Errorf("fast: %v: %w", tmpl.Name, err)
+	}
+
+	return append(pm.appendTo(nil), body...), nil
+}
+
+// instructions appends instructions encoded from fieldMap, or the header fields of msg from its Header if not nil.
+func (e *Encoder) instructions(
+	b []byte, pm *pmap, instructions []*Instruction, fieldMap *quickfix.FieldMap, msg *quickfix.Message,
+) ([]byte, error) {
+	var err error
+	for _, i := range instructions {
+		switch i.Kind {
+		case KindSequence:
+			var rg *quickfix.RepeatingGroup
+			present := false
+			if i.Length.ID > 0 {
+				var rejErr quickfix.MessageRejectError
+				if rg, rejErr = fieldMap.RepeatingGroup(quickfix.Tag(i.Length.ID), GroupTemplate(i)); rejErr != nil {
+					return nil, fmt.Errorf("%v: %w", i.Name, rejErr)
+				}
+				present = rg.Len() > 0 || fieldMap.Has(quickfix.Tag(i.Length.ID))
+			}
+			if !present && !i.Optional {
+				present, rg = true, quickfix.NewRepeatingGroup(0, nil)
+			}
+
+			length := Value{}
+			if rg != nil {
+				length.Uint = uint64(rg.Len())
+			}
+			if b, err = e.field(b, pm, i.Length, length, present); err != nil {
+				return nil, err
+			}
+			if !present {
+				continue
+			}
+
+			for n := 0; n < rg.Len(); n++ {
+				if b, err = e.segment(b, i.Instructions, &rg.Get(n).FieldMap, nil); err != nil {
+					return nil, fmt.Errorf("%v: %w", i.Name, err)
+				}
+			}
+
+		case KindGroup:
+			if i.Optional {
+				present := hasFields(i.Instructions, fieldMap, msg)
+				pm.set(present)
+				if !present {
+					continue
+				}
+			}
+
+			if b, err = e.segment(b, i.Instructions, fieldMap, msg); err != nil {
+				return nil, fmt.Errorf("%v: %w", i.Name, err)
+			}
+
+		default:
+			source := fieldMap
+			if msg != nil && quickfix.Tag(i.ID).IsHeader() {
+				source = &msg.Header.FieldMap
+			}
+
+			var v Value
+			present := i.ID > 0 && source.Has(quickfix.Tag(i.ID))
+			if present {
+				s, rejErr := source.GetString(quickfix.Tag(i.ID))
+				if rejErr != nil {
+					return nil, fmt.Errorf("%v: %w", i.Name, rejErr)
+				}
+				if v, err = parseValue(i.Kind, s); err != nil {
+					return nil, fmt.Errorf("%v: invalid value %q: %w", i.Name, s, err)
+				}
+				if err = checkRange(i, v); err != nil {
+					return nil, err
+				}
+			}
+
+			if b, err = e.field(b, pm, i, v, present); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// segment appends a sequence element or group with its own presence map.
+func (e *Encoder) segment(b []byte, instructions []*Instruction, fieldMap *quickfix.FieldMap, msg *quickfix.Message) (
+	[]byte, error,
+) {
+	pm := &pmap{}
+	body, err := e.instructions(nil, pm, instructions, fieldMap, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if needsPMap(instructions) {
+		b = pm.appendTo(b)
+	}
+	return append(b, body...), nil
+}
+
+// hasFields returns true if any field of instructions is set.
+func hasFields(instructions []*Instruction, fieldMap *quickfix.FieldMap, msg *quickfix.Message) bool {
+	for _, i := range instructions {
+		switch {
+		case i.Kind == KindGroup:
+			if hasFields(i.Instructions, fieldMap, msg) {
+				return true
+			}
+		case i.Kind == KindSequence:
+			if i.Length.ID > 0 && fieldMap.Has(quickfix.Tag(i.Length.ID)) {
+				return true
+			}
+		case i.ID > 0:
+			if fieldMap.Has(quickfix.Tag(i.ID)) || (msg != nil && msg.Header.Has(quickfix.Tag(i.ID))) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// field appends the value of a field by its operator, setting its bit of the presence map.
+func (e *Encoder) field(b []byte, pm *pmap, i *Instruction, v Value, present bool) ([]byte, error) {
+	if !present && !i.Optional && i.Operator != OpConstant {
+		return nil, fmt.Errorf("%v: required field missing", i.Name)
+	}
+
+	switch i.Operator {
+	case OpConstant:
+		if i.Optional {
+			pm.set(present)
+		}
+		return b, nil
+
+	case OpDefault:
+		if (present && i.Initial != nil && v.equal(*i.Initial)) || (!present && i.Initial == nil) {
+			pm.set(false)
+			return b, nil
+		}
+
+	case OpCopy, OpIncrement:
+		implicit, implicitPresent, err := e.dict.implicit(i)
+		e.dict.set(i, v, present)
+		if err == nil && present == implicitPresent && (!present || v.equal(implicit)) {
+			pm.set(false)
+			return b, nil
+		}
+
+	case OpDelta:
+		return e.delta(b, i, v, present)
+	}
+
+	if i.Operator != OpNone {
+		pm.set(true)
+	}
+	return e.write(b, i, v, !present)
+}
+
+func (e *Encoder) write(b []byte, i *Instruction, v Value, null bool) ([]byte, error) {
+	nullable := i.nullable()
+	switch i.Kind {
+	case KindUInt32, KindUInt64:
+		return appendUint(b, v.Uint, nullable, null), nil
+	case KindInt32, KindInt64:
+		return appendInt(b, v.Int, nullable, null), nil
+	case KindDecimal:
+		b = appendInt(b, int64(v.Exponent), nullable, null)
+		if null {
+			return b, nil
+		}
+		return appendInt(b, v.Int, false, false), nil
+	case KindASCII:
+		b, err := appendASCII(b, v.Bytes, nullable, null)
+		return b, wrapErr(i, err)
+	}
+
+	return appendByteVector(b, v.Bytes, nullable, null), nil
+}
+
+func (e *Encoder) delta(b []byte, i *Instruction, v Value, present bool) ([]byte, error) {
+	if !present {
+		return appendInt(b, 0, true, true), nil
+	}
+
+	base, err := e.dict.base(i)
+	if err != nil {
+		return nil, err
+	}
+	e.dict.set(i, v, true)
+
+	nullable := i.nullable()
+	switch i.Kind {
+	case KindUInt32, KindUInt64:
+		return appendInt(b, int64(v.Uint-base.Uint), nullable, false), nil
+	case KindInt32, KindInt64:
+		return appendInt(b, v.Int-base.Int, nullable, false), nil
+	case KindDecimal:
+		b = appendInt(b, int64(v.Exponent-base.Exponent), nullable, false)
+		return appendInt(b, v.Int-base.Int, false, false), nil
+	}
+
+	subtraction, diff := stringDelta(base.Bytes, v.Bytes)
+	b = appendInt(b, subtraction, nullable, false)
+	if i.Kind == KindASCII {
+		b, err = appendASCII(b, diff, false, false)
+		return b, wrapErr(i, err)
+	}
+
+	return appendByteVector(b, diff, false, false), nil
+}
+
+// stringDelta returns the subtraction length and difference of the shorter of the deltas from base to v removing from
+// the back or the front of base.
+func stringDelta(base, v []byte) (int64, []byte) {
+	prefix := 0
+	for prefix < len(base) && prefix < len(v) && base[prefix] == v[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(base) && suffix < len(v) && base[len(base)-1-suffix] == v[len(v)-1-suffix] {
+		suffix++
+	}
+
+	if suffix > prefix {
+		return -int64(len(base)-suffix) - 1, v[:len(v)-suffix]
+	}
+
+	return int64(len(base) - prefix), v[prefix:]
+}
@@ -0,0 +1,173 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fast
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func parseTestTemplates(t *testing.T) *Templates {
+	templates, err := ParseTemplatesFile("testdata/templates.xml")
+	require.Nil(t, err)
+
+	return templates
+}
+
+type mdEntry struct {
+	entryType, symbol, px, size, condition string
+}
+
+func mdIncRefresh(seqNum int, sendingTime string, entries ...mdEntry) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(35), "X").
+		SetInt(quickfix.Tag(34), seqNum).
+		SetString(quickfix.Tag(52), sendingTime)
+
+	group := quickfix.NewRepeatingGroup(quickfix.Tag(268), quickfix.GroupTemplate{
+		quickfix.GroupElement(quickfix.Tag(279)), quickfix.GroupElement(quickfix.Tag(269)),
+		quickfix.GroupElement(quickfix.Tag(55)), quickfix.GroupElement(quickfix.Tag(270)),
+		quickfix.GroupElement(quickfix.Tag(271)), quickfix.GroupElement(quickfix.Tag(277)),
+	})
+	for _, e := range entries {
+		g := group.Add().SetInt(quickfix.Tag(279), 1).
+			SetString(quickfix.Tag(269), e.entryType).
+			SetString(quickfix.Tag(55), e.symbol)
+		if e.px != "" {
+			g.SetString(quickfix.Tag(270), e.px)
+		}
+		if e.size != "" {
+			g.SetString(quickfix.Tag(271), e.size)
+		}
+		if e.condition != "" {
+			g.SetString(quickfix.Tag(277), e.condition)
+		}
+	}
+	msg.Body.SetGroup(group)
+
+	return msg
+}
+
+// fields returns the fields of msg after BodyLength and before CheckSum, separated by |.
+func fields(msg *quickfix.Message) string {
+	s := strings.ReplaceAll(msg.String(), "\x01", "|")
+	s = s[strings.Index(s, "|35=")+1:]
+	return s[:strings.Index(s, "10=")]
+}
+
+func TestEncodeDecode(t *testing.T) {
+	templates := parseTestTemplates(t)
+	encoder, decoder := NewEncoder(templates), NewDecoder(templates)
+
+	messages := []*quickfix.Message{
+		mdIncRefresh(1, "20240101120000000",
+			mdEntry{entryType: "0", symbol: "ESZ4", px: "5000.25", size: "10"},
+			mdEntry{entryType: "1", symbol: "ESZ4", px: "5000.5", size: "-3", condition: "R"},
+		),
+		mdIncRefresh(2, "20240101120000005",
+			mdEntry{entryType: "0", symbol: "ESZ4", px: "5000.75"},
+		),
+		mdIncRefresh(3, "20240101120000005"),
+	}
+	expected := []string{
+		"35=X|34=1|52=20240101120000000|268=2|279=1|269=0|55=ESZ4|270=5000.25|271=10|" +
+			"279=1|269=1|55=ESZ4|270=5000.5|271=-3|277=R|",
+		"35=X|34=2|52=20240101120000005|268=1|279=1|269=0|55=ESZ4|270=5000.75|",
+		"35=X|34=3|52=20240101120000005|",
+	}
+
+	var stream []byte
+	var sizes []int
+	for _, msg := range messages {
+		encoded, err := encoder.Encode(1, msg)
+		require.Nil(t, err)
+		stream = append(stream, encoded...)
+		sizes = append(sizes, len(encoded))
+	}
+
+	// The second message copies the template id and symbol, increments MsgSeqNum and encodes deltas.
+	assert.Less(t, sizes[1], sizes[0]/2)
+
+	for i, size := range sizes {
+		msg := quickfix.NewMessage()
+		n, err := decoder.Decode(stream, msg)
+		require.Nil(t, err)
+		assert.Equal(t, size, n)
+		stream = stream[n:]
+
+		assert.Equal(t, expected[i], fields(msg))
+	}
+	assert.Empty(t, stream)
+}
+
+func TestDecode(t *testing.T) {
+	decoder := NewDecoder(parseTestTemplates(t))
+
+	// PMap with the template id bit, template id 2 and MsgSeqNum 5.
+	msg := quickfix.NewMessage()
+	n, err := decoder.Decode([]byte{0xc0, 0x82, 0x85, 0xff}, msg)
+	require.Nil(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "35=0|34=5|", fields(msg))
+
+	// The template id is copied from the previous message.
+	n, err = decoder.Decode([]byte{0x80, 0x86}, msg)
+	require.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "35=0|34=6|", fields(msg))
+
+	decoder.Reset()
+	_, err = decoder.Decode([]byte{0x80, 0x86}, msg)
+	assert.NotNil(t, err)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	templates := parseTestTemplates(t)
+	encoded, err := NewEncoder(templates).Encode(1, mdIncRefresh(1, "20240101120000000",
+		mdEntry{entryType: "0", symbol: "ESZ4", px: "5000.25"}))
+	require.Nil(t, err)
+
+	for n := 0; n < len(encoded); n++ {
+		_, err := NewDecoder(templates).Decode(encoded[:n], quickfix.NewMessage())
+		assert.NotNil(t, err, n)
+	}
+
+	_, err = NewDecoder(templates).Decode([]byte{0xc0, 0x89}, quickfix.NewMessage())
+	assert.NotNil(t, err)
+}
+
+func TestEncodeErrors(t *testing.T) {
+	templates := parseTestTemplates(t)
+
+	_, err := NewEncoder(templates).Encode(9, mdIncRefresh(1, "1"))
+	assert.NotNil(t, err)
+
+	msg := mdIncRefresh(1, "1")
+	msg.Header.Remove(quickfix.Tag(52))
+	_, err = NewEncoder(templates).Encode(1, msg)
+	assert.NotNil(t, err)
+
+	_, err = NewEncoder(templates).Encode(1, mdIncRefresh(1, "-1"))
+	assert.NotNil(t, err)
+
+	_, err = NewEncoder(templates).Encode(1, mdIncRefresh(1, "1", mdEntry{entryType: "0", symbol: "É"}))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,263 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fast
+
+import (
+	"errors"
+	"fmt"
+)
+
+const stopBit = 0x80
+
+// ErrShortBuffer is returned when decoding a message that is not completely in the buffer.
+var ErrShortBuffer = errors.New("fast: short buffer")
+
+// errOverflow is returned for an integer too large for its type.
+var errOverflow = errors.New("integer overflow")
+
+// reader reads the stop bit encoded entities of a message.
+type reader struct {
+	data []byte
+}
+
+// entity returns the bytes of the next stop bit encoded entity, with the stop bit of the last byte set.
+func (r *reader) entity() ([]byte, error) {
+	for i, b := range r.data {
+		if b&stopBit != 0 {
+			e := r.data[:i+1]
+			r.data = r.data[i+1:]
+			return e, nil
+		}
+	}
+
+	return nil, ErrShortBuffer
+}
+
+func (r *reader) uint(nullable bool) (v uint64, null bool, err error) {
+	e, err := r.entity()
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, b := range e {
+		if v > (1<<64-1)>>7 {
+			return 0, false, errOverflow
+		}
+		v = v<<7 | uint64(b&^stopBit)
+	}
+
+	if nullable {
+		if v == 0 {
+			return 0, true, nil
+		}
+		v--
+	}
+
+	return v, false, nil
+}
+
+func (r *reader) int(nullable bool) (v int64, null bool, err error) {
+	e, err := r.entity()
+	if err != nil {
+		return 0, false, err
+	}
+	if len(e) > 10 {
+		return 0, false, errOverflow
+	}
+
+	// The sign is the most significant data bit of the first byte.
+	if e[0]&0x40 != 0 {
+		v = -1
+	}
+	for _, b := range e {
+		v = v<<7 | int64(b&^stopBit)
+	}
+
+	if nullable {
+		switch {
+		case v == 0:
+			return 0, true, nil
+		case v > 0:
+			v--
+		}
+	}
+
+	return v, false, nil
+}
+
+func (r *reader) ascii(nullable bool) (v []byte, null bool, err error) {
+	e, err := r.entity()
+	if err != nil {
+		return nil, false, err
+	}
+
+	v = append([]byte(nil), e...)
+	v[len(v)-1] &^= stopBit
+
+	switch {
+	case nullable && len(v) == 1 && v[0] == 0:
+		return nil, true, nil
+	case nullable && len(v) == 2 && v[0] == 0 && v[1] == 0:
+		return []byte{}, false, nil
+	case !nullable && len(v) == 1 && v[0] == 0:
+		return []byte{}, false, nil
+	}
+
+	return v, false, nil
+}
+
+func (r *reader) byteVector(nullable bool) (v []byte, null bool, err error) {
+	length, null, err := r.uint(nullable)
+	if err != nil || null {
+		return nil, null, err
+	}
+
+	if uint64(len(r.data)) < length {
+		return nil, false, ErrShortBuffer
+	}
+
+	v = append([]byte{}, r.data[:length]...)
+	r.data = r.data[length:]
+	return v, false, nil
+}
+
+// pmap reads the next presence map.
+func (r *reader) pmap() (*pmap, error) {
+	e, err := r.entity()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pmap{bytes: e}, nil
+}
+
+// pmap is a presence map, whose bits are taken in order.
+type pmap struct {
+	bytes []byte
+	bit   int
+}
+
+// next returns the next bit of the presence map. Bits past the end of the map are not set.
+func (p *pmap) next() bool {
+	i := p.bit / 7
+	mask := byte(0x40) >> (p.bit % 7)
+	p.bit++
+
+	return i < len(p.bytes) && p.bytes[i]&mask != 0
+}
+
+// set appends a bit to the presence map.
+func (p *pmap) set(bit bool) {
+	i := p.bit / 7
+	if i == len(p.bytes) {
+		p.bytes = append(p.bytes, 0)
+	}
+	if bit {
+		p.bytes[i] |= byte(0x40) >> (p.bit % 7)
+	}
+	p.bit++
+}
+
+// appendTo appends the presence map to b, without trailing bytes having no bits set.
+func (p *pmap) appendTo(b []byte) []byte {
+	n := len(p.bytes)
+	for n > 1 && p.bytes[n-1] == 0 {
+		n--
+	}
+	if n == 0 {
+		return append(b, stopBit)
+	}
+
+	b = append(b, p.bytes[:n]...)
+	b[len(b)-1] |= stopBit
+	return b
+}
+
+func appendUint(b []byte, v uint64, nullable, null bool) []byte {
+	if nullable {
+		if null {
+			return append(b, stopBit)
+		}
+		v++
+	}
+
+	var buf [10]byte
+	i := len(buf) - 1
+	buf[i] = byte(v&0x7f) | stopBit
+	for v >>= 7; v > 0; v >>= 7 {
+		i--
+		buf[i] = byte(v & 0x7f)
+	}
+
+	return append(b, buf[i:]...)
+}
+
+func appendInt(b []byte, v int64, nullable, null bool) []byte {
+	if nullable {
+		if null {
+			return append(b, stopBit)
+		}
+		if v >= 0 {
+			v++
+		}
+	}
+
+	var buf [10]byte
+	i := len(buf) - 1
+	buf[i] = byte(v&0x7f) | stopBit
+	// Emit bytes until the remaining value and the sign bit of the leading byte agree.
+	for {
+		sign := buf[i]&0x40 != 0
+		v >>= 7
+		if (v == 0 && !sign) || (v == -1 && sign) {
+			break
+		}
+		i--
+		buf[i] = byte(v & 0x7f)
+	}
+
+	return append(b, buf[i:]...)
+}
+
+func appendASCII(b []byte, v []byte, nullable, null bool) ([]byte, error) {
+	for _, c := range v {
+		if c&stopBit != 0 {
+			return nil, fmt.Errorf("non-ASCII character %q", c)
+		}
+	}
+
+	switch {
+	case null:
+		return append(b, stopBit), nil
+	case len(v) == 0 && nullable:
+		return append(b, 0, stopBit), nil
+	case len(v) == 0:
+		return append(b, stopBit), nil
+	}
+
+	b = append(b, v...)
+	b[len(b)-1] |= stopBit
+	return b, nil
+}
+
+func appendByteVector(b []byte, v []byte, nullable, null bool) []byte {
+	b = appendUint(b, uint64(len(v)), nullable, null)
+	if null {
+		return b
+	}
+
+	return append(b, v...)
+}
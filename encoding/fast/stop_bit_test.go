@@ -0,0 +1,144 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fast
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUint(t *testing.T) {
+	var tests = []struct {
+		value    uint64
+		nullable bool
+		encoded  []byte
+	}{
+		{0, false, []byte{0x80}},
+		{1, false, []byte{0x81}},
+		{942755, false, []byte{0x39, 0x45, 0xa3}},
+		{942755, true, []byte{0x39, 0x45, 0xa4}},
+		{0, true, []byte{0x81}},
+		{math.MaxUint64, false, []byte{0x01, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0xff}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.encoded, appendUint(nil, test.value, test.nullable, false), test.value)
+
+		r := reader{data: test.encoded}
+		v, null, err := r.uint(test.nullable)
+		require.Nil(t, err)
+		assert.False(t, null)
+		assert.Equal(t, test.value, v)
+	}
+
+	r := reader{data: appendUint(nil, 0, true, true)}
+	_, null, err := r.uint(true)
+	require.Nil(t, err)
+	assert.True(t, null)
+
+	r = reader{data: []byte{0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0xff}}
+	_, _, err = r.uint(false)
+	assert.Equal(t, errOverflow, err)
+}
+
+func TestInt(t *testing.T) {
+	var tests = []struct {
+		value    int64
+		nullable bool
+		encoded  []byte
+	}{
+		{0, false, []byte{0x80}},
+		{-1, false, []byte{0xff}},
+		{64, false, []byte{0x00, 0xc0}},
+		{-64, false, []byte{0xc0}},
+		{-65, false, []byte{0x7f, 0xbf}},
+		{942755, false, []byte{0x39, 0x45, 0xa3}},
+		{-942755, false, []byte{0x46, 0x3a, 0xdd}},
+		{-942755, true, []byte{0x46, 0x3a, 0xdd}},
+		{942755, true, []byte{0x39, 0x45, 0xa4}},
+		{math.MinInt64, false, []byte{0x7f, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.encoded, appendInt(nil, test.value, test.nullable, false), test.value)
+
+		r := reader{data: test.encoded}
+		v, null, err := r.int(test.nullable)
+		require.Nil(t, err)
+		assert.False(t, null)
+		assert.Equal(t, test.value, v)
+	}
+}
+
+func TestASCII(t *testing.T) {
+	var tests = []struct {
+		value    []byte
+		nullable bool
+		null     bool
+		encoded  []byte
+	}{
+		{[]byte("ABC"), false, false, []byte{0x41, 0x42, 0xc3}},
+		{[]byte{}, false, false, []byte{0x80}},
+		{[]byte{}, true, false, []byte{0x00, 0x80}},
+		{nil, true, true, []byte{0x80}},
+	}
+
+	for _, test := range tests {
+		encoded, err := appendASCII(nil, test.value, test.nullable, test.null)
+		require.Nil(t, err)
+		assert.Equal(t, test.encoded, encoded)
+
+		r := reader{data: test.encoded}
+		v, null, err := r.ascii(test.nullable)
+		require.Nil(t, err)
+		assert.Equal(t, test.null, null)
+		assert.Equal(t, test.value, v)
+	}
+
+	_, err := appendASCII(nil, []byte("é"), false, false)
+	assert.NotNil(t, err)
+}
+
+func TestPMap(t *testing.T) {
+	pm := &pmap{}
+	for _, bit := range []bool{true, false, true, false, false, false, false, true} {
+		pm.set(bit)
+	}
+	assert.Equal(t, []byte{0x50, 0xc0}, pm.appendTo(nil))
+
+	pm = &pmap{}
+	pm.set(true)
+	for i := 0; i < 10; i++ {
+		pm.set(false)
+	}
+	assert.Equal(t, []byte{0xc0}, pm.appendTo(nil))
+
+	r := reader{data: []byte{0x50, 0xc0}}
+	decoded, err := r.pmap()
+	require.Nil(t, err)
+
+	var bits []bool
+	for i := 0; i < 9; i++ {
+		bits = append(bits, decoded.next())
+	}
+	assert.Equal(t, []bool{true, false, true, false, false, false, false, true, false}, bits)
+
+	_, err = (&reader{data: []byte{0x50}}).pmap()
+	assert.Equal(t, ErrShortBuffer, err)
+}
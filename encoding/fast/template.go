@@ -0,0 +1,429 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package fast decodes and encodes FAST (FIX Adapted for STreaming) 1.1 messages described by XML templates,
+// converting them to and from quickfix Messages.
+package fast
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Kind is the type of a field instruction.
+type Kind int
+
+// The kinds of instructions.
+const (
+	KindUInt32 Kind = iota
+	KindInt32
+	KindUInt64
+	KindInt64
+	KindDecimal
+	KindASCII
+	KindUnicode
+	KindByteVector
+	KindSequence
+	KindGroup
+)
+
+var kindNames = map[string]Kind{
+	"uInt32":     KindUInt32,
+	"int32":      KindInt32,
+	"uInt64":     KindUInt64,
+	"int64":      KindInt64,
+	"decimal":    KindDecimal,
+	"string":     KindASCII,
+	"byteVector": KindByteVector,
+	"sequence":   KindSequence,
+	"group":      KindGroup,
+}
+
+// Operator is the field operator of an instruction.
+type Operator int
+
+// The field operators. The tail operator is not supported.
+const (
+	OpNone Operator = iota
+	OpConstant
+	OpDefault
+	OpCopy
+	OpIncrement
+	OpDelta
+)
+
+var operatorNames = map[string]Operator{
+	"constant":  OpConstant,
+	"default":   OpDefault,
+	"copy":      OpCopy,
+	"increment": OpIncrement,
+	"delta":     OpDelta,
+}
+
+// Instruction is a field instruction of a template.
+type Instruction struct {
+	Kind Kind
+	Name string
+
+	// ID is the FIX tag of the field, 0 if the field has no tag.
+	ID       int
+	Optional bool
+	Operator Operator
+
+	// Initial is the initial value of the operator, nil if it has none.
+	Initial *Value
+
+	// Key is the key of the previous value of the field in the dictionary.
+	Key string
+
+	// Length is the length field of a sequence.
+	Length *Instruction
+
+	// Instructions are the instructions of a sequence or group.
+	Instructions []*Instruction
+}
+
+// usesPMap returns true if the instruction uses a bit of the presence map of its segment.
+func (i *Instruction) usesPMap() bool {
+	switch i.Kind {
+	case KindSequence:
+		return i.Length.usesPMap()
+	case KindGroup:
+		return i.Optional
+	}
+
+	switch i.Operator {
+	case OpConstant:
+		return i.Optional
+	case OpDefault, OpCopy, OpIncrement:
+		return true
+	}
+
+	return false
+}
+
+// nullable returns true if the value of the instruction is encoded as nullable.
+func (i *Instruction) nullable() bool {
+	return i.Optional && i.Operator != OpConstant
+}
+
+// Template is a message template.
+type Template struct {
+	Name         string
+	ID           uint32
+	Instructions []*Instruction
+}
+
+// Templates are the templates of a FAST stream, by their ids.
+type Templates struct {
+	byID map[uint32]*Template
+}
+
+// Template returns the template with id.
+func (t *Templates) Template(id uint32) (*Template, bool) {
+	tmpl, ok := t.byID[id]
+	return tmpl, ok
+}
+
+// xmlNode is an element of a template definition document.
+type xmlNode struct {
+	XMLName    xml.Name
+	Name       string    `xml:"name,attr"`
+	ID         string    `xml:"id,attr"`
+	Presence   string    `xml:"presence,attr"`
+	Charset    string    `xml:"charset,attr"`
+	Dictionary string    `xml:"dictionary,attr"`
+	Key        string    `xml:"key,attr"`
+	Value      *string   `xml:"value,attr"`
+	Children   []xmlNode `xml:",any"`
+}
+
+// ParseTemplatesFile parses the template definitions at path.
+func ParseTemplatesFile(path string) (*Templates, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseTemplates(f)
+}
+
+// ParseTemplates parses a templates document of template definitions. Static template references are expanded into
+// the referring templates, and decimals with individual operators of exponent and mantissa are not supported.
+func ParseTemplates(r io.Reader) (*Templates, error) {
+	var doc xmlNode
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	b := templatesBuilder{byName: make(map[string]xmlNode), building: make(map[string]bool)}
+	for _, node := range doc.Children {
+		if node.XMLName.Local != "template" {
+			continue
+		}
+		if node.Dictionary == "" {
+			node.Dictionary = doc.Dictionary
+		}
+		b.byName[node.Name] = node
+	}
+
+	templates := &Templates{byID: make(map[uint32]*Template)}
+	for _, node := range doc.Children {
+		if node.XMLName.Local != "template" {
+			continue
+		}
+
+		id, err := strconv.ParseUint(node.ID, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("template %v: invalid id %q", node.Name, node.ID)
+		}
+
+		instructions, err := b.instructions(b.byName[node.Name], b.byName[node.Name])
+		if err != nil {
+			return nil, fmt.Errorf("template %v: %w", node.Name, err)
+		}
+
+		templates.byID[uint32(id)] = &Template{Name: node.Name, ID: uint32(id), Instructions: instructions}
+	}
+
+	return templates, nil
+}
+
+type templatesBuilder struct {
+	byName   map[string]xmlNode
+	building map[string]bool
+}
+
+// instructions returns the instructions of the children of node, with keys in the dictionary of template.
+func (b templatesBuilder) instructions(node, template xmlNode) ([]*Instruction, error) {
+	var instructions []*Instruction
+	for _, child := range node.Children {
+		switch child.XMLName.Local {
+		case "typeRef":
+			continue
+		case "templateRef":
+			if child.Name == "" {
+				return nil, fmt.Errorf("dynamic templateRef is not supported")
+			}
+
+			ref, ok := b.byName[child.Name]
+			if !ok {
+				return nil, fmt.Errorf("unknown templateRef %v", child.Name)
+			}
+			if b.building[child.Name] {
+				return nil, fmt.Errorf("templateRef %v refers to itself", child.Name)
+			}
+
+			b.building[child.Name] = true
+			refInstructions, err := b.instructions(ref, ref)
+			delete(b.building, child.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			instructions = append(instructions, refInstructions...)
+			continue
+		}
+
+		i, err := b.instruction(child, template)
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, i)
+	}
+
+	return instructions, nil
+}
+
+func (b templatesBuilder) instruction(node, template xmlNode) (*Instruction, error) {
+	kind, ok := kindNames[node.XMLName.Local]
+	if !ok {
+		return nil, fmt.Errorf("unknown instruction %v", node.XMLName.Local)
+	}
+	if kind == KindASCII && node.Charset == "unicode" {
+		kind = KindUnicode
+	}
+
+	i := &Instruction{Kind: kind, Name: node.Name, Optional: node.Presence == "optional"}
+	if node.Presence != "" && node.Presence != "optional" && node.Presence != "mandatory" {
+		return nil, fmt.Errorf("%v: unknown presence %q", node.Name, node.Presence)
+	}
+
+	if node.ID != "" {
+		id, err := strconv.Atoi(node.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%v: invalid id %q", node.Name, node.ID)
+		}
+		i.ID = id
+	}
+
+	switch kind {
+	case KindSequence:
+		children := node
+		children.Children = nil
+		for _, child := range node.Children {
+			if child.XMLName.Local != "length" {
+				children.Children = append(children.Children, child)
+				continue
+			}
+
+			length := child
+			length.XMLName.Local = "uInt32"
+			length.Presence = node.Presence
+			if length.Name == "" {
+				length.Name = node.Name + "Length"
+			}
+
+			var err error
+			if i.Length, err = b.instruction(length, template); err != nil {
+				return nil, err
+			}
+		}
+
+		if i.Length == nil {
+			i.Length = &Instruction{Kind: KindUInt32, Name: node.Name + "Length", Optional: i.Optional}
+		}
+
+		var err error
+		if i.Instructions, err = b.instructions(children, template); err != nil {
+			return nil, fmt.Errorf("%v: %w", node.Name, err)
+		}
+		return i, nil
+
+	case KindGroup:
+		var err error
+		if i.Instructions, err = b.instructions(node, template); err != nil {
+			return nil, fmt.Errorf("%v: %w", node.Name, err)
+		}
+		return i, nil
+	}
+
+	i.Key = node.Name
+	dictionary := template.Dictionary
+	for _, child := range node.Children {
+		switch child.XMLName.Local {
+		case "exponent", "mantissa":
+			return nil, fmt.Errorf("%v: individual decimal operators are not supported", node.Name)
+		case "tail":
+			return nil, fmt.Errorf("%v: tail operator is not supported", node.Name)
+		}
+
+		op, ok := operatorNames[child.XMLName.Local]
+		if !ok {
+			return nil, fmt.Errorf("%v: unknown operator %v", node.Name, child.XMLName.Local)
+		}
+		i.Operator = op
+
+		if child.Key != "" {
+			i.Key = child.Key
+		}
+		if child.Dictionary != "" {
+			dictionary = child.Dictionary
+		}
+
+		if child.Value != nil {
+			v, err := parseValue(kind, *child.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%v: invalid value %q: %w", node.Name, *child.Value, err)
+			}
+			i.Initial = &v
+		}
+	}
+
+	if i.Operator == OpConstant && i.Initial == nil {
+		return nil, fmt.Errorf("%v: constant operator without value", node.Name)
+	}
+	if i.Operator == OpIncrement && kind > KindInt64 {
+		return nil, fmt.Errorf("%v: increment operator on a %v", node.Name, node.XMLName.Local)
+	}
+
+	if dictionary == "template" {
+		i.Key = template.Name + "." + i.Key
+	}
+
+	return i, nil
+}
+
+// Value is the value of a field.
+type Value struct {
+	// Uint is the value of an unsigned integer.
+	Uint uint64
+
+	// Int is the value of a signed integer or the mantissa of a decimal.
+	Int int64
+
+	// Exponent is the exponent of a decimal.
+	Exponent int32
+
+	// Bytes is the value of a string or byte vector.
+	Bytes []byte
+}
+
+func (v Value) equal(other Value) bool {
+	return v.Uint == other.Uint && v.Int == other.Int && v.Exponent == other.Exponent &&
+		string(v.Bytes) == string(other.Bytes)
+}
+
+// parseValue parses the FIX value s of a field of kind.
+func parseValue(kind Kind, s string) (v Value, err error) {
+	switch kind {
+	case KindUInt32, KindUInt64:
+		bitSize := 64
+		if kind == KindUInt32 {
+			bitSize = 32
+		}
+		v.Uint, err = strconv.ParseUint(s, 10, bitSize)
+	case KindInt32, KindInt64:
+		bitSize := 64
+		if kind == KindInt32 {
+			bitSize = 32
+		}
+		v.Int, err = strconv.ParseInt(s, 10, bitSize)
+	case KindDecimal:
+		var d decimal.Decimal
+		if d, err = decimal.NewFromString(strings.TrimSpace(s)); err != nil {
+			return
+		}
+		if !d.Coefficient().IsInt64() {
+			return v, fmt.Errorf("mantissa out of range")
+		}
+		v.Int, v.Exponent = d.Coefficient().Int64(), d.Exponent()
+	default:
+		v.Bytes = []byte(s)
+	}
+
+	return
+}
+
+// format returns the FIX value of v as a field of kind.
+func (v Value) format(kind Kind) string {
+	switch kind {
+	case KindUInt32, KindUInt64:
+		return strconv.FormatUint(v.Uint, 10)
+	case KindInt32, KindInt64:
+		return strconv.FormatInt(v.Int, 10)
+	case KindDecimal:
+		return decimal.New(v.Int, v.Exponent).String()
+	}
+
+	return string(v.Bytes)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fast
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemplatesFile(t *testing.T) {
+	templates, err := ParseTemplatesFile("testdata/templates.xml")
+	require.Nil(t, err)
+
+	tmpl, ok := templates.Template(1)
+	require.True(t, ok)
+	assert.Equal(t, "MDIncRefresh", tmpl.Name)
+	require.Len(t, tmpl.Instructions, 5)
+
+	msgType := tmpl.Instructions[0]
+	assert.Equal(t, KindASCII, msgType.Kind)
+	assert.Equal(t, OpConstant, msgType.Operator)
+	assert.Equal(t, "X", string(msgType.Initial.Bytes))
+
+	tradeDate := tmpl.Instructions[3]
+	assert.True(t, tradeDate.Optional)
+	assert.Equal(t, OpCopy, tradeDate.Operator)
+	assert.Nil(t, tradeDate.Initial)
+
+	entries := tmpl.Instructions[4]
+	assert.Equal(t, KindSequence, entries.Kind)
+	assert.Equal(t, 268, entries.Length.ID)
+	require.Len(t, entries.Instructions, 6)
+	assert.Equal(t, uint64(1), entries.Instructions[0].Initial.Uint)
+	assert.Equal(t, KindDecimal, entries.Instructions[3].Kind)
+	assert.Equal(t, KindGroup, entries.Instructions[5].Kind)
+	assert.Equal(t, KindByteVector, entries.Instructions[5].Instructions[1].Kind)
+
+	heartbeat, ok := templates.Template(2)
+	require.True(t, ok)
+	require.Len(t, heartbeat.Instructions, 2)
+	assert.Equal(t, 34, heartbeat.Instructions[1].ID)
+}
+
+func TestParseTemplatesDictionary(t *testing.T) {
+	templates, err := ParseTemplates(strings.NewReader(`<templates dictionary="template">
+		<template name="A" id="1"><uInt32 name="Seq"><copy/></uInt32></template>
+		<template name="B" id="2" dictionary="global"><uInt32 name="Seq"><copy key="Number"/></uInt32></template>
+	</templates>`))
+	require.Nil(t, err)
+
+	a, _ := templates.Template(1)
+	assert.Equal(t, "A.Seq", a.Instructions[0].Key)
+	b, _ := templates.Template(2)
+	assert.Equal(t, "Number", b.Instructions[0].Key)
+}
+
+func TestParseTemplatesErrors(t *testing.T) {
+	var tests = []string{
+		`<templates><template name="A" id="x"/></templates>`,
+		`<templates><template name="A" id="1"><float name="F"/></template></templates>`,
+		`<templates><template name="A" id="1"><uInt32 name="F"><tail/></uInt32></template></templates>`,
+		`<templates><template name="A" id="1"><uInt32 name="F"><constant/></uInt32></template></templates>`,
+		`<templates><template name="A" id="1"><uInt32 name="F"><copy value="-1"/></uInt32></template></templates>`,
+		`<templates><template name="A" id="1"><string name="F"><increment/></string></template></templates>`,
+		`<templates><template name="A" id="1"><decimal name="F"><exponent><copy/></exponent></decimal>` +
+			`</template></templates>`,
+		`<templates><template name="A" id="1"><templateRef/></template></templates>`,
+		`<templates><template name="A" id="1"><templateRef name="A"/></template></templates>`,
+	}
+
+	for _, test := range tests {
+		_, err := ParseTemplates(strings.NewReader(test))
+		assert.NotNil(t, err, test)
+	}
+}
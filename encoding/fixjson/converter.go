@@ -0,0 +1,133 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package fixjson converts between quickfix Messages and the FIX JSON encoding, naming fields and nesting repeating
+// groups by the DataDictionary of the FIX version of each message.
+package fixjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+const (
+	tagBeginString quickfix.Tag = 8
+	tagApplVerID   quickfix.Tag = 1128
+
+	beginStringFIXT11 = "FIXT.1.1"
+)
+
+// applVerIDs are the versions of the ApplVerID enum values.
+var applVerIDs = map[string]string{
+	"2": "FIX.4.0",
+	"3": "FIX.4.1",
+	"4": "FIX.4.2",
+	"5": "FIX.4.3",
+	"6": "FIX.4.4",
+	"7": "FIX.5.0",
+	"8": "FIX.5.0SP1",
+	"9": "FIX.5.0SP2",
+}
+
+// Converter converts between Messages and the FIX JSON encoding with the DataDictionaries added to it.
+type Converter struct {
+	// DefaultApplVerID is the application version of FIXT.1.1 messages without ApplVerID(1128), either an ApplVerID
+	// enum value or the BeginString of the version, e.g. FIX.5.0SP2.
+	DefaultApplVerID string
+
+	dictionaries map[string]*datadictionary.DataDictionary
+}
+
+// NewConverter returns a Converter with the given DataDictionaries, which are selected by the BeginString of
+// messages, or for FIXT.1.1 messages by their ApplVerID.
+func NewConverter(dictionaries ...*datadictionary.DataDictionary) *Converter {
+	c := &Converter{dictionaries: make(map[string]*datadictionary.DataDictionary)}
+	for _, dict := range dictionaries {
+		c.Add(dict)
+	}
+
+	return c
+}
+
+// Add adds dict for messages of its FIX version, replacing any DataDictionary previously added for the version.
+func (c *Converter) Add(dict *datadictionary.DataDictionary) {
+	c.dictionaries[version(dict)] = dict
+}
+
+// version returns the BeginString of the FIX version of dict, e.g. FIX.4.4, FIX.5.0SP2 or FIXT.1.1.
+func version(dict *datadictionary.DataDictionary) string {
+	v := fmt.Sprintf("%v.%v.%v", dict.FIXType, dict.Major, dict.Minor)
+	if dict.ServicePack > 0 {
+		v += "SP" + strconv.Itoa(dict.ServicePack)
+	}
+
+	return v
+}
+
+// dictionariesFor returns the transport and application DataDictionaries of messages of beginString and applVerID.
+func (c *Converter) dictionariesFor(beginString, applVerID string) (transport, app *datadictionary.DataDictionary) {
+	if beginString != beginStringFIXT11 {
+		return nil, c.dictionaries[beginString]
+	}
+
+	if applVerID == "" {
+		applVerID = c.DefaultApplVerID
+	}
+	if v, ok := applVerIDs[applVerID]; ok {
+		applVerID = v
+	}
+
+	return c.dictionaries[beginStringFIXT11], c.dictionaries[applVerID]
+}
+
+// Marshal encodes msg in the FIX JSON encoding.
+func (c *Converter) Marshal(msg *quickfix.Message) ([]byte, error) {
+	beginString, _ := msg.Header.GetString(tagBeginString)
+	applVerID, _ := msg.Header.GetString(tagApplVerID)
+	transport, app := c.dictionariesFor(beginString, applVerID)
+
+	return quickfix.MarshalJSONWithDataDictionary(msg, transport, app)
+}
+
+// Unmarshal decodes msg from the FIX JSON encoding.
+func (c *Converter) Unmarshal(data []byte, msg *quickfix.Message) error {
+	var sections struct {
+		Header map[string]any
+	}
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return err
+	}
+
+	beginString := headerValue(sections.Header, "BeginString", tagBeginString)
+	applVerID := headerValue(sections.Header, "ApplVerID", tagApplVerID)
+	transport, app := c.dictionariesFor(beginString, applVerID)
+
+	return quickfix.UnmarshalJSONWithDataDictionary(msg, data, transport, app)
+}
+
+// headerValue returns the value of the header field named name or by its tag.
+func headerValue(header map[string]any, name string, tag quickfix.Tag) string {
+	for _, key := range []string{name, strconv.Itoa(int(tag))} {
+		if value, ok := header[key].(string); ok {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,100 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fixjson
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+func parseDictionary(t *testing.T, path string) *datadictionary.DataDictionary {
+	dict, err := datadictionary.Parse(path)
+	require.Nil(t, err)
+
+	return dict
+}
+
+func TestVersion(t *testing.T) {
+	assert.Equal(t, "FIX.4.4", version(parseDictionary(t, "../../spec/FIX44.xml")))
+	assert.Equal(t, "FIX.5.0SP2", version(parseDictionary(t, "../../spec/FIX50SP2.xml")))
+	assert.Equal(t, "FIXT.1.1", version(parseDictionary(t, "../../spec/FIXT11.xml")))
+}
+
+func TestConverterFIX44(t *testing.T) {
+	c := NewConverter(parseDictionary(t, "../../spec/FIX44.xml"))
+
+	raw := "8=FIX.4.4\x019=51\x0135=D\x0134=1\x0149=TW\x0152=20240101-00:00:00\x0156=ISLD\x0111=ID\x0110=006\x01"
+	msg := quickfix.NewMessage()
+	require.Nil(t, quickfix.ParseMessage(msg, bytes.NewBufferString(raw)))
+
+	data, err := c.Marshal(msg)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{
+		"Header": {"BeginString": "FIX.4.4", "MsgType": "D", "MsgSeqNum": "1", "SenderCompID": "TW",
+			"SendingTime": "20240101-00:00:00", "TargetCompID": "ISLD"},
+		"Body": {"ClOrdID": "ID"},
+		"Trailer": {}
+	}`, string(data))
+
+	unmarshaled := quickfix.NewMessage()
+	require.Nil(t, c.Unmarshal(data, unmarshaled))
+	assert.Equal(t, raw, unmarshaled.String())
+}
+
+func TestConverterFIXT11(t *testing.T) {
+	c := NewConverter(
+		parseDictionary(t, "../../spec/FIXT11.xml"),
+		parseDictionary(t, "../../spec/FIX50SP2.xml"),
+		parseDictionary(t, "../../spec/FIX44.xml"),
+	)
+	c.DefaultApplVerID = "FIX.5.0SP2"
+
+	var tests = []struct {
+		applVerID string
+		expected  string
+	}{
+		// HostCrossID(961) is only defined by FIX.5.0SP2.
+		{"", `"HostCrossID"`},
+		{"9", `"HostCrossID"`},
+		{"6", `"961"`},
+	}
+
+	for _, test := range tests {
+		msg := quickfix.NewMessage()
+		msg.Header.SetString(tagBeginString, beginStringFIXT11).SetString(quickfix.Tag(35), "D")
+		if test.applVerID != "" {
+			msg.Header.SetString(tagApplVerID, test.applVerID)
+		}
+		msg.Body.SetString(quickfix.Tag(961), "ID")
+
+		data, err := c.Marshal(msg)
+		require.Nil(t, err)
+		assert.Contains(t, string(data), `"MsgType":"D"`, test.applVerID)
+		assert.Contains(t, string(data), test.expected+`:"ID"`, test.applVerID)
+
+		unmarshaled := quickfix.NewMessage()
+		require.Nil(t, c.Unmarshal(data, unmarshaled), test.applVerID)
+		value, err := unmarshaled.Body.GetString(quickfix.Tag(961))
+		require.Nil(t, err)
+		assert.Equal(t, "ID", value)
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fixproto
+
+import (
+	"fmt"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// Bridge converts between Messages and the protobuf messages described by a DataDictionary.
+type Bridge struct {
+	file        protoreflect.FileDescriptor
+	beginString string
+	headerTags  map[int]bool
+
+	// msgTypes are the MsgTypes of the protobuf messages, and names the protobuf messages of the MsgTypes.
+	msgTypes map[protoreflect.FullName]string
+	names    map[string]protoreflect.FullName
+}
+
+// NewBridge returns a Bridge for the protobuf messages of the application data dictionary in protobuf package pkg,
+// as described by Descriptor.
+func NewBridge(transportDataDictionary, appDataDictionary *datadictionary.DataDictionary, pkg string) (*Bridge, error) {
+	fileProto, err := Descriptor(transportDataDictionary, appDataDictionary, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := protodesc.NewFile(fileProto, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fixproto: %w", err)
+	}
+
+	b := &Bridge{
+		file:        file,
+		beginString: fmt.Sprintf("FIX.%v.%v", appDataDictionary.Major, appDataDictionary.Minor),
+		headerTags:  make(map[int]bool),
+		msgTypes:    make(map[protoreflect.FullName]string),
+		names:       make(map[string]protoreflect.FullName),
+	}
+
+	header := appDataDictionary.Header
+	if transportDataDictionary != nil {
+		b.beginString = fmt.Sprintf("FIXT.%v.%v", transportDataDictionary.Major, transportDataDictionary.Minor)
+		header = transportDataDictionary.Header
+	}
+	if header != nil {
+		for _, def := range fieldDefs(header.Parts) {
+			b.headerTags[def.Tag()] = true
+		}
+	}
+
+	for _, def := range appDataDictionary.Messages {
+		name := protoreflect.FullName(pkg).Append(protoreflect.Name(def.Name))
+		b.msgTypes[name] = def.MsgType
+		b.names[def.MsgType] = name
+	}
+
+	return b, nil
+}
+
+// File returns the descriptor of the file of the protobuf messages.
+func (b *Bridge) File() protoreflect.FileDescriptor {
+	return b.file
+}
+
+// ToProto converts msg to the protobuf message of its MsgType. The message is of the generated type if it is linked
+// into the program, and otherwise a dynamic message.
+func (b *Bridge) ToProto(msg *quickfix.Message) (proto.Message, error) {
+	msgType, err := msg.MsgType()
+	if err != nil {
+		return nil, err
+	}
+
+	name, ok := b.names[msgType]
+	if !ok {
+		return nil, fmt.Errorf("fixproto: unknown MsgType %q", msgType)
+	}
+
+	var m protoreflect.Message
+	if messageType, err := protoregistry.GlobalTypes.FindMessageByName(name); err == nil {
+		m = messageType.New()
+	} else {
+		m = dynamicpb.NewMessage(b.file.Messages().ByName(name.Name()))
+	}
+
+	if err := b.Fill(m.Interface(), msg); err != nil {
+		return nil, err
+	}
+
+	return m.Interface(), nil
+}
+
+// Fill sets the fields of dst, a protobuf message of the MsgType of msg, to the fields of msg.
+func (b *Bridge) Fill(dst proto.Message, msg *quickfix.Message) error {
+	m := dst.ProtoReflect()
+	msgType, err := msg.MsgType()
+	if err != nil {
+		return err
+	}
+	if b.msgTypes[m.Descriptor().FullName()] != msgType {
+		return fmt.Errorf("fixproto: %v is not of MsgType %q", m.Descriptor().FullName(), msgType)
+	}
+
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		fieldMap := &msg.Body.FieldMap
+		if b.headerTags[int(fd.Number())] {
+			fieldMap = &msg.Header.FieldMap
+		}
+
+		if err := fillField(m, fd, fieldMap); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FromProto converts a protobuf message to a Message.
+func (b *Bridge) FromProto(src proto.Message) (*quickfix.Message, error) {
+	m := src.ProtoReflect()
+	msgType, ok := b.msgTypes[m.Descriptor().FullName()]
+	if !ok {
+		return nil, fmt.Errorf("fixproto: unknown message %v", m.Descriptor().FullName())
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(tagBeginString, b.beginString)
+	msg.Header.SetString(tagMsgType, msgType)
+
+	var err error
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		fieldMap := &msg.Body.FieldMap
+		if b.headerTags[int(fd.Number())] {
+			fieldMap = &msg.Header.FieldMap
+		}
+
+		err = setField(fieldMap, fd, v)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// fillField sets the field fd of m to the field of fieldMap.
+func fillField(m protoreflect.Message, fd protoreflect.FieldDescriptor, fieldMap *quickfix.FieldMap) error {
+	tag := quickfix.Tag(fd.Number())
+	if !fieldMap.Has(tag) {
+		return nil
+	}
+
+	if !fd.IsList() {
+		raw, err := fieldMap.GetBytes(tag)
+		if err != nil {
+			return err
+		}
+
+		v, convErr := protoValue(fd, raw)
+		if convErr != nil {
+			return fmt.Errorf("fixproto: %v: %w", fd.Name(), convErr)
+		}
+		m.Set(fd, v)
+		return nil
+	}
+
+	if fd.Message() == nil {
+		return fmt.Errorf("fixproto: %v: repeated scalars are not supported", fd.Name())
+	}
+
+	rg := quickfix.NewRepeatingGroup(tag, groupTemplate(fd.Message()))
+	if err := fieldMap.GetGroup(rg); err != nil {
+		return err
+	}
+
+	list := m.Mutable(fd).List()
+	fields := fd.Message().Fields()
+	for i := 0; i < rg.Len(); i++ {
+		instance := list.NewElement()
+		for j := 0; j < fields.Len(); j++ {
+			if err := fillField(instance.Message(), fields.Get(j), &rg.Get(i).FieldMap); err != nil {
+				return err
+			}
+		}
+		list.Append(instance)
+	}
+
+	return nil
+}
+
+// setField sets the field of fieldMap to the value v of the field fd.
+func setField(fieldMap *quickfix.FieldMap, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	tag := quickfix.Tag(fd.Number())
+	if !fd.IsList() {
+		raw, err := fixValue(fd, v)
+		if err != nil {
+			return fmt.Errorf("fixproto: %v: %w", fd.Name(), err)
+		}
+		fieldMap.SetBytes(tag, raw)
+		return nil
+	}
+
+	if fd.Message() == nil {
+		return fmt.Errorf("fixproto: %v: repeated scalars are not supported", fd.Name())
+	}
+
+	rg := quickfix.NewRepeatingGroup(tag, groupTemplate(fd.Message()))
+	list := v.List()
+	for i := 0; i < list.Len(); i++ {
+		group := rg.Add()
+
+		var err error
+		list.Get(i).Message().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			err = setField(&group.FieldMap, fd, v)
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	fieldMap.SetGroup(rg)
+
+	return nil
+}
+
+// groupTemplate returns the template of the repeating group whose instances are described by md.
+func groupTemplate(md protoreflect.MessageDescriptor) quickfix.GroupTemplate {
+	fields := md.Fields()
+	template := make(quickfix.GroupTemplate, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.IsList() && fd.Message() != nil {
+			template = append(template, quickfix.NewRepeatingGroup(quickfix.Tag(fd.Number()), groupTemplate(fd.Message())))
+		} else {
+			template = append(template, quickfix.GroupElement(quickfix.Tag(fd.Number())))
+		}
+	}
+
+	return template
+}
+
+// protoValue returns the value of the field fd with the FIX value raw.
+func protoValue(fd protoreflect.FieldDescriptor, raw []byte) (protoreflect.Value, error) {
+	s := string(raw)
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(append([]byte(nil), raw...)), nil
+	case protoreflect.BoolKind:
+		switch s {
+		case "Y":
+			return protoreflect.ValueOfBool(true), nil
+		case "N":
+			return protoreflect.ValueOfBool(false), nil
+		}
+		return protoreflect.Value{}, fmt.Errorf("invalid boolean %q", s)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(s, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(s, 64)
+		return protoreflect.ValueOfFloat64(v), err
+	}
+
+	return protoreflect.Value{}, fmt.Errorf("unsupported kind %v", fd.Kind())
+}
+
+// fixValue returns the FIX value of the value v of the field fd.
+func fixValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) ([]byte, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return []byte(v.String()), nil
+	case protoreflect.BytesKind:
+		return v.Bytes(), nil
+	case protoreflect.BoolKind:
+		if v.Bool() {
+			return []byte("Y"), nil
+		}
+		return []byte("N"), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.AppendInt(nil, v.Int(), 10), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.AppendUint(nil, v.Uint(), 10), nil
+	case protoreflect.FloatKind:
+		return strconv.AppendFloat(nil, v.Float(), 'f', -1, 32), nil
+	case protoreflect.DoubleKind:
+		return strconv.AppendFloat(nil, v.Float(), 'f', -1, 64), nil
+	}
+
+	return nil, fmt.Errorf("unsupported kind %v", fd.Kind())
+}
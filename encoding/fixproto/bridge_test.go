@@ -0,0 +1,117 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fixproto
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const newOrderSingle = "8=FIX.4.4\x019=0\x0135=D\x0134=2\x0149=CLIENT\x0152=20240102-10:11:12.000\x0156=BROKER\x01" +
+	"11=ORD1\x0138=100\x0140=2\x0144=101.25\x0154=1\x0155=IBM\x0160=20240102-10:11:12.000\x01114=Y\x01" +
+	"453=2\x01448=FIRM\x01447=D\x01452=1\x01802=1\x01523=DESK\x01803=4\x01448=CLIENT\x01447=D\x01452=3\x0110=000\x01"
+
+func newTestBridge(t *testing.T) *Bridge {
+	b, err := NewBridge(nil, parseDictionary(t, "../../spec/FIX44.xml"), "fix44")
+	require.Nil(t, err)
+
+	return b
+}
+
+// parseMessage parses raw, a message with BeginString, BodyLength and CheckSum, after setting its BodyLength and
+// CheckSum.
+func parseMessage(t *testing.T, raw string) *quickfix.Message {
+	beginString, rest, _ := strings.Cut(raw, "\x019=0\x01")
+	body := rest[:strings.LastIndex(rest, "10=")]
+	raw = fmt.Sprintf("%v\x019=%v\x01%v", beginString, len(body), body)
+
+	checkSum := 0
+	for i := 0; i < len(raw); i++ {
+		checkSum += int(raw[i])
+	}
+	raw += fmt.Sprintf("10=%03d\x01", checkSum%256)
+
+	msg := quickfix.NewMessage()
+	require.Nil(t, quickfix.ParseMessage(msg, bytes.NewBufferString(raw)))
+
+	return msg
+}
+
+func get(m protoreflect.Message, name string) protoreflect.Value {
+	return m.Get(m.Descriptor().Fields().ByName(protoreflect.Name(name)))
+}
+
+func TestBridgeToProto(t *testing.T) {
+	b := newTestBridge(t)
+
+	pm, err := b.ToProto(parseMessage(t, newOrderSingle))
+	require.Nil(t, err)
+
+	m := pm.ProtoReflect()
+	assert.Equal(t, protoreflect.FullName("fix44.NewOrderSingle"), m.Descriptor().FullName())
+	assert.Equal(t, "CLIENT", get(m, "SenderCompID").String())
+	assert.Equal(t, int64(2), get(m, "MsgSeqNum").Int())
+	assert.Equal(t, "ORD1", get(m, "ClOrdID").String())
+	assert.Equal(t, "101.25", get(m, "Price").String())
+	assert.True(t, get(m, "LocateReqd").Bool())
+	assert.False(t, m.Has(m.Descriptor().Fields().ByName("Account")))
+
+	parties := get(m, "NoPartyIDs").List()
+	require.Equal(t, 2, parties.Len())
+	assert.Equal(t, "FIRM", get(parties.Get(0).Message(), "PartyID").String())
+	assert.Equal(t, int64(1), get(parties.Get(0).Message(), "PartyRole").Int())
+	assert.Equal(t, "CLIENT", get(parties.Get(1).Message(), "PartyID").String())
+
+	subIDs := get(parties.Get(0).Message(), "NoPartySubIDs").List()
+	require.Equal(t, 1, subIDs.Len())
+	assert.Equal(t, "DESK", get(subIDs.Get(0).Message(), "PartySubID").String())
+	assert.Equal(t, 0, get(parties.Get(1).Message(), "NoPartySubIDs").List().Len())
+}
+
+func TestBridgeFromProto(t *testing.T) {
+	b := newTestBridge(t)
+	original := parseMessage(t, newOrderSingle)
+
+	pm, err := b.ToProto(original)
+	require.Nil(t, err)
+
+	msg, err := b.FromProto(pm)
+	require.Nil(t, err)
+
+	assert.Equal(t, original.String(), msg.String())
+}
+
+func TestBridgeErrors(t *testing.T) {
+	b := newTestBridge(t)
+
+	_, err := b.ToProto(parseMessage(t, "8=FIX.4.4\x019=0\x0135=ZZ\x0110=000\x01"))
+	assert.NotNil(t, err)
+
+	_, err = b.ToProto(parseMessage(t, "8=FIX.4.4\x019=0\x0135=D\x0134=x\x0110=000\x01"))
+	assert.NotNil(t, err)
+
+	pm, err := b.ToProto(parseMessage(t, "8=FIX.4.4\x019=0\x0135=8\x0110=000\x01"))
+	require.Nil(t, err)
+	assert.NotNil(t, b.Fill(pm, parseMessage(t, newOrderSingle)))
+}
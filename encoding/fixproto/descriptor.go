@@ -0,0 +1,217 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package fixproto maps quickfix Messages to and from protobuf messages described by a DataDictionary.
+//
+// Each FIX message of the dictionary is described by a protobuf message of the same name, whose fields are the header
+// and body fields of the FIX message, named by the dictionary and numbered by their tags. Repeating groups are
+// repeated fields of nested messages. Integer fields are int64, booleans bool, data fields bytes and all other fields,
+// including prices and quantities so that no precision is lost, strings. The .proto file written by Proto may be
+// compiled with protoc, and the generated types are converted by a Bridge.
+package fixproto
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+const (
+	tagBeginString = 8
+	tagBodyLength  = 9
+	tagMsgType     = 35
+)
+
+// groupSuffix is appended to the name of a repeating group field to name the message of its instances.
+const groupSuffix = "Group"
+
+// DefaultPackage returns the protobuf package of the messages of a DataDictionary, e.g. fix44 or fix50sp2.
+func DefaultPackage(dict *datadictionary.DataDictionary) string {
+	pkg := fmt.Sprintf("%v%v%v", strings.ToLower(dict.FIXType), dict.Major, dict.Minor)
+	if dict.ServicePack > 0 {
+		pkg += fmt.Sprintf("sp%v", dict.ServicePack)
+	}
+
+	return pkg
+}
+
+// Descriptor returns the descriptor of the file of the protobuf messages of the application data dictionary, in
+// protobuf package pkg. The header fields are defined by the transport data dictionary, which is only needed for
+// FIXT.1.1 application dictionaries.
+func Descriptor(transportDataDictionary, appDataDictionary *datadictionary.DataDictionary, pkg string) (*descriptorpb.FileDescriptorProto, error) {
+	if appDataDictionary == nil {
+		return nil, fmt.Errorf("fixproto: no application data dictionary")
+	}
+
+	header := appDataDictionary.Header
+	if transportDataDictionary != nil {
+		header = transportDataDictionary.Header
+	}
+
+	var headerFields []*datadictionary.FieldDef
+	if header != nil {
+		for _, def := range fieldDefs(header.Parts) {
+			switch def.Tag() {
+			case tagBeginString, tagBodyLength, tagMsgType:
+				continue
+			}
+			headerFields = append(headerFields, def)
+		}
+	}
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String(pkg + ".proto"),
+		Package: proto.String(pkg),
+		Syntax:  proto.String("proto3"),
+	}
+
+	names := make([]string, 0, len(appDataDictionary.Messages))
+	byName := make(map[string]*datadictionary.MessageDef, len(appDataDictionary.Messages))
+	for _, def := range appDataDictionary.Messages {
+		names = append(names, def.Name)
+		byName[def.Name] = def
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fields := append(append([]*datadictionary.FieldDef{}, headerFields...), fieldDefs(byName[name].Parts)...)
+
+		msg, err := messageDescriptor("."+pkg+"."+name, fields)
+		if err != nil {
+			return nil, fmt.Errorf("fixproto: %v: %w", name, err)
+		}
+		file.MessageType = append(file.MessageType, msg)
+	}
+
+	return file, nil
+}
+
+// fieldDefs returns the fields of parts, with those of components in place of the components.
+func fieldDefs(parts []datadictionary.MessagePart) []*datadictionary.FieldDef {
+	var defs []*datadictionary.FieldDef
+	for _, part := range parts {
+		switch p := part.(type) {
+		case *datadictionary.FieldDef:
+			defs = append(defs, p)
+		case datadictionary.Component:
+			defs = append(defs, p.Fields()...)
+		}
+	}
+
+	return defs
+}
+
+// messageDescriptor returns the descriptor of the message with the fully qualified name fullName.
+func messageDescriptor(fullName string, fields []*datadictionary.FieldDef) (*descriptorpb.DescriptorProto, error) {
+	msg := &descriptorpb.DescriptorProto{Name: proto.String(fullName[strings.LastIndexByte(fullName, '.')+1:])}
+
+	seen := make(map[int]bool, len(fields))
+	for _, def := range fields {
+		if seen[def.Tag()] {
+			continue
+		}
+		seen[def.Tag()] = true
+
+		field := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(def.Name()),
+			JsonName: proto.String(def.Name()),
+			Number:   proto.Int32(int32(def.Tag())),
+		}
+
+		if def.IsGroup() {
+			groupName := fullName + "." + def.Name() + groupSuffix
+			group, err := messageDescriptor(groupName, def.Fields)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", def.Name(), err)
+			}
+			msg.NestedType = append(msg.NestedType, group)
+
+			field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+			field.TypeName = proto.String(groupName)
+		} else {
+			// Scalar fields track their presence in a synthetic oneof.
+			field.Label = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+			field.Type = fieldType(def.Type).Enum()
+			field.Proto3Optional = proto.Bool(true)
+			field.OneofIndex = proto.Int32(int32(len(msg.OneofDecl)))
+			msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String("_" + def.Name())})
+		}
+
+		msg.Field = append(msg.Field, field)
+	}
+
+	return msg, nil
+}
+
+// fieldType returns the protobuf type of a field of FIX type fixType.
+func fieldType(fixType string) descriptorpb.FieldDescriptorProto_Type {
+	switch fixType {
+	case "INT", "LENGTH", "NUMINGROUP", "SEQNUM", "TAGNUM", "DAYOFMONTH":
+		return descriptorpb.FieldDescriptorProto_TYPE_INT64
+	case "BOOLEAN":
+		return descriptorpb.FieldDescriptorProto_TYPE_BOOL
+	case "DATA":
+		return descriptorpb.FieldDescriptorProto_TYPE_BYTES
+	}
+
+	return descriptorpb.FieldDescriptorProto_TYPE_STRING
+}
+
+// Proto returns the .proto source of the file described by file.
+func Proto(file *descriptorpb.FileDescriptorProto) []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by quickfix. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "syntax = %q;\n\npackage %v;\n", file.GetSyntax(), file.GetPackage())
+	if goPackage := file.GetOptions().GetGoPackage(); goPackage != "" {
+		fmt.Fprintf(&b, "\noption go_package = %q;\n", goPackage)
+	}
+
+	for _, msg := range file.MessageType {
+		b.WriteByte('\n')
+		writeMessage(&b, msg, "")
+	}
+
+	return b.Bytes()
+}
+
+func writeMessage(b *bytes.Buffer, msg *descriptorpb.DescriptorProto, indent string) {
+	fmt.Fprintf(b, "%vmessage %v {\n", indent, msg.GetName())
+	for _, field := range msg.Field {
+		label := "optional "
+		if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+			label = "repeated "
+		}
+
+		typeName := field.GetTypeName()[strings.LastIndexByte(field.GetTypeName(), '.')+1:]
+		if field.GetType() != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+			typeName = strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+		}
+
+		fmt.Fprintf(b, "%v  %v%v %v = %v;\n", indent, label, typeName, field.GetName(), field.GetNumber())
+	}
+
+	for _, nested := range msg.NestedType {
+		b.WriteByte('\n')
+		writeMessage(b, nested, indent+"  ")
+	}
+	fmt.Fprintf(b, "%v}\n", indent)
+}
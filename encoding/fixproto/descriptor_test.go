@@ -0,0 +1,108 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fixproto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+func parseDictionary(t *testing.T, path string) *datadictionary.DataDictionary {
+	dict, err := datadictionary.Parse(path)
+	require.Nil(t, err)
+
+	return dict
+}
+
+func TestDefaultPackage(t *testing.T) {
+	assert.Equal(t, "fix44", DefaultPackage(parseDictionary(t, "../../spec/FIX44.xml")))
+	assert.Equal(t, "fix50sp2", DefaultPackage(parseDictionary(t, "../../spec/FIX50SP2.xml")))
+}
+
+func TestDescriptor(t *testing.T) {
+	fileProto, err := Descriptor(nil, parseDictionary(t, "../../spec/FIX44.xml"), "fix44")
+	require.Nil(t, err)
+
+	file, err := protodesc.NewFile(fileProto, nil)
+	require.Nil(t, err)
+
+	order := file.Messages().ByName("NewOrderSingle")
+	require.NotNil(t, order)
+
+	var tests = []struct {
+		name   string
+		number protoreflect.FieldNumber
+		kind   protoreflect.Kind
+	}{
+		{"SenderCompID", 49, protoreflect.StringKind},
+		{"MsgSeqNum", 34, protoreflect.Int64Kind},
+		{"PossDupFlag", 43, protoreflect.BoolKind},
+		{"ClOrdID", 11, protoreflect.StringKind},
+		{"Price", 44, protoreflect.StringKind},
+		{"EncodedText", 355, protoreflect.BytesKind},
+	}
+
+	for _, test := range tests {
+		fd := order.Fields().ByName(protoreflect.Name(test.name))
+		require.NotNil(t, fd, test.name)
+		assert.Equal(t, test.number, fd.Number(), test.name)
+		assert.Equal(t, test.kind, fd.Kind(), test.name)
+		assert.True(t, fd.HasPresence(), test.name)
+	}
+
+	for _, tag := range []protoreflect.FieldNumber{tagBeginString, tagBodyLength, tagMsgType, 10} {
+		assert.Nil(t, order.Fields().ByNumber(tag))
+	}
+
+	parties := order.Fields().ByName("NoPartyIDs")
+	require.NotNil(t, parties)
+	assert.True(t, parties.IsList())
+	assert.Equal(t, protoreflect.FullName("fix44.NewOrderSingle.NoPartyIDsGroup"), parties.Message().FullName())
+	assert.Equal(t, protoreflect.FieldNumber(448), parties.Message().Fields().Get(0).Number())
+	assert.NotNil(t, parties.Message().Fields().ByName("NoPartySubIDs").Message())
+}
+
+func TestDescriptorFIXT(t *testing.T) {
+	fileProto, err := Descriptor(parseDictionary(t, "../../spec/FIXT11.xml"), parseDictionary(t, "../../spec/FIX50SP2.xml"), "fix50sp2")
+	require.Nil(t, err)
+
+	file, err := protodesc.NewFile(fileProto, nil)
+	require.Nil(t, err)
+
+	order := file.Messages().ByName("NewOrderSingle")
+	require.NotNil(t, order)
+	assert.NotNil(t, order.Fields().ByName("SenderCompID"))
+	assert.NotNil(t, order.Fields().ByName("ClOrdID"))
+}
+
+func TestProto(t *testing.T) {
+	fileProto, err := Descriptor(nil, parseDictionary(t, "../../spec/FIX44.xml"), "fix44")
+	require.Nil(t, err)
+
+	source := string(Proto(fileProto))
+	assert.Contains(t, source, "syntax = \"proto3\";\n\npackage fix44;\n")
+	assert.Contains(t, source, "\nmessage NewOrderSingle {\n")
+	assert.Contains(t, source, "\n  optional string ClOrdID = 11;\n")
+	assert.Contains(t, source, "\n  repeated NoPartyIDsGroup NoPartyIDs = 453;\n")
+	assert.Contains(t, source, "\n  message NoPartyIDsGroup {\n    optional string PartyID = 448;\n")
+	assert.Contains(t, source, "\n    repeated NoPartySubIDsGroup NoPartySubIDs = 802;\n")
+}
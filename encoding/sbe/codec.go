@@ -0,0 +1,578 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sbe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const tagMsgType quickfix.Tag = 35
+
+// ErrShortBuffer is returned when decoding a message that is not completely in the buffer.
+var ErrShortBuffer = errors.New("sbe: short buffer")
+
+// Decode decodes the SBE message at the start of data into msg, returning the number of bytes of the message. The
+// fields of the message are set by their ids as tags, the message fields of the FIX standard header in the Header of
+// msg, and MsgType(35) is set to the semanticType of the message template.
+//
+// Values are set as FIX values: enums by their encoded values, sets by the unsigned integer of their choices and
+// decimal composites of a mantissa and exponent as decimals. Optional fields with a null value are left out.
+func (s *Schema) Decode(data []byte, msg *quickfix.Message) (int, error) {
+	headerSize := s.Header.Size()
+	if len(data) < headerSize {
+		return 0, ErrShortBuffer
+	}
+
+	header := make(map[string]uint64, len(s.Header.Members))
+	for _, name := range []string{"blockLength", "templateId", "schemaId", "version"} {
+		v, err := s.readUint(s.Header.Member(name), data)
+		if err != nil {
+			return 0, fmt.Errorf("sbe: message header: %w", err)
+		}
+		header[name] = v
+	}
+
+	if int(header["schemaId"]) != s.ID {
+		return 0, fmt.Errorf("sbe: schema id %v, expected %v", header["schemaId"], s.ID)
+	}
+
+	tmpl, ok := s.MessageByID(int(header["templateId"]))
+	if !ok {
+		return 0, fmt.Errorf("sbe: unknown template id %v", header["templateId"])
+	}
+
+	msg.Header.Clear()
+	msg.Body.Clear()
+	msg.Trailer.Clear()
+	if tmpl.MsgType != "" {
+		msg.Header.SetString(tagMsgType, tmpl.MsgType)
+	}
+
+	d := decoder{schema: s, msg: msg}
+	rest, err := d.block(&msg.Body.FieldMap, &tmpl.Block, data[headerSize:], int(header["blockLength"]), true)
+	if err != nil {
+		return 0, fmt.Errorf("sbe: %v: %w", tmpl.Name, err)
+	}
+
+	return len(data) - len(rest), nil
+}
+
+type decoder struct {
+	schema *Schema
+	msg    *quickfix.Message
+}
+
+// block decodes block from data into fieldMap with the acting block length of the encoder, which may be longer than
+// the block of the schema if the message was extended. It returns the data after the block.
+func (d decoder) block(fieldMap *quickfix.FieldMap, block *Block, data []byte, blockLength int, top bool) ([]byte, error) {
+	if len(data) < blockLength {
+		return nil, ErrShortBuffer
+	}
+
+	for _, f := range block.Fields {
+		target := fieldMap
+		if top && quickfix.Tag(f.ID).IsHeader() {
+			target = &d.msg.Header.FieldMap
+		}
+
+		if f.Presence == Constant {
+			setValue(target, f.ID, f.ConstValue)
+			continue
+		}
+
+		// Fields added by a later version of the schema than that of the encoder.
+		if f.Offset+f.Type.Size() > blockLength {
+			continue
+		}
+
+		value, ok, err := d.schema.readValue(f.Type, f.Presence, data[f.Offset:])
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", f.Name, err)
+		}
+		if ok {
+			setValue(target, f.ID, value)
+		}
+	}
+	data = data[blockLength:]
+
+	for _, g := range block.Groups {
+		dimensionSize := g.Dimension.Size()
+		if len(data) < dimensionSize {
+			return nil, ErrShortBuffer
+		}
+
+		groupBlockLength, err := d.schema.readUint(g.Dimension.Member("blockLength"), data)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", g.Name, err)
+		}
+		numInGroup, err := d.schema.readUint(g.Dimension.Member("numInGroup"), data)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", g.Name, err)
+		}
+		data = data[dimensionSize:]
+
+		rg, rejErr := fieldMap.RepeatingGroup(quickfix.Tag(g.ID), GroupTemplate(g))
+		if rejErr != nil {
+			return nil, fmt.Errorf("%v: %w", g.Name, rejErr)
+		}
+		for i := uint64(0); i < numInGroup; i++ {
+			if data, err = d.block(&rg.Add().FieldMap, &g.Block, data, int(groupBlockLength), false); err != nil {
+				return nil, fmt.Errorf("%v: %w", g.Name, err)
+			}
+		}
+	}
+
+	for _, f := range block.Data {
+		lengthType, varData := f.Type.Member("length"), f.Type.Member("varData")
+		if len(data) < varData.Offset {
+			return nil, ErrShortBuffer
+		}
+
+		length, err := d.schema.readUint(lengthType, data)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", f.Name, err)
+		}
+		if uint64(len(data)-varData.Offset) < length {
+			return nil, ErrShortBuffer
+		}
+
+		if length > 0 && f.ID > 0 {
+			fieldMap.SetBytes(quickfix.Tag(f.ID), append([]byte(nil), data[varData.Offset:varData.Offset+int(length)]...))
+		}
+		data = data[varData.Offset+int(length):]
+	}
+
+	return data, nil
+}
+
+func setValue(fieldMap *quickfix.FieldMap, id int, value string) {
+	if id > 0 {
+		fieldMap.SetString(quickfix.Tag(id), value)
+	}
+}
+
+// GroupTemplate returns the GroupTemplate of the fields of a repeating group by their ids as tags.
+func GroupTemplate(g *Group) quickfix.GroupTemplate {
+	var template quickfix.GroupTemplate
+	for _, f := range g.Fields {
+		if f.ID > 0 {
+			template = append(template, quickfix.GroupElement(quickfix.Tag(f.ID)))
+		}
+	}
+	for _, nested := range g.Groups {
+		template = append(template, quickfix.NewRepeatingGroup(quickfix.Tag(nested.ID), GroupTemplate(nested)))
+	}
+	for _, f := range g.Data {
+		if f.ID > 0 {
+			template = append(template, quickfix.GroupElement(quickfix.Tag(f.ID)))
+		}
+	}
+
+	return template
+}
+
+// readValue reads a value of t from the start of data, returning false for a null optional value.
+func (s *Schema) readValue(t *Type, presence Presence, data []byte) (string, bool, error) {
+	if t.Kind == KindComposite {
+		return s.readDecimal(t, presence, data)
+	}
+
+	if t.Length != 1 {
+		if t.Primitive != "char" {
+			return "", false, fmt.Errorf("unsupported %v array", t.Primitive)
+		}
+
+		value := data[:t.Length]
+		if i := bytes.IndexByte(value, 0); i >= 0 {
+			value = value[:i]
+		}
+		return string(value), len(value) > 0 || presence != Optional, nil
+	}
+
+	value := readPrimitive(s.ByteOrder, t.Primitive, data)
+	if presence == Optional && value == nullValue(t) {
+		return "", false, nil
+	}
+
+	return value, true, nil
+}
+
+// readDecimal reads a composite of a mantissa and exponent as a decimal.
+func (s *Schema) readDecimal(t *Type, presence Presence, data []byte) (string, bool, error) {
+	mantissaType, exponentType := t.Member("mantissa"), t.Member("exponent")
+	if mantissaType == nil || exponentType == nil {
+		return "", false, fmt.Errorf("unsupported composite %v", t.Name)
+	}
+
+	mantissa := readPrimitive(s.ByteOrder, mantissaType.Primitive, data[mantissaType.Offset:])
+	if (presence == Optional || mantissaType.Presence == Optional) && mantissa == nullValue(mantissaType) {
+		return "", false, nil
+	}
+
+	exponent := exponentType.ConstValue
+	if exponentType.Presence != Constant {
+		exponent = readPrimitive(s.ByteOrder, exponentType.Primitive, data[exponentType.Offset:])
+	}
+
+	m, err := strconv.ParseInt(mantissa, 10, 64)
+	if err != nil {
+		return "", false, err
+	}
+	e, err := strconv.Atoi(exponent)
+	if err != nil {
+		return "", false, err
+	}
+
+	return decimal.New(m, int32(e)).String(), true, nil
+}
+
+// readUint reads an unsigned integer member of a composite from the start of the composite in data.
+func (s *Schema) readUint(t *Type, data []byte) (uint64, error) {
+	if t.Presence == Constant {
+		return strconv.ParseUint(t.ConstValue, 10, 64)
+	}
+
+	return strconv.ParseUint(readPrimitive(s.ByteOrder, t.Primitive, data[t.Offset:]), 10, 64)
+}
+
+func readPrimitive(order binary.ByteOrder, primitive string, data []byte) string {
+	switch primitive {
+	case "char":
+		return string(data[:1])
+	case "int8":
+		return strconv.FormatInt(int64(int8(data[0])), 10)
+	case "uint8":
+		return strconv.FormatUint(uint64(data[0]), 10)
+	case "int16":
+		return strconv.FormatInt(int64(int16(order.Uint16(data))), 10)
+	case "uint16":
+		return strconv.FormatUint(uint64(order.Uint16(data)), 10)
+	case "int32":
+		return strconv.FormatInt(int64(int32(order.Uint32(data))), 10)
+	case "uint32":
+		return strconv.FormatUint(uint64(order.Uint32(data)), 10)
+	case "int64":
+		return strconv.FormatInt(int64(order.Uint64(data)), 10)
+	case "uint64":
+		return strconv.FormatUint(order.Uint64(data), 10)
+	case "float":
+		return strconv.FormatFloat(float64(math.Float32frombits(order.Uint32(data))), 'f', -1, 32)
+	case "double":
+		return strconv.FormatFloat(math.Float64frombits(order.Uint64(data)), 'f', -1, 64)
+	}
+
+	return ""
+}
+
+// nullValue returns the null value of a single primitive value of t, as formatted by readPrimitive.
+func nullValue(t *Type) string {
+	if t.NullValue != "" {
+		if t.Primitive == "char" && len(t.NullValue) > 1 {
+			if v, err := strconv.ParseUint(t.NullValue, 0, 8); err == nil {
+				return string([]byte{byte(v)})
+			}
+		}
+		return t.NullValue
+	}
+
+	switch t.Primitive {
+	case "char":
+		return "\x00"
+	case "int8":
+		return strconv.Itoa(math.MinInt8)
+	case "uint8":
+		return strconv.Itoa(math.MaxUint8)
+	case "int16":
+		return strconv.Itoa(math.MinInt16)
+	case "uint16":
+		return strconv.Itoa(math.MaxUint16)
+	case "int32":
+		return strconv.Itoa(math.MinInt32)
+	case "uint32":
+		return strconv.FormatUint(math.MaxUint32, 10)
+	case "int64":
+		return strconv.FormatInt(math.MinInt64, 10)
+	case "uint64":
+		return strconv.FormatUint(math.MaxUint64, 10)
+	}
+
+	return "NaN"
+}
+
+// Encode encodes msg as an SBE message of the template of its MsgType(35). Fields are read from the Header and Body
+// of msg by their ids as tags, as values decoded by Decode.
+func (s *Schema) Encode(msg *quickfix.Message) ([]byte, error) {
+	msgType, rejErr := msg.Header.GetString(tagMsgType)
+	if rejErr != nil {
+		return nil, fmt.Errorf("sbe: %w", rejErr)
+	}
+
+	tmpl, ok := s.MessageByMsgType(msgType)
+	if !ok {
+		return nil, fmt.Errorf("sbe: no message template for MsgType %v", msgType)
+	}
+
+	buffer := make([]byte, s.Header.Size())
+	for name, value := range map[string]int{
+		"blockLength": tmpl.BlockLength,
+		"templateId":  tmpl.ID,
+		"schemaId":    s.ID,
+		"version":     s.Version,
+	} {
+		if err := s.writeUint(s.Header.Member(name), buffer, value); err != nil {
+			return nil, fmt.Errorf("sbe: message header: %w", err)
+		}
+	}
+
+	e := encoder{schema: s, msg: msg}
+	buffer, err := e.block(buffer, &msg.Body.FieldMap, &tmpl.Block, true)
+	if err != nil {
+		return nil, fmt.Errorf("sbe: %v: %w", tmpl.Name, err)
+	}
+
+	return buffer, nil
+}
+
+type encoder struct {
+	schema *Schema
+	msg    *quickfix.Message
+}
+
+// block appends block encoded from fieldMap to buffer.
+func (e encoder) block(buffer []byte, fieldMap *quickfix.FieldMap, block *Block, top bool) ([]byte, error) {
+	start := len(buffer)
+	buffer = append(buffer, make([]byte, block.BlockLength)...)
+
+	for _, f := range block.Fields {
+		if f.Presence == Constant {
+			continue
+		}
+
+		source := fieldMap
+		if top && quickfix.Tag(f.ID).IsHeader() {
+			source = &e.msg.Header.FieldMap
+		}
+
+		value, ok := "", false
+		if f.ID > 0 && source.Has(quickfix.Tag(f.ID)) {
+			v, err := source.GetString(quickfix.Tag(f.ID))
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", f.Name, err)
+			}
+			value, ok = v, true
+		}
+		if !ok && f.Presence == Required {
+			return nil, fmt.Errorf("%v: required field missing", f.Name)
+		}
+
+		if err := e.schema.writeValue(f.Type, buffer[start+f.Offset:], value, ok); err != nil {
+			return nil, fmt.Errorf("%v: %w", f.Name, err)
+		}
+	}
+
+	for _, g := range block.Groups {
+		rg, rejErr := fieldMap.RepeatingGroup(quickfix.Tag(g.ID), GroupTemplate(g))
+		if rejErr != nil {
+			return nil, fmt.Errorf("%v: %w", g.Name, rejErr)
+		}
+
+		dimension := make([]byte, g.Dimension.Size())
+		if err := e.schema.writeUint(g.Dimension.Member("blockLength"), dimension, g.BlockLength); err != nil {
+			return nil, fmt.Errorf("%v: %w", g.Name, err)
+		}
+		if err := e.schema.writeUint(g.Dimension.Member("numInGroup"), dimension, rg.Len()); err != nil {
+			return nil, fmt.Errorf("%v: %w", g.Name, err)
+		}
+		buffer = append(buffer, dimension...)
+
+		for i := 0; i < rg.Len(); i++ {
+			var err error
+			if buffer, err = e.block(buffer, &rg.Get(i).FieldMap, &g.Block, false); err != nil {
+				return nil, fmt.Errorf("%v: %w", g.Name, err)
+			}
+		}
+	}
+
+	for _, f := range block.Data {
+		var value []byte
+		if f.ID > 0 && fieldMap.Has(quickfix.Tag(f.ID)) {
+			var err error
+			if value, err = fieldMap.GetBytes(quickfix.Tag(f.ID)); err != nil {
+				return nil, fmt.Errorf("%v: %w", f.Name, err)
+			}
+		}
+
+		prefix := make([]byte, f.Type.Member("varData").Offset)
+		if err := e.schema.writeUint(f.Type.Member("length"), prefix, len(value)); err != nil {
+			return nil, fmt.Errorf("%v: %w", f.Name, err)
+		}
+		buffer = append(append(buffer, prefix...), value...)
+	}
+
+	return buffer, nil
+}
+
+// writeValue writes a value of t to the start of buffer, or the null value of t if the value is not present.
+func (s *Schema) writeValue(t *Type, buffer []byte, value string, present bool) error {
+	if t.Kind == KindComposite {
+		return s.writeDecimal(t, buffer, value, present)
+	}
+
+	if t.Length != 1 {
+		if t.Primitive != "char" {
+			return fmt.Errorf("unsupported %v array", t.Primitive)
+		}
+		if len(value) > t.Length {
+			return fmt.Errorf("value %q longer than %v", value, t.Length)
+		}
+
+		copy(buffer, value)
+		return nil
+	}
+
+	if !present {
+		return writePrimitive(s.ByteOrder, t.Primitive, buffer, nullValue(t))
+	}
+
+	if t.Kind == KindEnum && !validValue(t, value) {
+		return fmt.Errorf("invalid value %q", value)
+	}
+
+	return writePrimitive(s.ByteOrder, t.Primitive, buffer, value)
+}
+
+func validValue(t *Type, value string) bool {
+	for _, v := range t.ValidValues {
+		if v.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeDecimal writes a decimal as a composite of a mantissa and exponent.
+func (s *Schema) writeDecimal(t *Type, buffer []byte, value string, present bool) error {
+	mantissaType, exponentType := t.Member("mantissa"), t.Member("exponent")
+	if mantissaType == nil || exponentType == nil {
+		return fmt.Errorf("unsupported composite %v", t.Name)
+	}
+
+	if !present {
+		return writePrimitive(s.ByteOrder, mantissaType.Primitive, buffer[mantissaType.Offset:], nullValue(mantissaType))
+	}
+
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return err
+	}
+
+	exponent := d.Exponent()
+	if exponentType.Presence == Constant {
+		e, err := strconv.Atoi(exponentType.ConstValue)
+		if err != nil {
+			return err
+		}
+		exponent = int32(e)
+	}
+
+	mantissa := d.Shift(-exponent)
+	if !mantissa.IsInteger() {
+		return fmt.Errorf("value %v has more than %v decimal places", value, -exponent)
+	}
+
+	if err := writePrimitive(s.ByteOrder, mantissaType.Primitive, buffer[mantissaType.Offset:], mantissa.String()); err != nil {
+		return err
+	}
+	if exponentType.Presence == Constant {
+		return nil
+	}
+
+	return writePrimitive(s.ByteOrder, exponentType.Primitive, buffer[exponentType.Offset:], strconv.Itoa(int(exponent)))
+}
+
+// writeUint writes an unsigned integer member of a composite to the composite at the start of buffer.
+func (s *Schema) writeUint(t *Type, buffer []byte, value int) error {
+	if t.Presence == Constant {
+		return nil
+	}
+
+	return writePrimitive(s.ByteOrder, t.Primitive, buffer[t.Offset:], strconv.Itoa(value))
+}
+
+func writePrimitive(order binary.ByteOrder, primitive string, buffer []byte, value string) error {
+	switch primitive {
+	case "char":
+		if len(value) != 1 {
+			return fmt.Errorf("invalid char %q", value)
+		}
+		buffer[0] = value[0]
+		return nil
+	case "float", "double":
+		bitSize := 64
+		if primitive == "float" {
+			bitSize = 32
+		}
+		v, err := strconv.ParseFloat(value, bitSize)
+		if err != nil {
+			return err
+		}
+		if bitSize == 32 {
+			order.PutUint32(buffer, math.Float32bits(float32(v)))
+		} else {
+			order.PutUint64(buffer, math.Float64bits(v))
+		}
+		return nil
+	}
+
+	size := primitiveSizes[primitive]
+	var bits uint64
+	if primitive[0] == 'u' {
+		v, err := strconv.ParseUint(value, 10, size*8)
+		if err != nil {
+			return err
+		}
+		bits = v
+	} else {
+		v, err := strconv.ParseInt(value, 10, size*8)
+		if err != nil {
+			return err
+		}
+		bits = uint64(v)
+	}
+
+	switch size {
+	case 1:
+		buffer[0] = byte(bits)
+	case 2:
+		order.PutUint16(buffer, uint16(bits))
+	case 4:
+		order.PutUint32(buffer, uint32(bits))
+	case 8:
+		order.PutUint64(buffer, bits)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sbe
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func parseTestSchema(t *testing.T) *Schema {
+	s, err := ParseFile("testdata/orders.xml")
+	require.Nil(t, err)
+
+	return s
+}
+
+func newOrderSingle() *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(tagMsgType, "D").SetInt(quickfix.Tag(34), 12)
+	msg.Body.SetString(quickfix.Tag(11), "ORD1").
+		SetString(quickfix.Tag(54), "2").
+		SetString(quickfix.Tag(44), "101.25").
+		SetString(quickfix.Tag(38), "1.5").
+		SetInt(quickfix.Tag(18), 3).
+		SetString(quickfix.Tag(58), "a note")
+
+	parties := quickfix.NewRepeatingGroup(quickfix.Tag(453),
+		quickfix.GroupTemplate{quickfix.GroupElement(quickfix.Tag(448)), quickfix.GroupElement(quickfix.Tag(452))})
+	parties.Add().SetString(quickfix.Tag(448), "FIRM").SetInt(quickfix.Tag(452), 1)
+	parties.Add().SetString(quickfix.Tag(448), "CLIENT").SetInt(quickfix.Tag(452), 3)
+	msg.Body.SetGroup(parties)
+
+	return msg
+}
+
+func TestEncodeDecode(t *testing.T) {
+	s := parseTestSchema(t)
+
+	data, err := s.Encode(newOrderSingle())
+	require.Nil(t, err)
+
+	// Header 8, block 37, group dimension 3 and 2 blocks of 9, data length 2 and 6 bytes.
+	require.Len(t, data, 8+37+3+18+2+6)
+	assert.Equal(t, uint16(37), binary.LittleEndian.Uint16(data[0:]))
+	assert.Equal(t, uint16(1), binary.LittleEndian.Uint16(data[2:]))
+	assert.Equal(t, uint16(7), binary.LittleEndian.Uint16(data[4:]))
+	assert.Equal(t, uint16(1), binary.LittleEndian.Uint16(data[6:]))
+	assert.Equal(t, int64(1012500), int64(binary.LittleEndian.Uint64(data[8+19:])))
+
+	msg := quickfix.NewMessage()
+	n, err := s.Decode(append(data, 0xff), msg)
+	require.Nil(t, err)
+	assert.Equal(t, len(data), n)
+
+	var tests = []struct {
+		fieldMap *quickfix.FieldMap
+		tag      quickfix.Tag
+		expected string
+	}{
+		{&msg.Header.FieldMap, 35, "D"},
+		{&msg.Header.FieldMap, 34, "12"},
+		{&msg.Body.FieldMap, 11, "ORD1"},
+		{&msg.Body.FieldMap, 54, "2"},
+		{&msg.Body.FieldMap, 44, "101.25"},
+		{&msg.Body.FieldMap, 38, "1.5"},
+		{&msg.Body.FieldMap, 18, "3"},
+		{&msg.Body.FieldMap, 40, "2"},
+		{&msg.Body.FieldMap, 58, "a note"},
+	}
+	for _, test := range tests {
+		value, err := test.fieldMap.GetString(test.tag)
+		require.Nil(t, err, test.tag)
+		assert.Equal(t, test.expected, value, test.tag)
+	}
+
+	// Optional fields with null values.
+	assert.False(t, msg.Body.Has(quickfix.Tag(55)))
+	assert.False(t, msg.Body.Has(quickfix.Tag(110)))
+
+	assert.Contains(t, msg.String(),
+		"\x0135=D\x0134=12\x0111=ORD1\x0118=3\x0138=1.5\x0140=2\x0144=101.25\x0154=2\x0158=a note\x01"+
+			"453=2\x01448=FIRM\x01452=1\x01448=CLIENT\x01452=3\x01")
+
+	encoded, err := s.Encode(msg)
+	require.Nil(t, err)
+	assert.Equal(t, data, encoded)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	s := parseTestSchema(t)
+	data, err := s.Encode(newOrderSingle())
+	require.Nil(t, err)
+
+	_, err = s.Decode(data[:len(data)-1], quickfix.NewMessage())
+	assert.Equal(t, ErrShortBuffer, unwrapAll(err))
+
+	_, err = s.Decode(data[:4], quickfix.NewMessage())
+	assert.Equal(t, ErrShortBuffer, err)
+
+	unknownTemplate := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint16(unknownTemplate[2:], 99)
+	_, err = s.Decode(unknownTemplate, quickfix.NewMessage())
+	assert.NotNil(t, err)
+
+	otherSchema := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint16(otherSchema[4:], 8)
+	_, err = s.Decode(otherSchema, quickfix.NewMessage())
+	assert.NotNil(t, err)
+}
+
+func unwrapAll(err error) error {
+	for {
+		unwrapped, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return err
+		}
+		err = unwrapped.Unwrap()
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	s := parseTestSchema(t)
+
+	var tests = []struct {
+		name   string
+		modify func(*quickfix.Message)
+	}{
+		{"unknown MsgType", func(m *quickfix.Message) { m.Header.SetString(tagMsgType, "8") }},
+		{"required field missing", func(m *quickfix.Message) { m.Body.Remove(quickfix.Tag(44)) }},
+		{"invalid enum value", func(m *quickfix.Message) { m.Body.SetString(quickfix.Tag(54), "3") }},
+		{"too many decimal places", func(m *quickfix.Message) { m.Body.SetString(quickfix.Tag(44), "1.00001") }},
+		{"value too long", func(m *quickfix.Message) { m.Body.SetString(quickfix.Tag(11), "ORDER0001") }},
+		{"integer out of range", func(m *quickfix.Message) { m.Body.SetInt(quickfix.Tag(18), 256) }},
+	}
+
+	for _, test := range tests {
+		msg := newOrderSingle()
+		test.modify(msg)
+
+		_, err := s.Encode(msg)
+		assert.NotNil(t, err, test.name)
+	}
+}
@@ -0,0 +1,431 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sbe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"text/template"
+	"unicode"
+)
+
+// goTypes are the Go types of the primitive types of SBE.
+var goTypes = map[string]string{
+	"char":   "byte",
+	"int8":   "int8",
+	"uint8":  "uint8",
+	"int16":  "int16",
+	"uint16": "uint16",
+	"int32":  "int32",
+	"uint32": "uint32",
+	"int64":  "int64",
+	"uint64": "uint64",
+	"float":  "float32",
+	"double": "float64",
+}
+
+// Generate returns the Go source of a package named packageName with codecs for the messages of schema. For each
+// message and repeating group it generates a flyweight type over the bytes of its block, with getters and setters of
+// its fixed size fields, and for each message a constructor of a buffer holding the message header and block. Members
+// of composite fields are accessed by the field name followed by the member name. Enums are generated as named types
+// with a constant per valid value.
+//
+// Repeating groups and variable length data fields are not accessed by the generated code, but encoded and decoded
+// with Schema.Encode and Schema.Decode.
+func Generate(schema *Schema, packageName string) ([]byte, error) {
+	g := genPackage{
+		Package:       packageName,
+		SchemaID:      schema.ID,
+		SchemaVersion: schema.Version,
+		ByteOrder:     "LittleEndian",
+		HeaderSize:    schema.Header.Size(),
+		enums:         make(map[string]bool),
+	}
+	if schema.ByteOrder == binary.BigEndian {
+		g.ByteOrder = "BigEndian"
+	}
+
+	for _, name := range []string{"blockLength", "templateId", "schemaId", "version"} {
+		member := schema.Header.Member(name)
+		if member.Presence == Constant {
+			continue
+		}
+		g.HeaderFields = append(g.HeaderFields, genAccessor{
+			Name: exportedName(name), GoType: goTypes[member.Primitive], Offset: member.Offset,
+			Size: primitiveSizes[member.Primitive],
+		})
+	}
+
+	for _, msg := range schema.Messages {
+		block, err := g.block(msg.Name, &msg.Block)
+		if err != nil {
+			return nil, fmt.Errorf("message %v: %w", msg.Name, err)
+		}
+
+		g.Messages = append(g.Messages, genMessage{
+			Name: exportedName(msg.Name), TemplateID: msg.ID, MsgType: msg.MsgType, Block: block,
+		})
+	}
+	sort.Slice(g.Enums, func(i, j int) bool { return g.Enums[i].Name < g.Enums[j].Name })
+
+	var source bytes.Buffer
+	if err := generateTemplate.Execute(&source, g); err != nil {
+		return nil, err
+	}
+
+	return format.Source(source.Bytes())
+}
+
+type genPackage struct {
+	Package       string
+	SchemaID      int
+	SchemaVersion int
+	ByteOrder     string
+	HeaderSize    int
+	HeaderFields  []genAccessor
+	Messages      []genMessage
+	Blocks        []genBlock
+	Enums         []genEnum
+
+	enums map[string]bool
+}
+
+type genMessage struct {
+	Name       string
+	TemplateID int
+	MsgType    string
+	Block      genBlock
+}
+
+type genBlock struct {
+	Name        string
+	BlockLength int
+	Accessors   []genAccessor
+}
+
+// genAccessor is the getter and setter of a single primitive, or an array of chars as a string.
+type genAccessor struct {
+	Name   string
+	GoType string
+	Offset int
+	Size   int
+
+	// Length is the length of a char array.
+	Length int
+}
+
+type genEnum struct {
+	Name   string
+	GoType string
+	Values []genEnumValue
+}
+
+type genEnumValue struct {
+	Name    string
+	Literal string
+}
+
+func (g *genPackage) block(name string, block *Block) (genBlock, error) {
+	b := genBlock{Name: exportedName(name), BlockLength: block.BlockLength}
+	for _, f := range block.Fields {
+		if f.Presence == Constant {
+			continue
+		}
+
+		accessors, err := g.accessors(exportedName(f.Name), f.Type, f.Offset)
+		if err != nil {
+			return b, fmt.Errorf("field %v: %w", f.Name, err)
+		}
+		b.Accessors = append(b.Accessors, accessors...)
+	}
+	g.Blocks = append(g.Blocks, b)
+
+	for _, group := range block.Groups {
+		if _, err := g.block(name+exportedName(group.Name), &group.Block); err != nil {
+			return b, fmt.Errorf("group %v: %w", group.Name, err)
+		}
+	}
+
+	return b, nil
+}
+
+func (g *genPackage) accessors(name string, t *Type, offset int) ([]genAccessor, error) {
+	if t.Presence == Constant {
+		return nil, nil
+	}
+
+	switch t.Kind {
+	case KindComposite:
+		var accessors []genAccessor
+		for _, member := range t.Members {
+			a, err := g.accessors(name+exportedName(member.Name), member, offset+member.Offset)
+			if err != nil {
+				return nil, err
+			}
+			accessors = append(accessors, a...)
+		}
+		return accessors, nil
+
+	case KindEnum:
+		if err := g.enum(t); err != nil {
+			return nil, err
+		}
+		return []genAccessor{{
+			Name: name, GoType: exportedName(t.Name), Offset: offset, Size: primitiveSizes[t.Primitive],
+		}}, nil
+	}
+
+	if t.Length == 1 {
+		return []genAccessor{{
+			Name: name, GoType: goTypes[t.Primitive], Offset: offset, Size: primitiveSizes[t.Primitive],
+		}}, nil
+	}
+
+	if t.Primitive != "char" {
+		return nil, fmt.Errorf("unsupported %v array", t.Primitive)
+	}
+
+	return []genAccessor{{Name: name, GoType: "string", Offset: offset, Length: t.Length}}, nil
+}
+
+func (g *genPackage) enum(t *Type) error {
+	name := exportedName(t.Name)
+	if g.enums[name] {
+		return nil
+	}
+	g.enums[name] = true
+
+	e := genEnum{Name: name, GoType: goTypes[t.Primitive]}
+	for _, v := range t.ValidValues {
+		literal := v.Value
+		if t.Primitive == "char" {
+			if len(v.Value) != 1 {
+				return fmt.Errorf("enum %v: invalid char %q", t.Name, v.Value)
+			}
+			literal = strconv.QuoteRune(rune(v.Value[0]))
+		}
+		e.Values = append(e.Values, genEnumValue{Name: name + exportedName(v.Name), Literal: literal})
+	}
+	g.Enums = append(g.Enums, e)
+
+	return nil
+}
+
+// exportedName returns name with its first letter upper-cased.
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// getter returns the expression reading a primitive of size from b at offset as goType.
+func getter(a genAccessor) string {
+	read := fmt.Sprintf("b[%v]", a.Offset)
+	switch a.Size {
+	case 2:
+		read = fmt.Sprintf("byteOrder.Uint16(b[%v:])", a.Offset)
+	case 4:
+		read = fmt.Sprintf("byteOrder.Uint32(b[%v:])", a.Offset)
+	case 8:
+		read = fmt.Sprintf("byteOrder.Uint64(b[%v:])", a.Offset)
+	}
+
+	switch a.GoType {
+	case "float32":
+		return "math.Float32frombits(" + read + ")"
+	case "float64":
+		return "math.Float64frombits(" + read + ")"
+	}
+
+	return a.GoType + "(" + read + ")"
+}
+
+// setter returns the statement writing v to b at offset.
+func setter(a genAccessor) string {
+	value := "v"
+	switch a.GoType {
+	case "float32":
+		value = "math.Float32bits(v)"
+	case "float64":
+		value = "math.Float64bits(v)"
+	}
+
+	switch a.Size {
+	case 2:
+		return fmt.Sprintf("byteOrder.PutUint16(b[%v:], uint16(%v))", a.Offset, value)
+	case 4:
+		return fmt.Sprintf("byteOrder.PutUint32(b[%v:], uint32(%v))", a.Offset, value)
+	case 8:
+		return fmt.Sprintf("byteOrder.PutUint64(b[%v:], uint64(%v))", a.Offset, value)
+	}
+
+	return fmt.Sprintf("b[%v] = byte(%v)", a.Offset, value)
+}
+
+// headerValue returns the constant set as the header field of a message.
+func headerValue(message, field string) string {
+	switch field {
+	case "BlockLength":
+		return message + "BlockLength"
+	case "TemplateId":
+		return message + "TemplateID"
+	case "SchemaId":
+		return "SchemaID"
+	}
+
+	return "SchemaVersion"
+}
+
+func usesMath(g genPackage) bool {
+	return hasAccessor(g, func(a genAccessor) bool { return a.GoType == "float32" || a.GoType == "float64" })
+}
+
+func usesBytes(g genPackage) bool {
+	return hasAccessor(g, func(a genAccessor) bool { return a.Length > 0 })
+}
+
+func hasAccessor(g genPackage, f func(genAccessor) bool) bool {
+	for _, b := range g.Blocks {
+		for _, a := range b.Accessors {
+			if f(a) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+var generateTemplate = template.Must(template.New("sbe").Funcs(template.FuncMap{
+	"getter":      getter,
+	"setter":      setter,
+	"usesMath":    usesMath,
+	"usesBytes":   usesBytes,
+	"headerValue": headerValue,
+	"quote":       strconv.Quote,
+}).Parse(`// Code generated by sbe.Generate. DO NOT EDIT.
+
+// Package {{.Package}} encodes and decodes the messages of SBE schema {{.SchemaID}} version {{.SchemaVersion}}.
+package {{.Package}}
+
+import (
+{{- if usesBytes .}}
+	"bytes"
+{{- end}}
+	"encoding/binary"
+{{- if usesMath .}}
+	"math"
+{{- end}}
+)
+
+var byteOrder = binary.{{.ByteOrder}}
+
+const (
+	// SchemaID is the id of the schema.
+	SchemaID = {{.SchemaID}}
+
+	// SchemaVersion is the version of the schema.
+	SchemaVersion = {{.SchemaVersion}}
+
+	// MessageHeaderSize is the size of the message header.
+	MessageHeaderSize = {{.HeaderSize}}
+)
+
+// MessageHeader is the message header preceding the block of each message.
+type MessageHeader []byte
+{{range .HeaderFields}}
+// {{.Name}} returns {{.Name}} of the header.
+func (b MessageHeader) {{.Name}}() {{.GoType}} { return {{getter .}} }
+
+// Set{{.Name}} sets {{.Name}} of the header.
+func (b MessageHeader) Set{{.Name}}(v {{.GoType}}) { {{setter .}} }
+{{end}}
+{{- range .Enums}}
+{{$enum := .}}
+// {{.Name}} is an enum of the schema.
+type {{.Name}} {{.GoType}}
+
+// The values of {{.Name}}.
+const (
+{{- range .Values}}
+	{{.Name}} {{$enum.Name}} = {{.Literal}}
+{{- end}}
+)
+{{- end}}
+
+{{range .Messages}}
+const (
+	// {{.Name}}TemplateID is the template id of {{.Name}}.
+	{{.Name}}TemplateID = {{.TemplateID}}
+{{- if .MsgType}}
+
+	// {{.Name}}MsgType is the FIX MsgType of {{.Name}}.
+	{{.Name}}MsgType = {{quote .MsgType}}
+{{- end}}
+)
+
+// New{{.Name}} returns a buffer holding the message header and an empty block of a {{.Name}} message, and the block.
+func New{{.Name}}() ([]byte, {{.Name}}Block) {
+	buffer := make([]byte, MessageHeaderSize+{{.Name}}BlockLength)
+	header := MessageHeader(buffer)
+{{- $msg := .}}
+{{- range $.HeaderFields}}
+	header.Set{{.Name}}({{.GoType}}({{headerValue $msg.Name .Name}}))
+{{- end}}
+	return buffer, {{.Name}}Block(buffer[MessageHeaderSize:])
+}
+{{end}}
+{{- range .Blocks}}
+{{$block := .}}
+// {{.Name}}BlockLength is the length of the block of {{.Name}}.
+const {{.Name}}BlockLength = {{.BlockLength}}
+
+// {{.Name}}Block is the block of {{.Name}}.
+type {{.Name}}Block []byte
+{{range .Accessors}}
+{{- if .Length}}
+// {{.Name}} returns {{.Name}}, up to the first NUL.
+func (b {{$block.Name}}Block) {{.Name}}() string {
+	v := b[{{.Offset}}:{{.Offset}}+{{.Length}}]
+	if i := bytes.IndexByte(v, 0); i >= 0 {
+		v = v[:i]
+	}
+	return string(v)
+}
+
+// Set{{.Name}} sets {{.Name}}, truncated to {{.Length}} bytes and padded with NULs.
+func (b {{$block.Name}}Block) Set{{.Name}}(v string) {
+	n := copy(b[{{.Offset}}:{{.Offset}}+{{.Length}}], v)
+	clear(b[{{.Offset}}+n:{{.Offset}}+{{.Length}}])
+}
+{{- else}}
+// {{.Name}} returns {{.Name}}.
+func (b {{$block.Name}}Block) {{.Name}}() {{.GoType}} { return {{getter .}} }
+
+// Set{{.Name}} sets {{.Name}}.
+func (b {{$block.Name}}Block) Set{{.Name}}(v {{.GoType}}) { {{setter .}} }
+{{- end}}
+{{end}}
+{{- end}}
+`))
@@ -0,0 +1,61 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sbe
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	source, err := Generate(parseTestSchema(t), "orders")
+	require.Nil(t, err)
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "orders.go", source, 0)
+	require.Nil(t, err)
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("orders", fset, []*ast.File{file}, nil)
+	require.Nil(t, err, string(source))
+
+	for _, name := range []string{
+		"SchemaID", "MessageHeaderSize", "MessageHeader", "Side", "SideBuy", "PartyRoleClientID",
+		"NewOrderSingleTemplateID", "NewOrderSingleMsgType", "NewNewOrderSingle", "NewOrderSingleBlock",
+		"NewOrderSinglePartiesBlock", "HeartbeatBlock",
+	} {
+		assert.NotNil(t, pkg.Scope().Lookup(name), name)
+	}
+
+	block := pkg.Scope().Lookup("NewOrderSingleBlock").Type()
+	methods := types.NewMethodSet(block)
+	for _, name := range []string{
+		"MsgSeqNum", "SetMsgSeqNum", "ClOrdID", "SetClOrdID", "Side", "PriceMantissa", "OrderQtyMantissa",
+		"OrderQtyExponent", "MinQty",
+	} {
+		assert.NotNil(t, methods.Lookup(pkg, name), name)
+	}
+	// Constant members and fields have no accessors.
+	assert.Nil(t, methods.Lookup(pkg, "PriceExponent"))
+	assert.Nil(t, methods.Lookup(pkg, "OrdType"))
+}
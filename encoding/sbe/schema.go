@@ -0,0 +1,637 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package sbe encodes and decodes Simple Binary Encoding (SBE) messages described by SBE XML message schemas,
+// converting them to and from quickfix Messages, and generates Go codecs from the schemas.
+package sbe
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of an encoding type of a schema.
+type Kind int
+
+// The kinds of encoding types.
+const (
+	KindPrimitive Kind = iota
+	KindComposite
+	KindEnum
+	KindSet
+)
+
+// Presence is the presence of a field or encoding type.
+type Presence int
+
+// The presences of fields and encoding types.
+const (
+	Required Presence = iota
+	Optional
+	Constant
+)
+
+// primitiveSizes are the sizes of the primitive types of SBE.
+var primitiveSizes = map[string]int{
+	"char":   1,
+	"int8":   1,
+	"uint8":  1,
+	"int16":  2,
+	"uint16": 2,
+	"int32":  4,
+	"uint32": 4,
+	"int64":  8,
+	"uint64": 8,
+	"float":  4,
+	"double": 8,
+}
+
+// Type is an encoding type of a schema.
+type Type struct {
+	Name string
+	Kind Kind
+
+	// Primitive is the primitive type of a primitive type, or the encoding type of an enum or set.
+	Primitive string
+
+	// Length is the number of elements of an array of Primitive, 1 for a single value. A length of 0 is a variable
+	// length, as used by the varData member of a data encoding.
+	Length int
+
+	Presence Presence
+
+	// NullValue is the null value of an optional primitive type, if not the default of its primitive type.
+	NullValue string
+
+	// ConstValue is the value of a constant primitive type.
+	ConstValue string
+
+	// Offset is the offset of a member within its composite.
+	Offset int
+
+	// Members are the members of a composite.
+	Members []*Type
+
+	// ValidValues are the values of an enum, or the bit positions of the choices of a set.
+	ValidValues []ValidValue
+}
+
+// ValidValue is a value of an enum or a choice of a set.
+type ValidValue struct {
+	Name  string
+	Value string
+}
+
+// Size returns the number of bytes of the type in a block.
+func (t *Type) Size() int {
+	switch {
+	case t.Presence == Constant:
+		return 0
+	case t.Kind == KindComposite:
+		size := 0
+		for _, member := range t.Members {
+			if end := member.Offset + member.Size(); end > size {
+				size = end
+			}
+		}
+		return size
+	}
+
+	return primitiveSizes[t.Primitive] * t.Length
+}
+
+// Member returns the member of a composite with name.
+func (t *Type) Member(name string) *Type {
+	for _, member := range t.Members {
+		if member.Name == name {
+			return member
+		}
+	}
+
+	return nil
+}
+
+// Field is a field of a message or repeating group.
+type Field struct {
+	Name string
+	ID   int
+	Type *Type
+
+	// Offset is the offset of a fixed size field within its block. It is not used by data fields.
+	Offset int
+
+	Presence Presence
+
+	// ConstValue is the value of a constant field.
+	ConstValue string
+}
+
+// Block is the fixed size fields, repeating groups and variable length data fields of a message or repeating group.
+type Block struct {
+	BlockLength int
+	Fields      []*Field
+	Groups      []*Group
+	Data        []*Field
+}
+
+// Group is a repeating group.
+type Group struct {
+	Name string
+	ID   int
+
+	// Dimension is the composite encoding the block length and number of instances of the group.
+	Dimension *Type
+
+	Block
+}
+
+// Message is a message template of a schema.
+type Message struct {
+	Name string
+	ID   int
+
+	// MsgType is the FIX MsgType(35) of the message, from its semanticType.
+	MsgType string
+
+	Block
+}
+
+// Schema is an SBE message schema.
+type Schema struct {
+	Package   string
+	ID        int
+	Version   int
+	ByteOrder binary.ByteOrder
+
+	// Header is the composite of the message header.
+	Header *Type
+
+	Types    map[string]*Type
+	Messages []*Message
+
+	messagesByID      map[int]*Message
+	messagesByMsgType map[string]*Message
+}
+
+// MessageByID returns the message template with id.
+func (s *Schema) MessageByID(id int) (*Message, bool) {
+	msg, ok := s.messagesByID[id]
+	return msg, ok
+}
+
+// MessageByMsgType returns the message template of the FIX MsgType.
+func (s *Schema) MessageByMsgType(msgType string) (*Message, bool) {
+	msg, ok := s.messagesByMsgType[msgType]
+	return msg, ok
+}
+
+type xmlSchema struct {
+	Package    string       `xml:"package,attr"`
+	ID         string       `xml:"id,attr"`
+	Version    string       `xml:"version,attr"`
+	ByteOrder  string       `xml:"byteOrder,attr"`
+	HeaderType string       `xml:"headerType,attr"`
+	Types      []xmlTypes   `xml:"types"`
+	Messages   []xmlMessage `xml:"message"`
+}
+
+type xmlTypes struct {
+	Types []xmlType `xml:",any"`
+}
+
+// xmlType is a type, composite, enum, set or ref element.
+type xmlType struct {
+	XMLName       xml.Name
+	Name          string          `xml:"name,attr"`
+	PrimitiveType string          `xml:"primitiveType,attr"`
+	EncodingType  string          `xml:"encodingType,attr"`
+	Length        string          `xml:"length,attr"`
+	Presence      string          `xml:"presence,attr"`
+	NullValue     string          `xml:"nullValue,attr"`
+	Offset        string          `xml:"offset,attr"`
+	Type          string          `xml:"type,attr"`
+	Value         string          `xml:",chardata"`
+	ValidValues   []xmlValidValue `xml:"validValue"`
+	Choices       []xmlValidValue `xml:"choice"`
+	Members       []xmlType       `xml:",any"`
+}
+
+type xmlValidValue struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlMessage struct {
+	Name         string `xml:"name,attr"`
+	ID           string `xml:"id,attr"`
+	SemanticType string `xml:"semanticType,attr"`
+	xmlBlock
+}
+
+type xmlBlock struct {
+	BlockLength string     `xml:"blockLength,attr"`
+	Fields      []xmlField `xml:"field"`
+	Groups      []xmlGroup `xml:"group"`
+	Data        []xmlField `xml:"data"`
+}
+
+type xmlGroup struct {
+	Name          string `xml:"name,attr"`
+	ID            string `xml:"id,attr"`
+	DimensionType string `xml:"dimensionType,attr"`
+	xmlBlock
+}
+
+type xmlField struct {
+	Name     string `xml:"name,attr"`
+	ID       string `xml:"id,attr"`
+	Type     string `xml:"type,attr"`
+	Offset   string `xml:"offset,attr"`
+	Presence string `xml:"presence,attr"`
+	ValueRef string `xml:"valueRef,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// ParseFile parses the SBE message schema at path.
+func ParseFile(path string) (*Schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse parses an SBE message schema.
+func Parse(r io.Reader) (*Schema, error) {
+	var doc xmlSchema
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	b := schemaBuilder{
+		doc:   doc,
+		raw:   make(map[string]xmlType),
+		types: make(map[string]*Type),
+	}
+
+	return b.build()
+}
+
+type schemaBuilder struct {
+	doc   xmlSchema
+	raw   map[string]xmlType
+	types map[string]*Type
+}
+
+func (b *schemaBuilder) build() (s *Schema, err error) {
+	s = &Schema{
+		Package:           b.doc.Package,
+		Types:             b.types,
+		messagesByID:      make(map[int]*Message),
+		messagesByMsgType: make(map[string]*Message),
+	}
+
+	if s.ID, err = atoiAttr(b.doc.ID, 0); err != nil {
+		return nil, fmt.Errorf("messageSchema id: %w", err)
+	}
+	if s.Version, err = atoiAttr(b.doc.Version, 0); err != nil {
+		return nil, fmt.Errorf("messageSchema version: %w", err)
+	}
+
+	switch b.doc.ByteOrder {
+	case "", "littleEndian":
+		s.ByteOrder = binary.LittleEndian
+	case "bigEndian":
+		s.ByteOrder = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("unknown byteOrder %q", b.doc.ByteOrder)
+	}
+
+	for _, types := range b.doc.Types {
+		for _, t := range types.Types {
+			b.raw[t.Name] = t
+		}
+	}
+
+	for name := range b.raw {
+		if _, err := b.namedType(name); err != nil {
+			return nil, err
+		}
+	}
+
+	headerType := b.doc.HeaderType
+	if headerType == "" {
+		headerType = "messageHeader"
+	}
+	if s.Header, err = b.namedType(headerType); err != nil {
+		return nil, err
+	}
+	for _, member := range []string{"blockLength", "templateId", "schemaId", "version"} {
+		if s.Header.Member(member) == nil {
+			return nil, fmt.Errorf("%v: missing %v", headerType, member)
+		}
+	}
+
+	for _, m := range b.doc.Messages {
+		msg := &Message{Name: m.Name, MsgType: m.SemanticType}
+		if msg.ID, err = atoiAttr(m.ID, -1); err != nil || msg.ID < 0 {
+			return nil, fmt.Errorf("message %v: invalid id %q", m.Name, m.ID)
+		}
+
+		if msg.Block, err = b.block(m.xmlBlock); err != nil {
+			return nil, fmt.Errorf("message %v: %w", m.Name, err)
+		}
+
+		s.Messages = append(s.Messages, msg)
+		s.messagesByID[msg.ID] = msg
+		if msg.MsgType != "" {
+			s.messagesByMsgType[msg.MsgType] = msg
+		}
+	}
+
+	return s, nil
+}
+
+// namedType returns the type declared with name in the types of the schema, building it on first use.
+func (b *schemaBuilder) namedType(name string) (*Type, error) {
+	if t, ok := b.types[name]; ok {
+		if t == nil {
+			return nil, fmt.Errorf("type %v refers to itself", name)
+		}
+		return t, nil
+	}
+
+	if t, ok := primitiveType(name); ok {
+		return t, nil
+	}
+
+	raw, ok := b.raw[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %v", name)
+	}
+
+	b.types[name] = nil
+	t, err := b.buildType(raw)
+	if err != nil {
+		delete(b.types, name)
+		return nil, err
+	}
+	b.types[name] = t
+
+	return t, nil
+}
+
+// primitiveType returns a required primitive type referred to by the name of the primitive.
+func primitiveType(name string) (*Type, bool) {
+	if _, ok := primitiveSizes[name]; !ok {
+		return nil, false
+	}
+
+	return &Type{Name: name, Kind: KindPrimitive, Primitive: name, Length: 1}, true
+}
+
+func (b *schemaBuilder) buildType(raw xmlType) (t *Type, err error) {
+	t = &Type{Name: raw.Name, Length: 1}
+	if t.Presence, err = presence(raw.Presence); err != nil {
+		return nil, fmt.Errorf("type %v: %w", raw.Name, err)
+	}
+
+	switch raw.XMLName.Local {
+	case "type":
+		t.Kind = KindPrimitive
+		t.Primitive = raw.PrimitiveType
+		if _, ok := primitiveSizes[t.Primitive]; !ok {
+			return nil, fmt.Errorf("type %v: unknown primitiveType %q", raw.Name, raw.PrimitiveType)
+		}
+		if t.Length, err = atoiAttr(raw.Length, 1); err != nil || t.Length < 0 {
+			return nil, fmt.Errorf("type %v: invalid length %q", raw.Name, raw.Length)
+		}
+		t.NullValue = raw.NullValue
+		if t.Presence == Constant {
+			t.ConstValue = strings.TrimSpace(raw.Value)
+		}
+
+	case "enum", "set":
+		t.Kind = KindEnum
+		values := raw.ValidValues
+		if raw.XMLName.Local == "set" {
+			t.Kind = KindSet
+			values = raw.Choices
+		}
+
+		encoding, err := b.namedType(raw.EncodingType)
+		if err != nil {
+			return nil, fmt.Errorf("%v %v: %w", raw.XMLName.Local, raw.Name, err)
+		}
+		if encoding.Kind != KindPrimitive || encoding.Length != 1 {
+			return nil, fmt.Errorf("%v %v: encodingType must be a single primitive", raw.XMLName.Local, raw.Name)
+		}
+		t.Primitive = encoding.Primitive
+		if t.Presence == Required {
+			t.Presence = encoding.Presence
+		}
+		t.NullValue = encoding.NullValue
+
+		for _, v := range values {
+			t.ValidValues = append(t.ValidValues, ValidValue{Name: v.Name, Value: strings.TrimSpace(v.Value)})
+		}
+
+	case "composite":
+		t.Kind = KindComposite
+		offset := 0
+		for _, m := range raw.Members {
+			member, err := b.member(m)
+			if err != nil {
+				return nil, fmt.Errorf("composite %v: %w", raw.Name, err)
+			}
+
+			if member.Offset, err = atoiAttr(m.Offset, offset); err != nil {
+				return nil, fmt.Errorf("composite %v: %v: invalid offset %q", raw.Name, m.Name, m.Offset)
+			}
+			offset = member.Offset + member.Size()
+
+			t.Members = append(t.Members, member)
+		}
+
+	case "ref":
+		return nil, fmt.Errorf("ref %v must be a member of a composite", raw.Name)
+
+	default:
+		return nil, fmt.Errorf("unknown type element %v", raw.XMLName.Local)
+	}
+
+	return t, nil
+}
+
+// member returns a member of a composite, which is either declared in the composite or refers to a declared type.
+func (b *schemaBuilder) member(raw xmlType) (*Type, error) {
+	if raw.XMLName.Local != "ref" {
+		return b.buildType(raw)
+	}
+
+	t, err := b.namedType(raw.Type)
+	if err != nil {
+		return nil, fmt.Errorf("ref %v: %w", raw.Name, err)
+	}
+
+	member := *t
+	member.Name = raw.Name
+	return &member, nil
+}
+
+func (b *schemaBuilder) block(raw xmlBlock) (block Block, err error) {
+	offset := 0
+	for _, f := range raw.Fields {
+		field, err := b.field(f)
+		if err != nil {
+			return block, err
+		}
+
+		if field.Offset, err = atoiAttr(f.Offset, offset); err != nil {
+			return block, fmt.Errorf("field %v: invalid offset %q", f.Name, f.Offset)
+		}
+		if field.Presence != Constant {
+			offset = field.Offset + field.Type.Size()
+		}
+
+		block.Fields = append(block.Fields, field)
+	}
+
+	if block.BlockLength, err = atoiAttr(raw.BlockLength, offset); err != nil || block.BlockLength < offset {
+		return block, fmt.Errorf("invalid blockLength %q", raw.BlockLength)
+	}
+
+	for _, g := range raw.Groups {
+		group := &Group{Name: g.Name}
+		if group.ID, err = atoiAttr(g.ID, 0); err != nil {
+			return block, fmt.Errorf("group %v: invalid id %q", g.Name, g.ID)
+		}
+
+		dimensionType := g.DimensionType
+		if dimensionType == "" {
+			dimensionType = "groupSizeEncoding"
+		}
+		if group.Dimension, err = b.namedType(dimensionType); err != nil {
+			return block, fmt.Errorf("group %v: %w", g.Name, err)
+		}
+		if group.Dimension.Member("blockLength") == nil || group.Dimension.Member("numInGroup") == nil {
+			return block, fmt.Errorf("group %v: %v must have blockLength and numInGroup", g.Name, dimensionType)
+		}
+
+		if group.Block, err = b.block(g.xmlBlock); err != nil {
+			return block, fmt.Errorf("group %v: %w", g.Name, err)
+		}
+
+		block.Groups = append(block.Groups, group)
+	}
+
+	for _, d := range raw.Data {
+		field, err := b.field(d)
+		if err != nil {
+			return block, err
+		}
+
+		if field.Type.Member("length") == nil || field.Type.Member("varData") == nil {
+			return block, fmt.Errorf("data %v: %v must have length and varData", d.Name, d.Type)
+		}
+
+		block.Data = append(block.Data, field)
+	}
+
+	return block, nil
+}
+
+func (b *schemaBuilder) field(raw xmlField) (f *Field, err error) {
+	f = &Field{Name: raw.Name}
+	if f.ID, err = atoiAttr(raw.ID, 0); err != nil {
+		return nil, fmt.Errorf("field %v: invalid id %q", raw.Name, raw.ID)
+	}
+
+	if f.Type, err = b.namedType(raw.Type); err != nil {
+		return nil, fmt.Errorf("field %v: %w", raw.Name, err)
+	}
+
+	if f.Presence, err = presence(raw.Presence); err != nil {
+		return nil, fmt.Errorf("field %v: %w", raw.Name, err)
+	}
+	if raw.Presence == "" {
+		f.Presence = f.Type.Presence
+	}
+
+	if f.Presence == Constant {
+		switch {
+		case raw.ValueRef != "":
+			// valueRef is <enum name>.<validValue name>.
+			f.ConstValue, err = b.enumValue(raw.ValueRef)
+		case strings.TrimSpace(raw.Value) != "":
+			f.ConstValue = strings.TrimSpace(raw.Value)
+		default:
+			f.ConstValue = f.Type.ConstValue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %w", raw.Name, err)
+		}
+	}
+
+	return f, nil
+}
+
+func (b *schemaBuilder) enumValue(ref string) (string, error) {
+	enumName, valueName, ok := strings.Cut(ref, ".")
+	if !ok {
+		return "", fmt.Errorf("invalid valueRef %q", ref)
+	}
+
+	t, err := b.namedType(enumName)
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range t.ValidValues {
+		if v.Name == valueName {
+			return v.Value, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown valueRef %q", ref)
+}
+
+func presence(s string) (Presence, error) {
+	switch s {
+	case "", "required":
+		return Required, nil
+	case "optional":
+		return Optional, nil
+	case "constant":
+		return Constant, nil
+	}
+
+	return Required, fmt.Errorf("unknown presence %q", s)
+}
+
+func atoiAttr(s string, def int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	return strconv.Atoi(s)
+}
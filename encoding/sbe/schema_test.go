@@ -0,0 +1,88 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sbe
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFile(t *testing.T) {
+	s, err := ParseFile("testdata/orders.xml")
+	require.Nil(t, err)
+
+	assert.Equal(t, "orders", s.Package)
+	assert.Equal(t, 7, s.ID)
+	assert.Equal(t, 1, s.Version)
+	assert.Equal(t, binary.LittleEndian, s.ByteOrder)
+	assert.Equal(t, 8, s.Header.Size())
+
+	msg, ok := s.MessageByMsgType("D")
+	require.True(t, ok)
+	byID, ok := s.MessageByID(1)
+	require.True(t, ok)
+	assert.Equal(t, msg, byID)
+
+	assert.Equal(t, "NewOrderSingle", msg.Name)
+	// MsgSeqNum 4, ClOrdID 8, Symbol 6, Side 1, Price 8, OrderQty 5, ExecInst 1, MinQty 4.
+	assert.Equal(t, 37, msg.BlockLength)
+
+	var offsets []int
+	for _, f := range msg.Fields {
+		offsets = append(offsets, f.Offset)
+	}
+	assert.Equal(t, []int{0, 4, 12, 18, 19, 27, 32, 33, 33}, offsets)
+
+	assert.Equal(t, Constant, msg.Fields[7].Presence)
+	assert.Equal(t, "2", msg.Fields[7].ConstValue)
+	assert.Equal(t, Optional, msg.Fields[2].Presence)
+
+	require.Len(t, msg.Groups, 1)
+	assert.Equal(t, 453, msg.Groups[0].ID)
+	assert.Equal(t, 9, msg.Groups[0].BlockLength)
+	assert.Equal(t, 3, msg.Groups[0].Dimension.Size())
+
+	require.Len(t, msg.Data, 1)
+	assert.Equal(t, 2, msg.Data[0].Type.Member("varData").Offset)
+}
+
+func TestParseErrors(t *testing.T) {
+	const header = `<composite name="messageHeader"><type name="blockLength" primitiveType="uint16"/>` +
+		`<type name="templateId" primitiveType="uint16"/><type name="schemaId" primitiveType="uint16"/>` +
+		`<type name="version" primitiveType="uint16"/></composite>`
+
+	var tests = []string{
+		`<messageSchema id="1" byteOrder="middleEndian"><types>` + header + `</types></messageSchema>`,
+		`<messageSchema id="1"><types></types></messageSchema>`,
+		`<messageSchema id="1"><types>` + header + `<type name="T" primitiveType="int128"/></types></messageSchema>`,
+		`<messageSchema id="1"><types>` + header + `</types><message name="M"/></messageSchema>`,
+		`<messageSchema id="1"><types>` + header + `</types>` +
+			`<message name="M" id="1"><field name="F" id="1" type="Unknown"/></message></messageSchema>`,
+		`<messageSchema id="1"><types>` + header + `</types>` +
+			`<message name="M" id="1"><group name="G" id="1"/></message></messageSchema>`,
+		`<messageSchema id="1"><types>` + header + `<composite name="A"><ref name="b" type="A"/></composite>` +
+			`</types></messageSchema>`,
+	}
+
+	for _, test := range tests {
+		_, err := Parse(strings.NewReader(test))
+		assert.NotNil(t, err, test)
+	}
+}
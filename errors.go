@@ -23,6 +23,15 @@ import (
 // ErrDoNotSend is a convenience error to indicate a DoNotSend in ToApp.
 var ErrDoNotSend = errors.New("Do Not Send")
 
+// ErrSessionQueueFull is returned when sending an application message through a session whose send queue is full.
+var ErrSessionQueueFull = errors.New("Session send queue is full")
+
+// ErrLoggingOut is returned when sending an application message through a session that is draining for logout.
+var ErrLoggingOut = errors.New("Session is logging out")
+
+// ErrLogoutTimeout is returned by LogoutAndDrain if the session did not complete the logout before the timeout.
+var ErrLogoutTimeout = errors.New("Timed out waiting for logout")
+
 // rejectReason enum values.
 const (
 	rejectReasonInvalidTagNumber                          = 0
@@ -0,0 +1,155 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EventType is the type of an Event.
+type EventType int
+
+const (
+	// EventConnect is published when a session connects to the counterparty.
+	EventConnect EventType = iota
+
+	// EventDisconnect is published when a session disconnects from the counterparty.
+	EventDisconnect
+
+	// EventLogon is published when a session logs on.
+	EventLogon
+
+	// EventLogout is published when a logged on session logs out or disconnects.
+	EventLogout
+
+	// EventReject is published when a session receives or sends a Reject(3) or BusinessMessageReject(j) for a message.
+	EventReject
+
+	// EventResend is published when a session receives or sends a ResendRequest.
+	EventResend
+
+	// EventSeqNumReset is published when the sequence numbers of a session are reset or set, or a SequenceReset-Reset is received.
+	EventSeqNumReset
+
+	// EventValidationWarning is published when a session accepts a message the Validator warns about, such as a value
+	// not among the enumerated values of a field with EnumWarn.
+	EventValidationWarning
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventConnect:
+		return "Connect"
+	case EventDisconnect:
+		return "Disconnect"
+	case EventLogon:
+		return "Logon"
+	case EventLogout:
+		return "Logout"
+	case EventReject:
+		return "Reject"
+	case EventResend:
+		return "Resend"
+	case EventSeqNumReset:
+		return "SeqNumReset"
+	case EventValidationWarning:
+		return "ValidationWarning"
+	}
+
+	return "Unknown"
+}
+
+// Event is a notification of the engine about a session.
+type Event struct {
+	Type      EventType
+	SessionID SessionID
+	Time      time.Time
+
+	// Text describes the event, e.g. the reason of a reject or the range of a resend.
+	Text string
+}
+
+type eventSubscriber struct {
+	events chan Event
+
+	// types are the EventTypes the subscriber receives, all types if empty.
+	types map[EventType]bool
+}
+
+// EventBus publishes the Events of sessions to subscribers. Set it on an Acceptor or Initiator with SetEventBus.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[*eventSubscriber]struct{}
+}
+
+// NewEventBus returns an EventBus without subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[*eventSubscriber]struct{})}
+}
+
+// Subscribe returns a channel receiving Events of types, or of all types if no types are given,
+// and a function that cancels the subscription and closes the channel.
+// Publishing does not block sessions, Events are dropped while the buffer of size buffer of the channel is full.
+func (b *EventBus) Subscribe(buffer int, types ...EventType) (<-chan Event, func()) {
+	subscriber := &eventSubscriber{events: make(chan Event, buffer)}
+	if len(types) > 0 {
+		subscriber.types = make(map[EventType]bool, len(types))
+		for _, t := range types {
+			subscriber.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return subscriber.events, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, subscriber)
+			b.mu.Unlock()
+			close(subscriber.events)
+		})
+	}
+}
+
+// Publish sends event to the subscribers of its type.
+func (b *EventBus) Publish(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for subscriber := range b.subscribers {
+		if subscriber.types != nil && !subscriber.types[event.Type] {
+			continue
+		}
+
+		select {
+		case subscriber.events <- event:
+		default:
+		}
+	}
+}
+
+func (s *session) publishEvent(t EventType, format string, a ...interface{}) {
+	if s.events == nil {
+		return
+	}
+
+	s.events.Publish(Event{Type: t, SessionID: s.sessionID, Time: s.clock.Now(), Text: fmt.Sprintf(format, a...)})
+}
@@ -0,0 +1,146 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestEventBusSubscribe(t *testing.T) {
+	bus := NewEventBus()
+	all, cancelAll := bus.Subscribe(10)
+	logons, cancelLogons := bus.Subscribe(10, EventLogon, EventLogout)
+	defer cancelLogons()
+
+	bus.Publish(Event{Type: EventConnect})
+	bus.Publish(Event{Type: EventLogon})
+
+	assert.Equal(t, EventConnect, (<-all).Type)
+	assert.Equal(t, EventLogon, (<-all).Type)
+	assert.Equal(t, EventLogon, (<-logons).Type)
+	assert.Len(t, logons, 0)
+
+	cancelAll()
+	cancelAll()
+	_, ok := <-all
+	assert.False(t, ok)
+
+	bus.Publish(Event{Type: EventLogout})
+	assert.Equal(t, EventLogout, (<-logons).Type)
+}
+
+func TestEventBusDropsWhenFull(t *testing.T) {
+	bus := NewEventBus()
+	events, cancel := bus.Subscribe(1)
+	defer cancel()
+
+	bus.Publish(Event{Type: EventConnect})
+	bus.Publish(Event{Type: EventDisconnect})
+
+	assert.Equal(t, EventConnect, (<-events).Type)
+	assert.Len(t, events, 0)
+}
+
+type EventBusTestSuite struct {
+	SessionSuiteRig
+	events <-chan Event
+}
+
+func TestEventBusTestSuite(t *testing.T) {
+	suite.Run(t, new(EventBusTestSuite))
+}
+
+func (s *EventBusTestSuite) SetupTest() {
+	s.Init()
+	s.session.events = NewEventBus()
+	s.events, _ = s.session.events.Subscribe(10)
+	s.session.State = inSession{}
+}
+
+func (s *EventBusTestSuite) nextEvent(expected EventType) Event {
+	s.Require().NotEmpty(s.events)
+	event := <-s.events
+	s.Equal(expected, event.Type, event.Text)
+	s.Equal(s.session.sessionID, event.SessionID)
+	s.False(event.Time.IsZero())
+	return event
+}
+
+func (s *EventBusTestSuite) TestConnectAndDisconnect() {
+	s.session.State = latentState{}
+	s.session.stateMachine.setState(s.session, logonState{})
+	s.nextEvent(EventConnect)
+
+	s.session.stateMachine.setState(s.session, latentState{})
+	s.nextEvent(EventDisconnect)
+	s.Empty(s.events)
+}
+
+func (s *EventBusTestSuite) TestLogout() {
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.MockApp.On("OnLogout")
+	s.fixMsgIn(s.session, s.Logout())
+
+	s.nextEvent(EventLogout)
+	s.nextEvent(EventDisconnect)
+}
+
+func (s *EventBusTestSuite) TestReceivedReject() {
+	reject := s.buildMessage(string(msgTypeReject))
+	reject.Body.SetField(tagText, FIXString("Invalid tag number"))
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.fixMsgIn(s.session, reject)
+
+	s.Equal("Received reject: Invalid tag number", s.nextEvent(EventReject).Text)
+}
+
+func (s *EventBusTestSuite) TestSentReject() {
+	s.MockApp.On("FromApp").Return(ConditionallyRequiredFieldMissing(Tag(11)))
+	s.MockApp.On("ToApp").Return(nil)
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+
+	s.Contains(s.nextEvent(EventReject).Text, "Sent reject: ")
+}
+
+func (s *EventBusTestSuite) TestResend() {
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.session, s.ResendRequest(1))
+	s.Equal("Received ResendRequest FROM: 1 TO: 0", s.nextEvent(EventResend).Text)
+
+	s.MessageFactory.SetNextSeqNum(5)
+	s.MockApp.On("FromApp").Return(nil)
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+	s.Equal("Sent ResendRequest FROM: 2 TO: 0", s.nextEvent(EventResend).Text)
+}
+
+func (s *EventBusTestSuite) TestSequenceReset() {
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.fixMsgIn(s.session, s.SequenceReset(10))
+
+	s.nextEvent(EventSeqNumReset)
+	s.NextTargetMsgSeqNum(10)
+}
+
+func (s *EventBusTestSuite) TestValidationWarning() {
+	s.session.onUnknownEnum("D", Tag(40), []byte("X"))
+
+	s.Equal(`Value "X" of tag 40 is not an enumerated value`, s.nextEvent(EventValidationWarning).Text)
+}
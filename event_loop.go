@@ -0,0 +1,352 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"hash/fnv"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+const (
+	// timerWheelTick is the resolution of the timers of sessions in shared mode.
+	timerWheelTick = 10 * time.Millisecond
+
+	// timerWheelSlots is the number of slots of a timer wheel, a revolution takes about 10 seconds.
+	timerWheelSlots = 1024
+)
+
+// eventLoop runs the sessions assigned to it in shared mode on a single goroutine, in place of the run loop and timer
+// goroutines of each session. The timers of its sessions are those of a timer wheel advanced by the loop.
+type eventLoop struct {
+	clock Clock
+	wheel *internal.TimerWheel
+	id    uint64
+
+	mu    sync.Mutex
+	tasks []func()
+	wake  chan struct{}
+
+	// sessions are the sessions attached to the loop, with the function called once they stop. Only used by the loop.
+	sessions map[*session]func()
+
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// newEventLoop returns a running eventLoop driven by clock.
+func newEventLoop(clock Clock) *eventLoop {
+	l := &eventLoop{
+		clock:    clock,
+		wheel:    internal.NewTimerWheel(timerWheelTick, timerWheelSlots),
+		wake:     make(chan struct{}, 1),
+		sessions: make(map[*session]func()),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+
+	ready := make(chan struct{})
+	go l.run(ready)
+	<-ready
+
+	return l
+}
+
+func (l *eventLoop) run(ready chan<- struct{}) {
+	defer close(l.stopped)
+	l.id = internal.GoroutineID()
+	close(ready)
+
+	wheelTicker := l.clock.NewTicker(timerWheelTick)
+	defer wheelTicker.Stop()
+
+	// Session times are checked every second, aligned with a round second like the run loops of dedicated mode.
+	now := l.clock.Now()
+	align := l.clock.NewTimer(now.Truncate(time.Second).Add(time.Second).Sub(now))
+	var ticker Ticker
+	var ticks <-chan time.Time
+	defer func() {
+		align.Stop()
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-l.wake:
+			l.runTasks()
+
+		case <-wheelTicker.C():
+			l.wheel.Advance()
+
+		case <-align.C():
+			ticker = l.clock.NewTicker(time.Second)
+			ticks = ticker.C()
+
+		case now := <-ticks:
+			for s := range l.sessions {
+				l.handle(s, func() {
+					s.CheckSessionTime(s, now)
+					s.CheckResetTime(s, now)
+				})
+			}
+
+		case <-l.done:
+			// Requests posted before the sessions stopped are still processed.
+			l.runTasks()
+			return
+		}
+	}
+}
+
+// post runs f on the loop.
+func (l *eventLoop) post(f func()) {
+	l.mu.Lock()
+	l.tasks = append(l.tasks, f)
+	l.mu.Unlock()
+
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (l *eventLoop) runTasks() {
+	l.mu.Lock()
+	tasks := l.tasks
+	l.tasks = nil
+	l.mu.Unlock()
+
+	for _, f := range tasks {
+		f()
+	}
+}
+
+// start attaches s to the loop, which runs it until it stops, then calls done on the loop. done may be nil, and set
+// later by onStop.
+func (l *eventLoop) start(s *session, done func()) {
+	// Requests made from now on are posted to the loop, after s is attached.
+	s.setRunning(true, l.id)
+
+	l.post(func() {
+		l.sessions[s] = done
+		l.handle(s, func() {
+			s.Start(s)
+			s.stateTimer = internal.NewPassiveEventTimer(l.wheel.AfterFunc(time.Second, func() {
+				l.handle(s, func() { s.Timeout(s, internal.NeedHeartbeat) })
+			}))
+			s.peerTimer = internal.NewPassiveEventTimer(l.wheel.AfterFunc(time.Second, func() {
+				l.handle(s, func() { s.Timeout(s, internal.PeerTimeout) })
+			}))
+		})
+
+		// Messages queued while s was not running.
+		l.sendMessages(s)
+	})
+}
+
+// onStop sets the function called on the loop once s stops, calling it now if s stopped already.
+func (l *eventLoop) onStop(s *session, done func()) {
+	l.post(func() {
+		if _, attached := l.sessions[s]; attached {
+			l.sessions[s] = done
+			return
+		}
+		done()
+	})
+}
+
+// handle runs f for s if it is attached to the loop, then detaches s once it stopped, like the run loop of
+// dedicated mode.
+func (l *eventLoop) handle(s *session, f func()) {
+	if _, attached := l.sessions[s]; !attached {
+		return
+	}
+
+	f()
+
+	s.checkDrain(s.clock.Now())
+	if s.Stopped() {
+		l.detach(s)
+	}
+}
+
+func (l *eventLoop) detach(s *session) {
+	done := l.sessions[s]
+	delete(l.sessions, s)
+
+	s.setRunning(false, 0)
+	s.finishDrain(nil)
+	s.stateTimer.Stop()
+	s.peerTimer.Stop()
+
+	if done != nil {
+		done()
+	}
+}
+
+// admin processes req for s on the loop, or directly if s stopped before.
+func (l *eventLoop) admin(s *session, req interface{}) {
+	if _, attached := l.sessions[s]; attached {
+		l.handle(s, func() { s.onAdmin(req) })
+		return
+	}
+
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	s.onAdmin(req)
+}
+
+// sendMessages sends the messages queued for s once notified by notifyMessageOut. The notification is kept until s
+// is attached.
+func (l *eventLoop) sendMessages(s *session) {
+	if _, attached := l.sessions[s]; !attached {
+		return
+	}
+
+	select {
+	case <-s.messageEvent:
+	default:
+		return
+	}
+
+	l.handle(s, func() {
+		s.sendDispatchedRejects()
+		s.SendAppMessages(s)
+	})
+}
+
+// read delivers first, then the messages read by parser, to s for the connection of msgIn, and its disconnection once
+// reading fails. It waits for each message to be processed, so the connection reads no further ahead than in
+// dedicated mode.
+func (l *eventLoop) read(s *session, parser *parser, msgIn chan fixIn, first ...fixIn) {
+	ack := make(chan struct{}, 1)
+	deliver := func(in fixIn, ok bool) {
+		l.post(func() {
+			l.handle(s, func() {
+				// Messages of an earlier connection are dropped.
+				if s.messageIn != msgIn {
+					return
+				}
+
+				if ok {
+					s.Incoming(s, in)
+				} else {
+					s.Disconnected(s)
+				}
+			})
+			ack <- struct{}{}
+		})
+
+		select {
+		case <-ack:
+		case <-l.stopped:
+		}
+	}
+
+	for _, in := range first {
+		deliver(in, true)
+	}
+
+	for {
+		msg, err := parser.ReadMessage()
+		if err != nil {
+			s.log.OnEvent(err.Error())
+			deliver(fixIn{}, false)
+			return
+		}
+		deliver(fixIn{msg, parser.lastRead}, true)
+	}
+}
+
+// stop stops the loop, once its sessions are stopped.
+func (l *eventLoop) stop() {
+	l.once.Do(func() {
+		close(l.done)
+	})
+
+	<-l.stopped
+}
+
+// eventLoopGroup is the event loops the sessions of an Acceptor or Initiator are spread across in shared mode.
+type eventLoopGroup struct {
+	loops []*eventLoop
+}
+
+// loadEventLoopShards returns the number of event loops of the EventLoopMode, 0 in dedicated mode.
+func loadEventLoopShards(settings *SessionSettings) (int, error) {
+	if !settings.HasSetting(config.EventLoopMode) {
+		return 0, nil
+	}
+
+	mode, err := settings.Setting(config.EventLoopMode)
+	if err != nil {
+		return 0, err
+	}
+
+	switch mode {
+	case "dedicated":
+		return 0, nil
+	case "shared":
+	default:
+		return 0, IncorrectFormatForSetting{Setting: config.EventLoopMode, Value: []byte(mode)}
+	}
+
+	if !settings.HasSetting(config.EventLoopShards) {
+		return runtime.GOMAXPROCS(0), nil
+	}
+
+	return positiveIntSetting(settings, config.EventLoopShards)
+}
+
+// newEventLoopGroup returns shards event loops driven by clock.
+func newEventLoopGroup(shards int, clock Clock) *eventLoopGroup {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	g := &eventLoopGroup{loops: make([]*eventLoop, shards)}
+	for i := range g.loops {
+		g.loops[i] = newEventLoop(clock)
+	}
+
+	return g
+}
+
+// loop returns the event loop of the session, always the same one for a SessionID.
+func (g *eventLoopGroup) loop(sessionID SessionID) *eventLoop {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(sessionID.String()))
+
+	return g.loops[h.Sum32()%uint32(len(g.loops))]
+}
+
+// stop stops the event loops, once their sessions are stopped.
+func (g *eventLoopGroup) stop() {
+	if g == nil {
+		return
+	}
+
+	for _, loop := range g.loops {
+		loop.stop()
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+type heartbeatApp struct {
+	reloadApp
+	heartbeats chan SessionID
+}
+
+func (a heartbeatApp) FromAdmin(msg *Message, sessionID SessionID) MessageRejectError {
+	if msg.IsMsgTypeOf(string(msgTypeHeartbeat)) {
+		select {
+		case a.heartbeats <- sessionID:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func TestLoadEventLoopShards(t *testing.T) {
+	var tests = []struct {
+		settings map[string]string
+		expected int
+		invalid  bool
+	}{
+		{settings: nil, expected: 0},
+		{settings: map[string]string{config.EventLoopMode: "dedicated", config.EventLoopShards: "4"}, expected: 0},
+		{settings: map[string]string{config.EventLoopMode: "shared"}, expected: runtime.GOMAXPROCS(0)},
+		{settings: map[string]string{config.EventLoopMode: "shared", config.EventLoopShards: "4"}, expected: 4},
+		{settings: map[string]string{config.EventLoopMode: "shared", config.EventLoopShards: "0"}, invalid: true},
+		{settings: map[string]string{config.EventLoopMode: "pooled"}, invalid: true},
+	}
+
+	for _, test := range tests {
+		settings := NewSessionSettings()
+		for setting, value := range test.settings {
+			settings.Set(setting, value)
+		}
+
+		shards, err := loadEventLoopShards(settings)
+		if test.invalid {
+			assert.Error(t, err, test.settings)
+			continue
+		}
+
+		require.NoError(t, err, test.settings)
+		assert.Equal(t, test.expected, shards, test.settings)
+	}
+}
+
+func TestEventLoopGroup_Loop(t *testing.T) {
+	g := newEventLoopGroup(4, nil)
+	defer g.stop()
+
+	used := make(map[*eventLoop]bool)
+	for _, target := range []string{"A", "B", "C", "D", "E", "F", "G", "H"} {
+		sessionID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "S", TargetCompID: target}
+		loop := g.loop(sessionID)
+		assert.Same(t, loop, g.loop(sessionID))
+		used[loop] = true
+	}
+
+	assert.Greater(t, len(used), 1)
+}
+
+func TestSharedEventLoopMode(t *testing.T) {
+	host := "pipe://shared_loops"
+	shared := map[string]string{config.EventLoopMode: "shared", config.EventLoopShards: "2"}
+	targets := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+
+	var acceptorSessions, initiatorSessions []*SessionSettings
+	connect := map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5020", config.HeartBtInt: "1"}
+	for _, target := range targets {
+		acceptorSessions = append(acceptorSessions, reloadSessionSettings("ACCEPTOR", target, nil))
+		initiatorSessions = append(initiatorSessions, reloadSessionSettings(target, "ACCEPTOR", connect))
+	}
+
+	acceptorApp := newReloadApp()
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketAcceptHost: host, config.EventLoopMode: "shared", config.EventLoopShards: "2"},
+			acceptorSessions...),
+		NewNullLogFactory())
+	require.NoError(t, err)
+
+	// The sessions run on the event loops, rather than on goroutines of their own.
+	goroutines := runtime.NumGoroutine()
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+	assert.Less(t, runtime.NumGoroutine()-goroutines, len(targets))
+
+	initiatorApp := heartbeatApp{reloadApp: newReloadApp(), heartbeats: make(chan SessionID, 1)}
+	initiator, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(), reloadSettings(t, shared, initiatorSessions...),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	for range targets {
+		select {
+		case <-initiatorApp.loggedOn:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for logon")
+		}
+	}
+
+	// The heartbeats of the acceptor are sent on the timers of its event loops.
+	select {
+	case <-initiatorApp.heartbeats:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for heartbeat")
+	}
+	assertNoSession(t, initiatorApp.loggedOut)
+
+	for _, s := range acceptor.sessions {
+		assert.Same(t, acceptor.eventLoops.loop(s.sessionID), s.loop)
+		assert.Equal(t, s.loop.id, s.loopID.Load())
+	}
+	for _, s := range initiator.sessions {
+		assert.Same(t, initiator.eventLoops.loop(s.sessionID), s.loop)
+		assert.Equal(t, s.loop.id, s.loopID.Load())
+	}
+
+	initiator.Stop()
+	for range targets {
+		select {
+		case <-acceptorApp.loggedOut:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for logout")
+		}
+	}
+}
+
+func TestSharedEventLoopModeDynamicSessions(t *testing.T) {
+	host := "pipe://shared_dynamic"
+	acceptorApp := newReloadApp()
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(), reloadSettings(t, map[string]string{
+		config.SocketAcceptHost: host, config.SocketAcceptPort: "5028", config.DynamicSessions: "Y", config.EventLoopMode: "shared",
+	}, reloadSessionSettings("ACCEPTOR", "Z", nil)), NewNullLogFactory())
+	require.NoError(t, err)
+	// The acceptor listens on the address of its configured session, and accepts A as a dynamic session.
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	initiatorApp := newReloadApp()
+	initiator, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(), reloadSettings(t,
+		map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5028", config.HeartBtInt: "30"},
+		reloadSessionSettings("A", "ACCEPTOR", nil)), NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	dynamicA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "A"}
+	waitForSession(t, acceptorApp.loggedOn, dynamicA)
+	session, ok := lookupSession(dynamicA)
+	require.True(t, ok)
+	assert.NotNil(t, session.loop)
+
+	// The dynamic session is unregistered once it stops on its event loop.
+	initiator.Stop()
+	waitForSession(t, acceptorApp.loggedOut, dynamicA)
+	assert.Eventually(t, func() bool {
+		_, ok := lookupSession(dynamicA)
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+}
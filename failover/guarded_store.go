@@ -0,0 +1,136 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package failover
+
+import (
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type guardedStoreFactory struct {
+	node    *Node
+	factory quickfix.MessageStoreFactory
+}
+
+// GuardedStoreFactory returns a MessageStoreFactory whose stores, created by factory, reject writes with ErrNotActive
+// while the node is not active. Reads are not guarded, and the outbound queue of an OutboundQueueStore is kept.
+//
+// The guard is best effort: it checks the role of the node and the expiry of its lease by the local clock before
+// each write, but a node paused past the expiry of its lease, such as by a GC pause or a VM migration, may still
+// complete a write started before, after a standby took over. Stores fenced by the Token of the node, such as those
+// of the sql store's NewFencedStoreFactory, reject such writes.
+func (n *Node) GuardedStoreFactory(factory quickfix.MessageStoreFactory) quickfix.MessageStoreFactory {
+	return guardedStoreFactory{node: n, factory: factory}
+}
+
+// Create creates a MessageStore of the factory, which rejects writes while the node is not active.
+func (f guardedStoreFactory) Create(sessionID quickfix.SessionID) (quickfix.MessageStore, error) {
+	store, err := f.factory.Create(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	guarded := &guardedStore{MessageStore: store, node: f.node}
+	if queueStore, ok := store.(quickfix.OutboundQueueStore); ok {
+		return &guardedQueueStore{guardedStore: guarded, queue: queueStore}, nil
+	}
+	return guarded, nil
+}
+
+type guardedStore struct {
+	quickfix.MessageStore
+	node *Node
+}
+
+func (store *guardedStore) IncrNextSenderMsgSeqNum() error {
+	if err := store.node.checkActive(); err != nil {
+		return err
+	}
+	return store.MessageStore.IncrNextSenderMsgSeqNum()
+}
+
+func (store *guardedStore) IncrNextTargetMsgSeqNum() error {
+	if err := store.node.checkActive(); err != nil {
+		return err
+	}
+	return store.MessageStore.IncrNextTargetMsgSeqNum()
+}
+
+func (store *guardedStore) SetNextSenderMsgSeqNum(next int) error {
+	if err := store.node.checkActive(); err != nil {
+		return err
+	}
+	return store.MessageStore.SetNextSenderMsgSeqNum(next)
+}
+
+func (store *guardedStore) SetNextTargetMsgSeqNum(next int) error {
+	if err := store.node.checkActive(); err != nil {
+		return err
+	}
+	return store.MessageStore.SetNextTargetMsgSeqNum(next)
+}
+
+// SetCreationTime sets the creation time only while the node is active, as it can not fail.
+func (store *guardedStore) SetCreationTime(t time.Time) {
+	if store.node.checkActive() == nil {
+		store.MessageStore.SetCreationTime(t)
+	}
+}
+
+func (store *guardedStore) SaveMessage(seqNum int, msg []byte) error {
+	if err := store.node.checkActive(); err != nil {
+		return err
+	}
+	return store.MessageStore.SaveMessage(seqNum, msg)
+}
+
+func (store *guardedStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	if err := store.node.checkActive(); err != nil {
+		return err
+	}
+	return store.MessageStore.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, msg)
+}
+
+func (store *guardedStore) Reset() error {
+	if err := store.node.checkActive(); err != nil {
+		return err
+	}
+	return store.MessageStore.Reset()
+}
+
+type guardedQueueStore struct {
+	*guardedStore
+	queue quickfix.OutboundQueueStore
+}
+
+func (store *guardedQueueStore) QueueMessage(msg quickfix.QueuedMessage) error {
+	if err := store.node.checkActive(); err != nil {
+		return err
+	}
+	return store.queue.QueueMessage(msg)
+}
+
+func (store *guardedQueueStore) QueuedMessages() ([]quickfix.QueuedMessage, error) {
+	return store.queue.QueuedMessages()
+}
+
+func (store *guardedQueueStore) ClearQueuedMessages() error {
+	if err := store.node.checkActive(); err != nil {
+		return err
+	}
+	return store.queue.ClearQueuedMessages()
+}
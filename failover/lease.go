@@ -0,0 +1,81 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package failover
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrLeaseHeld is returned when acquiring a lease held by another owner.
+var ErrLeaseHeld = errors.New("failover: lease held by another owner")
+
+// Lease elects the active Node of a group of nodes sharing it. It is held by one owner at a time, until it expires
+// or is released.
+//
+// Leases expire by the clocks of the nodes, so the clocks of the nodes must not drift apart by more than a fraction
+// of the TTL of the lease.
+type Lease interface {
+	// Acquire acquires the lease for owner, or renews it if owner holds it, until ttl from now. It returns the fencing
+	// token of the lease, which increases each time the lease changes owner, or ErrLeaseHeld if another owner holds
+	// the lease.
+	Acquire(ctx context.Context, owner string, ttl time.Duration) (token int64, err error)
+
+	// Release releases the lease if owner holds it, so that another owner can acquire it before it expires.
+	Release(ctx context.Context, owner string) error
+}
+
+type memoryLease struct {
+	mu      sync.Mutex
+	owner   string
+	token   int64
+	expires time.Time
+}
+
+// NewMemoryLease returns a Lease held in memory, for nodes running in the same process.
+func NewMemoryLease() Lease {
+	return &memoryLease{}
+}
+
+func (l *memoryLease) Acquire(_ context.Context, owner string, ttl time.Duration) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.owner != owner {
+		if l.owner != "" && now.Before(l.expires) {
+			return 0, ErrLeaseHeld
+		}
+
+		l.owner = owner
+		l.token++
+	}
+
+	l.expires = now.Add(ttl)
+	return l.token, nil
+}
+
+func (l *memoryLease) Release(_ context.Context, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.owner == owner {
+		l.owner = ""
+	}
+	return nil
+}
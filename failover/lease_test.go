@@ -0,0 +1,91 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package failover
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLLease(t *testing.T) Lease {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "lease.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	lease, err := NewSQLLease(context.Background(), db, "fix_leases", "engine")
+	require.NoError(t, err)
+
+	// The lease is shared with nodes creating it again.
+	_, err = NewSQLLease(context.Background(), db, "fix_leases", "engine")
+	require.NoError(t, err)
+	return lease
+}
+
+func TestLease(t *testing.T) {
+	var tests = []struct {
+		name  string
+		lease func(t *testing.T) Lease
+	}{
+		{name: "memory", lease: func(*testing.T) Lease { return NewMemoryLease() }},
+		{name: "sql", lease: newSQLLease},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			lease := test.lease(t)
+			ttl := 50 * time.Millisecond
+
+			token, err := lease.Acquire(ctx, "a", ttl)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), token)
+
+			_, err = lease.Acquire(ctx, "b", ttl)
+			assert.ErrorIs(t, err, ErrLeaseHeld)
+
+			// Renewing keeps the token.
+			token, err = lease.Acquire(ctx, "a", ttl)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), token)
+
+			// Another owner acquires the lease once it expired.
+			time.Sleep(2 * ttl)
+			token, err = lease.Acquire(ctx, "b", ttl)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), token)
+
+			_, err = lease.Acquire(ctx, "a", ttl)
+			assert.ErrorIs(t, err, ErrLeaseHeld)
+
+			// Or once it is released, releasing a lease held by another owner has no effect.
+			require.NoError(t, lease.Release(ctx, "a"))
+			_, err = lease.Acquire(ctx, "a", ttl)
+			assert.ErrorIs(t, err, ErrLeaseHeld)
+
+			require.NoError(t, lease.Release(ctx, "b"))
+			token, err = lease.Acquire(ctx, "a", ttl)
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), token)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package failover runs a FIX engine on a group of primary and standby nodes, so that a standby takes over the
+// sessions of a failed primary. The nodes elect the active one with a Lease. The active node creates and starts the
+// engine, whose sessions resume from the sequence numbers of their MessageStores, so the nodes must share the stores,
+// such as the sql store, the file store on a shared volume, or a replicated store promoted on the standby.
+//
+// A node that can not renew its lease stops its engine before the lease expires, before a standby can acquire it.
+// Should its engine not stop in time, writes are fenced by the token of the lease, which increases each time the
+// lease changes owner. The sql store of a NewFencedStoreFactory, given a lease of NewSQLLease in the same database and
+// the Token of the node, writes only while the token of the node is the current token of the lease, checked in the
+// transaction of each write:
+//
+//	factory := sql.NewFencedStoreFactory(settings, sql.Fence{Table: "fix_leases", Name: "engine", Token: node.Token})
+//
+// Other stores may be guarded by the GuardedStoreFactory of a Node, which rejects writes once the node is no longer
+// active by its local clock, but does not fence writes of a node paused past the expiry of its lease.
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Role is the role of a Node in its group.
+type Role int
+
+// The roles of nodes.
+const (
+	// Standby waits to acquire the lease.
+	Standby Role = iota
+
+	// Active holds the lease and runs the engine.
+	Active
+)
+
+func (r Role) String() string {
+	switch r {
+	case Standby:
+		return "standby"
+	case Active:
+		return "active"
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+// Engine is the FIX engine run by the active Node, such as a quickfix.Acceptor or quickfix.Initiator.
+type Engine interface {
+	Start() error
+	StopWithContext(ctx context.Context) error
+}
+
+// ErrNotActive is returned by writes to the guarded stores of a Node that is not active.
+var ErrNotActive = errors.New("failover: node is not active")
+
+const defaultTTL = 10 * time.Second
+
+// Config configures a Node.
+type Config struct {
+	// ID identifies the node to the Lease, and must be unique to each run of each node. Defaults to the host name,
+	// process ID and start time.
+	ID string
+
+	// TTL is how long the lease is held once acquired or renewed, so a standby takes over within TTL of the active
+	// node failing. Defaults to 10 seconds.
+	TTL time.Duration
+
+	// RenewInterval is how often the active node renews the lease, and a standby tries to acquire it. Defaults to a
+	// third of TTL.
+	RenewInterval time.Duration
+
+	// OnRoleChange is called when the node becomes active or standby, with the fencing token of the lease, and may
+	// be nil.
+	OnRoleChange func(role Role, token int64)
+}
+
+// Node is a member of a group of nodes running an Engine on the one holding their Lease.
+type Node struct {
+	lease     Lease
+	newEngine func() (Engine, error)
+	config    Config
+
+	mu         sync.Mutex
+	role       Role
+	token      int64
+	validUntil time.Time
+	engine     Engine
+}
+
+// NewNode returns a standby Node of the group sharing lease. Once the node is active, it runs the Engine returned by
+// newEngine, a new one each time.
+func NewNode(lease Lease, newEngine func() (Engine, error), config Config) *Node {
+	if config.ID == "" {
+		host, _ := os.Hostname()
+		config.ID = fmt.Sprintf("%s-%d-%d", host, os.Getpid(), time.Now().UnixNano())
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaultTTL
+	}
+	if config.RenewInterval <= 0 {
+		config.RenewInterval = config.TTL / 3
+	}
+
+	return &Node{lease: lease, newEngine: newEngine, config: config}
+}
+
+// Role returns the role of the node.
+func (n *Node) Role() Role {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.role
+}
+
+// Token returns the fencing token of the lease while the node is active, and 0 otherwise.
+func (n *Node) Token() int64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.role != Active {
+		return 0
+	}
+	return n.token
+}
+
+// checkActive returns ErrNotActive unless the node is active and its lease has not expired by the local clock.
+func (n *Node) checkActive() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.role != Active || !time.Now().Before(n.validUntil) {
+		return ErrNotActive
+	}
+	return nil
+}
+
+// Run runs the node until ctx is done, then stops the engine if the node is active and releases the lease. It fails
+// if the engine can not be created or started, once the lease is released.
+func (n *Node) Run(ctx context.Context) error {
+	ticker := time.NewTicker(n.config.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := n.renew(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return n.deactivate()
+		case <-ticker.C:
+		}
+	}
+}
+
+// renew acquires or renews the lease, activating the node once it holds it and deactivating it once it lost it, or
+// could not renew it for so long that it would expire before the next attempt.
+func (n *Node) renew(ctx context.Context) error {
+	start := time.Now()
+	token, err := n.lease.Acquire(ctx, n.config.ID, n.config.TTL)
+
+	n.mu.Lock()
+	role, current, validUntil := n.role, n.token, n.validUntil
+	if err == nil && role == Active && token == current {
+		n.validUntil = start.Add(n.config.TTL)
+	}
+	n.mu.Unlock()
+
+	switch {
+	case ctx.Err() != nil:
+		if err == nil && role == Standby {
+			_ = n.release()
+		}
+		return nil
+
+	case err == nil && role == Standby:
+		return n.activate(token, start.Add(n.config.TTL))
+
+	case err == nil && token != current:
+		// Another node held the lease in between.
+		_ = n.deactivate()
+		return n.activate(token, start.Add(n.config.TTL))
+
+	case err != nil && role == Active:
+		if errors.Is(err, ErrLeaseHeld) || time.Until(validUntil) < n.config.RenewInterval {
+			_ = n.deactivate()
+		}
+	}
+
+	return nil
+}
+
+func (n *Node) activate(token int64, validUntil time.Time) error {
+	n.mu.Lock()
+	n.role, n.token, n.validUntil = Active, token, validUntil
+	n.mu.Unlock()
+
+	engine, err := n.newEngine()
+	if err == nil {
+		n.mu.Lock()
+		n.engine = engine
+		n.mu.Unlock()
+		err = engine.Start()
+	}
+	if err != nil {
+		_ = n.deactivate()
+		return err
+	}
+
+	if n.config.OnRoleChange != nil {
+		n.config.OnRoleChange(Active, token)
+	}
+	return nil
+}
+
+// deactivate stops the engine, at the latest when the lease expires, and releases the lease.
+func (n *Node) deactivate() error {
+	n.mu.Lock()
+	role, engine, token, validUntil := n.role, n.engine, n.token, n.validUntil
+	n.mu.Unlock()
+
+	if role != Active {
+		return nil
+	}
+
+	var err error
+	if engine != nil {
+		ctx, cancel := context.WithDeadline(context.Background(), validUntil)
+		err = engine.StopWithContext(ctx)
+		cancel()
+	}
+
+	n.mu.Lock()
+	n.role, n.engine = Standby, nil
+	n.mu.Unlock()
+
+	if releaseErr := n.release(); err == nil {
+		err = releaseErr
+	}
+
+	if n.config.OnRoleChange != nil {
+		n.config.OnRoleChange(Standby, token)
+	}
+	return err
+}
+
+func (n *Node) release() error {
+	ctx, cancel := context.WithTimeout(context.Background(), n.config.RenewInterval)
+	defer cancel()
+	return n.lease.Release(ctx, n.config.ID)
+}
@@ -0,0 +1,220 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package failover
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+	sqlstore "github.com/quickfixgo/quickfix/store/sql"
+)
+
+type fakeEngine struct {
+	started, stopped atomic.Bool
+}
+
+func (e *fakeEngine) Start() error { e.started.Store(true); return nil }
+
+func (e *fakeEngine) StopWithContext(context.Context) error { e.stopped.Store(true); return nil }
+
+// failingLease fails to acquire its Lease while failing is set, like a node cut off from the lease.
+type failingLease struct {
+	Lease
+	failing atomic.Bool
+}
+
+func (l *failingLease) Acquire(ctx context.Context, owner string, ttl time.Duration) (int64, error) {
+	if l.failing.Load() {
+		return 0, errors.New("unreachable")
+	}
+	return l.Lease.Acquire(ctx, owner, ttl)
+}
+
+type roleChange struct {
+	node  string
+	role  Role
+	token int64
+}
+
+type testNode struct {
+	*Node
+	engines chan *fakeEngine
+	done    chan error
+	cancel  context.CancelFunc
+}
+
+func runNode(t *testing.T, id string, lease Lease, changes func(roleChange)) *testNode {
+	n := &testNode{engines: make(chan *fakeEngine, 10), done: make(chan error, 1)}
+	n.Node = NewNode(lease, func() (Engine, error) {
+		engine := &fakeEngine{}
+		n.engines <- engine
+		return engine, nil
+	}, Config{
+		ID:            id,
+		TTL:           300 * time.Millisecond,
+		RenewInterval: 50 * time.Millisecond,
+		OnRoleChange:  func(role Role, token int64) { changes(roleChange{node: id, role: role, token: token}) },
+	})
+
+	var ctx context.Context
+	ctx, n.cancel = context.WithCancel(context.Background())
+	go func() { n.done <- n.Run(ctx) }()
+	t.Cleanup(n.cancel)
+	return n
+}
+
+func waitForEngine(t *testing.T, n *testNode) *fakeEngine {
+	select {
+	case engine := <-n.engines:
+		return engine
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for engine")
+		return nil
+	}
+}
+
+func TestNode_Failover(t *testing.T) {
+	var mu sync.Mutex
+	var changes []roleChange
+	record := func(change roleChange) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, change)
+	}
+
+	lease := NewMemoryLease()
+	primaryLease := &failingLease{Lease: lease}
+	primary := runNode(t, "primary", primaryLease, record)
+	primaryEngine := waitForEngine(t, primary)
+	assert.Eventually(t, primaryEngine.started.Load, time.Second, 10*time.Millisecond)
+	assert.Equal(t, Active, primary.Role())
+	assert.Equal(t, int64(1), primary.Token())
+
+	store, err := primary.GuardedStoreFactory(quickfix.NewMemoryStoreFactory()).Create(quickfix.SessionID{})
+	require.NoError(t, err)
+	require.NoError(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("message 1")))
+
+	standby := runNode(t, "standby", lease, record)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, Standby, standby.Role())
+
+	// The primary stops its engine before its lease expires, then the standby takes over.
+	primaryLease.failing.Store(true)
+	standbyEngine := waitForEngine(t, standby)
+	assert.True(t, primaryEngine.stopped.Load())
+	assert.Equal(t, Standby, primary.Role())
+	assert.Eventually(t, func() bool { return standby.Role() == Active }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(2), standby.Token())
+
+	// The stores of the primary reject writes.
+	assert.ErrorIs(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("message 2")), ErrNotActive)
+	assert.Equal(t, 2, store.NextSenderMsgSeqNum())
+
+	standby.cancel()
+	require.NoError(t, <-standby.done)
+	assert.True(t, standbyEngine.stopped.Load())
+	primary.cancel()
+	require.NoError(t, <-primary.done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []roleChange{
+		{node: "primary", role: Active, token: 1},
+		{node: "primary", role: Standby, token: 1},
+		{node: "standby", role: Active, token: 2},
+		{node: "standby", role: Standby, token: 2},
+	}, changes)
+}
+
+func TestNode_SQLFence(t *testing.T) {
+	ctx := context.Background()
+	dsn := filepath.Join(t.TempDir(), "fix.db")
+	db, err := sql.Open("sqlite3", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	lease, err := NewSQLLease(ctx, db, "fix_leases", "engine")
+	require.NoError(t, err)
+	newEngine := func() (Engine, error) { return &fakeEngine{}, nil }
+	config := Config{TTL: 100 * time.Millisecond}
+
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=sqlite3
+SQLStoreDataSourceName=%s
+SQLStoreAutoMigrate=Y
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, dsn, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.NoError(t, err)
+
+	createStore := func(n *Node) quickfix.MessageStore {
+		store, err := sqlstore.NewFencedStoreFactory(settings, sqlstore.Fence{Table: "fix_leases", Name: "engine", Token: n.Token}).Create(sessionID)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+		return store
+	}
+
+	config.ID = "primary"
+	primary := NewNode(lease, newEngine, config)
+	require.NoError(t, primary.renew(ctx))
+	require.Equal(t, Active, primary.Role())
+	primaryStore := createStore(primary)
+	require.NoError(t, primaryStore.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("message 1")))
+
+	// The primary pauses past the expiry of its lease, and the standby takes over.
+	time.Sleep(2 * config.TTL)
+	config.ID = "standby"
+	standby := NewNode(lease, newEngine, config)
+	require.NoError(t, standby.renew(ctx))
+	require.Equal(t, Active, standby.Role())
+	standbyStore := createStore(standby)
+
+	// Writes of the primary with its stale token are rejected by the store, though it still believes it is active.
+	assert.Equal(t, Active, primary.Role())
+	assert.ErrorIs(t, primaryStore.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("stale")), sqlstore.ErrFenced)
+
+	require.NoError(t, standbyStore.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("message 2")))
+	msgs, err := standbyStore.GetMessages(1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("message 1"), []byte("message 2")}, msgs)
+}
+
+func TestNode_EngineFails(t *testing.T) {
+	lease := NewMemoryLease()
+	node := NewNode(lease, func() (Engine, error) { return nil, errors.New("no engine") }, Config{ID: "node"})
+	assert.EqualError(t, node.Run(context.Background()), "no engine")
+	assert.Equal(t, Standby, node.Role())
+
+	// The lease is released.
+	_, err := lease.Acquire(context.Background(), "other", time.Second)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,88 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package failover
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type sqlLease struct {
+	db   *sql.DB
+	name string
+
+	acquire, token, release string
+}
+
+// NewSQLLease returns the Lease called name in table of db, which is created if it does not exist, so that nodes on
+// different hosts sharing the database elect one of them. Statements are written with "?" placeholders.
+func NewSQLLease(ctx context.Context, db *sql.DB, table, name string) (Lease, error) {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  name VARCHAR(64) NOT NULL,
+  owner VARCHAR(255) NOT NULL,
+  token BIGINT NOT NULL,
+  expires BIGINT NOT NULL,
+  PRIMARY KEY (name)
+)`, table)); err != nil {
+		return nil, errors.Wrap(err, "create lease table")
+	}
+
+	// Another node may insert the lease first.
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (name, owner, token, expires) VALUES (?, '', 0, 0)`, table), name); err != nil {
+		var token int64
+		if err := db.QueryRowContext(ctx, fmt.Sprintf(`SELECT token FROM %s WHERE name = ?`, table), name).Scan(&token); err != nil {
+			return nil, errors.Wrap(err, "insert lease")
+		}
+	}
+
+	return &sqlLease{
+		db:   db,
+		name: name,
+		acquire: fmt.Sprintf(`UPDATE %s SET token = CASE WHEN owner = ? THEN token ELSE token + 1 END, owner = ?, expires = ?
+  WHERE name = ? AND (owner = ? OR owner = '' OR expires < ?)`, table),
+		token:   fmt.Sprintf(`SELECT token FROM %s WHERE name = ? AND owner = ?`, table),
+		release: fmt.Sprintf(`UPDATE %s SET owner = '', expires = 0 WHERE name = ? AND owner = ?`, table),
+	}, nil
+}
+
+func (l *sqlLease) Acquire(ctx context.Context, owner string, ttl time.Duration) (int64, error) {
+	now := time.Now()
+	result, err := l.db.ExecContext(ctx, l.acquire, owner, owner, now.Add(ttl).UnixNano(), l.name, owner, now.UnixNano())
+	if err != nil {
+		return 0, errors.Wrap(err, "acquire lease")
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return 0, errors.Wrap(err, "acquire lease")
+	} else if n == 0 {
+		return 0, ErrLeaseHeld
+	}
+
+	var token int64
+	if err := l.db.QueryRowContext(ctx, l.token, l.name, owner).Scan(&token); err != nil {
+		return 0, errors.Wrap(err, "query lease token")
+	}
+	return token, nil
+}
+
+func (l *sqlLease) Release(ctx context.Context, owner string) error {
+	_, err := l.db.ExecContext(ctx, l.release, l.name, owner)
+	return errors.Wrap(err, "release lease")
+}
@@ -20,6 +20,8 @@ import (
 	"sort"
 	"sync"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // field stores a slice of TagValues.
@@ -56,6 +58,12 @@ type FieldMap struct {
 	tagLookup map[Tag]field
 	tagSort
 	rwLock *sync.RWMutex
+
+	// groups caches the repeating groups decoded by RepeatingGroup, which are written back on serialization.
+	groups map[Tag]*RepeatingGroup
+
+	// preserveOrder writes fields in the order they were parsed or set, rather than sorted.
+	preserveOrder bool
 }
 
 // ascending tags.
@@ -209,6 +217,16 @@ func (m FieldMap) GetTime(tag Tag) (t time.Time, err MessageRejectError) {
 	return val.Time, err
 }
 
+// GetDecimal is a GetField wrapper for float fields, such as prices, quantities and amounts, read as fixed-point
+// decimals without the rounding of float64.
+func (m FieldMap) GetDecimal(tag Tag) (decimal.Decimal, MessageRejectError) {
+	var val FIXDecimal
+	if err := m.GetField(tag, &val); err != nil {
+		return decimal.Decimal{}, err
+	}
+	return val.Decimal, nil
+}
+
 // GetString is a GetField wrapper for string fields.
 func (m FieldMap) GetString(tag Tag) (string, MessageRejectError) {
 	var val FIXString
@@ -247,6 +265,63 @@ func (m FieldMap) GetGroup(parser FieldGroupReader) MessageRejectError {
 	return nil
 }
 
+// RepeatingGroup returns the repeating group with Tag tag, decoded with template on first access. Nested groups are
+// only decoded when they are accessed, with RepeatingGroup of their Group and the template returned by
+// GroupTemplate.Nested. If the group is not present an empty RepeatingGroup is returned, to which Groups may be added.
+//
+// The RepeatingGroup is kept by the FieldMap, so that Groups added, removed or changed in place are written back when
+// the message is serialized. Until then, GetGroup reads the group as it was decoded.
+func (m *FieldMap) RepeatingGroup(tag Tag, template GroupTemplate) (*RepeatingGroup, MessageRejectError) {
+	m.rwLock.Lock()
+	defer m.rwLock.Unlock()
+
+	if rg, ok := m.groups[tag]; ok {
+		return rg, nil
+	}
+
+	rg := NewRepeatingGroup(tag, template)
+	if f, ok := m.tagLookup[tag]; ok {
+		if _, err := rg.Read(f); err != nil {
+			if msgRejErr, ok := err.(MessageRejectError); ok {
+				return nil, msgRejErr
+			}
+			return nil, IncorrectDataFormatForValue(tag)
+		}
+	}
+
+	if m.groups == nil {
+		m.groups = make(map[Tag]*RepeatingGroup)
+	}
+	m.groups[tag] = rg
+
+	return rg, nil
+}
+
+// writeGroups writes the repeating groups decoded by RepeatingGroup back to the FieldMap.
+func (m *FieldMap) writeGroups() {
+	m.rwLock.Lock()
+	defer m.rwLock.Unlock()
+
+	for tag, rg := range m.groups {
+		f, present := m.tagLookup[tag]
+		if !present && rg.Len() == 0 {
+			continue
+		}
+
+		// Without a DataDictionary the group fields are parsed as fields of the FieldMap, following a lone count field.
+		if len(f) == 1 && string(f[0].value) != "0" {
+			for _, t := range rg.template.appendTags(nil) {
+				delete(m.tagLookup, t)
+			}
+		}
+
+		if !present {
+			m.tags = append(m.tags, tag)
+		}
+		m.tagLookup[tag] = rg.Write()
+	}
+}
+
 // SetField sets the field with Tag tag.
 func (m *FieldMap) SetField(tag Tag, field FieldValueWriter) *FieldMap {
 	return m.SetBytes(tag, field.Write())
@@ -270,6 +345,12 @@ func (m *FieldMap) SetInt(tag Tag, value int) *FieldMap {
 	return m.SetBytes(tag, v.Write())
 }
 
+// SetDecimal is a SetField wrapper for float fields, such as prices, quantities and amounts. The value is written
+// with all of its digits after the decimal point, see NewFIXDecimal.
+func (m *FieldMap) SetDecimal(tag Tag, value decimal.Decimal) *FieldMap {
+	return m.SetField(tag, NewFIXDecimal(value))
+}
+
 // SetString is a SetField wrapper for string fields.
 func (m *FieldMap) SetString(tag Tag, value string) *FieldMap {
 	return m.SetBytes(tag, []byte(value))
@@ -281,6 +362,7 @@ func (m *FieldMap) Remove(tag Tag) {
 	defer m.rwLock.Unlock()
 
 	delete(m.tagLookup, tag)
+	delete(m.groups, tag)
 }
 
 // Clear purges all fields from field map.
@@ -292,6 +374,7 @@ func (m *FieldMap) Clear() {
 	for k := range m.tagLookup {
 		delete(m.tagLookup, k)
 	}
+	clear(m.groups)
 }
 
 func (m *FieldMap) clearNoLock() {
@@ -299,6 +382,7 @@ func (m *FieldMap) clearNoLock() {
 	for k := range m.tagLookup {
 		delete(m.tagLookup, k)
 	}
+	clear(m.groups)
 }
 
 // CopyInto overwrites the given FieldMap with this one.
@@ -315,6 +399,7 @@ func (m *FieldMap) CopyInto(to *FieldMap) {
 	to.tags = make([]Tag, len(m.tags))
 	copy(to.tags, m.tags)
 	to.compare = m.compare
+	to.groups = nil
 }
 
 func (m *FieldMap) add(f field) {
@@ -358,10 +443,15 @@ func (m *FieldMap) SetGroup(field FieldGroupWriter) *FieldMap {
 		m.tags = append(m.tags, field.Tag())
 	}
 	m.tagLookup[field.Tag()] = field.Write()
+	delete(m.groups, field.Tag())
 	return m
 }
 
 func (m *FieldMap) sortedTags() []Tag {
+	if m.preserveOrder {
+		return m.tags
+	}
+
 	sort.Sort(m)
 	return m.tags
 }
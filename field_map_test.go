@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -118,6 +119,34 @@ func TestFieldMap_TypedSetAndGet(t *testing.T) {
 	assert.True(t, bytes.Equal([]byte("hello"), b))
 }
 
+func TestFieldMap_DecimalTypedSetAndGet(t *testing.T) {
+	var fMap FieldMap
+	fMap.init()
+
+	fMap.SetDecimal(44, decimal.RequireFromString("100.10"))
+	fMap.SetDecimal(38, decimal.New(25, 2))
+	fMap.SetString(1, "hello")
+
+	s, err := fMap.GetString(44)
+	assert.Nil(t, err)
+	assert.Equal(t, "100.10", s)
+
+	s, err = fMap.GetString(38)
+	assert.Nil(t, err)
+	assert.Equal(t, "2500", s)
+
+	fMap.SetString(44, "0.30000000000000004")
+	d, err := fMap.GetDecimal(44)
+	assert.Nil(t, err)
+	assert.True(t, decimal.RequireFromString("0.30000000000000004").Equal(d))
+
+	_, err = fMap.GetDecimal(1)
+	assert.NotNil(t, err, "Type mismatch should occur error")
+
+	_, err = fMap.GetDecimal(2)
+	assert.NotNil(t, err, "Missing field should occur error")
+}
+
 func TestFieldMap_BoolTypedSetAndGet(t *testing.T) {
 	var fMap FieldMap
 	fMap.init()
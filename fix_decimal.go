@@ -25,6 +25,17 @@ type FIXDecimal struct {
 	Scale int32
 }
 
+// NewFIXDecimal returns a FIXDecimal of d written with the digits after the decimal point of d, without rounding,
+// e.g. 1.50 is written as 1.50 and 100 as 100.
+func NewFIXDecimal(d decimal.Decimal) FIXDecimal {
+	var scale int32
+	if exp := d.Exponent(); exp < 0 {
+		scale = -exp
+	}
+
+	return FIXDecimal{Decimal: d, Scale: scale}
+}
+
 func (d FIXDecimal) Write() []byte {
 	return []byte(d.Decimal.StringFixed(d.Scale))
 }
@@ -54,6 +54,9 @@ func TestFIXDecimalWrite(t *testing.T) {
 		{decimal: FIXDecimal{Decimal: decimal.New(-1243456, -4), Scale: 4}, expected: "-124.3456"},
 		{decimal: FIXDecimal{Decimal: decimal.New(-1243456, -4), Scale: 5}, expected: "-124.34560"},
 		{decimal: FIXDecimal{Decimal: decimal.New(-1243456, -4), Scale: 0}, expected: "-124"},
+		{decimal: NewFIXDecimal(decimal.New(-1243456, -4)), expected: "-124.3456"},
+		{decimal: NewFIXDecimal(decimal.RequireFromString("100.10")), expected: "100.10"},
+		{decimal: NewFIXDecimal(decimal.New(15, 2)), expected: "1500"},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,158 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FIXTZTimestamp is a FIX TZTimestamp value, a timestamp with the offset of its time zone, implements FieldValue.
+type FIXTZTimestamp struct {
+	time.Time
+	Precision TimestampPrecision
+}
+
+// FIXTZTimeOnly is a FIX TZTimeOnly value, a time of day with the offset of its time zone, implements FieldValue.
+// The date of Time is ignored.
+type FIXTZTimeOnly struct {
+	time.Time
+	Precision TimestampPrecision
+}
+
+const (
+	tzTimestampDateFormat = "20060102-"
+	tzTimeFormat          = "15:04:05"
+)
+
+func (f *FIXTZTimestamp) Read(bytes []byte) (err error) {
+	f.Time, f.Precision, err = parseTZTime(tzTimestampDateFormat, string(bytes))
+	if err != nil {
+		err = errors.New("Invalid Value for TZTimestamp: " + string(bytes))
+	}
+
+	return
+}
+
+func (f FIXTZTimestamp) Write() []byte {
+	return []byte(formatTZTime(f.Time, tzTimestampDateFormat, f.Precision))
+}
+
+func (f *FIXTZTimeOnly) Read(bytes []byte) (err error) {
+	f.Time, f.Precision, err = parseTZTime("", string(bytes))
+	if err != nil {
+		err = errors.New("Invalid Value for TZTimeOnly: " + string(bytes))
+	}
+
+	return
+}
+
+func (f FIXTZTimeOnly) Write() []byte {
+	return []byte(formatTZTime(f.Time, "", f.Precision))
+}
+
+// parseTZTime parses value as HH:MM[:SS[.sss[sss[sss]]]] followed by Z or the offset +hh[:mm] or -hh[:mm], after the
+// date of dateFormat if any. Values without seconds are read with a precision of seconds.
+func parseTZTime(dateFormat, value string) (t time.Time, precision TimestampPrecision, err error) {
+	if len(value) < len(dateFormat) {
+		return t, precision, errors.New("value too short")
+	}
+
+	var loc *time.Location
+	timeStart := len(dateFormat)
+	if strings.HasSuffix(value, "Z") {
+		loc = time.UTC
+		value = value[:len(value)-1]
+	} else {
+		i := strings.LastIndexAny(value[timeStart:], "+-")
+		if i < 0 {
+			return t, precision, errors.New("missing time zone")
+		}
+
+		i += timeStart
+		if loc, err = parseTZOffset(value[i:]); err != nil {
+			return
+		}
+		value = value[:i]
+	}
+
+	layout := dateFormat
+	switch len(value) - timeStart {
+	case 5:
+		layout += tzTimeFormat[:5]
+		precision = Seconds
+	case 8:
+		layout += tzTimeFormat
+		precision = Seconds
+	case 12:
+		layout += tzTimeFormat + ".000"
+		precision = Millis
+	case 15:
+		layout += tzTimeFormat + ".000000"
+		precision = Micros
+	case 18:
+		layout += tzTimeFormat + ".000000000"
+		precision = Nanos
+	default:
+		return t, precision, errors.New("invalid time")
+	}
+
+	t, err = time.ParseInLocation(layout, value, loc)
+	return
+}
+
+// parseTZOffset parses the offsets +hh, -hh, +hh:mm and -hh:mm.
+func parseTZOffset(offset string) (*time.Location, error) {
+	if len(offset) != 3 && (len(offset) != 6 || offset[3] != ':') {
+		return nil, errors.New("invalid time zone offset")
+	}
+
+	hours, err := strconv.Atoi(offset[1:3])
+	if err != nil || hours > 14 {
+		return nil, errors.New("invalid time zone offset")
+	}
+
+	var minutes int
+	if len(offset) == 6 {
+		if minutes, err = strconv.Atoi(offset[4:]); err != nil || minutes > 59 {
+			return nil, errors.New("invalid time zone offset")
+		}
+	}
+
+	seconds := hours*3600 + minutes*60
+	if offset[0] == '-' {
+		seconds = -seconds
+	}
+
+	return time.FixedZone("", seconds), nil
+}
+
+// formatTZTime formats t in its location with its offset, as Z if it is UTC.
+func formatTZTime(t time.Time, dateFormat string, precision TimestampPrecision) string {
+	layout := dateFormat + tzTimeFormat
+	switch precision {
+	case Millis:
+		layout += ".000"
+	case Micros:
+		layout += ".000000"
+	case Nanos:
+		layout += ".000000000"
+	}
+
+	return t.Format(layout + "Z07:00")
+}
@@ -0,0 +1,124 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFIXTZTimestampWrite(t *testing.T) {
+	ts := time.Date(2016, time.February, 8, 22, 7, 16, 954123123, time.UTC)
+	india := time.FixedZone("IST", 5*3600+1800)
+	eastern := time.FixedZone("EST", -5*3600)
+
+	var tests = []struct {
+		value    FIXTZTimestamp
+		expected string
+	}{
+		{FIXTZTimestamp{Time: ts, Precision: Seconds}, "20160208-22:07:16Z"},
+		{FIXTZTimestamp{Time: ts, Precision: Millis}, "20160208-22:07:16.954Z"},
+		{FIXTZTimestamp{Time: ts, Precision: Micros}, "20160208-22:07:16.954123Z"},
+		{FIXTZTimestamp{Time: ts, Precision: Nanos}, "20160208-22:07:16.954123123Z"},
+		{FIXTZTimestamp{Time: ts.In(india), Precision: Micros}, "20160209-03:37:16.954123+05:30"},
+		{FIXTZTimestamp{Time: ts.In(eastern), Precision: Seconds}, "20160208-17:07:16-05:00"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, string(test.value.Write()))
+	}
+}
+
+func TestFIXTZTimestampRead(t *testing.T) {
+	var tests = []struct {
+		value             string
+		expectedTime      time.Time
+		expectedPrecision TimestampPrecision
+		expectedOffset    int
+		expectError       bool
+	}{
+		{value: "20060901-07:39Z", expectedTime: time.Date(2006, time.September, 1, 7, 39, 0, 0, time.UTC), expectedPrecision: Seconds},
+		{value: "20060901-02:39-05", expectedTime: time.Date(2006, time.September, 1, 7, 39, 0, 0, time.UTC), expectedPrecision: Seconds, expectedOffset: -5 * 3600},
+		{value: "20060901-15:39:12+08", expectedTime: time.Date(2006, time.September, 1, 7, 39, 12, 0, time.UTC), expectedPrecision: Seconds, expectedOffset: 8 * 3600},
+		{value: "20060901-13:09:12.310+05:30", expectedTime: time.Date(2006, time.September, 1, 7, 39, 12, 310000000, time.UTC), expectedPrecision: Millis, expectedOffset: 5*3600 + 1800},
+		{value: "20060901-07:39:12.123456Z", expectedTime: time.Date(2006, time.September, 1, 7, 39, 12, 123456000, time.UTC), expectedPrecision: Micros},
+		{value: "20060901-07:39:12.123456789Z", expectedTime: time.Date(2006, time.September, 1, 7, 39, 12, 123456789, time.UTC), expectedPrecision: Nanos},
+		{value: "20060901-07:39:12", expectError: true},
+		{value: "20060901-07:39:12.1Z", expectError: true},
+		{value: "20060901-07:39:12+5", expectError: true},
+		{value: "20060901-07:39:12+05:3", expectError: true},
+		{value: "20060901-07:39:12+15", expectError: true},
+		{value: "20060901Z", expectError: true},
+		{value: "Z", expectError: true},
+	}
+
+	for _, test := range tests {
+		var f FIXTZTimestamp
+		err := f.Read([]byte(test.value))
+		if test.expectError {
+			assert.NotNil(t, err, test.value)
+			continue
+		}
+
+		require.Nil(t, err, test.value)
+		assert.True(t, test.expectedTime.Equal(f.Time), test.value)
+		assert.Equal(t, test.expectedPrecision, f.Precision, test.value)
+
+		_, offset := f.Zone()
+		assert.Equal(t, test.expectedOffset, offset, test.value)
+	}
+}
+
+func TestFIXTZTimeOnlyReadWrite(t *testing.T) {
+	var tests = []struct {
+		value             string
+		expectedHour      int
+		expectedNanos     int
+		expectedPrecision TimestampPrecision
+		expectedOffset    int
+		expectedWrite     string
+		expectError       bool
+	}{
+		{value: "07:39Z", expectedHour: 7, expectedPrecision: Seconds, expectedWrite: "07:39:00Z"},
+		{value: "02:39-05", expectedHour: 2, expectedPrecision: Seconds, expectedOffset: -5 * 3600, expectedWrite: "02:39:00-05:00"},
+		{value: "13:09:00.250+05:30", expectedHour: 13, expectedNanos: 250000000, expectedPrecision: Millis, expectedOffset: 5*3600 + 1800, expectedWrite: "13:09:00.250+05:30"},
+		{value: "15:39:00.000001+08", expectedHour: 15, expectedNanos: 1000, expectedPrecision: Micros, expectedOffset: 8 * 3600, expectedWrite: "15:39:00.000001+08:00"},
+		{value: "07:39", expectError: true},
+		{value: "7:39Z", expectError: true},
+		{value: "-05", expectError: true},
+	}
+
+	for _, test := range tests {
+		var f FIXTZTimeOnly
+		err := f.Read([]byte(test.value))
+		if test.expectError {
+			assert.NotNil(t, err, test.value)
+			continue
+		}
+
+		require.Nil(t, err, test.value)
+		assert.Equal(t, test.expectedHour, f.Hour(), test.value)
+		assert.Equal(t, test.expectedNanos, f.Nanosecond(), test.value)
+		assert.Equal(t, test.expectedPrecision, f.Precision, test.value)
+
+		_, offset := f.Zone()
+		assert.Equal(t, test.expectedOffset, offset, test.value)
+		assert.Equal(t, test.expectedWrite, string(f.Write()), test.value)
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fixp
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// FlowType is the type of the flow of messages from one side of a session.
+type FlowType uint8
+
+// The flow types.
+const (
+	// Recoverable flows are sequenced, and messages missed by the receiver are retransmitted from the MessageStore.
+	Recoverable FlowType = iota
+
+	// Unsequenced flows carry messages without sequence numbers.
+	Unsequenced
+
+	// Idempotent flows are sequenced, so that duplicates are recognized, but not recovered.
+	Idempotent
+
+	// None is the flow of a side not sending application messages.
+	None
+)
+
+func (f FlowType) String() string {
+	switch f {
+	case Recoverable:
+		return "Recoverable"
+	case Unsequenced:
+		return "Unsequenced"
+	case Idempotent:
+		return "Idempotent"
+	case None:
+		return "None"
+	}
+
+	return fmt.Sprintf("FlowType(%d)", uint8(f))
+}
+
+func (f FlowType) sequenced() bool {
+	return f == Recoverable || f == Idempotent
+}
+
+// The codes of NegotiationReject.
+const (
+	NegotiationRejectUnspecified uint8 = iota
+	NegotiationRejectCredentials
+	NegotiationRejectFlowTypeNotSupported
+	NegotiationRejectDuplicateID
+)
+
+// The codes of EstablishmentReject.
+const (
+	EstablishmentRejectUnnegotiated uint8 = iota
+	EstablishmentRejectAlreadyEstablished
+	EstablishmentRejectSessionBlocked
+	EstablishmentRejectKeepaliveInterval
+	EstablishmentRejectCredentials
+	EstablishmentRejectUnspecified
+)
+
+// The codes of Terminate.
+const (
+	TerminateFinished uint8 = iota
+	TerminateUnspecified
+	TerminateReRequestOutOfBounds
+	TerminateReRequestInProgress
+	TerminateKeepaliveLapsed
+)
+
+// The codes of RetransmitReject.
+const (
+	RetransmitRejectOutOfRange uint8 = iota
+	RetransmitRejectInvalidSession
+	RetransmitRejectRequestLimitExceeded
+)
+
+// The template ids of the session messages.
+const (
+	templateNegotiate            uint16 = 500
+	templateNegotiationResponse  uint16 = 501
+	templateNegotiationReject    uint16 = 502
+	templateEstablish            uint16 = 503
+	templateEstablishmentAck     uint16 = 504
+	templateEstablishmentReject  uint16 = 505
+	templateSequence             uint16 = 506
+	templateTerminate            uint16 = 507
+	templateRetransmitRequest    uint16 = 508
+	templateRetransmission       uint16 = 509
+	templateRetransmitReject     uint16 = 510
+	templateUnsequencedHeartbeat uint16 = 512
+)
+
+var templateNames = map[uint16]string{
+	templateNegotiate:            "Negotiate",
+	templateNegotiationResponse:  "NegotiationResponse",
+	templateNegotiationReject:    "NegotiationReject",
+	templateEstablish:            "Establish",
+	templateEstablishmentAck:     "EstablishmentAck",
+	templateEstablishmentReject:  "EstablishmentReject",
+	templateSequence:             "Sequence",
+	templateTerminate:            "Terminate",
+	templateRetransmitRequest:    "RetransmitRequest",
+	templateRetransmission:       "Retransmission",
+	templateRetransmitReject:     "RetransmitReject",
+	templateUnsequencedHeartbeat: "UnsequencedHeartbeat",
+}
+
+// The Simple Open Framing Header encoding types of session and application messages.
+const (
+	encodingSBE      uint16 = 0x5be0
+	encodingTagValue uint16 = 0xf000
+)
+
+const (
+	schemaID      uint16 = 0xfffe
+	schemaVersion uint16 = 0
+
+	sofhSize      = 6
+	sbeHeaderSize = 8
+
+	// maxFrameSize bounds the size of a frame read from a counterparty.
+	maxFrameSize = 1 << 20
+)
+
+// UUID identifies a negotiated session.
+type UUID [16]byte
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		return u, err
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+
+	return u, nil
+}
+
+func (u UUID) String() string {
+	s := hex.EncodeToString(u[:])
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// field is a field of the block of a session message.
+type field int
+
+const (
+	fieldSessionID field = iota
+	fieldTimestamp
+	fieldFlow
+	fieldKeepaliveInterval
+	fieldNextSeqNo
+	fieldFromSeqNo
+	fieldCount
+	fieldCode
+)
+
+var fieldSizes = map[field]int{
+	fieldSessionID:         16,
+	fieldTimestamp:         8,
+	fieldFlow:              1,
+	fieldKeepaliveInterval: 4,
+	fieldNextSeqNo:         8,
+	fieldFromSeqNo:         8,
+	fieldCount:             4,
+	fieldCode:              1,
+}
+
+// layouts are the fields of the blocks of the session messages, in order. The timestamp of responses is the timestamp
+// of the request.
+var layouts = map[uint16][]field{
+	templateNegotiate:            {fieldSessionID, fieldTimestamp, fieldFlow},
+	templateNegotiationResponse:  {fieldSessionID, fieldTimestamp, fieldFlow},
+	templateNegotiationReject:    {fieldSessionID, fieldTimestamp, fieldCode},
+	templateEstablish:            {fieldSessionID, fieldTimestamp, fieldKeepaliveInterval, fieldNextSeqNo},
+	templateEstablishmentAck:     {fieldSessionID, fieldTimestamp, fieldKeepaliveInterval, fieldNextSeqNo},
+	templateEstablishmentReject:  {fieldSessionID, fieldTimestamp, fieldCode},
+	templateSequence:             {fieldNextSeqNo},
+	templateTerminate:            {fieldSessionID, fieldCode},
+	templateRetransmitRequest:    {fieldSessionID, fieldTimestamp, fieldFromSeqNo, fieldCount},
+	templateRetransmission:       {fieldSessionID, fieldTimestamp, fieldNextSeqNo, fieldCount},
+	templateRetransmitReject:     {fieldSessionID, fieldTimestamp, fieldCode},
+	templateUnsequencedHeartbeat: {},
+}
+
+// sessionMessage is a session message, with the fields of its template set.
+type sessionMessage struct {
+	templateID uint16
+
+	sessionID         UUID
+	timestamp         uint64
+	flow              FlowType
+	keepaliveInterval uint32
+	nextSeqNo         uint64
+	fromSeqNo         uint64
+	count             uint32
+	code              uint8
+
+	// credentials are the credentials of Negotiate, encoded as variable length data.
+	credentials []byte
+}
+
+func (m sessionMessage) String() string {
+	return templateNames[m.templateID]
+}
+
+func blockLength(templateID uint16) int {
+	length := 0
+	for _, f := range layouts[templateID] {
+		length += fieldSizes[f]
+	}
+
+	return length
+}
+
+// encode returns the message with its SBE message header.
+func (m sessionMessage) encode() []byte {
+	layout := layouts[m.templateID]
+	length := blockLength(m.templateID)
+
+	b := make([]byte, sbeHeaderSize, sbeHeaderSize+length+2+len(m.credentials))
+	binary.LittleEndian.PutUint16(b[0:], uint16(length))
+	binary.LittleEndian.PutUint16(b[2:], m.templateID)
+	binary.LittleEndian.PutUint16(b[4:], schemaID)
+	binary.LittleEndian.PutUint16(b[6:], schemaVersion)
+
+	for _, f := range layout {
+		switch f {
+		case fieldSessionID:
+			b = append(b, m.sessionID[:]...)
+		case fieldTimestamp:
+			b = binary.LittleEndian.AppendUint64(b, m.timestamp)
+		case fieldFlow:
+			b = append(b, byte(m.flow))
+		case fieldKeepaliveInterval:
+			b = binary.LittleEndian.AppendUint32(b, m.keepaliveInterval)
+		case fieldNextSeqNo:
+			b = binary.LittleEndian.AppendUint64(b, m.nextSeqNo)
+		case fieldFromSeqNo:
+			b = binary.LittleEndian.AppendUint64(b, m.fromSeqNo)
+		case fieldCount:
+			b = binary.LittleEndian.AppendUint32(b, m.count)
+		case fieldCode:
+			b = append(b, m.code)
+		}
+	}
+
+	if m.templateID == templateNegotiate {
+		b = binary.LittleEndian.AppendUint16(b, uint16(len(m.credentials)))
+		b = append(b, m.credentials...)
+	}
+
+	return b
+}
+
+var errShortMessage = errors.New("fixp: short message")
+
+// decodeSessionMessage decodes a session message with its SBE message header.
+func decodeSessionMessage(b []byte) (m sessionMessage, err error) {
+	if len(b) < sbeHeaderSize {
+		return m, errShortMessage
+	}
+
+	length := int(binary.LittleEndian.Uint16(b[0:]))
+	m.templateID = binary.LittleEndian.Uint16(b[2:])
+	if id := binary.LittleEndian.Uint16(b[4:]); id != schemaID {
+		return m, fmt.Errorf("fixp: unknown schema id %v", id)
+	}
+
+	layout, ok := layouts[m.templateID]
+	if !ok {
+		return m, fmt.Errorf("fixp: unknown template id %v", m.templateID)
+	}
+
+	b = b[sbeHeaderSize:]
+	if length < blockLength(m.templateID) || len(b) < length {
+		return m, errShortMessage
+	}
+
+	block := b
+	for _, f := range layout {
+		switch f {
+		case fieldSessionID:
+			copy(m.sessionID[:], block)
+		case fieldTimestamp:
+			m.timestamp = binary.LittleEndian.Uint64(block)
+		case fieldFlow:
+			m.flow = FlowType(block[0])
+		case fieldKeepaliveInterval:
+			m.keepaliveInterval = binary.LittleEndian.Uint32(block)
+		case fieldNextSeqNo:
+			m.nextSeqNo = binary.LittleEndian.Uint64(block)
+		case fieldFromSeqNo:
+			m.fromSeqNo = binary.LittleEndian.Uint64(block)
+		case fieldCount:
+			m.count = binary.LittleEndian.Uint32(block)
+		case fieldCode:
+			m.code = block[0]
+		}
+		block = block[fieldSizes[f]:]
+	}
+
+	if m.templateID == templateNegotiate {
+		data := b[length:]
+		if len(data) < 2 || len(data)-2 < int(binary.LittleEndian.Uint16(data)) {
+			return m, errShortMessage
+		}
+		m.credentials = append([]byte(nil), data[2:2+int(binary.LittleEndian.Uint16(data))]...)
+	}
+
+	return m, nil
+}
+
+// writeFrame writes a message with a Simple Open Framing Header in a single write.
+func writeFrame(w io.Writer, encoding uint16, msg []byte) error {
+	frame := make([]byte, sofhSize, sofhSize+len(msg))
+	binary.BigEndian.PutUint32(frame[0:], uint32(sofhSize+len(msg)))
+	binary.BigEndian.PutUint16(frame[4:], encoding)
+
+	_, err := w.Write(append(frame, msg...))
+	return err
+}
+
+// readFrame reads the next message with a Simple Open Framing Header, returning its encoding type.
+func readFrame(r *bufio.Reader) (uint16, []byte, error) {
+	header := make([]byte, sofhSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[0:])
+	if size < sofhSize || size > maxFrameSize {
+		return 0, nil, fmt.Errorf("fixp: invalid message length %v", size)
+	}
+
+	msg := make([]byte, size-sofhSize)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return 0, nil, err
+	}
+
+	return binary.BigEndian.Uint16(header[4:]), msg, nil
+}
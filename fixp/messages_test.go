@@ -0,0 +1,105 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fixp
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionMessageEncodeDecode(t *testing.T) {
+	uuid, err := newUUID()
+	require.Nil(t, err)
+
+	var tests = []sessionMessage{
+		{templateID: templateNegotiate, sessionID: uuid, timestamp: 1, flow: Idempotent, credentials: []byte("secret")},
+		{templateID: templateNegotiationResponse, sessionID: uuid, timestamp: 2, flow: Unsequenced},
+		{templateID: templateNegotiationReject, sessionID: uuid, timestamp: 3, code: NegotiationRejectCredentials},
+		{templateID: templateEstablish, sessionID: uuid, timestamp: 4, keepaliveInterval: 1000, nextSeqNo: 5},
+		{templateID: templateEstablishmentAck, sessionID: uuid, timestamp: 5, keepaliveInterval: 1000, nextSeqNo: 1},
+		{templateID: templateEstablishmentReject, sessionID: uuid, timestamp: 6, code: EstablishmentRejectUnnegotiated},
+		{templateID: templateSequence, nextSeqNo: 42},
+		{templateID: templateTerminate, sessionID: uuid, code: TerminateKeepaliveLapsed},
+		{templateID: templateRetransmitRequest, sessionID: uuid, timestamp: 7, fromSeqNo: 3, count: 4},
+		{templateID: templateRetransmission, sessionID: uuid, timestamp: 7, nextSeqNo: 3, count: 4},
+		{templateID: templateRetransmitReject, sessionID: uuid, timestamp: 7, code: RetransmitRejectOutOfRange},
+		{templateID: templateUnsequencedHeartbeat},
+	}
+
+	for _, test := range tests {
+		t.Run(test.String(), func(t *testing.T) {
+			m, err := decodeSessionMessage(test.encode())
+			require.Nil(t, err)
+			if test.templateID != templateNegotiate {
+				test.credentials = nil
+			}
+			assert.Equal(t, test, m)
+		})
+	}
+}
+
+func TestDecodeSessionMessageErrors(t *testing.T) {
+	negotiate := sessionMessage{templateID: templateNegotiate, credentials: []byte("secret")}.encode()
+	unknown := sessionMessage{templateID: templateSequence}.encode()
+	unknown[2] = 0xff
+
+	var tests = []struct {
+		name string
+		data []byte
+	}{
+		{"short header", negotiate[:4]},
+		{"short block", negotiate[:sbeHeaderSize+10]},
+		{"short credentials", negotiate[:len(negotiate)-1]},
+		{"unknown template", unknown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decodeSessionMessage(test.data)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestFrames(t *testing.T) {
+	var buf bytes.Buffer
+	require.Nil(t, writeFrame(&buf, encodingSBE, []byte{1, 2, 3}))
+	require.Nil(t, writeFrame(&buf, encodingTagValue, []byte("8=FIX.4.4\x01")))
+	assert.Equal(t, []byte{0, 0, 0, 9, 0x5b, 0xe0, 1, 2, 3}, buf.Bytes()[:9])
+
+	r := bufio.NewReader(&buf)
+	encoding, msg, err := readFrame(r)
+	require.Nil(t, err)
+	assert.Equal(t, encodingSBE, encoding)
+	assert.Equal(t, []byte{1, 2, 3}, msg)
+
+	encoding, msg, err = readFrame(r)
+	require.Nil(t, err)
+	assert.Equal(t, encodingTagValue, encoding)
+	assert.Equal(t, "8=FIX.4.4\x01", string(msg))
+
+	_, _, err = readFrame(bufio.NewReader(bytes.NewReader([]byte{0, 0, 0, 2, 0x5b, 0xe0})))
+	assert.NotNil(t, err)
+}
+
+func TestUUIDString(t *testing.T) {
+	u := UUID{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0x4d, 0xef, 0x80, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}
+	assert.Equal(t, "12345678-9abc-4def-8001-020304050607", u.String())
+}
@@ -0,0 +1,762 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package fixp implements sessions of the FIX Performance Session Layer (FIXP), as an alternative to the FIX session
+// layer for venues that have adopted it.
+//
+// Session messages (Negotiate, Establish, Sequence, RetransmitRequest, Terminate, ...) are encoded with SBE, and
+// application messages are FIX tag=value messages delivered to a quickfix.Application. Each message is framed with a
+// Simple Open Framing Header, so sessions run over TCP connections and connected UDP sockets alike. The outbound
+// sequence numbers and messages of recoverable flows are kept in a quickfix.MessageStore, from which messages missed
+// by the counterparty are retransmitted.
+package fixp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Role is the role of a side of a session.
+type Role int
+
+// The roles of sessions.
+const (
+	// Client negotiates and establishes sessions.
+	Client Role = iota
+
+	// Server accepts the sessions negotiated and established by clients.
+	Server
+)
+
+const defaultKeepaliveInterval = 10 * time.Second
+
+// ErrNotEstablished is returned when sending a message on a session that is not established, and whose messages can
+// not be retransmitted later.
+var ErrNotEstablished = errors.New("fixp: session not established")
+
+// Config configures a Session.
+type Config struct {
+	// Flow is the flow of the application messages sent by this side of the session.
+	Flow FlowType
+
+	// KeepaliveInterval is the longest time a side of an established session remains silent before sending a
+	// heartbeat. It is proposed by the client when establishing the session, and a session whose counterparty is
+	// silent for two intervals is terminated. Defaults to 10 seconds.
+	KeepaliveInterval time.Duration
+
+	// Credentials are sent by a client when negotiating a session.
+	Credentials []byte
+
+	// Authenticate authenticates the credentials of clients negotiating with a server. All clients are accepted if
+	// it is not set.
+	Authenticate func(credentials []byte) bool
+}
+
+// Session is one side of a FIXP session, identified to the Application, MessageStore and Log by a SessionID.
+type Session struct {
+	role      Role
+	sessionID quickfix.SessionID
+	config    Config
+	app       quickfix.Application
+	store     quickfix.MessageStore
+	log       quickfix.Log
+
+	mu          sync.Mutex
+	conn        net.Conn
+	uuid        UUID
+	negotiated  bool
+	established bool
+	terminating bool
+	peerFlow    FlowType
+	keepalive   time.Duration
+	lastSent    time.Time
+
+	// The state of the inbound flow, only used by the goroutine running the session.
+	lastReceived time.Time
+	inSeqNo      uint64
+	retransmits  uint32
+	requesting   bool
+	pending      map[uint64]*quickfix.Message
+}
+
+// NewSession creates a Session of role, calling OnCreate of app.
+func NewSession(
+	role Role,
+	sessionID quickfix.SessionID,
+	config Config,
+	app quickfix.Application,
+	storeFactory quickfix.MessageStoreFactory,
+	logFactory quickfix.LogFactory,
+) (*Session, error) {
+	if config.KeepaliveInterval <= 0 {
+		config.KeepaliveInterval = defaultKeepaliveInterval
+	}
+
+	store, err := storeFactory.Create(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	log, err := logFactory.CreateSessionLog(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{role: role, sessionID: sessionID, config: config, app: app, store: store, log: log}
+	app.OnCreate(sessionID)
+
+	return s, nil
+}
+
+// SessionID returns the SessionID of the session.
+func (s *Session) SessionID() quickfix.SessionID {
+	return s.sessionID
+}
+
+// UUID returns the UUID of the negotiated session, and false if the session has not been negotiated.
+func (s *Session) UUID() (UUID, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.uuid, s.negotiated
+}
+
+// Dial connects to address on network, "tcp" or "udp", and runs the client side of the session on the connection.
+func (s *Session) Dial(network, address string) error {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return err
+	}
+
+	return s.Run(conn)
+}
+
+// Run runs the session on conn until it is terminated or disconnected, then closes conn. A client negotiates the
+// session if it has not been negotiated before, and establishes it. A server waits for the client to do so. Run
+// returns nil if the session was terminated by either side.
+func (s *Session) Run(conn net.Conn) error {
+	s.mu.Lock()
+	if s.conn != nil {
+		s.mu.Unlock()
+		return errors.New("fixp: session already connected")
+	}
+	s.conn = conn
+	s.mu.Unlock()
+
+	s.lastReceived = time.Now()
+	defer s.disconnect()
+
+	frames := make(chan frame)
+	stop := make(chan struct{})
+	defer close(stop)
+	go readFrames(conn, frames, stop)
+
+	if s.role == Client {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(s.config.KeepaliveInterval / 4)
+	defer ticker.Stop()
+
+	started := time.Now()
+	for {
+		select {
+		case f := <-frames:
+			if f.err != nil {
+				s.mu.Lock()
+				terminating := s.terminating
+				s.mu.Unlock()
+				if terminating || errors.Is(f.err, io.EOF) {
+					return nil
+				}
+				return f.err
+			}
+
+			s.lastReceived = time.Now()
+			done, err := s.onFrame(f)
+			if done || err != nil {
+				return err
+			}
+
+		case now := <-ticker.C:
+			if err := s.onTick(now, started); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+type frame struct {
+	encoding uint16
+	msg      []byte
+	err      error
+}
+
+func readFrames(conn net.Conn, frames chan<- frame, stop <-chan struct{}) {
+	r := bufio.NewReaderSize(conn, 1<<16)
+	for {
+		encoding, msg, err := readFrame(r)
+		select {
+		case frames <- frame{encoding: encoding, msg: msg, err: err}:
+		case <-stop:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// start negotiates the session if needed, and establishes it.
+func (s *Session) start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.negotiated {
+		return s.establishLocked()
+	}
+
+	uuid, err := newUUID()
+	if err != nil {
+		return err
+	}
+	s.uuid = uuid
+
+	return s.sendLocked(sessionMessage{
+		templateID:  templateNegotiate,
+		sessionID:   s.uuid,
+		timestamp:   timestamp(),
+		flow:        s.config.Flow,
+		credentials: s.config.Credentials,
+	})
+}
+
+func (s *Session) establishLocked() error {
+	return s.sendLocked(sessionMessage{
+		templateID:        templateEstablish,
+		sessionID:         s.uuid,
+		timestamp:         timestamp(),
+		keepaliveInterval: uint32(s.config.KeepaliveInterval / time.Millisecond),
+		nextSeqNo:         s.nextSeqNoLocked(),
+	})
+}
+
+// nextSeqNoLocked returns the sequence number of the next outbound message, 0 for unsequenced flows.
+func (s *Session) nextSeqNoLocked() uint64 {
+	if !s.config.Flow.sequenced() {
+		return 0
+	}
+
+	return uint64(s.store.NextSenderMsgSeqNum())
+}
+
+func (s *Session) disconnect() {
+	s.mu.Lock()
+	established := s.established
+	s.conn.Close()
+	s.conn = nil
+	s.established = false
+	s.terminating = false
+	s.mu.Unlock()
+
+	s.inSeqNo, s.retransmits, s.requesting, s.pending = 0, 0, false, nil
+
+	if established {
+		s.log.OnEvent("Session disconnected")
+		s.app.OnLogout(s.sessionID)
+	}
+}
+
+func (s *Session) onTick(now, started time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.established {
+		if now.Sub(started) > 2*s.config.KeepaliveInterval {
+			return errors.New("fixp: session not established in time")
+		}
+		return nil
+	}
+
+	if now.Sub(s.lastReceived) > 2*s.keepalive {
+		s.terminating = true
+		if err := s.sendLocked(sessionMessage{
+			templateID: templateTerminate, sessionID: s.uuid, code: TerminateKeepaliveLapsed,
+		}); err != nil {
+			return err
+		}
+		return errors.New("fixp: keepalive interval lapsed")
+	}
+
+	if now.Sub(s.lastSent) >= s.keepalive {
+		return s.heartbeatLocked()
+	}
+
+	return nil
+}
+
+func (s *Session) heartbeatLocked() error {
+	if s.config.Flow.sequenced() {
+		return s.sendLocked(sessionMessage{templateID: templateSequence, nextSeqNo: s.nextSeqNoLocked()})
+	}
+
+	return s.sendLocked(sessionMessage{templateID: templateUnsequencedHeartbeat})
+}
+
+// onFrame handles a received message, returning true when the session is terminated.
+func (s *Session) onFrame(f frame) (bool, error) {
+	switch f.encoding {
+	case encodingTagValue:
+		return false, s.onApplicationMessage(f.msg)
+	case encodingSBE:
+	default:
+		return false, fmt.Errorf("fixp: unknown encoding type %#x", f.encoding)
+	}
+
+	m, err := decodeSessionMessage(f.msg)
+	if err != nil {
+		return false, err
+	}
+	s.log.OnEventf("Received %v", m)
+
+	switch m.templateID {
+	case templateNegotiate:
+		return false, s.onNegotiate(m)
+	case templateNegotiationResponse:
+		return false, s.onNegotiationResponse(m)
+	case templateEstablish:
+		return false, s.onEstablish(m)
+	case templateEstablishmentAck:
+		return false, s.onEstablishmentAck(m)
+	case templateNegotiationReject, templateEstablishmentReject:
+		if m.templateID == templateEstablishmentReject && m.code == EstablishmentRejectUnnegotiated {
+			s.mu.Lock()
+			s.negotiated = false
+			s.mu.Unlock()
+		}
+		return false, fmt.Errorf("fixp: %v with code %v", m, m.code)
+	case templateSequence:
+		return false, s.onSequence(m.nextSeqNo)
+	case templateRetransmitRequest:
+		return false, s.onRetransmitRequest(m)
+	case templateRetransmission:
+		s.inSeqNo, s.retransmits = m.nextSeqNo, m.count
+		if m.count == 0 {
+			s.requesting = false
+		}
+		return false, nil
+	case templateRetransmitReject:
+		s.log.OnEventf("Retransmission rejected with code %v", m.code)
+		s.requesting = false
+		return false, s.skipGap()
+	case templateTerminate:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !s.terminating {
+			s.terminating = true
+			return true, s.sendLocked(sessionMessage{templateID: templateTerminate, sessionID: s.uuid, code: TerminateFinished})
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (s *Session) onNegotiate(m sessionMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reject := func(code uint8) error {
+		return s.sendLocked(sessionMessage{
+			templateID: templateNegotiationReject, sessionID: m.sessionID, timestamp: m.timestamp, code: code,
+		})
+	}
+
+	switch {
+	case s.role != Server || s.established:
+		return reject(NegotiationRejectUnspecified)
+	case s.negotiated && m.sessionID == s.uuid:
+		return reject(NegotiationRejectDuplicateID)
+	case s.config.Authenticate != nil && !s.config.Authenticate(m.credentials):
+		return reject(NegotiationRejectCredentials)
+	case m.flow > None:
+		return reject(NegotiationRejectFlowTypeNotSupported)
+	}
+
+	// A new session starts new flows.
+	if err := s.store.Reset(); err != nil {
+		return err
+	}
+	s.uuid, s.negotiated, s.peerFlow = m.sessionID, true, m.flow
+
+	return s.sendLocked(sessionMessage{
+		templateID: templateNegotiationResponse, sessionID: s.uuid, timestamp: m.timestamp, flow: s.config.Flow,
+	})
+}
+
+func (s *Session) onNegotiationResponse(m sessionMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.role != Client || m.sessionID != s.uuid {
+		return fmt.Errorf("fixp: unexpected %v", m)
+	}
+
+	if err := s.store.Reset(); err != nil {
+		return err
+	}
+	s.negotiated, s.peerFlow = true, m.flow
+
+	return s.establishLocked()
+}
+
+func (s *Session) onEstablish(m sessionMessage) error {
+	s.mu.Lock()
+
+	reject := func(code uint8) error {
+		defer s.mu.Unlock()
+		return s.sendLocked(sessionMessage{
+			templateID: templateEstablishmentReject, sessionID: m.sessionID, timestamp: m.timestamp, code: code,
+		})
+	}
+
+	switch {
+	case s.role != Server || !s.negotiated || m.sessionID != s.uuid:
+		return reject(EstablishmentRejectUnnegotiated)
+	case s.established:
+		return reject(EstablishmentRejectAlreadyEstablished)
+	case m.keepaliveInterval == 0:
+		return reject(EstablishmentRejectKeepaliveInterval)
+	}
+
+	s.keepalive = time.Duration(m.keepaliveInterval) * time.Millisecond
+	s.established = true
+	err := s.sendLocked(sessionMessage{
+		templateID:        templateEstablishmentAck,
+		sessionID:         s.uuid,
+		timestamp:         m.timestamp,
+		keepaliveInterval: m.keepaliveInterval,
+		nextSeqNo:         s.nextSeqNoLocked(),
+	})
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return s.onEstablished(m.nextSeqNo)
+}
+
+func (s *Session) onEstablishmentAck(m sessionMessage) error {
+	s.mu.Lock()
+	if s.role != Client || m.sessionID != s.uuid || s.established {
+		s.mu.Unlock()
+		return fmt.Errorf("fixp: unexpected %v", m)
+	}
+
+	s.keepalive = time.Duration(m.keepaliveInterval) * time.Millisecond
+	if s.keepalive <= 0 {
+		s.keepalive = s.config.KeepaliveInterval
+	}
+	s.established = true
+	s.mu.Unlock()
+
+	return s.onEstablished(m.nextSeqNo)
+}
+
+// onEstablished starts the flows of an established session, peerNextSeqNo being the next sequence number of the
+// counterparty.
+func (s *Session) onEstablished(peerNextSeqNo uint64) error {
+	s.log.OnEvent("Session established")
+	s.app.OnLogon(s.sessionID)
+
+	if err := s.onSequence(peerNextSeqNo); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.config.Flow.sequenced() {
+		return s.heartbeatLocked()
+	}
+
+	return nil
+}
+
+// onSequence sets the sequence number of the next message of the inbound flow, requesting the retransmission of
+// missed messages of a recoverable flow.
+func (s *Session) onSequence(nextSeqNo uint64) error {
+	if !s.peerFlow.sequenced() || nextSeqNo == 0 {
+		return nil
+	}
+
+	s.inSeqNo = nextSeqNo
+	expected := s.nextTargetSeqNo()
+	if nextSeqNo <= expected || s.requesting {
+		return nil
+	}
+
+	if s.peerFlow == Idempotent {
+		s.log.OnEventf("Messages %v to %v not received", expected, nextSeqNo-1)
+		return s.setNextTargetSeqNo(nextSeqNo)
+	}
+
+	s.requesting = true
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sendLocked(sessionMessage{
+		templateID: templateRetransmitRequest,
+		sessionID:  s.uuid,
+		timestamp:  timestamp(),
+		fromSeqNo:  expected,
+		count:      uint32(nextSeqNo - expected),
+	})
+}
+
+// skipGap gives up on messages not retransmitted, delivering the messages received after them.
+func (s *Session) skipGap() error {
+	next := s.inSeqNo
+	for seqNo := range s.pending {
+		if seqNo < next {
+			next = seqNo
+		}
+	}
+
+	if next > s.nextTargetSeqNo() {
+		if err := s.setNextTargetSeqNo(next); err != nil {
+			return err
+		}
+	}
+
+	return s.deliverPending()
+}
+
+func (s *Session) onRetransmitRequest(m sessionMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reject := func(code uint8) error {
+		return s.sendLocked(sessionMessage{
+			templateID: templateRetransmitReject, sessionID: s.uuid, timestamp: m.timestamp, code: code,
+		})
+	}
+
+	next := s.nextSeqNoLocked()
+	switch {
+	case s.config.Flow != Recoverable || m.sessionID != s.uuid:
+		return reject(RetransmitRejectInvalidSession)
+	case m.fromSeqNo < 1 || m.count == 0 || m.fromSeqNo+uint64(m.count) > next:
+		return reject(RetransmitRejectOutOfRange)
+	}
+
+	msgs, err := s.store.GetMessages(int(m.fromSeqNo), int(m.fromSeqNo)+int(m.count)-1)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendLocked(sessionMessage{
+		templateID: templateRetransmission,
+		sessionID:  s.uuid,
+		timestamp:  m.timestamp,
+		nextSeqNo:  m.fromSeqNo,
+		count:      uint32(len(msgs)),
+	}); err != nil {
+		return err
+	}
+
+	for _, msg := range msgs {
+		if err := s.writeLocked(encodingTagValue, msg); err != nil {
+			return err
+		}
+		s.log.OnOutgoing(msg)
+	}
+
+	// Restore the sequence of the live flow.
+	return s.heartbeatLocked()
+}
+
+func (s *Session) onApplicationMessage(raw []byte) error {
+	msg := quickfix.NewMessage()
+	if err := quickfix.ParseMessage(msg, bytes.NewBuffer(raw)); err != nil {
+		return err
+	}
+	s.log.OnIncoming(raw)
+
+	if !s.peerFlow.sequenced() {
+		s.deliver(msg)
+		return nil
+	}
+
+	seqNo := s.inSeqNo
+	s.inSeqNo++
+	if s.retransmits > 0 {
+		s.retransmits--
+		if s.retransmits == 0 {
+			s.requesting = false
+		}
+	}
+
+	expected := s.nextTargetSeqNo()
+	switch {
+	case seqNo < expected:
+		s.log.OnEventf("Ignoring duplicate message %v", seqNo)
+		return nil
+	case seqNo > expected:
+		if s.pending == nil {
+			s.pending = make(map[uint64]*quickfix.Message)
+		}
+		s.pending[seqNo] = msg
+		return nil
+	}
+
+	s.deliver(msg)
+	if err := s.incrNextTargetSeqNo(); err != nil {
+		return err
+	}
+
+	return s.deliverPending()
+}
+
+// deliverPending delivers the messages received ahead of missed messages that are now in sequence.
+func (s *Session) deliverPending() error {
+	for {
+		expected := s.nextTargetSeqNo()
+		msg, ok := s.pending[expected]
+		if !ok {
+			break
+		}
+		delete(s.pending, expected)
+
+		s.deliver(msg)
+		if err := s.incrNextTargetSeqNo(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The MessageStore is shared with Send, so the inbound flow accesses it under the lock.
+func (s *Session) nextTargetSeqNo() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return uint64(s.store.NextTargetMsgSeqNum())
+}
+
+func (s *Session) setNextTargetSeqNo(seqNo uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.SetNextTargetMsgSeqNum(int(seqNo))
+}
+
+func (s *Session) incrNextTargetSeqNo() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.IncrNextTargetMsgSeqNum()
+}
+
+func (s *Session) deliver(msg *quickfix.Message) {
+	if err := s.app.FromApp(msg, s.sessionID); err != nil {
+		s.log.OnEventf("Message rejected by application: %v", err)
+	}
+}
+
+// Send sends an application message, after calling ToApp of the Application. Messages of recoverable flows are
+// saved in the MessageStore; if the session is not established they are only saved, and retransmitted once the
+// counterparty establishes the session and requests them.
+func (s *Session) Send(m quickfix.Messagable) error {
+	if s.config.Flow == None {
+		return errors.New("fixp: session has no outbound flow")
+	}
+
+	msg := m.ToMessage()
+	if err := s.app.ToApp(msg, s.sessionID); err != nil {
+		return err
+	}
+	raw := msg.Bytes()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.established && !(s.config.Flow == Recoverable && s.negotiated) {
+		return ErrNotEstablished
+	}
+
+	switch s.config.Flow {
+	case Recoverable:
+		if err := s.store.SaveMessageAndIncrNextSenderMsgSeqNum(s.store.NextSenderMsgSeqNum(), raw); err != nil {
+			return err
+		}
+	case Idempotent:
+		if err := s.store.IncrNextSenderMsgSeqNum(); err != nil {
+			return err
+		}
+	}
+
+	if !s.established {
+		return nil
+	}
+
+	if err := s.writeLocked(encodingTagValue, raw); err != nil {
+		return err
+	}
+	s.log.OnOutgoing(raw)
+
+	return nil
+}
+
+// Terminate terminates an established session. Run returns once the counterparty acknowledges the termination or
+// disconnects.
+func (s *Session) Terminate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.established || s.terminating {
+		return ErrNotEstablished
+	}
+
+	s.terminating = true
+	return s.sendLocked(sessionMessage{templateID: templateTerminate, sessionID: s.uuid, code: TerminateFinished})
+}
+
+func (s *Session) sendLocked(m sessionMessage) error {
+	s.log.OnEventf("Sending %v", m)
+	return s.writeLocked(encodingSBE, m.encode())
+}
+
+func (s *Session) writeLocked(encoding uint16, msg []byte) error {
+	if s.conn == nil {
+		return ErrNotEstablished
+	}
+
+	s.lastSent = time.Now()
+	return writeFrame(s.conn, encoding, msg)
+}
+
+func timestamp() uint64 {
+	return uint64(time.Now().UnixNano())
+}
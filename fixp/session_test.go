@@ -0,0 +1,201 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package fixp
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type testApplication struct {
+	logons   chan quickfix.SessionID
+	logouts  chan quickfix.SessionID
+	received chan *quickfix.Message
+}
+
+func newTestApplication() *testApplication {
+	return &testApplication{
+		logons:   make(chan quickfix.SessionID, 10),
+		logouts:  make(chan quickfix.SessionID, 10),
+		received: make(chan *quickfix.Message, 10),
+	}
+}
+
+func (a *testApplication) OnCreate(quickfix.SessionID)                       {}
+func (a *testApplication) OnLogon(sessionID quickfix.SessionID)              { a.logons <- sessionID }
+func (a *testApplication) OnLogout(sessionID quickfix.SessionID)             { a.logouts <- sessionID }
+func (a *testApplication) ToAdmin(*quickfix.Message, quickfix.SessionID)     {}
+func (a *testApplication) ToApp(*quickfix.Message, quickfix.SessionID) error { return nil }
+func (a *testApplication) FromAdmin(*quickfix.Message, quickfix.SessionID) quickfix.MessageRejectError {
+	return nil
+}
+func (a *testApplication) FromApp(msg *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
+	a.received <- msg
+	return nil
+}
+
+type sessionPair struct {
+	client, server       *Session
+	clientApp, serverApp *testApplication
+	listener             net.Listener
+}
+
+func newSessionPair(t *testing.T, clientConfig, serverConfig Config) *sessionPair {
+	p := &sessionPair{clientApp: newTestApplication(), serverApp: newTestApplication()}
+
+	var err error
+	p.client, err = NewSession(Client, quickfix.SessionID{BeginString: "FIXT.1.1", SenderCompID: "CLIENT", TargetCompID: "SERVER"},
+		clientConfig, p.clientApp, quickfix.NewMemoryStoreFactory(), quickfix.NewNullLogFactory())
+	require.Nil(t, err)
+	p.server, err = NewSession(Server, quickfix.SessionID{BeginString: "FIXT.1.1", SenderCompID: "SERVER", TargetCompID: "CLIENT"},
+		serverConfig, p.serverApp, quickfix.NewMemoryStoreFactory(), quickfix.NewNullLogFactory())
+	require.Nil(t, err)
+
+	p.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	t.Cleanup(func() { p.listener.Close() })
+
+	return p
+}
+
+// connect runs both sides of the session on a new connection, returning the results of Run.
+func (p *sessionPair) connect(t *testing.T) (client, server chan error) {
+	client, server = make(chan error, 1), make(chan error, 1)
+	go func() {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			server <- err
+			return
+		}
+		server <- p.server.Run(conn)
+	}()
+	go func() { client <- p.client.Dial("tcp", p.listener.Addr().String()) }()
+
+	return
+}
+
+func waitFor[T any](t *testing.T, c chan T) T {
+	select {
+	case v := <-c:
+		return v
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out")
+	}
+
+	var v T
+	return v
+}
+
+func newOrder(clOrdID string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(8), "FIXT.1.1").SetString(quickfix.Tag(35), "D")
+	msg.Body.SetString(quickfix.Tag(11), clOrdID)
+
+	return msg
+}
+
+func clOrdID(t *testing.T, msg *quickfix.Message) string {
+	id, err := msg.Body.GetString(quickfix.Tag(11))
+	require.Nil(t, err)
+
+	return id
+}
+
+func TestSessionExchangesMessages(t *testing.T) {
+	p := newSessionPair(t,
+		Config{Flow: Recoverable, KeepaliveInterval: time.Second, Credentials: []byte("secret")},
+		Config{Flow: Idempotent, Authenticate: func(c []byte) bool { return string(c) == "secret" }})
+
+	clientDone, serverDone := p.connect(t)
+	waitFor(t, p.clientApp.logons)
+	waitFor(t, p.serverApp.logons)
+
+	clientUUID, ok := p.client.UUID()
+	assert.True(t, ok)
+	serverUUID, _ := p.server.UUID()
+	assert.Equal(t, clientUUID, serverUUID)
+
+	require.Nil(t, p.client.Send(newOrder("1")))
+	require.Nil(t, p.client.Send(newOrder("2")))
+	require.Nil(t, p.server.Send(newOrder("3")))
+
+	assert.Equal(t, "1", clOrdID(t, waitFor(t, p.serverApp.received)))
+	assert.Equal(t, "2", clOrdID(t, waitFor(t, p.serverApp.received)))
+	assert.Equal(t, "3", clOrdID(t, waitFor(t, p.clientApp.received)))
+
+	require.Nil(t, p.client.Terminate())
+	assert.Nil(t, waitFor(t, clientDone))
+	assert.Nil(t, waitFor(t, serverDone))
+	waitFor(t, p.clientApp.logouts)
+	waitFor(t, p.serverApp.logouts)
+}
+
+func TestSessionRetransmitsMissedMessages(t *testing.T) {
+	p := newSessionPair(t, Config{Flow: Recoverable, KeepaliveInterval: time.Second}, Config{Flow: None})
+
+	clientDone, serverDone := p.connect(t)
+	waitFor(t, p.serverApp.logons)
+	require.Nil(t, p.client.Send(newOrder("1")))
+	assert.Equal(t, "1", clOrdID(t, waitFor(t, p.serverApp.received)))
+
+	require.Nil(t, p.client.Terminate())
+	assert.Nil(t, waitFor(t, clientDone))
+	assert.Nil(t, waitFor(t, serverDone))
+
+	// Messages of the recoverable flow are stored while disconnected, and requested by the server once reestablished.
+	require.Nil(t, p.client.Send(newOrder("2")))
+	require.Nil(t, p.client.Send(newOrder("3")))
+
+	clientDone, serverDone = p.connect(t)
+	waitFor(t, p.serverApp.logons)
+	require.Nil(t, p.client.Send(newOrder("4")))
+
+	for _, id := range []string{"2", "3", "4"} {
+		assert.Equal(t, id, clOrdID(t, waitFor(t, p.serverApp.received)))
+	}
+
+	require.Nil(t, p.client.Terminate())
+	assert.Nil(t, waitFor(t, clientDone))
+	assert.Nil(t, waitFor(t, serverDone))
+}
+
+func TestSessionNegotiationRejected(t *testing.T) {
+	p := newSessionPair(t,
+		Config{Flow: Recoverable, Credentials: []byte("wrong")},
+		Config{Flow: Recoverable, Authenticate: func(c []byte) bool { return string(c) == "secret" }})
+
+	clientDone, serverDone := p.connect(t)
+	assert.NotNil(t, waitFor(t, clientDone))
+	waitFor(t, serverDone)
+
+	_, ok := p.client.UUID()
+	assert.False(t, ok)
+	_, ok = p.server.UUID()
+	assert.False(t, ok)
+}
+
+func TestSessionSendNotEstablished(t *testing.T) {
+	p := newSessionPair(t, Config{Flow: Unsequenced}, Config{Flow: None})
+
+	assert.Equal(t, ErrNotEstablished, p.client.Send(newOrder("1")))
+	assert.NotNil(t, p.server.Send(newOrder("1")))
+}
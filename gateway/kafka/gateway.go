@@ -0,0 +1,557 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package kafka provides a quickfix.Application bridging FIX sessions to Kafka topics in both directions: application
+// messages received from a session are published to a topic, and the messages of a topic are sent on a session.
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+const (
+	defaultPublishTopic = "fix.{SenderCompID}.{TargetCompID}.in"
+	defaultGroupID      = "quickfix"
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 100 * time.Millisecond
+
+	// defaultPublishTimeout bounds publishing a message received from a session, retries included, as the session does
+	// not process other messages meanwhile. It is well below the usual heartbeat intervals.
+	defaultPublishTimeout = time.Second
+
+	// writerBatchTimeout is how long the writer waits for more messages before writing a batch. Each message is
+	// published on its own, so waiting for more only delays it.
+	writerBatchTimeout = time.Millisecond
+
+	tagMsgSeqNum quickfix.Tag = 34
+
+	// businessRejectReasonApplicationNotAvailable is the BusinessRejectReason of messages that could not be published.
+	businessRejectReasonApplicationNotAvailable = 4
+)
+
+// Format is the payload of the messages on Kafka.
+type Format int
+
+const (
+	// FormatFIX is the raw FIX message.
+	FormatFIX Format = iota
+
+	// FormatJSON is the FIX JSON encoding, with fields named by their tags.
+	FormatJSON
+)
+
+// ErrorHandler is called with the messages the gateway gave up on: a message received from a session that could not
+// be published, or a message of a topic that could not be sent on a session, and with errors reading topics.
+type ErrorHandler func(sessionID quickfix.SessionID, message kafka.Message, err error)
+
+// producer is the part of kafka.Writer used to publish.
+type producer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// consumer is the part of kafka.Reader used to read a topic.
+type consumer interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// route is how a session is bridged to Kafka.
+type route struct {
+	publishTopic    string
+	subscribeTopic  string
+	deadLetterTopic string
+	groupID         string
+	format          Format
+	keyTag          quickfix.Tag
+	maxRetries      int
+	retryBackoff    time.Duration
+	publishTimeout  time.Duration
+}
+
+// gatewaySession is a session bridged by the gateway.
+type gatewaySession struct {
+	route
+	sessionID quickfix.SessionID
+
+	mu sync.Mutex
+	// loggedOn is closed while the session is logged on.
+	loggedOn chan struct{}
+}
+
+// Gateway is a quickfix.Application bridging its sessions to Kafka, as configured by their KafkaGateway settings.
+// Embed it in an Application to also handle admin messages, such as setting credentials on the Logon.
+type Gateway struct {
+	settings    *quickfix.Settings
+	producer    producer
+	newConsumer func(topic, groupID string) consumer
+	onError     ErrorHandler
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	sessions map[quickfix.SessionID]*gatewaySession
+	closers  []func() error
+}
+
+// NewGateway returns a Gateway bridging the sessions of settings to the Kafka brokers of KafkaGatewayBrokers.
+// Messages given up on are passed to onError, or logged with the standard logger if onError is nil.
+func NewGateway(settings *quickfix.Settings, onError ErrorHandler) (*Gateway, error) {
+	brokers, err := settings.GlobalSettings().Setting(config.KafkaGatewayBrokers)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := kafka.TCP(strings.Split(brokers, ",")...)
+	// The gateway retries failed writes itself, within KafkaGatewayPublishTimeout.
+	writer := &kafka.Writer{Addr: addr, Balancer: &kafka.Hash{}, RequiredAcks: kafka.RequireAll, BatchTimeout: writerBatchTimeout, MaxAttempts: 1}
+	newConsumer := func(topic, groupID string) consumer {
+		return kafka.NewReader(kafka.ReaderConfig{Brokers: strings.Split(brokers, ","), Topic: topic, GroupID: groupID})
+	}
+
+	g, err := newGateway(settings, writer, newConsumer, onError)
+	if err != nil {
+		return nil, err
+	}
+	g.closers = append(g.closers, writer.Close)
+	return g, nil
+}
+
+func newGateway(settings *quickfix.Settings, producer producer, newConsumer func(topic, groupID string) consumer, onError ErrorHandler) (*Gateway, error) {
+	if onError == nil {
+		onError = func(sessionID quickfix.SessionID, _ kafka.Message, err error) {
+			log.Printf("kafka gateway %v: %v", sessionID, err)
+		}
+	}
+
+	// Routes are checked here, as OnCreate can not fail.
+	if _, err := loadRoute(settings.GlobalSettings(), quickfix.SessionID{}); err != nil {
+		return nil, err
+	}
+	for sessionID, sessionSettings := range settings.SessionSettings() {
+		if _, err := loadRoute(sessionSettings, sessionID); err != nil {
+			return nil, fmt.Errorf("session %v: %w", sessionID, err)
+		}
+	}
+
+	g := &Gateway{
+		settings:    settings,
+		producer:    producer,
+		newConsumer: newConsumer,
+		onError:     onError,
+		sessions:    make(map[quickfix.SessionID]*gatewaySession),
+	}
+	g.ctx, g.cancel = context.WithCancel(context.Background())
+	return g, nil
+}
+
+// loadRoute returns the route of sessionID configured by settings.
+func loadRoute(settings *quickfix.SessionSettings, sessionID quickfix.SessionID) (r route, err error) {
+	r = route{
+		publishTopic:   defaultPublishTopic,
+		groupID:        defaultGroupID,
+		maxRetries:     defaultMaxRetries,
+		retryBackoff:   defaultRetryBackoff,
+		publishTimeout: defaultPublishTimeout,
+	}
+
+	for setting, topic := range map[string]*string{
+		config.KafkaGatewayPublishTopic:    &r.publishTopic,
+		config.KafkaGatewaySubscribeTopic:  &r.subscribeTopic,
+		config.KafkaGatewayDeadLetterTopic: &r.deadLetterTopic,
+		config.KafkaGatewayGroupID:         &r.groupID,
+	} {
+		if settings.HasSetting(setting) {
+			if *topic, err = settings.Setting(setting); err != nil {
+				return
+			}
+		}
+		*topic = expandTopic(*topic, sessionID)
+	}
+
+	if settings.HasSetting(config.KafkaGatewayFormat) {
+		var format string
+		if format, err = settings.Setting(config.KafkaGatewayFormat); err != nil {
+			return
+		}
+
+		switch format {
+		case "FIX":
+			r.format = FormatFIX
+		case "JSON":
+			r.format = FormatJSON
+		default:
+			err = quickfix.IncorrectFormatForSetting{Setting: config.KafkaGatewayFormat, Value: []byte(format)}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.KafkaGatewayKeyTag) {
+		var tag int
+		if tag, err = settings.IntSetting(config.KafkaGatewayKeyTag); err != nil {
+			return
+		}
+		if tag <= 0 {
+			err = quickfix.IncorrectFormatForSetting{Setting: config.KafkaGatewayKeyTag, Value: []byte(strconv.Itoa(tag))}
+			return
+		}
+		r.keyTag = quickfix.Tag(tag)
+	}
+
+	if settings.HasSetting(config.KafkaGatewayMaxRetries) {
+		if r.maxRetries, err = settings.IntSetting(config.KafkaGatewayMaxRetries); err != nil {
+			return
+		}
+		if r.maxRetries < 0 {
+			err = quickfix.IncorrectFormatForSetting{Setting: config.KafkaGatewayMaxRetries, Value: []byte(strconv.Itoa(r.maxRetries))}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.KafkaGatewayRetryBackoff) {
+		if r.retryBackoff, err = settings.DurationSetting(config.KafkaGatewayRetryBackoff); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.KafkaGatewayPublishTimeout) {
+		if r.publishTimeout, err = settings.DurationSetting(config.KafkaGatewayPublishTimeout); err != nil {
+			return
+		}
+		if r.publishTimeout <= 0 {
+			err = quickfix.IncorrectFormatForSetting{Setting: config.KafkaGatewayPublishTimeout, Value: []byte(r.publishTimeout.String())}
+			return
+		}
+	}
+
+	return
+}
+
+// expandTopic replaces the placeholders of a topic pattern with the fields of sessionID, leaving {MsgType}.
+func expandTopic(pattern string, sessionID quickfix.SessionID) string {
+	return strings.NewReplacer(
+		"{BeginString}", sessionID.BeginString,
+		"{SenderCompID}", sessionID.SenderCompID,
+		"{SenderSubID}", sessionID.SenderSubID,
+		"{SenderLocationID}", sessionID.SenderLocationID,
+		"{TargetCompID}", sessionID.TargetCompID,
+		"{TargetSubID}", sessionID.TargetSubID,
+		"{TargetLocationID}", sessionID.TargetLocationID,
+		"{Qualifier}", sessionID.Qualifier,
+	).Replace(pattern)
+}
+
+// Close stops reading topics and closes the connections of the gateway, once the initiator or acceptor is stopped.
+func (g *Gateway) Close() error {
+	g.cancel()
+	g.wg.Wait()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var err error
+	for _, closer := range g.closers {
+		if closeErr := closer(); err == nil {
+			err = closeErr
+		}
+	}
+	g.closers = nil
+	return err
+}
+
+func (g *Gateway) session(sessionID quickfix.SessionID) *gatewaySession {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.sessions[sessionID]
+}
+
+// OnCreate starts reading the KafkaGatewaySubscribeTopic of the session, if it is set.
+func (g *Gateway) OnCreate(sessionID quickfix.SessionID) {
+	settings, ok := g.settings.SessionSettings()[sessionID]
+	if !ok {
+		settings = g.settings.GlobalSettings()
+	}
+
+	r, err := loadRoute(settings, sessionID)
+	if err != nil {
+		g.onError(sessionID, kafka.Message{}, err)
+		return
+	}
+
+	s := &gatewaySession{route: r, sessionID: sessionID, loggedOn: make(chan struct{})}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.sessions[sessionID]; ok {
+		return
+	}
+	g.sessions[sessionID] = s
+
+	if r.subscribeTopic != "" {
+		c := g.newConsumer(r.subscribeTopic, r.groupID)
+		g.closers = append(g.closers, c.Close)
+		g.wg.Add(1)
+		go func() {
+			defer g.wg.Done()
+			g.consume(s, c)
+		}()
+	}
+}
+
+// OnLogon resumes sending the messages of the KafkaGatewaySubscribeTopic of the session.
+func (g *Gateway) OnLogon(sessionID quickfix.SessionID) {
+	if s := g.session(sessionID); s != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-s.loggedOn:
+		default:
+			close(s.loggedOn)
+		}
+	}
+}
+
+// OnLogout pauses sending the messages of the KafkaGatewaySubscribeTopic of the session.
+func (g *Gateway) OnLogout(sessionID quickfix.SessionID) {
+	if s := g.session(sessionID); s != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-s.loggedOn:
+			s.loggedOn = make(chan struct{})
+		default:
+		}
+	}
+}
+
+// ToAdmin does nothing.
+func (g *Gateway) ToAdmin(*quickfix.Message, quickfix.SessionID) {}
+
+// ToApp does nothing.
+func (g *Gateway) ToApp(*quickfix.Message, quickfix.SessionID) error { return nil }
+
+// FromAdmin does nothing.
+func (g *Gateway) FromAdmin(*quickfix.Message, quickfix.SessionID) quickfix.MessageRejectError {
+	return nil
+}
+
+// FromApp publishes msg to the KafkaGatewayPublishTopic of the session. A message that can not be published within
+// KafkaGatewayPublishTimeout is rejected with a BusinessMessageReject, with BusinessRejectReason
+// ApplicationNotAvailable.
+func (g *Gateway) FromApp(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	s := g.session(sessionID)
+	if s == nil {
+		return nil
+	}
+
+	message, err := s.encode(msg)
+	if err == nil {
+		err = g.publish(s, message)
+	}
+	if err != nil {
+		g.onError(sessionID, message, err)
+		return quickfix.NewBusinessMessageRejectError("Application not available", businessRejectReasonApplicationNotAvailable, nil)
+	}
+
+	return nil
+}
+
+// encode returns msg as a Kafka message of the KafkaGatewayPublishTopic of the session.
+func (s *gatewaySession) encode(msg *quickfix.Message) (kafka.Message, error) {
+	msgType, _ := msg.MsgType()
+	seqNum, _ := msg.Header.GetInt(tagMsgSeqNum)
+	message := kafka.Message{
+		Topic: strings.ReplaceAll(s.publishTopic, "{MsgType}", msgType),
+		Key:   []byte(s.sessionID.String()),
+		Time:  time.Now(),
+		Headers: []kafka.Header{
+			{Key: "session_id", Value: []byte(s.sessionID.String())},
+			{Key: "msg_type", Value: []byte(msgType)},
+			{Key: "seq_num", Value: []byte(strconv.Itoa(seqNum))},
+		},
+	}
+
+	if s.keyTag != 0 {
+		fields := &msg.Body.FieldMap
+		if s.keyTag.IsHeader() {
+			fields = &msg.Header.FieldMap
+		}
+		if key, err := fields.GetBytes(s.keyTag); err == nil {
+			message.Key = append([]byte(nil), key...)
+		}
+	}
+
+	var err error
+	switch s.format {
+	case FormatJSON:
+		message.Value, err = msg.MarshalJSON()
+	default:
+		// The message is reused once FromApp returns.
+		message.Value = append([]byte(nil), msg.Bytes()...)
+	}
+	return message, err
+}
+
+// decode returns the FIX message of a Kafka message of the KafkaGatewaySubscribeTopic of the session.
+func (s *gatewaySession) decode(message kafka.Message) (*quickfix.Message, error) {
+	msg := quickfix.NewMessage()
+	switch s.format {
+	case FormatJSON:
+		return msg, msg.UnmarshalJSON(message.Value)
+	default:
+		return msg, quickfix.ParseMessage(msg, bytes.NewBuffer(message.Value))
+	}
+}
+
+// publish publishes message, retrying KafkaGatewayMaxRetries times within KafkaGatewayPublishTimeout.
+func (g *Gateway) publish(s *gatewaySession, message kafka.Message) error {
+	ctx, cancel := context.WithTimeout(g.ctx, s.publishTimeout)
+	defer cancel()
+
+	backoff := s.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := g.producer.WriteMessages(ctx, message)
+		if err == nil || attempt == s.maxRetries || !sleep(ctx, backoff) {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// sleep waits for d, returning false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// waitLoggedOn waits until the session is logged on, returning false if the gateway is closed first.
+func (g *Gateway) waitLoggedOn(s *gatewaySession) bool {
+	s.mu.Lock()
+	loggedOn := s.loggedOn
+	s.mu.Unlock()
+
+	select {
+	case <-loggedOn:
+		return true
+	case <-g.ctx.Done():
+		return false
+	}
+}
+
+// consume sends the messages of the KafkaGatewaySubscribeTopic on the session while it is logged on, committing
+// each once it is written to the connection or given up on, until the gateway is closed.
+func (g *Gateway) consume(s *gatewaySession, c consumer) {
+	for g.waitLoggedOn(s) {
+		message, err := c.FetchMessage(g.ctx)
+		if err != nil {
+			if g.ctx.Err() == nil {
+				g.onError(s.sessionID, kafka.Message{}, err)
+				sleep(g.ctx, s.retryBackoff)
+			}
+			continue
+		}
+
+		if !g.deliver(s, message) {
+			return
+		}
+
+		if err := c.CommitMessages(g.ctx, message); err != nil && g.ctx.Err() == nil {
+			g.onError(s.sessionID, message, err)
+		}
+	}
+}
+
+// deliver sends message on the session, retrying KafkaGatewayMaxRetries times, and gives up on it if it can not be
+// sent. It returns false if the gateway is closed first, leaving the message to be read again.
+func (g *Gateway) deliver(s *gatewaySession, message kafka.Message) bool {
+	backoff := s.retryBackoff
+	for attempt := 0; ; attempt++ {
+		if !g.waitLoggedOn(s) {
+			return false
+		}
+
+		msg, err := s.decode(message)
+		if err != nil {
+			// Malformed messages are not retried.
+			g.giveUp(s, message, err)
+			return true
+		}
+
+		if err = g.send(s, msg); err == nil {
+			return true
+		} else if g.ctx.Err() != nil {
+			return false
+		}
+
+		if attempt == s.maxRetries {
+			g.giveUp(s, message, err)
+			return true
+		}
+		if !sleep(g.ctx, backoff) {
+			return false
+		}
+		backoff *= 2
+	}
+}
+
+// send sends msg on the session, waiting until it is written to the connection.
+func (g *Gateway) send(s *gatewaySession, msg *quickfix.Message) error {
+	receipt, err := quickfix.SendToTargetAsync(msg, s.sessionID)
+	if err != nil {
+		return err
+	}
+
+	_, err = receipt.Wait(g.ctx)
+	return err
+}
+
+// giveUp publishes message to the KafkaGatewayDeadLetterTopic of the session, or passes it to the ErrorHandler.
+func (g *Gateway) giveUp(s *gatewaySession, message kafka.Message, err error) {
+	if s.deadLetterTopic == "" {
+		g.onError(s.sessionID, message, err)
+		return
+	}
+
+	deadLetter := kafka.Message{
+		Topic:   s.deadLetterTopic,
+		Key:     message.Key,
+		Value:   message.Value,
+		Headers: append(append([]kafka.Header(nil), message.Headers...), kafka.Header{Key: "error", Value: []byte(err.Error())}),
+	}
+	if publishErr := g.publish(s, deadLetter); publishErr != nil {
+		g.onError(s.sessionID, message, fmt.Errorf("%w, dead letter: %v", err, publishErr))
+	}
+}
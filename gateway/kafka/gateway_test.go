@@ -0,0 +1,334 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// fakeProducer records the messages it publishes, failing while failing is set.
+type fakeProducer struct {
+	mu        sync.Mutex
+	failing   bool
+	published chan kafka.Message
+}
+
+func newFakeProducer() *fakeProducer {
+	return &fakeProducer{published: make(chan kafka.Message, 10)}
+}
+
+func (p *fakeProducer) setFailing(failing bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failing = failing
+}
+
+func (p *fakeProducer) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failing {
+		return errors.New("broker unavailable")
+	}
+	for _, msg := range msgs {
+		p.published <- msg
+	}
+	return nil
+}
+
+// fakeConsumer reads the messages of its channel, recording the ones committed.
+type fakeConsumer struct {
+	topic, groupID string
+	messages       chan kafka.Message
+	committed      chan kafka.Message
+}
+
+func (c *fakeConsumer) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case msg := <-c.messages:
+		return msg, nil
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	}
+}
+
+func (c *fakeConsumer) CommitMessages(_ context.Context, msgs ...kafka.Message) error {
+	for _, msg := range msgs {
+		c.committed <- msg
+	}
+	return nil
+}
+
+func (c *fakeConsumer) Close() error { return nil }
+
+// recordingApp records the application messages received by an initiator.
+type recordingApp struct {
+	loggedOn chan quickfix.SessionID
+	received chan *quickfix.Message
+}
+
+func (a recordingApp) OnCreate(quickfix.SessionID)                       {}
+func (a recordingApp) OnLogon(sessionID quickfix.SessionID)              { a.loggedOn <- sessionID }
+func (a recordingApp) OnLogout(quickfix.SessionID)                       {}
+func (a recordingApp) ToAdmin(*quickfix.Message, quickfix.SessionID)     {}
+func (a recordingApp) ToApp(*quickfix.Message, quickfix.SessionID) error { return nil }
+func (a recordingApp) FromAdmin(*quickfix.Message, quickfix.SessionID) quickfix.MessageRejectError {
+	return nil
+}
+func (a recordingApp) FromApp(msg *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
+	// The message is released once FromApp returns.
+	received := quickfix.NewMessage()
+	msg.CopyInto(received)
+	a.received <- received
+	return nil
+}
+
+func sessionSettings(senderCompID, targetCompID string, settings map[string]string) *quickfix.SessionSettings {
+	sessionSettings := quickfix.NewSessionSettings()
+	sessionSettings.Set(config.BeginString, quickfix.BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, senderCompID)
+	sessionSettings.Set(config.TargetCompID, targetCompID)
+	for setting, value := range settings {
+		sessionSettings.Set(setting, value)
+	}
+	return sessionSettings
+}
+
+func newSettings(t *testing.T, global map[string]string, sessions ...*quickfix.SessionSettings) *quickfix.Settings {
+	settings := quickfix.NewSettings()
+	for setting, value := range global {
+		settings.GlobalSettings().Set(setting, value)
+	}
+	for _, sessionSettings := range sessions {
+		_, err := settings.AddSession(sessionSettings)
+		require.NoError(t, err)
+	}
+	return settings
+}
+
+func receive[T any](t *testing.T, c chan T) T {
+	select {
+	case v := <-c:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+		var v T
+		return v
+	}
+}
+
+func newOrder(clOrdID string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(8), quickfix.BeginStringFIX42)
+	msg.Header.SetString(quickfix.Tag(35), "D")
+	msg.Body.SetString(quickfix.Tag(11), clOrdID)
+	msg.Body.SetString(quickfix.Tag(55), "MSFT")
+	return msg
+}
+
+func header(message kafka.Message, key string) string {
+	for _, h := range message.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func TestLoadRoute(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: quickfix.BeginStringFIX42, SenderCompID: "GW", TargetCompID: "CLIENT"}
+
+	var tests = []struct {
+		name     string
+		settings map[string]string
+		expected route
+		err      bool
+	}{
+		{name: "defaults", expected: route{publishTopic: "fix.GW.CLIENT.in", groupID: "quickfix", maxRetries: 3, retryBackoff: 100 * time.Millisecond, publishTimeout: time.Second}},
+		{
+			name: "configured",
+			settings: map[string]string{
+				config.KafkaGatewayPublishTopic:    "orders.{TargetCompID}.{MsgType}",
+				config.KafkaGatewaySubscribeTopic:  "fix.{SenderCompID}.{TargetCompID}.out",
+				config.KafkaGatewayDeadLetterTopic: "fix.dlq",
+				config.KafkaGatewayGroupID:         "gw-{TargetCompID}",
+				config.KafkaGatewayFormat:          "JSON",
+				config.KafkaGatewayKeyTag:          "11",
+				config.KafkaGatewayMaxRetries:      "0",
+				config.KafkaGatewayRetryBackoff:    "1s",
+				config.KafkaGatewayPublishTimeout:  "250ms",
+			},
+			expected: route{
+				publishTopic:    "orders.CLIENT.{MsgType}",
+				subscribeTopic:  "fix.GW.CLIENT.out",
+				deadLetterTopic: "fix.dlq",
+				groupID:         "gw-CLIENT",
+				format:          FormatJSON,
+				keyTag:          11,
+				retryBackoff:    time.Second,
+				publishTimeout:  250 * time.Millisecond,
+			},
+		},
+		{name: "invalid format", settings: map[string]string{config.KafkaGatewayFormat: "XML"}, err: true},
+		{name: "invalid key tag", settings: map[string]string{config.KafkaGatewayKeyTag: "0"}, err: true},
+		{name: "invalid max retries", settings: map[string]string{config.KafkaGatewayMaxRetries: "-1"}, err: true},
+		{name: "invalid publish timeout", settings: map[string]string{config.KafkaGatewayPublishTimeout: "0s"}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := loadRoute(sessionSettings("GW", "CLIENT", test.settings), sessionID)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, r)
+		})
+	}
+}
+
+func TestGatewaySession_EncodeDecode(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: quickfix.BeginStringFIX42, SenderCompID: "GW", TargetCompID: "CLIENT"}
+	for _, format := range []Format{FormatFIX, FormatJSON} {
+		s := &gatewaySession{route: route{publishTopic: "orders.{MsgType}", format: format, keyTag: 11}, sessionID: sessionID}
+
+		message, err := s.encode(newOrder("ORDER-1"))
+		require.NoError(t, err)
+		assert.Equal(t, "orders.D", message.Topic)
+		assert.Equal(t, "ORDER-1", string(message.Key))
+		assert.Equal(t, "D", header(message, "msg_type"))
+		assert.Equal(t, sessionID.String(), header(message, "session_id"))
+
+		msg, err := s.decode(message)
+		require.NoError(t, err)
+		clOrdID, err := msg.Body.GetString(quickfix.Tag(11))
+		require.NoError(t, err)
+		assert.Equal(t, "ORDER-1", clOrdID)
+	}
+}
+
+func TestGateway(t *testing.T) {
+	host := "pipe://kafka_gateway"
+	producer := newFakeProducer()
+	consumers := make(chan *fakeConsumer, 1)
+	errs := make(chan error, 10)
+
+	settings := newSettings(t, map[string]string{config.SocketAcceptHost: host},
+		sessionSettings("GW", "CLIENT", map[string]string{
+			config.KafkaGatewaySubscribeTopic:  "fix.{SenderCompID}.{TargetCompID}.out",
+			config.KafkaGatewayDeadLetterTopic: "fix.dlq",
+			config.KafkaGatewayMaxRetries:      "1",
+			config.KafkaGatewayRetryBackoff:    "10ms",
+		}))
+	gateway, err := newGateway(settings, producer, func(topic, groupID string) consumer {
+		c := &fakeConsumer{topic: topic, groupID: groupID, messages: make(chan kafka.Message, 10), committed: make(chan kafka.Message, 10)}
+		consumers <- c
+		return c
+	}, func(_ quickfix.SessionID, _ kafka.Message, err error) { errs <- err })
+	require.NoError(t, err)
+
+	acceptor, err := quickfix.NewAcceptor(gateway, quickfix.NewMemoryStoreFactory(), settings, quickfix.NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer func() {
+		acceptor.Stop()
+		assert.NoError(t, gateway.Close())
+	}()
+
+	c := receive(t, consumers)
+	assert.Equal(t, "fix.GW.CLIENT.out", c.topic)
+	assert.Equal(t, "quickfix", c.groupID)
+
+	app := recordingApp{loggedOn: make(chan quickfix.SessionID, 10), received: make(chan *quickfix.Message, 10)}
+	initiator, err := quickfix.NewInitiator(app, quickfix.NewMemoryStoreFactory(),
+		newSettings(t, nil, sessionSettings("CLIENT", "GW", map[string]string{
+			config.SocketConnectHost: host, config.SocketConnectPort: "5022", config.HeartBtInt: "30", config.ReconnectInterval: "1",
+		})),
+		quickfix.NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	clientID := quickfix.SessionID{BeginString: quickfix.BeginStringFIX42, SenderCompID: "CLIENT", TargetCompID: "GW"}
+	assert.Equal(t, clientID, receive(t, app.loggedOn))
+
+	// Messages received from the session are published.
+	require.NoError(t, quickfix.SendToTarget(newOrder("ORDER-1"), clientID))
+	published := receive(t, producer.published)
+	assert.Equal(t, "fix.GW.CLIENT.in", published.Topic)
+	assert.Equal(t, "D", header(published, "msg_type"))
+	assert.Equal(t, "2", header(published, "seq_num"))
+	msg := quickfix.NewMessage()
+	require.NoError(t, quickfix.ParseMessage(msg, bytes.NewBuffer(published.Value)))
+	clOrdID, err := msg.Body.GetString(quickfix.Tag(11))
+	require.NoError(t, err)
+	assert.Equal(t, "ORDER-1", clOrdID)
+
+	// Messages of the subscribed topic are sent on the session, and committed.
+	order := newOrder("ORDER-2")
+	c.messages <- kafka.Message{Value: []byte(order.String())}
+	received := receive(t, app.received)
+	clOrdID, err = received.Body.GetString(quickfix.Tag(11))
+	require.NoError(t, err)
+	assert.Equal(t, "ORDER-2", clOrdID)
+	receive(t, c.committed)
+
+	// Malformed messages go to the dead letter topic.
+	c.messages <- kafka.Message{Key: []byte("bad"), Value: []byte("not fix")}
+	deadLetter := receive(t, producer.published)
+	assert.Equal(t, "fix.dlq", deadLetter.Topic)
+	assert.Equal(t, "bad", string(deadLetter.Key))
+	assert.NotEmpty(t, header(deadLetter, "error"))
+	receive(t, c.committed)
+
+	// Messages that can not be published are rejected.
+	producer.setFailing(true)
+	require.NoError(t, quickfix.SendToTarget(newOrder("ORDER-3"), clientID))
+	assert.Error(t, receive(t, errs))
+	reject := receive(t, app.received)
+	msgType, err := reject.MsgType()
+	require.NoError(t, err)
+	assert.Equal(t, "j", msgType)
+	reason, err := reject.Body.GetInt(quickfix.Tag(380))
+	require.NoError(t, err)
+	assert.Equal(t, businessRejectReasonApplicationNotAvailable, reason)
+}
+
+func TestGateway_PublishTimeout(t *testing.T) {
+	producer := newFakeProducer()
+	producer.setFailing(true)
+	gateway, err := newGateway(newSettings(t, nil), producer, nil, nil)
+	require.NoError(t, err)
+	defer gateway.Close()
+
+	// Retries stop once the publish timeout elapses, as the session waits meanwhile.
+	s := &gatewaySession{route: route{maxRetries: 100, retryBackoff: 10 * time.Millisecond, publishTimeout: 100 * time.Millisecond}}
+	start := time.Now()
+	assert.Error(t, gateway.publish(s, kafka.Message{Topic: "fix.in"}))
+	assert.Less(t, time.Since(start), time.Second)
+}
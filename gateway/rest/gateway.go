@@ -0,0 +1,835 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package rest provides a quickfix.Application exposing order entry on its sessions over HTTP, for internal tools and
+// low rate API users. Orders are entered with NewOrderSingle and cancelled with OrderCancelRequest on the session,
+// and the ExecutionReports and rejects of the counterparty are correlated back to the HTTP responses, the order
+// status endpoint, and an optional webhook.
+//
+//	POST   /sessions/{session}/orders            enters an OrderRequest, answering its Order
+//	GET    /sessions/{session}/orders/{clOrdID}  answers the Order
+//	DELETE /sessions/{session}/orders/{clOrdID}  requests the cancel of the order, answering its Order
+//
+// POST and DELETE answer 200 OK once the counterparty responds, or 202 Accepted if it does not respond within
+// HTTPGatewayResponseTimeout. Orders are forgotten HTTPGatewayOrderTTL after they are filled, cancelled, rejected,
+// expired or done for the day.
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+const (
+	defaultResponseTimeout = 5 * time.Second
+	defaultOrderTTL        = time.Hour
+	webhookQueueSize       = 1024
+	webhookTimeout         = 10 * time.Second
+)
+
+const (
+	tagAccount      quickfix.Tag = 1
+	tagAvgPx        quickfix.Tag = 6
+	tagClOrdID      quickfix.Tag = 11
+	tagCumQty       quickfix.Tag = 14
+	tagHandlInst    quickfix.Tag = 21
+	tagOrderID      quickfix.Tag = 37
+	tagOrderQty     quickfix.Tag = 38
+	tagOrdStatus    quickfix.Tag = 39
+	tagOrdType      quickfix.Tag = 40
+	tagMsgSeqNum    quickfix.Tag = 34
+	tagOrigClOrdID  quickfix.Tag = 41
+	tagPrice        quickfix.Tag = 44
+	tagRefSeqNum    quickfix.Tag = 45
+	tagSide         quickfix.Tag = 54
+	tagSymbol       quickfix.Tag = 55
+	tagText         quickfix.Tag = 58
+	tagTimeInForce  quickfix.Tag = 59
+	tagTransactTime quickfix.Tag = 60
+	tagExecType     quickfix.Tag = 150
+	tagLeavesQty    quickfix.Tag = 151
+)
+
+const (
+	msgTypeExecutionReport         = "8"
+	msgTypeOrderCancelReject       = "9"
+	msgTypeReject                  = "3"
+	msgTypeBusinessMessageReject   = "j"
+	msgTypeNewOrderSingle          = "D"
+	msgTypeOrderCancelRequest      = "F"
+	ordStatusFilled                = "2"
+	ordStatusDoneForDay            = "3"
+	ordStatusCanceled              = "4"
+	ordStatusRejected              = "8"
+	ordStatusExpired               = "C"
+	handlInstAutomatedNoBrokerExec = "1"
+)
+
+// ErrorHandler is called with the errors the gateway can not answer to an HTTP request, such as failing to post to
+// the webhook.
+type ErrorHandler func(sessionID quickfix.SessionID, err error)
+
+// OrderRequest is the body of a request entering an order. Values are FIX values, e.g. Side 1 for Buy.
+type OrderRequest struct {
+	// ClOrdID identifies the order. The gateway generates one if it is empty.
+	ClOrdID     string `json:"clOrdID,omitempty"`
+	Account     string `json:"account,omitempty"`
+	Symbol      string `json:"symbol"`
+	Side        string `json:"side"`
+	OrderQty    string `json:"orderQty"`
+	OrdType     string `json:"ordType"`
+	Price       string `json:"price,omitempty"`
+	TimeInForce string `json:"timeInForce,omitempty"`
+
+	// Fields are additional fields of the NewOrderSingle, by tag.
+	Fields map[quickfix.Tag]string `json:"fields,omitempty"`
+}
+
+// Order is the status of an order entered through the gateway, as reported by the counterparty.
+type Order struct {
+	Session     string `json:"session"`
+	ClOrdID     string `json:"clOrdID"`
+	OrderID     string `json:"orderID,omitempty"`
+	Account     string `json:"account,omitempty"`
+	Symbol      string `json:"symbol"`
+	Side        string `json:"side"`
+	OrderQty    string `json:"orderQty"`
+	OrdType     string `json:"ordType"`
+	Price       string `json:"price,omitempty"`
+	TimeInForce string `json:"timeInForce,omitempty"`
+
+	// OrdStatus is empty until the counterparty responds.
+	OrdStatus string `json:"ordStatus,omitempty"`
+	ExecType  string `json:"execType,omitempty"`
+	CumQty    string `json:"cumQty,omitempty"`
+	LeavesQty string `json:"leavesQty,omitempty"`
+	AvgPx     string `json:"avgPx,omitempty"`
+	Text      string `json:"text,omitempty"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// order is an order entered through the gateway.
+type order struct {
+	Order
+
+	// updated is closed, and replaced, when the order is updated.
+	updated chan struct{}
+
+	// clOrdIDs are the keys of the order in the orders of its session: its ClOrdID and those of its cancels.
+	clOrdIDs []string
+
+	// doneAt is when the order reached a terminal OrdStatus, or zero.
+	doneAt time.Time
+}
+
+// sentRequest is an order entry message sent on a session, so that rejects referencing it can be correlated.
+type sentRequest struct {
+	order   *order
+	clOrdID string
+	cancel  bool
+}
+
+// gatewaySession is a session of the gateway.
+type gatewaySession struct {
+	name            string
+	sessionID       quickfix.SessionID
+	responseTimeout time.Duration
+	webhookURL      string
+	orderTTL        time.Duration
+
+	loggedOn bool
+	orders   map[string]*order
+	sent     map[int]sentRequest
+
+	// done are the orders in a terminal OrdStatus, in the order they reached it, to be evicted after orderTTL.
+	done []*order
+}
+
+// webhook is an order update to post to a webhook.
+type webhook struct {
+	sessionID quickfix.SessionID
+	url       string
+	order     Order
+}
+
+// Gateway is a quickfix.Application, and an http.Handler serving order entry on its sessions. Orders are kept until
+// HTTPGatewayOrderTTL after they reach a terminal OrdStatus.
+// Embed it in an Application to also handle admin messages, such as setting credentials on the Logon.
+type Gateway struct {
+	settings *quickfix.Settings
+	onError  ErrorHandler
+	mux      *http.ServeMux
+	client   *http.Client
+
+	idPrefix string
+	nextID   atomic.Int64
+
+	webhooks chan webhook
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+
+	mu       sync.Mutex
+	sessions map[quickfix.SessionID]*gatewaySession
+	byName   map[string]*gatewaySession
+}
+
+// NewGateway returns a Gateway serving the sessions of settings. Errors are passed to onError, or logged with the
+// standard logger if onError is nil.
+func NewGateway(settings *quickfix.Settings, onError ErrorHandler) (*Gateway, error) {
+	if onError == nil {
+		onError = func(sessionID quickfix.SessionID, err error) {
+			log.Printf("http gateway %v: %v", sessionID, err)
+		}
+	}
+
+	// Sessions are checked here, as OnCreate can not fail.
+	if _, err := newGatewaySession(settings.GlobalSettings(), quickfix.SessionID{}); err != nil {
+		return nil, err
+	}
+	names := make(map[string]quickfix.SessionID)
+	for sessionID, sessionSettings := range settings.SessionSettings() {
+		s, err := newGatewaySession(sessionSettings, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("session %v: %w", sessionID, err)
+		}
+		if other, ok := names[s.name]; ok {
+			return nil, fmt.Errorf("sessions %v and %v are both named %v", other, sessionID, s.name)
+		}
+		names[s.name] = sessionID
+	}
+
+	g := &Gateway{
+		settings: settings,
+		onError:  onError,
+		mux:      http.NewServeMux(),
+		client:   &http.Client{Timeout: webhookTimeout},
+		idPrefix: strconv.FormatInt(time.Now().UnixNano(), 36),
+		webhooks: make(chan webhook, webhookQueueSize),
+		done:     make(chan struct{}),
+		sessions: make(map[quickfix.SessionID]*gatewaySession),
+		byName:   make(map[string]*gatewaySession),
+	}
+	g.mux.HandleFunc("POST /sessions/{session}/orders", g.handleNewOrder)
+	g.mux.HandleFunc("GET /sessions/{session}/orders/{clOrdID}", g.handleGetOrder)
+	g.mux.HandleFunc("DELETE /sessions/{session}/orders/{clOrdID}", g.handleCancelOrder)
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		g.postWebhooks()
+	}()
+
+	return g, nil
+}
+
+func newGatewaySession(settings *quickfix.SessionSettings, sessionID quickfix.SessionID) (s *gatewaySession, err error) {
+	s = &gatewaySession{
+		name:            sessionID.TargetCompID,
+		sessionID:       sessionID,
+		responseTimeout: defaultResponseTimeout,
+		orderTTL:        defaultOrderTTL,
+		orders:          make(map[string]*order),
+		sent:            make(map[int]sentRequest),
+	}
+
+	if settings.HasSetting(config.HTTPGatewaySessionName) {
+		if s.name, err = settings.Setting(config.HTTPGatewaySessionName); err != nil {
+			return
+		}
+		if s.name == "" || strings.Contains(s.name, "/") {
+			err = quickfix.IncorrectFormatForSetting{Setting: config.HTTPGatewaySessionName, Value: []byte(s.name)}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.HTTPGatewayResponseTimeout) {
+		if s.responseTimeout, err = settings.DurationSetting(config.HTTPGatewayResponseTimeout); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.HTTPGatewayOrderTTL) {
+		if s.orderTTL, err = settings.DurationSetting(config.HTTPGatewayOrderTTL); err != nil {
+			return
+		}
+		if s.orderTTL <= 0 {
+			err = quickfix.IncorrectFormatForSetting{Setting: config.HTTPGatewayOrderTTL, Value: []byte(s.orderTTL.String())}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.HTTPGatewayWebhookURL) {
+		if s.webhookURL, err = settings.Setting(config.HTTPGatewayWebhookURL); err != nil {
+			return
+		}
+		if u, parseErr := url.Parse(s.webhookURL); parseErr != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			err = quickfix.IncorrectFormatForSetting{Setting: config.HTTPGatewayWebhookURL, Value: []byte(s.webhookURL), Err: parseErr}
+			return
+		}
+	}
+
+	return
+}
+
+// addOrder adds o to the orders of the session under clOrdID.
+func (s *gatewaySession) addOrder(clOrdID string, o *order) {
+	s.orders[clOrdID] = o
+	o.clOrdIDs = append(o.clOrdIDs, clOrdID)
+}
+
+// removeClOrdID removes the order or cancel of clOrdID, and the messages sent for it.
+func (s *gatewaySession) removeClOrdID(clOrdID string) {
+	o, ok := s.orders[clOrdID]
+	if !ok {
+		return
+	}
+	delete(s.orders, clOrdID)
+	for i, id := range o.clOrdIDs {
+		if id == clOrdID {
+			o.clOrdIDs = append(o.clOrdIDs[:i], o.clOrdIDs[i+1:]...)
+			break
+		}
+	}
+	for seqNum, sent := range s.sent {
+		if sent.clOrdID == clOrdID {
+			delete(s.sent, seqNum)
+		}
+	}
+}
+
+// evict removes the orders which reached a terminal OrdStatus orderTTL before now.
+func (s *gatewaySession) evict(now time.Time) {
+	for len(s.done) > 0 && now.Sub(s.done[0].doneAt) >= s.orderTTL {
+		o := s.done[0]
+		s.done[0] = nil
+		s.done = s.done[1:]
+
+		for _, clOrdID := range o.clOrdIDs {
+			delete(s.orders, clOrdID)
+		}
+		for seqNum, sent := range s.sent {
+			if sent.order == o {
+				delete(s.sent, seqNum)
+			}
+		}
+	}
+}
+
+// isTerminal returns whether an order of ordStatus is no longer working.
+func isTerminal(ordStatus string) bool {
+	switch ordStatus {
+	case ordStatusFilled, ordStatusDoneForDay, ordStatusCanceled, ordStatusRejected, ordStatusExpired:
+		return true
+	}
+	return false
+}
+
+// ServeHTTP serves the order entry endpoints.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// Close stops posting to webhooks, once the HTTP server and the initiator or acceptor are stopped.
+func (g *Gateway) Close() error {
+	g.once.Do(func() {
+		close(g.done)
+	})
+	g.wg.Wait()
+	return nil
+}
+
+// OnCreate adds the session to the gateway.
+func (g *Gateway) OnCreate(sessionID quickfix.SessionID) {
+	settings, ok := g.settings.SessionSettings()[sessionID]
+	if !ok {
+		settings = g.settings.GlobalSettings()
+	}
+
+	s, err := newGatewaySession(settings, sessionID)
+	if err != nil {
+		g.onError(sessionID, err)
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.sessions[sessionID]; ok {
+		return
+	}
+	if other, ok := g.byName[s.name]; ok {
+		g.onError(sessionID, fmt.Errorf("session %v is already named %v", other.sessionID, s.name))
+		return
+	}
+	g.sessions[sessionID] = s
+	g.byName[s.name] = s
+}
+
+// OnLogon starts accepting orders for the session.
+func (g *Gateway) OnLogon(sessionID quickfix.SessionID) {
+	g.setLoggedOn(sessionID, true)
+}
+
+// OnLogout stops accepting orders for the session.
+func (g *Gateway) OnLogout(sessionID quickfix.SessionID) {
+	g.setLoggedOn(sessionID, false)
+}
+
+func (g *Gateway) setLoggedOn(sessionID quickfix.SessionID, loggedOn bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if s, ok := g.sessions[sessionID]; ok {
+		s.loggedOn = loggedOn
+	}
+}
+
+// ToAdmin does nothing.
+func (g *Gateway) ToAdmin(*quickfix.Message, quickfix.SessionID) {}
+
+// ToApp records the MsgSeqNum of the orders and cancels sent, to correlate the rejects referencing it. It is
+// called before the message is written, so that a reject can not arrive first.
+func (g *Gateway) ToApp(msg *quickfix.Message, sessionID quickfix.SessionID) error {
+	msgType, _ := msg.MsgType()
+	if msgType != msgTypeNewOrderSingle && msgType != msgTypeOrderCancelRequest {
+		return nil
+	}
+	seqNum, err := msg.Header.GetInt(tagMsgSeqNum)
+	if err != nil {
+		return nil
+	}
+	clOrdID, _ := msg.Body.GetString(tagClOrdID)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if s, ok := g.sessions[sessionID]; ok {
+		if o, ok := s.orders[clOrdID]; ok {
+			s.sent[seqNum] = sentRequest{order: o, clOrdID: clOrdID, cancel: msgType == msgTypeOrderCancelRequest}
+		}
+	}
+	return nil
+}
+
+// FromAdmin correlates session level Rejects to the orders they reject.
+func (g *Gateway) FromAdmin(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	if msgType, _ := msg.MsgType(); msgType == msgTypeReject {
+		g.onReject(msg, sessionID)
+	}
+	return nil
+}
+
+// FromApp correlates ExecutionReports, OrderCancelRejects and BusinessMessageRejects to their orders.
+func (g *Gateway) FromApp(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	msgType, _ := msg.MsgType()
+	switch msgType {
+	case msgTypeExecutionReport, msgTypeOrderCancelReject:
+		g.onOrderResponse(msg, sessionID, msgType)
+	case msgTypeBusinessMessageReject:
+		g.onReject(msg, sessionID)
+	}
+	return nil
+}
+
+// onOrderResponse updates the order of an ExecutionReport or OrderCancelReject.
+func (g *Gateway) onOrderResponse(msg *quickfix.Message, sessionID quickfix.SessionID, msgType string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s, ok := g.sessions[sessionID]
+	if !ok {
+		return
+	}
+
+	var o *order
+	for _, tag := range []quickfix.Tag{tagClOrdID, tagOrigClOrdID} {
+		if clOrdID, err := msg.Body.GetString(tag); err == nil {
+			if o = s.orders[clOrdID]; o != nil {
+				break
+			}
+		}
+	}
+	if o == nil {
+		return
+	}
+
+	// The messages of the order are no longer rejected once the counterparty responds to them.
+	for seqNum, sent := range s.sent {
+		if sent.order == o {
+			delete(s.sent, seqNum)
+		}
+	}
+
+	for tag, value := range map[quickfix.Tag]*string{
+		tagOrderID:   &o.OrderID,
+		tagOrdStatus: &o.OrdStatus,
+		tagText:      &o.Text,
+	} {
+		if v, err := msg.Body.GetString(tag); err == nil {
+			*value = v
+		}
+	}
+	if msgType == msgTypeExecutionReport {
+		for tag, value := range map[quickfix.Tag]*string{
+			tagExecType:  &o.ExecType,
+			tagCumQty:    &o.CumQty,
+			tagLeavesQty: &o.LeavesQty,
+			tagAvgPx:     &o.AvgPx,
+		} {
+			if v, err := msg.Body.GetString(tag); err == nil {
+				*value = v
+			}
+		}
+	}
+
+	g.updated(s, o)
+}
+
+// onReject updates the order of the message referenced by a Reject or BusinessMessageReject.
+func (g *Gateway) onReject(msg *quickfix.Message, sessionID quickfix.SessionID) {
+	refSeqNum, err := msg.Body.GetInt(tagRefSeqNum)
+	if err != nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s, ok := g.sessions[sessionID]
+	if !ok {
+		return
+	}
+	sent, ok := s.sent[refSeqNum]
+	if !ok {
+		return
+	}
+	delete(s.sent, refSeqNum)
+
+	// A rejected cancel leaves the order as it was.
+	if !sent.cancel {
+		sent.order.OrdStatus = ordStatusRejected
+	}
+	sent.order.Text, _ = msg.Body.GetString(tagText)
+	g.updated(s, sent.order)
+}
+
+// updated signals the waiters of o and posts it to the webhook. It must be called with the lock held.
+func (g *Gateway) updated(s *gatewaySession, o *order) {
+	o.UpdatedAt = time.Now()
+	close(o.updated)
+	o.updated = make(chan struct{})
+
+	if o.doneAt.IsZero() && isTerminal(o.OrdStatus) {
+		o.doneAt = o.UpdatedAt
+		s.done = append(s.done, o)
+	}
+	s.evict(o.UpdatedAt)
+
+	if s.webhookURL == "" {
+		return
+	}
+	select {
+	case g.webhooks <- webhook{sessionID: s.sessionID, url: s.webhookURL, order: o.Order}:
+	default:
+		g.onError(s.sessionID, fmt.Errorf("webhook queue full, dropping update of order %v", o.ClOrdID))
+	}
+}
+
+// postWebhooks posts the order updates to their webhooks, in order, until the gateway is closed.
+func (g *Gateway) postWebhooks() {
+	for {
+		select {
+		case hook := <-g.webhooks:
+			if err := g.postWebhook(hook); err != nil {
+				g.onError(hook.sessionID, err)
+			}
+		case <-g.done:
+			return
+		}
+	}
+}
+
+func (g *Gateway) postWebhook(hook webhook) error {
+	body, err := json.Marshal(hook.order)
+	if err != nil {
+		return err
+	}
+
+	resp, err := g.client.Post(hook.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook %v answered %v", hook.url, resp.Status)
+	}
+	return nil
+}
+
+// newClOrdID returns a ClOrdID unique to the gateway.
+func (g *Gateway) newClOrdID() string {
+	return g.idPrefix + "-" + strconv.FormatInt(g.nextID.Add(1), 10)
+}
+
+// httpError is an error answered with its status code.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e httpError) Error() string { return e.err.Error() }
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var httpErr httpError
+	if errors.As(err, &httpErr) {
+		status = httpErr.status
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// session returns the session named by the request.
+func (g *Gateway) session(r *http.Request) (*gatewaySession, error) {
+	name := r.PathValue("session")
+	s, ok := g.byName[name]
+	if !ok {
+		return nil, httpError{http.StatusNotFound, fmt.Errorf("unknown session %v", name)}
+	}
+	return s, nil
+}
+
+// sessionOrder returns the order of the request. It must be called with the lock held.
+func (g *Gateway) sessionOrder(r *http.Request) (*gatewaySession, *order, error) {
+	s, err := g.session(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clOrdID := r.PathValue("clOrdID")
+	o, ok := s.orders[clOrdID]
+	if !ok {
+		return nil, nil, httpError{http.StatusNotFound, fmt.Errorf("unknown order %v", clOrdID)}
+	}
+	return s, o, nil
+}
+
+func (g *Gateway) handleGetOrder(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	_, o, err := g.sessionOrder(r)
+	var snapshot Order
+	if err == nil {
+		snapshot = o.Order
+	}
+	g.mu.Unlock()
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+func (g *Gateway) handleNewOrder(w http.ResponseWriter, r *http.Request) {
+	var req OrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, httpError{http.StatusBadRequest, err})
+		return
+	}
+	for field, value := range map[string]string{"symbol": req.Symbol, "side": req.Side, "orderQty": req.OrderQty, "ordType": req.OrdType} {
+		if value == "" {
+			writeError(w, httpError{http.StatusBadRequest, fmt.Errorf("missing %v", field)})
+			return
+		}
+	}
+	if req.ClOrdID == "" {
+		req.ClOrdID = g.newClOrdID()
+	}
+
+	g.mu.Lock()
+	s, err := g.session(r)
+	if err == nil {
+		s.evict(time.Now())
+		err = checkLoggedOn(s)
+	}
+	if err == nil {
+		if _, ok := s.orders[req.ClOrdID]; ok {
+			err = httpError{http.StatusConflict, fmt.Errorf("duplicate order %v", req.ClOrdID)}
+		}
+	}
+	var o *order
+	if err == nil {
+		o = &order{
+			Order: Order{
+				Session:     s.name,
+				ClOrdID:     req.ClOrdID,
+				Account:     req.Account,
+				Symbol:      req.Symbol,
+				Side:        req.Side,
+				OrderQty:    req.OrderQty,
+				OrdType:     req.OrdType,
+				Price:       req.Price,
+				TimeInForce: req.TimeInForce,
+				UpdatedAt:   time.Now(),
+			},
+			updated: make(chan struct{}),
+		}
+		s.addOrder(o.ClOrdID, o)
+	}
+	g.mu.Unlock()
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	msg := newMessage(s.sessionID, msgTypeNewOrderSingle)
+	for tag, value := range req.Fields {
+		msg.Body.SetString(tag, value)
+	}
+	for tag, value := range map[quickfix.Tag]string{
+		tagClOrdID:     o.ClOrdID,
+		tagAccount:     o.Account,
+		tagSymbol:      o.Symbol,
+		tagSide:        o.Side,
+		tagOrderQty:    o.OrderQty,
+		tagOrdType:     o.OrdType,
+		tagPrice:       o.Price,
+		tagTimeInForce: o.TimeInForce,
+	} {
+		if value != "" {
+			msg.Body.SetString(tag, value)
+		}
+	}
+	if s.sessionID.BeginString != quickfix.BeginStringFIXT11 && !msg.Body.Has(tagHandlInst) {
+		msg.Body.SetString(tagHandlInst, handlInstAutomatedNoBrokerExec)
+	}
+
+	g.sendAndWait(w, r, s, o, msg)
+}
+
+func (g *Gateway) handleCancelOrder(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	s, o, err := g.sessionOrder(r)
+	if err == nil {
+		err = checkLoggedOn(s)
+	}
+	var msg *quickfix.Message
+	if err == nil {
+		clOrdID := g.newClOrdID()
+		s.addOrder(clOrdID, o)
+
+		msg = newMessage(s.sessionID, msgTypeOrderCancelRequest)
+		for tag, value := range map[quickfix.Tag]string{
+			tagOrigClOrdID: o.ClOrdID,
+			tagClOrdID:     clOrdID,
+			tagOrderID:     o.OrderID,
+			tagAccount:     o.Account,
+			tagSymbol:      o.Symbol,
+			tagSide:        o.Side,
+			tagOrderQty:    o.OrderQty,
+		} {
+			if value != "" {
+				msg.Body.SetString(tag, value)
+			}
+		}
+	}
+	g.mu.Unlock()
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	g.sendAndWait(w, r, s, o, msg)
+}
+
+func checkLoggedOn(s *gatewaySession) error {
+	if !s.loggedOn {
+		return httpError{http.StatusServiceUnavailable, fmt.Errorf("session %v is not logged on", s.name)}
+	}
+	return nil
+}
+
+// newMessage returns an order entry message of msgType, with a TransactTime of now.
+func newMessage(sessionID quickfix.SessionID, msgType string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(8), sessionID.BeginString)
+	msg.Header.SetString(quickfix.Tag(35), msgType)
+	switch sessionID.BeginString {
+	case quickfix.BeginStringFIX40, quickfix.BeginStringFIX41:
+	default:
+		msg.Body.SetField(tagTransactTime, quickfix.FIXUTCTimestamp{Time: time.Now()})
+	}
+	return msg
+}
+
+// sendAndWait sends msg for o on the session, and answers the order once it is updated, or the response timeout
+// of the session elapses. The order or cancel of msg is removed if it can not be sent.
+func (g *Gateway) sendAndWait(w http.ResponseWriter, r *http.Request, s *gatewaySession, o *order, msg *quickfix.Message) {
+	ctx, stop := context.WithTimeout(r.Context(), s.responseTimeout)
+	defer stop()
+
+	g.mu.Lock()
+	updated := o.updated
+	g.mu.Unlock()
+
+	receipt, err := quickfix.SendToTargetAsync(msg, s.sessionID)
+	if err == nil {
+		// A message not yet written when the response timeout elapses is answered as pending.
+		if _, err = receipt.Wait(ctx); ctx.Err() != nil {
+			err = nil
+		}
+	}
+	if err != nil {
+		clOrdID, _ := msg.Body.GetString(tagClOrdID)
+		g.mu.Lock()
+		s.removeClOrdID(clOrdID)
+		g.mu.Unlock()
+		writeError(w, httpError{http.StatusBadGateway, err})
+		return
+	}
+
+	status := http.StatusOK
+	select {
+	case <-updated:
+	case <-ctx.Done():
+		status = http.StatusAccepted
+	}
+
+	g.mu.Lock()
+	snapshot := o.Order
+	g.mu.Unlock()
+	writeJSON(w, status, snapshot)
+}
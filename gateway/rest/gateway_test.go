@@ -0,0 +1,317 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package rest
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// brokerApp acknowledges the orders and cancels it receives, rejecting orders for the symbol REJECT.
+type brokerApp struct {
+	loggedOn chan quickfix.SessionID
+}
+
+func (a brokerApp) OnCreate(quickfix.SessionID)                       {}
+func (a brokerApp) OnLogon(sessionID quickfix.SessionID)              { a.loggedOn <- sessionID }
+func (a brokerApp) OnLogout(quickfix.SessionID)                       {}
+func (a brokerApp) ToAdmin(*quickfix.Message, quickfix.SessionID)     {}
+func (a brokerApp) ToApp(*quickfix.Message, quickfix.SessionID) error { return nil }
+func (a brokerApp) FromAdmin(*quickfix.Message, quickfix.SessionID) quickfix.MessageRejectError {
+	return nil
+}
+
+func (a brokerApp) FromApp(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	symbol, _ := msg.Body.GetString(tagSymbol)
+	if symbol == "REJECT" {
+		return quickfix.NewBusinessMessageRejectError("Unknown symbol", 2, nil)
+	}
+
+	ordStatus := "0"
+	if msgType, _ := msg.MsgType(); msgType == msgTypeOrderCancelRequest {
+		ordStatus = "4"
+	}
+
+	report := quickfix.NewMessage()
+	report.Header.SetString(quickfix.Tag(35), msgTypeExecutionReport)
+	for _, tag := range []quickfix.Tag{tagClOrdID, tagOrigClOrdID, tagSymbol, tagSide, tagOrderQty} {
+		if value, err := msg.Body.GetString(tag); err == nil {
+			report.Body.SetString(tag, value)
+		}
+	}
+	report.Body.SetString(tagOrderID, "BROKER-1")
+	report.Body.SetString(quickfix.Tag(17), "EXEC-"+ordStatus)
+	report.Body.SetString(tagExecType, ordStatus)
+	report.Body.SetString(tagOrdStatus, ordStatus)
+	report.Body.SetString(tagCumQty, "0")
+	report.Body.SetString(tagLeavesQty, "100")
+	report.Body.SetString(tagAvgPx, "0")
+	_ = quickfix.SendToTarget(report, sessionID)
+	return nil
+}
+
+func sessionSettings(senderCompID, targetCompID string, settings map[string]string) *quickfix.SessionSettings {
+	sessionSettings := quickfix.NewSessionSettings()
+	sessionSettings.Set(config.BeginString, quickfix.BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, senderCompID)
+	sessionSettings.Set(config.TargetCompID, targetCompID)
+	for setting, value := range settings {
+		sessionSettings.Set(setting, value)
+	}
+	return sessionSettings
+}
+
+func newSettings(t *testing.T, global map[string]string, sessions ...*quickfix.SessionSettings) *quickfix.Settings {
+	settings := quickfix.NewSettings()
+	for setting, value := range global {
+		settings.GlobalSettings().Set(setting, value)
+	}
+	for _, sessionSettings := range sessions {
+		_, err := settings.AddSession(sessionSettings)
+		require.NoError(t, err)
+	}
+	return settings
+}
+
+func do(t *testing.T, method, url, body string) (int, Order) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var o Order
+	if resp.StatusCode < http.StatusBadRequest {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&o))
+	}
+	return resp.StatusCode, o
+}
+
+func TestNewGateway_InvalidSettings(t *testing.T) {
+	var tests = []struct {
+		name     string
+		sessions []*quickfix.SessionSettings
+	}{
+		{name: "duplicate name", sessions: []*quickfix.SessionSettings{
+			sessionSettings("A", "BROKER", nil),
+			sessionSettings("B", "OTHER", map[string]string{config.HTTPGatewaySessionName: "BROKER"}),
+		}},
+		{name: "invalid name", sessions: []*quickfix.SessionSettings{
+			sessionSettings("A", "BROKER", map[string]string{config.HTTPGatewaySessionName: "a/b"}),
+		}},
+		{name: "invalid timeout", sessions: []*quickfix.SessionSettings{
+			sessionSettings("A", "BROKER", map[string]string{config.HTTPGatewayResponseTimeout: "soon"}),
+		}},
+		{name: "invalid order ttl", sessions: []*quickfix.SessionSettings{
+			sessionSettings("A", "BROKER", map[string]string{config.HTTPGatewayOrderTTL: "0s"}),
+		}},
+		{name: "invalid webhook", sessions: []*quickfix.SessionSettings{
+			sessionSettings("A", "BROKER", map[string]string{config.HTTPGatewayWebhookURL: "ftp://example.com"}),
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewGateway(newSettings(t, nil, test.sessions...), nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGateway(t *testing.T) {
+	host := "pipe://http_gateway"
+	webhooks := make(chan Order, 10)
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var o Order
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&o))
+		webhooks <- o
+	}))
+	defer webhookServer.Close()
+
+	broker := brokerApp{loggedOn: make(chan quickfix.SessionID, 10)}
+	acceptor, err := quickfix.NewAcceptor(broker, quickfix.NewMemoryStoreFactory(),
+		newSettings(t, map[string]string{config.SocketAcceptHost: host}, sessionSettings("BROKER", "CLIENT", nil)),
+		quickfix.NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	settings := newSettings(t, nil, sessionSettings("CLIENT", "BROKER", map[string]string{
+		config.SocketConnectHost: host, config.SocketConnectPort: "5023", config.HeartBtInt: "30", config.ReconnectInterval: "1",
+		config.HTTPGatewaySessionName: "broker", config.HTTPGatewayWebhookURL: webhookServer.URL,
+	}))
+	gateway, err := NewGateway(settings, nil)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, gateway.Close()) }()
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	initiator, err := quickfix.NewInitiator(gateway, quickfix.NewMemoryStoreFactory(), settings, quickfix.NewNullLogFactory())
+	require.NoError(t, err)
+
+	// Orders are refused until the session is logged on.
+	status, _ := do(t, http.MethodPost, server.URL+"/sessions/broker/orders", `{"symbol":"MSFT","side":"1","orderQty":"100","ordType":"1"}`)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+	select {
+	case <-broker.loggedOn:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for logon")
+	}
+	assert.Eventually(t, func() bool {
+		status, _ := do(t, http.MethodPost, server.URL+"/sessions/broker/orders", `{"clOrdID":"ORDER-1","symbol":"MSFT","side":"1","orderQty":"100","ordType":"2","price":"10.5"}`)
+		return status == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	var tests = []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		status   int
+		expected Order
+	}{
+		{
+			name: "order status", method: http.MethodGet, path: "/sessions/broker/orders/ORDER-1",
+			status:   http.StatusOK,
+			expected: Order{ClOrdID: "ORDER-1", OrderID: "BROKER-1", OrdStatus: "0", ExecType: "0", Price: "10.5"},
+		},
+		{
+			name: "cancel", method: http.MethodDelete, path: "/sessions/broker/orders/ORDER-1",
+			status:   http.StatusOK,
+			expected: Order{ClOrdID: "ORDER-1", OrderID: "BROKER-1", OrdStatus: "4", ExecType: "4", Price: "10.5"},
+		},
+		{
+			name: "rejected", method: http.MethodPost, path: "/sessions/broker/orders",
+			body:     `{"clOrdID":"ORDER-2","symbol":"REJECT","side":"1","orderQty":"100","ordType":"1"}`,
+			status:   http.StatusOK,
+			expected: Order{ClOrdID: "ORDER-2", OrdStatus: ordStatusRejected, Text: "Unknown symbol"},
+		},
+		{name: "duplicate", method: http.MethodPost, path: "/sessions/broker/orders", body: `{"clOrdID":"ORDER-1","symbol":"MSFT","side":"1","orderQty":"100","ordType":"1"}`, status: http.StatusConflict},
+		{name: "missing field", method: http.MethodPost, path: "/sessions/broker/orders", body: `{"symbol":"MSFT"}`, status: http.StatusBadRequest},
+		{name: "unknown session", method: http.MethodPost, path: "/sessions/other/orders", body: `{"symbol":"MSFT","side":"1","orderQty":"100","ordType":"1"}`, status: http.StatusNotFound},
+		{name: "unknown order", method: http.MethodDelete, path: "/sessions/broker/orders/ORDER-3", status: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, o := do(t, test.method, server.URL+test.path, test.body)
+			require.Equal(t, test.status, status)
+			if test.status != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, "broker", o.Session)
+			assert.Equal(t, test.expected.ClOrdID, o.ClOrdID)
+			assert.Equal(t, test.expected.OrderID, o.OrderID)
+			assert.Equal(t, test.expected.OrdStatus, o.OrdStatus)
+			assert.Equal(t, test.expected.ExecType, o.ExecType)
+			assert.Equal(t, test.expected.Price, o.Price)
+			assert.Equal(t, test.expected.Text, o.Text)
+		})
+	}
+
+	// Each update is posted to the webhook, in order.
+	var updates []string
+	for range 3 {
+		select {
+		case o := <-webhooks:
+			updates = append(updates, o.ClOrdID+":"+o.OrdStatus)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for webhook")
+		}
+	}
+	assert.Equal(t, []string{"ORDER-1:0", "ORDER-1:4", "ORDER-2:8"}, updates)
+}
+
+func TestGateway_EvictsDoneOrders(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: quickfix.BeginStringFIX42, SenderCompID: "CLIENT", TargetCompID: "BROKER"}
+	gateway, err := NewGateway(newSettings(t, nil, sessionSettings("CLIENT", "BROKER", map[string]string{config.HTTPGatewayOrderTTL: "1m"})), nil)
+	require.NoError(t, err)
+	defer gateway.Close()
+	gateway.OnCreate(sessionID)
+	s := gateway.sessions[sessionID]
+
+	for _, clOrdID := range []string{"ORDER-1", "ORDER-2"} {
+		s.addOrder(clOrdID, &order{Order: Order{ClOrdID: clOrdID}, updated: make(chan struct{})})
+	}
+	s.addOrder("CANCEL-1", s.orders["ORDER-1"])
+	s.sent[3] = sentRequest{order: s.orders["ORDER-1"], clOrdID: "CANCEL-1", cancel: true}
+
+	report := func(clOrdID, origClOrdID, ordStatus string) {
+		msg := quickfix.NewMessage()
+		msg.Header.SetString(quickfix.Tag(35), msgTypeExecutionReport)
+		msg.Body.SetString(tagClOrdID, clOrdID)
+		if origClOrdID != "" {
+			msg.Body.SetString(tagOrigClOrdID, origClOrdID)
+		}
+		msg.Body.SetString(tagOrdStatus, ordStatus)
+		assert.Nil(t, gateway.FromApp(msg, sessionID))
+	}
+	report("ORDER-1", "", "0")
+	report("ORDER-2", "", "0")
+	report("CANCEL-1", "ORDER-1", ordStatusCanceled)
+
+	// Working orders are kept, done orders are evicted with their cancels once the TTL elapses.
+	done := s.orders["ORDER-1"].doneAt
+	require.False(t, done.IsZero())
+	s.evict(done.Add(time.Minute - time.Nanosecond))
+	assert.Len(t, s.orders, 3)
+
+	s.addOrder("ORDER-3", &order{Order: Order{ClOrdID: "ORDER-3"}, updated: make(chan struct{})})
+	s.sent[4] = sentRequest{order: s.orders["ORDER-1"], clOrdID: "ORDER-1"}
+	s.evict(done.Add(time.Minute))
+	assert.Equal(t, []string{"ORDER-2", "ORDER-3"}, slices.Sorted(maps.Keys(s.orders)))
+	assert.Empty(t, s.sent)
+	assert.Empty(t, s.done)
+}
+
+func TestGateway_SendFailure(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: quickfix.BeginStringFIX42, SenderCompID: "CLIENT", TargetCompID: "BROKER"}
+	gateway, err := NewGateway(newSettings(t, nil, sessionSettings("CLIENT", "BROKER", map[string]string{config.HTTPGatewaySessionName: "broker"})), nil)
+	require.NoError(t, err)
+	defer gateway.Close()
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	// The session is not running, so nothing can be sent on it.
+	gateway.OnCreate(sessionID)
+	gateway.OnLogon(sessionID)
+	s := gateway.sessions[sessionID]
+	s.addOrder("ORDER-1", &order{Order: Order{ClOrdID: "ORDER-1"}, updated: make(chan struct{})})
+
+	status, _ := do(t, http.MethodPost, server.URL+"/sessions/broker/orders", `{"clOrdID":"ORDER-2","symbol":"MSFT","side":"1","orderQty":"100","ordType":"1"}`)
+	assert.Equal(t, http.StatusBadGateway, status)
+	status, _ = do(t, http.MethodDelete, server.URL+"/sessions/broker/orders/ORDER-1", "")
+	assert.Equal(t, http.StatusBadGateway, status)
+
+	assert.Equal(t, []string{"ORDER-1"}, slices.Collect(maps.Keys(s.orders)))
+	assert.Equal(t, []string{"ORDER-1"}, s.orders["ORDER-1"].clOrdIDs)
+}
@@ -0,0 +1,224 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SessionHealth is the status of a session.
+type SessionHealth struct {
+	SessionID SessionID
+	State     SessionState
+
+	// ConnectedSince is when the session connected to the counterparty, and is zero while not connected.
+	ConnectedSince time.Time
+
+	// LastHeartbeat is when the last Heartbeat was received from the counterparty.
+	LastHeartbeat time.Time
+
+	NextSenderMsgSeqNum int
+	NextTargetMsgSeqNum int
+
+	// LastError is the last error of the session, and is nil if there has been none.
+	LastError     error
+	LastErrorTime time.Time
+}
+
+// LoggedOn returns true if the session is logged on.
+func (h SessionHealth) LoggedOn() bool {
+	switch h.State {
+	case SessionStateLoggedOn, SessionStateResendPending, SessionStateTestRequestPending:
+		return true
+	}
+	return false
+}
+
+// Ready returns true if the session is logged on, or is outside of its session time.
+func (h SessionHealth) Ready() bool {
+	return h.LoggedOn() || h.State == SessionStateNotSessionTime
+}
+
+// Health is the status of an Acceptor or Initiator and its sessions.
+type Health struct {
+	// Running is true between Start and Stop.
+	Running bool
+
+	// Sessions are ordered by SessionID.
+	Sessions []SessionHealth
+}
+
+// Ready returns true if the engine is running and all of its sessions are ready.
+func (h Health) Ready() bool {
+	if !h.Running {
+		return false
+	}
+
+	for _, session := range h.Sessions {
+		if !session.Ready() {
+			return false
+		}
+	}
+	return true
+}
+
+// HealthReporter reports its Health, and is implemented by Acceptor and Initiator.
+type HealthReporter interface {
+	Health() Health
+}
+
+type sessionHealth struct {
+	mu             sync.Mutex
+	state          SessionState
+	connectedSince time.Time
+	lastHeartbeat  time.Time
+	lastError      error
+	lastErrorTime  time.Time
+}
+
+func (h *sessionHealth) stateChanged(to SessionState, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch {
+	case !to.IsConnected():
+		h.connectedSince = time.Time{}
+	case !h.state.IsConnected():
+		h.connectedSince = now
+	}
+	h.state = to
+}
+
+func (h *sessionHealth) heartbeatReceived(receivedAt time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastHeartbeat = receivedAt
+}
+
+func (h *sessionHealth) errorOccurred(err error, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastError = err
+	h.lastErrorTime = now
+}
+
+func (s *session) health() SessionHealth {
+	s.healthStatus.mu.Lock()
+	health := SessionHealth{
+		SessionID:      s.sessionID,
+		State:          s.healthStatus.state,
+		ConnectedSince: s.healthStatus.connectedSince,
+		LastHeartbeat:  s.healthStatus.lastHeartbeat,
+		LastError:      s.healthStatus.lastError,
+		LastErrorTime:  s.healthStatus.lastErrorTime,
+	}
+	s.healthStatus.mu.Unlock()
+
+	health.NextSenderMsgSeqNum = s.store.NextSenderMsgSeqNum()
+	health.NextTargetMsgSeqNum = s.store.NextTargetMsgSeqNum()
+	return health
+}
+
+func newHealth(running bool, sessions []*session) Health {
+	health := Health{Running: running, Sessions: make([]SessionHealth, 0, len(sessions))}
+	for _, s := range sessions {
+		health.Sessions = append(health.Sessions, s.health())
+	}
+
+	sort.Slice(health.Sessions, func(i, j int) bool {
+		return health.Sessions[i].SessionID.String() < health.Sessions[j].SessionID.String()
+	})
+	return health
+}
+
+type sessionHealthJSON struct {
+	SessionID           string     `json:"session_id"`
+	State               string     `json:"state"`
+	LoggedOn            bool       `json:"logged_on"`
+	ConnectedSince      *time.Time `json:"connected_since,omitempty"`
+	LastHeartbeat       *time.Time `json:"last_heartbeat,omitempty"`
+	NextSenderMsgSeqNum int        `json:"next_sender_msg_seq_num"`
+	NextTargetMsgSeqNum int        `json:"next_target_msg_seq_num"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastErrorTime       *time.Time `json:"last_error_time,omitempty"`
+}
+
+type healthJSON struct {
+	Running  bool                `json:"running"`
+	Ready    bool                `json:"ready"`
+	Sessions []sessionHealthJSON `json:"sessions"`
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func (h Health) toJSON() healthJSON {
+	j := healthJSON{Running: h.Running, Ready: h.Ready(), Sessions: make([]sessionHealthJSON, 0, len(h.Sessions))}
+	for _, s := range h.Sessions {
+		session := sessionHealthJSON{
+			SessionID:           s.SessionID.String(),
+			State:               s.State.String(),
+			LoggedOn:            s.LoggedOn(),
+			ConnectedSince:      optionalTime(s.ConnectedSince),
+			LastHeartbeat:       optionalTime(s.LastHeartbeat),
+			NextSenderMsgSeqNum: s.NextSenderMsgSeqNum,
+			NextTargetMsgSeqNum: s.NextTargetMsgSeqNum,
+			LastErrorTime:       optionalTime(s.LastErrorTime),
+		}
+		if s.LastError != nil {
+			session.LastError = s.LastError.Error()
+		}
+		j.Sessions = append(j.Sessions, session)
+	}
+	return j
+}
+
+type healthHandler struct {
+	engine HealthReporter
+	ok     func(Health) bool
+}
+
+func (h healthHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	health := h.engine.Health()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !h.ok(health) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(health.toJSON())
+}
+
+// LivenessHandler returns an http.Handler for liveness probes of engine. It responds with the Health of engine as JSON,
+// with status 200 OK while engine is running and 503 Service Unavailable otherwise.
+func LivenessHandler(engine HealthReporter) http.Handler {
+	return healthHandler{engine: engine, ok: func(h Health) bool { return h.Running }}
+}
+
+// ReadinessHandler returns an http.Handler for readiness probes of engine. It responds with the Health of engine as JSON,
+// with status 200 OK while engine is Ready and 503 Service Unavailable otherwise.
+func ReadinessHandler(engine HealthReporter) http.Handler {
+	return healthHandler{engine: engine, ok: Health.Ready}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestHealthTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthTestSuite))
+}
+
+func (s *HealthTestSuite) SetupTest() {
+	s.Init()
+	s.session.State = latentState{}
+}
+
+func (s *HealthTestSuite) TestStateAndConnectedSince() {
+	s.session.stateMachine.setState(s.session, logonState{})
+	health := s.session.health()
+	s.Equal(SessionStateAwaitingLogon, health.State)
+	s.False(health.LoggedOn())
+	connectedSince := health.ConnectedSince
+	s.False(connectedSince.IsZero())
+
+	s.session.stateMachine.setState(s.session, inSession{})
+	health = s.session.health()
+	s.Equal(SessionStateLoggedOn, health.State)
+	s.True(health.LoggedOn())
+	s.True(health.Ready())
+	s.Equal(connectedSince, health.ConnectedSince)
+	s.Equal(1, health.NextSenderMsgSeqNum)
+	s.Equal(1, health.NextTargetMsgSeqNum)
+
+	s.MockApp.On("OnLogout")
+	s.session.stateMachine.setState(s.session, latentState{})
+	health = s.session.health()
+	s.False(health.Ready())
+	s.True(health.ConnectedSince.IsZero())
+}
+
+func (s *HealthTestSuite) TestLastHeartbeatAndError() {
+	receivedAt := time.Now().Add(-time.Second)
+	heartbeat := s.Heartbeat()
+	heartbeat.ReceiveTime = receivedAt
+	s.session.handleHeartbeat(heartbeat)
+
+	err := errors.New("store unavailable")
+	s.session.logError(err)
+
+	health := s.session.health()
+	s.Equal(receivedAt, health.LastHeartbeat)
+	s.Equal(err, health.LastError)
+	s.False(health.LastErrorTime.IsZero())
+}
+
+type staticHealth Health
+
+func (h staticHealth) Health() Health { return Health(h) }
+
+func serveHealth(t *testing.T, handler http.Handler) (int, map[string]interface{}) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var body map[string]interface{}
+	require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	return recorder.Code, body
+}
+
+func TestHealthHandlers(t *testing.T) {
+	sessionID := SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	loggedOn := SessionHealth{SessionID: sessionID, State: SessionStateLoggedOn, NextSenderMsgSeqNum: 5, NextTargetMsgSeqNum: 7}
+	latent := SessionHealth{SessionID: sessionID, State: SessionStateLatent, LastError: errors.New("connection refused"), LastErrorTime: time.Now()}
+
+	var tests = []struct {
+		health        Health
+		expectedLive  int
+		expectedReady int
+	}{
+		{Health{Running: true, Sessions: []SessionHealth{loggedOn}}, http.StatusOK, http.StatusOK},
+		{Health{Running: true, Sessions: []SessionHealth{{State: SessionStateNotSessionTime}}}, http.StatusOK, http.StatusOK},
+		{Health{Running: true, Sessions: []SessionHealth{loggedOn, latent}}, http.StatusOK, http.StatusServiceUnavailable},
+		{Health{Running: false, Sessions: []SessionHealth{latent}}, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+	}
+
+	for _, test := range tests {
+		code, _ := serveHealth(t, LivenessHandler(staticHealth(test.health)))
+		assert.Equal(t, test.expectedLive, code)
+
+		code, _ = serveHealth(t, ReadinessHandler(staticHealth(test.health)))
+		assert.Equal(t, test.expectedReady, code)
+	}
+
+	_, body := serveHealth(t, ReadinessHandler(staticHealth(Health{Running: true, Sessions: []SessionHealth{loggedOn, latent}})))
+	assert.Equal(t, true, body["running"])
+	assert.Equal(t, false, body["ready"])
+
+	sessions := body["sessions"].([]interface{})
+	require.Len(t, sessions, 2)
+	session := sessions[0].(map[string]interface{})
+	assert.Equal(t, "FIX.4.2:SENDER->TARGET", session["session_id"])
+	assert.Equal(t, "LoggedOn", session["state"])
+	assert.Equal(t, true, session["logged_on"])
+	assert.Equal(t, float64(5), session["next_sender_msg_seq_num"])
+	assert.Equal(t, float64(7), session["next_target_msg_seq_num"])
+	assert.NotContains(t, session, "last_error")
+	assert.NotContains(t, session, "connected_since")
+	assert.Equal(t, "connection refused", sessions[1].(map[string]interface{})["last_error"])
+}
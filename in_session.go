@@ -17,7 +17,6 @@ package quickfix
 
 import (
 	"bytes"
-	"time"
 
 	"github.com/quickfixgo/quickfix/internal"
 )
@@ -54,9 +53,17 @@ func (state inSession) FixMsgIn(session *session, msg *Message) sessionState {
 		if err := session.verify(msg); err != nil {
 			return state.processReject(session, msg, err)
 		}
+
+		switch {
+		case bytes.Equal(msgTypeHeartbeat, msgType):
+			session.handleHeartbeat(msg)
+		case bytes.Equal(msgTypeReject, msgType), bytes.Equal(msgTypeBusinessMessageReject, msgType):
+			text, _ := msg.Body.GetString(tagText)
+			session.publishEvent(EventReject, "Received reject: %v", text)
+		}
 	}
 
-	if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+	if err := session.incrNextTargetMsgSeqNum(msg); err != nil {
 		return handleStateError(session, err)
 	}
 
@@ -72,14 +79,10 @@ func (state inSession) Timeout(session *session, event internal.Event) (nextStat
 			return handleStateError(session, err)
 		}
 	case internal.PeerTimeout:
-		testReq := NewMessage()
-		testReq.Header.SetField(tagMsgType, FIXString("1"))
-		testReq.Body.SetField(tagTestReqID, FIXString("TEST"))
-		if err := session.send(testReq); err != nil {
+		if err := session.sendTestRequest(); err != nil {
 			return handleStateError(session, err)
 		}
-		session.log.OnEvent("Sent test request TEST")
-		session.peerTimer.Reset(time.Duration(float64(1.2) * float64(session.HeartBtInt)))
+		session.peerTimer.Reset(session.peerTimeout())
 		return pendingTimeout{state}
 	}
 
@@ -92,6 +95,11 @@ func (state inSession) handleLogout(session *session, msg *Message) (nextState s
 	}
 
 	if session.IsLoggedOn() {
+		if seqNum, ok := logoutAheadOfGap(session, msg); ok {
+			session.log.OnEvent("Received logout request")
+			return logoutState{}.awaitResend(session, seqNum, true)
+		}
+
 		session.log.OnEvent("Received logout request")
 		session.log.OnEvent("Sending logout response")
 
@@ -117,7 +125,7 @@ func (state inSession) handleLogout(session *session, msg *Message) (nextState s
 		return latentState{}
 	}
 
-	if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+	if err := session.incrNextTargetMsgSeqNum(msg); err != nil {
 		session.logError(err)
 	}
 
@@ -140,7 +148,7 @@ func (state inSession) handleTestRequest(session *session, msg *Message) (nextSt
 		}
 	}
 
-	if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+	if err := session.incrNextTargetMsgSeqNum(msg); err != nil {
 		return handleStateError(session, err)
 	}
 	return state
@@ -168,6 +176,9 @@ func (state inSession) handleSequenceReset(session *session, msg *Message) (next
 			if err := session.store.SetNextTargetMsgSeqNum(int(newSeqNo)); err != nil {
 				return handleStateError(session, err)
 			}
+			if !gapFillFlag {
+				session.publishEvent(EventSeqNumReset, "Received SequenceReset FROM: %v TO: %v", expectedSeqNum, newSeqNo)
+			}
 		case newSeqNo < expectedSeqNum:
 			// FIXME: to be compliant with legacy tests, do not include tag in reftagid? (11c_NewSeqNoLess).
 			if err := session.doReject(msg, valueIsIncorrectNoTag()); err != nil {
@@ -199,6 +210,7 @@ func (state inSession) handleResendRequest(session *session, msg *Message) (next
 	endSeqNo := int(endSeqNoField)
 
 	session.log.OnEventf("Received ResendRequest FROM: %d TO: %d", beginSeqNo, endSeqNo)
+	session.publishEvent(EventResend, "Received ResendRequest FROM: %d TO: %d", beginSeqNo, endSeqNo)
 	expectedSeqNum := session.store.NextSenderMsgSeqNum()
 
 	if (session.sessionID.BeginString >= BeginStringFIX42 && endSeqNo == 0) ||
@@ -219,7 +231,7 @@ func (state inSession) handleResendRequest(session *session, msg *Message) (next
 		return state
 	}
 
-	if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+	if err := session.incrNextTargetMsgSeqNum(msg); err != nil {
 		return handleStateError(session, err)
 	}
 	return state
@@ -231,10 +243,9 @@ func (state inSession) resendMessages(session *session, beginSeqNo, endSeqNo int
 	}
 
 	seqNum := beginSeqNo
-	nextSeqNum := seqNum
 	msg := NewMessage()
 	err := session.store.IterateMessages(beginSeqNo, endSeqNo, func(msgBytes []byte) error {
-		err := ParseMessageWithDataDictionary(msg, bytes.NewBuffer(msgBytes), session.transportDataDictionary, session.appDataDictionary)
+		err := parseMessage(msg, bytes.NewBuffer(msgBytes), session.transportDataDictionary, session.appDataDictionary, session.appDataDictionaries, false)
 		if err != nil {
 			session.log.OnEventf("Resend Msg Parse Error: %v, %v", err.Error(), bytes.NewBuffer(msgBytes).String())
 			return err // We cant continue with a message that cant be parsed correctly.
@@ -242,13 +253,11 @@ func (state inSession) resendMessages(session *session, beginSeqNo, endSeqNo int
 		msgType, _ := msg.Header.GetBytes(tagMsgType)
 		sentMessageSeqNum, _ := msg.Header.GetInt(tagMsgSeqNum)
 
-		if isAdminMessageType(msgType) {
-			nextSeqNum = sentMessageSeqNum + 1
+		if isAdminMessageType(msgType) || session.filterResend(msgType, msgBytes) == ResendGapFill {
 			return nil
 		}
 
 		if !session.resend(msg) {
-			nextSeqNum = sentMessageSeqNum + 1
 			return nil
 		}
 
@@ -263,7 +272,6 @@ func (state inSession) resendMessages(session *session, beginSeqNo, endSeqNo int
 		session.EnqueueBytesAndSend(msgBytes)
 
 		seqNum = sentMessageSeqNum + 1
-		nextSeqNum = seqNum
 		return nil
 	})
 	if err != nil {
@@ -271,8 +279,8 @@ func (state inSession) resendMessages(session *session, beginSeqNo, endSeqNo int
 		return err
 	}
 
-	if seqNum != nextSeqNum { // gapfill for catch-up
-		if err = state.generateSequenceReset(session, seqNum, nextSeqNum, inReplyTo); err != nil {
+	if seqNum <= endSeqNo { // gapfill for catch-up, and for transient messages that were not persisted
+		if err = state.generateSequenceReset(session, seqNum, endSeqNo+1, inReplyTo); err != nil {
 			return err
 		}
 	}
@@ -283,13 +291,10 @@ func (state inSession) resendMessages(session *session, beginSeqNo, endSeqNo int
 func (state inSession) processReject(session *session, msg *Message, rej MessageRejectError) sessionState {
 	switch TypedError := rej.(type) {
 	case targetTooHigh:
-
-		var nextState resendState
-		switch currentState := session.State.(type) {
-		case resendState:
-			// Assumes target too high reject already sent.
-			nextState = currentState
-		default:
+		// Assumes target too high reject already sent if a resend is outstanding, messages are stashed
+		// until the gap is filled.
+		nextState, ok := outstandingResend(session.State)
+		if !ok {
 			var err error
 			if nextState, err = session.doTargetTooHigh(TypedError); err != nil {
 				return handleStateError(session, err)
@@ -300,7 +305,7 @@ func (state inSession) processReject(session *session, msg *Message, rej Message
 			nextState.messageStash = make(map[int]*Message)
 		}
 
-		nextState.messageStash[TypedError.ReceivedTarget] = msg
+		nextState.messageStash[TypedError.ReceivedTarget] = msg.detach()
 
 		return nextState
 
@@ -328,7 +333,7 @@ func (state inSession) processReject(session *session, msg *Message, rej Message
 			return handleStateError(session, err)
 		}
 
-		if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+		if err := session.incrNextTargetMsgSeqNum(msg); err != nil {
 			return handleStateError(session, err)
 		}
 		return state
@@ -16,6 +16,7 @@
 package quickfix
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
@@ -83,6 +84,7 @@ func (s *InSessionTestSuite) TestLogoutResetOnLogout() {
 	s.MockApp.On("FromAdmin").Return(nil)
 	s.MockApp.On("ToAdmin")
 	s.MockApp.On("OnLogout")
+	s.MessageFactory.SetNextSeqNum(1)
 	s.session.fixMsgIn(s.session, s.Logout())
 
 	s.MockApp.AssertExpectations(s.T())
@@ -98,19 +100,34 @@ func (s *InSessionTestSuite) TestLogoutResetOnLogout() {
 
 func (s *InSessionTestSuite) TestLogoutTargetTooHigh() {
 	s.MessageFactory.seqNum = 5
+	s.session.LogoutTimeout = time.Minute
 
 	s.MockApp.On("FromAdmin").Return(nil)
 	s.MockApp.On("ToAdmin")
-	s.MockApp.On("OnLogout")
 	s.session.fixMsgIn(s.session, s.Logout())
 
+	// The gap before the Logout is requested before replying.
+	s.State(logoutState{})
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeResendRequest), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagBeginSeqNo, 1, s.MockApp.lastToAdmin.Body)
+	s.NextTargetMsgSeqNum(1)
+	s.NextSenderMsgSeqNum(2)
+
+	s.MockApp.On("OnLogout")
+	s.MessageFactory.SetNextSeqNum(1)
+	sequenceReset := s.SequenceReset(6)
+	sequenceReset.Body.SetField(tagGapFillFlag, FIXBoolean(true))
+	sequenceReset.Header.SetField(tagPossDupFlag, FIXBoolean(true))
+	sequenceReset.Header.SetField(tagOrigSendingTime, FIXUTCTimestamp{Time: time.Now()})
+	s.session.fixMsgIn(s.session, sequenceReset)
+
 	s.MockApp.AssertExpectations(s.T())
 	s.State(latentState{})
-
 	s.LastToAdminMessageSent()
 	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
-	s.NextTargetMsgSeqNum(1)
-	s.NextSenderMsgSeqNum(2)
+	s.NextTargetMsgSeqNum(7)
+	s.NextSenderMsgSeqNum(3)
 }
 
 func (s *InSessionTestSuite) TestTimeoutNeedHeartbeat() {
@@ -135,6 +152,34 @@ func (s *InSessionTestSuite) TestTimeoutPeerTimeout() {
 	s.NextSenderMsgSeqNum(2)
 }
 
+func (s *InSessionTestSuite) TestTestRequestLatency() {
+	s.MockApp.On("ToAdmin").Return(nil)
+	s.session.Timeout(s.session, internal.PeerTimeout)
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeTestRequest), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagTestReqID, "TEST1", s.MockApp.lastToAdmin.Body)
+	sentAt := s.session.stats.testRequestSentAt
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	heartbeat := s.Heartbeat()
+	heartbeat.Body.SetField(tagTestReqID, FIXString("TEST1"))
+	heartbeat.ReceiveTime = sentAt.Add(25 * time.Millisecond)
+	s.fixMsgIn(s.session, heartbeat)
+	s.State(inSession{})
+
+	stats := s.session.stats.snapshot()
+	s.Equal(1, stats.TestRequestsSent)
+	s.Equal(1, stats.TestRequestsAnswered)
+	s.Equal(25*time.Millisecond, stats.LastTestRequestLatency)
+
+	// A heartbeat with no outstanding TestRequest does not count.
+	heartbeat = s.Heartbeat()
+	heartbeat.Body.SetField(tagTestReqID, FIXString("TEST1"))
+	s.fixMsgIn(s.session, heartbeat)
+	s.Equal(1, s.session.stats.snapshot().TestRequestsAnswered)
+}
+
 func (s *InSessionTestSuite) TestDisconnected() {
 	s.MockApp.On("OnLogout").Return(nil)
 	s.session.Disconnected(s.session)
@@ -373,6 +418,172 @@ func (s *InSessionTestSuite) TestFIXMsgInResendRequestDoNotSendApp() {
 	s.State(inSession{})
 }
 
+type resendFilterApp struct {
+	*MockApp
+	filter func(msgType string, msg []byte) ResendAction
+}
+
+func (a resendFilterApp) FilterResend(msgType string, msg []byte) ResendAction {
+	return a.filter(msgType, msg)
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInResendRequestFilterGapFill() {
+	var filtered []string
+	s.session.application = resendFilterApp{MockApp: &s.MockApp, filter: func(msgType string, msg []byte) ResendAction {
+		filtered = append(filtered, msgType)
+		if bytes.Contains(msg, []byte("\x0134=1\x01")) {
+			return ResendGapFill
+		}
+		return ResendReplay
+	}}
+
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.session.send(s.NewOrderSingle()))
+	s.LastToAppMessageSent()
+	s.Require().Nil(s.session.send(s.NewOrderSingle()))
+	s.LastToAppMessageSent()
+	s.NextSenderMsgSeqNum(3)
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.session, s.ResendRequest(1))
+
+	s.Equal([]string{"D", "D"}, filtered)
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToAdmin", 1)
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 3)
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeSequenceReset), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagMsgSeqNum, 1, s.MockApp.lastToAdmin.Header)
+	s.FieldEquals(tagNewSeqNo, 2, s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagGapFillFlag, true, s.MockApp.lastToAdmin.Body)
+
+	s.LastToAppMessageSent()
+	s.MessageType("D", s.MockApp.lastToApp)
+	s.FieldEquals(tagMsgSeqNum, 2, s.MockApp.lastToApp.Header)
+	s.FieldEquals(tagPossDupFlag, true, s.MockApp.lastToApp.Header)
+
+	s.NextSenderMsgSeqNum(3)
+	s.State(inSession{})
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInResendRequestTransientGapFill() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.session.send(s.NewOrderSingle()))
+	s.LastToAppMessageSent()
+	transient := s.NewOrderSingle()
+	transient.SetTransient(true)
+	s.Require().Nil(s.session.send(transient))
+	s.LastToAppMessageSent()
+	s.NextSenderMsgSeqNum(3)
+	s.NoMessagePersisted(2)
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.session, s.ResendRequest(1))
+
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToAdmin", 1)
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 3)
+
+	s.LastToAppMessageSent()
+	s.MessageType("D", s.MockApp.lastToApp)
+	s.FieldEquals(tagMsgSeqNum, 1, s.MockApp.lastToApp.Header)
+	s.FieldEquals(tagPossDupFlag, true, s.MockApp.lastToApp.Header)
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeSequenceReset), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagMsgSeqNum, 2, s.MockApp.lastToAdmin.Header)
+	s.FieldEquals(tagNewSeqNo, 3, s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagGapFillFlag, true, s.MockApp.lastToAdmin.Body)
+
+	s.NextSenderMsgSeqNum(3)
+	s.State(inSession{})
+}
+
+func (s *InSessionTestSuite) TestTransientMsgTypesNotPersisted() {
+	s.session.TransientMsgTypes = map[string]bool{"D": true}
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.session.send(s.NewOrderSingle()))
+	s.LastToAppMessageSent()
+
+	s.NoMessagePersisted(1)
+	s.NextSenderMsgSeqNum(2)
+}
+
+type possDupApp struct {
+	*MockApp
+	possDups []*Message
+}
+
+func (a *possDupApp) FromAppPossDup(msg *Message, _ SessionID) MessageRejectError {
+	a.possDups = append(a.possDups, msg)
+	return nil
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInPossDupApplication() {
+	app := &possDupApp{MockApp: &s.MockApp}
+	s.session.application = app
+
+	s.MockApp.On("FromApp").Return(nil)
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+
+	possDup := s.NewOrderSingle()
+	possDup.Header.SetField(tagPossDupFlag, FIXBoolean(true))
+	possDup.Header.SetField(tagOrigSendingTime, FIXUTCTimestamp{Time: time.Now()})
+	s.fixMsgIn(s.session, possDup)
+
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 1)
+	s.Len(app.possDups, 1)
+	s.NextTargetMsgSeqNum(3)
+	s.State(inSession{})
+}
+
+type duplicateApp struct {
+	*MockApp
+	duplicates []*Message
+}
+
+func (a *duplicateApp) FromAppDuplicate(msg *Message, _ SessionID) MessageRejectError {
+	a.duplicates = append(a.duplicates, msg)
+	return nil
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInDuplicateFlag() {
+	app := &duplicateApp{MockApp: &s.MockApp}
+	s.session.application = app
+	s.session.duplicates = newDuplicateWindow(2, DuplicateFlag)
+	s.MockApp.On("FromApp").Return(nil)
+
+	for _, clOrdID := range []string{"1", "2", "1", "3", "1"} {
+		msg := s.NewOrderSingle()
+		msg.Body.SetField(tagClOrdID, FIXString(clOrdID))
+		s.fixMsgIn(s.session, msg)
+	}
+
+	// The second "1" is a duplicate, the third has left the window.
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 4)
+	s.Require().Len(app.duplicates, 1)
+	s.FieldEquals(tagClOrdID, "1", app.duplicates[0].Body)
+	s.NextTargetMsgSeqNum(6)
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInDuplicateSuppress() {
+	s.session.duplicates = newDuplicateWindow(10, DuplicateSuppress)
+	s.MockApp.On("FromApp").Return(nil)
+
+	for _, execID := range []string{"A", "A"} {
+		msg := s.NewOrderSingle()
+		msg.Body.SetField(tagClOrdID, FIXString("1"))
+		msg.Body.SetField(tagExecID, FIXString(execID))
+		s.fixMsgIn(s.session, msg)
+	}
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 2)
+	s.NextTargetMsgSeqNum(4)
+	s.State(inSession{})
+}
+
 func (s *InSessionTestSuite) TestFIXMsgInTargetTooLow() {
 	s.IncrNextTargetMsgSeqNum()
 
@@ -19,8 +19,11 @@ import (
 	"bufio"
 	"context"
 	"crypto/tls"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/proxy"
@@ -28,61 +31,279 @@ import (
 
 // Initiator initiates connections and processes messages for all sessions.
 type Initiator struct {
-	app             Application
-	settings        *Settings
-	sessionSettings map[SessionID]*SessionSettings
-	storeFactory    MessageStoreFactory
-	logFactory      LogFactory
-	globalLog       Log
-	stopChan        chan interface{}
-	wg              sync.WaitGroup
-	sessions        map[SessionID]*session
+	app                Application
+	settings           *Settings
+	sessionSettings    map[SessionID]*SessionSettings
+	storeFactory       MessageStoreFactory
+	logFactory         LogFactory
+	globalLog          Log
+	stopChan           chan interface{}
+	running            atomic.Bool
+	wg                 sync.WaitGroup
+	sessions           map[SessionID]*session
+	sessionsLock       sync.RWMutex
+	sessionStop        map[SessionID]chan interface{}
+	sessionDone        map[SessionID]chan struct{}
+	reloadLock         sync.Mutex
+	stateListener      SessionStateListener
+	tracer             Tracer
+	events             *EventBus
+	latencyObserver    LatencyObserver
+	dispatcher         *Dispatcher
+	validationPolicy   *ValidationPolicy
+	inboundMiddleware  []InboundMiddleware
+	outboundMiddleware []OutboundMiddleware
+	reconnectListener  ReconnectListener
+	clock              Clock
+	eventLoopShards    int
+	eventLoops         *eventLoopGroup
 	sessionFactory
 }
 
 // Start Initiator.
 func (i *Initiator) Start() (err error) {
+	i.sessionsLock.Lock()
+	defer i.sessionsLock.Unlock()
+
 	i.stopChan = make(chan interface{})
+	if i.dispatcher != nil {
+		i.dispatcher.start()
+	}
+	if i.eventLoopShards > 0 {
+		i.eventLoops = newEventLoopGroup(i.eventLoopShards, i.clock)
+	}
 
 	for sessionID, settings := range i.sessionSettings {
-		// TODO: move into session factory.
-		var tlsConfig *tls.Config
-		if tlsConfig, err = loadTLSConfig(settings); err != nil {
+		s := i.sessions[sessionID]
+		if err = s.reopenStore(i.storeFactory); err != nil {
 			return
 		}
-
-		var dialer proxy.ContextDialer
-		if dialer, err = loadDialerConfig(settings); err != nil {
+		s.stopOnce = sync.Once{}
+		if err = i.startSession(s, settings); err != nil {
 			return
 		}
+	}
+	i.running.Store(true)
+	return
+}
 
-		i.wg.Add(1)
-		go func(sessID SessionID) {
-			i.handleConnection(i.sessions[sessID], tlsConfig, dialer)
-			i.wg.Done()
-		}(sessionID)
+// startSession configures s with the handlers of the Initiator and connects it until it is stopped. It must be
+// called with sessionsLock held.
+func (i *Initiator) startSession(s *session, settings *SessionSettings) error {
+	tlsConfig, dialer, tcpOptions, err := loadConnectionSettings(settings)
+	if err != nil {
+		return err
+	}
+
+	s.stateListener = i.stateListener
+	s.tracer = i.tracer
+	s.events = i.events
+	s.latencyObserver = i.latencyObserver
+	s.dispatcher = i.dispatcher
+	s.validationPolicy.merge(i.validationPolicy)
+	s.useMiddleware(i.inboundMiddleware, i.outboundMiddleware)
+	if i.clock != nil {
+		s.clock = i.clock
 	}
+	s.loop = nil
+	if i.eventLoops != nil {
+		s.loop = i.eventLoops.loop(s.sessionID)
+	}
+
+	stop := make(chan interface{})
+	done := make(chan struct{})
+	i.sessionStop[s.sessionID] = stop
+	i.sessionDone[s.sessionID] = done
+
+	i.wg.Add(1)
+	go func() {
+		i.handleConnection(s, stop, tlsConfig, dialer, tcpOptions)
+		close(done)
+		i.wg.Done()
+	}()
+
+	return nil
+}
+
+// loadConnectionSettings loads the TLS, dialer and TCP settings an initiator session connects with.
+// TODO: move into session factory.
+func loadConnectionSettings(settings *SessionSettings) (tlsConfig *tls.Config, dialer proxy.ContextDialer, tcpOptions tcpOptions, err error) {
+	if tlsConfig, err = loadTLSConfig(settings); err != nil {
+		return
+	}
+
+	if dialer, err = loadDialerConfig(settings); err != nil {
+		return
+	}
+
+	tcpOptions, err = loadTCPOptions(settings)
 	return
 }
 
+// stopSession logs out s, waiting for it to stop if it was started, and unregisters it. If drain is true, messages
+// queued for sending are sent before the Logout. It must be called after s is removed from the sessions of the
+// Initiator, without sessionsLock held.
+func (i *Initiator) stopSession(s *session, drain bool) error {
+	return errors.Join(i.haltSession(s, drain), UnregisterSession(s.sessionID))
+}
+
+// haltSession logs out s and waits for it to stop if it was started, leaving it registered. It must be called
+// without sessionsLock held.
+func (i *Initiator) haltSession(s *session, drain bool) (err error) {
+	i.sessionsLock.Lock()
+	stop, started := i.sessionStop[s.sessionID]
+	done := i.sessionDone[s.sessionID]
+	delete(i.sessionStop, s.sessionID)
+	delete(i.sessionDone, s.sessionID)
+	i.sessionsLock.Unlock()
+
+	if started {
+		if drain {
+			err = s.logoutAndDrain(s.LogoutTimeout)
+		}
+		close(stop)
+		<-done
+	}
+	flushLog(s.log)
+
+	return err
+}
+
+// SetSessionStateListener sets a SessionStateListener to be notified of state transitions of all
+// sessions of the Initiator. It must be called before Start.
+func (i *Initiator) SetSessionStateListener(listener SessionStateListener) {
+	i.stateListener = listener
+}
+
+// SetTracer sets a Tracer to trace the messages of all sessions of the Initiator. It must be called before Start.
+func (i *Initiator) SetTracer(tracer Tracer) {
+	i.tracer = tracer
+}
+
+// SetEventBus sets an EventBus to publish the Events of all sessions of the Initiator. It must be called before Start.
+func (i *Initiator) SetEventBus(bus *EventBus) {
+	i.events = bus
+}
+
+// SetLatencyObserver sets a LatencyObserver to receive the latencies of application messages of all sessions of
+// the Initiator. It must be called before Start.
+func (i *Initiator) SetLatencyObserver(observer LatencyObserver) {
+	i.latencyObserver = observer
+}
+
+// SetDispatcher sets a Dispatcher to pass the application messages of all sessions of the Initiator to FromApp.
+// It must be called before Start.
+func (i *Initiator) SetDispatcher(dispatcher *Dispatcher) {
+	i.dispatcher = dispatcher
+}
+
+// SetValidationPolicy sets a ValidationPolicy disabling validation checks for all sessions of the Initiator, in
+// addition to the checks disabled by their settings. It must be called before Start.
+func (i *Initiator) SetValidationPolicy(policy *ValidationPolicy) {
+	i.validationPolicy = policy
+}
+
+// UseInbound adds InboundMiddleware intercepting the messages received by all sessions of the Initiator. Middleware
+// is called in the order it is added. It must be called before Start.
+func (i *Initiator) UseInbound(middleware ...InboundMiddleware) {
+	i.inboundMiddleware = append(i.inboundMiddleware, middleware...)
+}
+
+// UseOutbound adds OutboundMiddleware intercepting the messages sent by all sessions of the Initiator. Middleware
+// is called in the order it is added. It must be called before Start.
+func (i *Initiator) UseOutbound(middleware ...OutboundMiddleware) {
+	i.outboundMiddleware = append(i.outboundMiddleware, middleware...)
+}
+
+// SetReconnectListener sets a ReconnectListener to be alerted when a session of the Initiator
+// fails to connect MaxReconnectAttempts times in a row. It must be called before Start.
+func (i *Initiator) SetReconnectListener(listener ReconnectListener) {
+	i.reconnectListener = listener
+}
+
+// SetClock sets the Clock used by all sessions of the Initiator in place of the SystemClock.
+// It must be called before Start.
+func (i *Initiator) SetClock(clock Clock) {
+	i.clock = clock
+}
+
 // Stop Initiator.
 func (i *Initiator) Stop() {
+	_ = i.StopWithContext(context.Background())
+}
+
+// StopWithContext logs out the sessions of the Initiator and stops connecting them. Logged on sessions send the
+// messages already queued and a Logout, and are disconnected once the counterparty confirms it, or once their
+// LogoutTimeout passes or ctx is done. The logs of the sessions are then flushed and their MessageStores closed, to be
+// created again by the MessageStoreFactory if Start is called again.
+// Returns the errors of the sessions that did not complete the logout or failed to close their store, joined.
+func (i *Initiator) StopWithContext(ctx context.Context) error {
 	select {
 	case <-i.stopChan:
 		// Closed already.
-		return
+		return nil
 	default:
 	}
-	close(i.stopChan)
+	i.running.Store(false)
+
+	i.sessionsLock.RLock()
+	started := make([]*session, 0, len(i.sessionStop))
+	for sessionID := range i.sessionStop {
+		started = append(started, i.sessions[sessionID])
+	}
+	i.sessionsLock.RUnlock()
 
+	errs := stopSessions(started, func(s *session) error { return s.logoutAndDrainContext(ctx) })
+
+	close(i.stopChan)
 	i.wg.Wait()
+	if i.dispatcher != nil {
+		i.dispatcher.stop()
+	}
+	i.eventLoops.stop()
+
+	i.sessionsLock.RLock()
+	defer i.sessionsLock.RUnlock()
+
+	for sessionID, s := range i.sessions {
+		flushLog(s.log)
+		if err := s.closeStore(); err != nil {
+			errs = append(errs, fmt.Errorf("session %v: %w", sessionID, err))
+		}
+	}
+	flushLog(i.globalLog)
 
 	for sessionID := range i.sessionSettings {
-		err := UnregisterSession(sessionID)
-		if err != nil {
-			return
+		if err := UnregisterSession(sessionID); err != nil {
+			break
 		}
 	}
+
+	return errors.Join(errs...)
+}
+
+// Health returns the status of the Initiator and of its sessions.
+func (i *Initiator) Health() Health {
+	i.sessionsLock.RLock()
+	sessions := make([]*session, 0, len(i.sessions))
+	for _, s := range i.sessions {
+		sessions = append(sessions, s)
+	}
+	i.sessionsLock.RUnlock()
+
+	return newHealth(i.running.Load(), sessions)
+}
+
+// Compact compacts the message stores of the sessions of the Initiator that implement Compactor.
+func (i *Initiator) Compact() error {
+	i.sessionsLock.RLock()
+	defer i.sessionsLock.RUnlock()
+
+	sessions := make([]*session, 0, len(i.sessions))
+	for _, s := range i.sessions {
+		sessions = append(sessions, s)
+	}
+	return compactSessions(sessions)
 }
 
 // NewInitiator creates and initializes a new Initiator.
@@ -94,15 +315,27 @@ func NewInitiator(app Application, storeFactory MessageStoreFactory, appSettings
 		sessionSettings: appSettings.SessionSettings(),
 		logFactory:      logFactory,
 		sessions:        make(map[SessionID]*session),
+		sessionStop:     make(map[SessionID]chan interface{}),
+		sessionDone:     make(map[SessionID]chan struct{}),
 		sessionFactory:  sessionFactory{true},
 	}
 
 	var err error
+	if i.eventLoopShards, err = loadEventLoopShards(appSettings.GlobalSettings()); err != nil {
+		return i, err
+	}
+
 	i.globalLog, err = logFactory.Create()
 	if err != nil {
 		return i, err
 	}
 
+	redactor, err := buildRedactor(appSettings.GlobalSettings())
+	if err != nil {
+		return i, err
+	}
+	i.globalLog = newSessionLog(i.globalLog, SessionID{}, redactor)
+
 	for sessionID, s := range i.sessionSettings {
 		session, err := i.createSession(sessionID, storeFactory, s, logFactory, app)
 		if err != nil {
@@ -116,7 +349,7 @@ func NewInitiator(app Application, storeFactory MessageStoreFactory, appSettings
 }
 
 // waitForInSessionTime returns true if the session is in session, false if the handler should stop.
-func (i *Initiator) waitForInSessionTime(session *session) bool {
+func (i *Initiator) waitForInSessionTime(session *session, stop <-chan interface{}) bool {
 	inSessionTime := make(chan interface{})
 	go func() {
 		session.waitForInSessionTime()
@@ -127,49 +360,63 @@ func (i *Initiator) waitForInSessionTime(session *session) bool {
 	case <-inSessionTime:
 	case <-i.stopChan:
 		return false
+	case <-stop:
+		return false
 	}
 
 	return true
 }
 
 // waitForReconnectInterval returns true if a reconnect should be re-attempted, false if handler should stop.
-func (i *Initiator) waitForReconnectInterval(reconnectInterval time.Duration) bool {
+func (i *Initiator) waitForReconnectInterval(reconnectInterval time.Duration, stop <-chan interface{}) bool {
 	select {
 	case <-time.After(reconnectInterval):
 	case <-i.stopChan:
 		return false
+	case <-stop:
+		return false
 	}
 
 	return true
 }
 
-func (i *Initiator) handleConnection(session *session, tlsConfig *tls.Config, dialer proxy.ContextDialer) {
+// handleConnection connects session until the Initiator is stopped or stop is closed.
+func (i *Initiator) handleConnection(session *session, stop <-chan interface{}, tlsConfig *tls.Config, dialer proxy.ContextDialer, tcpOptions tcpOptions) {
 	var wg sync.WaitGroup
 	wg.Add(1)
-	go func() {
-		session.run()
-		wg.Done()
-	}()
+	if session.loop != nil {
+		session.loop.start(session, wg.Done)
+	} else {
+		go func() {
+			session.run()
+			wg.Done()
+		}()
+	}
 
 	defer func() {
 		session.stop()
 		wg.Wait()
 	}()
 
-	connectionAttempt := 0
+	endpoint := 0
+	failures := 0
 
 	for {
-		if !i.waitForInSessionTime(session) {
+		if !i.waitForInSessionTime(session, stop) {
 			return
 		}
 
 		ctx, cancel := context.WithCancel(context.Background())
 
 		// We start a goroutine in order to be able to cancel the dialer mid-connection
-		// on receiving a stop signal to stop the initiator.
+		// on receiving a stop signal to stop the initiator. It may outlive the handler, so it does not read
+		// stopChan, which is replaced when the Initiator is started again.
+		stopChan := i.stopChan
 		go func() {
 			select {
-			case <-i.stopChan:
+			case <-stopChan:
+				cancel()
+			case <-stop:
 				cancel()
 			case <-ctx.Done():
 				return
@@ -179,18 +426,28 @@ func (i *Initiator) handleConnection(session *session, tlsConfig *tls.Config, di
 		var disconnected chan interface{}
 		var msgIn chan fixIn
 		var msgOut chan []byte
+		var msgParser *parser
+		connected := false
+
+		address := session.SocketConnectAddress[endpoint]
+		if session.dial.Load() != nil {
+			session.log.OnEvent("Connecting with custom dialer")
+		} else {
+			session.log.OnEventf("Connecting to: %v", address)
+		}
 
-		address := session.SocketConnectAddress[connectionAttempt%len(session.SocketConnectAddress)]
-		session.log.OnEventf("Connecting to: %v", address)
-
-		netConn, err := dialer.DialContext(ctx, "tcp", address)
+		netConn, err := session.dialEndpoint(ctx, dialer, endpoint)
 		if err != nil {
 			session.log.OnEventf("Failed to connect: %v", err)
 			goto reconnect
+		} else if err = tcpOptions.apply(netConn); err != nil {
+			session.log.OnEventf("Failed to set socket options: %v", err)
+			_ = netConn.Close()
+			goto reconnect
 		} else if tlsConfig != nil {
 			// Unless InsecureSkipVerify is true, server name config is required for TLS
 			// to verify the received certificate
-			if !tlsConfig.InsecureSkipVerify && len(tlsConfig.ServerName) == 0 {
+			if !tlsConfig.InsecureSkipVerify && len(tlsConfig.ServerName) == 0 && !isLocalTransport(address) {
 				serverName := address
 				if c := strings.LastIndex(serverName, ":"); c > 0 {
 					serverName = serverName[:c]
@@ -207,15 +464,18 @@ func (i *Initiator) handleConnection(session *session, tlsConfig *tls.Config, di
 
 		msgIn = make(chan fixIn)
 		msgOut = make(chan []byte)
-		if err := session.connect(msgIn, msgOut); err != nil {
+		if err := session.connect(msgIn, msgOut, netConn.RemoteAddr()); err != nil {
 			session.log.OnEventf("Failed to initiate: %v", err)
 			goto reconnect
 		}
 
-		go readLoop(newParser(bufio.NewReader(netConn)), msgIn, session.log)
+		connected = true
+		msgParser = newParser(bufio.NewReader(netConn))
+		msgParser.setLimits(session.MaxMessageSize, session.MaxInboundBytesPerSecond)
+		go session.readLoop(msgParser, msgIn)
 		disconnected = make(chan interface{})
 		go func() {
-			writeLoop(netConn, msgOut, session.log)
+			writeLoop(netConn, msgOut, session.log, session.writeBatching(), session.messageWritten)
 			if err := netConn.Close(); err != nil {
 				session.log.OnEvent(err.Error())
 			}
@@ -230,14 +490,29 @@ func (i *Initiator) handleConnection(session *session, tlsConfig *tls.Config, di
 		case <-disconnected:
 		case <-i.stopChan:
 			return
+		case <-stop:
+			return
 		}
 
 	reconnect:
 		cancel()
 
-		connectionAttempt++
-		session.log.OnEventf("Reconnecting in %v", session.ReconnectInterval)
-		if !i.waitForReconnectInterval(session.ReconnectInterval) {
+		if !connected || !session.SocketConnectSticky {
+			endpoint = (endpoint + 1) % len(session.SocketConnectAddress)
+		}
+
+		if connected {
+			failures = 0
+		} else {
+			failures++
+			if failures == session.MaxReconnectAttempts && i.reconnectListener != nil {
+				i.reconnectListener.OnReconnectAttemptsExceeded(session.sessionID, failures)
+			}
+		}
+
+		reconnectInterval := session.reconnectDelay(failures)
+		session.log.OnEventf("Reconnecting in %v", reconnectInterval)
+		if !i.waitForReconnectInterval(reconnectInterval, stop) {
 			return
 		}
 	}
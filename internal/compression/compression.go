@@ -0,0 +1,114 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package compression compresses message bodies kept by the message stores.
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Algorithm is a message compression algorithm.
+type Algorithm string
+
+const (
+	// None stores messages as is.
+	None Algorithm = "none"
+	// Gzip compresses messages with gzip.
+	Gzip Algorithm = "gzip"
+	// Zstd compresses messages with zstandard.
+	Zstd Algorithm = "zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// Parse returns the Algorithm named by s, case insensitive.
+func Parse(s string) (Algorithm, error) {
+	switch alg := Algorithm(strings.ToLower(s)); alg {
+	case None, Gzip, Zstd:
+		return alg, nil
+	case "":
+		return None, nil
+	}
+	return None, fmt.Errorf("unknown compression algorithm: %s", s)
+}
+
+func initZstd() error {
+	zstdOnce.Do(func() {
+		if zstdEncoder, zstdErr = zstd.NewWriter(nil); zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	return zstdErr
+}
+
+// Compress compresses msg with the algorithm. None returns msg unchanged.
+func (alg Algorithm) Compress(msg []byte) ([]byte, error) {
+	switch alg {
+	case Gzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(msg); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case Zstd:
+		if err := initZstd(); err != nil {
+			return nil, err
+		}
+		return zstdEncoder.EncodeAll(msg, nil), nil
+	}
+	return msg, nil
+}
+
+// Decompress returns the message held in data. The algorithm is detected from the data itself,
+// so messages saved before compression was enabled, or with another algorithm, are still readable.
+// A FIX message never starts with a compression magic number, so uncompressed data is returned unchanged.
+func Decompress(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	case bytes.HasPrefix(data, zstdMagic):
+		if err := initZstd(); err != nil {
+			return nil, err
+		}
+		return zstdDecoder.DecodeAll(data, nil)
+	}
+	return data, nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		value    string
+		expected Algorithm
+	}{
+		{"", None},
+		{"none", None},
+		{"GZIP", Gzip},
+		{"zstd", Zstd},
+	}
+
+	for _, test := range tests {
+		alg, err := Parse(test.value)
+		require.Nil(t, err)
+		assert.Equal(t, test.expected, alg)
+	}
+
+	_, err := Parse("lz4")
+	assert.NotNil(t, err)
+}
+
+func TestCompressDecompress(t *testing.T) {
+	msg := bytes.Repeat([]byte("8=FIX.4.4\x019=12\x0135=W\x01"), 20)
+
+	for _, alg := range []Algorithm{None, Gzip, Zstd} {
+		data, err := alg.Compress(msg)
+		require.Nil(t, err)
+		if alg != None {
+			assert.Less(t, len(data), len(msg), alg)
+		}
+
+		decompressed, err := Decompress(data)
+		require.Nil(t, err)
+		assert.Equal(t, msg, decompressed, alg)
+	}
+}
@@ -5,18 +5,40 @@ import (
 	"time"
 )
 
+// Timer is the subset of a time.Timer used by the EventTimer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+type realTimer struct{ t *time.Timer }
+
+func (t realTimer) C() <-chan time.Time        { return t.t.C }
+func (t realTimer) Stop() bool                 { return t.t.Stop() }
+func (t realTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
 type EventTimer struct {
 	f     func()
-	timer *time.Timer
+	timer Timer
 	done  chan struct{}
 	wg    sync.WaitGroup
 	once  sync.Once
 }
 
 func NewEventTimer(task func()) *EventTimer {
+	return NewEventTimerWithTimer(task, realTimer{time.NewTimer(time.Second)})
+}
+
+// NewEventTimerWithTimer returns an EventTimer driven by timer, which is stopped until the first Reset.
+func NewEventTimerWithTimer(task func(), timer Timer) *EventTimer {
+	if !timer.Stop() {
+		<-timer.C()
+	}
+
 	t := &EventTimer{
 		f:     task,
-		timer: newStoppedTimer(),
+		timer: timer,
 		done:  make(chan struct{}),
 	}
 
@@ -27,7 +49,7 @@ func NewEventTimer(task func()) *EventTimer {
 		for {
 			select {
 
-			case <-t.timer.C:
+			case <-t.timer.C():
 				t.f()
 
 			case <-t.done:
@@ -41,6 +63,14 @@ func NewEventTimer(task func()) *EventTimer {
 	return t
 }
 
+// NewPassiveEventTimer returns an EventTimer driven by timer, which is stopped until the first Reset, without a
+// goroutine of its own, for timers running the task themselves, such as those of a TimerWheel.
+func NewPassiveEventTimer(timer Timer) *EventTimer {
+	timer.Stop()
+
+	return &EventTimer{timer: timer, done: make(chan struct{})}
+}
+
 func (t *EventTimer) Stop() {
 	if t == nil {
 		return
@@ -48,6 +78,9 @@ func (t *EventTimer) Stop() {
 
 	t.once.Do(func() {
 		close(t.done)
+		if t.f == nil {
+			t.timer.Stop()
+		}
 	})
 
 	t.wg.Wait()
@@ -60,11 +93,3 @@ func (t *EventTimer) Reset(timeout time.Duration) {
 
 	t.timer.Reset(timeout)
 }
-
-func newStoppedTimer() *time.Timer {
-	timer := time.NewTimer(time.Second)
-	if !timer.Stop() {
-		<-timer.C
-	}
-	return timer
-}
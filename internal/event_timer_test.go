@@ -17,6 +17,9 @@ package internal
 
 import (
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestEventTimer_Stop_idempotent(*testing.T) {
@@ -25,3 +28,22 @@ func TestEventTimer_Stop_idempotent(*testing.T) {
 	t.Stop()
 	t.Stop()
 }
+
+func TestEventTimer_Passive(t *testing.T) {
+	w := NewTimerWheel(time.Millisecond, 8)
+	calls := 0
+	timer := NewPassiveEventTimer(w.AfterFunc(time.Millisecond, func() { calls++ }))
+
+	w.Advance()
+	assert.Equal(t, 0, calls, "the timer is stopped until the first Reset")
+
+	timer.Reset(time.Millisecond)
+	w.Advance()
+	assert.Equal(t, 1, calls)
+
+	timer.Reset(time.Millisecond)
+	timer.Stop()
+	timer.Stop()
+	w.Advance()
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,19 @@
+package internal
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// GoroutineID returns the ID of the calling goroutine, as printed in its stack trace.
+func GoroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	b := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
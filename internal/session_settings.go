@@ -10,23 +10,39 @@ type SessionSettings struct {
 	ResetOnDisconnect            bool
 	HeartBtInt                   time.Duration
 	HeartBtIntOverride           bool
+	TestRequestDelayMultiplier   float64
 	SessionTime                  *TimeRange
 	InitiateLogon                bool
 	ResendRequestChunkSize       int
 	EnableLastMsgSeqNumProcessed bool
 	EnableNextExpectedMsgSeqNum  bool
+	SendLogoutBeforeDisconnect   bool
 	SkipCheckLatency             bool
 	MaxLatency                   time.Duration
 	DisableMessagePersist        bool
+	PreserveMessageFieldsOrder   bool
+	TransientMsgTypes            map[string]bool
 	ResetSeqTime                 TimeOfDay
 	EnableResetSeqTime           bool
+	ResetSeqTimeLocation         *time.Location
+	MaxMessageSize               int
+	MaxInboundBytesPerSecond     int
+	OutboundBatchMaxBytes        int
+	OutboundBatchFlushLatency    time.Duration
+	ApplicationCallbackTimeout   time.Duration
 
 	// Required on logon for FIX.T.1 messages.
 	DefaultApplVerID string
 
 	// Specific to initiators.
-	ReconnectInterval    time.Duration
-	LogoutTimeout        time.Duration
-	LogonTimeout         time.Duration
-	SocketConnectAddress []string
+	ReconnectInterval          time.Duration
+	ReconnectBackoffMultiplier float64
+	MaxReconnectInterval       time.Duration
+	ReconnectJitter            float64
+	MaxReconnectAttempts       int
+	LogoutTimeout              time.Duration
+	LogonTimeout               time.Duration
+	SocketConnectAddress       []string
+	SocketConnectTimeout       []time.Duration
+	SocketConnectSticky        bool
 }
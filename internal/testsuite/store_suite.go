@@ -232,3 +232,38 @@ func (s *StoreTestSuite) TestMessageStoreCreationTime() {
 	s.Require().True(s.MsgStore.CreationTime().After(t0))
 	s.Require().True(s.MsgStore.CreationTime().Before(t1))
 }
+
+func (s *StoreTestSuite) TestMessageStoreOutboundQueue() {
+	store, ok := s.MsgStore.(quickfix.OutboundQueueStore)
+	if !ok {
+		s.T().Skip("MessageStore is not an OutboundQueueStore")
+	}
+
+	// Given a store with queued messages
+	queuedAt := time.Unix(0, time.Now().UnixNano())
+	expected := []quickfix.QueuedMessage{
+		{QueuedAt: queuedAt, Msg: []byte("hello")},
+		{QueuedAt: queuedAt.Add(time.Second), Msg: []byte("\x01cruel\nworld")},
+	}
+	for _, msg := range expected {
+		s.Require().Nil(store.QueueMessage(msg))
+	}
+
+	// The queue is kept when the store is reset and refreshed
+	s.Require().Nil(store.Reset())
+	s.Require().Nil(store.Refresh())
+
+	queued, err := store.QueuedMessages()
+	s.Require().Nil(err)
+	s.Require().Len(queued, len(expected))
+	for i := range expected {
+		s.True(expected[i].QueuedAt.Equal(queued[i].QueuedAt))
+		s.Equal(expected[i].Msg, queued[i].Msg)
+	}
+
+	// And is empty once cleared
+	s.Require().Nil(store.ClearQueuedMessages())
+	queued, err = store.QueuedMessages()
+	s.Require().Nil(err)
+	s.Empty(queued)
+}
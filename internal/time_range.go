@@ -12,7 +12,10 @@ type TimeOfDay struct {
 	d                    time.Duration
 }
 
-const shortForm = "15:04:05"
+const (
+	shortForm = "15:04:05"
+	dateForm  = "2006-01-02"
+)
 
 // NewTimeOfDay returns a newly initialized TimeOfDay.
 func NewTimeOfDay(hour, minute, second int) TimeOfDay {
@@ -23,6 +26,11 @@ func NewTimeOfDay(hour, minute, second int) TimeOfDay {
 	return TimeOfDay{hour: hour, minute: minute, second: second, d: d}
 }
 
+// Clock returns the hour, minute and second of the TimeOfDay.
+func (t TimeOfDay) Clock() (hour, minute, second int) {
+	return t.hour, t.minute, t.second
+}
+
 // ParseTimeOfDay parses a TimeOfDay from a string in the format HH:MM:SS.
 func ParseTimeOfDay(str string) (TimeOfDay, error) {
 	t, err := time.Parse(shortForm, str)
@@ -33,12 +41,35 @@ func ParseTimeOfDay(str string) (TimeOfDay, error) {
 	return NewTimeOfDay(t.Clock()), nil
 }
 
+// Date is a calendar day, without a time of day.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// ParseDate parses a Date from a string in the format YYYY-MM-DD.
+func ParseDate(str string) (Date, error) {
+	t, err := time.Parse(dateForm, str)
+	if err != nil {
+		return Date{}, errors.Wrap(err, "date must be in the format YYYY-MM-DD")
+	}
+
+	return Date{Year: t.Year(), Month: t.Month(), Day: t.Day()}, nil
+}
+
+func dateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
 // TimeRange represents a time band in a given time zone.
 type TimeRange struct {
 	startTime, endTime TimeOfDay
 	weekdays           []time.Weekday
 	startDay, endDay   *time.Weekday
 	loc                *time.Location
+	holidays           map[Date]struct{}
 }
 
 // NewUTCTimeRange returns a time range in UTC.
@@ -78,6 +109,38 @@ func NewWeekRangeInLocation(startTime, endTime TimeOfDay, startDay, endDay time.
 	return r, nil
 }
 
+// AddHolidays marks dates in the time zone of the range on which the range is not active.
+func (r *TimeRange) AddHolidays(dates ...Date) {
+	if len(dates) == 0 {
+		return
+	}
+
+	if r.holidays == nil {
+		r.holidays = make(map[Date]struct{}, len(dates))
+	}
+
+	for _, date := range dates {
+		r.holidays[date] = struct{}{}
+	}
+}
+
+func (r *TimeRange) isHoliday(t time.Time) bool {
+	_, ok := r.holidays[dateOf(t.In(r.loc))]
+	return ok
+}
+
+// spansHoliday returns true if a holiday starts between t1 and t2.
+func (r *TimeRange) spansHoliday(t1, t2 time.Time) bool {
+	for date := range r.holidays {
+		start := time.Date(date.Year, date.Month, date.Day, 0, 0, 0, 0, r.loc)
+		if t1.Before(start) && !t2.Before(start) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (r *TimeRange) isInTimeRange(t time.Time) bool {
 	t = t.In(r.loc)
 	ts := NewTimeOfDay(t.Clock()).d
@@ -151,6 +214,10 @@ func (r *TimeRange) IsInRange(t time.Time) bool {
 		return true
 	}
 
+	if r.isHoliday(t) {
+		return false
+	}
+
 	if r.startDay != nil {
 		return r.isInWeekRange(t)
 	}
@@ -172,6 +239,10 @@ func (r *TimeRange) IsInSameRange(t1, t2 time.Time) bool {
 		t1, t2 = t2, t1
 	}
 
+	if r.spansHoliday(t1, t2) {
+		return false
+	}
+
 	t1 = t1.In(r.loc)
 	t1Time := NewTimeOfDay(t1.Clock())
 	dayOffset := 0
@@ -1017,3 +1017,45 @@ func TestTimeRangeIsInSameRangeWhenNil(t *testing.T) {
 	var tr *TimeRange
 	assert.True(t, tr.IsInSameRange(time1, time2), "always in same range if time range is nil")
 }
+
+func TestParseDate(t *testing.T) {
+	d, err := ParseDate("2024-12-25")
+	assert.Nil(t, err)
+	assert.Equal(t, Date{Year: 2024, Month: time.December, Day: 25}, d)
+
+	_, err = ParseDate("20241225")
+	assert.NotNil(t, err)
+}
+
+func TestTimeRangeIsInRangeWithHolidays(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+
+	// FX style week, Sunday 17:00 to Friday 17:00 New York time.
+	r, err := NewWeekRangeInLocation(NewTimeOfDay(17, 0, 0), NewTimeOfDay(17, 0, 0), time.Sunday, time.Friday, loc)
+	assert.Nil(t, err)
+	r.AddHolidays(Date{Year: 2024, Month: time.December, Day: 25})
+
+	assert.True(t, r.IsInRange(time.Date(2024, time.December, 24, 23, 59, 59, 0, loc)))
+	assert.False(t, r.IsInRange(time.Date(2024, time.December, 25, 0, 0, 0, 0, loc)))
+	assert.False(t, r.IsInRange(time.Date(2024, time.December, 25, 12, 0, 0, 0, loc)))
+	assert.True(t, r.IsInRange(time.Date(2024, time.December, 26, 0, 0, 0, 0, loc)))
+
+	// The holiday is in the time zone of the range.
+	assert.True(t, r.IsInRange(time.Date(2024, time.December, 25, 4, 0, 0, 0, time.UTC)))
+	assert.False(t, r.IsInRange(time.Date(2024, time.December, 25, 6, 0, 0, 0, time.UTC)))
+}
+
+func TestTimeRangeIsInSameRangeWithHolidays(t *testing.T) {
+	r, err := NewUTCWeekRange(NewTimeOfDay(17, 0, 0), NewTimeOfDay(17, 0, 0), time.Sunday, time.Friday)
+	assert.Nil(t, err)
+	r.AddHolidays(Date{Year: 2024, Month: time.December, Day: 25})
+
+	monday := time.Date(2024, time.December, 23, 10, 0, 0, 0, time.UTC)
+	tuesday := time.Date(2024, time.December, 24, 10, 0, 0, 0, time.UTC)
+	thursday := time.Date(2024, time.December, 26, 10, 0, 0, 0, time.UTC)
+
+	assert.True(t, r.IsInSameRange(monday, tuesday))
+	assert.False(t, r.IsInSameRange(tuesday, thursday), "a holiday ends the session")
+	assert.False(t, r.IsInSameRange(thursday, tuesday))
+}
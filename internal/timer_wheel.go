@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// TimerWheel is a hashed timing wheel running the functions of many timers on the goroutine advancing it, in place
+// of a runtime timer and goroutine each. Timers expire on the first tick of the wheel at or after their deadline.
+type TimerWheel struct {
+	tick time.Duration
+
+	mu    sync.Mutex
+	slots []list.List
+	pos   int
+}
+
+type wheelEntry struct {
+	wheel  *TimerWheel
+	f      func()
+	rounds int
+	slot   int
+	elem   *list.Element
+}
+
+// NewTimerWheel returns a TimerWheel of slots slots, which its owner advances every tick.
+func NewTimerWheel(tick time.Duration, slots int) *TimerWheel {
+	return &TimerWheel{
+		tick:  tick,
+		slots: make([]list.List, slots),
+	}
+}
+
+// Advance advances the wheel by a tick, calling the functions of the timers expiring, without the lock of the
+// wheel held.
+func (w *TimerWheel) Advance() {
+	w.mu.Lock()
+	w.pos = (w.pos + 1) % len(w.slots)
+	slot := &w.slots[w.pos]
+	var expired []func()
+	for elem := slot.Front(); elem != nil; {
+		next := elem.Next()
+		e := elem.Value.(*wheelEntry)
+		if e.rounds > 0 {
+			e.rounds--
+		} else {
+			e.remove()
+			expired = append(expired, e.f)
+		}
+		elem = next
+	}
+	w.mu.Unlock()
+
+	for _, f := range expired {
+		f()
+	}
+}
+
+// schedule adds e to the slot expiring after d. It must be called with the lock of the wheel held.
+func (e *wheelEntry) schedule(d time.Duration) {
+	w := e.wheel
+	ticks := int((d + w.tick - 1) / w.tick)
+	if ticks < 1 {
+		ticks = 1
+	}
+
+	e.slot = (w.pos + ticks) % len(w.slots)
+	e.rounds = (ticks - 1) / len(w.slots)
+	e.elem = w.slots[e.slot].PushBack(e)
+}
+
+// remove removes e from its slot, returning false if it is not scheduled. It must be called with the lock of the
+// wheel held.
+func (e *wheelEntry) remove() bool {
+	if e.elem == nil {
+		return false
+	}
+
+	e.wheel.slots[e.slot].Remove(e.elem)
+	e.elem = nil
+	return true
+}
+
+// WheelTimer is a single event timer of a TimerWheel, with the semantics of a time.AfterFunc timer.
+type WheelTimer struct {
+	e *wheelEntry
+}
+
+// AfterFunc returns a WheelTimer calling f on the goroutine advancing the wheel once d has elapsed.
+func (w *TimerWheel) AfterFunc(d time.Duration, f func()) *WheelTimer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	e := &wheelEntry{wheel: w, f: f}
+	e.schedule(d)
+	return &WheelTimer{e: e}
+}
+
+// C returns nil, as the timer calls its function instead of sending the time.
+func (t *WheelTimer) C() <-chan time.Time { return nil }
+
+// Stop prevents the timer from expiring, returning false if it already expired or was stopped.
+func (t *WheelTimer) Stop() bool {
+	t.e.wheel.mu.Lock()
+	defer t.e.wheel.mu.Unlock()
+
+	return t.e.remove()
+}
+
+// Reset changes the timer to expire once d has elapsed, returning true if it had been active.
+func (t *WheelTimer) Reset(d time.Duration) bool {
+	t.e.wheel.mu.Lock()
+	defer t.e.wheel.mu.Unlock()
+
+	active := t.e.remove()
+	t.e.schedule(d)
+	return active
+}
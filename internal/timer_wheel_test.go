@@ -0,0 +1,91 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func advance(w *TimerWheel, n int) {
+	for range n {
+		w.Advance()
+	}
+}
+
+func TestTimerWheel_AfterFunc(t *testing.T) {
+	var tests = []struct {
+		d     time.Duration
+		ticks int
+	}{
+		{0, 1},
+		{time.Millisecond, 1},
+		{10 * time.Millisecond, 1},
+		{15 * time.Millisecond, 2},
+		{40 * time.Millisecond, 4},
+		{50 * time.Millisecond, 5},
+		{120 * time.Millisecond, 12},
+	}
+
+	for _, test := range tests {
+		w := NewTimerWheel(10*time.Millisecond, 4)
+
+		calls := 0
+		timer := w.AfterFunc(test.d, func() { calls++ })
+		advance(w, test.ticks-1)
+		assert.Equal(t, 0, calls, "%v expired early", test.d)
+		advance(w, 1)
+		assert.Equal(t, 1, calls, "%v did not expire", test.d)
+		assert.False(t, timer.Stop())
+		advance(w, 8)
+		assert.Equal(t, 1, calls, "%v expired again", test.d)
+	}
+}
+
+func TestTimerWheel_StopAndReset(t *testing.T) {
+	w := NewTimerWheel(10*time.Millisecond, 4)
+
+	calls := 0
+	timer := w.AfterFunc(20*time.Millisecond, func() { calls++ })
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop())
+	advance(w, 8)
+	assert.Equal(t, 0, calls)
+
+	assert.False(t, timer.Reset(20*time.Millisecond))
+	advance(w, 1)
+	assert.True(t, timer.Reset(20*time.Millisecond))
+	advance(w, 1)
+	assert.Equal(t, 0, calls)
+	advance(w, 1)
+	assert.Equal(t, 1, calls)
+}
+
+func TestTimerWheel_ResetFromFunc(t *testing.T) {
+	w := NewTimerWheel(10*time.Millisecond, 4)
+
+	// Timers may be reset by their function, as it is called without the lock of the wheel held.
+	calls := 0
+	var timer *WheelTimer
+	timer = w.AfterFunc(10*time.Millisecond, func() {
+		calls++
+		timer.Reset(10 * time.Millisecond)
+	})
+	advance(w, 3)
+	assert.Equal(t, 3, calls)
+}
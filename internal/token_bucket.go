@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket limits the rate of events to a sustained rate per second, allowing bursts.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewTokenBucket returns a full TokenBucket allowing rate events per second and bursts of up to burst events,
+// measuring time with now.
+func NewTokenBucket(rate float64, burst int, now func() time.Time) *TokenBucket {
+	return &TokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), now: now}
+}
+
+func (b *TokenBucket) refill() time.Time {
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	return now
+}
+
+func (b *TokenBucket) delay() time.Duration {
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// TryTake takes a token if one is available. Otherwise it returns false and how long until one will be.
+func (b *TokenBucket) TryTake() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// Reserve takes a token, returning how long the caller must wait before using it.
+func (b *TokenBucket) Reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return b.delay()
+}
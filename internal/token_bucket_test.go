@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucketTryTake(t *testing.T) {
+	now := time.Now()
+	b := NewTokenBucket(10, 2, func() time.Time { return now })
+
+	ok, _ := b.TryTake()
+	assert.True(t, ok)
+	ok, _ = b.TryTake()
+	assert.True(t, ok)
+
+	ok, wait := b.TryTake()
+	assert.False(t, ok)
+	assert.Equal(t, 100*time.Millisecond, wait)
+
+	now = now.Add(100 * time.Millisecond)
+	ok, _ = b.TryTake()
+	assert.True(t, ok)
+
+	// Refills no further than the burst.
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		ok, _ = b.TryTake()
+		assert.True(t, ok)
+	}
+	ok, _ = b.TryTake()
+	assert.False(t, ok)
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	now := time.Now()
+	b := NewTokenBucket(10, 1, func() time.Time { return now })
+
+	assert.Equal(t, time.Duration(0), b.Reserve())
+	assert.Equal(t, 100*time.Millisecond, b.Reserve())
+	assert.Equal(t, 200*time.Millisecond, b.Reserve())
+
+	now = now.Add(200 * time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, b.Reserve())
+}
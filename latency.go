@@ -0,0 +1,178 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"math/bits"
+	"time"
+)
+
+const (
+	// coarseLatencyBits gives histogram buckets of powers of two.
+	coarseLatencyBits = 0
+
+	// highResolutionLatencyBits splits the powers of two into 32 buckets, for an error of about 3%.
+	highResolutionLatencyBits = 5
+)
+
+// LatencyStats are percentiles of the latencies of a session's application messages.
+// Percentiles are the upper bounds of histogram buckets of powers of two, or with HighResolutionLatency of 32
+// buckets per power of two.
+type LatencyStats struct {
+	Count int
+	Max   time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+// LatencyObserver receives the latencies of application messages, e.g. to export them as metrics.
+// It is called from the goroutines of sessions and should not block.
+type LatencyObserver interface {
+	// ObserveInboundLatency is called with the time from reading a message from the connection to FromApp returning.
+	ObserveInboundLatency(sessionID SessionID, latency time.Duration)
+
+	// ObserveOutboundLatency is called with the time from calling ToApp to writing the message to the connection.
+	ObserveOutboundLatency(sessionID SessionID, latency time.Duration)
+}
+
+// latencyHistogram counts latencies in nanoseconds in log-linear buckets, values below 1<<subBucketBits are exact.
+type latencyHistogram struct {
+	subBucketBits uint
+	counts        []int
+	count         int
+	max           time.Duration
+}
+
+func (h *latencyHistogram) bucket(ns uint64) int {
+	subBuckets := uint64(1) << h.subBucketBits
+	if ns < subBuckets {
+		return int(ns)
+	}
+
+	shift := uint(bits.Len64(ns)) - h.subBucketBits - 1
+	return int(subBuckets*uint64(shift+1) + (ns >> shift) - subBuckets)
+}
+
+// upperBound returns the largest latency counted in bucket.
+func (h *latencyHistogram) upperBound(bucket int) time.Duration {
+	subBuckets := 1 << h.subBucketBits
+	if bucket < subBuckets {
+		return time.Duration(bucket)
+	}
+
+	shift := uint(bucket/subBuckets - 1)
+	mantissa := uint64(bucket%subBuckets + subBuckets)
+	return time.Duration((mantissa+1)<<shift - 1)
+}
+
+func (h *latencyHistogram) record(latency time.Duration) {
+	if latency < 0 {
+		latency = 0
+	}
+
+	bucket := h.bucket(uint64(latency))
+	if bucket >= len(h.counts) {
+		h.counts = append(h.counts, make([]int, bucket-len(h.counts)+1)...)
+	}
+	h.counts[bucket]++
+	h.count++
+	if latency > h.max {
+		h.max = latency
+	}
+}
+
+func (h *latencyHistogram) percentile(p float64) time.Duration {
+	rank := int(p*float64(h.count) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+
+	seen := 0
+	for bucket, count := range h.counts {
+		seen += count
+		if seen >= rank {
+			if upperBound := h.upperBound(bucket); upperBound < h.max {
+				return upperBound
+			}
+			return h.max
+		}
+	}
+	return h.max
+}
+
+func (h *latencyHistogram) stats() LatencyStats {
+	if h.count == 0 {
+		return LatencyStats{}
+	}
+
+	return LatencyStats{
+		Count: h.count,
+		Max:   h.max,
+		P50:   h.percentile(0.50),
+		P95:   h.percentile(0.95),
+		P99:   h.percentile(0.99),
+	}
+}
+
+func (s *sessionStats) setHighResolutionLatency(highResolution bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subBucketBits := uint(coarseLatencyBits)
+	if highResolution {
+		subBucketBits = highResolutionLatencyBits
+	}
+	s.inboundLatency = latencyHistogram{subBucketBits: subBucketBits}
+	s.outboundLatency = latencyHistogram{subBucketBits: subBucketBits}
+}
+
+func (s *sessionStats) inboundLatencyObserved(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inboundLatency.record(latency)
+}
+
+func (s *sessionStats) outboundLatencyObserved(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.outboundLatency.record(latency)
+}
+
+// observeInboundLatency records the latency of an application message from its ReceiveTime to now.
+func (s *session) observeInboundLatency(msg *Message) {
+	if msg.ReceiveTime.IsZero() {
+		return
+	}
+
+	latency := time.Since(msg.ReceiveTime)
+	s.stats.inboundLatencyObserved(latency)
+	if s.latencyObserver != nil {
+		s.latencyObserver.ObserveInboundLatency(s.sessionID, latency)
+	}
+}
+
+// observeOutboundLatency records the latency of an application message from calling ToApp at toAppAt to now, by the
+// clock of the session.
+func (s *session) observeOutboundLatency(toAppAt time.Time) {
+	latency := s.clock.Now().Sub(toAppAt)
+	s.stats.outboundLatencyObserved(latency)
+	if s.latencyObserver != nil {
+		s.latencyObserver.ObserveOutboundLatency(s.sessionID, latency)
+	}
+}
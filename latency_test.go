@@ -0,0 +1,138 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestLatencyHistogramBuckets(t *testing.T) {
+	for _, subBucketBits := range []uint{coarseLatencyBits, highResolutionLatencyBits} {
+		h := latencyHistogram{subBucketBits: subBucketBits}
+		for _, ns := range []uint64{0, 1, 31, 32, 33, 64, 65, 1000, 123456789, 1 << 40} {
+			bucket := h.bucket(ns)
+			assert.GreaterOrEqual(t, uint64(h.upperBound(bucket)), ns, "%v in bucket %v", ns, bucket)
+			if bucket > 0 {
+				assert.Less(t, uint64(h.upperBound(bucket-1)), ns, "%v in bucket %v", ns, bucket)
+			}
+		}
+	}
+}
+
+func TestLatencyHistogramPercentiles(t *testing.T) {
+	coarse := latencyHistogram{subBucketBits: coarseLatencyBits}
+	highResolution := latencyHistogram{subBucketBits: highResolutionLatencyBits}
+	for i := 1; i <= 100; i++ {
+		coarse.record(time.Duration(i) * time.Millisecond)
+		highResolution.record(time.Duration(i) * time.Millisecond)
+	}
+
+	stats := highResolution.stats()
+	assert.Equal(t, 100, stats.Count)
+	assert.Equal(t, 100*time.Millisecond, stats.Max)
+	assert.InEpsilon(t, float64(50*time.Millisecond), float64(stats.P50), 0.04)
+	assert.InEpsilon(t, float64(95*time.Millisecond), float64(stats.P95), 0.04)
+	assert.InEpsilon(t, float64(99*time.Millisecond), float64(stats.P99), 0.04)
+
+	stats = coarse.stats()
+	assert.GreaterOrEqual(t, stats.P50, 50*time.Millisecond)
+	assert.Less(t, stats.P50, 100*time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, stats.P99)
+
+	assert.Equal(t, LatencyStats{}, (&latencyHistogram{}).stats())
+}
+
+type latencyRecorder struct {
+	inbound, outbound []time.Duration
+}
+
+func (r *latencyRecorder) ObserveInboundLatency(_ SessionID, latency time.Duration) {
+	r.inbound = append(r.inbound, latency)
+}
+
+func (r *latencyRecorder) ObserveOutboundLatency(_ SessionID, latency time.Duration) {
+	r.outbound = append(r.outbound, latency)
+}
+
+type LatencyTestSuite struct {
+	SessionSuiteRig
+	observer *latencyRecorder
+}
+
+func TestLatencyTestSuite(t *testing.T) {
+	suite.Run(t, new(LatencyTestSuite))
+}
+
+func (s *LatencyTestSuite) SetupTest() {
+	s.Init()
+	s.observer = &latencyRecorder{}
+	s.session.latencyObserver = s.observer
+	s.session.State = inSession{}
+}
+
+func (s *LatencyTestSuite) TestInbound() {
+	s.MockApp.On("FromApp").Return(nil)
+	msg := s.NewOrderSingle()
+	msg.ReceiveTime = time.Now().Add(-time.Millisecond)
+	s.fixMsgIn(s.session, msg)
+
+	s.Require().Len(s.observer.inbound, 1)
+	s.GreaterOrEqual(s.observer.inbound[0], time.Millisecond)
+	stats := s.session.stats.snapshot()
+	s.Equal(1, stats.InboundLatency.Count)
+	s.Equal(s.observer.inbound[0], stats.InboundLatency.Max)
+	s.Equal(0, stats.OutboundLatency.Count)
+}
+
+func (s *LatencyTestSuite) TestOutbound() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.Require().Nil(s.session.send(s.NewOrderSingle()))
+	s.Require().Nil(s.session.send(s.Heartbeat()))
+
+	s.Len(s.observer.outbound, 1)
+	s.Equal(1, s.session.stats.snapshot().OutboundLatency.Count)
+}
+
+func (s *LatencyTestSuite) TestOutboundQueued() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.session.State = latentState{}
+	s.Require().Nil(s.session.queueForSend(s.NewOrderSingle()))
+	s.Empty(s.observer.outbound)
+
+	s.session.State = inSession{}
+	s.session.sendQueued(true)
+	s.Len(s.observer.outbound, 1)
+}
+
+func (s *LatencyTestSuite) TestOutboundClock() {
+	clock := &fakeClock{now: time.Date(2024, time.March, 4, 9, 30, 0, 0, time.UTC)}
+	s.session.clock = clock
+	s.MockApp.On("ToApp").Return(nil)
+	s.session.State = latentState{}
+	s.Require().Nil(s.session.queueForSend(s.NewOrderSingle()))
+
+	clock.mu.Lock()
+	clock.now = clock.now.Add(5 * time.Second)
+	clock.mu.Unlock()
+	s.session.State = inSession{}
+	s.session.sendQueued(true)
+	s.Equal([]time.Duration{5 * time.Second}, s.observer.outbound)
+}
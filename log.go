@@ -15,6 +15,11 @@
 
 package quickfix
 
+import (
+	"bytes"
+	"fmt"
+)
+
 // Log is a generic interface for logging FIX messages and events.
 type Log interface {
 	// OnIncoming log incoming fix message.
@@ -38,3 +43,141 @@ type LogFactory interface {
 	// CreateSessionLog session specific log.
 	CreateSessionLog(sessionID SessionID) (Log, error)
 }
+
+// LogDirection is whether a LogEntry is an event, or an incoming or outgoing message.
+type LogDirection int
+
+const (
+	// LogEvent is an event, with the description in Text.
+	LogEvent LogDirection = iota
+
+	// LogIncoming is a message received from the counterparty.
+	LogIncoming
+
+	// LogOutgoing is a message sent to the counterparty.
+	LogOutgoing
+)
+
+func (d LogDirection) String() string {
+	switch d {
+	case LogIncoming:
+		return "incoming"
+	case LogOutgoing:
+		return "outgoing"
+	default:
+		return "event"
+	}
+}
+
+// LogEntry is a message or event with the fields recorded by a StructuredLog.
+type LogEntry struct {
+	// SessionID is the zero value for the global log.
+	SessionID SessionID
+	Direction LogDirection
+
+	// MsgType and MsgSeqNum are set for messages, when present in the message.
+	MsgType   string
+	MsgSeqNum int
+
+	// Message is the raw message for incoming and outgoing messages.
+	Message []byte
+
+	// Text is the description of an event.
+	Text string
+}
+
+// StructuredLog is a Log that records messages and events as fields rather than strings.
+// The session calls OnEntry instead of the other Log methods when its Log implements StructuredLog.
+type StructuredLog interface {
+	Log
+
+	// OnEntry log a message or event.
+	OnEntry(LogEntry)
+}
+
+// FlushLog is a Log that buffers messages and events, such as one writing from a background goroutine.
+// Initiators and acceptors flush their logs on Stop.
+type FlushLog interface {
+	Log
+
+	// Flush blocks until everything logged before it has been written.
+	Flush()
+}
+
+// flushLog flushes log if it is a FlushLog.
+func flushLog(log Log) {
+	switch l := log.(type) {
+	case sessionLog:
+		log = l.StructuredLog
+	case redactLog:
+		log = l.Log
+	}
+
+	if l, ok := log.(FlushLog); ok {
+		l.Flush()
+	}
+}
+
+// sessionLog converts the Log calls of a session to entries for a StructuredLog.
+type sessionLog struct {
+	StructuredLog
+	sessionID SessionID
+	redactor  redactor
+}
+
+// newSessionLog returns the Log a session, or the global log with the zero SessionID, writes to.
+func newSessionLog(log Log, sessionID SessionID, redactor redactor) Log {
+	if structured, ok := log.(StructuredLog); ok {
+		return sessionLog{structured, sessionID, redactor}
+	}
+
+	if redactor != nil {
+		return redactLog{log, redactor}
+	}
+
+	return log
+}
+
+func (l sessionLog) OnIncoming(msg []byte) {
+	l.onMessage(LogIncoming, msg)
+}
+
+func (l sessionLog) OnOutgoing(msg []byte) {
+	l.onMessage(LogOutgoing, msg)
+}
+
+func (l sessionLog) OnEvent(text string) {
+	l.OnEntry(LogEntry{SessionID: l.sessionID, Direction: LogEvent, Text: l.redactor.redactString(text)})
+}
+
+func (l sessionLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+func (l sessionLog) onMessage(direction LogDirection, msg []byte) {
+	entry := LogEntry{SessionID: l.sessionID, Direction: direction, Message: l.redactor.redact(msg)}
+	if msgType := rawFieldValue(msg, tagMsgType); msgType != nil {
+		entry.MsgType = string(msgType)
+	}
+	if seqNum := rawFieldValue(msg, tagMsgSeqNum); seqNum != nil {
+		entry.MsgSeqNum, _ = atoi(seqNum)
+	}
+
+	l.OnEntry(entry)
+}
+
+// rawFieldValue returns the value of the first tag field in msg, or nil if msg has no such field.
+func rawFieldValue(msg []byte, tag Tag) []byte {
+	prefix := []byte(fmt.Sprintf("\x01%d=", tag))
+	start := bytes.Index(msg, prefix)
+	if start == -1 {
+		return nil
+	}
+
+	value := msg[start+len(prefix):]
+	if end := bytes.IndexByte(value, '\x01'); end != -1 {
+		value = value[:end]
+	}
+
+	return value
+}
@@ -0,0 +1,168 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package async provides a quickfix.LogFactory that writes to another LogFactory's logs from a background goroutine,
+// so slow disks or remote log sinks do not add latency to message processing.
+package async
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// FullPolicy determines what happens to messages and events logged while a log's buffer is full.
+type FullPolicy int
+
+const (
+	// FullBlock blocks the session until the buffer has room.
+	FullBlock FullPolicy = iota
+
+	// FullDrop drops the message or event. The number dropped is logged as an event once the buffer has room.
+	FullDrop
+)
+
+type record struct {
+	entry      quickfix.LogEntry
+	structured bool
+
+	// flushed is closed once the records before it are written.
+	flushed chan struct{}
+}
+
+type asyncLog struct {
+	log     quickfix.Log
+	records chan record
+	policy  FullPolicy
+	dropped atomic.Int64
+}
+
+func newAsyncLog(log quickfix.Log, size int, policy FullPolicy) quickfix.Log {
+	l := &asyncLog{log: log, records: make(chan record, size), policy: policy}
+	go l.run()
+
+	if _, ok := log.(quickfix.StructuredLog); ok {
+		return asyncStructuredLog{l}
+	}
+	return l
+}
+
+func (l *asyncLog) run() {
+	for r := range l.records {
+		if r.flushed == nil {
+			l.write(r)
+		}
+
+		if dropped := l.dropped.Swap(0); dropped > 0 {
+			l.log.OnEventf("Log buffer full, dropped %d messages and events", dropped)
+		}
+
+		if r.flushed != nil {
+			close(r.flushed)
+		}
+	}
+}
+
+func (l *asyncLog) write(r record) {
+	if r.structured {
+		l.log.(quickfix.StructuredLog).OnEntry(r.entry)
+		return
+	}
+
+	switch r.entry.Direction {
+	case quickfix.LogIncoming:
+		l.log.OnIncoming(r.entry.Message)
+	case quickfix.LogOutgoing:
+		l.log.OnOutgoing(r.entry.Message)
+	default:
+		l.log.OnEvent(r.entry.Text)
+	}
+}
+
+func (l *asyncLog) enqueue(r record) {
+	// The caller may reuse the message once the call returns.
+	r.entry.Message = append([]byte(nil), r.entry.Message...)
+
+	if l.policy == FullBlock {
+		l.records <- r
+		return
+	}
+
+	select {
+	case l.records <- r:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+func (l *asyncLog) OnIncoming(s []byte) {
+	l.enqueue(record{entry: quickfix.LogEntry{Direction: quickfix.LogIncoming, Message: s}})
+}
+
+func (l *asyncLog) OnOutgoing(s []byte) {
+	l.enqueue(record{entry: quickfix.LogEntry{Direction: quickfix.LogOutgoing, Message: s}})
+}
+
+func (l *asyncLog) OnEvent(s string) {
+	l.enqueue(record{entry: quickfix.LogEntry{Direction: quickfix.LogEvent, Text: s}})
+}
+
+func (l *asyncLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+// Flush blocks until everything logged before it has been written.
+func (l *asyncLog) Flush() {
+	flushed := make(chan struct{})
+	l.records <- record{flushed: flushed}
+	<-flushed
+}
+
+type asyncStructuredLog struct {
+	*asyncLog
+}
+
+func (l asyncStructuredLog) OnEntry(entry quickfix.LogEntry) {
+	l.enqueue(record{entry: entry, structured: true})
+}
+
+type asyncLogFactory struct {
+	logFactory quickfix.LogFactory
+	size       int
+	policy     FullPolicy
+}
+
+func (f asyncLogFactory) Create() (quickfix.Log, error) {
+	log, err := f.logFactory.Create()
+	if err != nil {
+		return nil, err
+	}
+	return newAsyncLog(log, f.size, f.policy), nil
+}
+
+func (f asyncLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	log, err := f.logFactory.CreateSessionLog(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return newAsyncLog(log, f.size, f.policy), nil
+}
+
+// NewLogFactory creates an instance of LogFactory whose logs buffer up to size messages and events,
+// written to the logs of logFactory in the background. Initiators and acceptors flush the logs on Stop.
+func NewLogFactory(logFactory quickfix.LogFactory, size int, policy FullPolicy) quickfix.LogFactory {
+	return asyncLogFactory{logFactory: logFactory, size: size, policy: policy}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package async
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type recordingLog struct {
+	mu      sync.Mutex
+	entries []quickfix.LogEntry
+
+	// blocked, if set, is waited on before each write.
+	blocked chan struct{}
+}
+
+func (l *recordingLog) add(entry quickfix.LogEntry) {
+	if l.blocked != nil {
+		<-l.blocked
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+func (l *recordingLog) OnIncoming(s []byte) {
+	l.add(quickfix.LogEntry{Direction: quickfix.LogIncoming, Message: s})
+}
+
+func (l *recordingLog) OnOutgoing(s []byte) {
+	l.add(quickfix.LogEntry{Direction: quickfix.LogOutgoing, Message: s})
+}
+
+func (l *recordingLog) OnEvent(s string) {
+	l.add(quickfix.LogEntry{Direction: quickfix.LogEvent, Text: s})
+}
+
+func (l *recordingLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+type recordingLogFactory struct {
+	log quickfix.Log
+}
+
+func (f recordingLogFactory) Create() (quickfix.Log, error) { return f.log, nil }
+
+func (f recordingLogFactory) CreateSessionLog(quickfix.SessionID) (quickfix.Log, error) {
+	return f.log, nil
+}
+
+type structuredRecordingLog struct {
+	*recordingLog
+}
+
+func (l structuredRecordingLog) OnEntry(entry quickfix.LogEntry) {
+	l.add(entry)
+}
+
+func TestAsyncLog(t *testing.T) {
+	recorder := &recordingLog{}
+	log, err := NewLogFactory(recordingLogFactory{recorder}, 16, FullBlock).CreateSessionLog(quickfix.SessionID{})
+	require.Nil(t, err)
+	_, structured := log.(quickfix.StructuredLog)
+	assert.False(t, structured)
+
+	msg := []byte("8=FIX.4.2")
+	log.OnIncoming(msg)
+	msg[0] = 'X'
+	log.OnOutgoing([]byte("outgoing"))
+	log.OnEvent("event")
+
+	log.(quickfix.FlushLog).Flush()
+	assert.Equal(t, []quickfix.LogEntry{
+		{Direction: quickfix.LogIncoming, Message: []byte("8=FIX.4.2")},
+		{Direction: quickfix.LogOutgoing, Message: []byte("outgoing")},
+		{Direction: quickfix.LogEvent, Text: "event"},
+	}, recorder.entries)
+}
+
+func TestAsyncLog_Structured(t *testing.T) {
+	recorder := &recordingLog{}
+	log, err := NewLogFactory(recordingLogFactory{structuredRecordingLog{recorder}}, 16, FullBlock).Create()
+	require.Nil(t, err)
+
+	structured, ok := log.(quickfix.StructuredLog)
+	require.True(t, ok)
+	entry := quickfix.LogEntry{Direction: quickfix.LogIncoming, MsgType: "D", MsgSeqNum: 2, Message: []byte("8=FIX.4.2")}
+	structured.OnEntry(entry)
+
+	log.(quickfix.FlushLog).Flush()
+	assert.Equal(t, []quickfix.LogEntry{entry}, recorder.entries)
+}
+
+func TestAsyncLog_FullDrop(t *testing.T) {
+	recorder := &recordingLog{blocked: make(chan struct{})}
+	log, err := NewLogFactory(recordingLogFactory{recorder}, 1, FullDrop).Create()
+	require.Nil(t, err)
+
+	// The first event is taken by the writer, blocked writing it, and the second fills the buffer.
+	log.OnEvent("first")
+	for i := 0; i < 10; i++ {
+		log.OnEvent("dropped")
+	}
+	close(recorder.blocked)
+
+	log.(quickfix.FlushLog).Flush()
+	require.NotEmpty(t, recorder.entries)
+	assert.Equal(t, "first", recorder.entries[0].Text)
+	assert.Less(t, len(recorder.entries), 11)
+	assert.Regexp(t, "^Log buffer full, dropped (9|10) messages and events$", recorder.entries[1].Text)
+}
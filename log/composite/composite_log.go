@@ -43,7 +43,26 @@ func (l compositeLog) OnEvent(s string) {
 
 func (l compositeLog) OnEventf(format string, a ...interface{}) {
 	for _, log := range l.logs {
-		log.OnEventf(format, a)
+		log.OnEventf(format, a...)
+	}
+}
+
+// OnEntry forwards the entry to the structured logs, and as a message or event to the others.
+func (l compositeLog) OnEntry(entry quickfix.LogEntry) {
+	for _, log := range l.logs {
+		if structured, ok := log.(quickfix.StructuredLog); ok {
+			structured.OnEntry(entry)
+			continue
+		}
+
+		switch entry.Direction {
+		case quickfix.LogIncoming:
+			log.OnIncoming(entry.Message)
+		case quickfix.LogOutgoing:
+			log.OnOutgoing(entry.Message)
+		default:
+			log.OnEvent(entry.Text)
+		}
 	}
 }
 
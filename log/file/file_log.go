@@ -47,12 +47,15 @@ func (l fileLog) OnEventf(format string, v ...interface{}) {
 }
 
 type fileLogFactory struct {
-	globalLogPath   string
-	sessionLogPaths map[quickfix.SessionID]string
+	globalLogPath    string
+	globalRotation   rotation
+	sessionLogPaths  map[quickfix.SessionID]string
+	sessionRotations map[quickfix.SessionID]rotation
 }
 
 // NewLogFactory creates an instance of LogFactory that writes messages and events to file.
-// The location of global and session log files is configured via FileLogPath.
+// The location of global and session log files is configured via FileLogPath, and their rotation
+// via FileLogMaxSize, FileLogMaxAge, FileLogMaxBackups and FileLogCompress.
 func NewLogFactory(settings *quickfix.Settings) (quickfix.LogFactory, error) {
 	logFactory := fileLogFactory{}
 
@@ -61,7 +64,12 @@ func NewLogFactory(settings *quickfix.Settings) (quickfix.LogFactory, error) {
 		return logFactory, err
 	}
 
+	if logFactory.globalRotation, err = loadRotation(settings.GlobalSettings()); err != nil {
+		return logFactory, err
+	}
+
 	logFactory.sessionLogPaths = make(map[quickfix.SessionID]string)
+	logFactory.sessionRotations = make(map[quickfix.SessionID]rotation)
 
 	for sid, sessionSettings := range settings.SessionSettings() {
 		logPath, err := sessionSettings.Setting(config.FileLogPath)
@@ -69,12 +77,50 @@ func NewLogFactory(settings *quickfix.Settings) (quickfix.LogFactory, error) {
 			return logFactory, err
 		}
 		logFactory.sessionLogPaths[sid] = logPath
+
+		if logFactory.sessionRotations[sid], err = loadRotation(sessionSettings); err != nil {
+			return logFactory, err
+		}
 	}
 
 	return logFactory, nil
 }
 
-func newFileLog(prefix string, logPath string) (fileLog, error) {
+func loadRotation(settings *quickfix.SessionSettings) (r rotation, err error) {
+	if settings.HasSetting(config.FileLogMaxSize) {
+		var maxSize int
+		if maxSize, err = settings.IntSetting(config.FileLogMaxSize); err != nil {
+			return
+		}
+		r.maxSize = int64(maxSize)
+	}
+
+	if settings.HasSetting(config.FileLogMaxAge) {
+		if r.maxAge, err = settings.DurationSetting(config.FileLogMaxAge); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.FileLogMaxBackups) {
+		if r.maxBackups, err = settings.IntSetting(config.FileLogMaxBackups); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.FileLogCompress) {
+		if r.compress, err = settings.BoolSetting(config.FileLogCompress); err != nil {
+			return
+		}
+	}
+
+	if r.maxSize < 0 || r.maxAge < 0 || r.maxBackups < 0 {
+		err = fmt.Errorf("%v, %v and %v must not be negative", config.FileLogMaxSize, config.FileLogMaxAge, config.FileLogMaxBackups)
+	}
+
+	return
+}
+
+func newFileLog(prefix string, logPath string, r rotation) (fileLog, error) {
 	l := fileLog{}
 
 	eventLogName := path.Join(logPath, prefix+".event.current.log")
@@ -84,13 +130,12 @@ func newFileLog(prefix string, logPath string) (fileLog, error) {
 		return l, err
 	}
 
-	fileFlags := os.O_RDWR | os.O_CREATE | os.O_APPEND
-	eventFile, err := os.OpenFile(eventLogName, fileFlags, os.ModePerm)
+	eventFile, err := openRotatingFile(eventLogName, r)
 	if err != nil {
 		return l, err
 	}
 
-	messageFile, err := os.OpenFile(messageLogName, fileFlags, os.ModePerm)
+	messageFile, err := openRotatingFile(messageLogName, r)
 	if err != nil {
 		return l, err
 	}
@@ -103,7 +148,7 @@ func newFileLog(prefix string, logPath string) (fileLog, error) {
 }
 
 func (f fileLogFactory) Create() (quickfix.Log, error) {
-	return newFileLog("GLOBAL", f.globalLogPath)
+	return newFileLog("GLOBAL", f.globalLogPath, f.globalRotation)
 }
 
 func (f fileLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
@@ -114,5 +159,5 @@ func (f fileLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix
 	}
 
 	prefix := sessionIDFilenamePrefix(sessionID)
-	return newFileLog(prefix, logPath)
+	return newFileLog(prefix, logPath, f.sessionRotations[sessionID])
 }
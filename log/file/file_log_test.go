@@ -77,7 +77,7 @@ func newFileLogHelper(t *testing.T) *fileLogHelper {
 	prefix := "myprefix"
 	logPath := path.Join(os.TempDir(), fmt.Sprintf("TestLogStore-%d", os.Getpid()))
 
-	log, err := newFileLog(prefix, logPath)
+	log, err := newFileLog(prefix, logPath, rotation{})
 	if err != nil {
 		t.Error("Unexpected error", err)
 	}
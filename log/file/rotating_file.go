@@ -0,0 +1,225 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package file
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	currentLogSuffix    = ".current.log"
+	rotatedTimeFormat   = "20060102-150405.000000"
+	compressedLogSuffix = ".gz"
+)
+
+// rotation configures when a log file is rotated and how many rotated files are kept.
+// The zero value never rotates.
+type rotation struct {
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+}
+
+func (r rotation) enabled() bool {
+	return r.maxSize > 0 || r.maxAge > 0
+}
+
+// rotatingFile is a log file that is renamed with a timestamp and replaced by a new file
+// once it exceeds the size or age of its rotation.
+type rotatingFile struct {
+	mu       sync.Mutex
+	name     string
+	rotation rotation
+	file     *os.File
+	size     int64
+	opened   time.Time
+	rotated  time.Time
+
+	// Rotated files are compressed in the background.
+	compressing sync.WaitGroup
+}
+
+func openRotatingFile(name string, r rotation) (*rotatingFile, error) {
+	f := &rotatingFile{name: name, rotation: r}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.name, os.O_RDWR|os.O_CREATE|os.O_APPEND, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file, f.size, f.opened = file, info.Size(), time.Now()
+	return nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.shouldRotate(len(p)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) shouldRotate(n int) bool {
+	// A file is not rotated while empty, so a write larger than maxSize still goes somewhere.
+	if !f.rotation.enabled() || f.size == 0 {
+		return false
+	}
+
+	if f.rotation.maxSize > 0 && f.size+int64(n) > f.rotation.maxSize {
+		return true
+	}
+
+	return f.rotation.maxAge > 0 && time.Since(f.opened) >= f.rotation.maxAge
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	// Rotated files are named after distinct, increasing times so none is overwritten.
+	rotatedAt := time.Now().UTC()
+	if !rotatedAt.After(f.rotated) {
+		rotatedAt = f.rotated.Add(time.Microsecond)
+	}
+	f.rotated = rotatedAt
+
+	rotated := f.base() + "." + rotatedAt.Format(rotatedTimeFormat) + ".log"
+	if err := os.Rename(f.name, rotated); err != nil {
+		return err
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	if !f.rotation.compress {
+		f.removeExpired()
+		return nil
+	}
+
+	f.compressing.Add(1)
+	go func() {
+		defer f.compressing.Done()
+		if err := compressFile(rotated); err == nil {
+			f.mu.Lock()
+			f.removeExpired()
+			f.mu.Unlock()
+		}
+	}()
+
+	return nil
+}
+
+// base returns the file name without the current log suffix, which rotated files are named after.
+func (f *rotatingFile) base() string {
+	return strings.TrimSuffix(f.name, currentLogSuffix)
+}
+
+// removeExpired removes the oldest rotated files beyond maxBackups.
+func (f *rotatingFile) removeExpired() {
+	if f.rotation.maxBackups <= 0 {
+		return
+	}
+
+	matches, err := filepath.Glob(f.base() + ".*.log*")
+	if err != nil {
+		return
+	}
+
+	var backups []string
+	for _, match := range matches {
+		if match != f.name && !strings.HasSuffix(match, ".log"+compressedLogSuffix+".tmp") {
+			backups = append(backups, match)
+		}
+	}
+
+	// The timestamp in the name sorts rotated files oldest first.
+	sort.Strings(backups)
+	for len(backups) > f.rotation.maxBackups {
+		os.Remove(backups[0])
+		backups = backups[1:]
+	}
+}
+
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	err := f.file.Close()
+	f.mu.Unlock()
+
+	f.compressing.Wait()
+	return err
+}
+
+// compressFile replaces name with a gzip of it.
+func compressFile(name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := name + compressedLogSuffix + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err = io.Copy(gz, in); err == nil {
+		err = gz.Close()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, name+compressedLogSuffix)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Remove(name)
+}
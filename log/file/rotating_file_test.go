@@ -0,0 +1,127 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package file
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func rotatedFiles(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "test.messages.2*"))
+	require.Nil(t, err)
+	return matches
+}
+
+func TestRotatingFile_MaxSize(t *testing.T) {
+	dir := t.TempDir()
+	f, err := openRotatingFile(filepath.Join(dir, "test.messages.current.log"), rotation{maxSize: 10, maxBackups: 2})
+	require.Nil(t, err)
+	defer f.Close()
+
+	for _, line := range []string{"12345678\n", "abcdefgh\n", "ABCDEFGH\n", "last\n"} {
+		_, err = f.Write([]byte(line))
+		require.Nil(t, err)
+	}
+
+	current, err := os.ReadFile(filepath.Join(dir, "test.messages.current.log"))
+	require.Nil(t, err)
+	assert.Equal(t, "last\n", string(current))
+
+	rotated := rotatedFiles(t, dir)
+	require.Len(t, rotated, 2, "the oldest rotated file is removed")
+	oldest, err := os.ReadFile(rotated[0])
+	require.Nil(t, err)
+	assert.Equal(t, "abcdefgh\n", string(oldest))
+}
+
+func TestRotatingFile_MaxAge(t *testing.T) {
+	dir := t.TempDir()
+	f, err := openRotatingFile(filepath.Join(dir, "test.messages.current.log"), rotation{maxAge: time.Hour})
+	require.Nil(t, err)
+	defer f.Close()
+
+	_, err = f.Write([]byte("first\n"))
+	require.Nil(t, err)
+	_, err = f.Write([]byte("second\n"))
+	require.Nil(t, err)
+	assert.Empty(t, rotatedFiles(t, dir))
+
+	f.opened = f.opened.Add(-time.Hour)
+	_, err = f.Write([]byte("third\n"))
+	require.Nil(t, err)
+	assert.Len(t, rotatedFiles(t, dir), 1)
+}
+
+func TestRotatingFile_Compress(t *testing.T) {
+	dir := t.TempDir()
+	f, err := openRotatingFile(filepath.Join(dir, "test.messages.current.log"), rotation{maxSize: 5, compress: true})
+	require.Nil(t, err)
+
+	_, err = f.Write([]byte("first\n"))
+	require.Nil(t, err)
+	_, err = f.Write([]byte("second\n"))
+	require.Nil(t, err)
+	require.Nil(t, f.Close())
+
+	rotated := rotatedFiles(t, dir)
+	require.Len(t, rotated, 1)
+	assert.Equal(t, ".gz", filepath.Ext(rotated[0]))
+
+	gzFile, err := os.Open(rotated[0])
+	require.Nil(t, err)
+	defer gzFile.Close()
+	reader, err := gzip.NewReader(gzFile)
+	require.Nil(t, err)
+	content, err := io.ReadAll(reader)
+	require.Nil(t, err)
+	assert.Equal(t, "first\n", string(content))
+}
+
+func TestFileLog_RotationSettings(t *testing.T) {
+	sessionSettings := quickfix.NewSessionSettings()
+	sessionSettings.Set(config.BeginString, "FIX.4.2")
+	sessionSettings.Set(config.SenderCompID, "SENDER")
+	sessionSettings.Set(config.TargetCompID, "TARGET")
+	sessionSettings.Set(config.FileLogMaxSize, "1048576")
+	sessionSettings.Set(config.FileLogMaxBackups, "5")
+
+	settings := quickfix.NewSettings()
+	settings.GlobalSettings().Set(config.FileLogPath, t.TempDir())
+	settings.GlobalSettings().Set(config.FileLogMaxAge, "24h")
+	settings.GlobalSettings().Set(config.FileLogCompress, "Y")
+	sessionID, err := settings.AddSession(sessionSettings)
+	require.Nil(t, err)
+
+	factory, err := NewLogFactory(settings)
+	require.Nil(t, err)
+	assert.Equal(t, rotation{maxAge: 24 * time.Hour, compress: true}, factory.(fileLogFactory).globalRotation)
+	assert.Equal(t, rotation{maxSize: 1048576, maxAge: 24 * time.Hour, maxBackups: 5, compress: true}, factory.(fileLogFactory).sessionRotations[sessionID])
+
+	sessionSettings.Set(config.FileLogMaxBackups, "-1")
+	_, err = NewLogFactory(settings)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,170 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package journald provides a quickfix.LogFactory sending events, and optionally messages, to systemd-journald
+// over its native protocol, with the session and message as structured fields.
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+const (
+	defaultIdentifier = "quickfix"
+
+	priorityInfo = "6"
+)
+
+// socketPath is the native protocol socket of journald.
+var socketPath = "/run/systemd/journal/socket"
+
+type field struct {
+	name, value string
+}
+
+// encode returns the fields as a journal entry, values containing a newline are length prefixed.
+func encode(fields []field) []byte {
+	var entry bytes.Buffer
+	for _, f := range fields {
+		if !strings.Contains(f.value, "\n") {
+			entry.WriteString(f.name + "=" + f.value + "\n")
+			continue
+		}
+
+		entry.WriteString(f.name + "\n")
+		_ = binary.Write(&entry, binary.LittleEndian, uint64(len(f.value)))
+		entry.WriteString(f.value + "\n")
+	}
+	return entry.Bytes()
+}
+
+type journaldLog struct {
+	conn       *net.UnixConn
+	identifier string
+	sessionID  quickfix.SessionID
+	messages   bool
+}
+
+func (l journaldLog) OnIncoming(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogIncoming, Message: s})
+}
+
+func (l journaldLog) OnOutgoing(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogOutgoing, Message: s})
+}
+
+func (l journaldLog) OnEvent(s string) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogEvent, Text: s})
+}
+
+func (l journaldLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+// OnEntry sends the entry with the fields QUICKFIX_SESSION_ID, QUICKFIX_DIRECTION, QUICKFIX_MSG_TYPE and QUICKFIX_SEQ_NUM.
+// Messages are sent as MESSAGE with '|' for the SOH delimiters, and unchanged as QUICKFIX_RAW_MESSAGE.
+func (l journaldLog) OnEntry(entry quickfix.LogEntry) {
+	if entry.Direction != quickfix.LogEvent && !l.messages {
+		return
+	}
+
+	fields := []field{
+		{"PRIORITY", priorityInfo},
+		{"SYSLOG_IDENTIFIER", l.identifier},
+		{"QUICKFIX_DIRECTION", entry.Direction.String()},
+	}
+	if entry.SessionID != (quickfix.SessionID{}) {
+		fields = append(fields, field{"QUICKFIX_SESSION_ID", entry.SessionID.String()})
+	}
+
+	if entry.Direction == quickfix.LogEvent {
+		fields = append(fields, field{"MESSAGE", entry.Text})
+	} else {
+		fields = append(fields,
+			field{"MESSAGE", string(bytes.ReplaceAll(entry.Message, []byte{1}, []byte("|")))},
+			field{"QUICKFIX_RAW_MESSAGE", string(entry.Message)},
+		)
+		if entry.MsgType != "" {
+			fields = append(fields, field{"QUICKFIX_MSG_TYPE", entry.MsgType})
+		}
+		if entry.MsgSeqNum != 0 {
+			fields = append(fields, field{"QUICKFIX_SEQ_NUM", strconv.Itoa(entry.MsgSeqNum)})
+		}
+	}
+
+	_, _ = l.conn.Write(encode(fields))
+}
+
+type journaldLogFactory struct {
+	settings *quickfix.Settings
+	conn     *net.UnixConn
+}
+
+// NewLogFactory returns a LogFactory sending to the local systemd-journald, it errors if journald is not running.
+func NewLogFactory(settings *quickfix.Settings) (quickfix.LogFactory, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+
+	return journaldLogFactory{settings: settings, conn: conn}, nil
+}
+
+func (f journaldLogFactory) Create() (quickfix.Log, error) {
+	return f.newLog(quickfix.SessionID{}, f.settings.GlobalSettings())
+}
+
+func (f journaldLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	globalSettings := f.settings.GlobalSettings()
+	dynamicSessions, _ := globalSettings.BoolSetting(config.DynamicSessions)
+
+	sessionSettings, ok := f.settings.SessionSettings()[sessionID]
+	if !ok {
+		if dynamicSessions {
+			sessionSettings = globalSettings
+		} else {
+			return nil, fmt.Errorf("unknown session: %v", sessionID)
+		}
+	}
+
+	return f.newLog(sessionID, sessionSettings)
+}
+
+func (f journaldLogFactory) newLog(sessionID quickfix.SessionID, settings *quickfix.SessionSettings) (quickfix.Log, error) {
+	l := journaldLog{conn: f.conn, identifier: defaultIdentifier, sessionID: sessionID}
+
+	var err error
+	if settings.HasSetting(config.JournaldLogIdentifier) {
+		if l.identifier, err = settings.Setting(config.JournaldLogIdentifier); err != nil {
+			return nil, err
+		}
+	}
+
+	if settings.HasSetting(config.JournaldLogMessages) {
+		if l.messages, err = settings.BoolSetting(config.JournaldLogMessages); err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestEncode(t *testing.T) {
+	entry := encode([]field{{"MESSAGE", "Logon"}, {"TEXT", "a\nb"}})
+
+	var expected bytes.Buffer
+	expected.WriteString("MESSAGE=Logon\nTEXT\n")
+	_ = binary.Write(&expected, binary.LittleEndian, uint64(3))
+	expected.WriteString("a\nb\n")
+	assert.Equal(t, expected.Bytes(), entry)
+}
+
+func listenJournal(t *testing.T) *net.UnixConn {
+	socketPath = filepath.Join(t.TempDir(), "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	require.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestJournaldLog(t *testing.T) {
+	conn := listenJournal(t)
+
+	settings, err := quickfix.ParseSettings(strings.NewReader(`
+[DEFAULT]
+JournaldLogIdentifier=fix-engine
+
+[SESSION]
+BeginString=FIX.4.2
+SenderCompID=SENDER
+TargetCompID=TARGET
+JournaldLogMessages=Y
+`))
+	require.Nil(t, err)
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	factory, err := NewLogFactory(settings)
+	require.Nil(t, err)
+
+	globalLog, err := factory.Create()
+	require.Nil(t, err)
+	globalLog.OnIncoming([]byte("8=FIX.4.2\x01"))
+	globalLog.OnEvent("Listening")
+
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	require.Nil(t, err)
+	assert.Equal(t, "PRIORITY=6\nSYSLOG_IDENTIFIER=fix-engine\nQUICKFIX_DIRECTION=event\nMESSAGE=Listening\n", string(buf[:n]))
+
+	log, err := factory.CreateSessionLog(sessionID)
+	require.Nil(t, err)
+	log.(quickfix.StructuredLog).OnEntry(quickfix.LogEntry{SessionID: sessionID, Direction: quickfix.LogOutgoing, MsgType: "A", MsgSeqNum: 1, Message: []byte("8=FIX.4.2\x0135=A\x01")})
+
+	n, err = conn.Read(buf)
+	require.Nil(t, err)
+	entry := string(buf[:n])
+	assert.Contains(t, entry, "QUICKFIX_SESSION_ID=FIX.4.2:SENDER->TARGET\n")
+	assert.Contains(t, entry, "QUICKFIX_DIRECTION=outgoing\n")
+	assert.Contains(t, entry, "MESSAGE=8=FIX.4.2|35=A|\n")
+	assert.Contains(t, entry, "QUICKFIX_RAW_MESSAGE=8=FIX.4.2\x0135=A\x01\n")
+	assert.Contains(t, entry, "QUICKFIX_MSG_TYPE=A\n")
+	assert.Contains(t, entry, "QUICKFIX_SEQ_NUM=1\n")
+}
+
+func TestJournaldNotRunning(t *testing.T) {
+	socketPath = filepath.Join(t.TempDir(), "socket")
+
+	_, err := NewLogFactory(quickfix.NewSettings())
+	assert.NotNil(t, err)
+}
+
+func TestJournaldLogUnknownSession(t *testing.T) {
+	listenJournal(t)
+	factory, err := NewLogFactory(quickfix.NewSettings())
+	require.Nil(t, err)
+
+	_, err = factory.CreateSessionLog(quickfix.SessionID{BeginString: "FIX.4.2"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,280 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package kafka provides a quickfix.LogFactory publishing messages and events to Kafka topics.
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+const (
+	defaultMessageTopic = "quickfix.messages"
+	defaultEventTopic   = "quickfix.events"
+	defaultBufferSize   = 10000
+	defaultBatchSize    = 100
+	defaultBatchTimeout = 10 * time.Millisecond
+)
+
+// ErrorHandler is called with the messages that could not be delivered to Kafka.
+type ErrorHandler func(messages []kafka.Message, err error)
+
+// producer is the part of kafka.Writer used to publish.
+type producer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+type record struct {
+	message kafka.Message
+
+	// flushed is closed once the records before it are delivered.
+	flushed chan struct{}
+}
+
+// publisher delivers the records of all the logs of a factory from one goroutine, in batches.
+type publisher struct {
+	producer  producer
+	records   chan record
+	batchSize int
+	onError   ErrorHandler
+}
+
+func (p *publisher) run() {
+	batch := make([]kafka.Message, 0, p.batchSize)
+	for r := range p.records {
+		var flushed []chan struct{}
+		for {
+			if r.flushed != nil {
+				flushed = append(flushed, r.flushed)
+			} else {
+				batch = append(batch, r.message)
+			}
+
+			if len(batch) == p.batchSize || len(p.records) == 0 {
+				break
+			}
+			r = <-p.records
+		}
+
+		if len(batch) > 0 {
+			if err := p.producer.WriteMessages(context.Background(), batch...); err != nil {
+				p.onError(batch, err)
+			}
+			batch = make([]kafka.Message, 0, p.batchSize)
+		}
+
+		for _, f := range flushed {
+			close(f)
+		}
+	}
+}
+
+type kafkaLog struct {
+	publisher    *publisher
+	sessionID    quickfix.SessionID
+	key          []byte
+	messageTopic string
+	eventTopic   string
+}
+
+func (l kafkaLog) OnIncoming(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogIncoming, Message: s})
+}
+
+func (l kafkaLog) OnOutgoing(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogOutgoing, Message: s})
+}
+
+func (l kafkaLog) OnEvent(s string) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogEvent, Text: s})
+}
+
+func (l kafkaLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+// OnEntry publishes the entry keyed by the SessionID, with the direction, message type and sequence number as headers.
+func (l kafkaLog) OnEntry(entry quickfix.LogEntry) {
+	message := kafka.Message{
+		Key:     l.key,
+		Time:    time.Now(),
+		Headers: []kafka.Header{{Key: "direction", Value: []byte(entry.Direction.String())}},
+	}
+
+	if entry.Direction == quickfix.LogEvent {
+		message.Topic = l.eventTopic
+		message.Value = []byte(entry.Text)
+	} else {
+		message.Topic = l.messageTopic
+		// The caller may reuse the message once the call returns.
+		message.Value = append([]byte(nil), entry.Message...)
+		if entry.MsgType != "" {
+			message.Headers = append(message.Headers, kafka.Header{Key: "msg_type", Value: []byte(entry.MsgType)})
+		}
+		if entry.MsgSeqNum != 0 {
+			message.Headers = append(message.Headers, kafka.Header{Key: "seq_num", Value: []byte(strconv.Itoa(entry.MsgSeqNum))})
+		}
+	}
+
+	l.publisher.records <- record{message: message}
+}
+
+// Flush blocks until everything logged before it has been delivered, or failed.
+func (l kafkaLog) Flush() {
+	flushed := make(chan struct{})
+	l.publisher.records <- record{flushed: flushed}
+	<-flushed
+}
+
+type kafkaLogFactory struct {
+	settings  *quickfix.Settings
+	publisher *publisher
+}
+
+// NewLogFactory returns a LogFactory publishing messages and events to the Kafka brokers of KafkaLogBrokers,
+// on the topics of KafkaLogMessageTopic and KafkaLogEventTopic. Messages that cannot be delivered are passed
+// to onError, or logged with the standard logger if onError is nil.
+func NewLogFactory(settings *quickfix.Settings, onError ErrorHandler) (quickfix.LogFactory, error) {
+	globalSettings := settings.GlobalSettings()
+
+	brokers, err := globalSettings.Setting(config.KafkaLogBrokers)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(strings.Split(brokers, ",")...),
+		Balancer:     &kafka.Hash{},
+		BatchSize:    defaultBatchSize,
+		BatchTimeout: defaultBatchTimeout,
+	}
+
+	if globalSettings.HasSetting(config.KafkaLogBatchSize) {
+		if writer.BatchSize, err = globalSettings.IntSetting(config.KafkaLogBatchSize); err != nil {
+			return nil, err
+		}
+	}
+
+	if globalSettings.HasSetting(config.KafkaLogBatchTimeout) {
+		if writer.BatchTimeout, err = globalSettings.DurationSetting(config.KafkaLogBatchTimeout); err != nil {
+			return nil, err
+		}
+	}
+
+	bufferSize := defaultBufferSize
+	if globalSettings.HasSetting(config.KafkaLogBufferSize) {
+		if bufferSize, err = globalSettings.IntSetting(config.KafkaLogBufferSize); err != nil {
+			return nil, err
+		}
+	}
+
+	if writer.BatchSize <= 0 || bufferSize <= 0 {
+		return nil, fmt.Errorf("%v and %v must be positive", config.KafkaLogBatchSize, config.KafkaLogBufferSize)
+	}
+
+	return newLogFactory(settings, writer, writer.BatchSize, bufferSize, onError), nil
+}
+
+func newLogFactory(settings *quickfix.Settings, producer producer, batchSize, bufferSize int, onError ErrorHandler) kafkaLogFactory {
+	if onError == nil {
+		onError = func(messages []kafka.Message, err error) {
+			log.Printf("failed to deliver %d messages to kafka: %v", len(messages), err)
+		}
+	}
+
+	p := &publisher{
+		producer:  producer,
+		records:   make(chan record, bufferSize),
+		batchSize: batchSize,
+		onError:   onError,
+	}
+	go p.run()
+
+	return kafkaLogFactory{settings: settings, publisher: p}
+}
+
+func (f kafkaLogFactory) Create() (quickfix.Log, error) {
+	return f.newLog(quickfix.SessionID{}, f.settings.GlobalSettings())
+}
+
+func (f kafkaLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	globalSettings := f.settings.GlobalSettings()
+	dynamicSessions, _ := globalSettings.BoolSetting(config.DynamicSessions)
+
+	sessionSettings, ok := f.settings.SessionSettings()[sessionID]
+	if !ok {
+		if dynamicSessions {
+			sessionSettings = globalSettings
+		} else {
+			return nil, fmt.Errorf("unknown session: %v", sessionID)
+		}
+	}
+
+	return f.newLog(sessionID, sessionSettings)
+}
+
+func (f kafkaLogFactory) newLog(sessionID quickfix.SessionID, settings *quickfix.SessionSettings) (quickfix.Log, error) {
+	l := kafkaLog{
+		publisher:    f.publisher,
+		sessionID:    sessionID,
+		messageTopic: defaultMessageTopic,
+		eventTopic:   defaultEventTopic,
+	}
+
+	if sessionID != (quickfix.SessionID{}) {
+		l.key = []byte(sessionID.String())
+	}
+
+	var err error
+	if settings.HasSetting(config.KafkaLogMessageTopic) {
+		if l.messageTopic, err = settings.Setting(config.KafkaLogMessageTopic); err != nil {
+			return nil, err
+		}
+	}
+
+	if settings.HasSetting(config.KafkaLogEventTopic) {
+		if l.eventTopic, err = settings.Setting(config.KafkaLogEventTopic); err != nil {
+			return nil, err
+		}
+	}
+
+	l.messageTopic = expandTopic(l.messageTopic, sessionID)
+	l.eventTopic = expandTopic(l.eventTopic, sessionID)
+	return l, nil
+}
+
+// expandTopic replaces the placeholders of a topic pattern with the fields of sessionID.
+func expandTopic(pattern string, sessionID quickfix.SessionID) string {
+	return strings.NewReplacer(
+		"{BeginString}", sessionID.BeginString,
+		"{SenderCompID}", sessionID.SenderCompID,
+		"{SenderSubID}", sessionID.SenderSubID,
+		"{SenderLocationID}", sessionID.SenderLocationID,
+		"{TargetCompID}", sessionID.TargetCompID,
+		"{TargetSubID}", sessionID.TargetSubID,
+		"{TargetLocationID}", sessionID.TargetLocationID,
+		"{Qualifier}", sessionID.Qualifier,
+	).Replace(pattern)
+}
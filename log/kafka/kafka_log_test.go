@@ -0,0 +1,136 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+type fakeProducer struct {
+	mu       sync.Mutex
+	messages []kafka.Message
+	err      error
+}
+
+func (p *fakeProducer) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, msgs...)
+	return nil
+}
+
+func newTestSettings(t *testing.T) (*quickfix.Settings, quickfix.SessionID) {
+	settings, err := quickfix.ParseSettings(strings.NewReader(`
+[DEFAULT]
+KafkaLogBrokers=localhost:9092
+KafkaLogEventTopic=events
+
+[SESSION]
+BeginString=FIX.4.2
+SenderCompID=SENDER
+TargetCompID=TARGET
+KafkaLogMessageTopic=fix.{SenderCompID}.{TargetCompID}
+`))
+	require.Nil(t, err)
+	return settings, quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+}
+
+func TestKafkaLog(t *testing.T) {
+	settings, sessionID := newTestSettings(t)
+	producer := &fakeProducer{}
+	factory := newLogFactory(settings, producer, 10, 100, nil)
+
+	log, err := factory.CreateSessionLog(sessionID)
+	require.Nil(t, err)
+
+	msg := []byte("8=FIX.4.2\x0135=D\x01")
+	log.(quickfix.StructuredLog).OnEntry(quickfix.LogEntry{SessionID: sessionID, Direction: quickfix.LogOutgoing, MsgType: "D", MsgSeqNum: 5, Message: msg})
+	msg[0] = 'X'
+	log.OnEvent("Logon accepted")
+	log.(quickfix.FlushLog).Flush()
+
+	require.Len(t, producer.messages, 2)
+	assert.Equal(t, "fix.SENDER.TARGET", producer.messages[0].Topic)
+	assert.Equal(t, sessionID.String(), string(producer.messages[0].Key))
+	assert.Equal(t, "8=FIX.4.2\x0135=D\x01", string(producer.messages[0].Value))
+	assert.Equal(t, []kafka.Header{
+		{Key: "direction", Value: []byte("outgoing")},
+		{Key: "msg_type", Value: []byte("D")},
+		{Key: "seq_num", Value: []byte("5")},
+	}, producer.messages[0].Headers)
+
+	assert.Equal(t, "events", producer.messages[1].Topic)
+	assert.Equal(t, "Logon accepted", string(producer.messages[1].Value))
+
+	global, err := factory.Create()
+	require.Nil(t, err)
+	global.OnIncoming([]byte("8=FIX.4.2"))
+	global.(quickfix.FlushLog).Flush()
+	require.Len(t, producer.messages, 3)
+	assert.Equal(t, defaultMessageTopic, producer.messages[2].Topic)
+	assert.Nil(t, producer.messages[2].Key)
+
+	_, err = factory.CreateSessionLog(quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "OTHER", TargetCompID: "TARGET"})
+	assert.NotNil(t, err)
+}
+
+func TestKafkaLog_DeliveryError(t *testing.T) {
+	settings, sessionID := newTestSettings(t)
+	producer := &fakeProducer{err: errors.New("broker unavailable")}
+
+	var failed []kafka.Message
+	var failure error
+	factory := newLogFactory(settings, producer, 10, 100, func(messages []kafka.Message, err error) {
+		failed, failure = append(failed, messages...), err
+	})
+
+	log, err := factory.CreateSessionLog(sessionID)
+	require.Nil(t, err)
+	log.OnIncoming([]byte("8=FIX.4.2"))
+	log.(quickfix.FlushLog).Flush()
+
+	require.Len(t, failed, 1)
+	assert.Equal(t, "8=FIX.4.2", string(failed[0].Value))
+	assert.EqualError(t, failure, "broker unavailable")
+}
+
+func TestNewLogFactory(t *testing.T) {
+	_, err := NewLogFactory(quickfix.NewSettings(), nil)
+	assert.NotNil(t, err, "KafkaLogBrokers is required")
+
+	settings, _ := newTestSettings(t)
+	settings.GlobalSettings().Set(config.KafkaLogBatchSize, "0")
+	_, err = NewLogFactory(settings, nil)
+	assert.NotNil(t, err)
+
+	settings.GlobalSettings().Set(config.KafkaLogBatchSize, "500")
+	_, err = NewLogFactory(settings, nil)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package slog provides a quickfix.LogFactory writing structured messages and events to a log/slog Logger.
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type slogLog struct {
+	logger    *slog.Logger
+	sessionID quickfix.SessionID
+}
+
+func (l slogLog) OnIncoming(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogIncoming, Message: s})
+}
+
+func (l slogLog) OnOutgoing(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogOutgoing, Message: s})
+}
+
+func (l slogLog) OnEvent(s string) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogEvent, Text: s})
+}
+
+func (l slogLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+func (l slogLog) OnEntry(entry quickfix.LogEntry) {
+	attrs := make([]slog.Attr, 0, 5)
+	if entry.SessionID != (quickfix.SessionID{}) {
+		attrs = append(attrs, slog.String("session_id", entry.SessionID.String()))
+	}
+	attrs = append(attrs, slog.String("direction", entry.Direction.String()))
+
+	if entry.Direction == quickfix.LogEvent {
+		l.logger.LogAttrs(context.Background(), slog.LevelInfo, entry.Text, attrs...)
+		return
+	}
+
+	if entry.MsgType != "" {
+		attrs = append(attrs, slog.String("msg_type", entry.MsgType))
+	}
+	if entry.MsgSeqNum != 0 {
+		attrs = append(attrs, slog.Int("seq_num", entry.MsgSeqNum))
+	}
+	attrs = append(attrs, slog.String("raw_message", string(entry.Message)))
+	l.logger.LogAttrs(context.Background(), slog.LevelInfo, entry.Direction.String(), attrs...)
+}
+
+type slogLogFactory struct {
+	logger *slog.Logger
+}
+
+func (f slogLogFactory) Create() (quickfix.Log, error) {
+	return slogLog{logger: f.logger}, nil
+}
+
+func (f slogLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	return slogLog{logger: f.logger, sessionID: sessionID}, nil
+}
+
+// NewLogFactory creates an instance of LogFactory that writes messages and events to logger,
+// with the session ID, direction, message type and sequence number as attributes.
+func NewLogFactory(logger *slog.Logger) quickfix.LogFactory {
+	return slogLogFactory{logger}
+}
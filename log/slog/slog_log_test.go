@@ -0,0 +1,60 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestSlogLog(t *testing.T) {
+	var buf bytes.Buffer
+	factory := NewLogFactory(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	log, err := factory.CreateSessionLog(sessionID)
+	require.Nil(t, err)
+
+	structured, ok := log.(quickfix.StructuredLog)
+	require.True(t, ok)
+	structured.OnEntry(quickfix.LogEntry{SessionID: sessionID, Direction: quickfix.LogIncoming, MsgType: "D", MsgSeqNum: 7, Message: []byte("8=FIX.4.2")})
+
+	var record map[string]interface{}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "incoming", record["msg"])
+	assert.Equal(t, sessionID.String(), record["session_id"])
+	assert.Equal(t, "D", record["msg_type"])
+	assert.Equal(t, float64(7), record["seq_num"])
+	assert.Equal(t, "8=FIX.4.2", record["raw_message"])
+
+	buf.Reset()
+	global, err := factory.Create()
+	require.Nil(t, err)
+	global.OnEventf("Listening on %v", ":5001")
+
+	record = nil
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "Listening on :5001", record["msg"])
+	assert.Equal(t, "event", record["direction"])
+	assert.NotContains(t, record, "session_id")
+}
@@ -0,0 +1,77 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sql
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultBatchInterval = time.Second
+
+type logRow struct {
+	time time.Time
+	text string
+}
+
+// batch holds the rows of a log until SQLLogBatchSize rows are logged to a table,
+// or SQLLogBatchInterval has passed since the first.
+type batch struct {
+	size     int
+	interval time.Duration
+
+	mu    sync.Mutex
+	rows  map[string][]logRow
+	timer *time.Timer
+}
+
+func (b *batch) add(l sqlLog, table string, row logRow) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rows == nil {
+		b.rows = make(map[string][]logRow)
+	}
+	b.rows[table] = append(b.rows[table], row)
+
+	if len(b.rows[table]) >= b.size {
+		l.insertRows(table, b.rows[table])
+		delete(b.rows, table)
+	}
+
+	switch {
+	case len(b.rows) == 0 && b.timer != nil:
+		b.timer.Stop()
+		b.timer = nil
+	case len(b.rows) > 0 && b.timer == nil:
+		b.timer = time.AfterFunc(b.interval, func() { b.flush(l) })
+	}
+}
+
+func (b *batch) flush(l sqlLog) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for table, rows := range b.rows {
+		l.insertRows(table, rows)
+	}
+	b.rows = nil
+
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/quickfixgo/quickfix"
@@ -37,6 +38,7 @@ type sqlLog struct {
 	sqlConnMaxLifetime time.Duration
 	db                 *sql.DB
 	placeholder        placeholderFunc
+	batch              *batch
 }
 
 type placeholderFunc func(int) string
@@ -60,31 +62,14 @@ func postgresPlaceholder(i int) string {
 }
 
 // NewLogFactory returns a sql-based implementation of LogFactory.
+// SQLLogDriver, SQLLogDataSourceName and SQLLogConnMaxLifetime default to the settings of the sql store.
 func NewLogFactory(settings *quickfix.Settings) quickfix.LogFactory {
 	return sqlLogFactory{settings: settings}
 }
 
 // Create creates a new SQLLog implementation of the Log interface.
 func (f sqlLogFactory) Create() (log quickfix.Log, err error) {
-	globalSettings := f.settings.GlobalSettings()
-
-	sqlDriver, err := globalSettings.Setting(config.SQLLogDriver)
-	if err != nil {
-		return nil, err
-	}
-	sqlDataSourceName, err := globalSettings.Setting(config.SQLLogDataSourceName)
-	if err != nil {
-		return nil, err
-	}
-	sqlConnMaxLifetime := 0 * time.Second
-	if globalSettings.HasSetting(config.SQLLogConnMaxLifetime) {
-		sqlConnMaxLifetime, err = globalSettings.DurationSetting(config.SQLLogConnMaxLifetime)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	return newSQLLog(quickfix.SessionID{}, sqlDriver, sqlDataSourceName, sqlConnMaxLifetime)
+	return newSQLLogFromSettings(quickfix.SessionID{}, f.settings.GlobalSettings())
 }
 
 // CreateSessionLog creates a new SQLLog implementation of the Log interface.
@@ -101,22 +86,59 @@ func (f sqlLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (log quick
 		}
 	}
 
-	sqlDriver, err := sessionSettings.Setting(config.SQLLogDriver)
+	return newSQLLogFromSettings(sessionID, sessionSettings)
+}
+
+// logOrStoreSetting returns the log setting, or the store setting if the log setting is not set.
+func logOrStoreSetting(settings *quickfix.SessionSettings, logSetting, storeSetting string) string {
+	if !settings.HasSetting(logSetting) && settings.HasSetting(storeSetting) {
+		return storeSetting
+	}
+	return logSetting
+}
+
+func newSQLLogFromSettings(sessionID quickfix.SessionID, settings *quickfix.SessionSettings) (*sqlLog, error) {
+	sqlDriver, err := settings.Setting(logOrStoreSetting(settings, config.SQLLogDriver, config.SQLStoreDriver))
 	if err != nil {
 		return nil, err
 	}
-	sqlDataSourceName, err := sessionSettings.Setting(config.SQLLogDataSourceName)
+	sqlDataSourceName, err := settings.Setting(logOrStoreSetting(settings, config.SQLLogDataSourceName, config.SQLStoreDataSourceName))
 	if err != nil {
 		return nil, err
 	}
 	sqlConnMaxLifetime := 0 * time.Second
-	if sessionSettings.HasSetting(config.SQLLogConnMaxLifetime) {
-		sqlConnMaxLifetime, err = sessionSettings.DurationSetting(config.SQLLogConnMaxLifetime)
+	if connMaxLifetime := logOrStoreSetting(settings, config.SQLLogConnMaxLifetime, config.SQLStoreConnMaxLifetime); settings.HasSetting(connMaxLifetime) {
+		sqlConnMaxLifetime, err = settings.DurationSetting(connMaxLifetime)
 		if err != nil {
 			return nil, err
 		}
 	}
-	return newSQLLog(sessionID, sqlDriver, sqlDataSourceName, sqlConnMaxLifetime)
+
+	batchSize := 1
+	if settings.HasSetting(config.SQLLogBatchSize) {
+		if batchSize, err = settings.IntSetting(config.SQLLogBatchSize); err != nil {
+			return nil, err
+		}
+		if batchSize <= 0 {
+			return nil, fmt.Errorf("%v must be a positive integer", config.SQLLogBatchSize)
+		}
+	}
+	batchInterval := defaultBatchInterval
+	if settings.HasSetting(config.SQLLogBatchInterval) {
+		if batchInterval, err = settings.DurationSetting(config.SQLLogBatchInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	l, err := newSQLLog(sessionID, sqlDriver, sqlDataSourceName, sqlConnMaxLifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	if batchSize > 1 {
+		l.batch = &batch{size: batchSize, interval: batchInterval}
+	}
+	return l, nil
 }
 
 func newSQLLog(sessionID quickfix.SessionID, driver string, dataSourceName string, connMaxLifetime time.Duration) (l *sqlLog, err error) {
@@ -160,26 +182,52 @@ func (l sqlLog) OnEventf(format string, v ...interface{}) {
 }
 
 func (l sqlLog) insert(table string, value string) {
+	if l.batch != nil {
+		l.batch.add(l, table, logRow{time.Now(), value})
+		return
+	}
+
+	l.insertRows(table, []logRow{{time.Now(), value}})
+}
+
+// insertRows inserts rows into table with a single statement.
+func (l sqlLog) insertRows(table string, rows []logRow) {
 	s := l.sessionID
 
+	values := make([]string, len(rows))
+	args := make([]interface{}, 0, 10*len(rows))
+	for i, row := range rows {
+		values[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		args = append(args,
+			row.time,
+			s.BeginString, s.Qualifier,
+			s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+			s.TargetCompID, s.TargetSubID, s.TargetLocationID,
+			row.text,
+		)
+	}
+
 	_, err := l.db.Exec(sqlString(`INSERT INTO `+table+` (
 			time,
 			beginstring, session_qualifier,
 			sendercompid, sendersubid, senderlocid,
 			targetcompid, targetsubid, targetlocid, 
 			text)
-			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, l.placeholder),
-		time.Now(),
-		s.BeginString, s.Qualifier,
-		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
-		s.TargetCompID, s.TargetSubID, s.TargetLocationID,
-		value,
+			VALUES`+strings.Join(values, ", "), l.placeholder),
+		args...,
 	)
 	if err != nil {
 		log.Println(err)
 	}
 }
 
+// Flush inserts the messages and events batched by SQLLogBatchSize.
+func (l sqlLog) Flush() {
+	if l.batch != nil {
+		l.batch.flush(l)
+	}
+}
+
 func (l *sqlLog) iterate(table string, cb func(string) error) error {
 	s := l.sessionID
 	rows, err := l.db.Query(sqlString(`SELECT text FROM `+table+`
@@ -218,6 +266,7 @@ func (l *sqlLog) getEntries(table string) ([]string, error) {
 
 // Close closes the log's database connection.
 func (l *sqlLog) close() error {
+	l.Flush()
 	if l.db != nil {
 		l.db.Close()
 		l.db = nil
@@ -27,6 +27,7 @@ import (
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -124,6 +125,64 @@ func (suite *SQLLogTestSuite) TestSQLLog() {
 	require.Equal(suite.T(), "Cool4", entries[1])
 }
 
+func (suite *SQLLogTestSuite) TestSQLLogStoreSettings() {
+	settings := quickfix.NewSettings()
+	for _, setting := range []string{config.SQLLogDriver, config.SQLLogDataSourceName, config.SQLLogConnMaxLifetime} {
+		value, err := suite.settings.GlobalSettings().Setting(setting)
+		require.Nil(suite.T(), err)
+		settings.GlobalSettings().Set(strings.Replace(setting, "SQLLog", "SQLStore", 1), value)
+	}
+
+	log, err := NewLogFactory(settings).Create()
+	require.Nil(suite.T(), err)
+	suite.log = log.(*sqlLog)
+	suite.Equal(14400*time.Second, suite.log.sqlConnMaxLifetime)
+
+	suite.log.OnEvent("Cool1")
+	entries, err := suite.log.getEntries("event_log")
+	require.Nil(suite.T(), err)
+	suite.Equal([]string{"Cool1"}, entries)
+}
+
+func (suite *SQLLogTestSuite) TestSQLLogBatch() {
+	suite.settings.GlobalSettings().Set(config.SQLLogBatchSize, "3")
+	suite.settings.GlobalSettings().Set(config.SQLLogBatchInterval, "1h")
+	log, err := NewLogFactory(suite.settings).CreateSessionLog(suite.sessionID)
+	require.Nil(suite.T(), err)
+	suite.log = log.(*sqlLog)
+
+	suite.log.OnIncoming([]byte("Cool1"))
+	suite.log.OnOutgoing([]byte("Cool2"))
+	suite.log.OnEvent("Cool3")
+	entries, err := suite.log.getEntries("messages_log")
+	require.Nil(suite.T(), err)
+	suite.Empty(entries, "batch is not full")
+
+	suite.log.OnIncoming([]byte("Cool4"))
+	entries, err = suite.log.getEntries("messages_log")
+	require.Nil(suite.T(), err)
+	suite.Equal([]string{"Cool1", "Cool2", "Cool4"}, entries)
+
+	log.(quickfix.FlushLog).Flush()
+	entries, err = suite.log.getEntries("event_log")
+	require.Nil(suite.T(), err)
+	suite.Equal([]string{"Cool3"}, entries)
+
+	suite.settings.GlobalSettings().Set(config.SQLLogBatchInterval, "10ms")
+	log, err = NewLogFactory(suite.settings).CreateSessionLog(suite.sessionID)
+	require.Nil(suite.T(), err)
+	log.OnEvent("Cool5")
+	suite.Eventually(func() bool {
+		entries, err = suite.log.getEntries("event_log")
+		return err == nil && len(entries) == 2
+	}, time.Second, 10*time.Millisecond, "batch is inserted after SQLLogBatchInterval")
+	log.(*sqlLog).close()
+
+	suite.settings.GlobalSettings().Set(config.SQLLogBatchSize, "0")
+	_, err = NewLogFactory(suite.settings).CreateSessionLog(suite.sessionID)
+	suite.NotNil(err)
+}
+
 func (suite *SQLLogTestSuite) TestSqlPlaceholderReplacement() {
 	got := sqlString("A ? B ? C ?", postgresPlaceholder)
 	suite.Equal("A $1 B $2 C $3", got)
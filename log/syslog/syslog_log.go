@@ -0,0 +1,238 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package syslog provides a quickfix.LogFactory sending events, and optionally messages, to syslog as RFC 5424 messages.
+package syslog
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+const (
+	defaultAppName = "quickfix"
+
+	// structuredDataID is the SD-ID of the fields of messages and events, under the enterprise number reserved for documentation.
+	structuredDataID = "fix@32473"
+
+	severityInfo = 6
+)
+
+var facilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// writer sends syslog messages over one connection, reconnecting once if a write fails.
+type writer struct {
+	mu       sync.Mutex
+	network  string
+	address  string
+	conn     net.Conn
+	hostname string
+	appName  string
+	facility int
+}
+
+func (w *writer) connect() (err error) {
+	if w.conn != nil {
+		w.conn.Close()
+	}
+	w.conn, err = net.Dial(w.network, w.address)
+	return
+}
+
+// framed returns msg framed for the transport, stream transports use octet counting per RFC 6587.
+func (w *writer) framed(msg []byte) []byte {
+	switch w.network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	default:
+		return msg
+	}
+}
+
+func (w *writer) write(msgID string, params []string, text string) {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "<%d>1 %s %s %s %d %s ", w.facility*8+severityInfo, time.Now().UTC().Format(time.RFC3339Nano), w.hostname, w.appName, os.Getpid(), msgID)
+	if len(params) == 0 {
+		msg.WriteString("-")
+	} else {
+		msg.WriteString("[" + structuredDataID)
+		for _, param := range params {
+			msg.WriteString(" " + param)
+		}
+		msg.WriteString("]")
+	}
+	msg.WriteString(" " + text)
+	framed := w.framed(msg.Bytes())
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		if _, err := w.conn.Write(framed); err == nil {
+			return
+		}
+	}
+	if err := w.connect(); err == nil {
+		_, _ = w.conn.Write(framed)
+	}
+}
+
+// param returns an SD-PARAM, escaping the value per RFC 5424.
+func param(name, value string) string {
+	return name + `="` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value) + `"`
+}
+
+type syslogLog struct {
+	writer    *writer
+	sessionID quickfix.SessionID
+	messages  bool
+}
+
+func (l syslogLog) OnIncoming(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogIncoming, Message: s})
+}
+
+func (l syslogLog) OnOutgoing(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogOutgoing, Message: s})
+}
+
+func (l syslogLog) OnEvent(s string) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogEvent, Text: s})
+}
+
+func (l syslogLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+// OnEntry sends the entry with the session ID, direction, message type and sequence number as structured data.
+// The SOH delimiters of messages are sent as '|'.
+func (l syslogLog) OnEntry(entry quickfix.LogEntry) {
+	if entry.Direction != quickfix.LogEvent && !l.messages {
+		return
+	}
+
+	var params []string
+	if entry.SessionID != (quickfix.SessionID{}) {
+		params = append(params, param("session", entry.SessionID.String()))
+	}
+
+	if entry.Direction == quickfix.LogEvent {
+		l.writer.write("event", params, entry.Text)
+		return
+	}
+
+	if entry.MsgType != "" {
+		params = append(params, param("msgType", entry.MsgType))
+	}
+	if entry.MsgSeqNum != 0 {
+		params = append(params, param("seqNum", strconv.Itoa(entry.MsgSeqNum)))
+	}
+	l.writer.write(entry.Direction.String(), params, string(bytes.ReplaceAll(entry.Message, []byte{1}, []byte("|"))))
+}
+
+type syslogLogFactory struct {
+	settings *quickfix.Settings
+	writer   *writer
+}
+
+// NewLogFactory returns a LogFactory sending to the syslog server of SyslogLogNetwork and SyslogLogAddress.
+func NewLogFactory(settings *quickfix.Settings) (quickfix.LogFactory, error) {
+	globalSettings := settings.GlobalSettings()
+
+	w := &writer{network: "udp", appName: defaultAppName, facility: facilities["local0"]}
+
+	var err error
+	if globalSettings.HasSetting(config.SyslogLogNetwork) {
+		if w.network, err = globalSettings.Setting(config.SyslogLogNetwork); err != nil {
+			return nil, err
+		}
+	}
+
+	if w.address, err = globalSettings.Setting(config.SyslogLogAddress); err != nil {
+		return nil, err
+	}
+
+	if globalSettings.HasSetting(config.SyslogLogAppName) {
+		if w.appName, err = globalSettings.Setting(config.SyslogLogAppName); err != nil {
+			return nil, err
+		}
+	}
+
+	if globalSettings.HasSetting(config.SyslogLogFacility) {
+		facility, err := globalSettings.Setting(config.SyslogLogFacility)
+		if err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if w.facility, ok = facilities[facility]; !ok {
+			return nil, quickfix.IncorrectFormatForSetting{Setting: config.SyslogLogFacility, Value: []byte(facility)}
+		}
+	}
+
+	if w.hostname, err = os.Hostname(); err != nil || w.hostname == "" {
+		w.hostname = "-"
+	}
+
+	if err = w.connect(); err != nil {
+		return nil, err
+	}
+
+	return syslogLogFactory{settings: settings, writer: w}, nil
+}
+
+func (f syslogLogFactory) Create() (quickfix.Log, error) {
+	return f.newLog(quickfix.SessionID{}, f.settings.GlobalSettings())
+}
+
+func (f syslogLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	globalSettings := f.settings.GlobalSettings()
+	dynamicSessions, _ := globalSettings.BoolSetting(config.DynamicSessions)
+
+	sessionSettings, ok := f.settings.SessionSettings()[sessionID]
+	if !ok {
+		if dynamicSessions {
+			sessionSettings = globalSettings
+		} else {
+			return nil, fmt.Errorf("unknown session: %v", sessionID)
+		}
+	}
+
+	return f.newLog(sessionID, sessionSettings)
+}
+
+func (f syslogLogFactory) newLog(sessionID quickfix.SessionID, settings *quickfix.SessionSettings) (quickfix.Log, error) {
+	l := syslogLog{writer: f.writer, sessionID: sessionID}
+	if settings.HasSetting(config.SyslogLogMessages) {
+		var err error
+		if l.messages, err = settings.BoolSetting(config.SyslogLogMessages); err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
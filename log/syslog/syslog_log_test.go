@@ -0,0 +1,108 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package syslog
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+var sessionID = quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+func newTestSettings(t *testing.T, network, address string) *quickfix.Settings {
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SyslogLogNetwork=%v
+SyslogLogAddress=%v
+SyslogLogFacility=local1
+
+[SESSION]
+BeginString=FIX.4.2
+SenderCompID=SENDER
+TargetCompID=TARGET
+SyslogLogMessages=Y
+`, network, address)))
+	require.Nil(t, err)
+	return settings
+}
+
+func TestSyslogLogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer conn.Close()
+
+	factory, err := NewLogFactory(newTestSettings(t, "udp", conn.LocalAddr().String()))
+	require.Nil(t, err)
+	log, err := factory.CreateSessionLog(sessionID)
+	require.Nil(t, err)
+
+	log.OnEvent(`Logon "accepted"`)
+	log.(quickfix.StructuredLog).OnEntry(quickfix.LogEntry{SessionID: sessionID, Direction: quickfix.LogIncoming, MsgType: "D", MsgSeqNum: 5, Message: []byte("8=FIX.4.2\x0135=D\x01")})
+
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	require.Nil(t, err)
+	event := string(buf[:n])
+	assert.True(t, strings.HasPrefix(event, "<142>1 "), event)
+	assert.Contains(t, event, ` quickfix `)
+	assert.Contains(t, event, ` event [fix@32473 session="FIX.4.2:SENDER->TARGET"] Logon "accepted"`)
+
+	n, _, err = conn.ReadFrom(buf)
+	require.Nil(t, err)
+	assert.Contains(t, string(buf[:n]), ` incoming [fix@32473 session="FIX.4.2:SENDER->TARGET" msgType="D" seqNum="5"] 8=FIX.4.2|35=D|`)
+}
+
+func TestSyslogLogTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+
+	factory, err := NewLogFactory(newTestSettings(t, "tcp", listener.Addr().String()))
+	require.Nil(t, err)
+	conn, err := listener.Accept()
+	require.Nil(t, err)
+	defer conn.Close()
+
+	log, err := factory.Create()
+	require.Nil(t, err)
+	log.OnIncoming([]byte("8=FIX.4.2\x01"))
+	log.OnEventf("Connected to %v", "localhost")
+
+	var length int
+	reader := bufio.NewReader(conn)
+	_, err = fmt.Fscanf(reader, "%d ", &length)
+	require.Nil(t, err)
+	msg := make([]byte, length)
+	_, err = reader.Read(msg)
+	require.Nil(t, err)
+	assert.True(t, strings.HasSuffix(string(msg), " event - Connected to localhost"), string(msg))
+}
+
+func TestSyslogLogUnknownFacility(t *testing.T) {
+	settings := newTestSettings(t, "udp", "127.0.0.1:514")
+	settings.GlobalSettings().Set("SyslogLogFacility", "local9")
+
+	_, err := NewLogFactory(settings)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package zap provides a quickfix.LogFactory writing structured messages and events to a zap Logger.
+package zap
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type zapLog struct {
+	logger    *zap.Logger
+	sessionID quickfix.SessionID
+}
+
+func (l zapLog) OnIncoming(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogIncoming, Message: s})
+}
+
+func (l zapLog) OnOutgoing(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogOutgoing, Message: s})
+}
+
+func (l zapLog) OnEvent(s string) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogEvent, Text: s})
+}
+
+func (l zapLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+func (l zapLog) OnEntry(entry quickfix.LogEntry) {
+	fields := make([]zap.Field, 0, 5)
+	if entry.SessionID != (quickfix.SessionID{}) {
+		fields = append(fields, zap.String("session_id", entry.SessionID.String()))
+	}
+	fields = append(fields, zap.String("direction", entry.Direction.String()))
+
+	if entry.Direction == quickfix.LogEvent {
+		l.logger.Info(entry.Text, fields...)
+		return
+	}
+
+	if entry.MsgType != "" {
+		fields = append(fields, zap.String("msg_type", entry.MsgType))
+	}
+	if entry.MsgSeqNum != 0 {
+		fields = append(fields, zap.Int("seq_num", entry.MsgSeqNum))
+	}
+	fields = append(fields, zap.ByteString("raw_message", entry.Message))
+	l.logger.Info(entry.Direction.String(), fields...)
+}
+
+type zapLogFactory struct {
+	logger *zap.Logger
+}
+
+func (f zapLogFactory) Create() (quickfix.Log, error) {
+	return zapLog{logger: f.logger}, nil
+}
+
+func (f zapLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	return zapLog{logger: f.logger, sessionID: sessionID}, nil
+}
+
+// NewLogFactory creates an instance of LogFactory that writes messages and events to logger,
+// with the session ID, direction, message type and sequence number as fields.
+func NewLogFactory(logger *zap.Logger) quickfix.LogFactory {
+	return zapLogFactory{logger}
+}
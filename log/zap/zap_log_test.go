@@ -0,0 +1,54 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package zap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestZapLog(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	factory := NewLogFactory(zap.New(core))
+
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	log, err := factory.CreateSessionLog(sessionID)
+	require.Nil(t, err)
+
+	structured, ok := log.(quickfix.StructuredLog)
+	require.True(t, ok)
+	structured.OnEntry(quickfix.LogEntry{SessionID: sessionID, Direction: quickfix.LogOutgoing, MsgType: "A", MsgSeqNum: 1, Message: []byte("8=FIX.4.2")})
+	log.OnEvent("Logon accepted")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "outgoing", entries[0].Message)
+	assert.Equal(t, map[string]interface{}{
+		"session_id":  sessionID.String(),
+		"direction":   "outgoing",
+		"msg_type":    "A",
+		"seq_num":     int64(1),
+		"raw_message": "8=FIX.4.2",
+	}, entries[0].ContextMap())
+	assert.Equal(t, "Logon accepted", entries[1].Message)
+	assert.Equal(t, map[string]interface{}{"session_id": sessionID.String(), "direction": "event"}, entries[1].ContextMap())
+}
@@ -0,0 +1,85 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package zerolog provides a quickfix.LogFactory writing structured messages and events to a zerolog Logger.
+package zerolog
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type zerologLog struct {
+	logger    zerolog.Logger
+	sessionID quickfix.SessionID
+}
+
+func (l zerologLog) OnIncoming(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogIncoming, Message: s})
+}
+
+func (l zerologLog) OnOutgoing(s []byte) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogOutgoing, Message: s})
+}
+
+func (l zerologLog) OnEvent(s string) {
+	l.OnEntry(quickfix.LogEntry{SessionID: l.sessionID, Direction: quickfix.LogEvent, Text: s})
+}
+
+func (l zerologLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
+
+func (l zerologLog) OnEntry(entry quickfix.LogEntry) {
+	event := l.logger.Info()
+	if entry.SessionID != (quickfix.SessionID{}) {
+		event = event.Str("session_id", entry.SessionID.String())
+	}
+	event = event.Str("direction", entry.Direction.String())
+
+	if entry.Direction == quickfix.LogEvent {
+		event.Msg(entry.Text)
+		return
+	}
+
+	if entry.MsgType != "" {
+		event = event.Str("msg_type", entry.MsgType)
+	}
+	if entry.MsgSeqNum != 0 {
+		event = event.Int("seq_num", entry.MsgSeqNum)
+	}
+	event.Bytes("raw_message", entry.Message).Msg(entry.Direction.String())
+}
+
+type zerologLogFactory struct {
+	logger zerolog.Logger
+}
+
+func (f zerologLogFactory) Create() (quickfix.Log, error) {
+	return zerologLog{logger: f.logger}, nil
+}
+
+func (f zerologLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	return zerologLog{logger: f.logger, sessionID: sessionID}, nil
+}
+
+// NewLogFactory creates an instance of LogFactory that writes messages and events to logger,
+// with the session ID, direction, message type and sequence number as fields.
+func NewLogFactory(logger zerolog.Logger) quickfix.LogFactory {
+	return zerologLogFactory{logger}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package zerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestZerologLog(t *testing.T) {
+	var buf bytes.Buffer
+	factory := NewLogFactory(zerolog.New(&buf))
+
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	log, err := factory.CreateSessionLog(sessionID)
+	require.Nil(t, err)
+
+	structured, ok := log.(quickfix.StructuredLog)
+	require.True(t, ok)
+	structured.OnEntry(quickfix.LogEntry{SessionID: sessionID, Direction: quickfix.LogIncoming, MsgType: "8", MsgSeqNum: 3, Message: []byte("8=FIX.4.2")})
+
+	var record map[string]interface{}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, map[string]interface{}{
+		"level":       "info",
+		"message":     "incoming",
+		"session_id":  sessionID.String(),
+		"direction":   "incoming",
+		"msg_type":    "8",
+		"seq_num":     float64(3),
+		"raw_message": "8=FIX.4.2",
+	}, record)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type entryLog struct {
+	nullLog
+	entries []LogEntry
+}
+
+func (l *entryLog) OnEntry(entry LogEntry) {
+	l.entries = append(l.entries, entry)
+}
+
+func TestSessionLog(t *testing.T) {
+	sessionID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "sender", TargetCompID: "target"}
+	assert.Equal(t, nullLog{}, newSessionLog(nullLog{}, sessionID, nil), "logs without OnEntry are used as is")
+
+	entries := &entryLog{}
+	log := newSessionLog(entries, sessionID, nil)
+
+	msg := []byte("8=FIX.4.2\x019=49\x0135=D\x0134=12\x0149=sender\x0156=target\x0110=000\x01")
+	log.OnIncoming(msg)
+	log.OnOutgoing([]byte("8=FIX.4.2\x019=5\x0135=0\x0110=000\x01"))
+	log.OnEventf("Sent %v", "logon")
+
+	require.Len(t, entries.entries, 3)
+	assert.Equal(t, LogEntry{SessionID: sessionID, Direction: LogIncoming, MsgType: "D", MsgSeqNum: 12, Message: msg}, entries.entries[0])
+	assert.Equal(t, LogOutgoing, entries.entries[1].Direction)
+	assert.Equal(t, "0", entries.entries[1].MsgType)
+	assert.Zero(t, entries.entries[1].MsgSeqNum)
+	assert.Equal(t, LogEntry{SessionID: sessionID, Direction: LogEvent, Text: "Sent logon"}, entries.entries[2])
+}
+
+type flushEntryLog struct {
+	entryLog
+	flushes int
+}
+
+func (l *flushEntryLog) Flush() {
+	l.flushes++
+}
+
+func TestFlushLog(t *testing.T) {
+	flushLog(nullLog{})
+	flushLog(nil)
+
+	log := &flushEntryLog{}
+	flushLog(newSessionLog(log, SessionID{BeginString: BeginStringFIX42}, nil))
+	flushLog(log)
+	assert.Equal(t, 2, log.flushes)
+}
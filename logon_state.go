@@ -52,6 +52,12 @@ func (s logonState) FixMsgIn(session *session, msg *Message) (nextState sessionS
 
 			return
 
+		case MessageRejectError:
+			if session.SendLogoutBeforeDisconnect {
+				return shutdownWithReason(session, msg, false, err.Error())
+			}
+			return handleStateError(session, err)
+
 		default:
 			return handleStateError(session, err)
 		}
@@ -81,7 +87,7 @@ func shutdownWithReason(session *session, msg *Message, incrNextTargetMsgSeqNum
 	}
 
 	if incrNextTargetMsgSeqNum {
-		if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+		if err := session.incrNextTargetMsgSeqNum(msg); err != nil {
 			session.logError(err)
 		}
 	}
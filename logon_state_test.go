@@ -17,6 +17,7 @@ package quickfix
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 	"time"
 
@@ -332,6 +333,166 @@ func (s *LogonStateTestSuite) TestFixMsgInLogonRejectLogon() {
 	s.NextSenderMsgSeqNum(3)
 }
 
+func (s *LogonStateTestSuite) TestFixMsgInLogonNextExpectedMsgSeqNum() {
+	s.session.EnableNextExpectedMsgSeqNum = true
+	s.MockApp.On("ToApp").Return(nil)
+	for i := 0; i < 2; i++ {
+		_, err := s.session.prepMessageForSend(s.NewOrderSingle(), nil)
+		s.Require().Nil(err)
+	}
+	s.NextSenderMsgSeqNum(3)
+
+	s.MessageFactory.seqNum = 0
+	logon := s.Logon()
+	logon.Body.SetField(tagHeartBtInt, FIXInt(32))
+	logon.Body.SetField(tagNextExpectedMsgSeqNum, FIXInt(1))
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("OnLogon")
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.session, logon)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.State(inSession{})
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 4)
+
+	// The Logon response, then both orders are resent before the Logon is gap filled.
+	for _, expected := range []struct {
+		msgType string
+		seqNum  int
+	}{{string(msgTypeLogon), 3}, {"D", 1}, {"D", 2}} {
+		msgBytes, _ := s.Receiver.LastMessage()
+		s.Require().NotNil(msgBytes)
+		sent := NewMessage()
+		s.Require().Nil(ParseMessage(sent, bytes.NewBuffer(msgBytes)))
+		s.MessageType(expected.msgType, sent)
+		s.FieldEquals(tagMsgSeqNum, expected.seqNum, sent.Header)
+	}
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeSequenceReset), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagMsgSeqNum, 3, s.MockApp.lastToAdmin.Header)
+	s.FieldEquals(tagNewSeqNo, 4, s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagGapFillFlag, true, s.MockApp.lastToAdmin.Body)
+
+	s.NextTargetMsgSeqNum(2)
+	s.NextSenderMsgSeqNum(4)
+}
+
+func (s *LogonStateTestSuite) TestFixMsgInLogonNextExpectedMsgSeqNumTooHigh() {
+	s.session.EnableNextExpectedMsgSeqNum = true
+	s.session.InitiateLogon = true
+	s.IncrNextSenderMsgSeqNum()
+
+	logon := s.Logon()
+	logon.Body.SetField(tagHeartBtInt, FIXInt(32))
+	logon.Body.SetField(tagNextExpectedMsgSeqNum, FIXInt(5))
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.MockApp.On("OnLogout")
+	s.fixMsgIn(s.session, logon)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.MockApp.AssertNotCalled(s.T(), "OnLogon")
+	s.State(latentState{})
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagText, "Tag 789 (NextExpectedMsgSeqNum) is higher than expected. Expected 2, Received 5", s.MockApp.lastToAdmin.Body)
+}
+
+func (s *LogonStateTestSuite) TestFixMsgInLogonInvalidSendLogoutBeforeDisconnect() {
+	s.session.SendLogoutBeforeDisconnect = true
+
+	logon := s.Logon()
+	logon.Body.SetField(tagHeartBtInt, FIXInt(32))
+
+	s.MockApp.On("FromAdmin").Return(compIDProblem())
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.session, logon)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.State(latentState{})
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagText, "CompID problem", s.MockApp.lastToAdmin.Body)
+	s.NextTargetMsgSeqNum(1)
+}
+
+func (s *LogonStateTestSuite) TestFixMsgInLogonInvalidSilentDisconnect() {
+	logon := s.Logon()
+	logon.Body.SetField(tagHeartBtInt, FIXInt(32))
+
+	s.MockApp.On("FromAdmin").Return(compIDProblem())
+	s.fixMsgIn(s.session, logon)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.State(latentState{})
+	s.NoMessageSent()
+}
+
+type authenticatorFunc func(LogonCredentials) error
+
+func (f authenticatorFunc) Authenticate(credentials LogonCredentials) error { return f(credentials) }
+
+func (s *LogonStateTestSuite) TestFixMsgInLogonAuthenticator() {
+	s.IncrNextSenderMsgSeqNum()
+	s.MessageFactory.seqNum = 1
+	s.IncrNextTargetMsgSeqNum()
+
+	var received LogonCredentials
+	s.session.authenticator = authenticatorFunc(func(credentials LogonCredentials) error {
+		received = credentials
+		return nil
+	})
+
+	logon := s.Logon()
+	logon.Body.SetField(tagHeartBtInt, FIXInt(32))
+	logon.Body.SetField(tagUsername, FIXString("user"))
+	logon.Body.SetField(tagPassword, FIXString("secret"))
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("OnLogon")
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.session, logon)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.State(inSession{})
+	s.Equal(LogonCredentials{SessionID: s.session.sessionID, Username: "user", Password: "secret"}, received)
+}
+
+func (s *LogonStateTestSuite) TestFixMsgInLogonAuthenticatorReject() {
+	s.IncrNextSenderMsgSeqNum()
+	s.MessageFactory.seqNum = 1
+	s.IncrNextTargetMsgSeqNum()
+
+	s.session.authenticator = authenticatorFunc(func(LogonCredentials) error {
+		return errors.New("invalid password")
+	})
+
+	logon := s.Logon()
+	logon.Body.SetField(tagHeartBtInt, FIXInt(32))
+	logon.Body.SetField(tagUsername, FIXString("user"))
+	logon.Body.SetField(tagPassword, FIXString("wrong"))
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.session, logon)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.MockApp.AssertNotCalled(s.T(), "OnLogon")
+	s.State(latentState{})
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagText, "invalid password", s.MockApp.lastToAdmin.Body)
+
+	s.NextTargetMsgSeqNum(3)
+	s.NextSenderMsgSeqNum(3)
+}
+
 func (s *LogonStateTestSuite) TestFixMsgInLogonSeqNumTooHigh() {
 	s.MessageFactory.SetNextSeqNum(6)
 	logon := s.Logon()
@@ -0,0 +1,165 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"time"
+)
+
+type logoutAndDrainReq struct {
+	deadline time.Time
+	rep      chan<- error
+}
+
+type drainState struct {
+	deadline   time.Time
+	logoutSent bool
+	reps       []chan<- error
+}
+
+// logoutAndDrain stops the session accepting application messages, and logs out once queued messages are sent.
+// It returns ErrLogoutTimeout if the session is not logged out within timeout. Called by a callback of the run loop,
+// it returns once the drain is started, as the drain only progresses after the callback returns.
+func (s *session) logoutAndDrain(timeout time.Duration) error {
+	s.draining.Store(true)
+
+	timer := s.clock.NewTimer(timeout)
+	defer timer.Stop()
+
+	rep := make(chan error, 1)
+	if !s.postAdmin(logoutAndDrainReq{deadline: s.clock.Now().Add(timeout), rep: rep}, timer.C()) {
+		// The run loop is busy, and never started the drain.
+		s.draining.Store(false)
+		return ErrLogoutTimeout
+	}
+
+	if s.onRunLoop() {
+		select {
+		case err := <-rep:
+			return err
+		default:
+			return nil
+		}
+	}
+
+	select {
+	case err := <-rep:
+		return err
+	case <-timer.C():
+		return ErrLogoutTimeout
+	}
+}
+
+// logoutAndDrainContext is logoutAndDrain, disconnecting the session once its LogoutTimeout passes or the deadline
+// of ctx, whichever is earlier. If ctx is done first while the session is connected, it returns the error of ctx
+// without waiting for the logout.
+func (s *session) logoutAndDrainContext(ctx context.Context) error {
+	timeout := s.LogoutTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, deadline.Sub(s.clock.Now()))
+	}
+
+	rep := make(chan error, 1)
+	go func() { rep <- s.logoutAndDrain(timeout) }()
+
+	select {
+	case err := <-rep:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.healthStatus.mu.Lock()
+	connected := s.healthStatus.state.IsConnected()
+	s.healthStatus.mu.Unlock()
+	if !connected {
+		return nil
+	}
+
+	return ctx.Err()
+}
+
+func (s *session) onLogoutAndDrain(req logoutAndDrainReq) {
+	// Sessions not started, or stopped, have nothing to drain.
+	if s.drain == nil && (s.State == nil || !s.IsLoggedOn()) {
+		s.draining.Store(false)
+		req.rep <- nil
+		return
+	}
+
+	if s.drain == nil {
+		s.log.OnEvent("Draining session for logout")
+		s.drain = &drainState{deadline: req.deadline}
+	} else if req.deadline.Before(s.drain.deadline) {
+		s.drain.deadline = req.deadline
+	}
+	s.drain.reps = append(s.drain.reps, req.rep)
+
+	s.notifyMessageOut()
+	s.checkDrain(s.clock.Now())
+}
+
+// hasPendingSends returns true if outbound messages are waiting to be written to the connection.
+func (s *session) hasPendingSends() bool {
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+
+	return len(s.toSend) > 0 || (s.throttle != nil && len(s.throttle.queue) > 0)
+}
+
+// checkDrain advances a pending logoutAndDrain: once queued messages are sent it initiates the logout,
+// and once disconnected it replies to the callers. If the deadline passes first, the session is disconnected.
+func (s *session) checkDrain(now time.Time) {
+	if s.drain == nil {
+		return
+	}
+
+	if !s.IsConnected() {
+		s.finishDrain(nil)
+		return
+	}
+
+	expired := !now.Before(s.drain.deadline)
+
+	if !s.drain.logoutSent {
+		if s.IsLoggedOn() {
+			if !expired && s.hasPendingSends() {
+				return
+			}
+
+			s.setState(s, s.State.Stop(s))
+		}
+		s.drain.logoutSent = true
+	}
+
+	if expired && s.IsConnected() {
+		s.log.OnEvent("Timed out draining session for logout")
+		s.setState(s, latentState{})
+		s.finishDrain(ErrLogoutTimeout)
+	}
+}
+
+func (s *session) finishDrain(err error) {
+	if s.drain == nil {
+		return
+	}
+
+	for _, rep := range s.drain.reps {
+		rep <- err
+	}
+	s.drain = nil
+	s.draining.Store(false)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+type LogoutDrainTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestLogoutDrainTestSuite(t *testing.T) {
+	suite.Run(t, new(LogoutDrainTestSuite))
+}
+
+func (s *LogoutDrainTestSuite) SetupTest() {
+	s.Init()
+	s.session.State = inSession{}
+	s.MockApp.On("ToApp").Return(nil)
+	s.MockApp.On("ToAdmin")
+}
+
+func (s *LogoutDrainTestSuite) TestDrainThenLogout() {
+	s.Require().Nil(s.session.queueForSend(s.NewOrderSingle()))
+
+	rep := make(chan error, 1)
+	s.session.onLogoutAndDrain(logoutAndDrainReq{deadline: time.Now().Add(time.Minute), rep: rep})
+
+	// The queued message is sent before the Logout.
+	s.State(inSession{})
+	s.NoMessageSent()
+
+	s.session.SendAppMessages(s.session)
+	s.session.checkDrain(time.Now())
+	s.LastToAppMessageSent()
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.State(logoutState{})
+	s.Len(rep, 0)
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("OnLogout")
+	s.MessageFactory.SetNextSeqNum(1)
+	s.fixMsgIn(s.session, s.Logout())
+	s.session.checkDrain(time.Now())
+
+	s.State(latentState{})
+	s.Nil(<-rep)
+	s.False(s.session.draining.Load())
+}
+
+func (s *LogoutDrainTestSuite) TestTimeout() {
+	s.Require().Nil(s.session.queueForSend(s.NewOrderSingle()))
+
+	rep := make(chan error, 1)
+	s.session.onLogoutAndDrain(logoutAndDrainReq{deadline: time.Now().Add(time.Minute), rep: rep})
+	s.State(inSession{})
+
+	s.MockApp.On("OnLogout")
+	s.session.checkDrain(time.Now().Add(2 * time.Minute))
+
+	s.State(latentState{})
+	s.Equal(ErrLogoutTimeout, <-rep)
+}
+
+func (s *LogoutDrainTestSuite) TestNotLoggedOn() {
+	s.session.State = latentState{}
+
+	rep := make(chan error, 1)
+	s.session.onLogoutAndDrain(logoutAndDrainReq{deadline: time.Now().Add(time.Minute), rep: rep})
+
+	s.Nil(<-rep)
+	s.State(latentState{})
+	s.NoMessageSent()
+}
+
+func (s *LogoutDrainTestSuite) TestRejectsAppMessages() {
+	s.Require().Nil(registerSession(s.session))
+	defer func() { s.Nil(UnregisterSession(s.session.sessionID)) }()
+
+	s.session.draining.Store(true)
+	s.Equal(ErrLoggingOut, SendToTarget(s.NewOrderSingle(), s.session.sessionID))
+}
+
+func (s *LogoutDrainTestSuite) TestCallerTimeout() {
+	// The run loop is running, but busy.
+	running := make(chan struct{})
+	go func() {
+		s.session.setRunning(true, internal.GoroutineID())
+		close(running)
+	}()
+	<-running
+	defer s.session.setRunning(false, 0)
+
+	start := time.Now()
+	s.Equal(ErrLogoutTimeout, s.session.logoutAndDrain(50*time.Millisecond))
+	s.Less(time.Since(start), time.Second)
+	s.False(s.session.draining.Load())
+}
@@ -15,21 +15,110 @@
 
 package quickfix
 
-import "github.com/quickfixgo/quickfix/internal"
+import (
+	"bytes"
 
-type logoutState struct{ connectedNotLoggedOn }
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+type logoutState struct {
+	connectedNotLoggedOn
+
+	// logoutSeqNum is the MsgSeqNum of a Logout received ahead of a sequence gap. Once the counterparty
+	// has resent the gap the session disconnects, first replying with a Logout if replyPending.
+	logoutSeqNum int
+	replyPending bool
+}
 
 func (state logoutState) String() string { return "Logout State" }
 
 func (state logoutState) FixMsgIn(session *session, msg *Message) (nextState sessionState) {
+	if state.logoutSeqNum == 0 {
+		if seqNum, ok := logoutAheadOfGap(session, msg); ok {
+			session.log.OnEvent("Received logout response")
+			return state.awaitResend(session, seqNum, false)
+		}
+	}
+
 	nextState = inSession{}.FixMsgIn(session, msg)
 	if nextState, ok := nextState.(latentState); ok {
 		return nextState
 	}
 
+	if state.logoutSeqNum != 0 && session.store.NextTargetMsgSeqNum() >= state.logoutSeqNum {
+		return state.completeAfterResend(session)
+	}
+
+	return state
+}
+
+// logoutAheadOfGap returns the MsgSeqNum of msg if it is a valid Logout with a MsgSeqNum higher than expected.
+func logoutAheadOfGap(session *session, msg *Message) (int, bool) {
+	msgType, err := msg.Header.GetBytes(tagMsgType)
+	if err != nil || !bytes.Equal(msgTypeLogout, msgType) {
+		return 0, false
+	}
+
+	if err := session.verifyIgnoreSeqNumTooHigh(msg); err != nil {
+		return 0, false
+	}
+
+	tooHigh, ok := session.checkTargetTooHigh(msg).(targetTooHigh)
+	if !ok {
+		return 0, false
+	}
+
+	return tooHigh.ReceivedTarget, true
+}
+
+// awaitResend requests the messages missing before a Logout, and waits up to the LogoutTimeout for them.
+func (state logoutState) awaitResend(session *session, logoutSeqNum int, replyPending bool) sessionState {
+	session.log.OnEventf("Logout MsgSeqNum too high, expecting %v but received %v", session.store.NextTargetMsgSeqNum(), logoutSeqNum)
+
+	resend, _ := session.buildResendRequest(session.store.NextTargetMsgSeqNum(), logoutSeqNum-1)
+	send := session.send
+	if !session.IsLoggedOn() {
+		// Once logging out the send queue is no longer sent, so session messages are sent directly.
+		send = session.dropAndSend
+	}
+	if err := send(resend); err != nil {
+		return handleStateError(session, err)
+	}
+	session.logResendRequest(resend)
+
+	if replyPending {
+		session.afterEvent(session.LogoutTimeout, internal.LogoutTimeout)
+	}
+
+	state.logoutSeqNum = logoutSeqNum
+	state.replyPending = replyPending
 	return state
 }
 
+// completeAfterResend finishes a logout once the messages missing before the Logout have been received.
+func (state logoutState) completeAfterResend(session *session) sessionState {
+	if session.store.NextTargetMsgSeqNum() == state.logoutSeqNum {
+		if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+			session.logError(err)
+		}
+	}
+
+	if state.replyPending {
+		session.log.OnEvent("Sending logout response")
+		if err := session.dropAndSend(session.buildLogout("")); err != nil {
+			session.logError(err)
+		}
+	}
+
+	if session.ResetOnLogout {
+		if err := session.dropAndReset(); err != nil {
+			session.logError(err)
+		}
+	}
+
+	return latentState{}
+}
+
 func (state logoutState) Timeout(session *session, event internal.Event) (nextState sessionState) {
 	switch event {
 	case internal.LogoutTimeout:
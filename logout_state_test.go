@@ -110,6 +110,7 @@ func (s *LogoutStateTestSuite) TestFixMsgInLogoutResetOnLogout() {
 
 	s.MockApp.On("FromAdmin").Return(nil)
 	s.MockApp.On("OnLogout").Return(nil)
+	s.MessageFactory.SetNextSeqNum(1)
 	s.fixMsgIn(s.session, s.Logout())
 
 	s.MockApp.AssertExpectations(s.T())
@@ -121,6 +122,33 @@ func (s *LogoutStateTestSuite) TestFixMsgInLogoutResetOnLogout() {
 	s.NoMessageQueued()
 }
 
+func (s *LogoutStateTestSuite) TestFixMsgInLogoutTargetTooHigh() {
+	s.MessageFactory.SetNextSeqNum(3)
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.session, s.Logout())
+
+	s.State(logoutState{})
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeResendRequest), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagBeginSeqNo, 1, s.MockApp.lastToAdmin.Body)
+	s.NextTargetMsgSeqNum(1)
+
+	// Resent messages are processed before disconnecting.
+	s.MockApp.On("FromApp").Return(nil)
+	s.MessageFactory.SetNextSeqNum(1)
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+	s.State(logoutState{})
+
+	s.MockApp.On("OnLogout").Return(nil)
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 2)
+	s.State(latentState{})
+	s.NextTargetMsgSeqNum(4)
+	s.NoMessageSent()
+}
+
 func (s *LogoutStateTestSuite) TestStop() {
 	s.session.Stop(s.session)
 	s.State(logoutState{})
@@ -25,6 +25,7 @@ type memoryStore struct {
 	senderMsgSeqNum, targetMsgSeqNum int
 	creationTime                     time.Time
 	messageMap                       map[int][]byte
+	queue                            []QueuedMessage
 }
 
 func (store *memoryStore) NextSenderMsgSeqNum() int {
@@ -108,6 +109,33 @@ func (store *memoryStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]
 	return nil
 }
 
+// PruneMessages deletes the saved messages with a sequence number below beforeSeqNum.
+func (store *memoryStore) PruneMessages(beforeSeqNum int) error {
+	for seqNum := range store.messageMap {
+		if seqNum < beforeSeqNum {
+			delete(store.messageMap, seqNum)
+		}
+	}
+	return nil
+}
+
+// QueueMessage appends msg to the outbound queue.
+func (store *memoryStore) QueueMessage(msg QueuedMessage) error {
+	store.queue = append(store.queue, msg)
+	return nil
+}
+
+// QueuedMessages returns the outbound queue, oldest first.
+func (store *memoryStore) QueuedMessages() ([]QueuedMessage, error) {
+	return append([]QueuedMessage(nil), store.queue...), nil
+}
+
+// ClearQueuedMessages empties the outbound queue.
+func (store *memoryStore) ClearQueuedMessages() error {
+	store.queue = nil
+	return nil
+}
+
 func (store *memoryStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
 	var msgs [][]byte
 	err := store.IterateMessages(beginSeqNum, endSeqNum, func(m []byte) error {
@@ -17,6 +17,7 @@ package quickfix
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"math"
 	"time"
@@ -38,6 +39,24 @@ type msgParser struct {
 	trailerBytes            []byte
 	foundBody               bool
 	foundTrailer            bool
+
+	// preserveOrder keeps the fields of the message, including repeated and unknown tags, in their original order.
+	preserveOrder bool
+	lastBodyTag   Tag
+
+	// dataTag is the Data field expected to follow the Length field just parsed, with a value of dataLength bytes.
+	dataTag    Tag
+	dataLength int
+
+	// appDataDictionaries, keyed by ApplVerID, select the application data dictionary of messages with ApplVerID(1128).
+	appDataDictionaries map[string]*datadictionary.DataDictionary
+}
+
+// selectAppDataDictionary parses the rest of the message with the application data dictionary of applVerID, if any.
+func (mp *msgParser) selectAppDataDictionary(applVerID string) {
+	if dict, ok := mp.appDataDictionaries[normalizeApplVerID(applVerID)]; ok {
+		mp.appDataDictionary = dict
+	}
 }
 
 // in the message header, the first 3 tags in the message header must be 8,9,35.
@@ -117,11 +136,29 @@ type Message struct {
 
 	// Field bytes as they appear in the raw message.
 	fields []TagValue
+
+	ctx context.Context
+
+	// receipt is set by SendToTargetAsync until the message is prepared for send.
+	receipt *SendReceipt
+
+	transient bool
+
+	// restored is set on messages restored from the outbound queue of PersistOutboundQueue, which are built with
+	// their body bytes to keep the order of repeating groups.
+	restored bool
+
+	// reuse is set while the message is owned by the zero allocation parser, which reuses it once processed.
+	reuse bool
 }
 
 // ToMessage returns the message itself.
 func (m *Message) ToMessage() *Message { return m }
 
+// SetTransient marks an outbound message as transient: it is sent and counted in sequence numbers but not saved to
+// the message store, and is gap filled if a resend is requested.
+func (m *Message) SetTransient(transient bool) { m.transient = transient }
+
 // parseError is returned when bytes cannot be parsed as a FIX message.
 type parseError struct {
 	OrigError string
@@ -129,8 +166,12 @@ type parseError struct {
 
 func (e parseError) Error() string { return fmt.Sprintf("error parsing message: %s", e.OrigError) }
 
-// NewMessage returns a newly initialized Message instance.
+// NewMessage returns a newly initialized Message instance, reusing a released Message if there is one.
 func NewMessage() *Message {
+	return messagePool.Get().(*Message)
+}
+
+func newMessage() *Message {
 	m := new(Message)
 	m.Header.Init()
 	m.Body.Init()
@@ -146,6 +187,8 @@ func (m *Message) CopyInto(to *Message) {
 	m.Body.CopyInto(&to.Body.FieldMap)
 	m.Trailer.CopyInto(&to.Trailer.FieldMap)
 
+	to.Header.preserveOrder = m.Header.preserveOrder
+	to.Body.preserveOrder = m.Body.preserveOrder
 	to.ReceiveTime = m.ReceiveTime
 	to.bodyBytes = make([]byte, len(m.bodyBytes))
 	copy(to.bodyBytes, m.bodyBytes)
@@ -166,12 +209,27 @@ func ParseMessageWithDataDictionary(
 	rawMessage *bytes.Buffer,
 	transportDataDictionary *datadictionary.DataDictionary,
 	appDataDictionary *datadictionary.DataDictionary,
+) (err error) {
+	return parseMessage(msg, rawMessage, transportDataDictionary, appDataDictionary, nil, false)
+}
+
+// parseMessage constructs a Message from a byte slice wrapping a FIX message, preserving the order of its fields if
+// preserveOrder is set.
+func parseMessage(
+	msg *Message,
+	rawMessage *bytes.Buffer,
+	transportDataDictionary *datadictionary.DataDictionary,
+	appDataDictionary *datadictionary.DataDictionary,
+	appDataDictionaries map[string]*datadictionary.DataDictionary,
+	preserveOrder bool,
 ) (err error) {
 	// Create msgparser before we go any further.
 	mp := &msgParser{
 		msg:                     msg,
 		transportDataDictionary: transportDataDictionary,
 		appDataDictionary:       appDataDictionary,
+		appDataDictionaries:     appDataDictionaries,
+		preserveOrder:           preserveOrder,
 	}
 	mp.msg.rawMessage = rawMessage
 	mp.rawBytes = rawMessage.Bytes()
@@ -192,6 +250,8 @@ func doParsing(mp *msgParser) (err error) {
 	mp.msg.Header.clearNoLock()
 	mp.msg.Body.clearNoLock()
 	mp.msg.Trailer.clearNoLock()
+	mp.msg.Header.preserveOrder = mp.preserveOrder
+	mp.msg.Body.preserveOrder = mp.preserveOrder
 
 	// Allocate expected message fields in one chunk.
 	fieldCount := bytes.Count(mp.rawBytes, []byte{'\001'})
@@ -229,27 +289,25 @@ func doParsing(mp *msgParser) (err error) {
 
 	// Start parsing.
 	mp.fieldIndex++
-	xmlDataLen := 0
 	xmlDataMsg := false
 	mp.trailerBytes = []byte{}
 	mp.foundBody = false
 	mp.foundTrailer = false
 	for {
 		mp.parsedFieldBytes = &mp.msg.fields[mp.fieldIndex]
-		if xmlDataLen > 0 {
-			mp.rawBytes, err = extractXMLDataField(mp.parsedFieldBytes, mp.rawBytes, xmlDataLen)
-			xmlDataLen = 0
-			xmlDataMsg = true
-		} else {
-			mp.rawBytes, err = extractField(mp.parsedFieldBytes, mp.rawBytes)
-		}
-		if err != nil {
+		if err = mp.extractNextField(); err != nil {
 			return
 		}
+		if mp.parsedFieldBytes.tag == tagXMLData {
+			xmlDataMsg = true
+		}
 
 		switch {
 		case isHeaderField(mp.parsedFieldBytes.tag, mp.transportDataDictionary):
 			mp.msg.Header.add(mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1])
+			if mp.parsedFieldBytes.tag == tagApplVerID {
+				mp.selectAppDataDictionary(string(mp.parsedFieldBytes.value))
+			}
 		case isTrailerField(mp.parsedFieldBytes.tag, mp.transportDataDictionary):
 			mp.msg.Trailer.add(mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1])
 			mp.foundTrailer = true
@@ -258,7 +316,7 @@ func doParsing(mp *msgParser) (err error) {
 		default:
 			mp.foundBody = true
 			mp.trailerBytes = mp.rawBytes
-			mp.msg.Body.add(mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1])
+			mp.addBody(mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1])
 		}
 		if mp.parsedFieldBytes.tag == tagCheckSum {
 			break
@@ -268,9 +326,6 @@ func doParsing(mp *msgParser) (err error) {
 			mp.msg.bodyBytes = mp.rawBytes
 		}
 
-		if mp.parsedFieldBytes.tag == tagXMLDataLen {
-			xmlDataLen, _ = mp.msg.Header.getIntNoLock(tagXMLDataLen)
-		}
 		mp.fieldIndex++
 	}
 
@@ -313,7 +368,7 @@ func parseGroup(mp *msgParser, tags []Tag) {
 	for {
 		mp.fieldIndex++
 		mp.parsedFieldBytes = &mp.msg.fields[mp.fieldIndex]
-		mp.rawBytes, _ = extractField(mp.parsedFieldBytes, mp.rawBytes)
+		_ = mp.extractNextField()
 		mp.trailerBytes = mp.rawBytes
 
 		// Is this field a member for the group.
@@ -329,22 +384,25 @@ func parseGroup(mp *msgParser, tags []Tag) {
 			dm = append(dm, *mp.parsedFieldBytes)
 		} else if isHeaderField(mp.parsedFieldBytes.tag, mp.transportDataDictionary) {
 			// Found a header tag for some reason..
-			mp.msg.Body.add(dm)
+			mp.addBody(dm)
 			mp.msg.Header.add(mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1])
 			break
 		} else if isTrailerField(mp.parsedFieldBytes.tag, mp.transportDataDictionary) {
 			// Found the trailer at the end of the message.
-			mp.msg.Body.add(dm)
+			mp.addBody(dm)
 			mp.msg.Trailer.add(mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1])
 			mp.foundTrailer = true
 			break
+		} else if mp.preserveOrder && !isMessageField(mp.msg, mp.parsedFieldBytes.tag, mp.appDataDictionary) {
+			// Keep a tag unknown to the message in the group it occurs in.
+			dm = append(dm, *mp.parsedFieldBytes)
 		} else {
 			// Found a body field outside the group.
 			searchTags := []Tag{mp.parsedFieldBytes.tag}
 			// Is this a new group not inside the existing group.
 			if isNumInGroupField(mp.msg, searchTags, mp.appDataDictionary) {
 				// Add the current repeating group.
-				mp.msg.Body.add(dm)
+				mp.addBody(dm)
 				// Cycle again with the new group.
 				dm = mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1]
 				fields = getGroupFields(mp.msg, searchTags, mp.appDataDictionary)
@@ -362,15 +420,51 @@ func parseGroup(mp *msgParser, tags []Tag) {
 				continue
 			}
 			// Add the repeating group.
-			mp.msg.Body.add(dm)
+			mp.addBody(dm)
 			// Add the next body field.
-			mp.msg.Body.add(mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1])
+			mp.addBody(mp.msg.fields[mp.fieldIndex : mp.fieldIndex+1])
 
 			break
 		}
 	}
 }
 
+// addBody adds a field to the body. If the order of fields is preserved, a field repeating a tag of the body, e.g. of
+// a repeating group parsed without a DataDictionary, is kept after the field before it rather than replacing the first.
+func (mp *msgParser) addBody(f field) {
+	if mp.preserveOrder {
+		if _, ok := mp.msg.Body.tagLookup[fieldTag(f)]; ok {
+			prev := mp.msg.Body.tagLookup[mp.lastBodyTag]
+			mp.msg.Body.tagLookup[mp.lastBodyTag] = append(prev[:len(prev):len(prev)], f...)
+			return
+		}
+	}
+
+	mp.msg.Body.add(f)
+	mp.lastBodyTag = fieldTag(f)
+}
+
+// isMessageField evaluates if this tag is defined for the message outside of its repeating groups. Tags of
+// messages not in the DataDictionary are assumed to be.
+func isMessageField(msg *Message, tag Tag, appDataDictionary *datadictionary.DataDictionary) bool {
+	if appDataDictionary == nil {
+		return true
+	}
+
+	msgt, err := msg.msgTypeNoLock()
+	if err != nil {
+		return true
+	}
+
+	mm, ok := appDataDictionary.Messages[msgt]
+	if !ok {
+		return true
+	}
+
+	_, ok = mm.Fields[int(tag)]
+	return ok
+}
+
 // isNumInGroupField evaluates if this tag is the start of a repeating group.
 // tags slice will contain multiple tags if the tag in question is found while processing a group already.
 func isNumInGroupField(msg *Message, tags []Tag, appDataDictionary *datadictionary.DataDictionary) bool {
@@ -541,19 +635,6 @@ func extractSpecificField(field *TagValue, expectedTag Tag, buffer []byte) (remB
 	return
 }
 
-func extractXMLDataField(parsedFieldBytes *TagValue, buffer []byte, dataLen int) (remBytes []byte, err error) {
-	endIndex := bytes.IndexByte(buffer, '=')
-	if endIndex == -1 {
-		err = parseError{OrigError: "extractField: No Trailing Delim in " + string(buffer)}
-		remBytes = buffer
-		return
-	}
-	endIndex += dataLen + 1
-
-	err = parsedFieldBytes.parse(buffer[:endIndex+1])
-	return buffer[(endIndex + 1):], err
-}
-
 func extractField(parsedFieldBytes *TagValue, buffer []byte) (remBytes []byte, err error) {
 	endIndex := bytes.IndexByte(buffer, '\001')
 	if endIndex == -1 {
@@ -590,11 +671,13 @@ func formatCheckSum(value int) string {
 func (m *Message) build() []byte {
 	m.cook()
 
-	var b bytes.Buffer
-	m.Header.write(&b)
-	m.Body.write(&b)
-	m.Trailer.write(&b)
-	return b.Bytes()
+	b := getBuffer()
+	defer putBuffer(b)
+
+	m.Header.write(b)
+	m.Body.write(b)
+	m.Trailer.write(b)
+	return append([]byte(nil), b.Bytes()...)
 }
 
 // Constructs a []byte from a Message instance, using the given bodyBytes.
@@ -605,14 +688,20 @@ func (m *Message) build() []byte {
 func (m *Message) buildWithBodyBytes(bodyBytes []byte) []byte {
 	m.cook()
 
-	var b bytes.Buffer
-	m.Header.write(&b)
+	b := getBuffer()
+	defer putBuffer(b)
+
+	m.Header.write(b)
 	b.Write(bodyBytes)
-	m.Trailer.write(&b)
-	return b.Bytes()
+	m.Trailer.write(b)
+	return append([]byte(nil), b.Bytes()...)
 }
 
 func (m *Message) cook() {
+	m.Header.writeGroups()
+	m.Body.writeGroups()
+	m.Trailer.writeGroups()
+
 	bodyLength := m.Header.length() + m.Body.length() + m.Trailer.length()
 	m.Header.SetInt(tagBodyLength, bodyLength)
 	checkSum := (m.Header.total() + m.Body.total() + m.Trailer.total()) % 256
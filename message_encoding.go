@@ -0,0 +1,170 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// Encoding transcodes the values of Encoded fields, such as EncodedText(355), between UTF-8 strings and the
+// character set named by the MessageEncoding(347) of a message.
+type Encoding interface {
+	Encode(text string) ([]byte, error)
+	Decode(data []byte) (string, error)
+}
+
+// NewEncoding returns an Encoding of a character set of golang.org/x/text, e.g. simplifiedchinese.GB18030.
+func NewEncoding(e encoding.Encoding) Encoding {
+	return textEncoding{e}
+}
+
+type textEncoding struct {
+	encoding.Encoding
+}
+
+func (e textEncoding) Encode(text string) ([]byte, error) {
+	return e.NewEncoder().Bytes([]byte(text))
+}
+
+func (e textEncoding) Decode(data []byte) (string, error) {
+	text, err := e.NewDecoder().Bytes(data)
+	return string(text), err
+}
+
+type utf8Encoding struct{}
+
+func (utf8Encoding) Encode(text string) ([]byte, error) {
+	if !utf8.ValidString(text) {
+		return nil, fmt.Errorf("invalid UTF-8 text")
+	}
+	return []byte(text), nil
+}
+
+func (utf8Encoding) Decode(data []byte) (string, error) {
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("invalid UTF-8 data")
+	}
+	return string(data), nil
+}
+
+// UTF8 is the Encoding of the UTF-8 MessageEncoding, and of messages without MessageEncoding.
+var UTF8 Encoding = utf8Encoding{}
+
+var encodingsLock sync.RWMutex
+
+// encodings are the registered Encodings by upper case MessageEncoding.
+var encodings = map[string]Encoding{
+	"UTF-8":       UTF8,
+	"ISO-2022-JP": NewEncoding(japanese.ISO2022JP),
+	"EUC-JP":      NewEncoding(japanese.EUCJP),
+	"SHIFT_JIS":   NewEncoding(japanese.ShiftJIS),
+	"GBK":         NewEncoding(simplifiedchinese.GBK),
+}
+
+// RegisterEncoding registers enc as the Encoding of messages with MessageEncoding(347) messageEncoding, in addition to
+// or in place of the built in UTF-8, ISO-2022-JP, EUC-JP, Shift_JIS and GBK Encodings. MessageEncodings are matched
+// case insensitively.
+func RegisterEncoding(messageEncoding string, enc Encoding) {
+	encodingsLock.Lock()
+	defer encodingsLock.Unlock()
+
+	encodings[strings.ToUpper(messageEncoding)] = enc
+}
+
+// LookupEncoding returns the Encoding registered for MessageEncoding(347) messageEncoding.
+func LookupEncoding(messageEncoding string) (Encoding, bool) {
+	encodingsLock.RLock()
+	defer encodingsLock.RUnlock()
+
+	enc, ok := encodings[strings.ToUpper(messageEncoding)]
+	return enc, ok
+}
+
+// Encoding returns the Encoding of the MessageEncoding(347) of the message header, or UTF8 if it has none.
+func (m *Message) Encoding() (Encoding, MessageRejectError) {
+	if !m.Header.Has(tagMessageEncoding) {
+		return UTF8, nil
+	}
+
+	messageEncoding, err := m.Header.GetString(tagMessageEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	enc, ok := LookupEncoding(messageEncoding)
+	if !ok {
+		return nil, ValueIsIncorrect(tagMessageEncoding)
+	}
+
+	return enc, nil
+}
+
+// SetEncodedString sets the Encoded field dataTag to text encoded by enc, and its Length field lengthTag to the
+// length of the encoded bytes.
+func (m *FieldMap) SetEncodedString(lengthTag, dataTag Tag, text string, enc Encoding) error {
+	data, err := enc.Encode(text)
+	if err != nil {
+		return fmt.Errorf("encoding tag %v: %w", dataTag, err)
+	}
+
+	m.SetData(lengthTag, dataTag, data)
+	return nil
+}
+
+// GetEncodedString returns the Encoded field dataTag decoded by enc. The length of the encoded bytes must be the value
+// of its Length field lengthTag.
+func (m FieldMap) GetEncodedString(lengthTag, dataTag Tag, enc Encoding) (string, MessageRejectError) {
+	data, err := m.GetData(lengthTag, dataTag)
+	if err != nil {
+		return "", err
+	}
+
+	text, decodeErr := enc.Decode(data)
+	if decodeErr != nil {
+		return "", IncorrectDataFormatForValue(dataTag)
+	}
+
+	return text, nil
+}
+
+// SetEncodedString sets the Encoded field dataTag of the body to text encoded by the Encoding of the MessageEncoding
+// of the message, which must be set first, and its Length field lengthTag to the length of the encoded bytes.
+func (m *Message) SetEncodedString(lengthTag, dataTag Tag, text string) error {
+	enc, err := m.Encoding()
+	if err != nil {
+		return err
+	}
+
+	return m.Body.SetEncodedString(lengthTag, dataTag, text, enc)
+}
+
+// GetEncodedString returns the Encoded field dataTag of the body decoded by the Encoding of the MessageEncoding of the
+// message.
+func (m *Message) GetEncodedString(lengthTag, dataTag Tag) (string, MessageRejectError) {
+	enc, err := m.Encoding()
+	if err != nil {
+		return "", err
+	}
+
+	return m.Body.GetEncodedString(lengthTag, dataTag, enc)
+}
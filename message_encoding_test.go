@@ -0,0 +1,141 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+const (
+	tagEncodedTextLen Tag = 354
+	tagEncodedText    Tag = 355
+)
+
+func TestMessageEncodingRoundTrip(t *testing.T) {
+	var tests = []struct {
+		messageEncoding string
+		text            string
+	}{
+		{"", "naïve café"},
+		{"UTF-8", "注文を受け付けました"},
+		{"Shift_JIS", "注文を受け付けました"},
+		{"SHIFT_JIS", "ｶﾀｶﾅ"},
+		{"EUC-JP", "日本語"},
+		{"ISO-2022-JP", "日本語"},
+		{"GBK", "订单已接受"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.messageEncoding, func(t *testing.T) {
+			msg := NewMessage()
+			msg.Header.SetString(tagBeginString, BeginStringFIX44)
+			msg.Header.SetString(tagMsgType, "8")
+			if test.messageEncoding != "" {
+				msg.Header.SetString(tagMessageEncoding, test.messageEncoding)
+			}
+			require.Nil(t, msg.SetEncodedString(tagEncodedTextLen, tagEncodedText, test.text))
+			msg.Body.SetString(tagText, test.text)
+
+			raw := msg.build()
+			parsed := NewMessage()
+			require.Nil(t, ParseMessage(parsed, bytes.NewBuffer(raw)))
+
+			text, err := parsed.GetEncodedString(tagEncodedTextLen, tagEncodedText)
+			require.Nil(t, err)
+			assert.Equal(t, test.text, text)
+
+			plain, err := parsed.Body.GetString(tagText)
+			require.Nil(t, err)
+			assert.Equal(t, test.text, plain)
+		})
+	}
+}
+
+func TestMessageEncodingBodyLengthAndCheckSum(t *testing.T) {
+	msg := NewMessage()
+	msg.Header.SetString(tagBeginString, BeginStringFIX44)
+	msg.Header.SetString(tagMsgType, "8")
+	msg.Header.SetString(tagMessageEncoding, "Shift_JIS")
+	require.Nil(t, msg.SetEncodedString(tagEncodedTextLen, tagEncodedText, "注文"))
+	raw := msg.build()
+
+	// The BodyLength and CheckSum count bytes, not runes.
+	bodyStart := bytes.Index(raw, []byte("\x0135=")) + 1
+	bodyEnd := bytes.Index(raw, []byte("10="))
+	bodyLength, err := msg.Header.GetInt(tagBodyLength)
+	require.Nil(t, err)
+	assert.Equal(t, bodyEnd-bodyStart, bodyLength)
+
+	sum := 0
+	for _, b := range raw[:bodyEnd] {
+		sum += int(b)
+	}
+	checkSum, err := msg.Trailer.GetString(tagCheckSum)
+	require.Nil(t, err)
+	assert.Equal(t, formatCheckSum(sum%256), checkSum)
+
+	length, err := msg.Body.GetInt(tagEncodedTextLen)
+	require.Nil(t, err)
+	assert.Equal(t, 4, length)
+	assert.Contains(t, string(raw), "354="+strconv.Itoa(length)+"\x01")
+}
+
+func TestMessageEncodingErrors(t *testing.T) {
+	msg := NewMessage()
+	msg.Header.SetString(tagMessageEncoding, "EBCDIC")
+	_, err := msg.Encoding()
+	assert.NotNil(t, err)
+	assert.NotNil(t, msg.SetEncodedString(tagEncodedTextLen, tagEncodedText, "text"))
+
+	// The Length field must be the length of the encoded bytes.
+	msg = NewMessage()
+	msg.Body.SetInt(tagEncodedTextLen, 2).SetString(tagEncodedText, "注文")
+	_, err = msg.GetEncodedString(tagEncodedTextLen, tagEncodedText)
+	assert.NotNil(t, err)
+
+	msg.Body.SetData(tagEncodedTextLen, tagEncodedText, []byte{0xff, 0xfe})
+	_, err = msg.GetEncodedString(tagEncodedTextLen, tagEncodedText)
+	assert.NotNil(t, err)
+
+	assert.NotNil(t, msg.Body.SetEncodedString(tagEncodedTextLen, tagEncodedText, "\xff", UTF8))
+}
+
+func TestRegisterEncoding(t *testing.T) {
+	_, ok := LookupEncoding("GB18030")
+	require.False(t, ok)
+
+	RegisterEncoding("GB18030", NewEncoding(simplifiedchinese.GB18030))
+	defer func() {
+		encodingsLock.Lock()
+		delete(encodings, "GB18030")
+		encodingsLock.Unlock()
+	}()
+
+	enc, ok := LookupEncoding("gb18030")
+	require.True(t, ok)
+
+	msg := NewMessage()
+	require.Nil(t, msg.Body.SetEncodedString(tagEncodedTextLen, tagEncodedText, "订单", enc))
+	text, err := msg.Body.GetEncodedString(tagEncodedTextLen, tagEncodedText, enc)
+	require.Nil(t, err)
+	assert.Equal(t, "订单", text)
+}
@@ -0,0 +1,410 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// The sections of a message in the FIX JSON encoding.
+const (
+	jsonHeader  = "Header"
+	jsonBody    = "Body"
+	jsonTrailer = "Trailer"
+)
+
+// MarshalJSON encodes the message in the FIX JSON encoding, naming fields by their tags.
+// See MarshalJSONWithDataDictionary to name fields and nest repeating groups by a DataDictionary.
+func (m *Message) MarshalJSON() ([]byte, error) {
+	return MarshalJSONWithDataDictionary(m, nil, nil)
+}
+
+// UnmarshalJSON decodes the message from the FIX JSON encoding, with fields named by their tags.
+// See UnmarshalJSONWithDataDictionary to decode fields named by a DataDictionary.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	return UnmarshalJSONWithDataDictionary(m, data, nil, nil)
+}
+
+// MarshalJSONWithDataDictionary encodes msg in the FIX JSON encoding, naming fields and nesting repeating groups by
+// the transport and application data dictionaries. Fields not in the dictionaries are named by their tags, and the
+// transport data dictionary is only needed for FIXT.1.1 messages. Values are encoded as strings, repeating groups as
+// arrays of objects, and BodyLength and CheckSum are left out.
+func MarshalJSONWithDataDictionary(
+	msg *Message,
+	transportDataDictionary *datadictionary.DataDictionary,
+	appDataDictionary *datadictionary.DataDictionary,
+) ([]byte, error) {
+	msg.Header.writeGroups()
+	msg.Body.writeGroups()
+	msg.Trailer.writeGroups()
+
+	d := jsonDictionaries{transport: transportDataDictionary, app: appDataDictionary}
+	msgType, _ := msg.Header.GetString(tagMsgType)
+
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	d.writeFieldMap(&buffer, jsonHeader, &msg.Header.FieldMap, d.headerDef())
+	buffer.WriteByte(',')
+	d.writeFieldMap(&buffer, jsonBody, &msg.Body.FieldMap, d.bodyDef(msgType))
+	buffer.WriteByte(',')
+	d.writeFieldMap(&buffer, jsonTrailer, &msg.Trailer.FieldMap, d.trailerDef())
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSONWithDataDictionary decodes msg from the FIX JSON encoding, with fields named by the transport and
+// application data dictionaries or by their tags. BodyLength and CheckSum are set when the message is built.
+func UnmarshalJSONWithDataDictionary(
+	msg *Message,
+	data []byte,
+	transportDataDictionary *datadictionary.DataDictionary,
+	appDataDictionary *datadictionary.DataDictionary,
+) error {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return err
+	}
+
+	d := jsonDictionaries{transport: transportDataDictionary, app: appDataDictionary}
+	msg.Header.Clear()
+	msg.Body.Clear()
+	msg.Trailer.Clear()
+	msg.rawMessage = nil
+	msg.bodyBytes = nil
+	msg.fields = msg.fields[:0]
+
+	for _, section := range []struct {
+		name     string
+		fieldMap *FieldMap
+	}{
+		{jsonHeader, &msg.Header.FieldMap},
+		{jsonBody, &msg.Body.FieldMap},
+		{jsonTrailer, &msg.Trailer.FieldMap},
+	} {
+		raw, ok := sections[section.name]
+		if !ok {
+			continue
+		}
+
+		fields, err := decodeJSONObject(json.NewDecoder(bytes.NewReader(raw)))
+		if err != nil {
+			return fmt.Errorf("fix json: %v: %w", section.name, err)
+		}
+
+		if err := d.addFields(section.fieldMap, fields); err != nil {
+			return fmt.Errorf("fix json: %v: %w", section.name, err)
+		}
+	}
+
+	return nil
+}
+
+// jsonDictionaries name the fields of messages in the FIX JSON encoding. Either dictionary may be nil.
+type jsonDictionaries struct {
+	transport, app *datadictionary.DataDictionary
+}
+
+func (d jsonDictionaries) headerDef() *datadictionary.MessageDef {
+	switch {
+	case d.transport != nil:
+		return d.transport.Header
+	case d.app != nil:
+		return d.app.Header
+	}
+
+	return nil
+}
+
+func (d jsonDictionaries) bodyDef(msgType string) *datadictionary.MessageDef {
+	if d.app != nil {
+		if def, ok := d.app.Messages[msgType]; ok {
+			return def
+		}
+	}
+
+	if d.transport != nil {
+		return d.transport.Messages[msgType]
+	}
+
+	return nil
+}
+
+func (d jsonDictionaries) trailerDef() *datadictionary.MessageDef {
+	switch {
+	case d.transport != nil:
+		return d.transport.Trailer
+	case d.app != nil:
+		return d.app.Trailer
+	}
+
+	return nil
+}
+
+// name returns the name of the field with tag, or the tag if it is not in the dictionaries.
+func (d jsonDictionaries) name(tag Tag) string {
+	for _, dict := range []*datadictionary.DataDictionary{d.app, d.transport} {
+		if dict == nil {
+			continue
+		}
+
+		if fieldType, ok := dict.FieldTypeByTag[int(tag)]; ok {
+			return fieldType.Name()
+		}
+	}
+
+	return strconv.Itoa(int(tag))
+}
+
+// tag returns the tag of the field named name, which may also be the tag.
+func (d jsonDictionaries) tag(name string) (Tag, error) {
+	if tag, err := strconv.Atoi(name); err == nil && tag > 0 {
+		return Tag(tag), nil
+	}
+
+	for _, dict := range []*datadictionary.DataDictionary{d.app, d.transport} {
+		if dict == nil {
+			continue
+		}
+
+		if fieldType, ok := dict.FieldTypeByName[name]; ok {
+			return Tag(fieldType.Tag()), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown field %q", name)
+}
+
+// writeFieldMap writes the fields of fieldMap as the member section of the enclosing object.
+func (d jsonDictionaries) writeFieldMap(buffer *bytes.Buffer, section string, fieldMap *FieldMap, def *datadictionary.MessageDef) {
+	fieldMap.rwLock.Lock()
+	defer fieldMap.rwLock.Unlock()
+
+	writeJSONString(buffer, section)
+	buffer.WriteString(":{")
+	first := true
+	for _, tag := range fieldMap.sortedTags() {
+		f, ok := fieldMap.tagLookup[tag]
+		if !ok || tag == tagBodyLength || tag == tagCheckSum {
+			continue
+		}
+
+		if !first {
+			buffer.WriteByte(',')
+		}
+		first = false
+
+		if len(f) == 1 {
+			d.writeField(buffer, f[0])
+			continue
+		}
+
+		var groupDef *datadictionary.FieldDef
+		if def != nil {
+			groupDef = def.Fields[int(tag)]
+		}
+		writeJSONString(buffer, d.name(tag))
+		buffer.WriteByte(':')
+		for _, tv := range d.writeGroup(buffer, f[1:], groupDef) {
+			// Fields kept with the group that are not members of it.
+			buffer.WriteByte(',')
+			d.writeField(buffer, tv)
+		}
+	}
+	buffer.WriteByte('}')
+}
+
+func (d jsonDictionaries) writeField(buffer *bytes.Buffer, tv TagValue) {
+	writeJSONString(buffer, d.name(tv.tag))
+	buffer.WriteByte(':')
+	writeJSONString(buffer, string(tv.value))
+}
+
+// writeGroup writes the instances of a repeating group as an array, fields being the fields after its NumInGroup
+// field. Without a definition of the group its instances are delimited by the first field, and nested groups are not
+// told apart. It returns the fields after the group.
+func (d jsonDictionaries) writeGroup(buffer *bytes.Buffer, fields []TagValue, def *datadictionary.FieldDef) []TagValue {
+	buffer.WriteByte('[')
+	if len(fields) == 0 {
+		buffer.WriteByte(']')
+		return fields
+	}
+
+	delimiter := fields[0].tag
+	var members map[Tag]*datadictionary.FieldDef
+	if def != nil && len(def.Fields) > 0 {
+		delimiter = Tag(def.Fields[0].Tag())
+		members = make(map[Tag]*datadictionary.FieldDef, len(def.Fields))
+		for _, member := range def.Fields {
+			members[Tag(member.Tag())] = member
+		}
+	}
+
+	for instance := 0; len(fields) > 0 && fields[0].tag == delimiter; instance++ {
+		if instance > 0 {
+			buffer.WriteByte(',')
+		}
+		buffer.WriteByte('{')
+		for i := 0; len(fields) > 0; i++ {
+			tv := fields[0]
+			member, isMember := members[tv.tag]
+			if i > 0 && (tv.tag == delimiter || (members != nil && !isMember)) {
+				break
+			}
+
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+
+			if isMember && member.IsGroup() {
+				writeJSONString(buffer, d.name(tv.tag))
+				buffer.WriteByte(':')
+				fields = d.writeGroup(buffer, fields[1:], member)
+				continue
+			}
+
+			d.writeField(buffer, tv)
+			fields = fields[1:]
+		}
+		buffer.WriteByte('}')
+	}
+	buffer.WriteByte(']')
+
+	return fields
+}
+
+func writeJSONString(buffer *bytes.Buffer, s string) {
+	b, _ := json.Marshal(s)
+	buffer.Write(b)
+}
+
+// jsonField is a member of an object of the FIX JSON encoding, with either a value or the instances of a group.
+type jsonField struct {
+	name  string
+	value string
+	group [][]jsonField
+}
+
+// decodeJSONObject decodes the next object of dec, keeping the order of its members.
+func decodeJSONObject(dec *json.Decoder) ([]jsonField, error) {
+	dec.UseNumber()
+	if err := expectJSONDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var fields []jsonField
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		f := jsonField{name: token.(string)}
+
+		if token, err = dec.Token(); err != nil {
+			return nil, err
+		}
+
+		switch v := token.(type) {
+		case string:
+			f.value = v
+		case json.Number:
+			f.value = v.String()
+		case json.Delim:
+			if v != '[' {
+				return nil, fmt.Errorf("field %q must be a string or an array of groups", f.name)
+			}
+
+			f.group = [][]jsonField{}
+			for dec.More() {
+				instance, err := decodeJSONObject(dec)
+				if err != nil {
+					return nil, err
+				}
+				f.group = append(f.group, instance)
+			}
+
+			if err := expectJSONDelim(dec, ']'); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("field %q must be a string or an array of groups", f.name)
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields, expectJSONDelim(dec, '}')
+}
+
+func expectJSONDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != delim {
+		return fmt.Errorf("expected %v, got %v", delim, token)
+	}
+
+	return nil
+}
+
+// addFields adds decoded fields to fieldMap, with each repeating group following its NumInGroup field.
+func (d jsonDictionaries) addFields(fieldMap *FieldMap, fields []jsonField) error {
+	fieldMap.rwLock.Lock()
+	defer fieldMap.rwLock.Unlock()
+
+	for _, f := range fields {
+		tvs, err := d.appendField(nil, f)
+		if err != nil {
+			return err
+		}
+
+		fieldMap.add(tvs)
+	}
+
+	return nil
+}
+
+func (d jsonDictionaries) appendField(tvs field, f jsonField) (field, error) {
+	tag, err := d.tag(f.name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tv TagValue
+	if f.group == nil {
+		tv.init(tag, []byte(f.value))
+		return append(tvs, tv), nil
+	}
+
+	tv.init(tag, []byte(strconv.Itoa(len(f.group))))
+	tvs = append(tvs, tv)
+	for _, instance := range f.group {
+		for _, member := range instance {
+			if tvs, err = d.appendField(tvs, member); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return tvs, nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// jsonTestMsg returns a FIX.4.4 MarketDataSnapshotFullRefresh with the repeating groups NoSecurityAltID and
+// NoMDEntries.
+func jsonTestMsg() []byte {
+	msg := NewMessage()
+	msg.Header.SetString(tagBeginString, BeginStringFIX44).
+		SetString(tagMsgType, "W").
+		SetString(tagSenderCompID, "TW").
+		SetString(tagTargetCompID, "ISLD").
+		SetInt(tagMsgSeqNum, 3).
+		SetString(tagSendingTime, "20240101-00:00:00.000")
+	msg.Body.SetString(Tag(262), "req\"1").
+		SetString(Tag(55), "EUR/USD")
+
+	altIDs := NewRepeatingGroup(Tag(454), GroupTemplate{GroupElement(Tag(455)), GroupElement(Tag(456))})
+	altIDs.Add().SetString(Tag(455), "EURUSD").SetString(Tag(456), "8")
+	altIDs.Add().SetString(Tag(455), "EUR=")
+	msg.Body.SetGroup(altIDs)
+
+	entries := NewRepeatingGroup(Tag(268), GroupTemplate{GroupElement(Tag(269)), GroupElement(Tag(270))})
+	entries.Add().SetString(Tag(269), "0").SetString(Tag(270), "1.1")
+	entries.Add().SetString(Tag(269), "1").SetString(Tag(270), "1.2")
+	msg.Body.SetGroup(entries)
+
+	return msg.build()
+}
+
+func TestMarshalJSONWithDataDictionary(t *testing.T) {
+	dict, err := datadictionary.Parse("spec/FIX44.xml")
+	require.Nil(t, err)
+
+	raw := jsonTestMsg()
+	msg := NewMessage()
+	require.Nil(t, ParseMessageWithDataDictionary(msg, bytes.NewBuffer(raw), dict, dict))
+
+	data, err := MarshalJSONWithDataDictionary(msg, nil, dict)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{
+		"Header": {"BeginString": "FIX.4.4", "MsgType": "W", "SenderCompID": "TW", "TargetCompID": "ISLD",
+			"MsgSeqNum": "3", "SendingTime": "20240101-00:00:00.000"},
+		"Body": {"Symbol": "EUR/USD", "MDReqID": "req\"1",
+			"NoMDEntries": [{"MDEntryType": "0", "MDEntryPx": "1.1"}, {"MDEntryType": "1", "MDEntryPx": "1.2"}],
+			"NoSecurityAltID": [{"SecurityAltID": "EURUSD", "SecurityAltIDSource": "8"}, {"SecurityAltID": "EUR="}]},
+		"Trailer": {}
+	}`, string(data))
+
+	unmarshaled := NewMessage()
+	require.Nil(t, UnmarshalJSONWithDataDictionary(unmarshaled, data, nil, dict))
+	assert.Equal(t, string(raw), string(unmarshaled.build()))
+}
+
+func TestMarshalJSON(t *testing.T) {
+	msg := NewMessage()
+	msg.Header.SetString(tagBeginString, BeginStringFIX44).
+		SetString(tagMsgType, "W").
+		SetString(tagSenderCompID, "TW")
+	msg.Body.SetString(Tag(262), "req\"1")
+
+	entries := NewRepeatingGroup(Tag(268), GroupTemplate{GroupElement(Tag(269)), GroupElement(Tag(270))})
+	entries.Add().SetString(Tag(269), "0").SetString(Tag(270), "1.1")
+	entries.Add().SetString(Tag(269), "1")
+	msg.Body.SetGroup(entries)
+
+	data, err := json.Marshal(msg)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{
+		"Header": {"8": "FIX.4.4", "35": "W", "49": "TW"},
+		"Body": {"262": "req\"1", "268": [{"269": "0", "270": "1.1"}, {"269": "1"}]},
+		"Trailer": {}
+	}`, string(data))
+
+	unmarshaled := NewMessage()
+	require.Nil(t, json.Unmarshal(data, unmarshaled))
+	assert.Equal(t, string(msg.build()), string(unmarshaled.build()))
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	dict, err := datadictionary.Parse("spec/FIX44.xml")
+	require.Nil(t, err)
+
+	var tests = []string{
+		`[]`,
+		`{"Header": {"Unknown": "1"}}`,
+		`{"Body": {"Symbol": true}}`,
+		`{"Body": {"NoMDEntries": {"MDEntryType": "0"}}}`,
+		`{"Body": {"NoMDEntries": ["0"]}}`,
+	}
+
+	for _, test := range tests {
+		assert.NotNil(t, UnmarshalJSONWithDataDictionary(NewMessage(), []byte(test), nil, dict), test)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// maxPooledBufferSize bounds the serialization buffers kept for reuse, so that an occasional large message does not
+// pin its buffer.
+const maxPooledBufferSize = 64 * 1024
+
+var (
+	messagePool = sync.Pool{New: func() any { return newMessage() }}
+	bufferPool  = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+	tagSetPool  = sync.Pool{New: func() any { return make(datadictionary.TagSet) }}
+)
+
+// Release gives the message back to be reused by NewMessage, along with its field maps, so that GC pressure does not
+// grow with the message rate. Releasing is optional, messages that are not released are garbage collected as before.
+//
+// The message must not be used after Release. Release must not be called on a message still held by the engine: one
+// passed to an Application callback, which belongs to the engine and is released by it once the callback returns
+// unless ZeroAllocParser is N, so should be copied with CopyInto to be retained past the callback, or one queued by
+// a ThrottleMode of Queue until it is sent. A message passed to SendToTarget may
+// otherwise be released once SendToTarget returns.
+func (m *Message) Release() {
+	m.reset()
+	messagePool.Put(m)
+}
+
+func (m *Message) reset() {
+	m.Header.Clear()
+	m.Body.Clear()
+	m.Trailer.Clear()
+	m.Header.preserveOrder = false
+	m.Body.preserveOrder = false
+
+	m.ReceiveTime = time.Time{}
+	m.rawMessage = nil
+	m.bodyBytes = nil
+	clear(m.fields)
+	m.fields = m.fields[:0]
+	m.ctx = nil
+	m.receipt = nil
+	m.transient = false
+	m.reuse = false
+	m.restored = false
+}
+
+func getBuffer() *bytes.Buffer {
+	b := bufferPool.Get().(*bytes.Buffer)
+	b.Reset()
+
+	return b
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(b)
+	}
+}
+
+func getTagSet() datadictionary.TagSet {
+	return tagSetPool.Get().(datadictionary.TagSet)
+}
+
+func putTagSet(t datadictionary.TagSet) {
+	clear(t)
+	tagSetPool.Put(t)
+}
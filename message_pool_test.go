@@ -0,0 +1,99 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func BenchmarkNewMessageRelease(b *testing.B) {
+	rawMsg := bytes.NewBufferString(zeroAllocTestMsg)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg := NewMessage()
+		_ = ParseMessage(msg, rawMsg)
+		msg.Release()
+	}
+}
+
+func BenchmarkMessageBuild(b *testing.B) {
+	msg := NewMessage()
+	require.Nil(b, ParseMessage(msg, bytes.NewBufferString(zeroAllocTestMsg)))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = msg.build()
+	}
+}
+
+func TestMessageReset(t *testing.T) {
+	msg := NewMessage()
+	require.Nil(t, ParseMessage(msg, bytes.NewBufferString(zeroAllocTestMsg)))
+	msg.SetTransient(true)
+	msg.restored = true
+
+	msg.reset()
+	assert.Empty(t, msg.Header.Tags())
+	assert.Empty(t, msg.Body.Tags())
+	assert.Empty(t, msg.Trailer.Tags())
+	assert.Empty(t, msg.fields)
+	assert.Nil(t, msg.rawMessage)
+	assert.Nil(t, msg.bodyBytes)
+	assert.False(t, msg.transient)
+	assert.False(t, msg.restored)
+	assert.True(t, msg.ReceiveTime.IsZero())
+
+	msg.Header.SetField(tagMsgType, FIXString("0"))
+	msgType, err := msg.MsgType()
+	require.Nil(t, err)
+	assert.Equal(t, "0", msgType)
+
+	require.Nil(t, ParseMessage(msg, bytes.NewBufferString(zeroAllocTestMsg)))
+	assert.Equal(t, zeroAllocTestMsg, msg.String())
+}
+
+func TestMessageBuildDoesNotAliasBuffer(t *testing.T) {
+	msg := NewMessage()
+	require.Nil(t, ParseMessage(msg, bytes.NewBufferString(zeroAllocTestMsg)))
+
+	first := msg.build()
+	expected := string(first)
+	second := msg.build()
+	copy(first, "XXXX")
+
+	assert.Equal(t, expected, string(second))
+}
+
+func TestPutBufferDropsLargeBuffers(t *testing.T) {
+	b := getBuffer()
+	b.Grow(2 * maxPooledBufferSize)
+	putBuffer(b)
+
+	assert.Zero(t, getBuffer().Len())
+}
+
+func TestPutTagSetClears(t *testing.T) {
+	tags := getTagSet()
+	tags.Add(35)
+	putTagSet(tags)
+
+	assert.Empty(t, tags)
+}
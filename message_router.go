@@ -34,12 +34,24 @@ const (
 	ApplVerIDFIX50SP2 = "9"
 )
 
+// RouteAny is a wildcard matching any begin string or msgType of a route, or any value of a field of the SessionID
+// of a session route.
+const RouteAny = "*"
+
 // A MessageRoute is a function that can process a fromApp/fromAdmin callback.
 type MessageRoute func(msg *Message, sessionID SessionID) MessageRejectError
 
+type sessionRoute struct {
+	sessionID SessionID
+	msgType   string
+	route     MessageRoute
+}
+
 // A MessageRouter is a mutex for MessageRoutes.
 type MessageRouter struct {
-	routes map[routeKey]MessageRoute
+	routes        map[routeKey]MessageRoute
+	sessionRoutes []sessionRoute
+	unhandled     MessageRoute
 }
 
 // NewMessageRouter returns an initialized MessageRouter instance.
@@ -48,11 +60,31 @@ func NewMessageRouter() *MessageRouter {
 }
 
 // AddRoute adds a route to the MessageRouter instance keyed to begin string and msgType.
+//
+// Either may be RouteAny: a route for RouteAny and a msgType is the default route of the msgType for all begin
+// strings, and a route for a begin string and RouteAny is the fallback route of the application messages of the begin
+// string. A route keyed to both begin string and msgType takes precedence over the default route of the msgType,
+// which takes precedence over the fallback route of the begin string, and then the route for RouteAny and RouteAny.
+// Fallback routes do not receive admin messages.
 func (c MessageRouter) AddRoute(beginString string, msgType string, router MessageRoute) {
 	c.routes[routeKey{beginString, msgType}] = router
 }
 
-// Route may be called from the fromApp/fromAdmin callbacks. Messages that cannot be routed will be rejected with UnsupportedMessageType.
+// AddSessionRoute adds a route for the messages of msgType received by the sessions matching sessionID. Any field of
+// sessionID and the msgType may be RouteAny. Session routes take precedence over the routes added with AddRoute, and
+// are tried in the order they were added.
+func (c *MessageRouter) AddSessionRoute(sessionID SessionID, msgType string, router MessageRoute) {
+	c.sessionRoutes = append(c.sessionRoutes, sessionRoute{sessionID: sessionID, msgType: msgType, route: router})
+}
+
+// SetUnhandled sets the route of the application messages that cannot be routed, in place of rejecting them with
+// UnsupportedMessageType. The raw message as received is available with msg.Bytes(), e.g. to relay it unchanged.
+func (c *MessageRouter) SetUnhandled(router MessageRoute) {
+	c.unhandled = router
+}
+
+// Route may be called from the fromApp/fromAdmin callbacks. Messages that cannot be routed will be rejected with
+// UnsupportedMessageType, unless they are admin messages or a route is set with SetUnhandled.
 func (c MessageRouter) Route(msg *Message, sessionID SessionID) MessageRejectError {
 	beginString, err := msg.Header.GetBytes(tagBeginString)
 	if err != nil {
@@ -94,14 +126,65 @@ func (c MessageRouter) tryRoute(beginString string, msgType string, msg *Message
 		}
 	}
 
+	for _, r := range c.sessionRoutes {
+		if r.matches(sessionID, msgType, isAdminMsg) {
+			return r.route(msg, sessionID)
+		}
+	}
+
 	if route, ok := c.routes[routeKey{fixVersion, msgType}]; ok {
 		return route(msg, sessionID)
 	}
 
-	switch {
-	case isAdminMsg || msgType == "j":
+	if route, ok := c.routes[routeKey{RouteAny, msgType}]; ok {
+		return route(msg, sessionID)
+	}
+
+	if isAdminMsg {
+		return nil
+	}
+
+	if route, ok := c.routes[routeKey{fixVersion, RouteAny}]; ok {
+		return route(msg, sessionID)
+	}
+
+	if route, ok := c.routes[routeKey{RouteAny, RouteAny}]; ok {
+		return route(msg, sessionID)
+	}
+
+	if c.unhandled != nil {
+		return c.unhandled(msg, sessionID)
+	}
+
+	if msgType == "j" {
 		return nil
 	}
 
 	return UnsupportedMessageType()
 }
+
+// matches returns true if the route is for the msgType of the messages of sessionID. Wildcard routes do not match
+// admin messages.
+func (r sessionRoute) matches(sessionID SessionID, msgType string, isAdminMsg bool) bool {
+	if r.msgType == RouteAny {
+		if isAdminMsg {
+			return false
+		}
+	} else if r.msgType != msgType {
+		return false
+	}
+
+	pattern := r.sessionID
+	return matchesRouteField(pattern.BeginString, sessionID.BeginString) &&
+		matchesRouteField(pattern.SenderCompID, sessionID.SenderCompID) &&
+		matchesRouteField(pattern.SenderSubID, sessionID.SenderSubID) &&
+		matchesRouteField(pattern.SenderLocationID, sessionID.SenderLocationID) &&
+		matchesRouteField(pattern.TargetCompID, sessionID.TargetCompID) &&
+		matchesRouteField(pattern.TargetSubID, sessionID.TargetSubID) &&
+		matchesRouteField(pattern.TargetLocationID, sessionID.TargetLocationID) &&
+		matchesRouteField(pattern.Qualifier, sessionID.Qualifier)
+}
+
+func matchesRouteField(pattern, value string) bool {
+	return pattern == RouteAny || pattern == value
+}
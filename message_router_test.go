@@ -205,3 +205,75 @@ func (suite *MessageRouterTestSuite) TestRouteFIXTAppWithDefaultApplVerID() {
 	suite.verifyMessageRoutedBy(ApplVerIDFIX50SP1, "D")
 	suite.Nil(rej)
 }
+
+func (suite *MessageRouterTestSuite) TestMsgTypeDefaultRoute() {
+	suite.givenTheRoute(RouteAny, "D")
+	suite.givenTheRoute(string(BeginStringFIX42), RouteAny)
+
+	suite.givenAFIX42NewOrderSingle()
+	rej := suite.Route(suite.msg, suite.sessionID)
+
+	suite.verifyMessageRoutedBy(RouteAny, "D")
+	suite.Nil(rej)
+}
+
+func (suite *MessageRouterTestSuite) TestExactRouteBeforeDefaultRoute() {
+	suite.givenTheRoute(RouteAny, "D")
+	suite.givenTheRoute(string(BeginStringFIX42), "D")
+
+	suite.givenAFIX42NewOrderSingle()
+	suite.Nil(suite.Route(suite.msg, suite.sessionID))
+	suite.verifyMessageRoutedBy(string(BeginStringFIX42), "D")
+}
+
+func (suite *MessageRouterTestSuite) TestBeginStringFallbackRoute() {
+	suite.givenTheRoute(string(BeginStringFIX42), RouteAny)
+	suite.givenTheRoute(RouteAny, RouteAny)
+
+	suite.givenAFIX42NewOrderSingle()
+	suite.Nil(suite.Route(suite.msg, suite.sessionID))
+	suite.verifyMessageRoutedBy(string(BeginStringFIX42), RouteAny)
+
+	suite.resetRouter()
+	suite.givenTheRoute(RouteAny, RouteAny)
+	suite.Nil(suite.Route(suite.msg, suite.sessionID))
+	suite.verifyMessageRoutedBy(RouteAny, RouteAny)
+}
+
+func (suite *MessageRouterTestSuite) TestFallbackRouteSkipsAdminMessages() {
+	suite.givenTheRoute(RouteAny, RouteAny)
+
+	suite.givenTheMessage([]byte("8=FIX.4.3\x019=87\x0135=0\x0149=TW\x0134=3\x0156=ISLD\x0152=20160421-14:43:50\x0140=1\x0160=20160421-14:43:50\x0154=1\x0121=3\x0111=id\x0110=235\x01"))
+	suite.Nil(suite.Route(suite.msg, suite.sessionID))
+	suite.verifyMessageNotRouted()
+}
+
+func (suite *MessageRouterTestSuite) TestSessionRoute() {
+	suite.givenTheRoute(string(BeginStringFIX42), "D")
+	suite.AddSessionRoute(SessionID{BeginString: RouteAny, SenderCompID: "ISLD", TargetCompID: RouteAny}, RouteAny,
+		func(msg *Message, sessionID SessionID) MessageRejectError {
+			suite.routedBy = "session"
+			return nil
+		})
+	suite.AddSessionRoute(SessionID{BeginString: RouteAny, SenderCompID: "OTHER", TargetCompID: RouteAny}, RouteAny,
+		func(msg *Message, sessionID SessionID) MessageRejectError {
+			suite.routedBy = "other"
+			return nil
+		})
+
+	suite.givenAFIX42NewOrderSingle()
+	suite.Nil(suite.Route(suite.msg, suite.sessionID))
+	suite.Equal("session", suite.routedBy)
+}
+
+func (suite *MessageRouterTestSuite) TestUnhandled() {
+	suite.SetUnhandled(func(msg *Message, sessionID SessionID) MessageRejectError {
+		suite.routedBy = string(msg.Bytes())
+		return nil
+	})
+
+	raw := "8=FIX.4.3\x019=87\x0135=D\x0149=TW\x0134=3\x0156=ISLD\x0152=20160421-14:43:50\x0140=1\x0160=20160421-14:43:50\x0154=1\x0121=3\x0111=id\x0110=235\x01"
+	suite.givenTheMessage([]byte(raw))
+	suite.Nil(suite.Route(suite.msg, suite.sessionID))
+	suite.Equal(raw, suite.routedBy)
+}
@@ -18,6 +18,7 @@ package quickfix
 import (
 	"bytes"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -515,3 +516,37 @@ func checkFieldString(s *MessageSuite, fields FieldMap, tag int, expected string
 	s.NoError(err)
 	s.Equal(expected, toCheck)
 }
+
+func (s *MessageSuite) TestPreserveFieldsOrderWithDictionary() {
+	dict, dictErr := datadictionary.Parse("spec/FIX44.xml")
+	s.Nil(dictErr)
+
+	// Given message bytes with fields out of tag order and an unknown tag, 9001, inside the 386 repeating group.
+	raw := "8=FIX.4.4\x019=217\x0135=D\x0134=2\x01347=UTF-8\x0152=20231231-20:19:41\x0149=01001\x0150=01001a\x0156=TEST\x0144=12\x0111=13976\x011=10100400\x0121=1\x01386=1\x01336=NOPL\x019001=X\x0155=SYMABC\x0154=1\x0160=20231231-20:19:41\x0138=1\x0140=2\x0159=0\x01453=1\x01448=4501\x01447=D\x01452=28\x01354=6\x01355=Public\x0110=104\x01"
+
+	s.Nil(parseMessage(s.msg, bytes.NewBufferString(raw), dict, dict, nil, true))
+	s.Equal(raw[:len(raw)-7], string(s.msg.build()[:len(raw)-7]), "fields should be rebuilt in their original order")
+
+	// When a field is edited it keeps its position.
+	s.msg.Body.SetString(Tag(55), "SYMXYZ")
+	rebuilt := NewMessage()
+	s.Nil(parseMessage(rebuilt, bytes.NewBuffer(s.msg.build()), dict, dict, nil, true))
+	s.Equal(strings.Replace(raw[:len(raw)-7], "SYMABC", "SYMXYZ", 1), rebuilt.String()[:len(raw)-7])
+}
+
+func (s *MessageSuite) TestPreserveFieldsOrderWithoutDictionary() {
+	// Given message bytes with a repeating group, 268, and a custom tag, 5001, before other body fields.
+	raw := "8=FIX.4.4\x019=63\x0135=X\x0134=2\x015001=venue\x01268=2\x01279=0\x01270=1.5\x01279=1\x01270=1.6\x0158=done\x0110=000\x01"
+
+	s.Nil(parseMessage(s.msg, bytes.NewBufferString(raw), nil, nil, nil, true))
+	rebuilt := string(s.msg.build())
+	s.Equal(raw[:len(raw)-7], rebuilt[:len(rebuilt)-7])
+
+	text, err := s.msg.Body.GetString(Tag(58))
+	s.Nil(err)
+	s.Equal("done", text)
+
+	// Without PreserveMessageFieldsOrder, the repeated tags of the group are lost.
+	s.Nil(ParseMessage(s.msg, bytes.NewBufferString(raw)))
+	s.NotContains(string(s.msg.build()), "270=1.5")
+}
@@ -0,0 +1,58 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package prometheus provides a quickfix.LatencyObserver exporting the latencies of application messages to Prometheus.
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const namespace = "quickfix"
+
+type latencyObserver struct {
+	latency *prometheus.HistogramVec
+}
+
+// NewLatencyObserver returns a quickfix.LatencyObserver exporting latencies as a histogram labelled with the session
+// and the direction of the message, registering its collector with registerer.
+func NewLatencyObserver(registerer prometheus.Registerer) (quickfix.LatencyObserver, error) {
+	o := latencyObserver{
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "session",
+			Name:      "message_latency_seconds",
+			Help:      "Time from reading an application message to FromApp returning, or from ToApp to writing it.",
+			Buckets:   prometheus.ExponentialBuckets(0.00001, 2, 20),
+		}, []string{"session", "direction"}),
+	}
+
+	if err := registerer.Register(o.latency); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o latencyObserver) ObserveInboundLatency(sessionID quickfix.SessionID, latency time.Duration) {
+	o.latency.WithLabelValues(sessionID.String(), "inbound").Observe(latency.Seconds())
+}
+
+func (o latencyObserver) ObserveOutboundLatency(sessionID quickfix.SessionID, latency time.Duration) {
+	o.latency.WithLabelValues(sessionID.String(), "outbound").Observe(latency.Seconds())
+}
@@ -0,0 +1,45 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestLatencyObserver(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	observer, err := NewLatencyObserver(reg)
+	require.Nil(t, err)
+
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	observer.ObserveInboundLatency(sessionID, time.Millisecond)
+	observer.ObserveOutboundLatency(sessionID, 2*time.Millisecond)
+
+	count, err := testutil.GatherAndCount(reg, "quickfix_session_message_latency_seconds")
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = NewLatencyObserver(reg)
+	assert.NotNil(t, err)
+}
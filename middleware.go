@@ -0,0 +1,98 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+// InboundHandler handles a message received by a session after it passed validation.
+type InboundHandler func(msg *Message, sessionID SessionID) MessageRejectError
+
+// InboundMiddleware intercepts received admin and application messages before they are passed to FromAdmin or
+// FromApp. It may inspect or modify the message and call next to continue, return a MessageRejectError to reject
+// the message, or return nil without calling next to accept the message without passing it to the Application.
+type InboundMiddleware func(next InboundHandler) InboundHandler
+
+// OutboundHandler handles a message being sent by a session before it is persisted.
+type OutboundHandler func(msg *Message, sessionID SessionID) error
+
+// OutboundMiddleware intercepts admin and application messages being sent before they are passed to ToAdmin or
+// ToApp, including application messages being resent. It may inspect or modify the message and call next to
+// continue, or return an error without calling next to fail the send. Returning ErrDoNotSend skips the message
+// without failing the send, just as returning it from ToApp does.
+type OutboundMiddleware func(next OutboundHandler) OutboundHandler
+
+// useMiddleware wraps the Application of the session in the middleware. The first middleware is the outermost, and
+// sees a message first.
+func (s *session) useMiddleware(inbound []InboundMiddleware, outbound []OutboundMiddleware) {
+	s.inbound, s.outbound = nil, nil
+
+	if len(inbound) > 0 {
+		handler := s.deliverInbound
+		for i := len(inbound) - 1; i >= 0; i-- {
+			handler = inbound[i](handler)
+		}
+		s.inbound = handler
+	}
+
+	if len(outbound) > 0 {
+		handler := s.deliverOutbound
+		for i := len(outbound) - 1; i >= 0; i-- {
+			handler = outbound[i](handler)
+		}
+		s.outbound = handler
+	}
+}
+
+// interceptInbound passes msg through the inbound middleware to the Application.
+func (s *session) interceptInbound(msg *Message) MessageRejectError {
+	if s.inbound == nil {
+		return s.deliverInbound(msg, s.sessionID)
+	}
+	return s.inbound(msg, s.sessionID)
+}
+
+// interceptOutbound passes msg through the outbound middleware to the Application.
+func (s *session) interceptOutbound(msg *Message) error {
+	if s.outbound == nil {
+		return s.deliverOutbound(msg, s.sessionID)
+	}
+	return s.outbound(msg, s.sessionID)
+}
+
+func (s *session) deliverInbound(msg *Message, _ SessionID) MessageRejectError {
+	msgType, err := msg.Header.GetBytes(tagMsgType)
+	if err != nil {
+		return err
+	}
+
+	if isAdminMessageType(msgType) {
+		return s.application.FromAdmin(msg, s.sessionID)
+	}
+
+	return s.fromAppChecked(msg, msgType)
+}
+
+func (s *session) deliverOutbound(msg *Message, _ SessionID) error {
+	msgType, err := msg.Header.GetBytes(tagMsgType)
+	if err != nil {
+		return err
+	}
+
+	if isAdminMessageType(msgType) {
+		s.application.ToAdmin(msg, s.sessionID)
+		return nil
+	}
+
+	return s.toApp(msg)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MiddlewareTestSuite struct {
+	SessionSuiteRig
+	calls []string
+}
+
+func TestMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(MiddlewareTestSuite))
+}
+
+func (s *MiddlewareTestSuite) SetupTest() {
+	s.Init()
+	s.calls = nil
+	s.session.State = inSession{}
+}
+
+func (s *MiddlewareTestSuite) recordInbound(name string) InboundMiddleware {
+	return func(next InboundHandler) InboundHandler {
+		return func(msg *Message, sessionID SessionID) MessageRejectError {
+			s.calls = append(s.calls, name)
+			return next(msg, sessionID)
+		}
+	}
+}
+
+func (s *MiddlewareTestSuite) recordOutbound(name string) OutboundMiddleware {
+	return func(next OutboundHandler) OutboundHandler {
+		return func(msg *Message, sessionID SessionID) error {
+			s.calls = append(s.calls, name)
+			return next(msg, sessionID)
+		}
+	}
+}
+
+func (s *MiddlewareTestSuite) incoming(msg *Message) {
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(msg.build())})
+}
+
+func (s *MiddlewareTestSuite) TestInboundOrder() {
+	s.session.useMiddleware([]InboundMiddleware{s.recordInbound("first"), s.recordInbound("second")}, nil)
+	s.MockApp.On("FromApp").Return(nil)
+
+	s.incoming(s.NewOrderSingle())
+	s.MockApp.AssertExpectations(s.T())
+	s.Equal([]string{"first", "second"}, s.calls)
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *MiddlewareTestSuite) TestInboundAdmin() {
+	s.session.useMiddleware([]InboundMiddleware{s.recordInbound("admin")}, nil)
+	s.MockApp.On("FromAdmin").Return(nil)
+
+	s.incoming(s.Heartbeat())
+	s.MockApp.AssertExpectations(s.T())
+	s.Equal([]string{"admin"}, s.calls)
+}
+
+func (s *MiddlewareTestSuite) TestInboundReject() {
+	s.session.useMiddleware([]InboundMiddleware{func(InboundHandler) InboundHandler {
+		return func(*Message, SessionID) MessageRejectError {
+			return ConditionallyRequiredFieldMissing(Tag(11))
+		}
+	}}, nil)
+	s.MockApp.On("ToApp").Return(nil)
+
+	s.incoming(s.NewOrderSingle())
+	s.MockApp.AssertExpectations(s.T())
+	s.MockApp.AssertNotCalled(s.T(), "FromApp")
+	s.LastToAppMessageSent()
+	s.MessageType(string(msgTypeBusinessMessageReject), s.MockApp.lastToApp)
+}
+
+func (s *MiddlewareTestSuite) TestInboundShortCircuit() {
+	s.session.useMiddleware([]InboundMiddleware{func(InboundHandler) InboundHandler {
+		return func(*Message, SessionID) MessageRejectError { return nil }
+	}}, nil)
+
+	s.incoming(s.NewOrderSingle())
+	s.MockApp.AssertNotCalled(s.T(), "FromApp")
+	s.NoMessageSent()
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *MiddlewareTestSuite) TestOutboundEnrich() {
+	s.session.useMiddleware(nil, []OutboundMiddleware{s.recordOutbound("first"), func(next OutboundHandler) OutboundHandler {
+		return func(msg *Message, sessionID SessionID) error {
+			msg.Body.SetField(Tag(58), FIXString("enriched"))
+			return next(msg, sessionID)
+		}
+	}})
+	s.MockApp.On("ToApp").Return(nil)
+
+	s.Require().Nil(s.session.send(s.NewOrderSingle()))
+	s.MockApp.AssertExpectations(s.T())
+	s.Equal([]string{"first"}, s.calls)
+	s.LastToAppMessageSent()
+	s.FieldEquals(Tag(58), "enriched", s.MockApp.lastToApp.Body)
+}
+
+func (s *MiddlewareTestSuite) TestOutboundAdmin() {
+	s.session.useMiddleware(nil, []OutboundMiddleware{s.recordOutbound("admin")})
+	s.MockApp.On("ToAdmin")
+
+	s.Require().Nil(s.session.send(s.Heartbeat()))
+	s.MockApp.AssertExpectations(s.T())
+	s.Equal([]string{"admin"}, s.calls)
+	s.LastToAdminMessageSent()
+}
+
+func (s *MiddlewareTestSuite) TestOutboundDoNotSend() {
+	s.session.useMiddleware(nil, []OutboundMiddleware{func(OutboundHandler) OutboundHandler {
+		return func(*Message, SessionID) error { return ErrDoNotSend }
+	}})
+
+	s.Equal(ErrDoNotSend, s.session.send(s.NewOrderSingle()))
+	s.MockApp.AssertNotCalled(s.T(), "ToApp")
+	s.NoMessageSent()
+	s.NextSenderMsgSeqNum(1)
+}
@@ -24,6 +24,7 @@ var msgTypeResendRequest = []byte("2")
 var msgTypeReject = []byte("3")
 var msgTypeSequenceReset = []byte("4")
 var msgTypeLogout = []byte("5")
+var msgTypeBusinessMessageReject = []byte("j")
 
 // isAdminMessageType returns true if the message type is a session level message.
 func isAdminMessageType(m []byte) bool {
@@ -0,0 +1,149 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+var errQueuedTooLong = errors.New("message queued for longer than OutboundQueueMaxAge")
+
+// outboundQueue is the queue of PersistOutboundQueue, holding application messages sent while the session is not
+// logged on in its OutboundQueueStore.
+type outboundQueue struct {
+	store  OutboundQueueStore
+	maxAge time.Duration
+
+	// pending is set while the store may hold queued messages, initially as they may have been queued by a
+	// previous process.
+	pending bool
+
+	// receipts are of the last messages of the queue, those sent with SendToTargetAsync by this process.
+	receipts []*SendReceipt
+}
+
+// buildOutboundQueue returns the outbound queue configured by the PersistOutboundQueue and OutboundQueueMaxAge
+// settings, which is nil unless PersistOutboundQueue is enabled.
+func buildOutboundQueue(settings *SessionSettings, store MessageStore) (*outboundQueue, error) {
+	persist, err := settings.BoolSetting(config.PersistOutboundQueue)
+	if err != nil || !persist {
+		return nil, err
+	}
+
+	queueStore, ok := store.(OutboundQueueStore)
+	if !ok {
+		return nil, fmt.Errorf("%v requires a MessageStore implementing OutboundQueueStore", config.PersistOutboundQueue)
+	}
+
+	q := &outboundQueue{store: queueStore, pending: true}
+	if settings.HasSetting(config.OutboundQueueMaxAge) {
+		if q.maxAge, err = settings.DurationSetting(config.OutboundQueueMaxAge); err != nil {
+			return nil, err
+		}
+		if q.maxAge < 0 {
+			return nil, IncorrectFormatForSetting{Setting: config.OutboundQueueMaxAge, Value: []byte(q.maxAge.String())}
+		}
+	}
+
+	return q, nil
+}
+
+// queueOutbound saves msg in the outbound queue if it is an application message sent while the session is not
+// logged on, returning false otherwise. Must be called with the sendMutex held.
+func (s *session) queueOutbound(msg *Message) (bool, error) {
+	// The state is nil until the session is started.
+	if s.outboundQueue == nil || (s.State != nil && s.IsLoggedOn()) {
+		return false, nil
+	}
+
+	if msgType, err := msg.Header.GetBytes(tagMsgType); err != nil || isAdminMessageType(msgType) {
+		return false, nil
+	}
+
+	s.fillDefaultHeader(msg, nil)
+	if err := s.outboundQueue.store.QueueMessage(QueuedMessage{QueuedAt: s.clock.Now(), Msg: msg.build()}); err != nil {
+		return true, err
+	}
+
+	s.outboundQueue.pending = true
+	s.outboundQueue.receipts = append(s.outboundQueue.receipts, msg.receipt)
+	msg.receipt = nil
+
+	// The session may have logged on since IsLoggedOn was checked.
+	s.notifyMessageOut()
+	return true, nil
+}
+
+// restoreOutboundQueue moves the messages of the outbound queue to the send queue once the session is logged on,
+// dropping those queued for longer than OutboundQueueMaxAge. Must be called with the sendMutex held.
+func (s *session) restoreOutboundQueue() {
+	q := s.outboundQueue
+	if q == nil || !q.pending || !s.IsLoggedOn() {
+		return
+	}
+
+	queued, err := q.store.QueuedMessages()
+	if err != nil {
+		s.logError(err)
+		return
+	}
+
+	// The queue is cleared before its messages are assigned MsgSeqNums, so that they are never sent twice under
+	// different MsgSeqNums. The queue stays pending for the next logon if it can not be cleared.
+	if err := q.store.ClearQueuedMessages(); err != nil {
+		s.logError(err)
+		return
+	}
+	q.pending = false
+
+	receipts := make([]*SendReceipt, len(queued))
+	if len(q.receipts) > len(queued) {
+		q.receipts = q.receipts[len(q.receipts)-len(queued):]
+	}
+	copy(receipts[len(queued)-len(q.receipts):], q.receipts)
+	q.receipts = nil
+
+	now := s.clock.Now()
+	for i, m := range queued {
+		if q.maxAge > 0 && now.Sub(m.QueuedAt) > q.maxAge {
+			s.log.OnEventf("Dropping message queued at %v, for longer than %v", m.QueuedAt, q.maxAge)
+			receipts[i].done(errQueuedTooLong)
+			continue
+		}
+
+		msg := NewMessage()
+		if err := parseMessage(msg, bytes.NewBuffer(m.Msg), s.transportDataDictionary, s.appDataDictionary, s.appDataDictionaries, false); err != nil {
+			s.logError(err)
+			receipts[i].done(err)
+			continue
+		}
+		msg.restored = true
+		msg.receipt = receipts[i]
+
+		sent, err := s.prepMessageForSend(msg, nil)
+		if err != nil {
+			s.logError(err)
+			receipts[i].done(err)
+			continue
+		}
+		s.toSend = append(s.toSend, sent)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// sameStoreFactory returns the same MessageStore for each session, like a persistent store after a restart.
+type sameStoreFactory struct {
+	stores map[SessionID]MessageStore
+}
+
+func (f sameStoreFactory) Create(sessionID SessionID) (MessageStore, error) {
+	if store, ok := f.stores[sessionID]; ok {
+		return store, nil
+	}
+
+	store, err := NewMemoryStoreFactory().Create(sessionID)
+	f.stores[sessionID] = store
+	return store, err
+}
+
+// plainStore is a MessageStore without an outbound queue.
+type plainStore struct{ MessageStore }
+
+type orderApp struct {
+	logonApp
+	clOrdIDs chan string
+}
+
+func (a orderApp) FromApp(msg *Message, _ SessionID) MessageRejectError {
+	clOrdID, _ := msg.Body.GetString(tagClOrdID)
+	a.clOrdIDs <- clOrdID
+	return nil
+}
+
+func newOrder(clOrdID string) *Message {
+	msg := NewMessage()
+	msg.Header.SetBytes(tagMsgType, []byte("D"))
+	msg.Body.SetField(tagClOrdID, FIXString(clOrdID))
+	return msg
+}
+
+func TestBuildOutboundQueue(t *testing.T) {
+	memoryStore, err := NewMemoryStoreFactory().Create(SessionID{})
+	require.NoError(t, err)
+
+	var tests = []struct {
+		settings map[string]string
+		store    MessageStore
+		maxAge   time.Duration
+		disabled bool
+		invalid  bool
+	}{
+		{settings: map[string]string{config.PersistOutboundQueue: "N"}, store: memoryStore, disabled: true},
+		{settings: map[string]string{config.PersistOutboundQueue: "Y"}, store: memoryStore},
+		{settings: map[string]string{config.PersistOutboundQueue: "Y", config.OutboundQueueMaxAge: "30m"}, store: memoryStore, maxAge: 30 * time.Minute},
+		{settings: map[string]string{config.PersistOutboundQueue: "Y", config.OutboundQueueMaxAge: "-1s"}, store: memoryStore, invalid: true},
+		{settings: map[string]string{config.PersistOutboundQueue: "Y"}, store: plainStore{memoryStore}, invalid: true},
+		{settings: map[string]string{config.PersistOutboundQueue: "N"}, store: plainStore{memoryStore}, disabled: true},
+	}
+
+	for _, test := range tests {
+		settings := NewSessionSettings()
+		for setting, value := range test.settings {
+			settings.Set(setting, value)
+		}
+
+		q, err := buildOutboundQueue(settings, test.store)
+		if test.invalid {
+			assert.Error(t, err, test.settings)
+			continue
+		}
+
+		require.NoError(t, err, test.settings)
+		if test.disabled {
+			assert.Nil(t, q, test.settings)
+			continue
+		}
+
+		require.NotNil(t, q, test.settings)
+		assert.Equal(t, test.maxAge, q.maxAge, test.settings)
+	}
+}
+
+func TestPersistOutboundQueue(t *testing.T) {
+	host := "pipe://outbound_queue"
+	acceptorApp := orderApp{logonApp: logonApp{loggedOn: make(chan SessionID, 10)}, clOrdIDs: make(chan string, 10)}
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketAcceptHost: host}, reloadSessionSettings("ACCEPTOR", "A", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	sessionID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "A", TargetCompID: "ACCEPTOR"}
+	settings := reloadSettings(t, nil, reloadSessionSettings("A", "ACCEPTOR", map[string]string{
+		config.SocketConnectHost: host, config.SocketConnectPort: "5021", config.HeartBtInt: "30", config.ResetOnLogon: "Y",
+		config.PersistOutboundQueue: "Y", config.OutboundQueueMaxAge: "1m",
+	}))
+	storeFactory := sameStoreFactory{stores: make(map[SessionID]MessageStore)}
+
+	// Messages sent before the first process stopped are kept in the store.
+	_, err = NewInitiator(&MockApp{}, storeFactory, settings, NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, SendToTarget(newOrder("1"), sessionID))
+	require.NoError(t, SendToTarget(newOrder("2"), sessionID))
+	require.NoError(t, UnregisterSession(sessionID))
+
+	store := storeFactory.stores[sessionID].(OutboundQueueStore)
+	require.NoError(t, store.QueueMessage(QueuedMessage{QueuedAt: time.Now().Add(-time.Hour), Msg: newOrder("expired").build()}))
+
+	initiatorApp := newReloadApp()
+	initiator, err := NewInitiator(initiatorApp, storeFactory, settings, NewNullLogFactory())
+	require.NoError(t, err)
+	receipt, err := SendToTargetAsync(newOrder("3"), sessionID)
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+	waitForSession(t, initiatorApp.loggedOn, sessionID)
+
+	for _, expected := range []string{"1", "2", "3"} {
+		select {
+		case clOrdID := <-acceptorApp.clOrdIDs:
+			assert.Equal(t, expected, clOrdID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %v", expected)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	seqNum, err := receipt.Wait(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, seqNum)
+
+	select {
+	case clOrdID := <-acceptorApp.clOrdIDs:
+		t.Fatalf("unexpected %v", clOrdID)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	queued, err := store.QueuedMessages()
+	require.NoError(t, err)
+	assert.Empty(t, queued)
+}
+
+// failingClearStore is an OutboundQueueStore failing to clear its outbound queue.
+type failingClearStore struct{ OutboundQueueStore }
+
+func (failingClearStore) ClearQueuedMessages() error { return errors.New("clear failed") }
+
+func TestRestoreOutboundQueue_ClearFails(t *testing.T) {
+	memoryStore, err := NewMemoryStoreFactory().Create(SessionID{})
+	require.NoError(t, err)
+	store := failingClearStore{memoryStore.(OutboundQueueStore)}
+	require.NoError(t, store.QueueMessage(QueuedMessage{QueuedAt: time.Now(), Msg: newOrder("1").build()}))
+
+	s := &session{
+		sessionID:     SessionID{BeginString: BeginStringFIX42, SenderCompID: "A", TargetCompID: "B"},
+		store:         store,
+		application:   logonApp{},
+		log:           nullLog{},
+		clock:         SystemClock{},
+		outboundQueue: &outboundQueue{store: store, pending: true},
+	}
+	s.State = inSession{}
+
+	// The queue is neither sent nor cleared, and is restored on the next logon.
+	s.restoreOutboundQueue()
+	assert.Empty(t, s.toSend)
+	assert.True(t, s.outboundQueue.pending)
+	assert.Equal(t, 1, store.NextSenderMsgSeqNum())
+}
@@ -18,6 +18,7 @@ package quickfix
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"time"
 )
@@ -31,13 +32,29 @@ type parser struct {
 	bigBuffer, buffer []byte
 	reader            io.Reader
 	lastRead          time.Time
+
+	// Zero for no limit.
+	maxMessageSize, maxBytesPerSecond int
+	windowStart                       time.Time
+	windowBytes                       int
 }
 
 func newParser(reader io.Reader) *parser {
 	return &parser{reader: reader}
 }
 
+// setLimits limits the size of messages read by the parser, and the number of bytes read per second.
+func (p *parser) setLimits(maxMessageSize, maxBytesPerSecond int) {
+	p.maxMessageSize = maxMessageSize
+	p.maxBytesPerSecond = maxBytesPerSecond
+}
+
 func (p *parser) readMore() (int, error) {
+	// The buffer only holds an incomplete message and anything preceding it when more is read.
+	if p.maxMessageSize > 0 && len(p.buffer) > p.maxMessageSize {
+		return 0, fmt.Errorf("message exceeds MaxMessageSize of %v bytes", p.maxMessageSize)
+	}
+
 	if len(p.buffer) == cap(p.buffer) {
 		var newBuffer []byte
 		switch {
@@ -63,6 +80,18 @@ func (p *parser) readMore() (int, error) {
 	n, e := p.reader.Read(p.buffer[len(p.buffer):cap(p.buffer)])
 	p.lastRead = time.Now()
 	p.buffer = p.buffer[:len(p.buffer)+n]
+
+	if p.maxBytesPerSecond > 0 {
+		if p.lastRead.Sub(p.windowStart) >= time.Second {
+			p.windowStart, p.windowBytes = p.lastRead, 0
+		}
+
+		p.windowBytes += n
+		if p.windowBytes > p.maxBytesPerSecond {
+			return 0, fmt.Errorf("inbound bytes exceed MaxInboundBytesPerSecond of %v", p.maxBytesPerSecond)
+		}
+	}
+
 	return n, e
 }
 
@@ -136,6 +165,10 @@ func (p *parser) jumpLength() (int, error) {
 		return length, errors.New("Invalid length")
 	}
 
+	if p.maxMessageSize > 0 && offset+length > p.maxMessageSize {
+		return length, fmt.Errorf("BodyLength %v exceeds MaxMessageSize of %v bytes", length, p.maxMessageSize)
+	}
+
 	return offset + length, nil
 }
 
@@ -186,3 +186,41 @@ func (s *ParserSuite) TestReadMessageGrowBuffer() {
 		s.Equal(tc.expectedBufferLen, len(s.parser.buffer))
 	}
 }
+
+func (s *ParserSuite) TestMaxMessageSize() {
+	stream := "8=FIX.4.0\x019=5\x01blah\x0110=103\x018=FIX.4.0\x019=40\x01foo"
+	s.reader = strings.NewReader(stream)
+	s.setLimits(len("8=FIX.4.0\x019=5\x01blah\x0110=103\x01"), 0)
+
+	msg, err := s.ReadMessage()
+	s.Require().Nil(err)
+	s.Equal("8=FIX.4.0\x019=5\x01blah\x0110=103\x01", msg.String())
+
+	_, err = s.ReadMessage()
+	s.NotNil(err, "BodyLength exceeds the limit")
+}
+
+func (s *ParserSuite) TestMaxMessageSizeWithoutEnd() {
+	s.reader = strings.NewReader("8=FIX.4.0\x019=5\x01" + strings.Repeat("x", 2*defaultBufSize))
+	s.setLimits(100, 0)
+
+	_, err := s.ReadMessage()
+	s.NotNil(err, "message without an end should not be buffered past the limit")
+	s.LessOrEqual(len(s.buffer), defaultBufSize)
+}
+
+func (s *ParserSuite) TestMaxBytesPerSecond() {
+	stream := "8=FIX.4.0\x019=5\x01blah\x0110=103\x01"
+	s.reader = strings.NewReader(strings.Repeat(stream, 10))
+	s.setLimits(0, len(stream))
+
+	_, err := s.ReadMessage()
+	s.NotNil(err)
+
+	s.SetupTest()
+	s.reader = strings.NewReader(strings.Repeat(stream, 10))
+	s.setLimits(0, 10*len(stream))
+
+	_, err = s.ReadMessage()
+	s.Nil(err)
+}
@@ -220,6 +220,7 @@ func (s *SessionSuiteRig) Init() {
 		log:          nullLog{},
 		messageOut:   s.Receiver.sendChannel,
 		sessionEvent: make(chan internal.Event),
+		clock:        SystemClock{},
 	}
 	s.MaxLatency = 120 * time.Second
 }
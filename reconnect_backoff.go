@@ -0,0 +1,48 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// ReconnectListener may be set on an Initiator to be alerted when a session repeatedly fails to connect.
+type ReconnectListener interface {
+	// OnReconnectAttemptsExceeded is called once a session has failed to connect MaxReconnectAttempts times in a row.
+	// The Initiator continues to reconnect until a connection succeeds or it is stopped.
+	OnReconnectAttemptsExceeded(sessionID SessionID, attempts int)
+}
+
+// reconnectDelay returns how long to wait before reconnecting after failures consecutive failed connection attempts.
+// Without backoff settings this is the ReconnectInterval.
+func (s *session) reconnectDelay(failures int) time.Duration {
+	delay := float64(s.ReconnectInterval)
+	if s.ReconnectBackoffMultiplier > 1 {
+		delay *= math.Pow(s.ReconnectBackoffMultiplier, float64(failures))
+	}
+
+	if s.MaxReconnectInterval > 0 && delay > float64(s.MaxReconnectInterval) {
+		delay = float64(s.MaxReconnectInterval)
+	}
+
+	if s.ReconnectJitter > 0 {
+		delay *= 1 + s.ReconnectJitter*(2*rand.Float64()-1)
+	}
+
+	return time.Duration(delay)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	s := &session{}
+	s.ReconnectInterval = time.Second
+	assert.Equal(t, time.Second, s.reconnectDelay(0))
+	assert.Equal(t, time.Second, s.reconnectDelay(5), "no backoff by default")
+
+	s.ReconnectBackoffMultiplier = 2
+	s.MaxReconnectInterval = 10 * time.Second
+	for failures, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		assert.Equal(t, expected, s.reconnectDelay(failures), "after %v failures", failures)
+	}
+
+	s.ReconnectJitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := s.reconnectDelay(1)
+		assert.GreaterOrEqual(t, delay, time.Second)
+		assert.LessOrEqual(t, delay, 3*time.Second)
+	}
+}
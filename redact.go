@@ -0,0 +1,165 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// redactedValue replaces the values of redacted fields in logs.
+const redactedValue = "***"
+
+var redactedTags = struct {
+	sync.RWMutex
+	tags []Tag
+}{tags: []Tag{tagPassword, tagNewPassword, tagRawData}}
+
+// RegisterRedactedTags adds tags to Password(554), NewPassword(925) and RawData(96),
+// whose values are masked in the message and event logs of sessions created afterwards.
+func RegisterRedactedTags(tags ...Tag) {
+	redactedTags.Lock()
+	defer redactedTags.Unlock()
+	redactedTags.tags = append(redactedTags.tags, tags...)
+}
+
+// redactor masks the values of fields in raw messages. A nil redactor masks nothing.
+type redactor map[Tag]struct{}
+
+// buildRedactor returns the redactor for LogRedact and LogRedactTags.
+func buildRedactor(settings *SessionSettings) (redactor, error) {
+	if settings.HasSetting(config.LogRedact) {
+		redact, err := settings.BoolSetting(config.LogRedact)
+		if err != nil || !redact {
+			return nil, err
+		}
+	}
+
+	r := make(redactor)
+	redactedTags.RLock()
+	for _, tag := range redactedTags.tags {
+		r[tag] = struct{}{}
+	}
+	redactedTags.RUnlock()
+
+	if settings.HasSetting(config.LogRedactTags) {
+		tags, err := settings.Setting(config.LogRedactTags)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tag := range strings.Split(tags, ",") {
+			t, err := strconv.Atoi(strings.TrimSpace(tag))
+			if err != nil || t <= 0 {
+				return nil, IncorrectFormatForSetting{Setting: config.LogRedactTags, Value: []byte(tags), Err: err}
+			}
+			r[Tag(t)] = struct{}{}
+		}
+	}
+
+	return r, nil
+}
+
+// redact returns msg with the values of the redactor's fields masked, or msg itself if it has none of them.
+// Fields are found after each SOH, so messages quoted by events are redacted too.
+func (r redactor) redact(msg []byte) []byte {
+	if len(r) == 0 {
+		return msg
+	}
+
+	var redacted []byte
+	copied := 0
+	previousTag, previousValue := Tag(0), -1
+	for start := 0; start < len(msg); {
+		end := bytes.IndexByte(msg[start:], '\001')
+		if end == -1 {
+			end = len(msg)
+		} else {
+			end += start
+		}
+
+		eq := bytes.IndexByte(msg[start:end], '=')
+		if eq == -1 {
+			start = end + 1
+			continue
+		}
+
+		tagValue, err := parseUInt(msg[start : start+eq])
+		if err != nil {
+			start = end + 1
+			continue
+		}
+		tag, valueStart := Tag(tagValue), start+eq+1
+
+		// RawData may contain SOH, so its length is taken from RawDataLength.
+		if tag == tagRawData && previousTag == tagRawDataLength && previousValue >= 0 && valueStart+previousValue <= len(msg) {
+			end = valueStart + previousValue
+		}
+
+		if _, ok := r[tag]; ok {
+			redacted = append(redacted, msg[copied:valueStart]...)
+			redacted = append(redacted, redactedValue...)
+			copied = end
+		}
+
+		previousTag, previousValue = tag, -1
+		if n, err := parseUInt(msg[valueStart:end]); err == nil {
+			previousValue = n
+		}
+		start = end + 1
+	}
+
+	if redacted == nil {
+		return msg
+	}
+
+	return append(redacted, msg[copied:]...)
+}
+
+func (r redactor) redactString(text string) string {
+	if len(r) == 0 || !strings.Contains(text, "=") {
+		return text
+	}
+
+	return string(r.redact([]byte(text)))
+}
+
+// redactLog masks fields in the messages and events it passes to a Log.
+type redactLog struct {
+	Log
+	redactor redactor
+}
+
+func (l redactLog) OnIncoming(msg []byte) {
+	l.Log.OnIncoming(l.redactor.redact(msg))
+}
+
+func (l redactLog) OnOutgoing(msg []byte) {
+	l.Log.OnOutgoing(l.redactor.redact(msg))
+}
+
+func (l redactLog) OnEvent(text string) {
+	l.Log.OnEvent(l.redactor.redactString(text))
+}
+
+func (l redactLog) OnEventf(format string, a ...interface{}) {
+	l.OnEvent(fmt.Sprintf(format, a...))
+}
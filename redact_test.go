@@ -0,0 +1,95 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	r := redactor{tagPassword: {}, tagNewPassword: {}, tagRawData: {}}
+
+	var tests = []struct {
+		msg, expected string
+	}{
+		{"8=FIX.4.4\x0135=A\x01553=user\x01554=secret\x01925=newsecret\x0110=000\x01", "8=FIX.4.4\x0135=A\x01553=user\x01554=***\x01925=***\x0110=000\x01"},
+		{"8=FIX.4.4\x0135=A\x0195=5\x0196=a\x01b=c\x0110=000\x01", "8=FIX.4.4\x0135=A\x0195=5\x0196=***\x0110=000\x01"},
+		{"554=secret", "554=***"},
+		{"8=FIX.4.4\x0135=0\x0110=000\x01", "8=FIX.4.4\x0135=0\x0110=000\x01"},
+		{"Invalid Message: 8=FIX.4.4\x01554=secret\x01", "Invalid Message: 8=FIX.4.4\x01554=***\x01"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, string(r.redact([]byte(test.msg))))
+		assert.Equal(t, test.expected, r.redactString(test.msg))
+	}
+
+	var none redactor
+	assert.Equal(t, "554=secret", string(none.redact([]byte("554=secret"))))
+}
+
+func TestBuildRedactor(t *testing.T) {
+	settings := NewSessionSettings()
+	r, err := buildRedactor(settings)
+	require.Nil(t, err)
+	assert.Equal(t, redactor{tagPassword: {}, tagNewPassword: {}, tagRawData: {}}, r)
+
+	settings.Set(config.LogRedactTags, "553, 1402")
+	r, err = buildRedactor(settings)
+	require.Nil(t, err)
+	assert.Contains(t, r, Tag(553))
+	assert.Contains(t, r, Tag(1402))
+	assert.Contains(t, r, tagPassword)
+
+	settings.Set(config.LogRedactTags, "553,user")
+	_, err = buildRedactor(settings)
+	assert.NotNil(t, err)
+
+	settings.Set(config.LogRedact, "N")
+	r, err = buildRedactor(settings)
+	require.Nil(t, err)
+	assert.Nil(t, r)
+}
+
+type eventLog struct {
+	nullLog
+	messages [][]byte
+	events   []string
+}
+
+func (l *eventLog) OnIncoming(msg []byte) { l.messages = append(l.messages, msg) }
+func (l *eventLog) OnEvent(text string)   { l.events = append(l.events, text) }
+
+func TestRedactLog(t *testing.T) {
+	events := &eventLog{}
+	log := newSessionLog(events, SessionID{}, redactor{tagPassword: {}})
+
+	log.OnIncoming([]byte("35=A\x01554=secret\x01"))
+	log.OnEventf("Session not found for incoming message: %s", "35=A\x01554=secret\x01")
+	assert.Equal(t, [][]byte{[]byte("35=A\x01554=***\x01")}, events.messages)
+	assert.Equal(t, []string{"Session not found for incoming message: 35=A\x01554=***\x01"}, events.events)
+
+	entries := &entryLog{}
+	log = newSessionLog(entries, SessionID{}, redactor{tagPassword: {}})
+	log.OnIncoming([]byte("35=A\x01554=secret\x01"))
+	require.Len(t, entries.entries, 1)
+	assert.Equal(t, "35=A\x01554=***\x01", string(entries.entries[0].Message))
+}
@@ -18,6 +18,7 @@ package quickfix
 import (
 	"errors"
 	"sync"
+	"time"
 )
 
 var sessionsLock sync.RWMutex
@@ -30,6 +31,13 @@ type Messagable interface {
 	ToMessage() *Message
 }
 
+// ValidatingMessagable is a Messagable that validates itself before it is sent, such as the generated message types
+// checking their required fields.
+type ValidatingMessagable interface {
+	Messagable
+	Validate() error
+}
+
 // Send determines the session to send Messagable using header fields BeginString, TargetCompID, SenderCompID.
 func Send(m Messagable) (err error) {
 	msg := m.ToMessage()
@@ -50,33 +58,78 @@ func Send(m Messagable) (err error) {
 
 	sessionID := SessionID{BeginString: string(beginString), TargetCompID: string(targetCompID), SenderCompID: string(senderCompID)}
 
-	return SendToTarget(msg, sessionID)
+	return SendToTarget(m, sessionID)
 }
 
 // SendToTarget sends a message based on the sessionID. Convenient for use in FromApp since it provides a session ID for incoming messages.
+// A ValidatingMessagable is validated first, and not sent if Validate returns an error.
 func SendToTarget(m Messagable, sessionID SessionID) error {
+	if v, ok := m.(ValidatingMessagable); ok {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+
 	msg := m.ToMessage()
 	session, ok := lookupSession(sessionID)
 	if !ok {
 		return errUnknownSession
 	}
 
+	if session.draining.Load() {
+		return ErrLoggingOut
+	}
+
 	return session.queueForSend(msg)
 }
 
-// ResetSession resets session's sequence numbers.
+// LogoutAndDrain logs out the session matching the session id once messages already queued for sending have been sent.
+// Application messages sent after it is called are rejected with ErrLoggingOut. It returns once the Logout exchange
+// completes and the session is disconnected, or ErrLogoutTimeout if that takes longer than timeout,
+// in which case the session is disconnected.
+func LogoutAndDrain(sessionID SessionID, timeout time.Duration) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return errUnknownSession
+	}
+	return session.logoutAndDrain(timeout)
+}
+
+// ResetSession resets session's sequence numbers. It is safe to call on a running session.
 func ResetSession(sessionID SessionID) error {
 	session, ok := lookupSession(sessionID)
 	if !ok {
 		return errUnknownSession
 	}
-	session.log.OnEvent("Session reset")
-	session.State.ShutdownNow(session)
-	if err := session.dropAndReset(); err != nil {
-		session.logError(err)
-		return err
+	return session.reset()
+}
+
+// SetNextSeqNums sets the next outgoing and next expected incoming sequence numbers of the session matching
+// the session id, without racing with messages being processed by a running session.
+// If sendSequenceReset is true and the session is logged on, a SequenceReset-Reset with NewSeqNo senderSeq is
+// sent first to tell the counterparty of the new outgoing sequence number.
+func SetNextSeqNums(sessionID SessionID, senderSeq, targetSeq int, sendSequenceReset bool) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return errUnknownSession
 	}
+	return session.setNextSeqNums(senderSeq, targetSeq, sendSequenceReset)
+}
 
+// SetDialer sets the DialFunc an initiator session connects with, in place of dialing its SocketConnectHost and SocketConnectPort,
+// from its next connection attempt. TLS is still configured by the session's settings.
+// To return to dialing SocketConnectHost and SocketConnectPort call it with a nil value.
+func SetDialer(sessionID SessionID, dial DialFunc) error {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return errUnknownSession
+	}
+
+	if dial == nil {
+		session.dial.Store(nil)
+	} else {
+		session.dial.Store(&dial)
+	}
 	return nil
 }
 
@@ -129,6 +182,15 @@ func GetExpectedTargetNum(sessionID SessionID) (int, error) {
 	return session.store.NextTargetMsgSeqNum(), nil
 }
 
+// GetSessionStats returns the connectivity statistics for the session matching the session id.
+func GetSessionStats(sessionID SessionID) (SessionStats, error) {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return SessionStats{}, errUnknownSession
+	}
+	return session.stats.snapshot(), nil
+}
+
 // GetMessageStore returns the MessageStore interface for session matching the session id.
 func GetMessageStore(sessionID SessionID) (MessageStore, error) {
 	session, ok := lookupSession(sessionID)
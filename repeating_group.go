@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
 )
 
 // GroupItem interface is used to construct repeating group templates.
@@ -71,6 +73,44 @@ func (gt GroupTemplate) Clone() GroupTemplate {
 	return clone
 }
 
+// NewGroupTemplate returns the GroupTemplate of a repeating group field in a DataDictionary, including the templates
+// of nested repeating groups of any depth.
+func NewGroupTemplate(def *datadictionary.FieldDef) GroupTemplate {
+	template := make(GroupTemplate, 0, len(def.Fields))
+	for _, field := range def.Fields {
+		if field.IsGroup() {
+			template = append(template, NewRepeatingGroup(Tag(field.Tag()), NewGroupTemplate(field)))
+		} else {
+			template = append(template, GroupElement(Tag(field.Tag())))
+		}
+	}
+
+	return template
+}
+
+// Nested returns the template of the nested repeating group with tag.
+func (gt GroupTemplate) Nested(tag Tag) (GroupTemplate, bool) {
+	for _, item := range gt {
+		if rg, ok := item.(*RepeatingGroup); ok && rg.tag == tag {
+			return rg.template, true
+		}
+	}
+
+	return nil, false
+}
+
+// appendTags appends the tags of the template, including those of nested repeating groups.
+func (gt GroupTemplate) appendTags(tags []Tag) []Tag {
+	for _, item := range gt {
+		tags = append(tags, item.Tag())
+		if rg, ok := item.(*RepeatingGroup); ok {
+			tags = rg.template.appendTags(tags)
+		}
+	}
+
+	return tags
+}
+
 // Group is a group of fields occurring in a repeating group.
 type Group struct{ FieldMap }
 
@@ -112,6 +152,11 @@ func (f RepeatingGroup) Get(i int) *Group {
 	return f.groups[i]
 }
 
+// Template returns the GroupTemplate of this RepeatingGroup.
+func (f RepeatingGroup) Template() GroupTemplate {
+	return f.template
+}
+
 // Add appends a new group to the RepeatingGroup and returns the new Group.
 func (f *RepeatingGroup) Add() *Group {
 	g := new(Group)
@@ -121,6 +166,11 @@ func (f *RepeatingGroup) Add() *Group {
 	return g
 }
 
+// Remove removes the ith group from the RepeatingGroup.
+func (f *RepeatingGroup) Remove(i int) {
+	f.groups = append(f.groups[:i], f.groups[i+1:]...)
+}
+
 // Write returns tagValues for all Items in the repeating group ordered by
 // Group sequence and Group template order.
 func (f RepeatingGroup) Write() []TagValue {
@@ -128,6 +178,7 @@ func (f RepeatingGroup) Write() []TagValue {
 	tvs[0].init(f.tag, []byte(strconv.Itoa(len(f.groups))))
 
 	for _, group := range f.groups {
+		group.writeGroups()
 		tags := group.sortedTags()
 		group.rwLock.RLock()
 		for _, tag := range tags {
@@ -216,7 +267,7 @@ func (f *RepeatingGroup) Read(tv []TagValue) ([]TagValue, error) {
 		}
 
 		group.rwLock.Lock()
-		group.tagLookup[tvRange[0].tag] = tvRange
+		group.tagLookup[tvRange[0].tag] = tvRange[:len(tvRange)-len(tv)]
 		group.tags = append(group.tags, gi.Tag())
 		group.rwLock.Unlock()
 	}
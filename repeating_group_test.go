@@ -21,6 +21,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
 )
 
 func TestRepeatingGroup_Add(t *testing.T) {
@@ -291,3 +293,139 @@ func TestRepeatingGroup_ReadComplete(t *testing.T) {
 		}
 	}
 }
+
+func TestRepeatingGroup_ReadStoresFieldsOnly(t *testing.T) {
+	f := NewRepeatingGroup(Tag(1), GroupTemplate{GroupElement(2), GroupElement(3)})
+	_, err := f.Read([]TagValue{
+		{value: []byte("1")},
+		{tag: Tag(2), value: []byte("hello")},
+		{tag: Tag(3), value: []byte("world")},
+	})
+	require.Nil(t, err)
+
+	require.Equal(t, 1, f.Len())
+	assert.Len(t, f.Get(0).tagLookup[Tag(2)], 1)
+	assert.Len(t, f.Get(0).tagLookup[Tag(3)], 1)
+}
+
+func TestRepeatingGroup_Remove(t *testing.T) {
+	f := NewRepeatingGroup(Tag(1), GroupTemplate{GroupElement(2)})
+	f.Add().SetString(Tag(2), "a")
+	f.Add().SetString(Tag(2), "b")
+	f.Add().SetString(Tag(2), "c")
+
+	f.Remove(1)
+	require.Equal(t, 2, f.Len())
+
+	first, err := f.Get(0).GetString(Tag(2))
+	require.Nil(t, err)
+	assert.Equal(t, "a", first)
+	last, err := f.Get(1).GetString(Tag(2))
+	require.Nil(t, err)
+	assert.Equal(t, "c", last)
+}
+
+func TestNewGroupTemplate(t *testing.T) {
+	dict, err := datadictionary.Parse("spec/FIX44.xml")
+	require.Nil(t, err)
+
+	template := NewGroupTemplate(dict.Messages["X"].Fields[268])
+	require.NotEmpty(t, template)
+	assert.Equal(t, Tag(279), template[0].Tag())
+
+	underlyings, ok := template.Nested(Tag(711))
+	require.True(t, ok)
+	assert.Equal(t, Tag(311), underlyings[0].Tag())
+
+	altIDs, ok := underlyings.Nested(Tag(457))
+	require.True(t, ok)
+	assert.Equal(t, GroupTemplate{GroupElement(458), GroupElement(459)}, altIDs)
+
+	_, ok = template.Nested(Tag(270))
+	assert.False(t, ok)
+}
+
+func TestFieldMap_RepeatingGroupNested(t *testing.T) {
+	dict, err := datadictionary.Parse("spec/FIX44.xml")
+	require.Nil(t, err)
+	template := NewGroupTemplate(dict.Messages["X"].Fields[268])
+	underlyingsTemplate, _ := template.Nested(Tag(711))
+
+	msg := NewMessage()
+	msg.Header.SetString(tagBeginString, "FIX.4.4")
+	msg.Header.SetString(tagMsgType, "X")
+
+	entries, rerr := msg.Body.RepeatingGroup(Tag(268), template)
+	require.Nil(t, rerr)
+	require.Equal(t, 0, entries.Len())
+
+	entry := entries.Add()
+	entry.SetString(Tag(279), "0")
+	entry.SetString(Tag(270), "1.5")
+	underlyings, rerr := entry.RepeatingGroup(Tag(711), underlyingsTemplate)
+	require.Nil(t, rerr)
+	underlyings.Add().SetString(Tag(311), "IBM")
+
+	parsed := NewMessage()
+	require.Nil(t, ParseMessageWithDataDictionary(parsed, bytes.NewBuffer(msg.build()), dict, dict))
+
+	entries, rerr = parsed.Body.RepeatingGroup(Tag(268), template)
+	require.Nil(t, rerr)
+	require.Equal(t, 1, entries.Len())
+	px, rerr := entries.Get(0).GetString(Tag(270))
+	require.Nil(t, rerr)
+	assert.Equal(t, "1.5", px)
+
+	underlyings, rerr = entries.Get(0).RepeatingGroup(Tag(711), underlyingsTemplate)
+	require.Nil(t, rerr)
+	require.Equal(t, 1, underlyings.Len())
+	symbol, rerr := underlyings.Get(0).GetString(Tag(311))
+	require.Nil(t, rerr)
+	assert.Equal(t, "IBM", symbol)
+
+	cached, rerr := parsed.Body.RepeatingGroup(Tag(268), nil)
+	require.Nil(t, rerr)
+	assert.Same(t, entries, cached)
+
+	// Changes in place are written back when the message is serialized.
+	underlyings.Get(0).SetString(Tag(311), "MSFT")
+	entries.Add().SetString(Tag(279), "2")
+
+	reparsed := NewMessage()
+	require.Nil(t, ParseMessageWithDataDictionary(reparsed, bytes.NewBuffer(parsed.build()), dict, dict))
+
+	entries, rerr = reparsed.Body.RepeatingGroup(Tag(268), template)
+	require.Nil(t, rerr)
+	require.Equal(t, 2, entries.Len())
+	underlyings, rerr = entries.Get(0).RepeatingGroup(Tag(711), underlyingsTemplate)
+	require.Nil(t, rerr)
+	symbol, rerr = underlyings.Get(0).GetString(Tag(311))
+	require.Nil(t, rerr)
+	assert.Equal(t, "MSFT", symbol)
+	action, rerr := entries.Get(1).GetString(Tag(279))
+	require.Nil(t, rerr)
+	assert.Equal(t, "2", action)
+}
+
+func TestFieldMap_RepeatingGroupWithoutDataDictionary(t *testing.T) {
+	rawMsg := bytes.NewBufferString("8=FIXT.1.1\x019=267\x0135=W\x0134=711\x0149=TEST\x0152=20151027-18:41:52.698\x0156=TST\x0122=99\x0148=TSTX15\x01262=7\x01268=4\x01269=4\x01270=0.07499\x01272=20151027\x01273=18:41:52.698\x01269=7\x01270=0.07501\x01272=20151027\x01273=18:41:52.698\x01269=8\x01270=0.07494\x01272=20151027\x01273=18:41:52.698\x01269=B\x01271=60\x01272=20151027\x01273=18:41:52.698\x0110=163\x01")
+	template := GroupTemplate{GroupElement(269), GroupElement(270), GroupElement(271), GroupElement(272), GroupElement(273)}
+
+	msg := NewMessage()
+	require.Nil(t, ParseMessage(msg, rawMsg))
+
+	entries, rerr := msg.Body.RepeatingGroup(Tag(268), template)
+	require.Nil(t, rerr)
+	require.Equal(t, 4, entries.Len())
+	entries.Remove(0)
+
+	parsed := NewMessage()
+	require.Nil(t, ParseMessage(parsed, bytes.NewBuffer(msg.build())))
+
+	entries, rerr = parsed.Body.RepeatingGroup(Tag(268), template)
+	require.Nil(t, rerr)
+	require.Equal(t, 3, entries.Len())
+	entryType, rerr := entries.Get(0).GetString(Tag(269))
+	require.Nil(t, rerr)
+	assert.Equal(t, "7", entryType)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MissingFieldsError lists the required fields of a message that are not set.
+type MissingFieldsError struct {
+	MsgType string
+	Tags    []Tag
+}
+
+func (e *MissingFieldsError) Error() string {
+	tags := make([]string, len(e.Tags))
+	for i, tag := range e.Tags {
+		tags[i] = fmt.Sprint(int(tag))
+	}
+
+	return fmt.Sprintf("message type %q is missing required fields: %v", e.MsgType, strings.Join(tags, ", "))
+}
+
+// ValidateRequiredFields returns a *MissingFieldsError listing the tags that are not set in the body of msg, or nil
+// if all are set. The Validate methods of generated message types call it with their required fields.
+func ValidateRequiredFields(msg *Message, tags ...Tag) error {
+	var missing []Tag
+	for _, tag := range tags {
+		if !msg.Body.Has(tag) {
+			missing = append(missing, tag)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	msgType, _ := msg.Header.GetString(tagMsgType)
+	return &MissingFieldsError{MsgType: msgType, Tags: missing}
+}
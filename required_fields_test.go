@@ -0,0 +1,60 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatingMessage struct {
+	*Message
+}
+
+func (m validatingMessage) ToMessage() *Message { return m.Message }
+
+func (m validatingMessage) Validate() error {
+	return ValidateRequiredFields(m.Message, Tag(11), Tag(54))
+}
+
+func TestValidateRequiredFields(t *testing.T) {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString("D"))
+	msg.Body.SetField(Tag(11), FIXString("order"))
+
+	err := ValidateRequiredFields(msg, Tag(11), Tag(54), Tag(55))
+	var missing *MissingFieldsError
+	require.ErrorAs(t, err, &missing)
+	assert.Equal(t, "D", missing.MsgType)
+	assert.Equal(t, []Tag{54, 55}, missing.Tags)
+	assert.Equal(t, `message type "D" is missing required fields: 54, 55`, err.Error())
+
+	msg.Body.SetField(Tag(54), FIXString("1"))
+	msg.Body.SetField(Tag(55), FIXString("MSFT"))
+	assert.Nil(t, ValidateRequiredFields(msg, Tag(11), Tag(54), Tag(55)))
+}
+
+func TestSendToTargetValidates(t *testing.T) {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString("D"))
+
+	err := SendToTarget(validatingMessage{msg}, SessionID{BeginString: BeginStringFIX42, SenderCompID: "S", TargetCompID: "T"})
+	var missing *MissingFieldsError
+	require.ErrorAs(t, err, &missing)
+	assert.Equal(t, []Tag{11, 54}, missing.Tags)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+// ResendAction determines how a stored application message is answered when the counterparty requests a resend.
+type ResendAction int
+
+const (
+	// ResendReplay resends the message with PossDupFlag set. ToApp may still veto the resend.
+	ResendReplay ResendAction = iota
+
+	// ResendGapFill skips the message, covering its sequence number with a SequenceReset-GapFill.
+	ResendGapFill
+)
+
+// ResendFilter may be implemented by an Application to choose, per message, whether stored application
+// messages are replayed or gap filled when answering a ResendRequest. Admin messages are always gap filled.
+type ResendFilter interface {
+	// FilterResend is called with the message type and stored bytes of each application message in the
+	// requested range, before ToApp. msg must not be retained after FilterResend returns.
+	FilterResend(msgType string, msg []byte) ResendAction
+}
+
+func (s *session) filterResend(msgType, msgBytes []byte) ResendAction {
+	if filter, ok := s.application.(ResendFilter); ok {
+		return filter.FilterResend(string(msgType), msgBytes)
+	}
+
+	return ResendReplay
+}
@@ -91,5 +91,45 @@ func (s resendState) FixMsgIn(session *session, msg *Message) (nextState session
 		}
 	}
 
+	// Messages are still stashed past a gap the counterparty did not fill, request the missing range
+	// rather than dropping the stash.
+	if nextGap := s.nextStashedSeqNum(session.store.NextTargetMsgSeqNum()); nextGap != 0 {
+		nextResendState, err := session.sendResendRequest(session.store.NextTargetMsgSeqNum(), nextGap-1)
+		if err != nil {
+			return handleStateError(session, err)
+		}
+		nextResendState.messageStash = s.messageStash
+		return nextResendState
+	}
+
+	return
+}
+
+// outstandingResend returns the resendState of a session waiting for the counterparty to resend a gap,
+// including while waiting for a reply to a TestRequest.
+func outstandingResend(state sessionState) (resendState, bool) {
+	switch state := state.(type) {
+	case resendState:
+		return state, true
+	case pendingTimeout:
+		return outstandingResend(state.sessionState)
+	}
+
+	return resendState{}, false
+}
+
+// nextStashedSeqNum returns the lowest stashed sequence number after targetSeqNum, or 0 if there is none.
+func (s resendState) nextStashedSeqNum(targetSeqNum int) (seqNum int) {
+	for stashed := range s.messageStash {
+		if stashed < targetSeqNum {
+			delete(s.messageStash, stashed)
+			continue
+		}
+
+		if seqNum == 0 || stashed < seqNum {
+			seqNum = stashed
+		}
+	}
+
 	return
 }
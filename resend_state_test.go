@@ -111,6 +111,74 @@ func (s *resendStateTestSuite) TestFixMsgIn() {
 	s.NextTargetMsgSeqNum(5)
 }
 
+func (s *resendStateTestSuite) TestFixMsgInStashedGap() {
+	s.session.State = inSession{}
+
+	// In session expects seq number 1, send too high.
+	s.MessageFactory.SetNextSeqNum(3)
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+
+	s.State(resendState{})
+	s.LastToAdminMessageSent()
+	s.FieldEquals(tagBeginSeqNo, 1, s.MockApp.lastToAdmin.Body)
+
+	// Seq number 4 never arrives.
+	s.MessageFactory.SetNextSeqNum(5)
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+	s.State(resendState{})
+
+	s.MessageFactory.SetNextSeqNum(1)
+	s.MockApp.On("FromApp").Return(nil)
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 3)
+	s.State(resendState{})
+	s.NextTargetMsgSeqNum(4)
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeResendRequest), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagBeginSeqNo, 4, s.MockApp.lastToAdmin.Body)
+
+	s.MessageFactory.SetNextSeqNum(4)
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 5)
+	s.State(inSession{})
+	s.NextTargetMsgSeqNum(6)
+}
+
+func (s *resendStateTestSuite) TestFixMsgInPendingTimeout() {
+	s.session.State = inSession{}
+
+	// In session expects seq number 1, send too high.
+	s.MessageFactory.SetNextSeqNum(3)
+	s.MockApp.On("ToAdmin")
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+	s.State(resendState{})
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeResendRequest), s.MockApp.lastToAdmin)
+
+	s.session.Timeout(s.session, internal.PeerTimeout)
+	s.State(pendingTimeout{resendState{}})
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeTestRequest), s.MockApp.lastToAdmin)
+
+	// The resend is still outstanding, so higher messages are stashed without another ResendRequest.
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+	s.State(resendState{})
+	s.NoMessageSent()
+
+	s.MessageFactory.SetNextSeqNum(1)
+	s.MockApp.On("FromApp").Return(nil)
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+
+	s.MockApp.AssertNumberOfCalls(s.T(), "FromApp", 4)
+	s.State(inSession{})
+	s.NextTargetMsgSeqNum(5)
+}
+
 func (s *resendStateTestSuite) TestFixMsgInSequenceReset() {
 	s.session.State = inSession{}
 
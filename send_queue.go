@@ -0,0 +1,209 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	errNotSent          = errors.New("message not sent, connection busy or disconnected")
+	errDroppedFromQueue = errors.New("message dropped from send queue")
+)
+
+// queuedMessage is a message in the send queue.
+type queuedMessage struct {
+	bytes []byte
+
+	// ctx carries span, the SpanOutbound span of the message that is ended once the message is sent.
+	// span is nil for resent messages.
+	ctx  context.Context
+	span Span
+
+	// toAppAt is when ToApp was called for an application message, and is zero otherwise.
+	toAppAt time.Time
+
+	// receipt is the SendReceipt of a message sent with SendToTargetAsync, and is nil otherwise.
+	receipt *SendReceipt
+}
+
+func (m queuedMessage) drop() {
+	if m.span != nil {
+		m.span.End(errDroppedFromQueue)
+	}
+	m.receipt.done(errDroppedFromQueue)
+}
+
+// QueueFullPolicy determines what happens to application messages sent while the session's send queue is full.
+type QueueFullPolicy int
+
+const (
+	// QueueFullBlock blocks the sender until the session has sent queued messages, failing the send with
+	// ErrSessionQueueFull after the QueueFullTimeout, or at once when sent from the session's run loop.
+	QueueFullBlock QueueFullPolicy = iota
+
+	// QueueFullError fails the send with ErrSessionQueueFull.
+	QueueFullError
+
+	// QueueFullDropOldestQuote drops the oldest queued Quote(S) or MassQuote(i) to make room,
+	// failing the send with ErrSessionQueueFull if there is none.
+	// Dropped quotes are persisted, so the counterparty can still request them with a ResendRequest.
+	QueueFullDropOldestQuote
+)
+
+// defaultQueueFullTimeout is how long QueueFullBlock waits for space in the send queue by default.
+const defaultQueueFullTimeout = 10 * time.Second
+
+type sendQueueLimit struct {
+	capacity int
+	policy   QueueFullPolicy
+
+	// timeout is how long QueueFullBlock waits for space in the send queue.
+	timeout time.Duration
+
+	// space is signalled when messages leave the send queue. Its Locker is the session's sendMutex.
+	space *sync.Cond
+}
+
+var quoteMsgTypes = [][]byte{[]byte("S"), []byte("i")}
+
+func isQuoteMsgType(msgType []byte) bool {
+	for _, quoteMsgType := range quoteMsgTypes {
+		if bytes.Equal(quoteMsgType, msgType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isQuoteMsgBytes(msgBytes []byte) bool {
+	for _, quoteMsgType := range quoteMsgTypes {
+		if bytes.Contains(msgBytes, append(append([]byte("\00135="), quoteMsgType...), '\001')) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *session) queuedLen() int {
+	n := len(s.toSend)
+	if s.throttle != nil {
+		n += len(s.throttle.queue)
+	}
+
+	return n
+}
+
+// reserveQueueSpace applies the session's send queue limit before an application message is queued.
+// Must be called with the sendMutex held.
+func (s *session) reserveQueueSpace(msg *Message) error {
+	if s.queueLimit == nil {
+		return nil
+	}
+
+	if msgType, err := msg.Header.GetBytes(tagMsgType); err == nil && isAdminMessageType(msgType) {
+		return nil
+	}
+
+	var timedOut bool
+	var stopTimer func()
+	for s.queuedLen() >= s.queueLimit.capacity {
+		switch s.queueLimit.policy {
+		case QueueFullBlock:
+			// The run loop sends the queued messages, so it would wait on itself.
+			if s.onRunLoop() || timedOut {
+				return ErrSessionQueueFull
+			}
+			if stopTimer == nil {
+				stopTimer = s.queueFullTimer(&timedOut)
+				defer stopTimer()
+			}
+			s.queueLimit.space.Wait()
+		case QueueFullDropOldestQuote:
+			if !s.dropOldestQueuedQuote() {
+				return ErrSessionQueueFull
+			}
+		default:
+			return ErrSessionQueueFull
+		}
+	}
+
+	return nil
+}
+
+// queueFullTimer sets timedOut and wakes the senders waiting for space in the send queue once the queue limit
+// timeout has elapsed on the session's clock, unless stop is called first. Must be called with the sendMutex held.
+func (s *session) queueFullTimer(timedOut *bool) (stop func()) {
+	timer := s.clock.NewTimer(s.queueLimit.timeout)
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-timer.C():
+			s.sendMutex.Lock()
+			*timedOut = true
+			s.sendMutex.Unlock()
+			s.queueLimit.space.Broadcast()
+		case <-stopped:
+		}
+	}()
+
+	return func() {
+		timer.Stop()
+		close(stopped)
+	}
+}
+
+// dropOldestQueuedQuote removes the oldest quote from the send queue, returning false if there is none.
+// Must be called with the sendMutex held.
+func (s *session) dropOldestQueuedQuote() bool {
+	for i, queued := range s.toSend {
+		if isQuoteMsgBytes(queued.bytes) {
+			queued.drop()
+			s.toSend = append(s.toSend[:i], s.toSend[i+1:]...)
+			s.log.OnEvent("Send queue full, dropped oldest queued quote")
+			return true
+		}
+	}
+
+	if s.throttle == nil {
+		return false
+	}
+
+	for i, msg := range s.throttle.queue {
+		if msgType, err := msg.Header.GetBytes(tagMsgType); err == nil && isQuoteMsgType(msgType) {
+			s.throttle.queue = append(s.throttle.queue[:i], s.throttle.queue[i+1:]...)
+			msg.receipt.done(errDroppedFromQueue)
+			msg.receipt = nil
+			s.log.OnEvent("Send queue full, dropped oldest queued quote")
+			return true
+		}
+	}
+
+	return false
+}
+
+// signalQueueSpace wakes senders blocked on a full send queue. Must be called with the sendMutex held.
+func (s *session) signalQueueSpace() {
+	if s.queueLimit != nil {
+		s.queueLimit.space.Broadcast()
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+type SendQueueTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestSendQueueTestSuite(t *testing.T) {
+	suite.Run(t, new(SendQueueTestSuite))
+}
+
+func (s *SendQueueTestSuite) SetupTest() {
+	s.Init()
+	s.MockApp.On("ToApp").Return(nil)
+}
+
+func (s *SendQueueTestSuite) limit(capacity int, policy QueueFullPolicy) {
+	s.session.queueLimit = &sendQueueLimit{capacity: capacity, policy: policy, timeout: defaultQueueFullTimeout,
+		space: sync.NewCond(&s.session.sendMutex)}
+}
+
+func (s *SendQueueTestSuite) quote() *Message {
+	return s.buildMessage("S")
+}
+
+func (s *SendQueueTestSuite) TestError() {
+	s.limit(2, QueueFullError)
+
+	s.Nil(s.session.queueForSend(s.NewOrderSingle()))
+	s.Nil(s.session.queueForSend(s.NewOrderSingle()))
+	s.Equal(ErrSessionQueueFull, s.session.queueForSend(s.NewOrderSingle()))
+	s.Len(s.session.toSend, 2)
+	s.NextSenderMsgSeqNum(3)
+
+	// Admin messages are not limited.
+	s.MockApp.On("ToAdmin")
+	s.Nil(s.session.queueForSend(s.Heartbeat()))
+	s.Len(s.session.toSend, 3)
+}
+
+func (s *SendQueueTestSuite) TestDropOldestQuote() {
+	s.limit(2, QueueFullDropOldestQuote)
+
+	s.Nil(s.session.queueForSend(s.NewOrderSingle()))
+	s.Nil(s.session.queueForSend(s.quote()))
+	s.Nil(s.session.queueForSend(s.quote()))
+	s.Len(s.session.toSend, 2)
+	s.True(isQuoteMsgBytes(s.session.toSend[1].bytes))
+	s.False(isQuoteMsgBytes(s.session.toSend[0].bytes))
+
+	s.Nil(s.session.queueForSend(s.quote()))
+	s.Len(s.session.toSend, 2)
+
+	// With no quote to drop, the send fails.
+	s.session.toSend = s.session.toSend[:1]
+	s.Nil(s.session.queueForSend(s.NewOrderSingle()))
+	s.Equal(ErrSessionQueueFull, s.session.queueForSend(s.NewOrderSingle()))
+}
+
+func (s *SendQueueTestSuite) TestBlock() {
+	s.limit(1, QueueFullBlock)
+	s.Nil(s.session.queueForSend(s.NewOrderSingle()))
+
+	msg := s.NewOrderSingle()
+	sent := make(chan error)
+	go func() { sent <- s.session.queueForSend(msg) }()
+
+	select {
+	case <-sent:
+		s.Fail("send should block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	s.session.sendMutex.Lock()
+	s.session.dropQueued()
+	s.session.sendMutex.Unlock()
+
+	s.Nil(<-sent)
+	s.Len(s.session.toSend, 1)
+}
+
+func (s *SendQueueTestSuite) TestBlockTimeout() {
+	s.limit(1, QueueFullBlock)
+	s.session.queueLimit.timeout = 20 * time.Millisecond
+	s.Nil(s.session.queueForSend(s.NewOrderSingle()))
+
+	s.Equal(ErrSessionQueueFull, s.session.queueForSend(s.NewOrderSingle()))
+	s.Len(s.session.toSend, 1)
+}
+
+func (s *SendQueueTestSuite) TestBlockOnRunLoop() {
+	s.limit(1, QueueFullBlock)
+	s.Nil(s.session.queueForSend(s.NewOrderSingle()))
+
+	// Sent from a callback of the run loop, the send fails rather than wait on the run loop.
+	s.session.setRunning(true, internal.GoroutineID())
+	defer s.session.setRunning(false, 0)
+	s.Equal(ErrSessionQueueFull, s.session.queueForSend(s.NewOrderSingle()))
+}
@@ -0,0 +1,126 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"sync"
+)
+
+// SendReceipt reports the progress of a message sent with SendToTargetAsync.
+type SendReceipt struct {
+	persisted, written       chan struct{}
+	persistOnce, writtenOnce sync.Once
+	msgSeqNum                int
+	err                      error
+}
+
+func newSendReceipt() *SendReceipt {
+	return &SendReceipt{persisted: make(chan struct{}), written: make(chan struct{})}
+}
+
+// Persisted returns a channel that is closed once the message is assigned its MsgSeqNum and persisted to the
+// MessageStore, or failed.
+func (r *SendReceipt) Persisted() <-chan struct{} { return r.persisted }
+
+// Written returns a channel that is closed once the message is written to the connection, or failed.
+func (r *SendReceipt) Written() <-chan struct{} { return r.written }
+
+// MsgSeqNum returns the MsgSeqNum of the message once Persisted is closed, and 0 if it failed.
+func (r *SendReceipt) MsgSeqNum() int {
+	<-r.persisted
+	return r.msgSeqNum
+}
+
+// Err returns why the message was not written once Written is closed, or nil if it was.
+func (r *SendReceipt) Err() error {
+	<-r.written
+	return r.err
+}
+
+// Wait waits until the message is written to the connection, returning its MsgSeqNum, or until ctx is done.
+func (r *SendReceipt) Wait(ctx context.Context) (int, error) {
+	select {
+	case <-r.written:
+		return r.msgSeqNum, r.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func (r *SendReceipt) persist(msgSeqNum int) {
+	if r == nil {
+		return
+	}
+
+	r.persistOnce.Do(func() {
+		r.msgSeqNum = msgSeqNum
+		close(r.persisted)
+	})
+}
+
+func (r *SendReceipt) done(err error) {
+	if r == nil {
+		return
+	}
+
+	r.persist(0)
+	r.writtenOnce.Do(func() {
+		r.err = err
+		close(r.written)
+	})
+}
+
+// SendToTargetAsync sends a message like SendToTarget, returning a SendReceipt resolved once the message is
+// persisted and once it is written to the connection. Like SendToTarget, it blocks while the send queue or the
+// throttle of the session blocks.
+func SendToTargetAsync(m Messagable, sessionID SessionID) (*SendReceipt, error) {
+	receipt := newSendReceipt()
+	msg := m.ToMessage()
+	msg.receipt = receipt
+
+	if err := SendToTarget(m, sessionID); err != nil {
+		msg.receipt = nil
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// handOffReceipt registers the receipt of queued before its bytes are handed to the connection.
+func (s *session) handOffReceipt(queued queuedMessage) {
+	if queued.receipt != nil {
+		s.pendingWrites.Store(&queued.bytes[0], queued.receipt)
+	}
+}
+
+// cancelReceipt unregisters the receipt of queued after its bytes failed to be handed to the connection.
+func (s *session) cancelReceipt(queued queuedMessage) {
+	if queued.receipt != nil {
+		s.pendingWrites.Delete(&queued.bytes[0])
+	}
+}
+
+// messageWritten resolves the receipt of msg once the connection wrote it.
+func (s *session) messageWritten(msg []byte, err error) {
+	if len(msg) == 0 {
+		return
+	}
+
+	if receipt, ok := s.pendingWrites.LoadAndDelete(&msg[0]); ok {
+		receipt.(*SendReceipt).done(err)
+	}
+}
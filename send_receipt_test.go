@@ -0,0 +1,114 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SendReceiptTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestSendReceiptTestSuite(t *testing.T) {
+	suite.Run(t, new(SendReceiptTestSuite))
+}
+
+func (s *SendReceiptTestSuite) SetupTest() {
+	s.Init()
+	s.session.State = inSession{}
+}
+
+func (s *SendReceiptTestSuite) sendWithReceipt(msg *Message) *SendReceipt {
+	receipt := newSendReceipt()
+	msg.receipt = receipt
+	s.Require().Nil(s.session.send(msg))
+	return receipt
+}
+
+func (s *SendReceiptTestSuite) TestWritten() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.IncrNextSenderMsgSeqNum()
+
+	receipt := s.sendWithReceipt(s.NewOrderSingle())
+	s.MockApp.AssertExpectations(s.T())
+
+	<-receipt.Persisted()
+	s.Equal(2, receipt.MsgSeqNum())
+	select {
+	case <-receipt.Written():
+		s.Fail("receipt should not be written before the connection writes the message")
+	default:
+	}
+
+	var written bytes.Buffer
+	go writeLoop(&written, s.Receiver.sendChannel, nullLog{}, writeBatching{}, s.session.messageWritten)
+	defer close(s.Receiver.sendChannel)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	seqNum, err := receipt.Wait(ctx)
+	s.Nil(err)
+	s.Equal(2, seqNum)
+	s.Contains(written.String(), "\x0134=2\x01")
+}
+
+func (s *SendReceiptTestSuite) TestDropped() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.session.State = latentState{}
+
+	receipt := s.sendWithReceipt(s.NewOrderSingle())
+	<-receipt.Persisted()
+	s.Equal(1, receipt.MsgSeqNum())
+
+	s.session.dropQueued()
+	s.Equal(errDroppedFromQueue, receipt.Err())
+}
+
+func (s *SendReceiptTestSuite) TestDoNotSend() {
+	s.MockApp.On("ToApp").Return(ErrDoNotSend)
+
+	receipt := newSendReceipt()
+	msg := s.NewOrderSingle()
+	msg.receipt = receipt
+	s.Equal(ErrDoNotSend, s.session.send(msg))
+
+	s.Equal(0, receipt.MsgSeqNum())
+	s.Equal(ErrDoNotSend, receipt.Err())
+	s.Nil(msg.receipt)
+}
+
+func (s *SendReceiptTestSuite) TestWaitContextDone() {
+	receipt := newSendReceipt()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := receipt.Wait(ctx)
+	s.Equal(context.Canceled, err)
+}
+
+func (s *SendReceiptTestSuite) TestSendToTargetAsyncUnknownSession() {
+	msg := s.NewOrderSingle()
+	receipt, err := SendToTargetAsync(msg, SessionID{BeginString: BeginStringFIX42, SenderCompID: "unknown", TargetCompID: "unknown"})
+	s.Nil(receipt)
+	s.Equal(errUnknownSession, err)
+	s.Nil(msg.receipt)
+}
@@ -17,9 +17,12 @@ package quickfix
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/quickfixgo/quickfix/datadictionary"
@@ -37,14 +40,95 @@ type session struct {
 	messageIn  <-chan fixIn
 
 	// Application messages are queued up for send here.
-	toSend [][]byte
+	toSend []queuedMessage
 
 	// Mutex for access to toSend.
 	sendMutex sync.Mutex
 
+	// queueLimit bounds toSend and the throttle queue, and may be nil.
+	queueLimit *sendQueueLimit
+
+	// outboundQueue persists application messages sent while not logged on, and may be nil.
+	outboundQueue *outboundQueue
+
+	// duplicates detects inbound application messages received more than once, and may be nil.
+	duplicates *duplicateWindow
+
 	sessionEvent chan internal.Event
 	messageEvent chan bool
 	application  Application
+	// throttle limits the rate of application messages, and may be nil.
+	throttle *throttle
+	// authenticator is set by the Acceptor, and may be nil.
+	authenticator Authenticator
+
+	// stateListener is set by the Acceptor or Initiator, and may be nil.
+	stateListener SessionStateListener
+
+	// tracer is set by the Acceptor or Initiator, and may be nil.
+	tracer Tracer
+
+	// events is set by the Acceptor or Initiator, and may be nil.
+	events *EventBus
+
+	// latencyObserver is set by the Acceptor or Initiator, and may be nil.
+	latencyObserver LatencyObserver
+
+	// dispatcher is set by the Acceptor or Initiator, and may be nil.
+	dispatcher *Dispatcher
+
+	// validationPolicy disables the checks of the Validator, and is extended by the Acceptor or Initiator.
+	validationPolicy *ValidationPolicy
+
+	// validatorSettings are the settings of the Validators, kept to rebuild them when a data dictionary is reloaded.
+	validatorSettings ValidatorSettings
+
+	// zeroAllocParser parses inbound messages if ZeroAllocParser is set, and may be nil.
+	zeroAllocParser *zeroAllocParser
+
+	// pendingWrites holds the SendReceipts of messages handed to the connection, keyed by their first byte.
+	pendingWrites sync.Map
+
+	dispatchedRejectsMu sync.Mutex
+	dispatchedRejects   []dispatchedReject
+
+	// inbound and outbound wrap the Application in the middleware of the Acceptor or Initiator, and may be nil.
+	inbound  InboundHandler
+	outbound OutboundHandler
+
+	clock Clock
+
+	// loop runs the session in place of its own run loop in shared mode, and is nil in dedicated mode.
+	loop *eventLoop
+
+	stats        sessionStats
+	healthStatus sessionHealth
+
+	// draining is set while the session logs out with LogoutAndDrain, and rejects application messages.
+	draining atomic.Bool
+	drain    *drainState
+
+	// storeClosed is set once the store is closed by the engine stopping, to be recreated if it is started again.
+	storeClosed bool
+
+	// runMu guards running and runDone. running is set while the session's run loop is processing admin requests,
+	// and runDone is closed when it stops.
+	runMu   sync.Mutex
+	running bool
+	runDone chan struct{}
+
+	// loopID is the ID of the goroutine of the run loop, or event loop, while it runs, and 0 otherwise.
+	loopID atomic.Uint64
+
+	// dial replaces dialing the SocketConnectAddress of an initiator session if set with SetDialer.
+	dial atomic.Pointer[DialFunc]
+
+	// remoteAddr is the address of the counterparty while connected.
+	remoteAddr atomic.Pointer[net.Addr]
+
+	// connCtx is cancelled when the session disconnects.
+	connCtx    context.Context
+	connCancel context.CancelFunc
 	Validator
 	stateMachine
 	stateTimer *internal.EventTimer
@@ -59,10 +143,16 @@ type session struct {
 	transportDataDictionary *datadictionary.DataDictionary
 	appDataDictionary       *datadictionary.DataDictionary
 
+	// appDataDictionaries and appValidators are keyed by ApplVerID, if AppDataDictionary is set for more than the
+	// DefaultApplVerID of a FIXT session.
+	appDataDictionaries map[string]*datadictionary.DataDictionary
+	appValidators       map[string]Validator
+
 	timestampPrecision TimestampPrecision
 }
 
 func (s *session) logError(err error) {
+	s.healthStatus.errorOccurred(err, s.clock.Now())
 	s.log.OnEvent(err.Error())
 }
 
@@ -75,16 +165,18 @@ func (s *session) TargetDefaultApplicationVersionID() string {
 type connect struct {
 	messageOut chan<- []byte
 	messageIn  <-chan fixIn
+	remoteAddr net.Addr
 	err        chan<- error
 }
 
-func (s *session) connect(msgIn <-chan fixIn, msgOut chan<- []byte) error {
-	rep := make(chan error)
-	s.admin <- connect{
+func (s *session) connect(msgIn <-chan fixIn, msgOut chan<- []byte, remoteAddr net.Addr) error {
+	rep := make(chan error, 1)
+	s.sendAdmin(connect{
 		messageOut: msgOut,
 		messageIn:  msgIn,
+		remoteAddr: remoteAddr,
 		err:        rep,
-	}
+	})
 
 	return <-rep
 }
@@ -94,7 +186,7 @@ type stopReq struct{}
 func (s *session) stop() {
 	// Stop once.
 	s.stopOnce.Do(func() {
-		s.admin <- stopReq{}
+		s.sendAdmin(stopReq{})
 	})
 }
 
@@ -103,15 +195,160 @@ type waitChan <-chan interface{}
 type waitForInSessionReq struct{ rep chan<- waitChan }
 
 func (s *session) waitForInSessionTime() {
-	rep := make(chan waitChan)
-	s.admin <- waitForInSessionReq{rep}
+	rep := make(chan waitChan, 1)
+	s.sendAdmin(waitForInSessionReq{rep})
 	if wait, ok := <-rep; ok {
 		<-wait
 	}
 }
 
+type resetReq struct{ rep chan<- error }
+
+type setSeqNumsReq struct {
+	sender, target    int
+	sendSequenceReset bool
+	rep               chan<- error
+}
+
+// doAdmin processes req in the session's run loop, so that it does not race with message processing, and waits
+// for its reply.
+func (s *session) doAdmin(req interface{}, rep <-chan error) error {
+	s.postAdmin(req, nil)
+	return <-rep
+}
+
+// sendAdmin hands req to the session's run loop, which must be running in dedicated mode, waiting for it to be received.
+func (s *session) sendAdmin(req interface{}) {
+	if s.loop != nil {
+		s.postAdmin(req, nil)
+		return
+	}
+
+	s.admin <- req
+}
+
+// postAdmin hands req to the session's run loop, or event loop. If the session is not running, or req is made by a callback of the
+// run loop, req is processed directly. It returns false if timeout fires before the run loop receives req.
+func (s *session) postAdmin(req interface{}, timeout <-chan time.Time) bool {
+	if s.onRunLoop() {
+		s.onAdmin(req)
+		return true
+	}
+
+	for {
+		s.runMu.Lock()
+		if !s.running {
+			s.onAdmin(req)
+			s.runMu.Unlock()
+			return true
+		}
+		if s.loop != nil {
+			// Posted with runMu held, so that the loop processes req before it stops.
+			loop := s.loop
+			loop.post(func() { loop.admin(s, req) })
+			s.runMu.Unlock()
+			return true
+		}
+		done := s.runDone
+		s.runMu.Unlock()
+
+		select {
+		case s.admin <- req:
+			return true
+		case <-done:
+			// The run loop stopped before receiving req.
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+// setRunning marks the run loop, running on the goroutine of loopID, as started or stopped.
+func (s *session) setRunning(running bool, loopID uint64) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	s.running = running
+	if running {
+		s.runDone = make(chan struct{})
+		s.loopID.Store(loopID)
+	} else {
+		s.loopID.Store(0)
+		close(s.runDone)
+	}
+}
+
+// onRunLoop returns whether it is called on the goroutine of the run loop, such as by a callback of the Application.
+func (s *session) onRunLoop() bool {
+	id := s.loopID.Load()
+	return id != 0 && id == internal.GoroutineID()
+}
+
+func (s *session) reset() error {
+	rep := make(chan error, 1)
+	return s.doAdmin(resetReq{rep}, rep)
+}
+
+func (s *session) setNextSeqNums(sender, target int, sendSequenceReset bool) error {
+	rep := make(chan error, 1)
+	return s.doAdmin(setSeqNumsReq{sender: sender, target: target, sendSequenceReset: sendSequenceReset, rep: rep}, rep)
+}
+
+func (s *session) onReset() error {
+	s.log.OnEvent("Session reset")
+	if s.State != nil {
+		s.State.ShutdownNow(s)
+	}
+	if err := s.dropAndReset(); err != nil {
+		s.logError(err)
+		return err
+	}
+	s.publishEvent(EventSeqNumReset, "Session reset")
+
+	return nil
+}
+
+func (s *session) onSetNextSeqNums(req setSeqNumsReq) error {
+	if req.sender <= 0 || req.target <= 0 {
+		return errors.New("sequence numbers must be positive")
+	}
+
+	// The SequenceReset is written, and the sequence numbers set, without another message being sent in between.
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+
+	if req.sendSequenceReset && s.IsLoggedOn() {
+		sequenceReset := NewMessage()
+		sequenceReset.Header.SetBytes(tagMsgType, msgTypeSequenceReset)
+		sequenceReset.Body.SetField(tagNewSeqNo, FIXInt(req.sender))
+		sequenceReset.Body.SetField(tagGapFillFlag, FIXBoolean(false))
+		queued, err := s.prepMessageForSend(sequenceReset, nil)
+		if err != nil {
+			return err
+		}
+
+		// Messages queued before the SequenceReset keep their MsgSeqNums, and are sent first.
+		s.sendQueued(true)
+		if !s.sendQueuedMessage(queued, true) {
+			return errNotSent
+		}
+	}
+
+	if err := s.store.SetNextTargetMsgSeqNum(req.target); err != nil {
+		return err
+	}
+
+	if err := s.store.SetNextSenderMsgSeqNum(req.sender); err != nil {
+		return err
+	}
+
+	s.log.OnEventf("Sequence numbers set, next sender %v, next target %v", req.sender, req.target)
+	s.publishEvent(EventSeqNumReset, "Sequence numbers set, next sender %v, next target %v", req.sender, req.target)
+	return nil
+}
+
 func (s *session) insertSendingTime(msg *Message) {
-	sendingTime := time.Now().UTC()
+	sendingTime := s.clock.Now().UTC()
 
 	if s.sessionID.BeginString >= BeginStringFIX42 {
 		msg.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: sendingTime, Precision: s.timestampPrecision})
@@ -196,9 +433,8 @@ func (s *session) sendLogonInReplyTo(setResetSeqNum bool, inReplyTo *Message) er
 				logon.Body.SetField(tagNextExpectedMsgSeqNum, FIXInt(nextSeqNum+1))
 			}
 		} else {
-			// We are sending a logon.
-			nextseqnum := s.store.NextTargetMsgSeqNum()
-			logon.Body.SetField(tagNextExpectedMsgSeqNum, FIXInt(nextseqnum+1))
+			// We are sending a logon, the next message expected is the logon response.
+			logon.Body.SetField(tagNextExpectedMsgSeqNum, FIXInt(s.store.NextTargetMsgSeqNum()))
 		}
 	}
 
@@ -209,31 +445,6 @@ func (s *session) sendLogonInReplyTo(setResetSeqNum bool, inReplyTo *Message) er
 	return nil
 }
 
-func (s *session) generateSequenceReset(beginSeqNo int, endSeqNo int, inReplyTo Message) (err error) {
-	sequenceReset := NewMessage()
-	s.fillDefaultHeader(sequenceReset, &inReplyTo)
-
-	sequenceReset.Header.SetField(tagMsgType, FIXString("4"))
-	sequenceReset.Header.SetField(tagMsgSeqNum, FIXInt(beginSeqNo))
-	sequenceReset.Header.SetField(tagPossDupFlag, FIXBoolean(true))
-	sequenceReset.Body.SetField(tagNewSeqNo, FIXInt(endSeqNo))
-	sequenceReset.Body.SetField(tagGapFillFlag, FIXBoolean(true))
-
-	var origSendingTime FIXString
-	if err := sequenceReset.Header.GetField(tagSendingTime, &origSendingTime); err == nil {
-		sequenceReset.Header.SetField(tagOrigSendingTime, origSendingTime)
-	}
-
-	s.application.ToAdmin(sequenceReset, s.sessionID)
-
-	msgBytes := sequenceReset.build()
-
-	s.EnqueueBytesAndSend(msgBytes)
-	s.log.OnEventf("Sent SequenceReset TO: %v", endSeqNo)
-
-	return
-}
-
 func (s *session) buildLogout(reason string) *Message {
 	logout := NewMessage()
 	logout.Header.SetField(tagMsgType, FIXString("5"))
@@ -266,20 +477,39 @@ func (s *session) resend(msg *Message) bool {
 
 	s.insertSendingTime(msg)
 
-	return s.application.ToApp(msg, s.sessionID) == nil
+	return s.interceptOutbound(msg) == nil
 }
 
 // queueForSend will validate, persist, and queue the message for send.
 func (s *session) queueForSend(msg *Message) error {
+	if s.outboundQueue != nil {
+		s.sendMutex.Lock()
+		queued, err := s.queueOutbound(msg)
+		s.sendMutex.Unlock()
+		if queued || err != nil {
+			return err
+		}
+	}
+
+	if s.throttle != nil {
+		if queued, err := s.throttleSend(msg); queued || err != nil {
+			return err
+		}
+	}
+
 	s.sendMutex.Lock()
 	defer s.sendMutex.Unlock()
 
-	msgBytes, err := s.prepMessageForSend(msg, nil)
+	if err := s.reserveQueueSpace(msg); err != nil {
+		return err
+	}
+
+	queued, err := s.prepMessageForSend(msg, nil)
 	if err != nil {
 		return err
 	}
 
-	s.toSend = append(s.toSend, msgBytes)
+	s.toSend = append(s.toSend, queued)
 
 	s.notifyMessageOut()
 
@@ -289,6 +519,9 @@ func (s *session) queueForSend(msg *Message) error {
 func (s *session) notifyMessageOut() {
 	select {
 	case s.messageEvent <- true:
+		if loop := s.loop; loop != nil {
+			loop.post(func() { loop.sendMessages(s) })
+		}
 	default:
 	}
 }
@@ -305,12 +538,12 @@ func (s *session) sendInReplyTo(msg *Message, inReplyTo *Message) error {
 	s.sendMutex.Lock()
 	defer s.sendMutex.Unlock()
 
-	msgBytes, err := s.prepMessageForSend(msg, inReplyTo)
+	queued, err := s.prepMessageForSend(msg, inReplyTo)
 	if err != nil {
 		return err
 	}
 
-	s.toSend = append(s.toSend, msgBytes)
+	s.toSend = append(s.toSend, queued)
 	s.sendQueued(true)
 
 	return nil
@@ -333,19 +566,33 @@ func (s *session) dropAndSendInReplyTo(msg *Message, inReplyTo *Message) error {
 	s.sendMutex.Lock()
 	defer s.sendMutex.Unlock()
 
-	msgBytes, err := s.prepMessageForSend(msg, inReplyTo)
+	queued, err := s.prepMessageForSend(msg, inReplyTo)
 	if err != nil {
 		return err
 	}
 
 	s.dropQueued()
-	s.toSend = append(s.toSend, msgBytes)
+	s.toSend = append(s.toSend, queued)
 	s.sendQueued(true)
 
 	return nil
 }
 
-func (s *session) prepMessageForSend(msg *Message, inReplyTo *Message) (msgBytes []byte, err error) {
+// prepMessageForSend prepares msg and persists it, returning it queued for send with its SpanOutbound span.
+func (s *session) prepMessageForSend(msg *Message, inReplyTo *Message) (queued queuedMessage, err error) {
+	parentCtx := msg.ctx
+	ctx, span := s.startSpan(msg.Context(), SpanOutbound)
+	receipt := msg.receipt
+	msg.receipt = nil
+	defer func() {
+		msg.ctx = parentCtx
+		if err != nil {
+			span.End(err)
+			receipt.done(err)
+		}
+	}()
+	msg.ctx = ctx
+
 	s.fillDefaultHeader(msg, inReplyTo)
 	seqNum := s.store.NextSenderMsgSeqNum()
 	msg.Header.SetField(tagMsgSeqNum, FIXInt(seqNum))
@@ -355,37 +602,62 @@ func (s *session) prepMessageForSend(msg *Message, inReplyTo *Message) (msgBytes
 		return
 	}
 
-	if isAdminMessageType(msgType) {
-		s.application.ToAdmin(msg, s.sessionID)
-		if bytes.Equal(msgType, msgTypeLogon) {
-			var resetSeqNumFlag FIXBoolean
-			if msg.Body.Has(tagResetSeqNumFlag) {
-				if err = msg.Body.GetField(tagResetSeqNumFlag, &resetSeqNumFlag); err != nil {
-					return
-				}
+	toAppSpan := s.startMessageSpan(msg, SpanToApp)
+	if !isAdminMessageType(msgType) {
+		queued.toAppAt = s.clock.Now()
+	}
+	err = s.interceptOutbound(msg)
+	if err == nil && bytes.Equal(msgType, msgTypeLogon) {
+		var resetSeqNumFlag FIXBoolean
+		if msg.Body.Has(tagResetSeqNumFlag) {
+			if err = msg.Body.GetField(tagResetSeqNumFlag, &resetSeqNumFlag); err != nil {
+				toAppSpan.End(err)
+				return
 			}
+		}
 
-			if resetSeqNumFlag.Bool() {
-				if err = s.store.Reset(); err != nil {
-					return
-				}
-
-				s.sentReset = true
-				seqNum = s.store.NextSenderMsgSeqNum()
-				msg.Header.SetField(tagMsgSeqNum, FIXInt(seqNum))
+		if resetSeqNumFlag.Bool() {
+			if err = s.store.Reset(); err != nil {
+				toAppSpan.End(err)
+				return
 			}
+
+			s.sentReset = true
+			s.publishEvent(EventSeqNumReset, "Sent Logon with ResetSeqNumFlag=Y")
+			seqNum = s.store.NextSenderMsgSeqNum()
+			msg.Header.SetField(tagMsgSeqNum, FIXInt(seqNum))
 		}
-	} else {
-		if err = s.application.ToApp(msg, s.sessionID); err != nil {
-			return
-		}
 	}
+	toAppSpan.End(err)
+	if err != nil {
+		return
+	}
+	setSpanMessage(span, msg)
 
 	// Message converted to bytes here.
-	msgBytes = msg.build()
-	err = s.persist(seqNum, msgBytes)
+	serializeSpan := s.startMessageSpan(msg, SpanSerialize)
+	var msgBytes []byte
+	if msg.restored {
+		msgBytes = msg.buildWithBodyBytes(msg.bodyBytes)
+	} else {
+		msgBytes = msg.build()
+	}
+	serializeSpan.End(nil)
 
-	return
+	storeSpan := s.startMessageSpan(msg, SpanStore)
+	if msg.transient || s.TransientMsgTypes[string(msgType)] {
+		err = s.store.IncrNextSenderMsgSeqNum()
+	} else {
+		err = s.persist(seqNum, msgBytes)
+	}
+	storeSpan.End(err)
+
+	if err == nil {
+		receipt.persist(seqNum)
+	}
+
+	queued.bytes, queued.ctx, queued.span, queued.receipt = msgBytes, ctx, span, receipt
+	return queued, err
 }
 
 func (s *session) persist(seqNum int, msgBytes []byte) error {
@@ -397,29 +669,57 @@ func (s *session) persist(seqNum int, msgBytes []byte) error {
 }
 
 func (s *session) sendQueued(blockUntilSent bool) {
-	for i, msgBytes := range s.toSend {
-		if !s.sendBytes(msgBytes, blockUntilSent) {
+	for i, queued := range s.toSend {
+		if !s.sendQueuedMessage(queued, blockUntilSent) {
 			s.toSend = s.toSend[i:]
+			s.signalQueueSpace()
 			s.notifyMessageOut()
 			return
 		}
 	}
 
-	s.dropQueued()
+	s.toSend = s.toSend[:0]
+	s.signalQueueSpace()
 }
 
 func (s *session) dropQueued() {
+	for _, queued := range s.toSend {
+		queued.drop()
+	}
 	s.toSend = s.toSend[:0]
+	s.signalQueueSpace()
 }
 
 func (s *session) EnqueueBytesAndSend(msg []byte) {
 	s.sendMutex.Lock()
 	defer s.sendMutex.Unlock()
 
-	s.toSend = append(s.toSend, msg)
+	s.toSend = append(s.toSend, queuedMessage{bytes: msg})
 	s.sendQueued(true)
 }
 
+// sendQueuedMessage sends queued, tracing the hand-off to the connection as a SpanWrite span.
+func (s *session) sendQueuedMessage(queued queuedMessage, blockUntilSent bool) bool {
+	if queued.span == nil {
+		return s.sendBytes(queued.bytes, blockUntilSent)
+	}
+
+	_, span := s.startSpan(queued.ctx, SpanWrite)
+	s.handOffReceipt(queued)
+	if !s.sendBytes(queued.bytes, blockUntilSent) {
+		s.cancelReceipt(queued)
+		span.End(errNotSent)
+		return false
+	}
+
+	span.End(nil)
+	queued.span.End(nil)
+	if !queued.toAppAt.IsZero() {
+		s.observeOutboundLatency(queued.toAppAt)
+	}
+	return true
+}
+
 func (s *session) sendBytes(msg []byte, blockUntilSent bool) bool {
 	if s.messageOut == nil {
 		s.log.OnEventf("Failed to send: disconnected")
@@ -449,9 +749,26 @@ func (s *session) doTargetTooHigh(reject targetTooHigh) (nextState resendState,
 }
 
 func (s *session) sendResendRequest(beginSeq, endSeq int) (nextState resendState, err error) {
+	resend, nextState := s.buildResendRequest(beginSeq, endSeq)
+	if err = s.send(resend); err != nil {
+		return
+	}
+	s.logResendRequest(resend)
+
+	return
+}
+
+func (s *session) logResendRequest(resend *Message) {
+	beginSeqNo, _ := resend.Body.GetInt(tagBeginSeqNo)
+	endSeqNo, _ := resend.Body.GetInt(tagEndSeqNo)
+	s.log.OnEventf("Sent ResendRequest FROM: %v TO: %v", beginSeqNo, endSeqNo)
+	s.publishEvent(EventResend, "Sent ResendRequest FROM: %v TO: %v", beginSeqNo, endSeqNo)
+}
+
+func (s *session) buildResendRequest(beginSeq, endSeq int) (resend *Message, nextState resendState) {
 	nextState.resendRangeEnd = endSeq
 
-	resend := NewMessage()
+	resend = NewMessage()
 	resend.Header.SetBytes(tagMsgType, msgTypeResendRequest)
 	resend.Body.SetField(tagBeginSeqNo, FIXInt(beginSeq))
 
@@ -473,11 +790,6 @@ func (s *session) sendResendRequest(beginSeq, endSeq int) (nextState resendState
 	}
 	resend.Body.SetField(tagEndSeqNo, FIXInt(endSeqNo))
 
-	if err = s.send(resend); err != nil {
-		return
-	}
-	s.log.OnEventf("Sent ResendRequest FROM: %v TO: %v", beginSeq, endSeqNo)
-
 	return
 }
 
@@ -490,7 +802,10 @@ func (s *session) handleLogon(msg *Message) error {
 			return err
 		}
 
-		s.targetDefaultApplVerID = string(targetApplVerID)
+		s.targetDefaultApplVerID = normalizeApplVerID(string(targetApplVerID))
+		if err := s.checkTargetDefaultApplVerID(); err != nil {
+			return err
+		}
 	}
 
 	resetStore := false
@@ -507,13 +822,17 @@ func (s *session) handleLogon(msg *Message) error {
 		}
 	}
 
-	nextSenderMsgNumAtLogonReceived := s.store.NextSenderMsgSeqNum()
-
 	// Make sure this is a valid session before resetting the store.
 	if err := s.verifyMsgAgainstAppImpl(msg); err != nil {
 		return err
 	}
 
+	if !s.InitiateLogon && s.authenticator != nil {
+		if err := s.authenticate(msg); err != nil {
+			return err
+		}
+	}
+
 	var resetSeqNumFlag FIXBoolean
 	if err := msg.Body.GetField(tagResetSeqNumFlag, &resetSeqNumFlag); err == nil {
 		if resetSeqNumFlag {
@@ -528,6 +847,7 @@ func (s *session) handleLogon(msg *Message) error {
 		if err := s.store.Reset(); err != nil {
 			return err
 		}
+		s.publishEvent(EventSeqNumReset, "Received Logon with ResetSeqNumFlag=Y")
 	}
 
 	// Verify seq num too high but dont check against app implementation since we just did that.
@@ -551,31 +871,49 @@ func (s *session) handleLogon(msg *Message) error {
 	}
 	s.sentReset = false
 
-	s.peerTimer.Reset(time.Duration(float64(1.2) * float64(s.HeartBtInt)))
-	s.application.OnLogon(s.sessionID)
-
-	// Evaluate tag 789 to see if we end up with an implied gapfill/resend.
-	if s.EnableNextExpectedMsgSeqNum && !msg.Body.Has(tagResetSeqNumFlag) {
-		targetWantsNextSeqNumToBe, getErr := msg.Body.GetInt(tagNextExpectedMsgSeqNum)
-		if getErr == nil {
-			if targetWantsNextSeqNumToBe != nextSenderMsgNumAtLogonReceived {
-				if !s.DisableMessagePersist {
-					seqResetErr := s.generateSequenceReset(targetWantsNextSeqNumToBe, nextSenderMsgNumAtLogonReceived+1, *msg)
-					if seqResetErr != nil {
-						return seqResetErr
-					}
-				} else {
-					return targetTooHigh{ReceivedTarget: targetWantsNextSeqNumToBe, ExpectedTarget: nextSenderMsgNumAtLogonReceived}
-				}
-			}
+	// Evaluate tag 789 to see if we end up with an implied resend.
+	if s.EnableNextExpectedMsgSeqNum && !resetSeqNumFlag.Bool() {
+		if err := s.resendNextExpected(msg); err != nil {
+			return err
 		}
 	}
 
+	s.peerTimer.Reset(s.peerTimeout())
+	s.onLogon(msg)
+	s.publishEvent(EventLogon, "Logged on")
+	if s.outboundQueue != nil {
+		s.notifyMessageOut()
+	}
+
 	if err := s.checkTargetTooHigh(msg); err != nil {
 		return err
 	}
 
-	return s.store.IncrNextTargetMsgSeqNum()
+	return s.incrNextTargetMsgSeqNum(msg)
+}
+
+// resendNextExpected retransmits the messages sent before our Logon that the counterparty has not received,
+// as indicated by the NextExpectedMsgSeqNum(789) of its Logon.
+func (s *session) resendNextExpected(msg *Message) error {
+	nextExpected, err := msg.Body.GetInt(tagNextExpectedMsgSeqNum)
+	if err != nil {
+		return nil
+	}
+
+	// Both sides have sent their Logon by now.
+	logonSeqNum := s.store.NextSenderMsgSeqNum() - 1
+	if nextExpected > logonSeqNum+1 {
+		return RejectLogon{fmt.Sprintf("Tag 789 (NextExpectedMsgSeqNum) is higher than expected. Expected %d, Received %d", logonSeqNum+1, nextExpected)}
+	}
+
+	if nextExpected >= logonSeqNum {
+		return nil
+	}
+
+	s.log.OnEventf("Received NextExpectedMsgSeqNum %d, resending FROM: %d TO: %d", nextExpected, nextExpected, logonSeqNum-1)
+
+	// The range includes our Logon, so the closing gap fill moves the counterparty past it.
+	return inSession{}.resendMessages(s, nextExpected, logonSeqNum, *msg)
 }
 
 func (s *session) initiateLogout(reason string) (err error) {
@@ -588,7 +926,7 @@ func (s *session) initiateLogoutInReplyTo(reason string, inReplyTo *Message) (er
 		return
 	}
 	s.log.OnEvent("Inititated logout request")
-	time.AfterFunc(s.LogoutTimeout, func() { s.sessionEvent <- internal.LogoutTimeout })
+	s.afterEvent(s.LogoutTimeout, internal.LogoutTimeout)
 	return
 }
 
@@ -641,8 +979,11 @@ func (s *session) verifySelect(msg *Message, checkTooHigh bool, checkTooLow bool
 }
 
 func (s *session) verifyMsgAgainstAppImpl(msg *Message) MessageRejectError {
-	if s.Validator != nil {
-		if reject := s.Validator.Validate(msg); reject != nil {
+	if validator := s.validatorFor(msg); validator != nil {
+		span := s.startMessageSpan(msg, SpanValidate)
+		reject := validator.Validate(msg)
+		span.End(reject)
+		if reject != nil {
 			return reject
 		}
 	}
@@ -650,14 +991,53 @@ func (s *session) verifyMsgAgainstAppImpl(msg *Message) MessageRejectError {
 	return s.fromCallback(msg)
 }
 
-func (s *session) fromCallback(msg *Message) MessageRejectError {
-	msgType, err := msg.Header.GetBytes(tagMsgType)
-	if err != nil {
+// onUnknownEnum logs and publishes a value the Validator accepted with EnumWarn.
+func (s *session) onUnknownEnum(msgType string, tag Tag, value []byte) {
+	s.log.OnEventf("Accepted value %q of tag %v in message of MsgType %v, which is not an enumerated value", value, tag, msgType)
+	s.publishEvent(EventValidationWarning, "Value %q of tag %v is not an enumerated value", value, tag)
+}
+
+func (s *session) fromCallback(msg *Message) (reject MessageRejectError) {
+	if _, err := msg.Header.GetBytes(tagMsgType); err != nil {
 		return err
 	}
 
-	if isAdminMessageType(msgType) {
-		return s.application.FromAdmin(msg, s.sessionID)
+	span := s.startMessageSpan(msg, SpanCallback)
+	defer func() { span.End(reject) }()
+
+	return s.interceptInbound(msg)
+}
+
+func (s *session) fromApp(msg *Message) MessageRejectError {
+	if s.dispatcher != nil {
+		s.dispatcher.dispatch(s, msg.detach())
+		return nil
+	}
+
+	return s.deliverApp(msg)
+}
+
+// deliverApp passes an application message to the Application.
+func (s *session) deliverApp(msg *Message) MessageRejectError {
+	defer s.observeInboundLatency(msg)
+
+	if app, ok := s.application.(PossDupApplication); ok {
+		var possDup FIXBoolean
+		if msg.Header.Has(tagPossDupFlag) {
+			if err := msg.Header.GetField(tagPossDupFlag, &possDup); err != nil {
+				return err
+			}
+		}
+
+		if possDup {
+			return app.FromAppPossDup(msg, s.sessionID)
+		}
+	}
+
+	if app, ok := s.application.(contextApplication); ok {
+		ctx, cancel := s.callbackContext(msg, true)
+		defer cancel()
+		return app.app.FromApp(ctx, msg, s.sessionID)
 	}
 
 	return s.application.FromApp(msg, s.sessionID)
@@ -731,7 +1111,8 @@ func (s *session) checkSendingTime(msg *Message) MessageRejectError {
 		return err
 	}
 
-	if delta := time.Since(sendingTime); delta <= -1*s.MaxLatency || delta >= s.MaxLatency {
+	if delta := s.clock.Now().Sub(sendingTime); delta <= -1*s.MaxLatency || delta >= s.MaxLatency {
+		s.log.OnEventf("SendingTime %v differs from local time by %v, more than MaxLatency %v", sendingTime.UTC(), delta, s.MaxLatency)
 		return sendingTimeAccuracyProblem()
 	}
 
@@ -795,6 +1176,7 @@ func (s *session) doReject(msg *Message, rej MessageRejectError) error {
 	}
 
 	s.log.OnEventf("Message Rejected: %v", rej.Error())
+	s.publishEvent(EventReject, "Sent reject: %v", rej.Error())
 	return s.sendInReplyTo(reply, msg)
 }
 
@@ -817,6 +1199,19 @@ func (s *session) onDisconnect() {
 	}
 
 	s.messageIn = nil
+	s.remoteAddr.Store(nil)
+	if s.connCancel != nil {
+		s.connCancel()
+		s.connCtx, s.connCancel = nil, nil
+	}
+}
+
+// connContext returns a context that is cancelled when the session disconnects.
+func (s *session) connContext() context.Context {
+	if s.connCtx == nil {
+		return context.Background()
+	}
+	return s.connCtx
 }
 
 func (s *session) onAdmin(msg interface{}) {
@@ -848,11 +1243,33 @@ func (s *session) onAdmin(msg interface{}) {
 		s.messageIn = msg.messageIn
 		s.messageOut = msg.messageOut
 		s.sentReset = false
+		if msg.remoteAddr != nil {
+			s.remoteAddr.Store(&msg.remoteAddr)
+		}
+		s.connCtx, s.connCancel = context.WithCancel(context.Background())
 
 		s.Connect(s)
 
 	case stopReq:
-		s.Stop(s)
+		// Sessions never started have nothing to stop.
+		if s.State != nil {
+			s.Stop(s)
+		}
+
+	case logoutAndDrainReq:
+		s.onLogoutAndDrain(msg)
+
+	case resetReq:
+		msg.rep <- s.onReset()
+
+	case setSeqNumsReq:
+		msg.rep <- s.onSetNextSeqNums(msg)
+
+	case reloadDataDictionaryReq:
+		msg.rep <- s.onReloadDataDictionary(msg.dict)
+
+	case dataDictionariesReq:
+		msg.rep <- s.onDataDictionaries(msg)
 
 	case waitForInSessionReq:
 		if !s.IsSessionTime() {
@@ -863,34 +1280,37 @@ func (s *session) onAdmin(msg interface{}) {
 }
 
 func (s *session) run() {
+	s.setRunning(true, internal.GoroutineID())
 	s.Start(s)
 	var stopChan = make(chan struct{})
-	s.stateTimer = internal.NewEventTimer(func() {
+	s.stateTimer = internal.NewEventTimerWithTimer(func() {
 		select {
 		// Deadlock in write to chan s.sessionEvent after s.Stopped()==true and end of loop session.go:766 because no reader of chan s.sessionEvent.
 		case s.sessionEvent <- internal.NeedHeartbeat:
 		case <-stopChan:
 		}
-	})
-	s.peerTimer = internal.NewEventTimer(func() {
+	}, s.clock.NewTimer(time.Second))
+	s.peerTimer = internal.NewEventTimerWithTimer(func() {
 		select {
 		// Deadlock in write to chan s.sessionEvent after s.Stopped()==true and end of loop session.go:766 because no reader of chan s.sessionEvent.
 		case s.sessionEvent <- internal.PeerTimeout:
 		case <-stopChan:
 		}
-
-	})
+	}, s.clock.NewTimer(time.Second))
 
 	// Without this sleep the ticker will be aligned at the millisecond which
 	// corresponds to the creation of the session. If the session creation
 	// happened at 07:00:00.678 and the session StartTime is 07:30:00, any new
 	// connection received between 07:30:00.000 and 07:30:00.677 will be
 	// rejected. Aligning the ticker with a round second fixes that.
-	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	now := s.clock.Now()
+	<-s.clock.NewTimer(now.Truncate(time.Second).Add(time.Second).Sub(now)).C()
 
-	ticker := time.NewTicker(time.Second)
+	ticker := s.clock.NewTicker(time.Second)
 
 	defer func() {
+		s.setRunning(false, 0)
+		s.finishDrain(nil)
 		close(stopChan)
 		s.stateTimer.Stop()
 		s.peerTimer.Stop()
@@ -904,6 +1324,7 @@ func (s *session) run() {
 			s.onAdmin(msg)
 
 		case <-s.messageEvent:
+			s.sendDispatchedRejects()
 			s.SendAppMessages(s)
 
 		case fixIn, ok := <-s.messageIn:
@@ -916,9 +1337,11 @@ func (s *session) run() {
 		case evt := <-s.sessionEvent:
 			s.Timeout(s, evt)
 
-		case now := <-ticker.C:
+		case now := <-ticker.C():
 			s.CheckSessionTime(s, now)
 			s.CheckResetTime(s, now)
 		}
+
+		s.checkDrain(s.clock.Now())
 	}
 }
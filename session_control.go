@@ -0,0 +1,110 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"sync"
+)
+
+var errNotStarted = errors.New("Not started")
+
+// StopSession logs out the session matching the session id and stops it, leaving the other sessions of the Acceptor
+// running. Logons from the counterparty are rejected until the session is started again with StartSession. Dynamic
+// sessions cannot be stopped.
+func (a *Acceptor) StopSession(sessionID SessionID) error {
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+
+	a.sessionsLock.RLock()
+	s, ok := a.configuredSession(sessionID)
+	a.sessionsLock.RUnlock()
+
+	if !ok {
+		return errUnknownSession
+	}
+
+	return a.haltSession(s, false)
+}
+
+// StartSession starts the session matching the session id after it was stopped with StopSession. Starting a running
+// session has no effect. Returns an error if the Acceptor is not started.
+func (a *Acceptor) StartSession(sessionID SessionID) error {
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+
+	a.sessionsLock.Lock()
+	defer a.sessionsLock.Unlock()
+
+	s, ok := a.configuredSession(sessionID)
+	if !ok {
+		return errUnknownSession
+	}
+
+	if !a.running.Load() {
+		return errNotStarted
+	}
+
+	if _, running := a.sessionDone[s.sessionID]; !running {
+		s.stopOnce = sync.Once{}
+		a.runSession(s)
+	}
+
+	return nil
+}
+
+// StopSession logs out the session matching the session id and stops connecting it, leaving the other sessions of
+// the Initiator running, until it is started again with StartSession.
+func (i *Initiator) StopSession(sessionID SessionID) error {
+	i.reloadLock.Lock()
+	defer i.reloadLock.Unlock()
+
+	i.sessionsLock.RLock()
+	s, ok := i.sessions[sessionID]
+	i.sessionsLock.RUnlock()
+
+	if !ok {
+		return errUnknownSession
+	}
+
+	return i.haltSession(s, false)
+}
+
+// StartSession connects the session matching the session id after it was stopped with StopSession. Starting a
+// running session has no effect. Returns an error if the Initiator is not started.
+func (i *Initiator) StartSession(sessionID SessionID) error {
+	i.reloadLock.Lock()
+	defer i.reloadLock.Unlock()
+
+	i.sessionsLock.Lock()
+	defer i.sessionsLock.Unlock()
+
+	s, ok := i.sessions[sessionID]
+	if !ok {
+		return errUnknownSession
+	}
+
+	if !i.running.Load() {
+		return errNotStarted
+	}
+
+	if _, started := i.sessionStop[sessionID]; started {
+		return nil
+	}
+
+	s.stopOnce = sync.Once{}
+	return i.startSession(s, i.sessionSettings[sessionID])
+}
@@ -0,0 +1,320 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestStartAndStopSession(t *testing.T) {
+	host := "pipe://session_control"
+	acceptorA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "A"}
+	acceptorB := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "B"}
+	initiatorA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "A", TargetCompID: "ACCEPTOR"}
+	initiatorB := SessionID{BeginString: BeginStringFIX42, SenderCompID: "B", TargetCompID: "ACCEPTOR"}
+
+	acceptorApp := newReloadApp()
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketAcceptHost: host},
+			reloadSessionSettings("ACCEPTOR", "A", nil), reloadSessionSettings("ACCEPTOR", "B", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	assert.ErrorIs(t, acceptor.StartSession(acceptorA), errNotStarted)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	initiatorApp := newReloadApp()
+	initiator, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5016", config.HeartBtInt: "30", config.ReconnectInterval: "1"},
+			reloadSessionSettings("A", "ACCEPTOR", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	assert.ErrorIs(t, initiator.StartSession(initiatorA), errNotStarted)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	_, err = initiator.AddSession(reloadSessionSettings("B", "ACCEPTOR", nil))
+	require.NoError(t, err)
+
+	logons := map[SessionID]bool{}
+	for range 2 {
+		logons[<-initiatorApp.loggedOn] = true
+	}
+	assert.Equal(t, map[SessionID]bool{initiatorA: true, initiatorB: true}, logons)
+	for range 2 {
+		<-acceptorApp.loggedOn
+	}
+
+	// Logons are rejected while the acceptor session is stopped, the other session is not affected.
+	require.NoError(t, acceptor.StopSession(acceptorA))
+	waitForSession(t, acceptorApp.loggedOut, acceptorA)
+	waitForSession(t, initiatorApp.loggedOut, initiatorA)
+	assertNoSession(t, initiatorApp.loggedOn)
+	assert.NoError(t, acceptor.StopSession(acceptorA))
+	_, registered := lookupSession(acceptorA)
+	assert.True(t, registered, "stopped sessions remain registered")
+
+	require.NoError(t, acceptor.StartSession(acceptorA))
+	waitForSession(t, initiatorApp.loggedOn, initiatorA)
+	waitForSession(t, acceptorApp.loggedOn, acceptorA)
+	assert.NoError(t, acceptor.StartSession(acceptorA))
+
+	require.NoError(t, initiator.StopSession(initiatorB))
+	waitForSession(t, initiatorApp.loggedOut, initiatorB)
+	waitForSession(t, acceptorApp.loggedOut, acceptorB)
+	assertNoSession(t, acceptorApp.loggedOn)
+
+	require.NoError(t, initiator.StartSession(initiatorB))
+	waitForSession(t, initiatorApp.loggedOn, initiatorB)
+	waitForSession(t, acceptorApp.loggedOn, acceptorB)
+	assert.NoError(t, initiator.StartSession(initiatorB))
+
+	unknown := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "C"}
+	assert.ErrorIs(t, acceptor.StopSession(unknown), errUnknownSession)
+	assert.ErrorIs(t, acceptor.StartSession(unknown), errUnknownSession)
+	assert.ErrorIs(t, initiator.StopSession(unknown), errUnknownSession)
+	assert.ErrorIs(t, initiator.StartSession(unknown), errUnknownSession)
+	assertNoSession(t, initiatorApp.loggedOut)
+}
+
+func TestStopWithContext(t *testing.T) {
+	host := "pipe://stop_with_context"
+	acceptorA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "A"}
+	acceptorB := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "B"}
+	initiatorA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "A", TargetCompID: "ACCEPTOR"}
+	initiatorB := SessionID{BeginString: BeginStringFIX42, SenderCompID: "B", TargetCompID: "ACCEPTOR"}
+
+	acceptorApp := newReloadApp()
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketAcceptHost: host},
+			reloadSessionSettings("ACCEPTOR", "A", nil), reloadSessionSettings("ACCEPTOR", "B", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	global := map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5017", config.HeartBtInt: "30"}
+	initiatorApp := newReloadApp()
+	initiatorOne, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, global, reloadSessionSettings("A", "ACCEPTOR", nil)), NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiatorOne.Start())
+	defer initiatorOne.Stop()
+
+	waitForSession(t, initiatorApp.loggedOn, initiatorA)
+	waitForSession(t, acceptorApp.loggedOn, acceptorA)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, initiatorOne.StopWithContext(ctx), "the acceptor confirms the logout")
+	waitForSession(t, initiatorApp.loggedOut, initiatorA)
+	waitForSession(t, acceptorApp.loggedOut, acceptorA)
+	assert.False(t, initiatorOne.Health().Running)
+	assert.NoError(t, initiatorOne.StopWithContext(ctx))
+
+	initiatorTwo, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, global, reloadSessionSettings("B", "ACCEPTOR", nil)), NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiatorTwo.Start())
+	defer initiatorTwo.Stop()
+
+	waitForSession(t, initiatorApp.loggedOn, initiatorB)
+	waitForSession(t, acceptorApp.loggedOn, acceptorB)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = acceptor.StopWithContext(cancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, acceptorB.String())
+	assert.NotContains(t, err.Error(), acceptorA.String(), "logged out sessions stop without error")
+	waitForSession(t, acceptorApp.loggedOut, acceptorB)
+	assert.False(t, acceptor.Health().Running)
+}
+
+// closingStore is a MessageStore failing to save messages once closed, like a file or SQL store.
+type closingStore struct {
+	MessageStore
+	closed bool
+}
+
+func (s *closingStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	if s.closed {
+		return errors.New("store closed")
+	}
+	return s.MessageStore.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, msg)
+}
+
+func (s *closingStore) Close() error {
+	s.closed = true
+	return s.MessageStore.Close()
+}
+
+type closingStoreFactory struct{ created chan *closingStore }
+
+func (f closingStoreFactory) Create(sessionID SessionID) (MessageStore, error) {
+	store, err := NewMemoryStoreFactory().Create(sessionID)
+	s := &closingStore{MessageStore: store}
+	f.created <- s
+	return s, err
+}
+
+func TestStartAfterStopWithContext(t *testing.T) {
+	host := "pipe://start_after_stop"
+	acceptorApp := newReloadApp()
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketAcceptHost: host}, reloadSessionSettings("ACCEPTOR", "A", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	initiatorA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "A", TargetCompID: "ACCEPTOR"}
+	storeFactory := closingStoreFactory{created: make(chan *closingStore, 10)}
+	initiatorApp := newReloadApp()
+	initiator, err := NewInitiator(initiatorApp, storeFactory, reloadSettings(t,
+		map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5024", config.HeartBtInt: "30", config.ReconnectInterval: "1"},
+		reloadSessionSettings("A", "ACCEPTOR", nil)), NewNullLogFactory())
+	require.NoError(t, err)
+	first := <-storeFactory.created
+
+	for range 2 {
+		require.NoError(t, initiator.Start())
+		waitForSession(t, initiatorApp.loggedOn, initiatorA)
+		require.NoError(t, initiator.StopWithContext(context.Background()))
+		waitForSession(t, initiatorApp.loggedOut, initiatorA)
+	}
+
+	// The store closed by the first stop is replaced as the initiator starts again.
+	assert.True(t, first.closed)
+	second := <-storeFactory.created
+	assert.True(t, second.closed)
+}
+
+// callbackApp calls call with the session of each application message it receives.
+type callbackApp struct {
+	reloadApp
+	call func(sessionID SessionID) error
+	errs chan error
+}
+
+func (a callbackApp) FromApp(_ *Message, sessionID SessionID) MessageRejectError {
+	a.errs <- a.call(sessionID)
+	return nil
+}
+
+// testCallback sends an application message to an acceptor whose FromApp calls call, returning the error of call.
+func testCallback(t *testing.T, host, port string, global map[string]string, call func(sessionID SessionID) error) {
+	acceptorA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "A"}
+	initiatorA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "A", TargetCompID: "ACCEPTOR"}
+
+	acceptorApp := callbackApp{reloadApp: newReloadApp(), call: call, errs: make(chan error, 1)}
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, mergeSettings(global, map[string]string{config.SocketAcceptHost: host}), reloadSessionSettings("ACCEPTOR", "A", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	initiatorApp := newReloadApp()
+	initiator, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(), reloadSettings(t,
+		mergeSettings(global, map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: port, config.HeartBtInt: "30"}),
+		reloadSessionSettings("A", "ACCEPTOR", nil)), NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+	waitForSession(t, acceptorApp.loggedOn, acceptorA)
+	waitForSession(t, initiatorApp.loggedOn, initiatorA)
+
+	msg := NewMessage()
+	msg.Header.SetString(tagMsgType, "B")
+	msg.Body.SetString(Tag(148), "headline")
+	require.NoError(t, SendToTarget(msg, initiatorA))
+
+	// The call runs on the run loop calling FromApp, rather than waiting for it.
+	select {
+	case err := <-acceptorApp.errs:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for callback")
+	}
+	waitForSession(t, acceptorApp.loggedOut, acceptorA)
+}
+
+// mergeSettings returns the settings of both a and b.
+func mergeSettings(a, b map[string]string) map[string]string {
+	merged := make(map[string]string, len(a)+len(b))
+	for setting, value := range a {
+		merged[setting] = value
+	}
+	for setting, value := range b {
+		merged[setting] = value
+	}
+	return merged
+}
+
+func TestResetSessionFromCallback(t *testing.T) {
+	var tests = []struct {
+		host   string
+		global map[string]string
+	}{
+		{host: "pipe://reset_callback"},
+		{host: "pipe://reset_callback_shared", global: map[string]string{config.EventLoopMode: "shared"}},
+	}
+
+	for _, test := range tests {
+		testCallback(t, test.host, "5026", test.global, ResetSession)
+	}
+}
+
+func TestLogoutAndDrainFromCallback(t *testing.T) {
+	var tests = []struct {
+		host   string
+		global map[string]string
+	}{
+		{host: "pipe://drain_callback"},
+		{host: "pipe://drain_callback_shared", global: map[string]string{config.EventLoopMode: "shared"}},
+	}
+
+	for _, test := range tests {
+		testCallback(t, test.host, "5027", test.global, func(sessionID SessionID) error {
+			return LogoutAndDrain(sessionID, time.Second)
+		})
+	}
+}
+
+func TestAdminNotStarted(t *testing.T) {
+	acceptorA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "A"}
+	_, err := NewAcceptor(newReloadApp(), NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketAcceptHost: "pipe://admin_not_started"}, reloadSessionSettings("ACCEPTOR", "A", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, UnregisterSession(acceptorA)) }()
+
+	assert.NoError(t, ResetSession(acceptorA))
+	assert.NoError(t, LogoutAndDrain(acceptorA, 100*time.Millisecond))
+
+	session, ok := lookupSession(acceptorA)
+	require.True(t, ok)
+	assert.False(t, session.draining.Load(), "application messages are accepted again")
+}
@@ -0,0 +1,75 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "github.com/quickfixgo/quickfix/datadictionary"
+
+// SessionDataDictionaries are the data dictionaries a session validates messages with. They must not be modified.
+type SessionDataDictionaries struct {
+	// Transport is the TransportDataDictionary of a FIXT session.
+	Transport *datadictionary.DataDictionary
+
+	// App is the DataDictionary of a FIX session, or the AppDataDictionary of the DefaultApplVerID of a FIXT session.
+	App *datadictionary.DataDictionary
+
+	// AppByApplVerID are the AppDataDictionaries of a FIXT session by ApplVerID, e.g. 9 for FIX.5.0SP2.
+	AppByApplVerID map[string]*datadictionary.DataDictionary
+}
+
+// AppDataDictionary returns the application data dictionary of the ApplVerID of a FIXT session, or the data
+// dictionary of a FIX session if applVerID is empty.
+func (d SessionDataDictionaries) AppDataDictionary(applVerID string) (*datadictionary.DataDictionary, bool) {
+	if applVerID == "" {
+		return d.App, d.App != nil
+	}
+
+	dict, ok := d.AppByApplVerID[normalizeApplVerID(applVerID)]
+	return dict, ok
+}
+
+// GetDataDictionaries returns the data dictionaries of the session matching the session id, for introspection of the
+// messages, fields and enums it validates. They are empty if the session is configured without data dictionaries.
+func GetDataDictionaries(sessionID SessionID) (SessionDataDictionaries, error) {
+	session, ok := lookupSession(sessionID)
+	if !ok {
+		return SessionDataDictionaries{}, errUnknownSession
+	}
+
+	var dicts SessionDataDictionaries
+	rep := make(chan error, 1)
+	err := session.doAdmin(dataDictionariesReq{dicts: &dicts, rep: rep}, rep)
+	return dicts, err
+}
+
+type dataDictionariesReq struct {
+	dicts *SessionDataDictionaries
+	rep   chan<- error
+}
+
+func (s *session) onDataDictionaries(req dataDictionariesReq) error {
+	req.dicts.Transport = s.transportDataDictionary
+	req.dicts.App = s.appDataDictionary
+
+	// Reloads replace the entries of the map, so the caller gets a copy.
+	if s.appDataDictionaries != nil {
+		req.dicts.AppByApplVerID = make(map[string]*datadictionary.DataDictionary, len(s.appDataDictionaries))
+		for applVerID, dict := range s.appDataDictionaries {
+			req.dicts.AppByApplVerID[applVerID] = dict
+		}
+	}
+
+	return nil
+}
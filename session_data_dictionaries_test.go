@@ -0,0 +1,73 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestGetDataDictionaries(t *testing.T) {
+	sessionID := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ISLD", TargetCompID: "TW"}
+	_, err := GetDataDictionaries(sessionID)
+	assert.Equal(t, errUnknownSession, err)
+
+	s := newReloadTestSession(t, sessionID, map[string]string{config.DataDictionary: "spec/FIX42.xml"})
+
+	dicts, err := GetDataDictionaries(sessionID)
+	require.Nil(t, err)
+	assert.Same(t, s.appDataDictionary, dicts.App)
+	assert.Nil(t, dicts.Transport)
+	assert.Nil(t, dicts.AppByApplVerID)
+
+	dict, ok := dicts.AppDataDictionary("")
+	require.True(t, ok)
+	assert.Equal(t, "FIX.4.2", dict.Version())
+}
+
+func TestGetDataDictionariesFIXT(t *testing.T) {
+	sessionID := SessionID{BeginString: BeginStringFIXT11, SenderCompID: "ISLD", TargetCompID: "TW"}
+	s := newReloadTestSession(t, sessionID, map[string]string{
+		config.DefaultApplVerID:               "FIX.5.0SP2",
+		config.TransportDataDictionary:        "spec/FIXT11.xml",
+		config.AppDataDictionary:              "spec/FIX50SP2.xml",
+		config.AppDataDictionary + ".FIX.4.4": "spec/FIX44.xml",
+	})
+
+	dicts, err := GetDataDictionaries(sessionID)
+	require.Nil(t, err)
+	assert.Same(t, s.transportDataDictionary, dicts.Transport)
+	assert.Same(t, s.appDataDictionary, dicts.App)
+
+	dict, ok := dicts.AppDataDictionary("FIX.4.4")
+	require.True(t, ok)
+	assert.Equal(t, "FIX.4.4", dict.Version())
+
+	dict, ok = dicts.AppDataDictionary("9")
+	require.True(t, ok)
+	assert.Equal(t, "FIX.5.0SP2", dict.Version())
+
+	_, ok = dicts.AppDataDictionary("FIX.4.2")
+	assert.False(t, ok)
+
+	// Reloads do not change the dictionaries already returned.
+	require.Nil(t, ReloadDictionaryFile(sessionID, "spec/FIX44.xml"))
+	assert.NotSame(t, s.appDataDictionaries["6"], dicts.AppByApplVerID["6"])
+}
@@ -17,6 +17,7 @@ package quickfix
 
 import (
 	"net"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -88,6 +89,7 @@ func (f sessionFactory) newSession(
 	s = &session{
 		sessionID: sessionID,
 		stopOnce:  sync.Once{},
+		clock:     SystemClock{},
 	}
 
 	var validatorSettings = defaultValidatorSettings
@@ -115,6 +117,76 @@ func (f sessionFactory) newSession(
 		}
 	}
 
+	s.validationPolicy = NewValidationPolicy()
+	validatorSettings.Policy = s.validationPolicy
+	s.validatorSettings = validatorSettings
+	if settings.HasSetting(config.ValidateRequiredFields) {
+		var validate bool
+		if validate, err = settings.BoolSetting(config.ValidateRequiredFields); err != nil {
+			return
+		}
+		if !validate {
+			s.validationPolicy.Disable(CheckRequiredFields)
+		}
+	}
+
+	if settings.HasSetting(config.ValidateFieldValues) {
+		var validate bool
+		if validate, err = settings.BoolSetting(config.ValidateFieldValues); err != nil {
+			return
+		}
+		if !validate {
+			s.validationPolicy.Disable(CheckFieldValues)
+		}
+	}
+
+	if settings.HasSetting(config.ValidationMsgTypeOverrides) {
+		var overrides string
+		if overrides, err = settings.Setting(config.ValidationMsgTypeOverrides); err != nil {
+			return
+		}
+		if s.validationPolicy.parseMsgTypeOverrides(overrides) != nil {
+			err = IncorrectFormatForSetting{Setting: config.ValidationMsgTypeOverrides, Value: []byte(overrides)}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.ValidationTagOverrides) {
+		var overrides string
+		if overrides, err = settings.Setting(config.ValidationTagOverrides); err != nil {
+			return
+		}
+		if s.validationPolicy.parseTagOverrides(overrides) != nil {
+			err = IncorrectFormatForSetting{Setting: config.ValidationTagOverrides, Value: []byte(overrides)}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.EnumValidation) {
+		var enumValidationStr string
+		if enumValidationStr, err = settings.Setting(config.EnumValidation); err != nil {
+			return
+		}
+		enumValidation, ok := enumValidationNames[enumValidationStr]
+		if !ok {
+			err = IncorrectFormatForSetting{Setting: config.EnumValidation, Value: []byte(enumValidationStr)}
+			return
+		}
+		s.validationPolicy.SetEnumValidation(enumValidation)
+	}
+
+	if settings.HasSetting(config.AdditionalEnumValues) {
+		var values string
+		if values, err = settings.Setting(config.AdditionalEnumValues); err != nil {
+			return
+		}
+		if s.validationPolicy.parseEnumValues(values) != nil {
+			err = IncorrectFormatForSetting{Setting: config.AdditionalEnumValues, Value: []byte(values)}
+			return
+		}
+	}
+	s.validationPolicy.onUnknownEnum = s.onUnknownEnum
+
 	if sessionID.IsFIXT() {
 		if s.DefaultApplVerID, err = settings.Setting(config.DefaultApplVerID); err != nil {
 			return
@@ -135,7 +207,7 @@ func (f sessionFactory) newSession(
 				return
 			}
 
-			if s.transportDataDictionary, err = datadictionary.Parse(transportDataDictionaryPath); err != nil {
+			if s.transportDataDictionary, err = parseDataDictionary(settings, transportDataDictionaryPath); err != nil {
 				err = errors.Wrapf(
 					err, "problem parsing XML datadictionary path '%v' for setting '%v",
 					settings.settings[config.TransportDataDictionary], config.TransportDataDictionary,
@@ -143,7 +215,7 @@ func (f sessionFactory) newSession(
 				return
 			}
 
-			if s.appDataDictionary, err = datadictionary.Parse(appDataDictionaryPath); err != nil {
+			if s.appDataDictionary, err = parseDataDictionary(settings, appDataDictionaryPath); err != nil {
 				err = errors.Wrapf(
 					err, "problem parsing XML datadictionary path '%v' for setting '%v",
 					settings.settings[config.AppDataDictionary], config.AppDataDictionary,
@@ -152,6 +224,10 @@ func (f sessionFactory) newSession(
 			}
 
 			s.Validator = NewValidator(validatorSettings, s.appDataDictionary, s.transportDataDictionary)
+
+			if err = f.buildAppDataDictionaries(s, settings, validatorSettings); err != nil {
+				return
+			}
 		}
 	} else if settings.HasSetting(config.DataDictionary) {
 		var dataDictionaryPath string
@@ -159,7 +235,7 @@ func (f sessionFactory) newSession(
 			return
 		}
 
-		if s.appDataDictionary, err = datadictionary.Parse(dataDictionaryPath); err != nil {
+		if s.appDataDictionary, err = parseDataDictionary(settings, dataDictionaryPath); err != nil {
 			err = errors.Wrapf(
 				err, "problem parsing XML datadictionary path '%v' for setting '%v",
 				settings.settings[config.DataDictionary], config.DataDictionary,
@@ -200,6 +276,46 @@ func (f sessionFactory) newSession(
 		}
 	}
 
+	if settings.HasSetting(config.SendLogoutBeforeDisconnectFromLogon) {
+		if s.SendLogoutBeforeDisconnect, err = settings.BoolSetting(config.SendLogoutBeforeDisconnectFromLogon); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.ThrottleMessagesPerSecond) {
+		if s.throttle, err = buildThrottle(settings, func() time.Time { return s.clock.Now() }); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.MaxQueuedMessages) {
+		if s.queueLimit, err = buildSendQueueLimit(settings, &s.sendMutex); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.DuplicateWindow) {
+		if s.duplicates, err = buildDuplicateWindow(settings); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.PreserveMessageFieldsOrder) {
+		if s.PreserveMessageFieldsOrder, err = settings.BoolSetting(config.PreserveMessageFieldsOrder); err != nil {
+			return
+		}
+	}
+
+	zeroAlloc := true
+	if settings.HasSetting(config.ZeroAllocParser) {
+		if zeroAlloc, err = settings.BoolSetting(config.ZeroAllocParser); err != nil {
+			return
+		}
+	}
+	if zeroAlloc {
+		s.zeroAllocParser = newZeroAllocParser()
+	}
+
 	if settings.HasSetting(config.EnableNextExpectedMsgSeqNum) {
 		if s.EnableNextExpectedMsgSeqNum, err = settings.BoolSetting(config.EnableNextExpectedMsgSeqNum); err != nil {
 			return
@@ -231,6 +347,50 @@ func (f sessionFactory) newSession(
 		s.MaxLatency = time.Duration(maxLatency) * time.Second
 	}
 
+	s.TestRequestDelayMultiplier = 0.2
+	if settings.HasSetting(config.TestRequestDelayMultiplier) {
+		if s.TestRequestDelayMultiplier, err = settings.FloatSetting(config.TestRequestDelayMultiplier); err != nil {
+			return
+		}
+
+		if s.TestRequestDelayMultiplier <= 0 {
+			err = errors.New("TestRequestDelayMultiplier must be greater than zero")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.MaxMessageSize) {
+		if s.MaxMessageSize, err = positiveIntSetting(settings, config.MaxMessageSize); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.MaxInboundBytesPerSecond) {
+		if s.MaxInboundBytesPerSecond, err = positiveIntSetting(settings, config.MaxInboundBytesPerSecond); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.OutboundBatchMaxBytes) {
+		if s.OutboundBatchMaxBytes, err = settings.IntSetting(config.OutboundBatchMaxBytes); err != nil {
+			return
+		}
+		if s.OutboundBatchMaxBytes < 0 {
+			err = IncorrectFormatForSetting{Setting: config.OutboundBatchMaxBytes, Value: []byte(strconv.Itoa(s.OutboundBatchMaxBytes))}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.OutboundBatchFlushLatency) {
+		if s.OutboundBatchFlushLatency, err = settings.DurationSetting(config.OutboundBatchFlushLatency); err != nil {
+			return
+		}
+		if s.OutboundBatchFlushLatency < 0 {
+			err = IncorrectFormatForSetting{Setting: config.OutboundBatchFlushLatency, Value: []byte(s.OutboundBatchFlushLatency.String())}
+			return
+		}
+	}
+
 	if settings.HasSetting(config.ResendRequestChunkSize) {
 		if s.ResendRequestChunkSize, err = settings.IntSetting(config.ResendRequestChunkSize); err != nil {
 			return
@@ -264,21 +424,9 @@ func (f sessionFactory) newSession(
 			return
 		}
 
-		loc := time.UTC
-		if settings.HasSetting(config.TimeZone) {
-			var locStr string
-			if locStr, err = settings.Setting(config.TimeZone); err != nil {
-				return
-			}
-
-			loc, err = time.LoadLocation(locStr)
-			if err != nil {
-				err = errors.Wrapf(
-					err, "problem parsing time zone '%v' for setting '%v",
-					settings.settings[config.TimeZone], config.TimeZone,
-				)
-				return
-			}
+		var loc *time.Location
+		if loc, err = parseTimeZone(settings); err != nil {
+			return
 		}
 
 		if !settings.HasSetting(config.StartDay) && !settings.HasSetting(config.EndDay) {
@@ -346,6 +494,15 @@ func (f sessionFactory) newSession(
 			}
 			s.SessionTime = sessionTime
 		}
+
+		var holidays []internal.Date
+		if holidays, err = parseHolidays(settings); err != nil {
+			return
+		}
+		s.SessionTime.AddHolidays(holidays...)
+	} else if settings.HasSetting(config.Holidays) || settings.HasSetting(config.HolidayFile) {
+		err = errors.New("Holidays and HolidayFile require StartTime and EndTime")
+		return
 	}
 
 	if settings.HasSetting(config.ResetSeqTime) {
@@ -358,10 +515,14 @@ func (f sessionFactory) newSession(
 		if seqTime, err = internal.ParseTimeOfDay(seqTimeStr); err != nil {
 			err = errors.Wrapf(
 				err, "problem parsing time of day '%v' for setting '%v",
-				settings.settings[config.StartTime], config.StartTime,
+				settings.settings[config.ResetSeqTime], config.ResetSeqTime,
 			)
 			return
 		}
+
+		if s.ResetSeqTimeLocation, err = parseTimeZone(settings); err != nil {
+			return
+		}
 		s.EnableResetSeqTime = true
 		s.ResetSeqTime = seqTime
 	} else {
@@ -399,6 +560,35 @@ func (f sessionFactory) newSession(
 		s.DisableMessagePersist = !persistMessages
 	}
 
+	if settings.HasSetting(config.TransientMessageTypes) {
+		var msgTypesStr string
+		if msgTypesStr, err = settings.Setting(config.TransientMessageTypes); err != nil {
+			return
+		}
+
+		s.TransientMsgTypes = make(map[string]bool)
+		for _, msgType := range strings.Split(msgTypesStr, ",") {
+			if msgType = strings.TrimSpace(msgType); msgType != "" {
+				s.TransientMsgTypes[msgType] = true
+			}
+		}
+	}
+
+	if settings.HasSetting(config.ApplicationCallbackTimeout) {
+		if s.ApplicationCallbackTimeout, err = settings.DurationSetting(config.ApplicationCallbackTimeout); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.HighResolutionLatency) {
+		var highResolution bool
+		if highResolution, err = settings.BoolSetting(config.HighResolutionLatency); err != nil {
+			return
+		}
+
+		s.stats.setHighResolutionLatency(highResolution)
+	}
+
 	if f.BuildInitiators {
 		if err = f.buildInitiatorSettings(s, settings); err != nil {
 			return
@@ -410,11 +600,22 @@ func (f sessionFactory) newSession(
 	if s.log, err = logFactory.CreateSessionLog(s.sessionID); err != nil {
 		return
 	}
+	var redactor redactor
+	if redactor, err = buildRedactor(settings); err != nil {
+		return
+	}
+	s.log = newSessionLog(s.log, s.sessionID, redactor)
 
 	if s.store, err = storeFactory.Create(s.sessionID); err != nil {
 		return
 	}
 
+	if settings.HasSetting(config.PersistOutboundQueue) {
+		if s.outboundQueue, err = buildOutboundQueue(settings, s.store); err != nil {
+			return
+		}
+	}
+
 	s.sessionEvent = make(chan internal.Event)
 	s.messageEvent = make(chan bool, 1)
 	s.admin = make(chan interface{})
@@ -422,6 +623,236 @@ func (f sessionFactory) newSession(
 	return
 }
 
+// buildThrottle returns the throttle configured by the ThrottleMessagesPerSecond, ThrottleBurst and ThrottleMode settings,
+// measuring time with now.
+func buildThrottle(settings *SessionSettings, now func() time.Time) (*throttle, error) {
+	rate, err := settings.IntSetting(config.ThrottleMessagesPerSecond)
+	if err != nil {
+		return nil, err
+	}
+	if rate == 0 {
+		return nil, nil
+	}
+	if rate < 0 {
+		return nil, IncorrectFormatForSetting{Setting: config.ThrottleMessagesPerSecond, Value: []byte(strconv.Itoa(rate))}
+	}
+
+	burst := rate
+	if settings.HasSetting(config.ThrottleBurst) {
+		if burst, err = settings.IntSetting(config.ThrottleBurst); err != nil {
+			return nil, err
+		}
+		if burst <= 0 {
+			return nil, IncorrectFormatForSetting{Setting: config.ThrottleBurst, Value: []byte(strconv.Itoa(burst))}
+		}
+	}
+
+	t := &throttle{mode: ThrottleBlock, bucket: internal.NewTokenBucket(float64(rate), burst, now)}
+	if settings.HasSetting(config.ThrottleMode) {
+		modeStr, err := settings.Setting(config.ThrottleMode)
+		if err != nil {
+			return nil, err
+		}
+
+		switch modeStr {
+		case "Block":
+			t.mode = ThrottleBlock
+		case "Queue":
+			t.mode = ThrottleQueue
+		default:
+			return nil, IncorrectFormatForSetting{Setting: config.ThrottleMode, Value: []byte(modeStr)}
+		}
+	}
+
+	return t, nil
+}
+
+// buildSendQueueLimit returns the send queue limit configured by the MaxQueuedMessages and QueueFullPolicy settings.
+func buildSendQueueLimit(settings *SessionSettings, sendMutex *sync.Mutex) (*sendQueueLimit, error) {
+	capacity, err := settings.IntSetting(config.MaxQueuedMessages)
+	if err != nil {
+		return nil, err
+	}
+	if capacity == 0 {
+		return nil, nil
+	}
+	if capacity < 0 {
+		return nil, IncorrectFormatForSetting{Setting: config.MaxQueuedMessages, Value: []byte(strconv.Itoa(capacity))}
+	}
+
+	l := &sendQueueLimit{capacity: capacity, policy: QueueFullBlock, timeout: defaultQueueFullTimeout, space: sync.NewCond(sendMutex)}
+	if settings.HasSetting(config.QueueFullPolicy) {
+		policyStr, err := settings.Setting(config.QueueFullPolicy)
+		if err != nil {
+			return nil, err
+		}
+
+		switch policyStr {
+		case "Block":
+			l.policy = QueueFullBlock
+		case "Error":
+			l.policy = QueueFullError
+		case "DropOldestQuote":
+			l.policy = QueueFullDropOldestQuote
+		default:
+			return nil, IncorrectFormatForSetting{Setting: config.QueueFullPolicy, Value: []byte(policyStr)}
+		}
+	}
+	if settings.HasSetting(config.QueueFullTimeout) {
+		if l.timeout, err = settings.DurationSetting(config.QueueFullTimeout); err != nil {
+			return nil, err
+		} else if l.timeout <= 0 {
+			return nil, IncorrectFormatForSetting{Setting: config.QueueFullTimeout, Value: []byte(l.timeout.String())}
+		}
+	}
+
+	return l, nil
+}
+
+// buildDuplicateWindow returns the duplicate window configured by the DuplicateWindow and DuplicatePolicy settings.
+func buildDuplicateWindow(settings *SessionSettings) (*duplicateWindow, error) {
+	size, err := settings.IntSetting(config.DuplicateWindow)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	if size < 0 {
+		return nil, IncorrectFormatForSetting{Setting: config.DuplicateWindow, Value: []byte(strconv.Itoa(size))}
+	}
+
+	policy := DuplicateFlag
+	if settings.HasSetting(config.DuplicatePolicy) {
+		policyStr, err := settings.Setting(config.DuplicatePolicy)
+		if err != nil {
+			return nil, err
+		}
+
+		switch policyStr {
+		case "Flag":
+			policy = DuplicateFlag
+		case "Suppress":
+			policy = DuplicateSuppress
+		default:
+			return nil, IncorrectFormatForSetting{Setting: config.DuplicatePolicy, Value: []byte(policyStr)}
+		}
+	}
+
+	return newDuplicateWindow(size, policy), nil
+}
+
+// parseTimeZone returns the location configured by the TimeZone setting, UTC by default.
+func parseTimeZone(settings *SessionSettings) (*time.Location, error) {
+	if !settings.HasSetting(config.TimeZone) {
+		return time.UTC, nil
+	}
+
+	locStr, err := settings.Setting(config.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(locStr)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "problem parsing time zone '%v' for setting '%v",
+			settings.settings[config.TimeZone], config.TimeZone,
+		)
+	}
+
+	return loc, nil
+}
+
+// parseHolidays returns the dates configured by the Holidays and HolidayFile settings.
+func parseHolidays(settings *SessionSettings) (holidays []internal.Date, err error) {
+	var lines []string
+	if settings.HasSetting(config.Holidays) {
+		var holidaysStr string
+		if holidaysStr, err = settings.Setting(config.Holidays); err != nil {
+			return
+		}
+
+		for _, dateStr := range strings.Split(holidaysStr, ",") {
+			var date internal.Date
+			if date, err = internal.ParseDate(strings.TrimSpace(dateStr)); err != nil {
+				return nil, IncorrectFormatForSetting{Setting: config.Holidays, Value: []byte(holidaysStr), Err: err}
+			}
+			holidays = append(holidays, date)
+		}
+	}
+
+	if settings.HasSetting(config.HolidayFile) {
+		var fileName string
+		if fileName, err = settings.Setting(config.HolidayFile); err != nil {
+			return
+		}
+
+		var contents []byte
+		if contents, err = os.ReadFile(fileName); err != nil {
+			return nil, errors.Wrapf(err, "problem reading holidays for setting '%v'", config.HolidayFile)
+		}
+
+		lines = strings.Split(string(contents), "\n")
+	}
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var date internal.Date
+		if date, err = internal.ParseDate(line); err != nil {
+			return nil, errors.Wrapf(err, "problem parsing line %v of holiday file for setting '%v'", i+1, config.HolidayFile)
+		}
+		holidays = append(holidays, date)
+	}
+
+	return
+}
+
+// parseDataDictionary parses the data dictionary at path, in the data dictionary file system of settings if it is set.
+func parseDataDictionary(settings *SessionSettings, path string) (*datadictionary.DataDictionary, error) {
+	if settings.dataDictionaryFS != nil {
+		return datadictionary.ParseFS(settings.dataDictionaryFS, path)
+	}
+
+	return datadictionary.Parse(path)
+}
+
+// buildAppDataDictionaries loads the application data dictionaries of the AppDataDictionary.<BeginString> settings
+// of a FIXT session, with a Validator for each.
+func (f sessionFactory) buildAppDataDictionaries(session *session, settings *SessionSettings, validatorSettings ValidatorSettings) error {
+	prefix := config.AppDataDictionary + "."
+	for setting := range settings.settings {
+		if !strings.HasPrefix(setting, prefix) {
+			continue
+		}
+
+		path, err := settings.Setting(setting)
+		if err != nil {
+			return err
+		}
+
+		dict, err := parseDataDictionary(settings, path)
+		if err != nil {
+			return errors.Wrapf(err, "problem parsing XML datadictionary path '%v' for setting '%v", path, setting)
+		}
+
+		if session.appDataDictionaries == nil {
+			session.appDataDictionaries = map[string]*datadictionary.DataDictionary{session.DefaultApplVerID: session.appDataDictionary}
+			session.appValidators = map[string]Validator{session.DefaultApplVerID: session.Validator}
+		}
+
+		applVerID := normalizeApplVerID(strings.TrimPrefix(setting, prefix))
+		session.appDataDictionaries[applVerID] = dict
+		session.appValidators[applVerID] = NewValidator(validatorSettings, dict, session.transportDataDictionary)
+	}
+
+	return nil
+}
+
 func (f sessionFactory) buildAcceptorSettings(session *session, settings *SessionSettings) error {
 	if err := f.buildHeartBtIntSettings(session, settings, false); err != nil {
 		return err
@@ -455,6 +886,10 @@ func (f sessionFactory) buildInitiatorSettings(session *session, settings *Sessi
 		}
 	}
 
+	if err := f.buildReconnectBackoffSettings(session, settings); err != nil {
+		return err
+	}
+
 	session.LogoutTimeout = 2 * time.Second
 	if settings.HasSetting(config.LogoutTimeout) {
 		timeout, err := settings.DurationSetting(config.LogoutTimeout)
@@ -496,14 +931,92 @@ func (f sessionFactory) buildInitiatorSettings(session *session, settings *Sessi
 	return f.configureSocketConnectAddress(session, settings)
 }
 
+func (f sessionFactory) buildReconnectBackoffSettings(session *session, settings *SessionSettings) (err error) {
+	session.ReconnectBackoffMultiplier = 1
+	if settings.HasSetting(config.ReconnectBackoffMultiplier) {
+		if session.ReconnectBackoffMultiplier, err = settings.FloatSetting(config.ReconnectBackoffMultiplier); err != nil {
+			return
+		}
+
+		if session.ReconnectBackoffMultiplier < 1 {
+			return errors.New("ReconnectBackoffMultiplier must be at least 1")
+		}
+	}
+
+	if settings.HasSetting(config.MaxReconnectInterval) {
+		interval, err := settings.DurationSetting(config.MaxReconnectInterval)
+		if err != nil {
+			intervalInt, err := settings.IntSetting(config.MaxReconnectInterval)
+			if err != nil {
+				return err
+			}
+
+			session.MaxReconnectInterval = time.Duration(intervalInt) * time.Second
+		} else {
+			session.MaxReconnectInterval = interval
+		}
+
+		if session.MaxReconnectInterval < session.ReconnectInterval {
+			return errors.New("MaxReconnectInterval must not be less than ReconnectInterval")
+		}
+	}
+
+	if settings.HasSetting(config.ReconnectJitter) {
+		if session.ReconnectJitter, err = settings.FloatSetting(config.ReconnectJitter); err != nil {
+			return
+		}
+
+		if session.ReconnectJitter < 0 || session.ReconnectJitter >= 1 {
+			return errors.New("ReconnectJitter must be at least 0 and less than 1")
+		}
+	}
+
+	if settings.HasSetting(config.MaxReconnectAttempts) {
+		if session.MaxReconnectAttempts, err = settings.IntSetting(config.MaxReconnectAttempts); err != nil {
+			return
+		}
+
+		if session.MaxReconnectAttempts < 0 {
+			return errors.New("MaxReconnectAttempts must not be negative")
+		}
+	}
+
+	return
+}
+
 func (f sessionFactory) configureSocketConnectAddress(session *session, settings *SessionSettings) (err error) {
 	session.SocketConnectAddress = []string{}
+	session.SocketConnectTimeout = []time.Duration{}
+
+	session.SocketConnectSticky = false
+	if settings.HasSetting(config.SocketConnectRotation) {
+		var rotation string
+		if rotation, err = settings.Setting(config.SocketConnectRotation); err != nil {
+			return
+		}
+
+		switch rotation {
+		case "RoundRobin":
+		case "Sticky":
+			session.SocketConnectSticky = true
+		default:
+			return IncorrectFormatForSetting{Setting: config.SocketConnectRotation, Value: []byte(rotation)}
+		}
+	}
+
+	var defaultTimeout time.Duration
+	if settings.HasSetting(config.SocketConnectTimeout) {
+		if defaultTimeout, err = socketConnectTimeout(settings, config.SocketConnectTimeout); err != nil {
+			return
+		}
+	}
 
 	var socketConnectHost, socketConnectPort string
 	for i := 0; ; {
 
 		hostConfig := config.SocketConnectHost
 		portConfig := config.SocketConnectPort
+		timeout := defaultTimeout
 
 		if i > 0 {
 			hostConfig = hostConfig + strconv.Itoa(i)
@@ -518,15 +1031,45 @@ func (f sessionFactory) configureSocketConnectAddress(session *session, settings
 			return
 		}
 
-		if socketConnectPort, err = settings.Setting(portConfig); err != nil {
-			return
+		address := socketConnectHost
+		if !isLocalTransport(socketConnectHost) {
+			if socketConnectPort, err = settings.Setting(portConfig); err != nil {
+				return
+			}
+			address = net.JoinHostPort(socketConnectHost, socketConnectPort)
 		}
 
-		session.SocketConnectAddress = append(session.SocketConnectAddress, net.JoinHostPort(socketConnectHost, socketConnectPort))
+		if timeoutConfig := config.SocketConnectTimeout + strconv.Itoa(i); i > 0 && settings.HasSetting(timeoutConfig) {
+			if timeout, err = socketConnectTimeout(settings, timeoutConfig); err != nil {
+				return
+			}
+		}
+
+		session.SocketConnectAddress = append(session.SocketConnectAddress, address)
+		session.SocketConnectTimeout = append(session.SocketConnectTimeout, timeout)
 		i++
 	}
 }
 
+// socketConnectTimeout parses a SocketConnectTimeout setting as a duration, or else as a number of seconds.
+func socketConnectTimeout(settings *SessionSettings, setting string) (time.Duration, error) {
+	timeout, err := settings.DurationSetting(setting)
+	if err != nil {
+		timeoutInt, err := settings.IntSetting(setting)
+		if err != nil {
+			return 0, err
+		}
+
+		timeout = time.Duration(timeoutInt) * time.Second
+	}
+
+	if timeout < 0 {
+		return 0, IncorrectFormatForSetting{Setting: setting, Value: []byte(timeout.String())}
+	}
+
+	return timeout, nil
+}
+
 func (f sessionFactory) buildHeartBtIntSettings(session *session, settings *SessionSettings, mustProvide bool) (err error) {
 	if settings.HasSetting(config.HeartBtIntOverride) {
 		if session.HeartBtIntOverride, err = settings.BoolSetting(config.HeartBtIntOverride); err != nil {
@@ -546,3 +1089,33 @@ func (f sessionFactory) buildHeartBtIntSettings(session *session, settings *Sess
 	}
 	return
 }
+
+// closeStore closes the MessageStore of s as its engine stops.
+func (s *session) closeStore() error {
+	s.storeClosed = true
+	return s.store.Close()
+}
+
+// reopenStore creates the MessageStore of s again with storeFactory if it was closed by its engine stopping, as the
+// engine is started again.
+func (s *session) reopenStore(storeFactory MessageStoreFactory) (err error) {
+	if !s.storeClosed {
+		return nil
+	}
+
+	if s.store, err = storeFactory.Create(s.sessionID); err != nil {
+		return err
+	}
+	s.storeClosed = false
+
+	if s.outboundQueue != nil {
+		queueStore, ok := s.store.(OutboundQueueStore)
+		if !ok {
+			return errors.Errorf("%v requires a MessageStore implementing OutboundQueueStore", config.PersistOutboundQueue)
+		}
+		s.outboundQueue.store = queueStore
+		s.outboundQueue.pending = true
+	}
+
+	return nil
+}
@@ -16,6 +16,8 @@
 package quickfix
 
 import (
+	"os"
+	"path"
 	"testing"
 	"time"
 
@@ -150,6 +152,71 @@ func (s *SessionFactorySuite) TestResendRequestChunkSize() {
 	s.NotNil(err)
 }
 
+func (s *SessionFactorySuite) TestInboundLimits() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Zero(session.MaxMessageSize)
+	s.Zero(session.MaxInboundBytesPerSecond)
+
+	s.SessionSettings.Set(config.MaxMessageSize, "65536")
+	s.SessionSettings.Set(config.MaxInboundBytesPerSecond, "1048576")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(65536, session.MaxMessageSize)
+	s.Equal(1048576, session.MaxInboundBytesPerSecond)
+
+	for _, setting := range []string{config.MaxMessageSize, config.MaxInboundBytesPerSecond} {
+		s.SessionSettings.Set(setting, "0")
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, setting)
+		s.SessionSettings.Set(setting, "1024")
+	}
+}
+
+func (s *SessionFactorySuite) TestHighResolutionLatency() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(uint(coarseLatencyBits), session.stats.inboundLatency.subBucketBits)
+
+	s.SessionSettings.Set(config.HighResolutionLatency, "Y")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(uint(highResolutionLatencyBits), session.stats.inboundLatency.subBucketBits)
+	s.Equal(uint(highResolutionLatencyBits), session.stats.outboundLatency.subBucketBits)
+}
+
+func (s *SessionFactorySuite) TestApplicationCallbackTimeout() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Zero(session.ApplicationCallbackTimeout)
+
+	s.SessionSettings.Set(config.ApplicationCallbackTimeout, "2s")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(2*time.Second, session.ApplicationCallbackTimeout)
+
+	s.SessionSettings.Set(config.ApplicationCallbackTimeout, "soon")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestTestRequestDelayMultiplier() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(0.2, session.TestRequestDelayMultiplier)
+
+	s.SessionSettings.Set(config.TestRequestDelayMultiplier, "0.5")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(0.5, session.TestRequestDelayMultiplier)
+
+	for _, invalid := range []string{"0", "-1", "notafloat"} {
+		s.SessionSettings.Set(config.TestRequestDelayMultiplier, invalid)
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err)
+	}
+}
+
 func (s *SessionFactorySuite) TestEnableLastMsgSeqNumProcessed() {
 	var tests = []struct {
 		setting  string
@@ -167,6 +234,120 @@ func (s *SessionFactorySuite) TestEnableLastMsgSeqNumProcessed() {
 	}
 }
 
+func (s *SessionFactorySuite) TestSendLogoutBeforeDisconnectFromLogon() {
+	var tests = []struct {
+		setting  string
+		expected bool
+	}{{"Y", true}, {"N", false}}
+
+	for _, test := range tests {
+		s.SetupTest()
+		s.SessionSettings.Set(config.SendLogoutBeforeDisconnectFromLogon, test.setting)
+		session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.Nil(err)
+		s.NotNil(session)
+
+		s.Equal(test.expected, session.SendLogoutBeforeDisconnect)
+	}
+}
+
+func (s *SessionFactorySuite) TestThrottle() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.throttle)
+
+	s.SessionSettings.Set(config.ThrottleMessagesPerSecond, "10")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Require().NotNil(session.throttle)
+	s.Equal(ThrottleBlock, session.throttle.mode)
+
+	s.SessionSettings.Set(config.ThrottleBurst, "20")
+	s.SessionSettings.Set(config.ThrottleMode, "Queue")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Require().NotNil(session.throttle)
+	s.Equal(ThrottleQueue, session.throttle.mode)
+}
+
+func (s *SessionFactorySuite) TestThrottleInvalid() {
+	var tests = []struct {
+		setting, value string
+	}{
+		{config.ThrottleMessagesPerSecond, "-1"},
+		{config.ThrottleBurst, "0"},
+		{config.ThrottleMode, "Drop"},
+	}
+
+	for _, test := range tests {
+		s.SetupTest()
+		s.SessionSettings.Set(config.ThrottleMessagesPerSecond, "10")
+		s.SessionSettings.Set(test.setting, test.value)
+
+		_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, test.setting)
+	}
+}
+
+func (s *SessionFactorySuite) TestMaxQueuedMessages() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.queueLimit)
+
+	s.SessionSettings.Set(config.MaxQueuedMessages, "100")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Require().NotNil(session.queueLimit)
+	s.Equal(100, session.queueLimit.capacity)
+	s.Equal(QueueFullBlock, session.queueLimit.policy)
+	s.Equal(defaultQueueFullTimeout, session.queueLimit.timeout)
+
+	s.SessionSettings.Set(config.QueueFullPolicy, "DropOldestQuote")
+	s.SessionSettings.Set(config.QueueFullTimeout, "500ms")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(QueueFullDropOldestQuote, session.queueLimit.policy)
+	s.Equal(500*time.Millisecond, session.queueLimit.timeout)
+
+	for _, invalid := range []struct{ setting, value string }{
+		{config.MaxQueuedMessages, "-1"},
+		{config.QueueFullPolicy, "Drop"},
+		{config.QueueFullTimeout, "0s"},
+		{config.QueueFullTimeout, "10"},
+	} {
+		s.SetupTest()
+		s.SessionSettings.Set(config.MaxQueuedMessages, "100")
+		s.SessionSettings.Set(invalid.setting, invalid.value)
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid.setting)
+	}
+}
+
+func (s *SessionFactorySuite) TestDuplicateWindow() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.duplicates)
+
+	s.SessionSettings.Set(config.DuplicateWindow, "1000")
+	s.SessionSettings.Set(config.DuplicatePolicy, "Suppress")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Require().NotNil(session.duplicates)
+	s.Equal(1000, cap(session.duplicates.order))
+	s.Equal(DuplicateSuppress, session.duplicates.policy)
+
+	for _, invalid := range []struct{ setting, value string }{
+		{config.DuplicateWindow, "-1"},
+		{config.DuplicatePolicy, "Drop"},
+	} {
+		s.SetupTest()
+		s.SessionSettings.Set(config.DuplicateWindow, "1000")
+		s.SessionSettings.Set(invalid.setting, invalid.value)
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid.setting)
+	}
+}
+
 func (s *SessionFactorySuite) TestCheckLatency() {
 	var tests = []struct {
 		setting  string
@@ -426,6 +607,112 @@ func (s *SessionFactorySuite) TestStartEndDayWithWeekdaysError() {
 	s.NotNil(err)
 }
 
+func (s *SessionFactorySuite) TestResetSeqTime() {
+	s.SessionSettings.Set(config.ResetSeqTime, "17:00:00")
+	s.SessionSettings.Set(config.TimeZone, "America/New_York")
+
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.True(session.EnableResetSeqTime)
+	s.Equal(internal.NewTimeOfDay(17, 0, 0), session.ResetSeqTime)
+	s.Equal("America/New_York", session.ResetSeqTimeLocation.String())
+
+	s.SessionSettings.Set(config.ResetSeqTime, "1700")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestHolidays() {
+	holidayFile := path.Join(s.T().TempDir(), "holidays")
+	s.Require().Nil(os.WriteFile(holidayFile, []byte("# exchange holidays\n2025-01-01\n\n2025-04-18\n"), 0o600))
+
+	s.SessionSettings.Set(config.StartTime, "17:00:00")
+	s.SessionSettings.Set(config.EndTime, "17:00:00")
+	s.SessionSettings.Set(config.StartDay, "Sunday")
+	s.SessionSettings.Set(config.EndDay, "Friday")
+	s.SessionSettings.Set(config.Holidays, "2024-12-25, 2024-12-26")
+	s.SessionSettings.Set(config.HolidayFile, holidayFile)
+
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.NotNil(session.SessionTime)
+
+	expectedRange, err := internal.NewUTCWeekRange(
+		internal.NewTimeOfDay(17, 0, 0), internal.NewTimeOfDay(17, 0, 0),
+		time.Sunday, time.Friday,
+	)
+	s.Nil(err)
+	expectedRange.AddHolidays(
+		internal.Date{Year: 2024, Month: time.December, Day: 25},
+		internal.Date{Year: 2024, Month: time.December, Day: 26},
+		internal.Date{Year: 2025, Month: time.January, Day: 1},
+		internal.Date{Year: 2025, Month: time.April, Day: 18},
+	)
+	s.Equal(*expectedRange, *session.SessionTime)
+}
+
+func (s *SessionFactorySuite) TestHolidaysParseError() {
+	s.SessionSettings.Set(config.StartTime, "12:00:00")
+	s.SessionSettings.Set(config.EndTime, "14:00:00")
+	s.SessionSettings.Set(config.Holidays, "2024/12/25")
+
+	_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+
+	s.SetupTest()
+	s.SessionSettings.Set(config.StartTime, "12:00:00")
+	s.SessionSettings.Set(config.EndTime, "14:00:00")
+	s.SessionSettings.Set(config.HolidayFile, path.Join(s.T().TempDir(), "missing"))
+
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestHolidaysWithoutSessionTime() {
+	s.SessionSettings.Set(config.Holidays, "2024-12-25")
+
+	_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestAppDataDictionaryForApplVerID() {
+	s.SessionID = SessionID{BeginString: BeginStringFIXT11, TargetCompID: "TW", SenderCompID: "ISLD"}
+	s.SessionSettings.Set(config.DefaultApplVerID, "FIX.5.0SP2")
+	s.SessionSettings.Set(config.TransportDataDictionary, "spec/FIXT11.xml")
+	s.SessionSettings.Set(config.AppDataDictionary, "spec/FIX50SP2.xml")
+
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.appDataDictionaries)
+	s.Nil(session.appValidators)
+
+	s.SessionSettings.Set(config.AppDataDictionary+".FIX.4.4", "spec/FIX44.xml")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Len(session.appDataDictionaries, 2)
+	s.Equal(session.appDataDictionary, session.appDataDictionaries["9"])
+	s.Equal(4, session.appDataDictionaries["6"].Major)
+	s.Equal(4, session.appDataDictionaries["6"].Minor)
+	s.Equal(session.Validator, session.appValidators["9"])
+	s.NotNil(session.appValidators["6"])
+
+	s.SessionSettings.Set(config.AppDataDictionary+".FIX.4.2", "spec/missing.xml")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestDataDictionaryFS() {
+	s.SessionSettings.Set(config.DataDictionary, "FIX42.xml")
+	_, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+
+	s.SessionSettings.SetDataDictionaryFS(os.DirFS("spec"))
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(2, session.appDataDictionary.Minor)
+	s.NotNil(session.Validator)
+}
+
 func (s *SessionFactorySuite) TestDefaultApplVerID() {
 	s.SessionID = SessionID{BeginString: BeginStringFIXT11, TargetCompID: "TW", SenderCompID: "ISLD"}
 
@@ -649,6 +936,73 @@ func (s *SessionFactorySuite) TestConfigureSocketConnectAddress() {
 	}
 }
 
+func (s *SessionFactorySuite) TestReconnectBackoff() {
+	s.SessionSettings.Set(config.SocketConnectHost, "127.0.0.1")
+	s.SessionSettings.Set(config.SocketConnectPort, "5000")
+	s.SessionSettings.Set(config.HeartBtInt, "34")
+	s.sessionFactory.BuildInitiators = true
+
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(1.0, session.ReconnectBackoffMultiplier)
+	s.Zero(session.MaxReconnectInterval)
+	s.Zero(session.ReconnectJitter)
+	s.Zero(session.MaxReconnectAttempts)
+
+	s.SessionSettings.Set(config.ReconnectBackoffMultiplier, "2")
+	s.SessionSettings.Set(config.MaxReconnectInterval, "5m")
+	s.SessionSettings.Set(config.ReconnectJitter, "0.2")
+	s.SessionSettings.Set(config.MaxReconnectAttempts, "10")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(2.0, session.ReconnectBackoffMultiplier)
+	s.Equal(5*time.Minute, session.MaxReconnectInterval)
+	s.Equal(0.2, session.ReconnectJitter)
+	s.Equal(10, session.MaxReconnectAttempts)
+
+	for _, invalid := range []struct{ setting, value string }{
+		{config.ReconnectBackoffMultiplier, "0.5"},
+		{config.MaxReconnectInterval, "1s"},
+		{config.ReconnectJitter, "1"},
+		{config.MaxReconnectAttempts, "-1"},
+	} {
+		s.SetupTest()
+		s.SessionSettings.Set(config.SocketConnectHost, "127.0.0.1")
+		s.SessionSettings.Set(config.SocketConnectPort, "5000")
+		s.SessionSettings.Set(config.HeartBtInt, "34")
+		s.sessionFactory.BuildInitiators = true
+		s.SessionSettings.Set(invalid.setting, invalid.value)
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+		s.NotNil(err, invalid.setting)
+	}
+}
+
+func (s *SessionFactorySuite) TestConfigureSocketConnectTimeoutAndRotation() {
+	session := new(session)
+	s.SessionSettings.Set(config.SocketConnectHost, "127.0.0.1")
+	s.SessionSettings.Set(config.SocketConnectPort, "3000")
+	s.SessionSettings.Set(config.SocketConnectHost+"1", "127.0.0.2")
+	s.SessionSettings.Set(config.SocketConnectPort+"1", "4000")
+
+	s.Require().Nil(s.configureSocketConnectAddress(session, s.SessionSettings))
+	s.Equal([]time.Duration{0, 0}, session.SocketConnectTimeout)
+	s.False(session.SocketConnectSticky)
+
+	s.SessionSettings.Set(config.SocketConnectTimeout, "5")
+	s.SessionSettings.Set(config.SocketConnectTimeout+"1", "500ms")
+	s.SessionSettings.Set(config.SocketConnectRotation, "Sticky")
+	s.Require().Nil(s.configureSocketConnectAddress(session, s.SessionSettings))
+	s.Equal([]time.Duration{5 * time.Second, 500 * time.Millisecond}, session.SocketConnectTimeout)
+	s.True(session.SocketConnectSticky)
+
+	s.SessionSettings.Set(config.SocketConnectRotation, "Random")
+	s.NotNil(s.configureSocketConnectAddress(session, s.SessionSettings))
+
+	s.SessionSettings.Set(config.SocketConnectRotation, "RoundRobin")
+	s.SessionSettings.Set(config.SocketConnectTimeout+"1", "-1s")
+	s.NotNil(s.configureSocketConnectAddress(session, s.SessionSettings))
+}
+
 func (s *SessionFactorySuite) TestConfigureSocketConnectAddressMulti() {
 	session := new(session)
 	s.SessionSettings.Set(config.SocketConnectHost, "127.0.0.1")
@@ -744,3 +1098,113 @@ func (s *SessionFactorySuite) TestPersistMessages() {
 		s.Equal(test.expected, session.DisableMessagePersist)
 	}
 }
+
+func (s *SessionFactorySuite) TestOutboundBatching() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(writeBatching{}, session.writeBatching())
+
+	s.SessionSettings.Set(config.OutboundBatchMaxBytes, "65536")
+	s.SessionSettings.Set(config.OutboundBatchFlushLatency, "500us")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(writeBatching{maxBytes: 65536, flushLatency: 500 * time.Microsecond}, session.writeBatching())
+
+	s.SessionSettings.Set(config.OutboundBatchMaxBytes, "-1")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+
+	s.SessionSettings.Set(config.OutboundBatchMaxBytes, "1")
+	s.SessionSettings.Set(config.OutboundBatchFlushLatency, "-1ms")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestTransientMessageTypes() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.TransientMsgTypes)
+
+	s.SessionSettings.Set(config.TransientMessageTypes, "W, S,i")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(map[string]bool{"W": true, "S": true, "i": true}, session.TransientMsgTypes)
+}
+
+func (s *SessionFactorySuite) TestZeroAllocParser() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.NotNil(session.zeroAllocParser)
+
+	s.SessionSettings.Set(config.ZeroAllocParser, "N")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Nil(session.zeroAllocParser)
+
+	s.SessionSettings.Set(config.ZeroAllocParser, "maybe")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestPreserveMessageFieldsOrder() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.False(session.PreserveMessageFieldsOrder)
+
+	s.SessionSettings.Set(config.PreserveMessageFieldsOrder, "Y")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.True(session.PreserveMessageFieldsOrder)
+}
+
+func (s *SessionFactorySuite) TestValidationPolicy() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.True(session.validationPolicy.checks(CheckRequiredFields, "D"))
+	s.True(session.validationPolicy.checks(CheckFieldValues, "D"))
+
+	s.SessionSettings.Set(config.ValidateRequiredFields, "N")
+	s.SessionSettings.Set(config.ValidateFieldValues, "N")
+	s.SessionSettings.Set(config.ValidationMsgTypeOverrides, "8=Unknown")
+	s.SessionSettings.Set(config.ValidationTagOverrides, "9001=Order")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.False(session.validationPolicy.checks(CheckRequiredFields, "D"))
+	s.False(session.validationPolicy.checks(CheckFieldValues, "D"))
+	s.False(session.validationPolicy.checks(CheckUnknownFields, "8"))
+	s.True(session.validationPolicy.checks(CheckUnknownFields, "D"))
+	s.False(session.validationPolicy.checksTag(CheckFieldOrder, "D", Tag(9001)))
+	s.True(session.validationPolicy.checksTag(CheckFieldOrder, "D", Tag(58)))
+
+	s.SessionSettings.Set(config.ValidationMsgTypeOverrides, "8=Everything")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+
+	s.SessionSettings.Set(config.ValidationMsgTypeOverrides, "8=Unknown")
+	s.SessionSettings.Set(config.ValidationTagOverrides, "Text=Values")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestEnumValidation() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(EnumStrict, session.validationPolicy.enumValidation)
+	s.NotNil(session.validationPolicy.onUnknownEnum)
+
+	s.SessionSettings.Set(config.EnumValidation, "Warn")
+	s.SessionSettings.Set(config.AdditionalEnumValues, "40=X,Y")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Nil(err)
+	s.Equal(EnumWarn, session.validationPolicy.enumValidation)
+	s.True(session.validationPolicy.hasEnumValue(Tag(40), []byte("Y")))
+
+	s.SessionSettings.Set(config.EnumValidation, "Loose")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+
+	s.SessionSettings.Set(config.EnumValidation, "Lenient")
+	s.SessionSettings.Set(config.AdditionalEnumValues, "OrdType=X")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
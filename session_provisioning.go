@@ -0,0 +1,92 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+// AddSession creates a session with sessionSettings, overlaying the default settings of the Acceptor, and accepts it
+// if the Acceptor is started, without restarting other sessions. Its MessageStore and Log are created with the
+// factories of the Acceptor. Returns an error if the settings are invalid or a session with the same SessionID
+// exists.
+func (a *Acceptor) AddSession(sessionSettings *SessionSettings) (SessionID, error) {
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+
+	a.sessionsLock.RLock()
+	settings := a.settings.clone()
+	a.sessionsLock.RUnlock()
+
+	sessionID, err := settings.AddSession(sessionSettings)
+	if err != nil {
+		return sessionID, err
+	}
+
+	return sessionID, a.applySettings(settings, false)
+}
+
+// RemoveSession logs out and removes the session matching the session id, added by AddSession or configured when the
+// Acceptor was created. If drain is true, messages queued for sending are sent before the Logout, see
+// LogoutAndDrain. Dynamic sessions cannot be removed.
+func (a *Acceptor) RemoveSession(sessionID SessionID, drain bool) error {
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+
+	a.sessionsLock.RLock()
+	settings := a.settings.clone()
+	a.sessionsLock.RUnlock()
+
+	if !settings.removeSession(sessionID) {
+		return errUnknownSession
+	}
+
+	return a.applySettings(settings, drain)
+}
+
+// AddSession creates a session with sessionSettings, overlaying the default settings of the Initiator, and connects
+// it if the Initiator is started, without restarting other sessions. Its MessageStore and Log are created with the
+// factories of the Initiator. Returns an error if the settings are invalid or a session with the same SessionID
+// exists.
+func (i *Initiator) AddSession(sessionSettings *SessionSettings) (SessionID, error) {
+	i.reloadLock.Lock()
+	defer i.reloadLock.Unlock()
+
+	i.sessionsLock.RLock()
+	settings := i.settings.clone()
+	i.sessionsLock.RUnlock()
+
+	sessionID, err := settings.AddSession(sessionSettings)
+	if err != nil {
+		return sessionID, err
+	}
+
+	return sessionID, i.applySettings(settings, false)
+}
+
+// RemoveSession logs out and removes the session matching the session id, added by AddSession or configured when the
+// Initiator was created. If drain is true, messages queued for sending are sent before the Logout, see
+// LogoutAndDrain.
+func (i *Initiator) RemoveSession(sessionID SessionID, drain bool) error {
+	i.reloadLock.Lock()
+	defer i.reloadLock.Unlock()
+
+	i.sessionsLock.RLock()
+	settings := i.settings.clone()
+	i.sessionsLock.RUnlock()
+
+	if !settings.removeSession(sessionID) {
+		return errUnknownSession
+	}
+
+	return i.applySettings(settings, drain)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestAddAndRemoveSession(t *testing.T) {
+	host := "pipe://provisioning"
+	acceptorApp := newReloadApp()
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketAcceptHost: host}, reloadSessionSettings("ACCEPTOR", "A", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	initiatorApp := newReloadApp()
+	initiator, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5015", config.HeartBtInt: "30"},
+			reloadSessionSettings("A", "ACCEPTOR", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	waitForSession(t, initiatorApp.loggedOn, SessionID{BeginString: BeginStringFIX42, SenderCompID: "A", TargetCompID: "ACCEPTOR"})
+	waitForSession(t, acceptorApp.loggedOn, SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "A"})
+
+	acceptorB, err := acceptor.AddSession(reloadSessionSettings("ACCEPTOR", "B", nil))
+	require.NoError(t, err)
+	assert.Equal(t, SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "B"}, acceptorB)
+
+	_, err = acceptor.AddSession(reloadSessionSettings("ACCEPTOR", "B", nil))
+	assert.Error(t, err, "duplicate session")
+
+	// The session is created with the default settings of the Initiator.
+	initiatorB, err := initiator.AddSession(reloadSessionSettings("B", "ACCEPTOR", nil))
+	require.NoError(t, err)
+	waitForSession(t, initiatorApp.loggedOn, initiatorB)
+	waitForSession(t, acceptorApp.loggedOn, acceptorB)
+
+	require.NoError(t, initiator.RemoveSession(initiatorB, true))
+	waitForSession(t, initiatorApp.loggedOut, initiatorB)
+	waitForSession(t, acceptorApp.loggedOut, acceptorB)
+	assert.ErrorIs(t, ResetSession(initiatorB), errUnknownSession)
+
+	require.NoError(t, acceptor.RemoveSession(acceptorB, false))
+	assert.ErrorIs(t, ResetSession(acceptorB), errUnknownSession)
+	assert.Len(t, acceptor.Health().Sessions, 1)
+	assert.Len(t, initiator.Health().Sessions, 1)
+
+	assert.ErrorIs(t, acceptor.RemoveSession(acceptorB, false), errUnknownSession)
+	assert.ErrorIs(t, initiator.RemoveSession(initiatorB, true), errUnknownSession)
+	assertNoSession(t, initiatorApp.loggedOut)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+)
+
+// InboundLogon is a Logon received by an acceptor on a new connection, before its session is known.
+type InboundLogon struct {
+	// SessionID is the SessionID of the Logon from the side of the acceptor, without Qualifier, so its TargetCompID is
+	// the SenderCompID of the Logon.
+	SessionID SessionID
+
+	// Logon is the Logon message, e.g. to read OnBehalfOfCompID(115).
+	Logon *Message
+
+	// Address is the address of the listener the Logon is received on.
+	Address string
+
+	// RemoteAddr is the address of the counterparty.
+	RemoteAddr net.Addr
+
+	// PeerCertificates are the certificates presented by the counterparty if the connection uses TLS, starting with
+	// its client certificate.
+	PeerCertificates []*x509.Certificate
+}
+
+// CommonName returns the common name of the TLS client certificate of the counterparty, or "" if there is none.
+func (l InboundLogon) CommonName() string {
+	if len(l.PeerCertificates) == 0 {
+		return ""
+	}
+
+	return l.PeerCertificates[0].Subject.CommonName
+}
+
+// SessionResolver is an interface allowing an acceptor to choose the session of a Logon received on a new connection,
+// e.g. for tenants sharing comp IDs that are differentiated by OnBehalfOfCompID(115) or their TLS client certificate.
+type SessionResolver interface {
+	// ResolveSession returns the SessionID of the session logon is for, which must have the comp IDs of
+	// logon.SessionID and may have a Qualifier. If no session is configured for it, the session is created with
+	// settings overlaying the default settings of the Acceptor or, if settings are nil, from a matching session
+	// template or as a dynamic session. A non-nil error rejects the logon, and its text is sent to the counterparty in
+	// Text(58) of the Logout.
+	ResolveSession(logon InboundLogon) (sessionID SessionID, settings *SessionSettings, err error)
+}
+
+// resolveSession returns the SessionID and settings the SessionResolver chooses for the Logon for sessID.
+func (a *Acceptor) resolveSession(sessID SessionID, logon *Message, address string, netConn net.Conn) (SessionID, *SessionSettings, error) {
+	inbound := InboundLogon{SessionID: sessID, Logon: logon, Address: address, RemoteAddr: netConn.RemoteAddr()}
+	if tlsConn, ok := netConn.(*tls.Conn); ok {
+		inbound.PeerCertificates = tlsConn.ConnectionState().PeerCertificates
+	}
+
+	resolved, settings, err := a.sessionResolver.ResolveSession(inbound)
+	if err != nil {
+		return resolved, nil, err
+	}
+
+	compIDs := resolved
+	compIDs.Qualifier = ""
+	if compIDs != sessID {
+		return resolved, nil, fmt.Errorf("resolved session %v does not have the comp IDs of the Logon", resolved)
+	}
+
+	return resolved, settings, nil
+}
+
+// configuredSession returns the configured session for sessID, ignoring the Qualifier of sessID if it is empty. It
+// must be called with sessionsLock held.
+func (a *Acceptor) configuredSession(sessID SessionID) (*session, bool) {
+	key := sessID
+	key.Qualifier = ""
+
+	s, ok := a.sessions[key]
+	if !ok || (sessID.Qualifier != "" && sessID != s.sessionID) {
+		return nil, false
+	}
+
+	return s, true
+}
+
+// SetSessionResolver sets a SessionResolver to choose the sessions of Logons received on new connections. It must be
+// called before Start.
+func (a *Acceptor) SetSessionResolver(resolver SessionResolver) {
+	a.sessionResolver = resolver
+}
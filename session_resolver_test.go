@@ -0,0 +1,136 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestInboundLogon_CommonName(t *testing.T) {
+	assert.Equal(t, "", InboundLogon{}.CommonName())
+
+	logon := InboundLogon{PeerCertificates: []*x509.Certificate{
+		{Subject: pkix.Name{CommonName: "tenant"}},
+		{Subject: pkix.Name{CommonName: "issuer"}},
+	}}
+	assert.Equal(t, "tenant", logon.CommonName())
+}
+
+// tenantApp sends the Qualifier of the session in OnBehalfOfCompID(115) of its Logon.
+type tenantApp struct {
+	reloadApp
+}
+
+func (a tenantApp) ToAdmin(msg *Message, sessionID SessionID) {
+	if msg.IsMsgTypeOf(string(msgTypeLogon)) {
+		msg.Header.SetString(tagOnBehalfOfCompID, sessionID.Qualifier)
+	}
+}
+
+// tenantResolver qualifies sessions with the OnBehalfOfCompID(115) of their Logon.
+type tenantResolver struct {
+	logons chan InboundLogon
+}
+
+func (r tenantResolver) ResolveSession(logon InboundLogon) (SessionID, *SessionSettings, error) {
+	r.logons <- logon
+
+	tenant, err := logon.Logon.Header.GetString(tagOnBehalfOfCompID)
+	if err != nil {
+		return SessionID{}, nil, err
+	}
+
+	sessionID := logon.SessionID
+	sessionID.Qualifier = tenant
+	switch tenant {
+	case "CONFIGURED":
+		return sessionID, nil, nil
+	case "BLOCKED":
+		return sessionID, nil, errors.New("Unknown tenant")
+	case "OTHER":
+		sessionID.TargetCompID = "OTHER"
+		return sessionID, NewSessionSettings(), nil
+	}
+
+	settings := NewSessionSettings()
+	settings.Set(config.ResetOnLogon, "Y")
+	return sessionID, settings, nil
+}
+
+func TestAcceptor_SessionResolver(t *testing.T) {
+	host := "pipe://session_resolver"
+
+	acceptorApp := newReloadApp()
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketAcceptHost: host},
+			reloadSessionSettings("ACCEPTOR", "CLIENT", map[string]string{config.SessionQualifier: "CONFIGURED"})),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	resolver := tenantResolver{logons: make(chan InboundLogon, 10)}
+	acceptor.SetSessionResolver(resolver)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	initiatorApp := tenantApp{newReloadApp()}
+	initiatorSettings := reloadSettings(t, map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5019", config.HeartBtInt: "30"})
+	for _, tenant := range []string{"CONFIGURED", "T1", "T2", "BLOCKED", "OTHER"} {
+		_, err = initiatorSettings.AddSession(reloadSessionSettings("CLIENT", "ACCEPTOR", map[string]string{config.SessionQualifier: tenant}))
+		require.NoError(t, err)
+	}
+	initiator, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(), initiatorSettings, NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	loggedOn := map[SessionID]bool{}
+	for range 3 {
+		select {
+		case sessionID := <-acceptorApp.loggedOn:
+			loggedOn[sessionID] = true
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for logons")
+		}
+	}
+	assertNoSession(t, acceptorApp.loggedOn)
+
+	client := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "CLIENT"}
+	tenant := func(qualifier string) SessionID {
+		sessionID := client
+		sessionID.Qualifier = qualifier
+		return sessionID
+	}
+	assert.Equal(t, map[SessionID]bool{tenant("CONFIGURED"): true, tenant("T1"): true, tenant("T2"): true}, loggedOn)
+
+	session, ok := lookupSession(tenant("T1"))
+	require.True(t, ok)
+	assert.True(t, session.ResetOnLogon, "the session is created with the resolved settings")
+
+	require.Len(t, resolver.logons, 5)
+	logon := <-resolver.logons
+	assert.Equal(t, client, logon.SessionID)
+	assert.Equal(t, host, logon.Address)
+	assert.NotNil(t, logon.RemoteAddr)
+	assert.Empty(t, logon.CommonName())
+}
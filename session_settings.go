@@ -16,7 +16,10 @@
 package quickfix
 
 import (
+	"bytes"
 	"fmt"
+	"io/fs"
+	"reflect"
 	"strconv"
 	"time"
 )
@@ -24,6 +27,9 @@ import (
 // SessionSettings maps session settings to values with typed accessors.
 type SessionSettings struct {
 	settings map[string][]byte
+
+	// dataDictionaryFS, if set, is the file system data dictionary paths are opened in.
+	dataDictionaryFS fs.FS
 }
 
 // ConditionallyRequiredSetting indicates a missing setting.
@@ -79,6 +85,13 @@ func (s *SessionSettings) Set(setting string, val string) {
 	s.settings[setting] = []byte(val)
 }
 
+// SetDataDictionaryFS sets the file system, such as an embed.FS, in which the DataDictionary, TransportDataDictionary
+// and AppDataDictionary paths are opened, in place of the operating system's. Set on the global settings it applies
+// to every session.
+func (s *SessionSettings) SetDataDictionaryFS(fsys fs.FS) {
+	s.dataDictionaryFS = fsys
+}
+
 // HasSetting returns true if a setting is set, false if not.
 func (s *SessionSettings) HasSetting(setting string) bool {
 	_, ok := s.settings[setting]
@@ -120,6 +133,21 @@ func (s *SessionSettings) IntSetting(setting string) (int, error) {
 	return 0, IncorrectFormatForSetting{Setting: setting, Value: rawVal, Err: err}
 }
 
+// FloatSetting returns the requested setting parsed as a float64.
+// Returns an error if the setting is not set or cannot be parsed as a float64.
+func (s *SessionSettings) FloatSetting(setting string) (float64, error) {
+	rawVal, err := s.RawSetting(setting)
+	if err != nil {
+		return 0, err
+	}
+
+	if val, err := strconv.ParseFloat(string(rawVal), 64); err == nil {
+		return val, nil
+	}
+
+	return 0, IncorrectFormatForSetting{Setting: setting, Value: rawVal, Err: err}
+}
+
 // DurationSetting returns the requested setting parsed as a time.Duration.
 // Returns an error if the setting is not set or cannot be parsed as a time.Duration.
 func (s *SessionSettings) DurationSetting(setting string) (time.Duration, error) {
@@ -156,6 +184,10 @@ func (s *SessionSettings) overlay(overlay *SessionSettings) {
 	for key, val := range overlay.settings {
 		s.settings[key] = val
 	}
+
+	if overlay.dataDictionaryFS != nil {
+		s.dataDictionaryFS = overlay.dataDictionaryFS
+	}
 }
 
 func (s *SessionSettings) clone() *SessionSettings {
@@ -164,6 +196,34 @@ func (s *SessionSettings) clone() *SessionSettings {
 	for k, v := range s.settings {
 		sClone.settings[k] = v
 	}
+	sClone.dataDictionaryFS = s.dataDictionaryFS
 
 	return sClone
 }
+
+// equal returns true if s and other have the same settings with the same values.
+func (s *SessionSettings) equal(other *SessionSettings) bool {
+	if len(s.settings) != len(other.settings) || !sameFS(s.dataDictionaryFS, other.dataDictionaryFS) {
+		return false
+	}
+
+	for key, val := range s.settings {
+		otherVal, ok := other.settings[key]
+		if !ok || !bytes.Equal(val, otherVal) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sameFS returns true if fsys1 and fsys2 are the same file system. File systems of types that are not comparable,
+// such as fstest.MapFS, are never the same.
+func sameFS(fsys1, fsys2 fs.FS) bool {
+	if fsys1 == nil || fsys2 == nil {
+		return fsys1 == nil && fsys2 == nil
+	}
+
+	t := reflect.TypeOf(fsys1)
+	return t == reflect.TypeOf(fsys2) && t.Comparable() && fsys1 == fsys2
+}
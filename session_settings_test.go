@@ -77,6 +77,33 @@ func TestSessionSettings_IntSettings(t *testing.T) {
 	}
 }
 
+func TestSessionSettings_FloatSettings(t *testing.T) {
+	s := NewSessionSettings()
+	if _, err := s.FloatSetting(config.TestRequestDelayMultiplier); err == nil {
+		t.Error("Expected error for unknown setting")
+	}
+
+	s.Set(config.TestRequestDelayMultiplier, "notafloat")
+	_, err := s.FloatSetting(config.TestRequestDelayMultiplier)
+	if err == nil {
+		t.Error("Expected error for unparsable value")
+	}
+
+	if err.Error() != `"notafloat" is invalid for TestRequestDelayMultiplier` {
+		t.Errorf("Expected %s, got %s", `"notafloat" is invalid for TestRequestDelayMultiplier`, err)
+	}
+
+	s.Set(config.TestRequestDelayMultiplier, "0.5")
+	val, err := s.FloatSetting(config.TestRequestDelayMultiplier)
+	if err != nil {
+		t.Error("Unexpected err", err)
+	}
+
+	if val != 0.5 {
+		t.Errorf("Expected %v, got %v", 0.5, val)
+	}
+}
+
 func TestSessionSettings_BoolSettings(t *testing.T) {
 	s := NewSessionSettings()
 	if _, err := s.BoolSetting(config.ResetOnLogon); err == nil {
@@ -26,6 +26,9 @@ type stateMachine struct {
 	State                 sessionState
 	pendingStop, stopped  bool
 	notifyOnInSessionTime chan interface{}
+
+	// lastResetCheck is when CheckResetTime last ran.
+	lastResetCheck time.Time
 }
 
 func (sm *stateMachine) Start(s *session) {
@@ -33,7 +36,7 @@ func (sm *stateMachine) Start(s *session) {
 	sm.stopped = false
 
 	sm.State = latentState{}
-	sm.CheckSessionTime(s, time.Now())
+	sm.CheckSessionTime(s, s.clock.Now())
 }
 
 func (sm *stateMachine) Connect(session *session) {
@@ -55,6 +58,7 @@ func (sm *stateMachine) Connect(session *session) {
 			session.logError(err)
 			return
 		}
+		session.publishEvent(EventSeqNumReset, "Reset on logon")
 	}
 
 	session.log.OnEvent("Sending logon request")
@@ -65,7 +69,7 @@ func (sm *stateMachine) Connect(session *session) {
 
 	sm.setState(session, logonState{})
 	// Fire logon timeout event after the pre-configured delay period.
-	time.AfterFunc(session.LogonTimeout, func() { session.sessionEvent <- internal.LogonTimeout })
+	session.afterEvent(session.LogonTimeout, internal.LogonTimeout)
 }
 
 func (sm *stateMachine) Stop(session *session) {
@@ -84,22 +88,32 @@ func (sm *stateMachine) Disconnected(session *session) {
 }
 
 func (sm *stateMachine) Incoming(session *session, m fixIn) {
-	sm.CheckSessionTime(session, time.Now())
+	sm.CheckSessionTime(session, session.clock.Now())
 	if !sm.IsConnected() {
 		return
 	}
 
 	session.log.OnIncoming(m.bytes.Bytes())
 
-	msg := NewMessage()
-	if err := ParseMessageWithDataDictionary(msg, m.bytes, session.transportDataDictionary, session.appDataDictionary); err != nil {
+	ctx, span := session.startSpan(session.connContext(), SpanInbound)
+	_, parseSpan := session.startSpan(ctx, SpanParse)
+	msg, err := session.parseIncoming(m.bytes)
+	if err != nil {
+		parseSpan.End(err)
+		span.End(err)
 		session.log.OnEventf("Msg Parse Error: %v, %q", err.Error(), m.bytes)
 	} else {
+		setSpanMessage(parseSpan, msg)
+		parseSpan.End(nil)
+		setSpanMessage(span, msg)
+		msg.ctx = ctx
 		msg.ReceiveTime = m.receiveTime
 		sm.fixMsgIn(session, msg)
+		span.End(nil)
+		session.releaseIncoming(msg)
 	}
 
-	session.peerTimer.Reset(time.Duration(float64(1.2) * float64(session.HeartBtInt)))
+	session.peerTimer.Reset(session.peerTimeout())
 }
 
 func (sm *stateMachine) fixMsgIn(session *session, m *Message) {
@@ -107,11 +121,13 @@ func (sm *stateMachine) fixMsgIn(session *session, m *Message) {
 }
 
 func (sm *stateMachine) SendAppMessages(session *session) {
-	sm.CheckSessionTime(session, time.Now())
+	sm.CheckSessionTime(session, session.clock.Now())
 
 	session.sendMutex.Lock()
 	defer session.sendMutex.Unlock()
 
+	session.restoreOutboundQueue()
+	session.drainThrottleQueue()
 	if session.IsLoggedOn() {
 		session.sendQueued(false)
 	} else {
@@ -120,7 +136,7 @@ func (sm *stateMachine) SendAppMessages(session *session) {
 }
 
 func (sm *stateMachine) Timeout(session *session, e internal.Event) {
-	sm.CheckSessionTime(session, time.Now())
+	sm.CheckSessionTime(session, session.clock.Now())
 	sm.setState(session, sm.State.Timeout(session, e))
 }
 
@@ -155,12 +171,38 @@ func (sm *stateMachine) CheckSessionTime(session *session, now time.Time) {
 	}
 }
 
+// CheckResetTime sends a Logon with ResetSeqNumFlag=Y if the ResetSeqTime has passed since the last check.
+// The reset is only initiated on a logged on session, as the Logon exchange resets both sides.
 func (sm *stateMachine) CheckResetTime(session *session, now time.Time) {
-	if session.EnableResetSeqTime {
-		ts := internal.NewTimeOfDay(now.Clock())
-		if session.ResetSeqTime == ts {
-			session.sendLogonInReplyTo(true, nil)
-		}
+	if !session.EnableResetSeqTime {
+		return
+	}
+
+	last := sm.lastResetCheck
+	sm.lastResetCheck = now
+	if last.IsZero() || !now.After(last) {
+		last = now.Add(-time.Second)
+	}
+
+	loc := session.ResetSeqTimeLocation
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	year, month, day := now.In(loc).Date()
+	hour, minute, second := session.ResetSeqTime.Clock()
+	resetTime := time.Date(year, month, day, hour, minute, second, 0, loc)
+	if resetTime.After(now) {
+		resetTime = resetTime.AddDate(0, 0, -1)
+	}
+
+	if !resetTime.After(last) || !session.IsLoggedOn() {
+		return
+	}
+
+	session.log.OnEvent("Reset time reached, sending logon with ResetSeqNumFlag=Y")
+	if err := session.sendLogonInReplyTo(true, nil); err != nil {
+		session.logError(err)
 	}
 }
 
@@ -176,7 +218,23 @@ func (sm *stateMachine) setState(session *session, nextState sessionState) {
 		}
 	}
 
+	prevState := sm.State
 	sm.State = nextState
+
+	from, to := session.externalState(prevState), session.externalState(nextState)
+	if from != to {
+		switch {
+		case !from.IsConnected() && to.IsConnected():
+			session.publishEvent(EventConnect, "Connected")
+		case from.IsConnected() && !to.IsConnected():
+			session.publishEvent(EventDisconnect, "Disconnected")
+		}
+
+		session.healthStatus.stateChanged(to, session.clock.Now())
+		if session.stateListener != nil {
+			session.stateListener.OnStateChange(session.sessionID, from, to)
+		}
+	}
 }
 
 func (sm *stateMachine) notifyInSessionTime() {
@@ -200,6 +258,7 @@ func (sm *stateMachine) handleDisconnectState(s *session) {
 
 	if doOnLogout {
 		s.application.OnLogout(s.sessionID)
+		s.publishEvent(EventLogout, "Logged out")
 	}
 
 	s.onDisconnect()
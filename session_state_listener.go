@@ -0,0 +1,104 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+// SessionState is the externally visible state of a session's state machine.
+type SessionState int
+
+const (
+	// SessionStateLatent is a session in session time that is not connected.
+	SessionStateLatent SessionState = iota
+
+	// SessionStateNotSessionTime is a session outside of its configured session time.
+	SessionStateNotSessionTime
+
+	// SessionStateAwaitingLogon is an acceptor session that is connected and waiting for a Logon.
+	SessionStateAwaitingLogon
+
+	// SessionStateLogonSent is an initiator session that has sent a Logon and is waiting for the response.
+	SessionStateLogonSent
+
+	// SessionStateLoggedOn is a logged on session.
+	SessionStateLoggedOn
+
+	// SessionStateResendPending is a logged on session waiting for a ResendRequest to be satisfied.
+	SessionStateResendPending
+
+	// SessionStateTestRequestPending is a logged on session that has sent a TestRequest after the
+	// counterparty went quiet.
+	SessionStateTestRequestPending
+
+	// SessionStateLogoutPending is a session that has sent a Logout and is waiting for the response.
+	SessionStateLogoutPending
+)
+
+func (s SessionState) String() string {
+	switch s {
+	case SessionStateLatent:
+		return "Latent"
+	case SessionStateNotSessionTime:
+		return "NotSessionTime"
+	case SessionStateAwaitingLogon:
+		return "AwaitingLogon"
+	case SessionStateLogonSent:
+		return "LogonSent"
+	case SessionStateLoggedOn:
+		return "LoggedOn"
+	case SessionStateResendPending:
+		return "ResendPending"
+	case SessionStateTestRequestPending:
+		return "TestRequestPending"
+	case SessionStateLogoutPending:
+		return "LogoutPending"
+	}
+
+	return "Unknown"
+}
+
+// IsConnected returns true if a session in this state has a connection to the counterparty.
+func (s SessionState) IsConnected() bool {
+	return s != SessionStateLatent && s != SessionStateNotSessionTime
+}
+
+// SessionStateListener is notified of session state machine transitions.
+// A disconnect is reported as a transition from a connected state to SessionStateLatent or
+// SessionStateNotSessionTime.
+type SessionStateListener interface {
+	// OnStateChange is called from the session's goroutine and should not block.
+	OnStateChange(sessionID SessionID, from, to SessionState)
+}
+
+func (s *session) externalState(state sessionState) SessionState {
+	switch state.(type) {
+	case notSessionTime:
+		return SessionStateNotSessionTime
+	case logonState:
+		if s.InitiateLogon {
+			return SessionStateLogonSent
+		}
+		return SessionStateAwaitingLogon
+	case inSession:
+		return SessionStateLoggedOn
+	case resendState:
+		return SessionStateResendPending
+	case pendingTimeout:
+		return SessionStateTestRequestPending
+	case logoutState:
+		return SessionStateLogoutPending
+	}
+
+	return SessionStateLatent
+}
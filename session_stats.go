@@ -0,0 +1,124 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SessionStats are connectivity statistics of a session.
+type SessionStats struct {
+	// TestRequestsSent is the number of TestRequests sent after the counterparty went quiet.
+	TestRequestsSent int
+
+	// TestRequestsAnswered is the number of TestRequests answered by a Heartbeat with the matching TestReqID(112).
+	TestRequestsAnswered int
+
+	// LastTestRequestLatency is the round trip time from the last answered TestRequest to its Heartbeat.
+	LastTestRequestLatency time.Duration
+
+	// LastTestRequestAnswered is when the last answered TestRequest was answered.
+	LastTestRequestAnswered time.Time
+
+	// InboundLatency is the latency from reading application messages from the connection to FromApp returning.
+	InboundLatency LatencyStats
+
+	// OutboundLatency is the latency from calling ToApp to writing application messages to the connection.
+	OutboundLatency LatencyStats
+}
+
+type sessionStats struct {
+	mu    sync.Mutex
+	stats SessionStats
+
+	// pendingTestReqID is the TestReqID of the last TestRequest sent, if not yet answered.
+	pendingTestReqID  string
+	testRequestSentAt time.Time
+
+	inboundLatency  latencyHistogram
+	outboundLatency latencyHistogram
+}
+
+func (s *sessionStats) snapshot() SessionStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	stats.InboundLatency = s.inboundLatency.stats()
+	stats.OutboundLatency = s.outboundLatency.stats()
+	return stats
+}
+
+func (s *sessionStats) testRequestSent(sentAt time.Time) (testReqID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.TestRequestsSent++
+	s.pendingTestReqID = "TEST" + strconv.Itoa(s.stats.TestRequestsSent)
+	s.testRequestSentAt = sentAt
+
+	return s.pendingTestReqID
+}
+
+func (s *sessionStats) heartbeatReceived(testReqID string, receivedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pendingTestReqID == "" || testReqID != s.pendingTestReqID {
+		return
+	}
+
+	s.pendingTestReqID = ""
+	s.stats.TestRequestsAnswered++
+	s.stats.LastTestRequestLatency = receivedAt.Sub(s.testRequestSentAt)
+	s.stats.LastTestRequestAnswered = receivedAt
+}
+
+// peerTimeout is how long the session waits for a message from the counterparty before sending a TestRequest,
+// and after sending the TestRequest before timing out.
+func (s *session) peerTimeout() time.Duration {
+	return time.Duration((1 + s.TestRequestDelayMultiplier) * float64(s.HeartBtInt))
+}
+
+func (s *session) sendTestRequest() error {
+	testReqID := s.stats.testRequestSent(s.clock.Now())
+
+	testReq := NewMessage()
+	testReq.Header.SetField(tagMsgType, FIXString("1"))
+	testReq.Body.SetField(tagTestReqID, FIXString(testReqID))
+	if err := s.send(testReq); err != nil {
+		return err
+	}
+	s.log.OnEventf("Sent test request %v", testReqID)
+
+	return nil
+}
+
+func (s *session) handleHeartbeat(msg *Message) {
+	receivedAt := msg.ReceiveTime
+	if receivedAt.IsZero() {
+		receivedAt = s.clock.Now()
+	}
+	s.healthStatus.heartbeatReceived(receivedAt)
+
+	var testReqID FIXString
+	if err := msg.Body.GetField(tagTestReqID, &testReqID); err != nil {
+		return
+	}
+	s.stats.heartbeatReceived(string(testReqID), receivedAt)
+}
@@ -787,6 +787,105 @@ func (s *SessionSuite) TestOnAdminStop() {
 	s.Stopped()
 }
 
+type stateTransition struct {
+	from, to SessionState
+}
+
+type stateListenerFunc func(sessionID SessionID, from, to SessionState)
+
+func (f stateListenerFunc) OnStateChange(sessionID SessionID, from, to SessionState) {
+	f(sessionID, from, to)
+}
+
+func (s *SessionSuite) TestSessionStateListener() {
+	var transitions []stateTransition
+	s.session.stateListener = stateListenerFunc(func(sessionID SessionID, from, to SessionState) {
+		s.Equal(s.session.sessionID, sessionID)
+		transitions = append(transitions, stateTransition{from, to})
+	})
+
+	s.session.State = latentState{}
+	s.session.InitiateLogon = true
+	s.MockApp.On("ToAdmin")
+	s.session.onAdmin(connect{messageOut: s.Receiver.sendChannel})
+	s.State(logonState{})
+
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("OnLogon")
+	s.fixMsgIn(s.session, s.Logon())
+	s.State(inSession{})
+
+	// Staying in session is not a transition.
+	s.session.Timeout(s.session, internal.NeedHeartbeat)
+	s.session.Timeout(s.session, internal.PeerTimeout)
+
+	s.MockApp.On("OnLogout")
+	s.session.Disconnected(s.session)
+
+	s.Equal([]stateTransition{
+		{SessionStateLatent, SessionStateLogonSent},
+		{SessionStateLogonSent, SessionStateLoggedOn},
+		{SessionStateLoggedOn, SessionStateTestRequestPending},
+		{SessionStateTestRequestPending, SessionStateLatent},
+	}, transitions)
+	s.False(SessionStateLatent.IsConnected())
+	s.True(SessionStateLoggedOn.IsConnected())
+}
+
+func (s *SessionSuite) TestReset() {
+	s.IncrNextSenderMsgSeqNum()
+	s.IncrNextTargetMsgSeqNum()
+	s.session.State = inSession{}
+
+	s.MockApp.On("ToAdmin")
+	s.Nil(s.session.reset())
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.ExpectStoreReset()
+}
+
+func (s *SessionSuite) TestSetNextSeqNums() {
+	s.session.State = inSession{}
+
+	s.MockApp.On("ToAdmin")
+	s.Nil(s.session.setNextSeqNums(10, 20, true))
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeSequenceReset), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagNewSeqNo, 10, s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagGapFillFlag, false, s.MockApp.lastToAdmin.Body)
+	s.NextSenderMsgSeqNum(10)
+	s.NextTargetMsgSeqNum(20)
+
+	s.Nil(s.session.setNextSeqNums(5, 6, false))
+	s.NoMessageSent()
+	s.NextSenderMsgSeqNum(5)
+	s.NextTargetMsgSeqNum(6)
+
+	s.NotNil(s.session.setNextSeqNums(0, 6, false))
+	s.NextSenderMsgSeqNum(5)
+}
+
+func (s *SessionSuite) TestDoAdminRunLoopStops() {
+	s.session.setRunning(true, internal.GoroutineID())
+
+	// A request not received by the run loop before it stops is processed directly.
+	rep := make(chan error, 1)
+	go func() { rep <- s.session.setNextSeqNums(5, 6, false) }()
+	time.Sleep(10 * time.Millisecond)
+	s.session.setRunning(false, 0)
+
+	select {
+	case err := <-rep:
+		s.Nil(err)
+	case <-time.After(time.Second):
+		s.Fail("timed out waiting for request")
+	}
+	s.NextSenderMsgSeqNum(5)
+	s.NextTargetMsgSeqNum(6)
+}
+
 func (s *SessionSuite) TestResetOnDisconnect() {
 	s.IncrNextSenderMsgSeqNum()
 	s.IncrNextTargetMsgSeqNum()
@@ -1009,6 +1108,7 @@ func (s *SessionSuite) TestSeqNumResetTime() {
 	now := time.Now().UTC()
 	s.session.ResetSeqTime = internal.NewTimeOfDay(now.Clock())
 	s.session.EnableResetSeqTime = true
+	s.session.State = inSession{}
 
 	s.IncrNextSenderMsgSeqNum()
 	s.IncrNextTargetMsgSeqNum()
@@ -1026,3 +1126,43 @@ func (s *SessionSuite) TestSeqNumResetTime() {
 	s.NextSenderMsgSeqNum(2)
 
 }
+
+func (s *SessionSuite) TestSeqNumResetTimeNotLoggedOn() {
+	s.MockApp.On("ToAdmin")
+	now := time.Now().UTC()
+	s.session.ResetSeqTime = internal.NewTimeOfDay(now.Clock())
+	s.session.EnableResetSeqTime = true
+
+	s.IncrNextSenderMsgSeqNum()
+	s.session.CheckResetTime(s.session, now)
+
+	s.NoMessageSent()
+	s.NextSenderMsgSeqNum(2)
+}
+
+func (s *SessionSuite) TestSeqNumResetTimeMissedTick() {
+	s.MockApp.On("ToAdmin")
+	loc, err := time.LoadLocation("America/New_York")
+	s.Require().Nil(err)
+	s.session.ResetSeqTime = internal.NewTimeOfDay(17, 0, 0)
+	s.session.ResetSeqTimeLocation = loc
+	s.session.EnableResetSeqTime = true
+	s.session.State = inSession{}
+
+	s.IncrNextSenderMsgSeqNum()
+	s.IncrNextTargetMsgSeqNum()
+
+	s.session.CheckResetTime(s.session, time.Date(2024, time.March, 4, 16, 59, 59, 0, loc))
+	s.NoMessageSent()
+
+	// The tick at 17:00:00 New York time was skipped.
+	s.session.CheckResetTime(s.session, time.Date(2024, time.March, 4, 22, 0, 1, 0, time.UTC))
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogon), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagResetSeqNumFlag, true, s.MockApp.lastToAdmin.Body)
+	s.NextSenderMsgSeqNum(2)
+
+	// Only once per day.
+	s.session.CheckResetTime(s.session, time.Date(2024, time.March, 4, 17, 0, 2, 0, loc))
+	s.NoMessageSent()
+}
@@ -177,6 +177,10 @@ func (s *Settings) AddSession(sessionSettings *SessionSettings) (SessionID, erro
 	case BeginStringFIX43:
 	case BeginStringFIX44:
 	case BeginStringFIXT11:
+	case templateWildcard:
+		if !isSessionTemplate(sessionSettings) {
+			return sessionID, errors.New("BeginString must be FIX.4.0 to FIX.4.4 or FIXT.1.1")
+		}
 	default:
 		return sessionID, errors.New("BeginString must be FIX.4.0 to FIX.4.4 or FIXT.1.1")
 	}
@@ -189,3 +193,28 @@ func (s *Settings) AddSession(sessionSettings *SessionSettings) (SessionID, erro
 
 	return sessionID, nil
 }
+
+// clone returns a copy of s, whose session settings can be added or removed without changing s.
+func (s *Settings) clone() *Settings {
+	s.lazyInit()
+
+	sClone := &Settings{
+		globalSettings:  s.globalSettings.clone(),
+		sessionSettings: make(map[SessionID]*SessionSettings, len(s.sessionSettings)),
+	}
+	for sessionID, settings := range s.sessionSettings {
+		sClone.sessionSettings[sessionID] = settings.clone()
+	}
+
+	return sClone
+}
+
+// removeSession removes the session settings of sessionID, returning false if there are none.
+func (s *Settings) removeSession(sessionID SessionID) bool {
+	if _, ok := s.sessionSettings[sessionID]; !ok {
+		return false
+	}
+
+	delete(s.sessionSettings, sessionID)
+	return true
+}
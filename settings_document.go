@@ -0,0 +1,483 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SettingsDocumentError indicates an invalid YAML or JSON settings document, at Line and Column of the document.
+type SettingsDocumentError struct {
+	Line, Column int
+	Err          error
+}
+
+func (e SettingsDocumentError) Error() string {
+	return fmt.Sprintf("line %v, column %v: %v", e.Line, e.Column, e.Err)
+}
+
+func (e SettingsDocumentError) Unwrap() error {
+	return e.Err
+}
+
+// documentPosition is the position of a value in a settings document.
+type documentPosition struct {
+	line, column int
+}
+
+func (p documentPosition) errorf(format string, args ...interface{}) error {
+	return SettingsDocumentError{Line: p.line, Column: p.column, Err: fmt.Errorf(format, args...)}
+}
+
+type documentSetting struct {
+	name, value string
+	pos         documentPosition
+}
+
+type documentSection struct {
+	pos      documentPosition
+	settings []documentSetting
+}
+
+// add adds the setting to the section, returning an error if the section already has it.
+func (s *documentSection) add(setting documentSetting) error {
+	for _, other := range s.settings {
+		if other.name == setting.name {
+			return setting.pos.errorf("duplicate setting %v", setting.name)
+		}
+	}
+
+	s.settings = append(s.settings, setting)
+	return nil
+}
+
+// settingsDocument is a YAML or JSON settings document, with the default section and the session sections of the
+// sections of a cfg file.
+type settingsDocument struct {
+	defaults *documentSection
+	sessions []*documentSection
+}
+
+const (
+	documentDefaultKey  = "default"
+	documentSessionsKey = "sessions"
+
+	// documentExtensionPrefix prefixes the keys of a settings document that are ignored, e.g. to hold YAML anchors.
+	documentExtensionPrefix = "x-"
+)
+
+func (d *settingsDocument) settings() (*Settings, error) {
+	s := NewSettings()
+	if d.defaults != nil {
+		for _, setting := range d.defaults.settings {
+			s.GlobalSettings().Set(setting.name, setting.value)
+		}
+	}
+
+	if len(d.sessions) == 0 {
+		return s, fmt.Errorf("no sessions declared")
+	}
+
+	for _, section := range d.sessions {
+		settings := NewSessionSettings()
+		for _, setting := range section.settings {
+			settings.Set(setting.name, setting.value)
+		}
+
+		if _, err := s.AddSession(settings); err != nil {
+			return s, section.pos.errorf("%v", err)
+		}
+	}
+
+	return s, nil
+}
+
+// ParseYAMLSettings creates and initializes a Settings instance with config parsed from a YAML document. The document
+// has the semantics of a cfg file: the settings of the default mapping are inherited by the settings of each mapping
+// of the sessions sequence.
+//
+//	default:
+//	  ConnectionType: initiator
+//	  HeartBtInt: 30
+//	sessions:
+//	  - BeginString: FIX.4.4
+//	    SenderCompID: SENDER
+//	    TargetCompID: TARGET
+//
+// Setting values are scalars, true and false are the Y and N of boolean settings. Sections may merge the settings of
+// anchored mappings with the << merge key, top level keys prefixed with x- are ignored to hold them. Returns a SettingsDocumentError if the document is not a valid settings
+// document.
+func ParseYAMLSettings(reader io.Reader) (*Settings, error) {
+	var root yaml.Node
+	if err := yaml.NewDecoder(reader).Decode(&root); err != nil {
+		if errors.Is(err, io.EOF) {
+			return NewSettings(), fmt.Errorf("no sessions declared")
+		}
+		return NewSettings(), err
+	}
+
+	doc, err := parseYAMLDocument(&root)
+	if err != nil {
+		return NewSettings(), err
+	}
+
+	return doc.settings()
+}
+
+func yamlPosition(node *yaml.Node) documentPosition {
+	return documentPosition{line: node.Line, column: node.Column}
+}
+
+func resolveYAMLAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+func parseYAMLDocument(root *yaml.Node) (*settingsDocument, error) {
+	node := root
+	if node.Kind == yaml.DocumentNode {
+		node = node.Content[0]
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil, yamlPosition(node).errorf("expected a mapping of %v and %v", documentDefaultKey, documentSessionsKey)
+	}
+
+	doc := new(settingsDocument)
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], resolveYAMLAlias(node.Content[i+1])
+
+		switch {
+		case strings.HasPrefix(key.Value, documentExtensionPrefix):
+			continue
+
+		case strings.EqualFold(key.Value, documentDefaultKey):
+			if doc.defaults != nil {
+				return nil, yamlPosition(key).errorf("duplicate %v", documentDefaultKey)
+			}
+
+			var err error
+			if doc.defaults, err = parseYAMLSection(value); err != nil {
+				return nil, err
+			}
+
+		case strings.EqualFold(key.Value, documentSessionsKey):
+			if doc.sessions != nil {
+				return nil, yamlPosition(key).errorf("duplicate %v", documentSessionsKey)
+			}
+
+			if value.Kind != yaml.SequenceNode {
+				return nil, yamlPosition(value).errorf("expected a sequence of sessions")
+			}
+
+			doc.sessions = make([]*documentSection, 0, len(value.Content))
+			for _, sessionNode := range value.Content {
+				session, err := parseYAMLSection(resolveYAMLAlias(sessionNode))
+				if err != nil {
+					return nil, err
+				}
+				doc.sessions = append(doc.sessions, session)
+			}
+
+		default:
+			return nil, yamlPosition(key).errorf("unexpected key %v, expected %v or %v", key.Value, documentDefaultKey, documentSessionsKey)
+		}
+	}
+
+	return doc, nil
+}
+
+func parseYAMLSection(node *yaml.Node) (*documentSection, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, yamlPosition(node).errorf("expected a mapping of settings")
+	}
+
+	section := &documentSection{pos: yamlPosition(node)}
+	var merged []documentSetting
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], resolveYAMLAlias(node.Content[i+1])
+
+		if key.Tag == "!!merge" {
+			settings, err := parseYAMLMerge(value)
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, settings...)
+			continue
+		}
+
+		if value.Kind != yaml.ScalarNode || value.Tag == "!!null" {
+			return nil, yamlPosition(value).errorf("setting %v must be a string, number or boolean", key.Value)
+		}
+
+		setting := documentSetting{name: key.Value, value: value.Value, pos: yamlPosition(key)}
+		if value.Tag == "!!bool" {
+			var b bool
+			if err := value.Decode(&b); err != nil {
+				return nil, yamlPosition(value).errorf("%v", err)
+			}
+			setting.value = string(FIXBoolean(b).Write())
+		}
+
+		if err := section.add(setting); err != nil {
+			return nil, err
+		}
+	}
+
+	// Settings of the section override the merged settings.
+	for _, setting := range merged {
+		overridden := false
+		for _, other := range section.settings {
+			if other.name == setting.name {
+				overridden = true
+				break
+			}
+		}
+
+		if !overridden {
+			section.settings = append(section.settings, setting)
+		}
+	}
+
+	return section, nil
+}
+
+// parseYAMLMerge returns the settings of the mapping or sequence of mappings of a merge key, the settings of earlier
+// mappings overriding the later ones.
+func parseYAMLMerge(node *yaml.Node) ([]documentSetting, error) {
+	nodes := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		nodes = node.Content
+	}
+
+	var settings []documentSetting
+	seen := make(map[string]bool)
+	for _, n := range nodes {
+		section, err := parseYAMLSection(resolveYAMLAlias(n))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, setting := range section.settings {
+			if !seen[setting.name] {
+				seen[setting.name] = true
+				settings = append(settings, setting)
+			}
+		}
+	}
+
+	return settings, nil
+}
+
+// ParseJSONSettings creates and initializes a Settings instance with config parsed from a JSON document, an object
+// with the default and sessions keys of the documents of ParseYAMLSettings.
+//
+//	{
+//	  "default": {"ConnectionType": "initiator", "HeartBtInt": 30},
+//	  "sessions": [{"BeginString": "FIX.4.4", "SenderCompID": "SENDER", "TargetCompID": "TARGET"}]
+//	}
+//
+// Returns a SettingsDocumentError if the document is not a valid settings document.
+func ParseJSONSettings(reader io.Reader) (*Settings, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return NewSettings(), err
+	}
+
+	p := jsonDocumentParser{data: data, dec: json.NewDecoder(bytes.NewReader(data))}
+	p.dec.UseNumber()
+
+	doc, err := p.parse()
+	if err != nil {
+		return NewSettings(), err
+	}
+
+	return doc.settings()
+}
+
+// jsonDocumentParser parses the tokens of a JSON settings document, keeping the document to locate them.
+type jsonDocumentParser struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+// position returns the position of offset in the document.
+func (p *jsonDocumentParser) position(offset int64) documentPosition {
+	if offset > int64(len(p.data)) {
+		offset = int64(len(p.data))
+	}
+
+	before := p.data[:offset]
+	pos := documentPosition{line: bytes.Count(before, []byte("\n")) + 1}
+	pos.column = len(before) - bytes.LastIndexByte(before, '\n')
+	return pos
+}
+
+// nextPosition returns the position of the next token.
+func (p *jsonDocumentParser) nextPosition() documentPosition {
+	offset := p.dec.InputOffset()
+	for offset < int64(len(p.data)) && strings.IndexByte(" \t\r\n,:", p.data[offset]) >= 0 {
+		offset++
+	}
+
+	return p.position(offset)
+}
+
+// next returns the next token and its position.
+func (p *jsonDocumentParser) next() (json.Token, documentPosition, error) {
+	pos := p.nextPosition()
+
+	tok, err := p.dec.Token()
+	if err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, pos, p.position(syntaxErr.Offset-1).errorf("%v", err)
+		}
+		if errors.Is(err, io.EOF) {
+			return nil, pos, pos.errorf("unexpected end of document")
+		}
+		return nil, pos, pos.errorf("%v", err)
+	}
+
+	return tok, pos, nil
+}
+
+func (p *jsonDocumentParser) expectDelim(delim json.Delim, format string, args ...interface{}) (documentPosition, error) {
+	tok, pos, err := p.next()
+	if err != nil {
+		return pos, err
+	}
+
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return pos, pos.errorf(format, args...)
+	}
+
+	return pos, nil
+}
+
+func (p *jsonDocumentParser) parse() (*settingsDocument, error) {
+	if _, err := p.expectDelim('{', "expected an object of %v and %v", documentDefaultKey, documentSessionsKey); err != nil {
+		return nil, err
+	}
+
+	doc := new(settingsDocument)
+	for p.dec.More() {
+		tok, pos, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+
+		switch {
+		case strings.EqualFold(key, documentDefaultKey):
+			if doc.defaults != nil {
+				return nil, pos.errorf("duplicate %v", documentDefaultKey)
+			}
+
+			if doc.defaults, err = p.parseSection(); err != nil {
+				return nil, err
+			}
+
+		case strings.EqualFold(key, documentSessionsKey):
+			if doc.sessions != nil {
+				return nil, pos.errorf("duplicate %v", documentSessionsKey)
+			}
+
+			if _, err := p.expectDelim('[', "expected an array of sessions"); err != nil {
+				return nil, err
+			}
+
+			doc.sessions = []*documentSection{}
+			for p.dec.More() {
+				session, err := p.parseSection()
+				if err != nil {
+					return nil, err
+				}
+				doc.sessions = append(doc.sessions, session)
+			}
+
+			if _, _, err := p.next(); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, pos.errorf("unexpected key %v, expected %v or %v", key, documentDefaultKey, documentSessionsKey)
+		}
+	}
+
+	if _, _, err := p.next(); err != nil {
+		return nil, err
+	}
+
+	pos := p.nextPosition()
+	if _, err := p.dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, pos.errorf("unexpected data after the document")
+	}
+
+	return doc, nil
+}
+
+func (p *jsonDocumentParser) parseSection() (*documentSection, error) {
+	pos, err := p.expectDelim('{', "expected an object of settings")
+	if err != nil {
+		return nil, err
+	}
+
+	section := &documentSection{pos: pos}
+	for p.dec.More() {
+		tok, keyPos, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		name := tok.(string)
+
+		tok, valuePos, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+
+		setting := documentSetting{name: name, pos: keyPos}
+		switch v := tok.(type) {
+		case string:
+			setting.value = v
+		case json.Number:
+			setting.value = v.String()
+		case bool:
+			setting.value = string(FIXBoolean(v).Write())
+		default:
+			return nil, valuePos.errorf("setting %v must be a string, number or boolean", name)
+		}
+
+		if err := section.add(setting); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, _, err := p.next(); err != nil {
+		return nil, err
+	}
+
+	return section, nil
+}
@@ -0,0 +1,168 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const yamlSettings = `
+default:
+  ConnectionType: initiator
+  ReconnectInterval: 60
+  SenderCompID: TW
+  ResetOnLogon: true
+
+x-common: &common
+  HeartBtInt: 30
+  StartTime: "12:30:00"
+  EndTime: "23:30:00"
+
+sessions:
+  - BeginString: FIX.4.1
+    TargetCompID: ARCA
+    HeartBtInt: 20
+    StartTime: "12:30:00"
+    EndTime: "23:30:00"
+  - <<: *common
+    BeginString: FIX.4.2
+    TargetCompID: INCA
+    ReconnectInterval: 30
+    ResetOnLogon: false
+`
+
+const jsonSettings = `{
+  "default": {"ConnectionType": "initiator", "ReconnectInterval": 60, "SenderCompID": "TW", "ResetOnLogon": true},
+  "sessions": [
+    {"BeginString": "FIX.4.1", "TargetCompID": "ARCA", "HeartBtInt": 20, "StartTime": "12:30:00", "EndTime": "23:30:00"},
+    {"BeginString": "FIX.4.2", "TargetCompID": "INCA", "HeartBtInt": 30, "StartTime": "12:30:00", "EndTime": "23:30:00",
+     "ReconnectInterval": 30, "ResetOnLogon": false}
+  ]
+}`
+
+func TestParseSettingsDocument(t *testing.T) {
+	s, err := ParseYAMLSettings(strings.NewReader(yamlSettings))
+	require.Nil(t, err)
+	assertDocumentSettings(t, s)
+
+	s, err = ParseJSONSettings(strings.NewReader(jsonSettings))
+	require.Nil(t, err)
+	assertDocumentSettings(t, s)
+}
+
+func assertDocumentSettings(t *testing.T, s *Settings) {
+	var globalTCs = []struct {
+		setting  string
+		expected string
+	}{
+		{"ConnectionType", "initiator"},
+		{"ReconnectInterval", "60"},
+		{"SenderCompID", "TW"},
+		{"ResetOnLogon", "Y"},
+	}
+
+	for _, tc := range globalTCs {
+		actual, err := s.GlobalSettings().Setting(tc.setting)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expected, actual)
+	}
+
+	sessionSettings := s.SessionSettings()
+	require.Len(t, sessionSettings, 2)
+
+	var sessionTCs = []struct {
+		sessionID SessionID
+		setting   string
+		expected  string
+	}{
+		{SessionID{BeginString: "FIX.4.1", SenderCompID: "TW", TargetCompID: "ARCA"}, "HeartBtInt", "20"},
+		{SessionID{BeginString: "FIX.4.1", SenderCompID: "TW", TargetCompID: "ARCA"}, "ReconnectInterval", "60"},
+		{SessionID{BeginString: "FIX.4.1", SenderCompID: "TW", TargetCompID: "ARCA"}, "ResetOnLogon", "Y"},
+		{SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "INCA"}, "HeartBtInt", "30"},
+		{SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "INCA"}, "StartTime", "12:30:00"},
+		{SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "INCA"}, "ReconnectInterval", "30"},
+		{SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "INCA"}, "ResetOnLogon", "N"},
+		{SessionID{BeginString: "FIX.4.2", SenderCompID: "TW", TargetCompID: "INCA"}, "ConnectionType", "initiator"},
+	}
+
+	for _, tc := range sessionTCs {
+		settings, ok := sessionSettings[tc.sessionID]
+		require.True(t, ok, tc.sessionID.String())
+
+		actual, err := settings.Setting(tc.setting)
+		assert.Nil(t, err, tc.setting)
+		assert.Equal(t, tc.expected, actual, tc.setting)
+	}
+}
+
+func TestParseSettingsDocumentErrors(t *testing.T) {
+	var tests = []struct {
+		name           string
+		yaml           bool
+		doc            string
+		expectedLine   int
+		expectedColumn int
+	}{
+		{"yaml not a mapping", true, "- a\n- b\n", 1, 1},
+		{"yaml unknown key", true, "default:\n  A: 1\nsession:\n  - B: 2\n", 3, 1},
+		{"yaml sessions not a sequence", true, "sessions:\n  BeginString: FIX.4.2\n", 2, 3},
+		{"yaml setting not a scalar", true, "sessions:\n  - BeginString: FIX.4.2\n    HeartBtInt: [30]\n", 3, 17},
+		{"yaml null setting", true, "sessions:\n  - BeginString: FIX.4.2\n    HeartBtInt:\n", 3, 16},
+		{"yaml duplicate setting", true, "sessions:\n  - BeginString: FIX.4.2\n    BeginString: FIX.4.4\n", 3, 5},
+		{"yaml invalid session", true, "sessions:\n  - BeginString: FIX.4.2\n    TargetCompID: A\n  - BeginString: FIX.9\n", 4, 5},
+		{"yaml duplicate session", true, "sessions:\n  - BeginString: FIX.4.2\n  - BeginString: FIX.4.2\n", 3, 5},
+		{"json not an object", false, "[]", 1, 1},
+		{"json unknown key", false, "{\n  \"session\": []\n}", 2, 3},
+		{"json sessions not an array", false, "{\"sessions\": {}}", 1, 14},
+		{"json setting not a scalar", false, "{\"sessions\": [\n  {\"BeginString\": \"FIX.4.2\",\n   \"HeartBtInt\": null}\n]}", 3, 18},
+		{"json duplicate setting", false, "{\"sessions\": [{\"BeginString\": \"FIX.4.2\", \"BeginString\": \"FIX.4.4\"}]}", 1, 42},
+		{"json invalid session", false, "{\"sessions\": [\n  {\"BeginString\": \"FIX.9\"}\n]}", 2, 3},
+		{"json syntax error", false, "{\"sessions\": [\n  {\"BeginString\" \"FIX.4.2\"}\n]}", 2, 18},
+		{"json trailing data", false, "{\"sessions\": [{\"BeginString\": \"FIX.4.2\"}]} {}", 1, 44},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var err error
+			if test.yaml {
+				_, err = ParseYAMLSettings(strings.NewReader(test.doc))
+			} else {
+				_, err = ParseJSONSettings(strings.NewReader(test.doc))
+			}
+
+			var docErr SettingsDocumentError
+			require.True(t, errors.As(err, &docErr), "%v", err)
+			assert.Equal(t, test.expectedLine, docErr.Line, docErr.Error())
+			assert.Equal(t, test.expectedColumn, docErr.Column, docErr.Error())
+		})
+	}
+}
+
+func TestParseSettingsDocumentNoSessions(t *testing.T) {
+	_, err := ParseYAMLSettings(strings.NewReader(""))
+	assert.EqualError(t, err, "no sessions declared")
+
+	_, err = ParseYAMLSettings(strings.NewReader("default:\n  SenderCompID: TW\n"))
+	assert.EqualError(t, err, "no sessions declared")
+
+	_, err = ParseJSONSettings(strings.NewReader(`{"default": {"SenderCompID": "TW"}, "sessions": []}`))
+	assert.EqualError(t, err, "no sessions declared")
+}
@@ -0,0 +1,339 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// sessionSettingsDiff is the difference between the sessions of two Settings.
+type sessionSettingsDiff struct {
+	added, removed, changed, unchanged []SessionID
+}
+
+func diffSessionSettings(oldSettings, newSettings map[SessionID]*SessionSettings) (diff sessionSettingsDiff) {
+	for sessionID, settings := range newSettings {
+		old, ok := oldSettings[sessionID]
+		switch {
+		case !ok:
+			diff.added = append(diff.added, sessionID)
+		case !old.equal(settings):
+			diff.changed = append(diff.changed, sessionID)
+		default:
+			diff.unchanged = append(diff.unchanged, sessionID)
+		}
+	}
+
+	for sessionID := range oldSettings {
+		if _, ok := newSettings[sessionID]; !ok {
+			diff.removed = append(diff.removed, sessionID)
+		}
+	}
+
+	for _, ids := range [][]SessionID{diff.added, diff.removed, diff.changed, diff.unchanged} {
+		sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	}
+
+	return
+}
+
+// stopSessions stops sessions concurrently with stop, returning the errors of the sessions that failed to stop.
+func stopSessions(sessions []*session, stop func(*session) error) []error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(sessions))
+	for i, s := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := stop(s); err != nil {
+				errs[i] = fmt.Errorf("session %v: %w", s.sessionID, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// seqNums are the next sequence numbers of a MessageStore.
+type seqNums struct {
+	sender, target int
+}
+
+// closeStores closes the stores of the sessions stopped by a reload, returning their sequence numbers, to be kept by
+// the sessions created again in their place, and the errors of the stores that failed to close.
+func closeStores(stopped []*session) (map[SessionID]seqNums, []error) {
+	kept := make(map[SessionID]seqNums, len(stopped))
+	var errs []error
+	for _, s := range stopped {
+		kept[s.sessionID] = seqNums{sender: s.store.NextSenderMsgSeqNum(), target: s.store.NextTargetMsgSeqNum()}
+		if err := s.store.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("session %v: %w", s.sessionID, err))
+		}
+	}
+
+	return kept, errs
+}
+
+// keepSeqNums sets the sequence numbers of the store of s, created again by a reload, to those of its previous
+// store, for stores that do not persist them such as the memory store.
+func (s *session) keepSeqNums(kept seqNums) error {
+	if s.store.NextSenderMsgSeqNum() != kept.sender {
+		if err := s.store.SetNextSenderMsgSeqNum(kept.sender); err != nil {
+			return err
+		}
+	}
+
+	if s.store.NextTargetMsgSeqNum() != kept.target {
+		return s.store.SetNextTargetMsgSeqNum(kept.target)
+	}
+
+	return nil
+}
+
+// ReloadSettings applies settings to the Acceptor without restarting the sessions whose settings are unchanged.
+// Sessions that are no longer configured are logged out and removed, new sessions are created and accepted, and
+// sessions whose settings changed are logged out and created again with their new settings, keeping the sequence
+// numbers of their MessageStore. The MessageStores of the sessions stopped are closed. Listeners are opened for new addresses, opened again if their settings, such as
+// their TLS settings, change, and closed once no session is accepted on them. Session templates are replaced, the
+// sessions already created from them are not stopped.
+//
+// Nothing is applied if the sessions accepted on an address have different listener settings. The Acceptor settings
+// of the default section, such as DynamicSessions, are not reloaded. Errors creating or stopping sessions, or opening
+// listeners, are returned together once the other changes are applied.
+func (a *Acceptor) ReloadSettings(settings *Settings) error {
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+
+	return a.applySettings(settings, false)
+}
+
+// applySettings applies settings to the Acceptor, draining the sessions it stops if drain is true. It must be called
+// with reloadLock held.
+func (a *Acceptor) applySettings(settings *Settings, drain bool) error {
+	a.sessionsLock.RLock()
+	oldAllSettings := a.settings.SessionSettings()
+	a.sessionsLock.RUnlock()
+
+	// Templates are not sessions, but sessions created from them are accepted on their listeners.
+	allSettings := settings.SessionSettings()
+	templates, newSettings, err := splitSessionTemplates(allSettings)
+	if err != nil {
+		return err
+	}
+	_, oldSettings, _ := splitSessionTemplates(oldAllSettings)
+	diff := diffSessionSettings(oldSettings, newSettings)
+
+	sessionListener, listenerSettings, err := acceptorListeners(allSettings)
+	if err != nil {
+		return err
+	}
+	_, oldListenerSettings, _ := acceptorListeners(oldAllSettings)
+
+	// Sessions accepted on a listener share its settings, so all of them are changed if its settings change.
+	reopen := make(map[string]bool)
+	for address, old := range oldListenerSettings {
+		if listenerSetting, ok := listenerSettings[address]; ok {
+			if _, same := conflictingListenerSetting(old, listenerSetting); !same {
+				reopen[address] = true
+			}
+		}
+	}
+
+	sessIDs := make(map[SessionID]bool, len(newSettings))
+	for sessionID := range newSettings {
+		sessID := sessionID
+		sessID.Qualifier = ""
+		if sessIDs[sessID] {
+			return errDuplicateSessionID
+		}
+		sessIDs[sessID] = true
+	}
+
+	created := append(append([]SessionID{}, diff.added...), diff.changed...)
+	allowedAddresses := make(map[SessionID][]*net.IPNet)
+	for _, sessionID := range created {
+		if newSettings[sessionID].HasSetting(config.AllowedRemoteAddresses) {
+			sessID := sessionID
+			sessID.Qualifier = ""
+			if allowedAddresses[sessID], err = parseAddressList(newSettings[sessionID], config.AllowedRemoteAddresses); err != nil {
+				return err
+			}
+		}
+	}
+
+	a.sessionsLock.Lock()
+	running := a.running.Load()
+	var stopped []*session
+	for _, sessionID := range append(append([]SessionID{}, diff.removed...), diff.changed...) {
+		sessID := sessionID
+		sessID.Qualifier = ""
+		if s, ok := a.sessions[sessID]; ok {
+			stopped = append(stopped, s)
+			delete(a.sessions, sessID)
+		}
+		delete(a.allowedAddresses, sessID)
+	}
+
+	var closed []net.Listener
+	for address, listener := range a.listeners {
+		if _, ok := listenerSettings[address]; !ok || reopen[address] {
+			closed = append(closed, listener)
+			delete(a.listeners, address)
+		}
+	}
+	a.sessionListener = sessionListener
+	a.templates = templates
+	a.settings = settings
+	a.sessionsLock.Unlock()
+
+	errs := stopSessions(stopped, func(s *session) error { return a.stopSession(s, drain) })
+	kept, closeErrs := closeStores(stopped)
+	errs = append(errs, closeErrs...)
+	for _, listener := range closed {
+		listener.Close()
+	}
+
+	a.sessionsLock.Lock()
+	defer a.sessionsLock.Unlock()
+
+	if running {
+		for address, listenerSetting := range listenerSettings {
+			if _, ok := a.listeners[address]; ok {
+				continue
+			}
+
+			listener, err := a.listen(address, listenerSetting)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			a.listeners[address] = listener
+			a.listenerShutdown.Add(1)
+			go a.listenForConnections(address, listener)
+		}
+	}
+
+	for _, sessionID := range created {
+		s, err := a.createSession(sessionID, a.storeFactory, newSettings[sessionID], a.logFactory, a.app)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("session %v: %w", sessionID, err))
+			continue
+		}
+
+		if seqNums, ok := kept[sessionID]; ok {
+			if err := s.keepSeqNums(seqNums); err != nil {
+				errs = append(errs, fmt.Errorf("session %v: %w", sessionID, err))
+			}
+		}
+
+		sessID := sessionID
+		sessID.Qualifier = ""
+		a.sessions[sessID] = s
+		if allowed, ok := allowedAddresses[sessID]; ok {
+			a.allowedAddresses[sessID] = allowed
+		}
+
+		if running {
+			a.configureSession(s)
+			a.runSession(s)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ReloadSettings applies settings to the Initiator without restarting the sessions whose settings are unchanged.
+// Sessions that are no longer configured are logged out and removed, new sessions are created and connected, and
+// sessions whose settings changed are logged out and created again with their new settings, keeping the sequence
+// numbers of their MessageStore. The MessageStores of the sessions stopped are closed.
+//
+// Nothing is applied if the connection settings of a new or changed session, such as its TLS or proxy settings, are
+// invalid. Errors creating or stopping sessions are returned together once the other changes are applied.
+func (i *Initiator) ReloadSettings(settings *Settings) error {
+	i.reloadLock.Lock()
+	defer i.reloadLock.Unlock()
+
+	return i.applySettings(settings, false)
+}
+
+// applySettings applies settings to the Initiator, draining the sessions it stops if drain is true. It must be
+// called with reloadLock held.
+func (i *Initiator) applySettings(settings *Settings, drain bool) error {
+	newSettings := settings.SessionSettings()
+
+	i.sessionsLock.RLock()
+	diff := diffSessionSettings(i.sessionSettings, newSettings)
+	i.sessionsLock.RUnlock()
+
+	created := append(append([]SessionID{}, diff.added...), diff.changed...)
+	for _, sessionID := range created {
+		if _, _, _, err := loadConnectionSettings(newSettings[sessionID]); err != nil {
+			return fmt.Errorf("session %v: %w", sessionID, err)
+		}
+	}
+
+	i.sessionsLock.Lock()
+	running := i.running.Load()
+	var stopped []*session
+	for _, sessionID := range append(append([]SessionID{}, diff.removed...), diff.changed...) {
+		if s, ok := i.sessions[sessionID]; ok {
+			stopped = append(stopped, s)
+			delete(i.sessions, sessionID)
+		}
+		delete(i.sessionSettings, sessionID)
+	}
+	i.settings = settings
+	i.sessionsLock.Unlock()
+
+	errs := stopSessions(stopped, func(s *session) error { return i.stopSession(s, drain) })
+	kept, closeErrs := closeStores(stopped)
+	errs = append(errs, closeErrs...)
+
+	i.sessionsLock.Lock()
+	defer i.sessionsLock.Unlock()
+
+	for _, sessionID := range created {
+		s, err := i.createSession(sessionID, i.storeFactory, newSettings[sessionID], i.logFactory, i.app)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("session %v: %w", sessionID, err))
+			continue
+		}
+
+		if seqNums, ok := kept[sessionID]; ok {
+			if err := s.keepSeqNums(seqNums); err != nil {
+				errs = append(errs, fmt.Errorf("session %v: %w", sessionID, err))
+			}
+		}
+
+		i.sessions[sessionID] = s
+		i.sessionSettings[sessionID] = newSettings[sessionID]
+		if running {
+			if err := i.startSession(s, newSettings[sessionID]); err != nil {
+				errs = append(errs, fmt.Errorf("session %v: %w", sessionID, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,263 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+type reloadApp struct {
+	logonApp
+	loggedOut chan SessionID
+}
+
+func newReloadApp() reloadApp {
+	return reloadApp{logonApp: logonApp{loggedOn: make(chan SessionID, 10)}, loggedOut: make(chan SessionID, 10)}
+}
+
+func (a reloadApp) OnLogout(sessionID SessionID) { a.loggedOut <- sessionID }
+
+func waitForSession(t *testing.T, c chan SessionID, expected SessionID) {
+	select {
+	case sessionID := <-c:
+		assert.Equal(t, expected, sessionID)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %v", expected)
+	}
+}
+
+func assertNoSession(t *testing.T, c chan SessionID) {
+	select {
+	case sessionID := <-c:
+		t.Fatalf("unexpected %v", sessionID)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func reloadSessionSettings(senderCompID, targetCompID string, settings map[string]string) *SessionSettings {
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, senderCompID)
+	sessionSettings.Set(config.TargetCompID, targetCompID)
+	for setting, value := range settings {
+		sessionSettings.Set(setting, value)
+	}
+
+	return sessionSettings
+}
+
+func reloadSettings(t *testing.T, global map[string]string, sessions ...*SessionSettings) *Settings {
+	settings := NewSettings()
+	for setting, value := range global {
+		settings.GlobalSettings().Set(setting, value)
+	}
+
+	for _, sessionSettings := range sessions {
+		_, err := settings.AddSession(sessionSettings)
+		require.NoError(t, err)
+	}
+
+	return settings
+}
+
+func TestDiffSessionSettings(t *testing.T) {
+	a := SessionID{BeginString: BeginStringFIX42, SenderCompID: "S", TargetCompID: "A"}
+	b := SessionID{BeginString: BeginStringFIX42, SenderCompID: "S", TargetCompID: "B"}
+	c := SessionID{BeginString: BeginStringFIX42, SenderCompID: "S", TargetCompID: "C"}
+	d := SessionID{BeginString: BeginStringFIX42, SenderCompID: "S", TargetCompID: "D"}
+
+	oldSettings := map[SessionID]*SessionSettings{
+		a: reloadSessionSettings("S", "A", nil),
+		b: reloadSessionSettings("S", "B", map[string]string{config.HeartBtInt: "30"}),
+		c: reloadSessionSettings("S", "C", nil),
+	}
+	newSettings := map[SessionID]*SessionSettings{
+		a: reloadSessionSettings("S", "A", nil),
+		b: reloadSessionSettings("S", "B", map[string]string{config.HeartBtInt: "20"}),
+		d: reloadSessionSettings("S", "D", nil),
+	}
+
+	diff := diffSessionSettings(oldSettings, newSettings)
+	assert.Equal(t, []SessionID{d}, diff.added)
+	assert.Equal(t, []SessionID{c}, diff.removed)
+	assert.Equal(t, []SessionID{b}, diff.changed)
+	assert.Equal(t, []SessionID{a}, diff.unchanged)
+}
+
+func TestAcceptor_ReloadSettings(t *testing.T) {
+	host := "pipe://reload_acceptor"
+	global := map[string]string{config.SocketAcceptHost: host}
+	sessionA := reloadSessionSettings("ACCEPTOR", "A", nil)
+
+	acceptorApp := newReloadApp()
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(), reloadSettings(t, global, sessionA), NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	connect := map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5011", config.HeartBtInt: "30", config.ReconnectInterval: "1"}
+	initiatorApp := newReloadApp()
+	initiator, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, nil, reloadSessionSettings("A", "ACCEPTOR", connect), reloadSessionSettings("B", "ACCEPTOR", connect)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	acceptorA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "A"}
+	acceptorB := SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "B"}
+	waitForSession(t, acceptorApp.loggedOn, acceptorA)
+
+	// B is accepted once it is added, without logging out A.
+	sessionB := reloadSessionSettings("ACCEPTOR", "B", nil)
+	require.NoError(t, acceptor.ReloadSettings(reloadSettings(t, global, sessionA, sessionB)))
+	waitForSession(t, acceptorApp.loggedOn, acceptorB)
+	assertNoSession(t, acceptorApp.loggedOut)
+
+	// Changing the settings of B logs it out and accepts it again.
+	sessionB = reloadSessionSettings("ACCEPTOR", "B", map[string]string{config.ResetOnLogon: "Y"})
+	require.NoError(t, acceptor.ReloadSettings(reloadSettings(t, global, sessionA, sessionB)))
+	waitForSession(t, acceptorApp.loggedOut, acceptorB)
+	waitForSession(t, acceptorApp.loggedOn, acceptorB)
+
+	// Removing B logs it out and unregisters it.
+	require.NoError(t, acceptor.ReloadSettings(reloadSettings(t, global, sessionA)))
+	waitForSession(t, acceptorApp.loggedOut, acceptorB)
+	assert.ErrorIs(t, ResetSession(acceptorB), errUnknownSession)
+	assertNoSession(t, acceptorApp.loggedOut)
+
+	health := acceptor.Health()
+	require.Len(t, health.Sessions, 1)
+	assert.Equal(t, acceptorA, health.Sessions[0].SessionID)
+	assert.True(t, health.Sessions[0].LoggedOn())
+}
+
+func TestAcceptor_ReloadSettingsListeners(t *testing.T) {
+	global := map[string]string{config.SocketAcceptPort: "5012"}
+	sessionA := reloadSessionSettings("ACCEPTOR", "A", nil)
+
+	acceptor, err := NewAcceptor(&MockApp{}, NewMemoryStoreFactory(), reloadSettings(t, global, sessionA), NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	// Sessions accepted on the same address must agree on the settings of its listener.
+	sessionB := reloadSessionSettings("ACCEPTOR", "B", map[string]string{config.SocketNoDelay: "N"})
+	err = acceptor.ReloadSettings(reloadSettings(t, global, sessionA, sessionB))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), config.SocketNoDelay)
+	assert.Len(t, acceptor.Health().Sessions, 1)
+
+	// Changing the settings of the listener opens it again.
+	listener := acceptor.listeners[":5012"]
+	require.NoError(t, acceptor.ReloadSettings(reloadSettings(t, map[string]string{config.SocketAcceptPort: "5012", config.SocketNoDelay: "N"}, sessionA)))
+	assert.NotSame(t, listener, acceptor.listeners[":5012"])
+	require.NoError(t, acceptor.ReloadSettings(reloadSettings(t, global, sessionA)))
+
+	// B on a new port opens a listener, which is closed once B is removed.
+	sessionB = reloadSessionSettings("ACCEPTOR", "B", map[string]string{config.SocketAcceptPort: "5013"})
+	require.NoError(t, acceptor.ReloadSettings(reloadSettings(t, global, sessionA, sessionB)))
+	assert.Len(t, acceptor.listeners, 2)
+	assert.Equal(t, ":5013", acceptor.sessionListener[SessionID{BeginString: BeginStringFIX42, SenderCompID: "ACCEPTOR", TargetCompID: "B"}])
+
+	require.NoError(t, acceptor.ReloadSettings(reloadSettings(t, global, sessionA)))
+	assert.Len(t, acceptor.listeners, 1)
+	_, ok := acceptor.listeners[":5012"]
+	assert.True(t, ok)
+}
+
+func TestInitiator_ReloadSettings(t *testing.T) {
+	host := "pipe://reload_initiator"
+	acceptorApp := newReloadApp()
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketAcceptHost: host},
+			reloadSessionSettings("ACCEPTOR", "A", nil), reloadSessionSettings("ACCEPTOR", "B", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	connect := map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5014", config.HeartBtInt: "30"}
+	sessionA := reloadSessionSettings("A", "ACCEPTOR", connect)
+	initiatorApp := newReloadApp()
+	initiator, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(), reloadSettings(t, nil, sessionA), NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	initiatorA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "A", TargetCompID: "ACCEPTOR"}
+	initiatorB := SessionID{BeginString: BeginStringFIX42, SenderCompID: "B", TargetCompID: "ACCEPTOR"}
+	waitForSession(t, initiatorApp.loggedOn, initiatorA)
+
+	// Invalid connection settings are not applied.
+	invalid := reloadSessionSettings("B", "ACCEPTOR", map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5014", config.HeartBtInt: "30", config.ProxyType: "unknown"})
+	require.Error(t, initiator.ReloadSettings(reloadSettings(t, nil, sessionA, invalid)))
+	assert.Len(t, initiator.Health().Sessions, 1)
+
+	require.NoError(t, initiator.ReloadSettings(reloadSettings(t, nil, sessionA, reloadSessionSettings("B", "ACCEPTOR", connect))))
+	waitForSession(t, initiatorApp.loggedOn, initiatorB)
+	assertNoSession(t, initiatorApp.loggedOut)
+
+	require.NoError(t, initiator.ReloadSettings(reloadSettings(t, nil, reloadSessionSettings("B", "ACCEPTOR", connect))))
+	waitForSession(t, initiatorApp.loggedOut, initiatorA)
+	assert.ErrorIs(t, ResetSession(initiatorA), errUnknownSession)
+	assert.Len(t, initiator.Health().Sessions, 1)
+}
+
+func TestInitiator_ReloadSettingsStores(t *testing.T) {
+	host := "pipe://reload_stores"
+	acceptorApp := newReloadApp()
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(),
+		reloadSettings(t, map[string]string{config.SocketAcceptHost: host}, reloadSessionSettings("ACCEPTOR", "A", nil)),
+		NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	connect := map[string]string{config.SocketConnectHost: host, config.SocketConnectPort: "5025", config.HeartBtInt: "30"}
+	storeFactory := closingStoreFactory{created: make(chan *closingStore, 10)}
+	initiatorApp := newReloadApp()
+	initiator, err := NewInitiator(initiatorApp, storeFactory, reloadSettings(t, nil, reloadSessionSettings("A", "ACCEPTOR", connect)), NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	initiatorA := SessionID{BeginString: BeginStringFIX42, SenderCompID: "A", TargetCompID: "ACCEPTOR"}
+	first := <-storeFactory.created
+	waitForSession(t, initiatorApp.loggedOn, initiatorA)
+
+	// The changed session is created again with a new store, keeping the sequence numbers of the closed one, which
+	// the acceptor expects.
+	connect[config.HeartBtInt] = "20"
+	require.NoError(t, initiator.ReloadSettings(reloadSettings(t, nil, reloadSessionSettings("A", "ACCEPTOR", connect))))
+	waitForSession(t, initiatorApp.loggedOut, initiatorA)
+	assert.True(t, first.closed)
+
+	second := <-storeFactory.created
+	waitForSession(t, initiatorApp.loggedOn, initiatorA)
+	assert.Eventually(t, func() bool { return second.NextSenderMsgSeqNum() == 4 }, time.Second, 10*time.Millisecond)
+
+	// Removed sessions are closed.
+	require.NoError(t, initiator.ReloadSettings(NewSettings()))
+	waitForSession(t, initiatorApp.loggedOut, initiatorA)
+	assert.True(t, second.closed)
+}
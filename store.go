@@ -16,6 +16,8 @@
 package quickfix
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -34,7 +36,13 @@ type MessageStore interface {
 	SetCreationTime(time.Time)
 
 	SaveMessage(seqNum int, msg []byte) error
+
+	// SaveMessageAndIncrNextSenderMsgSeqNum saves an outgoing message and increments the next sender MsgSeqNum.
+	// Sessions persist every outgoing message through it, so implementations should make both changes
+	// atomically where the backend allows it. If a crash can leave the message saved without the increment,
+	// the store must recover the seqnum on load so that it is never reused for a different message.
 	SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error
+
 	GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error)
 	IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error
 
@@ -44,6 +52,54 @@ type MessageStore interface {
 	Close() error
 }
 
+// QueuedMessage is an application message of the outbound queue of an OutboundQueueStore.
+type QueuedMessage struct {
+	// QueuedAt is when the message was sent by the application.
+	QueuedAt time.Time
+
+	// Msg is the message, without a MsgSeqNum.
+	Msg []byte
+}
+
+// OutboundQueueStore is a MessageStore that also persists the outbound queue of its session, the application
+// messages sent while the session is not logged on, required by PersistOutboundQueue. Reset does not clear the queue.
+//
+// The session clears the queue before it saves the queued messages in the MessageStore, so that a message is never
+// sent twice, and a message may be lost if the process stops in between.
+type OutboundQueueStore interface {
+	MessageStore
+
+	// QueueMessage appends msg to the outbound queue.
+	QueueMessage(msg QueuedMessage) error
+
+	// QueuedMessages returns the outbound queue, oldest first.
+	QueuedMessages() ([]QueuedMessage, error)
+
+	// ClearQueuedMessages empties the outbound queue.
+	ClearQueuedMessages() error
+}
+
+// Compactor is implemented by MessageStores that can reclaim the space of the messages they no longer return,
+// such as those created by file.NewStoreFactory. Acceptor.Compact and Initiator.Compact compact the stores
+// of their sessions.
+type Compactor interface {
+	// Compact rewrites the saved messages without those superseded by a later save of the same seqnum.
+	Compact() error
+}
+
+// compactSessions compacts the stores of the sessions that implement Compactor.
+func compactSessions(sessions []*session) error {
+	var errs []error
+	for _, s := range sessions {
+		if compactor, ok := s.store.(Compactor); ok {
+			if err := compactor.Compact(); err != nil {
+				errs = append(errs, fmt.Errorf("%v: %w", s.sessionID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // The MessageStoreFactory interface is used by session to create a session specific message store.
 type MessageStoreFactory interface {
 	Create(sessionID SessionID) (MessageStore, error)
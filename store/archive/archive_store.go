@@ -0,0 +1,344 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const (
+	tagMsgSeqNum   quickfix.Tag = 34
+	tagSendingTime quickfix.Tag = 52
+)
+
+// Format is the layout of an archived object.
+type Format int
+
+const (
+	// FIXLines writes one raw FIX message per line.
+	FIXLines Format = iota
+	// JSONLines writes one JSON object per line holding the sequence number and raw FIX message.
+	JSONLines
+)
+
+// ObjectStore is the destination of archived messages, e.g. an S3, GCS or Azure Blob Storage bucket.
+type ObjectStore interface {
+	// PutObject stores body under key, replacing any existing object.
+	PutObject(ctx context.Context, key string, body []byte) error
+}
+
+// Pruner is implemented by message stores that can delete saved messages.
+// Stores that do not implement it keep their messages after they are archived.
+type Pruner interface {
+	// PruneMessages deletes the saved messages with a sequence number below beforeSeqNum.
+	PruneMessages(beforeSeqNum int) error
+}
+
+// Options configures archival.
+type Options struct {
+	// Format is the layout of archived objects.
+	Format Format
+
+	// KeyPrefix is prepended to the key of every archived object.
+	KeyPrefix string
+
+	// MaxAge is the age, based on SendingTime, past which saved messages are archived and pruned.
+	MaxAge time.Duration
+
+	// Interval is how often messages older than MaxAge are archived. Zero disables periodic
+	// archival; Archive can still be called on the store directly.
+	Interval time.Duration
+
+	// ArchiveOnReset archives all saved messages before the store is reset.
+	ArchiveOnReset bool
+
+	// OnError is called with errors from periodic archival. Optional.
+	OnError func(error)
+}
+
+type archiveStoreFactory struct {
+	inner   quickfix.MessageStoreFactory
+	objects ObjectStore
+	opts    Options
+}
+
+// NewStoreFactory returns a MessageStoreFactory whose stores export saved messages from the stores created
+// by inner to objects, then prune them from the inner store.
+func NewStoreFactory(inner quickfix.MessageStoreFactory, objects ObjectStore, opts Options) quickfix.MessageStoreFactory {
+	return archiveStoreFactory{inner: inner, objects: objects, opts: opts}
+}
+
+// Create creates a new archiving MessageStore wrapping a store created by the inner factory.
+func (f archiveStoreFactory) Create(sessionID quickfix.SessionID) (quickfix.MessageStore, error) {
+	inner, err := f.inner.Create(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &archiveStore{
+		inner:     inner,
+		sessionID: sessionID,
+		objects:   f.objects,
+		opts:      f.opts,
+		now:       time.Now,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if f.opts.Interval > 0 {
+		go store.run()
+	} else {
+		close(store.done)
+	}
+	return store, nil
+}
+
+// archiveStore serializes access to the inner store so archival can run alongside the session.
+type archiveStore struct {
+	mu        sync.Mutex
+	inner     quickfix.MessageStore
+	sessionID quickfix.SessionID
+	objects   ObjectStore
+	opts      Options
+	now       func() time.Time
+
+	// archived is the highest sequence number archived from a store that cannot prune.
+	archived int
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (store *archiveStore) run() {
+	defer close(store.done)
+	ticker := time.NewTicker(store.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := store.Archive(); err != nil && store.opts.OnError != nil {
+				store.opts.OnError(err)
+			}
+		case <-store.stop:
+			return
+		}
+	}
+}
+
+// Archive exports saved messages older than MaxAge and prunes them from the inner store.
+func (store *archiveStore) Archive() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	cutoff := store.now().Add(-store.opts.MaxAge)
+	return store.archiveLocked(func(sendingTime time.Time) bool {
+		return sendingTime.Before(cutoff)
+	})
+}
+
+// archiveLocked exports the oldest saved messages for as long as include accepts their SendingTime.
+func (store *archiveStore) archiveLocked(include func(sendingTime time.Time) bool) error {
+	var buf bytes.Buffer
+	first, last := 0, 0
+	errStop := errors.New("stop")
+
+	err := store.inner.IterateMessages(store.archived+1, store.inner.NextSenderMsgSeqNum()-1, func(raw []byte) error {
+		msg := quickfix.NewMessage()
+		if err := quickfix.ParseMessage(msg, bytes.NewBuffer(raw)); err != nil {
+			return errors.Wrap(err, "parse message")
+		}
+		sendingTime, err := msg.Header.GetTime(tagSendingTime)
+		if err != nil {
+			return errors.Wrap(err, "sending time")
+		}
+		if !include(sendingTime) {
+			return errStop
+		}
+		seqNum, err := msg.Header.GetInt(tagMsgSeqNum)
+		if err != nil {
+			return errors.Wrap(err, "msg seq num")
+		}
+
+		if first == 0 {
+			first = seqNum
+		}
+		last = seqNum
+		return store.encode(&buf, seqNum, raw)
+	})
+	if err != nil && err != errStop {
+		return err
+	}
+	if first == 0 {
+		return nil
+	}
+
+	key := store.objectKey(first, last)
+	if err = store.objects.PutObject(context.Background(), key, buf.Bytes()); err != nil {
+		return errors.Wrapf(err, "put %s", key)
+	}
+
+	if pruner, ok := store.inner.(Pruner); ok {
+		return errors.Wrap(pruner.PruneMessages(last+1), "prune")
+	}
+	store.archived = last
+	return nil
+}
+
+func (store *archiveStore) encode(buf *bytes.Buffer, seqNum int, raw []byte) error {
+	switch store.opts.Format {
+	case JSONLines:
+		line, err := json.Marshal(struct {
+			MsgSeqNum int    `json:"msgseqnum"`
+			Message   string `json:"message"`
+		}{seqNum, string(raw)})
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+	default:
+		buf.Write(raw)
+	}
+	buf.WriteByte('\n')
+	return nil
+}
+
+func (store *archiveStore) objectKey(first, last int) string {
+	ext := "fix"
+	if store.opts.Format == JSONLines {
+		ext = "jsonl"
+	}
+	session := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, store.sessionID.String())
+	return fmt.Sprintf("%s%s/%s-%d-%d.%s", store.opts.KeyPrefix, session, store.now().UTC().Format("20060102T150405Z"), first, last, ext)
+}
+
+// Reset archives all saved messages if ArchiveOnReset is set, then resets the inner store.
+func (store *archiveStore) Reset() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.opts.ArchiveOnReset {
+		if err := store.archiveLocked(func(time.Time) bool { return true }); err != nil {
+			return errors.Wrap(err, "archive")
+		}
+	}
+	store.archived = 0
+	return store.inner.Reset()
+}
+
+func (store *archiveStore) NextSenderMsgSeqNum() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.NextSenderMsgSeqNum()
+}
+
+func (store *archiveStore) NextTargetMsgSeqNum() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.NextTargetMsgSeqNum()
+}
+
+func (store *archiveStore) IncrNextSenderMsgSeqNum() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.IncrNextSenderMsgSeqNum()
+}
+
+func (store *archiveStore) IncrNextTargetMsgSeqNum() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.IncrNextTargetMsgSeqNum()
+}
+
+func (store *archiveStore) SetNextSenderMsgSeqNum(next int) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.SetNextSenderMsgSeqNum(next)
+}
+
+func (store *archiveStore) SetNextTargetMsgSeqNum(next int) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.SetNextTargetMsgSeqNum(next)
+}
+
+func (store *archiveStore) CreationTime() time.Time {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.CreationTime()
+}
+
+func (store *archiveStore) SetCreationTime(t time.Time) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.inner.SetCreationTime(t)
+}
+
+func (store *archiveStore) SaveMessage(seqNum int, msg []byte) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.SaveMessage(seqNum, msg)
+}
+
+func (store *archiveStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, msg)
+}
+
+func (store *archiveStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.GetMessages(beginSeqNum, endSeqNum)
+}
+
+func (store *archiveStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.IterateMessages(beginSeqNum, endSeqNum, cb)
+}
+
+func (store *archiveStore) Refresh() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.Refresh()
+}
+
+// Close stops periodic archival and closes the inner store.
+func (store *archiveStore) Close() error {
+	store.closeOnce.Do(func() { close(store.stop) })
+	<-store.done
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inner.Close()
+}
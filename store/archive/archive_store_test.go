@@ -0,0 +1,149 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal/testsuite"
+)
+
+type fakeObjectStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeObjectStore) PutObject(_ context.Context, key string, body []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.objects == nil {
+		f.objects = make(map[string][]byte)
+	}
+	f.objects[key] = append([]byte(nil), body...)
+	return nil
+}
+
+func (f *fakeObjectStore) only(t *testing.T) (string, []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	require.Len(t, f.objects, 1)
+	for key, body := range f.objects {
+		return key, body
+	}
+	return "", nil
+}
+
+// ArchiveStoreTestSuite runs all tests in the message.StoreTestSuite against the archiving store.
+type ArchiveStoreTestSuite struct {
+	testsuite.StoreTestSuite
+}
+
+func (suite *ArchiveStoreTestSuite) SetupTest() {
+	var err error
+	factory := NewStoreFactory(quickfix.NewMemoryStoreFactory(), &fakeObjectStore{}, Options{MaxAge: time.Hour, Interval: time.Millisecond})
+	suite.MsgStore, err = factory.Create(quickfix.SessionID{})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *ArchiveStoreTestSuite) TearDownTest() {
+	if suite.MsgStore != nil {
+		suite.MsgStore.Close()
+	}
+}
+
+func TestArchiveStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(ArchiveStoreTestSuite))
+}
+
+func newArchiveStore(t *testing.T, objects ObjectStore, opts Options) *archiveStore {
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	store, err := NewStoreFactory(quickfix.NewMemoryStoreFactory(), objects, opts).Create(sessionID)
+	require.Nil(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store.(*archiveStore)
+}
+
+func saveMessage(t *testing.T, store quickfix.MessageStore, sendingTime time.Time) []byte {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(8), "FIX.4.4")
+	msg.Header.SetString(quickfix.Tag(35), "0")
+	msg.Header.SetInt(tagMsgSeqNum, store.NextSenderMsgSeqNum())
+	msg.Header.SetString(tagSendingTime, sendingTime.UTC().Format("20060102-15:04:05.000"))
+	raw := []byte(msg.String())
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(store.NextSenderMsgSeqNum(), raw))
+	return raw
+}
+
+func TestArchiveOldMessages(t *testing.T) {
+	objects := &fakeObjectStore{}
+	store := newArchiveStore(t, objects, Options{KeyPrefix: "fix/", MaxAge: time.Hour})
+	now := time.Now()
+
+	old1 := saveMessage(t, store, now.Add(-3*time.Hour))
+	old2 := saveMessage(t, store, now.Add(-2*time.Hour))
+	recent := saveMessage(t, store, now)
+
+	require.Nil(t, store.Archive())
+
+	key, body := objects.only(t)
+	require.True(t, strings.HasPrefix(key, "fix/FIX.4.4_SENDER-_TARGET/"), key)
+	require.True(t, strings.HasSuffix(key, "-1-2.fix"), key)
+	require.Equal(t, string(old1)+"\n"+string(old2)+"\n", string(body))
+
+	// Archived messages are pruned from the inner store.
+	msgs, err := store.GetMessages(1, 3)
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{recent}, msgs)
+	require.Equal(t, 4, store.NextSenderMsgSeqNum())
+}
+
+func TestArchiveJSONLinesOnReset(t *testing.T) {
+	objects := &fakeObjectStore{}
+	store := newArchiveStore(t, objects, Options{Format: JSONLines, MaxAge: time.Hour, ArchiveOnReset: true})
+
+	raw := saveMessage(t, store, time.Now())
+	require.Nil(t, store.Reset())
+
+	key, body := objects.only(t)
+	require.True(t, strings.HasSuffix(key, "-1-1.jsonl"), key)
+
+	var row struct {
+		MsgSeqNum int    `json:"msgseqnum"`
+		Message   string `json:"message"`
+	}
+	require.Nil(t, json.Unmarshal(body, &row))
+	require.Equal(t, 1, row.MsgSeqNum)
+	require.Equal(t, string(raw), row.Message)
+	require.Equal(t, 1, store.NextSenderMsgSeqNum())
+}
+
+func TestArchiveNothingToArchive(t *testing.T) {
+	objects := &fakeObjectStore{}
+	store := newArchiveStore(t, objects, Options{MaxAge: time.Hour})
+
+	saveMessage(t, store, time.Now())
+	require.Nil(t, store.Archive())
+	require.Empty(t, objects.objects)
+}
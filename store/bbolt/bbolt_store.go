@@ -0,0 +1,370 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package bbolt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+var (
+	sessionBucket  = []byte("session")
+	messagesBucket = []byte("messages")
+
+	keyCreationTime   = []byte("creation_time")
+	keyIncomingSeqNum = []byte("incoming_seq_num")
+	keyOutgoingSeqNum = []byte("outgoing_seq_num")
+)
+
+type boltStoreFactory struct {
+	settings *quickfix.Settings
+}
+
+type boltStore struct {
+	sessionID quickfix.SessionID
+	cache     quickfix.MessageStore
+	fname     string
+	noSync    bool
+	db        *bolt.DB
+}
+
+// NewStoreFactory returns a bbolt-based implementation of MessageStoreFactory.
+func NewStoreFactory(settings *quickfix.Settings) quickfix.MessageStoreFactory {
+	return boltStoreFactory{settings: settings}
+}
+
+// Create creates a new BoltStore implementation of the MessageStore interface.
+func (f boltStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfix.MessageStore, err error) {
+	globalSettings := f.settings.GlobalSettings()
+	dynamicSessions, _ := globalSettings.BoolSetting(config.DynamicSessions)
+
+	sessionSettings, ok := f.settings.SessionSettings()[sessionID]
+	if !ok {
+		if dynamicSessions {
+			sessionSettings = globalSettings
+		} else {
+			return nil, fmt.Errorf("unknown session: %v", sessionID)
+		}
+	}
+
+	dirname, err := sessionSettings.Setting(config.BoltStorePath)
+	if err != nil {
+		return nil, err
+	}
+	fsync := true
+	if sessionSettings.HasSetting(config.BoltStoreSync) {
+		if fsync, err = sessionSettings.BoolSetting(config.BoltStoreSync); err != nil {
+			return nil, err
+		}
+	}
+	return newBoltStore(sessionID, dirname, fsync)
+}
+
+func newBoltStore(sessionID quickfix.SessionID, dirname string, fileSync bool) (*boltStore, error) {
+	if err := os.MkdirAll(dirname, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	memStore, memErr := quickfix.NewMemoryStoreFactory().Create(sessionID)
+	if memErr != nil {
+		return nil, errors.Wrap(memErr, "cache creation")
+	}
+
+	store := &boltStore{
+		sessionID: sessionID,
+		cache:     memStore,
+		fname:     path.Join(dirname, createFilenamePrefix(sessionID)+".db"),
+		noSync:    !fileSync,
+	}
+
+	var err error
+	if store.db, err = bolt.Open(store.fname, 0660, &bolt.Options{Timeout: time.Second, NoSync: store.noSync}); err != nil {
+		return nil, errors.Wrapf(err, "open %v", store.fname)
+	}
+	if err = store.Refresh(); err != nil {
+		_ = store.db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func createFilenamePrefix(s quickfix.SessionID) string {
+	sender := []string{s.SenderCompID}
+	if s.SenderSubID != "" {
+		sender = append(sender, s.SenderSubID)
+	}
+	if s.SenderLocationID != "" {
+		sender = append(sender, s.SenderLocationID)
+	}
+
+	target := []string{s.TargetCompID}
+	if s.TargetSubID != "" {
+		target = append(target, s.TargetSubID)
+	}
+	if s.TargetLocationID != "" {
+		target = append(target, s.TargetLocationID)
+	}
+
+	fname := []string{s.BeginString, strings.Join(sender, "_"), strings.Join(target, "_")}
+	if s.Qualifier != "" {
+		fname = append(fname, s.Qualifier)
+	}
+	return strings.Join(fname, "-")
+}
+
+func seqNumKey(seqNum int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(seqNum))
+	return key
+}
+
+func putSeqNum(b *bolt.Bucket, key []byte, seqNum int) error {
+	return b.Put(key, seqNumKey(seqNum))
+}
+
+func getSeqNum(b *bolt.Bucket, key []byte) (int, bool) {
+	value := b.Get(key)
+	if len(value) != 8 {
+		return 0, false
+	}
+	return int(binary.BigEndian.Uint64(value)), true
+}
+
+// Reset deletes the store records and sets the seqnums back to 1.
+func (store *boltStore) Reset() error {
+	if err := store.cache.Reset(); err != nil {
+		return errors.Wrap(err, "cache reset")
+	}
+
+	return store.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(messagesBucket); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		if _, err := tx.CreateBucket(messagesBucket); err != nil {
+			return err
+		}
+		return store.writeSession(tx)
+	})
+}
+
+// writeSession writes the cached creation time and seqnums to the session bucket.
+func (store *boltStore) writeSession(tx *bolt.Tx) error {
+	b := tx.Bucket(sessionBucket)
+	creationTime, err := store.cache.CreationTime().MarshalText()
+	if err != nil {
+		return err
+	}
+	if err = b.Put(keyCreationTime, creationTime); err != nil {
+		return err
+	}
+	if err = putSeqNum(b, keyIncomingSeqNum, store.cache.NextTargetMsgSeqNum()); err != nil {
+		return err
+	}
+	return putSeqNum(b, keyOutgoingSeqNum, store.cache.NextSenderMsgSeqNum())
+}
+
+// Refresh reloads the store from the database file.
+func (store *boltStore) Refresh() error {
+	if err := store.cache.Reset(); err != nil {
+		return errors.Wrap(err, "cache reset")
+	}
+
+	return store.db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(messagesBucket); err != nil {
+			return err
+		}
+		b, err := tx.CreateBucketIfNotExists(sessionBucket)
+		if err != nil {
+			return err
+		}
+
+		// session record not found, create it
+		creationTime := b.Get(keyCreationTime)
+		if creationTime == nil {
+			return store.writeSession(tx)
+		}
+
+		// session record found, load it
+		var ctime time.Time
+		if err = ctime.UnmarshalText(creationTime); err != nil {
+			return errors.Wrap(err, "creation time")
+		}
+		store.cache.SetCreationTime(ctime)
+		if seqNum, ok := getSeqNum(b, keyIncomingSeqNum); ok {
+			if err = store.cache.SetNextTargetMsgSeqNum(seqNum); err != nil {
+				return errors.Wrap(err, "cache set next target")
+			}
+		}
+		if seqNum, ok := getSeqNum(b, keyOutgoingSeqNum); ok {
+			if err = store.cache.SetNextSenderMsgSeqNum(seqNum); err != nil {
+				return errors.Wrap(err, "cache set next sender")
+			}
+		}
+		return nil
+	})
+}
+
+// NextSenderMsgSeqNum returns the next MsgSeqNum that will be sent.
+func (store *boltStore) NextSenderMsgSeqNum() int {
+	return store.cache.NextSenderMsgSeqNum()
+}
+
+// NextTargetMsgSeqNum returns the next MsgSeqNum that should be received.
+func (store *boltStore) NextTargetMsgSeqNum() int {
+	return store.cache.NextTargetMsgSeqNum()
+}
+
+// SetNextSenderMsgSeqNum sets the next MsgSeqNum that will be sent.
+func (store *boltStore) SetNextSenderMsgSeqNum(next int) error {
+	err := store.db.Update(func(tx *bolt.Tx) error {
+		return putSeqNum(tx.Bucket(sessionBucket), keyOutgoingSeqNum, next)
+	})
+	if err != nil {
+		return err
+	}
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+// SetNextTargetMsgSeqNum sets the next MsgSeqNum that should be received.
+func (store *boltStore) SetNextTargetMsgSeqNum(next int) error {
+	err := store.db.Update(func(tx *bolt.Tx) error {
+		return putSeqNum(tx.Bucket(sessionBucket), keyIncomingSeqNum, next)
+	})
+	if err != nil {
+		return err
+	}
+	return store.cache.SetNextTargetMsgSeqNum(next)
+}
+
+// IncrNextSenderMsgSeqNum increments the next MsgSeqNum that will be sent.
+func (store *boltStore) IncrNextSenderMsgSeqNum() error {
+	if err := store.SetNextSenderMsgSeqNum(store.cache.NextSenderMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "store next")
+	}
+	return nil
+}
+
+// IncrNextTargetMsgSeqNum increments the next MsgSeqNum that should be received.
+func (store *boltStore) IncrNextTargetMsgSeqNum() error {
+	if err := store.SetNextTargetMsgSeqNum(store.cache.NextTargetMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "store next")
+	}
+	return nil
+}
+
+// CreationTime returns the creation time of the store.
+func (store *boltStore) CreationTime() time.Time {
+	return store.cache.CreationTime()
+}
+
+// SetCreationTime is a no-op for BoltStore.
+func (store *boltStore) SetCreationTime(_ time.Time) {
+}
+
+func (store *boltStore) SaveMessage(seqNum int, msg []byte) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(messagesBucket).Put(seqNumKey(seqNum), msg)
+	})
+}
+
+// SaveMessageAndIncrNextSenderMsgSeqNum saves the message and increments the next sender seqnum in one transaction.
+func (store *boltStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	next := store.cache.NextSenderMsgSeqNum() + 1
+	err := store.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(messagesBucket).Put(seqNumKey(seqNum), msg); err != nil {
+			return err
+		}
+		return putSeqNum(tx.Bucket(sessionBucket), keyOutgoingSeqNum, next)
+	})
+	if err != nil {
+		return err
+	}
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+func (store *boltStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	if endSeqNum < beginSeqNum || endSeqNum < 1 {
+		return nil
+	}
+	if beginSeqNum < 0 {
+		beginSeqNum = 0
+	}
+
+	end := seqNumKey(endSeqNum)
+	return store.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(messagesBucket).Cursor()
+		for k, v := c.Seek(seqNumKey(beginSeqNum)); k != nil && string(k) <= string(end); k, v = c.Next() {
+			// Values are only valid for the life of the transaction.
+			if err := cb(append([]byte(nil), v...)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (store *boltStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	var msgs [][]byte
+	err := store.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	return msgs, err
+}
+
+// PruneMessages deletes the saved messages with a sequence number below beforeSeqNum.
+func (store *boltStore) PruneMessages(beforeSeqNum int) error {
+	if beforeSeqNum < 1 {
+		return nil
+	}
+
+	before := seqNumKey(beforeSeqNum)
+	return store.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(messagesBucket)
+		var keys [][]byte
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil && string(k) < string(before); k, _ = c.Next() {
+			keys = append(keys, k)
+		}
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes the store's database file.
+func (store *boltStore) Close() error {
+	if store.db != nil {
+		if err := store.db.Close(); err != nil {
+			return err
+		}
+		store.db = nil
+	}
+	return nil
+}
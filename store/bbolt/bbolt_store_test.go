@@ -0,0 +1,99 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package bbolt
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal/testsuite"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// BoltStoreTestSuite runs all tests in the MessageStoreTestSuite against the BoltStore implementation.
+type BoltStoreTestSuite struct {
+	testsuite.StoreTestSuite
+	boltStoreRootPath string
+	settings          *quickfix.Settings
+	sessionID         quickfix.SessionID
+}
+
+func (suite *BoltStoreTestSuite) SetupTest() {
+	suite.boltStoreRootPath = path.Join(os.TempDir(), fmt.Sprintf("BoltStoreTestSuite-%d", os.Getpid()))
+	boltStorePath := path.Join(suite.boltStoreRootPath, fmt.Sprintf("%d", time.Now().UnixNano()))
+	suite.sessionID = quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	// create settings
+	var err error
+	suite.settings, err = quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+BoltStorePath=%s
+BoltStoreSync=N
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, boltStorePath, suite.sessionID.BeginString, suite.sessionID.SenderCompID, suite.sessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	// create store
+	suite.MsgStore, err = NewStoreFactory(suite.settings).Create(suite.sessionID)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *BoltStoreTestSuite) TestReopen() {
+	require.Nil(suite.T(), suite.MsgStore.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("msg1")))
+	require.Nil(suite.T(), suite.MsgStore.IncrNextTargetMsgSeqNum())
+	creationTime := suite.MsgStore.CreationTime()
+	require.Nil(suite.T(), suite.MsgStore.Close())
+
+	var err error
+	suite.MsgStore, err = NewStoreFactory(suite.settings).Create(suite.sessionID)
+	require.Nil(suite.T(), err)
+	suite.Equal(2, suite.MsgStore.NextSenderMsgSeqNum())
+	suite.Equal(2, suite.MsgStore.NextTargetMsgSeqNum())
+	suite.True(creationTime.Equal(suite.MsgStore.CreationTime()))
+
+	msgs, err := suite.MsgStore.GetMessages(1, 1)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{[]byte("msg1")}, msgs)
+}
+
+func (suite *BoltStoreTestSuite) TestPruneMessages() {
+	for seqNum := 1; seqNum <= 3; seqNum++ {
+		require.Nil(suite.T(), suite.MsgStore.SaveMessage(seqNum, []byte(fmt.Sprintf("msg%d", seqNum))))
+	}
+
+	require.Nil(suite.T(), suite.MsgStore.(*boltStore).PruneMessages(3))
+	msgs, err := suite.MsgStore.GetMessages(1, 3)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{[]byte("msg3")}, msgs)
+}
+
+func (suite *BoltStoreTestSuite) TearDownTest() {
+	suite.MsgStore.Close()
+	os.RemoveAll(suite.boltStoreRootPath)
+}
+
+func TestBoltStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(BoltStoreTestSuite))
+}
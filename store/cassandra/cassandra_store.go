@@ -0,0 +1,319 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package cassandra
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+const (
+	defaultMessagesTable = "messages"
+	defaultSessionsTable = "sessions"
+)
+
+type cassandraStoreFactory struct {
+	settings *quickfix.Settings
+}
+
+type cassandraStore struct {
+	sessionID  quickfix.SessionID
+	sessionKey string
+	cache      quickfix.MessageStore
+	db         *gocql.Session
+	messageTTL int
+
+	cqlInsertSession      string
+	cqlGetSession         string
+	cqlUpdateSenderSeqNum string
+	cqlUpdateTargetSeqNum string
+	cqlInsertMessage      string
+	cqlGetMessages        string
+	cqlDeleteMessages     string
+}
+
+// NewStoreFactory returns a cassandra-based implementation of MessageStoreFactory.
+func NewStoreFactory(settings *quickfix.Settings) quickfix.MessageStoreFactory {
+	return cassandraStoreFactory{settings: settings}
+}
+
+// Create creates a new CassandraStore implementation of the MessageStore interface.
+func (f cassandraStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfix.MessageStore, err error) {
+	globalSettings := f.settings.GlobalSettings()
+	dynamicSessions, _ := globalSettings.BoolSetting(config.DynamicSessions)
+
+	sessionSettings, ok := f.settings.SessionSettings()[sessionID]
+	if !ok {
+		if dynamicSessions {
+			sessionSettings = globalSettings
+		} else {
+			return nil, fmt.Errorf("unknown session: %v", sessionID)
+		}
+	}
+	hosts, err := sessionSettings.Setting(config.CassandraStoreHosts)
+	if err != nil {
+		return nil, err
+	}
+	keyspace, err := sessionSettings.Setting(config.CassandraStoreKeyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Optional.
+	cluster := gocql.NewCluster(strings.Split(hosts, ",")...)
+	cluster.Keyspace = keyspace
+	cluster.Consistency = gocql.Quorum
+	if sessionSettings.HasSetting(config.CassandraStoreConsistency) {
+		consistency, err := sessionSettings.Setting(config.CassandraStoreConsistency)
+		if err != nil {
+			return nil, err
+		}
+		if cluster.Consistency, err = gocql.ParseConsistencyWrapper(consistency); err != nil {
+			return nil, quickfix.IncorrectFormatForSetting{Setting: config.CassandraStoreConsistency, Value: []byte(consistency), Err: err}
+		}
+	}
+	if sessionSettings.HasSetting(config.CassandraStoreUsername) {
+		username, err := sessionSettings.Setting(config.CassandraStoreUsername)
+		if err != nil {
+			return nil, err
+		}
+		password, _ := sessionSettings.Setting(config.CassandraStorePassword)
+		cluster.Authenticator = gocql.PasswordAuthenticator{Username: username, Password: password}
+	}
+	var messageTTL time.Duration
+	if sessionSettings.HasSetting(config.CassandraStoreMessageTTL) {
+		if messageTTL, err = sessionSettings.DurationSetting(config.CassandraStoreMessageTTL); err != nil {
+			return nil, err
+		}
+	}
+	messagesTable := defaultMessagesTable
+	if name, err := sessionSettings.Setting(config.CassandraStoreMessagesTableName); err == nil {
+		messagesTable = name
+	}
+	sessionsTable := defaultSessionsTable
+	if name, err := sessionSettings.Setting(config.CassandraStoreSessionsTableName); err == nil {
+		sessionsTable = name
+	}
+
+	return newCassandraStore(sessionID, cluster, messagesTable, sessionsTable, messageTTL)
+}
+
+func newCassandraStore(sessionID quickfix.SessionID, cluster *gocql.ClusterConfig, messagesTable, sessionsTable string, messageTTL time.Duration) (store *cassandraStore, err error) {
+	memStore, memErr := quickfix.NewMemoryStoreFactory().Create(sessionID)
+	if memErr != nil {
+		err = errors.Wrap(memErr, "cache creation")
+		return
+	}
+
+	store = &cassandraStore{
+		sessionID:  sessionID,
+		sessionKey: sessionID.String(),
+		cache:      memStore,
+		messageTTL: int(messageTTL / time.Second),
+	}
+
+	if err = store.cache.Reset(); err != nil {
+		err = errors.Wrap(err, "cache reset")
+		return
+	}
+
+	if store.db, err = cluster.CreateSession(); err != nil {
+		err = errors.Wrap(err, "connect")
+		return
+	}
+
+	store.setCQLStatements(messagesTable, sessionsTable)
+	err = store.populateCache()
+	return
+}
+
+// setCQLStatements builds the statements used by the store. gocql prepares each statement on first use
+// and reuses the prepared statement afterwards.
+func (store *cassandraStore) setCQLStatements(messagesTable, sessionsTable string) {
+	store.cqlInsertSession = fmt.Sprintf(`INSERT INTO %s (session_id, creation_time, incoming_seqnum, outgoing_seqnum) VALUES (?, ?, ?, ?)`,
+		sessionsTable)
+	store.cqlGetSession = fmt.Sprintf(`SELECT creation_time, incoming_seqnum, outgoing_seqnum FROM %s WHERE session_id=?`,
+		sessionsTable)
+	store.cqlUpdateSenderSeqNum = fmt.Sprintf(`UPDATE %s SET outgoing_seqnum=? WHERE session_id=?`,
+		sessionsTable)
+	store.cqlUpdateTargetSeqNum = fmt.Sprintf(`UPDATE %s SET incoming_seqnum=? WHERE session_id=?`,
+		sessionsTable)
+	store.cqlInsertMessage = fmt.Sprintf(`INSERT INTO %s (session_id, msgseqnum, message) VALUES (?, ?, ?) USING TTL ?`,
+		messagesTable)
+	store.cqlGetMessages = fmt.Sprintf(`SELECT message FROM %s WHERE session_id=? AND msgseqnum>=? AND msgseqnum<=?`,
+		messagesTable)
+	store.cqlDeleteMessages = fmt.Sprintf(`DELETE FROM %s WHERE session_id=?`,
+		messagesTable)
+}
+
+// Reset deletes the store records and sets the seqnums back to 1.
+func (store *cassandraStore) Reset() error {
+	if err := store.db.Query(store.cqlDeleteMessages, store.sessionKey).Exec(); err != nil {
+		return err
+	}
+
+	if err := store.cache.Reset(); err != nil {
+		return err
+	}
+
+	return store.db.Query(store.cqlInsertSession, store.sessionKey,
+		store.cache.CreationTime(), store.cache.NextTargetMsgSeqNum(), store.cache.NextSenderMsgSeqNum()).Exec()
+}
+
+// Refresh reloads the store from the database.
+func (store *cassandraStore) Refresh() error {
+	if err := store.cache.Reset(); err != nil {
+		return err
+	}
+	return store.populateCache()
+}
+
+func (store *cassandraStore) populateCache() error {
+	var creationTime time.Time
+	var incomingSeqNum, outgoingSeqNum int
+	err := store.db.Query(store.cqlGetSession, store.sessionKey).Scan(&creationTime, &incomingSeqNum, &outgoingSeqNum)
+
+	// session record found, load it
+	if err == nil {
+		store.cache.SetCreationTime(creationTime)
+		if err = store.cache.SetNextTargetMsgSeqNum(incomingSeqNum); err != nil {
+			return errors.Wrap(err, "cache set next target")
+		}
+		if err = store.cache.SetNextSenderMsgSeqNum(outgoingSeqNum); err != nil {
+			return errors.Wrap(err, "cache set next sender")
+		}
+		return nil
+	}
+
+	// fatal error, give up
+	if err != gocql.ErrNotFound {
+		return errors.Wrap(err, "query")
+	}
+
+	// session record not found, create it
+	return store.db.Query(store.cqlInsertSession, store.sessionKey,
+		store.cache.CreationTime(), store.cache.NextTargetMsgSeqNum(), store.cache.NextSenderMsgSeqNum()).Exec()
+}
+
+// NextSenderMsgSeqNum returns the next MsgSeqNum that will be sent.
+func (store *cassandraStore) NextSenderMsgSeqNum() int {
+	return store.cache.NextSenderMsgSeqNum()
+}
+
+// NextTargetMsgSeqNum returns the next MsgSeqNum that should be received.
+func (store *cassandraStore) NextTargetMsgSeqNum() int {
+	return store.cache.NextTargetMsgSeqNum()
+}
+
+// SetNextSenderMsgSeqNum sets the next MsgSeqNum that will be sent.
+func (store *cassandraStore) SetNextSenderMsgSeqNum(next int) error {
+	if err := store.db.Query(store.cqlUpdateSenderSeqNum, next, store.sessionKey).Exec(); err != nil {
+		return err
+	}
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+// SetNextTargetMsgSeqNum sets the next MsgSeqNum that should be received.
+func (store *cassandraStore) SetNextTargetMsgSeqNum(next int) error {
+	if err := store.db.Query(store.cqlUpdateTargetSeqNum, next, store.sessionKey).Exec(); err != nil {
+		return err
+	}
+	return store.cache.SetNextTargetMsgSeqNum(next)
+}
+
+// IncrNextSenderMsgSeqNum increments the next MsgSeqNum that will be sent.
+func (store *cassandraStore) IncrNextSenderMsgSeqNum() error {
+	if err := store.SetNextSenderMsgSeqNum(store.cache.NextSenderMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "store next")
+	}
+	return nil
+}
+
+// IncrNextTargetMsgSeqNum increments the next MsgSeqNum that should be received.
+func (store *cassandraStore) IncrNextTargetMsgSeqNum() error {
+	if err := store.SetNextTargetMsgSeqNum(store.cache.NextTargetMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "store next")
+	}
+	return nil
+}
+
+// CreationTime returns the creation time of the store.
+func (store *cassandraStore) CreationTime() time.Time {
+	return store.cache.CreationTime()
+}
+
+// SetCreationTime is a no-op for CassandraStore.
+func (store *cassandraStore) SetCreationTime(_ time.Time) {
+}
+
+func (store *cassandraStore) SaveMessage(seqNum int, msg []byte) error {
+	return store.db.Query(store.cqlInsertMessage, store.sessionKey, seqNum, msg, store.messageTTL).Exec()
+}
+
+// SaveMessageAndIncrNextSenderMsgSeqNum saves the message and the incremented seqnum in a logged batch,
+// so either both writes are eventually applied or neither is.
+func (store *cassandraStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	next := store.cache.NextSenderMsgSeqNum() + 1
+
+	batch := store.db.NewBatch(gocql.LoggedBatch)
+	batch.Query(store.cqlInsertMessage, store.sessionKey, seqNum, msg, store.messageTTL)
+	batch.Query(store.cqlUpdateSenderSeqNum, next, store.sessionKey)
+	if err := store.db.ExecuteBatch(batch); err != nil {
+		return err
+	}
+
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+func (store *cassandraStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	iter := store.db.Query(store.cqlGetMessages, store.sessionKey, beginSeqNum, endSeqNum).Iter()
+
+	var msg []byte
+	for iter.Scan(&msg) {
+		if err := cb(msg); err != nil {
+			_ = iter.Close()
+			return err
+		}
+		msg = nil
+	}
+
+	return iter.Close()
+}
+
+func (store *cassandraStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	var msgs [][]byte
+	err := store.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	return msgs, err
+}
+
+// Close closes the store's database session.
+func (store *cassandraStore) Close() error {
+	if store.db != nil {
+		store.db.Close()
+		store.db = nil
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package cassandra
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal/testsuite"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// CassandraStoreTestSuite runs all tests in the message.StoreTestSuite against the CassandraStore implementation.
+type CassandraStoreTestSuite struct {
+	testsuite.StoreTestSuite
+}
+
+func (suite *CassandraStoreTestSuite) SetupTest() {
+	cassandraHosts := os.Getenv("CASSANDRA_TEST_HOSTS")
+	if len(cassandraHosts) <= 0 {
+		log.Println("CASSANDRA_TEST_HOSTS environment arg is not provided, skipping...")
+		suite.T().SkipNow()
+	}
+	keyspace := "automated_testing_keyspace"
+
+	// create keyspace and tables
+	cluster := gocql.NewCluster(strings.Split(cassandraHosts, ",")...)
+	db, err := cluster.CreateSession()
+	require.Nil(suite.T(), err)
+	defer db.Close()
+	err = db.Query(fmt.Sprintf(`CREATE KEYSPACE IF NOT EXISTS %s WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 1}`, keyspace)).Exec()
+	require.Nil(suite.T(), err)
+	cqlFnames, err := filepath.Glob("../../_sql/cassandra/*.cql")
+	require.Nil(suite.T(), err)
+	for _, fname := range cqlFnames {
+		cqlBytes, err := os.ReadFile(fname)
+		require.Nil(suite.T(), err)
+		err = db.Query(strings.Replace(string(cqlBytes), "IF NOT EXISTS ", "IF NOT EXISTS "+keyspace+".", 1)).Exec()
+		require.Nil(suite.T(), err)
+	}
+
+	// create settings
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+CassandraStoreHosts=%s
+CassandraStoreKeyspace=%s
+CassandraStoreConsistency=ONE
+CassandraStoreMessageTTL=1h
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, cassandraHosts, keyspace, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	// create store
+	suite.MsgStore, err = NewStoreFactory(settings).Create(sessionID)
+	require.Nil(suite.T(), err)
+	err = suite.MsgStore.Reset()
+	require.Nil(suite.T(), err)
+}
+
+func (suite *CassandraStoreTestSuite) TearDownTest() {
+	if suite.MsgStore != nil {
+		err := suite.MsgStore.Close()
+		require.Nil(suite.T(), err)
+	}
+}
+
+func TestCassandraStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(CassandraStoreTestSuite))
+}
@@ -0,0 +1,435 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+const (
+	defaultSessionsTable = "quickfix_sessions"
+	defaultMessagesTable = "quickfix_messages"
+
+	// batchWriteLimit is the maximum number of requests in a BatchWriteItem call.
+	batchWriteLimit = 25
+)
+
+// ErrSeqNumConflict is returned when the stored sequence numbers were changed by another
+// process since they were loaded, e.g. by a second engine running the same session.
+var ErrSeqNumConflict = errors.New("sequence numbers were modified by another process")
+
+type dynamoDBStoreFactory struct {
+	settings *quickfix.Settings
+}
+
+type dynamoDBStore struct {
+	sessionID     quickfix.SessionID
+	cache         quickfix.MessageStore
+	db            *dynamodb.Client
+	sessionKey    types.AttributeValue
+	sessionsTable string
+	messagesTable string
+}
+
+// NewStoreFactory returns a DynamoDB-based implementation of MessageStoreFactory.
+func NewStoreFactory(settings *quickfix.Settings) quickfix.MessageStoreFactory {
+	return dynamoDBStoreFactory{settings: settings}
+}
+
+// Create creates a new DynamoDBStore implementation of the MessageStore interface.
+func (f dynamoDBStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfix.MessageStore, err error) {
+	globalSettings := f.settings.GlobalSettings()
+	dynamicSessions, _ := globalSettings.BoolSetting(config.DynamicSessions)
+
+	sessionSettings, ok := f.settings.SessionSettings()[sessionID]
+	if !ok {
+		if dynamicSessions {
+			sessionSettings = globalSettings
+		} else {
+			return nil, fmt.Errorf("unknown session: %v", sessionID)
+		}
+	}
+	region, err := sessionSettings.Setting(config.DynamoDBStoreRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Optional.
+	endpoint, _ := sessionSettings.Setting(config.DynamoDBStoreEndpoint)
+	sessionsTable := defaultSessionsTable
+	if name, err := sessionSettings.Setting(config.DynamoDBStoreSessionsTableName); err == nil {
+		sessionsTable = name
+	}
+	messagesTable := defaultMessagesTable
+	if name, err := sessionSettings.Setting(config.DynamoDBStoreMessagesTableName); err == nil {
+		messagesTable = name
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, errors.Wrap(err, "load aws config")
+	}
+	db := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+
+	return newDynamoDBStore(sessionID, db, sessionsTable, messagesTable)
+}
+
+func newDynamoDBStore(sessionID quickfix.SessionID, db *dynamodb.Client, sessionsTable, messagesTable string) (store *dynamoDBStore, err error) {
+	memStore, memErr := quickfix.NewMemoryStoreFactory().Create(sessionID)
+	if memErr != nil {
+		err = errors.Wrap(memErr, "cache creation")
+		return
+	}
+
+	store = &dynamoDBStore{
+		sessionID:     sessionID,
+		cache:         memStore,
+		db:            db,
+		sessionKey:    &types.AttributeValueMemberS{Value: sessionID.String()},
+		sessionsTable: sessionsTable,
+		messagesTable: messagesTable,
+	}
+
+	if err = store.cache.Reset(); err != nil {
+		err = errors.Wrap(err, "cache reset")
+		return
+	}
+
+	err = store.populateCache()
+	return
+}
+
+func number(n int) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.Itoa(n)}
+}
+
+func (store *dynamoDBStore) sessionItem() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"session_id":       store.sessionKey,
+		"creation_time":    &types.AttributeValueMemberS{Value: store.cache.CreationTime().UTC().Format(time.RFC3339Nano)},
+		"incoming_seq_num": number(store.cache.NextTargetMsgSeqNum()),
+		"outgoing_seq_num": number(store.cache.NextSenderMsgSeqNum()),
+	}
+}
+
+// updateSeqNum returns an update of a session sequence number attribute that only succeeds
+// if the attribute still holds the value cached by this store.
+func (store *dynamoDBStore) updateSeqNum(attribute string, current, next int) *types.Update {
+	return &types.Update{
+		TableName:           aws.String(store.sessionsTable),
+		Key:                 map[string]types.AttributeValue{"session_id": store.sessionKey},
+		UpdateExpression:    aws.String("SET #seq = :next"),
+		ConditionExpression: aws.String("#seq = :current"),
+		ExpressionAttributeNames: map[string]string{
+			"#seq": attribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":next":    number(next),
+			":current": number(current),
+		},
+	}
+}
+
+func (store *dynamoDBStore) execUpdate(update *types.Update) error {
+	_, err := store.db.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{
+		TableName:                 update.TableName,
+		Key:                       update.Key,
+		UpdateExpression:          update.UpdateExpression,
+		ConditionExpression:       update.ConditionExpression,
+		ExpressionAttributeNames:  update.ExpressionAttributeNames,
+		ExpressionAttributeValues: update.ExpressionAttributeValues,
+	})
+	return conflictError(err)
+}
+
+func conflictError(err error) error {
+	var conditionFailed *types.ConditionalCheckFailedException
+	var txCanceled *types.TransactionCanceledException
+	if errors.As(err, &conditionFailed) || errors.As(err, &txCanceled) {
+		return errors.Wrap(ErrSeqNumConflict, err.Error())
+	}
+	return err
+}
+
+// Reset deletes the store records and sets the seqnums back to 1.
+func (store *dynamoDBStore) Reset() error {
+	if err := store.deleteMessages(); err != nil {
+		return err
+	}
+
+	if err := store.cache.Reset(); err != nil {
+		return err
+	}
+
+	_, err := store.db.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(store.sessionsTable),
+		Item:      store.sessionItem(),
+	})
+	return err
+}
+
+func (store *dynamoDBStore) deleteMessages() error {
+	ctx := context.Background()
+	paginator := dynamodb.NewQueryPaginator(store.db, &dynamodb.QueryInput{
+		TableName:                 aws.String(store.messagesTable),
+		KeyConditionExpression:    aws.String("session_id = :s"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":s": store.sessionKey},
+		ProjectionExpression:      aws.String("session_id, msgseq"),
+		ConsistentRead:            aws.Bool(true),
+	})
+
+	var deletes []types.WriteRequest
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return errors.Wrap(err, "query")
+		}
+		for _, item := range page.Items {
+			deletes = append(deletes, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: item}})
+		}
+	}
+
+	for len(deletes) > 0 {
+		n := min(len(deletes), batchWriteLimit)
+		requests := map[string][]types.WriteRequest{store.messagesTable: deletes[:n]}
+		deletes = deletes[n:]
+		for len(requests) > 0 {
+			out, err := store.db.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: requests})
+			if err != nil {
+				return errors.Wrap(err, "delete")
+			}
+			requests = out.UnprocessedItems
+		}
+	}
+	return nil
+}
+
+// Refresh reloads the store from the database.
+func (store *dynamoDBStore) Refresh() error {
+	if err := store.cache.Reset(); err != nil {
+		return err
+	}
+	return store.populateCache()
+}
+
+func (store *dynamoDBStore) populateCache() error {
+	ctx := context.Background()
+	out, err := store.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(store.sessionsTable),
+		Key:            map[string]types.AttributeValue{"session_id": store.sessionKey},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return errors.Wrap(err, "query")
+	}
+
+	if len(out.Item) == 0 {
+		// session record not found, create it
+		_, err = store.db.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:           aws.String(store.sessionsTable),
+			Item:                store.sessionItem(),
+			ConditionExpression: aws.String("attribute_not_exists(session_id)"),
+		})
+		return errors.Wrap(conflictError(err), "insert")
+	}
+
+	creationTime, ok := out.Item["creation_time"].(*types.AttributeValueMemberS)
+	if !ok {
+		return errors.New("session record missing creation_time")
+	}
+	incomingSeqNum, ok := out.Item["incoming_seq_num"].(*types.AttributeValueMemberN)
+	if !ok {
+		return errors.New("session record missing incoming_seq_num")
+	}
+	outgoingSeqNum, ok := out.Item["outgoing_seq_num"].(*types.AttributeValueMemberN)
+	if !ok {
+		return errors.New("session record missing outgoing_seq_num")
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, creationTime.Value)
+	if err != nil {
+		return errors.Wrap(err, "parse creation time")
+	}
+	incoming, err := strconv.Atoi(incomingSeqNum.Value)
+	if err != nil {
+		return errors.Wrap(err, "parse incoming seqnum")
+	}
+	outgoing, err := strconv.Atoi(outgoingSeqNum.Value)
+	if err != nil {
+		return errors.Wrap(err, "parse outgoing seqnum")
+	}
+
+	store.cache.SetCreationTime(t)
+	if err := store.cache.SetNextTargetMsgSeqNum(incoming); err != nil {
+		return errors.Wrap(err, "cache set next target")
+	}
+	if err := store.cache.SetNextSenderMsgSeqNum(outgoing); err != nil {
+		return errors.Wrap(err, "cache set next sender")
+	}
+	return nil
+}
+
+// NextSenderMsgSeqNum returns the next MsgSeqNum that will be sent.
+func (store *dynamoDBStore) NextSenderMsgSeqNum() int {
+	return store.cache.NextSenderMsgSeqNum()
+}
+
+// NextTargetMsgSeqNum returns the next MsgSeqNum that should be received.
+func (store *dynamoDBStore) NextTargetMsgSeqNum() int {
+	return store.cache.NextTargetMsgSeqNum()
+}
+
+// SetNextSenderMsgSeqNum sets the next MsgSeqNum that will be sent.
+func (store *dynamoDBStore) SetNextSenderMsgSeqNum(next int) error {
+	if err := store.execUpdate(store.updateSeqNum("outgoing_seq_num", store.cache.NextSenderMsgSeqNum(), next)); err != nil {
+		return err
+	}
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+// SetNextTargetMsgSeqNum sets the next MsgSeqNum that should be received.
+func (store *dynamoDBStore) SetNextTargetMsgSeqNum(next int) error {
+	if err := store.execUpdate(store.updateSeqNum("incoming_seq_num", store.cache.NextTargetMsgSeqNum(), next)); err != nil {
+		return err
+	}
+	return store.cache.SetNextTargetMsgSeqNum(next)
+}
+
+// IncrNextSenderMsgSeqNum increments the next MsgSeqNum that will be sent.
+func (store *dynamoDBStore) IncrNextSenderMsgSeqNum() error {
+	if err := store.SetNextSenderMsgSeqNum(store.cache.NextSenderMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "save sequence number")
+	}
+	return nil
+}
+
+// IncrNextTargetMsgSeqNum increments the next MsgSeqNum that should be received.
+func (store *dynamoDBStore) IncrNextTargetMsgSeqNum() error {
+	if err := store.SetNextTargetMsgSeqNum(store.cache.NextTargetMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "save sequence number")
+	}
+	return nil
+}
+
+// CreationTime returns the creation time of the store.
+func (store *dynamoDBStore) CreationTime() time.Time {
+	return store.cache.CreationTime()
+}
+
+// SetCreationTime is a no-op for DynamoDBStore.
+func (store *dynamoDBStore) SetCreationTime(_ time.Time) {
+}
+
+func (store *dynamoDBStore) messageItem(seqNum int, msg []byte) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"session_id": store.sessionKey,
+		"msgseq":     number(seqNum),
+		"message":    &types.AttributeValueMemberB{Value: msg},
+	}
+}
+
+func (store *dynamoDBStore) SaveMessage(seqNum int, msg []byte) error {
+	_, err := store.db.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(store.messagesTable),
+		Item:      store.messageItem(seqNum, msg),
+	})
+	return err
+}
+
+func (store *dynamoDBStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	next := store.cache.NextSenderMsgSeqNum() + 1
+	_, err := store.db.TransactWriteItems(context.Background(), &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{
+				TableName: aws.String(store.messagesTable),
+				Item:      store.messageItem(seqNum, msg),
+			}},
+			{Update: store.updateSeqNum("outgoing_seq_num", store.cache.NextSenderMsgSeqNum(), next)},
+		},
+	})
+	if err != nil {
+		return conflictError(err)
+	}
+
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+func (store *dynamoDBStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	if beginSeqNum > endSeqNum {
+		return nil
+	}
+
+	ctx := context.Background()
+	paginator := dynamodb.NewQueryPaginator(store.db, &dynamodb.QueryInput{
+		TableName:              aws.String(store.messagesTable),
+		KeyConditionExpression: aws.String("session_id = :s AND msgseq BETWEEN :begin AND :end"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":s":     store.sessionKey,
+			":begin": number(beginSeqNum),
+			":end":   number(endSeqNum),
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range page.Items {
+			msg, ok := item["message"].(*types.AttributeValueMemberB)
+			if !ok {
+				return errors.New("message record missing message")
+			}
+			if err = cb(msg.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (store *dynamoDBStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	var msgs [][]byte
+	err := store.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	return msgs, err
+}
+
+// Close is a no-op for DynamoDBStore, the client holds no connections that need closing.
+func (store *dynamoDBStore) Close() error {
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal/testsuite"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// DynamoDBStoreTestSuite runs all tests in the message.StoreTestSuite against the DynamoDBStore implementation.
+type DynamoDBStoreTestSuite struct {
+	testsuite.StoreTestSuite
+	settings  *quickfix.Settings
+	sessionID quickfix.SessionID
+}
+
+func (suite *DynamoDBStoreTestSuite) SetupTest() {
+	endpoint := os.Getenv("DYNAMODB_TEST_ENDPOINT")
+	if len(endpoint) <= 0 {
+		log.Println("DYNAMODB_TEST_ENDPOINT environment arg is not provided, skipping...")
+		suite.T().SkipNow()
+	}
+	if os.Getenv("AWS_ACCESS_KEY_ID") == "" {
+		suite.T().Setenv("AWS_ACCESS_KEY_ID", "test")
+		suite.T().Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	}
+
+	// create settings
+	suite.sessionID = quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	var err error
+	suite.settings, err = quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+DynamoDBStoreRegion=us-east-1
+DynamoDBStoreEndpoint=%s
+DynamoDBStoreSessionsTableName=automated_testing_sessions
+DynamoDBStoreMessagesTableName=automated_testing_messages
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, endpoint, suite.sessionID.BeginString, suite.sessionID.SenderCompID, suite.sessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	// create tables and store
+	suite.createTables(endpoint)
+	suite.MsgStore, err = NewStoreFactory(suite.settings).Create(suite.sessionID)
+	require.Nil(suite.T(), err)
+	err = suite.MsgStore.Reset()
+	require.Nil(suite.T(), err)
+}
+
+func (suite *DynamoDBStoreTestSuite) createTables(endpoint string) {
+	credentials := aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: os.Getenv("AWS_ACCESS_KEY_ID"), SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY")}, nil
+	})
+	db := dynamodb.NewFromConfig(aws.Config{Region: "us-east-1", Credentials: credentials}, func(o *dynamodb.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+	})
+	tables := []*dynamodb.CreateTableInput{
+		{
+			TableName:            aws.String("automated_testing_sessions"),
+			AttributeDefinitions: []types.AttributeDefinition{{AttributeName: aws.String("session_id"), AttributeType: types.ScalarAttributeTypeS}},
+			KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String("session_id"), KeyType: types.KeyTypeHash}},
+			BillingMode:          types.BillingModePayPerRequest,
+		},
+		{
+			TableName: aws.String("automated_testing_messages"),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{AttributeName: aws.String("session_id"), AttributeType: types.ScalarAttributeTypeS},
+				{AttributeName: aws.String("msgseq"), AttributeType: types.ScalarAttributeTypeN},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("session_id"), KeyType: types.KeyTypeHash},
+				{AttributeName: aws.String("msgseq"), KeyType: types.KeyTypeRange},
+			},
+			BillingMode: types.BillingModePayPerRequest,
+		},
+	}
+	for _, table := range tables {
+		_, err := db.CreateTable(context.Background(), table)
+		var inUse *types.ResourceInUseException
+		if !errors.As(err, &inUse) {
+			require.Nil(suite.T(), err)
+		}
+	}
+}
+
+func (suite *DynamoDBStoreTestSuite) TestSeqNumConflict() {
+	other, err := NewStoreFactory(suite.settings).Create(suite.sessionID)
+	require.Nil(suite.T(), err)
+	require.Nil(suite.T(), other.IncrNextSenderMsgSeqNum())
+
+	// The second store changed the seqnum behind this store's back.
+	err = suite.MsgStore.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("msg"))
+	suite.True(errors.Is(err, ErrSeqNumConflict), err)
+
+	require.Nil(suite.T(), suite.MsgStore.Refresh())
+	suite.Equal(2, suite.MsgStore.NextSenderMsgSeqNum())
+}
+
+func (suite *DynamoDBStoreTestSuite) TearDownTest() {
+	if suite.MsgStore != nil {
+		err := suite.MsgStore.Close()
+		require.Nil(suite.T(), err)
+	}
+}
+
+func TestDynamoDBStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(DynamoDBStoreTestSuite))
+}
@@ -0,0 +1,322 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package encrypted
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// prefix marks an encrypted message. Messages without it are returned as is,
+// so encryption can be enabled on a store that already holds plaintext messages.
+var prefix = []byte("qfenc2:")
+
+// prefixV1 marks a message encrypted without its seqnum, which is still decrypted.
+var prefixV1 = []byte("qfenc1:")
+
+// queueSeqNum is the seqnum messages of the outbound queue are encrypted with, as they have none yet.
+const queueSeqNum = 0
+
+// KeyProvider supplies the AES keys used to encrypt and decrypt messages.
+// Keys must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+type KeyProvider interface {
+	// CurrentKey returns the key new messages are encrypted with, and its id.
+	CurrentKey() (id string, key []byte, err error)
+
+	// Key returns the key with the given id. Keys that were rotated out must stay
+	// available for as long as messages encrypted with them are kept in the store.
+	Key(id string) ([]byte, error)
+}
+
+type keyRing struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+// NewKeyRing returns a KeyProvider holding a fixed set of keys by id, encrypting with the key currentID.
+// To rotate keys, add the new key and make it current, keeping the previous keys for decryption.
+func NewKeyRing(currentID string, keys map[string][]byte) (KeyProvider, error) {
+	if _, ok := keys[currentID]; !ok {
+		return nil, fmt.Errorf("unknown current key id: %s", currentID)
+	}
+	for id, key := range keys {
+		if len(id) > 255 {
+			return nil, fmt.Errorf("key id too long: %s", id)
+		}
+		if _, err := aes.NewCipher(key); err != nil {
+			return nil, errors.Wrapf(err, "key %s", id)
+		}
+	}
+	return keyRing{currentID: currentID, keys: keys}, nil
+}
+
+func (k keyRing) CurrentKey() (string, []byte, error) {
+	return k.currentID, k.keys[k.currentID], nil
+}
+
+func (k keyRing) Key(id string) ([]byte, error) {
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %s", id)
+	}
+	return key, nil
+}
+
+type encryptedStoreFactory struct {
+	inner quickfix.MessageStoreFactory
+	keys  KeyProvider
+}
+
+// NewStoreFactory returns a MessageStoreFactory whose stores AES-GCM encrypt message bodies
+// before saving them to the stores created by inner, and decrypt them on replay.
+// Each message is bound to its session and seqnum. Sequence numbers and creation times are stored unencrypted.
+//
+// The stores forward PruneMessages, Flush and Compact to the stores created by inner, doing nothing if those do not
+// implement them, and implement quickfix.OutboundQueueStore, encrypting the queued messages, if those do.
+func NewStoreFactory(inner quickfix.MessageStoreFactory, keys KeyProvider) quickfix.MessageStoreFactory {
+	return encryptedStoreFactory{inner: inner, keys: keys}
+}
+
+// Create creates a new encrypted MessageStore wrapping a store created by the inner factory.
+func (f encryptedStoreFactory) Create(sessionID quickfix.SessionID) (quickfix.MessageStore, error) {
+	inner, err := f.inner.Create(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	store := &encryptedStore{
+		MessageStore: inner,
+		keys:         f.keys,
+		aad:          []byte(sessionID.String()),
+	}
+	if queueStore, ok := inner.(quickfix.OutboundQueueStore); ok {
+		return &encryptedQueueStore{encryptedStore: store, queueStore: queueStore}, nil
+	}
+	return store, nil
+}
+
+type encryptedStore struct {
+	quickfix.MessageStore
+	keys KeyProvider
+
+	// aad is the session of the store, authenticated with every message along with its seqnum.
+	aad []byte
+}
+
+// additionalData returns the additional data authenticated with the message saved at seqNum.
+func (store *encryptedStore) additionalData(seqNum int) []byte {
+	aad := make([]byte, 0, len(store.aad)+21)
+	aad = append(aad, store.aad...)
+	aad = append(aad, '|')
+	return strconv.AppendInt(aad, int64(seqNum), 10)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt returns prefix followed by base64(len(id) | id | seqNum | nonce | ciphertext), with seqNum as 8 bytes
+// big endian. The output is text so it can be kept by stores with text message columns.
+func (store *encryptedStore) encrypt(seqNum int, msg []byte) ([]byte, error) {
+	id, key, err := store.keys.CurrentKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "current key")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "key %s", id)
+	}
+
+	raw := make([]byte, 0, 1+len(id)+8+gcm.NonceSize()+len(msg)+gcm.Overhead())
+	raw = append(raw, byte(len(id)))
+	raw = append(raw, id...)
+	raw = binary.BigEndian.AppendUint64(raw, uint64(seqNum))
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "nonce")
+	}
+	raw = append(raw, nonce...)
+	raw = gcm.Seal(raw, nonce, msg, store.additionalData(seqNum))
+
+	out := make([]byte, len(prefix)+base64.StdEncoding.EncodedLen(len(raw)))
+	copy(out, prefix)
+	base64.StdEncoding.Encode(out[len(prefix):], raw)
+	return out, nil
+}
+
+// decrypt returns the message encrypted in data and the seqnum it was encrypted for,
+// which is -1 for plaintext messages and messages encrypted without their seqnum.
+func (store *encryptedStore) decrypt(data []byte) (msg []byte, seqNum int, err error) {
+	seqNum = -1
+	withSeqNum := bytes.HasPrefix(data, prefix)
+	if !withSeqNum && !bytes.HasPrefix(data, prefixV1) {
+		return data, seqNum, nil
+	}
+
+	data = data[len(prefix):] // prefix and prefixV1 have the same length
+	raw := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+	n, err := base64.StdEncoding.Decode(raw, data)
+	if err != nil {
+		return nil, seqNum, errors.Wrap(err, "decode message")
+	}
+	raw = raw[:n]
+
+	if len(raw) < 1 || len(raw) < 1+int(raw[0]) {
+		return nil, seqNum, errors.New("truncated message")
+	}
+	id := string(raw[1 : 1+int(raw[0])])
+	raw = raw[1+int(raw[0]):]
+
+	aad := store.aad
+	if withSeqNum {
+		if len(raw) < 8 {
+			return nil, seqNum, errors.New("truncated message")
+		}
+		seqNum = int(binary.BigEndian.Uint64(raw))
+		aad = store.additionalData(seqNum)
+		raw = raw[8:]
+	}
+
+	key, err := store.keys.Key(id)
+	if err != nil {
+		return nil, seqNum, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, seqNum, errors.Wrapf(err, "key %s", id)
+	}
+	if len(raw) < gcm.NonceSize() {
+		return nil, seqNum, errors.New("truncated message")
+	}
+
+	msg, err = gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], aad)
+	return msg, seqNum, errors.Wrap(err, "decrypt message")
+}
+
+func (store *encryptedStore) SaveMessage(seqNum int, msg []byte) error {
+	data, err := store.encrypt(seqNum, msg)
+	if err != nil {
+		return err
+	}
+	return store.MessageStore.SaveMessage(seqNum, data)
+}
+
+func (store *encryptedStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	data, err := store.encrypt(seqNum, msg)
+	if err != nil {
+		return err
+	}
+	return store.MessageStore.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, data)
+}
+
+func (store *encryptedStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	return store.MessageStore.IterateMessages(beginSeqNum, endSeqNum, func(data []byte) error {
+		msg, seqNum, err := store.decrypt(data)
+		if err != nil {
+			return err
+		}
+		// A message moved to another seqnum in the inner store is authentic, but not the one saved there.
+		if seqNum >= 0 && (seqNum < beginSeqNum || seqNum > endSeqNum) {
+			return fmt.Errorf("message encrypted for seqnum %d read between seqnums %d and %d", seqNum, beginSeqNum, endSeqNum)
+		}
+		return cb(msg)
+	})
+}
+
+func (store *encryptedStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	var msgs [][]byte
+	err := store.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	return msgs, err
+}
+
+// PruneMessages deletes the saved messages with a sequence number below beforeSeqNum, if the inner store can.
+func (store *encryptedStore) PruneMessages(beforeSeqNum int) error {
+	if pruner, ok := store.MessageStore.(interface{ PruneMessages(beforeSeqNum int) error }); ok {
+		return pruner.PruneMessages(beforeSeqNum)
+	}
+	return nil
+}
+
+// Flush writes the writes buffered by the inner store, such as a sql store with SQLStoreWriteBehind, if it has any.
+func (store *encryptedStore) Flush() error {
+	if flusher, ok := store.MessageStore.(interface{ Flush() error }); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Compact compacts the inner store, if it implements quickfix.Compactor.
+func (store *encryptedStore) Compact() error {
+	if compactor, ok := store.MessageStore.(quickfix.Compactor); ok {
+		return compactor.Compact()
+	}
+	return nil
+}
+
+// encryptedQueueStore is an encryptedStore over a quickfix.OutboundQueueStore, encrypting its queue too.
+type encryptedQueueStore struct {
+	*encryptedStore
+	queueStore quickfix.OutboundQueueStore
+}
+
+// QueueMessage appends msg to the outbound queue of the inner store, encrypted.
+func (store *encryptedQueueStore) QueueMessage(msg quickfix.QueuedMessage) error {
+	data, err := store.encrypt(queueSeqNum, msg.Msg)
+	if err != nil {
+		return err
+	}
+	return store.queueStore.QueueMessage(quickfix.QueuedMessage{QueuedAt: msg.QueuedAt, Msg: data})
+}
+
+// QueuedMessages returns the outbound queue of the inner store, decrypted.
+func (store *encryptedQueueStore) QueuedMessages() ([]quickfix.QueuedMessage, error) {
+	queued, err := store.queueStore.QueuedMessages()
+	if err != nil {
+		return nil, err
+	}
+	for i, msg := range queued {
+		data, seqNum, err := store.decrypt(msg.Msg)
+		if err != nil {
+			return nil, err
+		} else if seqNum >= 0 && seqNum != queueSeqNum {
+			return nil, fmt.Errorf("message encrypted for seqnum %d read from the outbound queue", seqNum)
+		}
+		queued[i].Msg = data
+	}
+	return queued, nil
+}
+
+// ClearQueuedMessages empties the outbound queue of the inner store.
+func (store *encryptedQueueStore) ClearQueuedMessages() error {
+	return store.queueStore.ClearQueuedMessages()
+}
@@ -0,0 +1,236 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package encrypted
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal/testsuite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	key1 = bytes.Repeat([]byte{1}, 32)
+	key2 = bytes.Repeat([]byte{2}, 16)
+)
+
+// EncryptedStoreTestSuite runs all tests in the MessageStoreTestSuite against an encrypted MemoryStore.
+type EncryptedStoreTestSuite struct {
+	testsuite.StoreTestSuite
+}
+
+func (suite *EncryptedStoreTestSuite) SetupTest() {
+	keys, err := NewKeyRing("1", map[string][]byte{"1": key1})
+	require.Nil(suite.T(), err)
+	suite.MsgStore, err = NewStoreFactory(quickfix.NewMemoryStoreFactory(), keys).Create(quickfix.SessionID{})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *EncryptedStoreTestSuite) TestMessagesEncrypted() {
+	msg := []byte("8=FIX.4.4\x019=12\x0135=0\x01")
+	require.Nil(suite.T(), suite.MsgStore.SaveMessage(1, msg))
+
+	inner := suite.MsgStore.(*encryptedQueueStore).MessageStore
+	stored, err := inner.GetMessages(1, 1)
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), stored, 1)
+	suite.True(bytes.HasPrefix(stored[0], prefix))
+	suite.NotContains(string(stored[0]), "35=0")
+}
+
+func TestEncryptedStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(EncryptedStoreTestSuite))
+}
+
+func TestKeyRotation(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	inner, err := quickfix.NewMemoryStoreFactory().Create(sessionID)
+	require.Nil(t, err)
+	innerFactory := existingStoreFactory{inner}
+
+	// A plaintext message saved before encryption is enabled stays readable.
+	require.Nil(t, inner.SaveMessage(1, []byte("plain")))
+
+	keys, err := NewKeyRing("1", map[string][]byte{"1": key1})
+	require.Nil(t, err)
+	store, err := NewStoreFactory(innerFactory, keys).Create(sessionID)
+	require.Nil(t, err)
+	require.Nil(t, store.SaveMessage(2, []byte("first key")))
+
+	keys, err = NewKeyRing("2", map[string][]byte{"1": key1, "2": key2})
+	require.Nil(t, err)
+	store, err = NewStoreFactory(innerFactory, keys).Create(sessionID)
+	require.Nil(t, err)
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(3, []byte("second key")))
+
+	msgs, err := store.GetMessages(1, 3)
+	require.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("plain"), []byte("first key"), []byte("second key")}, msgs)
+
+	// Dropping a key still in use fails replay.
+	keys, err = NewKeyRing("2", map[string][]byte{"2": key2})
+	require.Nil(t, err)
+	store, err = NewStoreFactory(innerFactory, keys).Create(sessionID)
+	require.Nil(t, err)
+	_, err = store.GetMessages(1, 3)
+	assert.NotNil(t, err)
+}
+
+func TestEncryptedMessageBoundToSession(t *testing.T) {
+	keys, err := NewKeyRing("1", map[string][]byte{"1": key1})
+	require.Nil(t, err)
+	inner, err := quickfix.NewMemoryStoreFactory().Create(quickfix.SessionID{})
+	require.Nil(t, err)
+	innerFactory := existingStoreFactory{inner}
+
+	store, err := NewStoreFactory(innerFactory, keys).Create(quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "A", TargetCompID: "B"})
+	require.Nil(t, err)
+	require.Nil(t, store.SaveMessage(1, []byte("secret")))
+
+	other, err := NewStoreFactory(innerFactory, keys).Create(quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "A", TargetCompID: "C"})
+	require.Nil(t, err)
+	_, err = other.GetMessages(1, 1)
+	assert.NotNil(t, err)
+}
+
+func TestEncryptedMessageBoundToSeqNum(t *testing.T) {
+	keys, err := NewKeyRing("1", map[string][]byte{"1": key1})
+	require.Nil(t, err)
+	inner, err := quickfix.NewMemoryStoreFactory().Create(quickfix.SessionID{})
+	require.Nil(t, err)
+	store, err := NewStoreFactory(existingStoreFactory{inner}, keys).Create(quickfix.SessionID{})
+	require.Nil(t, err)
+	require.Nil(t, store.SaveMessage(1, []byte("secret")))
+
+	// A message copied to another seqnum in the inner store is not returned for it.
+	stored, err := inner.GetMessages(1, 1)
+	require.Nil(t, err)
+	require.Nil(t, inner.SaveMessage(2, stored[0]))
+	_, err = store.GetMessages(2, 2)
+	assert.NotNil(t, err)
+
+	msgs, err := store.GetMessages(1, 1)
+	require.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("secret")}, msgs)
+}
+
+func TestDecryptsMessagesWithoutSeqNum(t *testing.T) {
+	keys, err := NewKeyRing("1", map[string][]byte{"1": key1})
+	require.Nil(t, err)
+	inner, err := quickfix.NewMemoryStoreFactory().Create(quickfix.SessionID{})
+	require.Nil(t, err)
+	store, err := NewStoreFactory(existingStoreFactory{inner}, keys).Create(quickfix.SessionID{})
+	require.Nil(t, err)
+
+	// Messages saved before the seqnum was authenticated only carry the session.
+	gcm, err := newGCM(key1)
+	require.Nil(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	raw := gcm.Seal(append([]byte{1, '1'}, nonce...), nonce, []byte("old secret"), []byte(quickfix.SessionID{}.String()))
+	require.Nil(t, inner.SaveMessage(1, append(append([]byte{}, prefixV1...), base64.StdEncoding.EncodeToString(raw)...)))
+
+	msgs, err := store.GetMessages(1, 1)
+	require.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("old secret")}, msgs)
+}
+
+func TestEncryptedOutboundQueue(t *testing.T) {
+	keys, err := NewKeyRing("1", map[string][]byte{"1": key1})
+	require.Nil(t, err)
+	inner, err := quickfix.NewMemoryStoreFactory().Create(quickfix.SessionID{})
+	require.Nil(t, err)
+	store, err := NewStoreFactory(existingStoreFactory{inner}, keys).Create(quickfix.SessionID{})
+	require.Nil(t, err)
+
+	queueStore, ok := store.(quickfix.OutboundQueueStore)
+	require.True(t, ok)
+	require.Nil(t, queueStore.QueueMessage(quickfix.QueuedMessage{Msg: []byte("secret")}))
+
+	stored, err := inner.(quickfix.OutboundQueueStore).QueuedMessages()
+	require.Nil(t, err)
+	require.Len(t, stored, 1)
+	assert.True(t, bytes.HasPrefix(stored[0].Msg, prefix))
+
+	queued, err := queueStore.QueuedMessages()
+	require.Nil(t, err)
+	require.Len(t, queued, 1)
+	assert.Equal(t, []byte("secret"), queued[0].Msg)
+	require.Nil(t, queueStore.ClearQueuedMessages())
+}
+
+// optionalStore counts the calls of the optional interfaces of the stores.
+type optionalStore struct {
+	quickfix.MessageStore
+	prunes, flushes, compactions int
+}
+
+func (s *optionalStore) PruneMessages(int) error { s.prunes++; return nil }
+func (s *optionalStore) Flush() error            { s.flushes++; return nil }
+func (s *optionalStore) Compact() error          { s.compactions++; return nil }
+
+func TestForwardsOptionalInterfaces(t *testing.T) {
+	keys, err := NewKeyRing("1", map[string][]byte{"1": key1})
+	require.Nil(t, err)
+	memory, err := quickfix.NewMemoryStoreFactory().Create(quickfix.SessionID{})
+	require.Nil(t, err)
+
+	var tests = []struct {
+		inner    quickfix.MessageStore
+		expected int
+	}{
+		{inner: &optionalStore{MessageStore: memory}, expected: 1},
+		// Stores without the interfaces have nothing to prune, flush or compact.
+		{inner: struct{ quickfix.MessageStore }{memory}, expected: 0},
+	}
+
+	for _, test := range tests {
+		store, err := NewStoreFactory(existingStoreFactory{test.inner}, keys).Create(quickfix.SessionID{})
+		require.Nil(t, err)
+		_, isQueueStore := store.(quickfix.OutboundQueueStore)
+		assert.False(t, isQueueStore)
+
+		require.Nil(t, store.(interface{ PruneMessages(int) error }).PruneMessages(1))
+		require.Nil(t, store.(interface{ Flush() error }).Flush())
+		require.Nil(t, store.(quickfix.Compactor).Compact())
+		if inner, ok := test.inner.(*optionalStore); ok {
+			assert.Equal(t, test.expected, inner.prunes)
+			assert.Equal(t, test.expected, inner.flushes)
+			assert.Equal(t, test.expected, inner.compactions)
+		}
+	}
+}
+
+func TestNewKeyRing(t *testing.T) {
+	_, err := NewKeyRing("missing", map[string][]byte{"1": key1})
+	assert.NotNil(t, err)
+
+	_, err = NewKeyRing("1", map[string][]byte{"1": []byte("short")})
+	assert.NotNil(t, err)
+}
+
+// existingStoreFactory returns the same store for every session.
+type existingStoreFactory struct {
+	store quickfix.MessageStore
+}
+
+func (f existingStoreFactory) Create(quickfix.SessionID) (quickfix.MessageStore, error) {
+	return f.store, nil
+}
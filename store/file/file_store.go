@@ -20,6 +20,7 @@ import (
 	"io"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,6 +29,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/quickfixgo/quickfix"
 	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal/compression"
 )
 
 type fileStoreFactory struct {
@@ -39,9 +41,11 @@ type fileStore struct {
 	cache              quickfix.MessageStore
 	bodyFname          string
 	headerFname        string
+	segmentsFname      string
 	sessionFname       string
 	senderSeqNumsFname string
 	targetSeqNumsFname string
+	queueFname         string
 
 	fileMu            sync.Mutex
 	bodyFile          *os.File
@@ -50,6 +54,11 @@ type fileStore struct {
 	senderSeqNumsFile *os.File
 	targetSeqNumsFile *os.File
 	fileSync          bool
+	compression       compression.Algorithm
+
+	// segments are the body and header files in write order, the last one is written to.
+	segments       []*segment
+	maxSegmentSize int64
 }
 
 // NewStoreFactory returns a file-based implementation of MessageStoreFactory.
@@ -84,10 +93,28 @@ func (f fileStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfi
 	} else {
 		fsync = true //existing behavior is to fsync writes
 	}
-	return newFileStore(sessionID, dirname, fsync)
+	alg := compression.None
+	if sessionSettings.HasSetting(config.MessageStoreCompression) {
+		var algStr string
+		if algStr, err = sessionSettings.Setting(config.MessageStoreCompression); err != nil {
+			return nil, err
+		}
+		if alg, err = compression.Parse(algStr); err != nil {
+			return nil, quickfix.IncorrectFormatForSetting{Setting: config.MessageStoreCompression, Value: []byte(algStr), Err: err}
+		}
+	}
+	var maxSegmentSize int
+	if sessionSettings.HasSetting(config.FileStoreMaxSegmentSize) {
+		if maxSegmentSize, err = sessionSettings.IntSetting(config.FileStoreMaxSegmentSize); err != nil {
+			return nil, err
+		} else if maxSegmentSize < 0 {
+			return nil, quickfix.IncorrectFormatForSetting{Setting: config.FileStoreMaxSegmentSize, Value: []byte(strconv.Itoa(maxSegmentSize))}
+		}
+	}
+	return newFileStore(sessionID, dirname, fsync, alg, int64(maxSegmentSize))
 }
 
-func newFileStore(sessionID quickfix.SessionID, dirname string, fileSync bool) (*fileStore, error) {
+func newFileStore(sessionID quickfix.SessionID, dirname string, fileSync bool, alg compression.Algorithm, maxSegmentSize int64) (*fileStore, error) {
 	if err := os.MkdirAll(dirname, os.ModePerm); err != nil {
 		return nil, err
 	}
@@ -104,10 +131,14 @@ func newFileStore(sessionID quickfix.SessionID, dirname string, fileSync bool) (
 		cache:              memStore,
 		bodyFname:          path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "body")),
 		headerFname:        path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "header")),
+		segmentsFname:      path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "segments")),
 		sessionFname:       path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "session")),
 		senderSeqNumsFname: path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "senderseqnums")),
 		targetSeqNumsFname: path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "targetseqnums")),
+		queueFname:         path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "queue")),
 		fileSync:           fileSync,
+		compression:        alg,
+		maxSegmentSize:     maxSegmentSize,
 	}
 
 	if err := store.Refresh(); err != nil {
@@ -126,10 +157,19 @@ func (store *fileStore) Reset() error {
 	if err := store.Close(); err != nil {
 		return errors.Wrap(err, "close")
 	}
-	if err := removeFile(store.bodyFname); err != nil {
-		return err
+	// Remove the files of every segment, including any left behind by a crash during a rewrite.
+	for _, pattern := range []string{store.bodyFname, store.bodyFname + ".*", store.headerFname, store.headerFname + ".*"} {
+		fnames, err := filepath.Glob(pattern)
+		if err != nil {
+			return err
+		}
+		for _, fname := range fnames {
+			if err := removeFile(fname); err != nil {
+				return err
+			}
+		}
 	}
-	if err := removeFile(store.headerFname); err != nil {
+	if err := removeFile(store.segmentsFname); err != nil {
 		return err
 	}
 	if err := removeFile(store.sessionFname); err != nil {
@@ -155,15 +195,19 @@ func (store *fileStore) Refresh() (err error) {
 		return err
 	}
 
-	creationTimePopulated, err := store.populateCache()
-	if err != nil {
+	if err = store.loadSegments(); err != nil {
 		return err
 	}
 
-	if store.bodyFile, err = openOrCreateFile(store.bodyFname, 0660); err != nil {
+	creationTimePopulated, err := store.populateCache()
+	if err != nil {
 		return err
 	}
-	if store.headerFile, err = openOrCreateFile(store.headerFname, 0660); err != nil {
+
+	store.fileMu.Lock()
+	err = store.openSegmentFilesLocked()
+	store.fileMu.Unlock()
+	if err != nil {
 		return err
 	}
 	if store.sessionFile, err = openOrCreateFile(store.sessionFname, 0660); err != nil {
@@ -209,6 +253,15 @@ func (store *fileStore) populateCache() (creationTimePopulated bool, err error)
 		}
 	}
 
+	// A crash between saving a message and incrementing the sender seqnum leaves the last saved message
+	// at the next sender seqnum. Move past it so the seqnum is not reused for a different message.
+	lastSeqNum := store.lastSavedSeqNum()
+	if lastSeqNum > 0 && lastSeqNum == store.cache.NextSenderMsgSeqNum() {
+		if err = store.cache.SetNextSenderMsgSeqNum(lastSeqNum + 1); err != nil {
+			return creationTimePopulated, errors.Wrap(err, "cache set next sender")
+		}
+	}
+
 	if targetSeqNumBytes, err := os.ReadFile(store.targetSeqNumsFname); err == nil {
 		if targetSeqNum, err := strconv.Atoi(strings.Trim(string(targetSeqNumBytes), "\r\n")); err == nil {
 			if err = store.cache.SetNextTargetMsgSeqNum(targetSeqNum); err != nil {
@@ -220,6 +273,16 @@ func (store *fileStore) populateCache() (creationTimePopulated bool, err error)
 	return creationTimePopulated, nil
 }
 
+// lastSavedSeqNum returns the seqnum of the last saved message, or 0 if there is none.
+func (store *fileStore) lastSavedSeqNum() int {
+	for i := len(store.segments) - 1; i >= 0; i-- {
+		if seqNum := store.segments[i].lastSeqNum; seqNum > 0 {
+			return seqNum
+		}
+	}
+	return 0
+}
+
 func (store *fileStore) setSession() error {
 	store.fileMu.Lock()
 	defer store.fileMu.Unlock()
@@ -312,22 +375,35 @@ func (store *fileStore) SetCreationTime(_ time.Time) {
 }
 
 func (store *fileStore) SaveMessage(seqNum int, msg []byte) error {
+	msg, err := store.compression.Compress(msg)
+	if err != nil {
+		return errors.Wrap(err, "compress message")
+	}
+
 	store.fileMu.Lock()
 	defer store.fileMu.Unlock()
+	if seg := store.activeSegment(); store.maxSegmentSize > 0 && seg.size > 0 && seg.size+int64(len(msg)) > store.maxSegmentSize {
+		if err := store.rotateSegmentLocked(); err != nil {
+			return errors.Wrap(err, "rotate segment")
+		}
+	}
+
+	seg := store.activeSegment()
 	offset, err := store.bodyFile.Seek(0, io.SeekEnd)
 	if err != nil {
-		return fmt.Errorf("unable to seek to end of file: %s: %s", store.bodyFname, err.Error())
+		return fmt.Errorf("unable to seek to end of file: %s: %s", seg.bodyFname, err.Error())
 	}
 	if _, err := store.headerFile.Seek(0, io.SeekEnd); err != nil {
-		return fmt.Errorf("unable to seek to end of file: %s: %s", store.headerFname, err.Error())
+		return fmt.Errorf("unable to seek to end of file: %s: %s", seg.headerFname, err.Error())
 	}
 	if _, err := fmt.Fprintf(store.headerFile, "%d,%d,%d\n", seqNum, offset, len(msg)); err != nil {
-		return fmt.Errorf("unable to write to file: %s: %s", store.headerFname, err.Error())
+		return fmt.Errorf("unable to write to file: %s: %s", seg.headerFname, err.Error())
 	}
 
 	if _, err := store.bodyFile.Write(msg); err != nil {
-		return fmt.Errorf("unable to write to file: %s: %s", store.bodyFname, err.Error())
+		return fmt.Errorf("unable to write to file: %s: %s", seg.bodyFname, err.Error())
 	}
+	seg.add(seqNum, len(msg))
 	if store.fileSync {
 		return store.syncBodyAndHeaderFilesLocked()
 	}
@@ -343,10 +419,11 @@ func (store *fileStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []
 }
 
 func (store *fileStore) syncBodyAndHeaderFilesLocked() error {
+	seg := store.activeSegment()
 	if err := store.bodyFile.Sync(); err != nil {
-		return fmt.Errorf("unable to flush file: %s: %s", store.bodyFname, err.Error())
+		return fmt.Errorf("unable to flush file: %s: %s", seg.bodyFname, err.Error())
 	} else if err = store.headerFile.Sync(); err != nil {
-		return fmt.Errorf("unable to flush file: %s: %s", store.headerFname, err.Error())
+		return fmt.Errorf("unable to flush file: %s: %s", seg.headerFname, err.Error())
 	}
 	return nil
 }
@@ -355,24 +432,41 @@ func (store *fileStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]by
 	// Sync files
 	store.fileMu.Lock()
 	err := store.syncBodyAndHeaderFilesLocked()
+	segments := make([]segment, 0, len(store.segments))
+	for _, seg := range store.segments {
+		segments = append(segments, *seg)
+	}
 	store.fileMu.Unlock()
 	if err != nil {
 		return err
 	}
 
+	for _, seg := range segments {
+		if seg.maxSeqNum < beginSeqNum {
+			continue
+		}
+		if done, err := iterateSegment(&seg, beginSeqNum, endSeqNum, cb); err != nil || done {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterateSegment calls cb with the messages of seg from beginSeqNum, reporting done once past endSeqNum.
+func iterateSegment(seg *segment, beginSeqNum, endSeqNum int, cb func([]byte) error) (done bool, err error) {
 	// Open a read only view to body and header file
-	bodyFile, err := openOrCreateFile(store.bodyFname, 0440)
+	bodyFile, err := openOrCreateFile(seg.bodyFname, 0440)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer func() { _ = bodyFile.Close() }()
-	headerFile, err := openOrCreateFile(store.headerFname, 0440)
+	headerFile, err := openOrCreateFile(seg.headerFname, 0440)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer func() { _ = headerFile.Close() }()
 	if _, err = headerFile.Seek(0, io.SeekStart); err != nil {
-		return fmt.Errorf("unable to seek to start of file: %s: %s", store.headerFname, err.Error())
+		return false, fmt.Errorf("unable to seek to start of file: %s: %s", seg.headerFname, err.Error())
 	}
 
 	// Iterate over the header file
@@ -381,12 +475,12 @@ func (store *fileStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]by
 		var offset int64
 		if cnt, err := fmt.Fscanf(headerFile, "%d,%d,%d\n", &seqNum, &offset, &size); err != nil {
 			if errors.Is(err, io.EOF) {
-				break
+				return false, nil
 			}
-			return fmt.Errorf("unable to read from file: %s: %s", store.headerFname, err.Error())
+			return false, fmt.Errorf("unable to read from file: %s: %s", seg.headerFname, err.Error())
 		} else if cnt < 3 || seqNum > endSeqNum {
 			// If we have reached the end of possible iteration then break
-			break
+			return true, nil
 		} else if seqNum < beginSeqNum {
 			// If we have not yet reached the starting sequence number then continue
 			continue
@@ -394,12 +488,13 @@ func (store *fileStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]by
 		// Otherwise process the file
 		msg := make([]byte, size)
 		if _, err := bodyFile.ReadAt(msg, offset); err != nil {
-			return fmt.Errorf("unable to read from file: %s: %s", store.bodyFname, err.Error())
+			return false, fmt.Errorf("unable to read from file: %s: %s", seg.bodyFname, err.Error())
+		} else if msg, err = compression.Decompress(msg); err != nil {
+			return false, errors.Wrap(err, "decompress message")
 		} else if err = cb(msg); err != nil {
-			return err
+			return false, err
 		}
 	}
-	return nil
 }
 
 func (store *fileStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal/compression"
 	"github.com/quickfixgo/quickfix/internal/testsuite"
 	assert2 "github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -67,6 +68,209 @@ func TestFileStoreTestSuite(t *testing.T) {
 	suite.Run(t, new(FileStoreTestSuite))
 }
 
+// FileStoreCompressionTestSuite runs all tests in the MessageStoreTestSuite against a FileStore compressing messages.
+type FileStoreCompressionTestSuite struct {
+	FileStoreTestSuite
+}
+
+func (suite *FileStoreCompressionTestSuite) SetupTest() {
+	suite.fileStoreRootPath = path.Join(os.TempDir(), fmt.Sprintf("FileStoreCompressionTestSuite-%d", os.Getpid()))
+	fileStorePath := path.Join(suite.fileStoreRootPath, fmt.Sprintf("%d", time.Now().UnixNano()))
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	// create settings
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+FileStorePath=%s
+MessageStoreCompression=zstd
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, fileStorePath, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	// create store
+	suite.MsgStore, err = NewStoreFactory(settings).Create(sessionID)
+	require.Nil(suite.T(), err)
+}
+
+func TestFileStoreCompressionTestSuite(t *testing.T) {
+	suite.Run(t, new(FileStoreCompressionTestSuite))
+}
+
+// FileStoreSegmentTestSuite runs all tests in the MessageStoreTestSuite against a FileStore writing tiny segments.
+type FileStoreSegmentTestSuite struct {
+	FileStoreTestSuite
+}
+
+func (suite *FileStoreSegmentTestSuite) SetupTest() {
+	suite.fileStoreRootPath = path.Join(os.TempDir(), fmt.Sprintf("FileStoreSegmentTestSuite-%d", os.Getpid()))
+	fileStorePath := path.Join(suite.fileStoreRootPath, fmt.Sprintf("%d", time.Now().UnixNano()))
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	var err error
+	suite.MsgStore, err = newFileStore(sessionID, fileStorePath, false, compression.None, 16)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *FileStoreSegmentTestSuite) TestSegmentsRotate() {
+	store := suite.MsgStore.(*fileStore)
+	for seqNum := 1; seqNum <= 4; seqNum++ {
+		require.Nil(suite.T(), store.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, []byte(fmt.Sprintf("message %d", seqNum))))
+	}
+	suite.Len(store.segments, 4)
+
+	// Segments are discovered again when the store is reopened.
+	require.Nil(suite.T(), store.Refresh())
+	suite.Len(store.segments, 4)
+	suite.Equal(5, store.NextSenderMsgSeqNum())
+	msgs, err := store.GetMessages(2, 3)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{[]byte("message 2"), []byte("message 3")}, msgs)
+}
+
+func (suite *FileStoreSegmentTestSuite) TestPruneMessages() {
+	store := suite.MsgStore.(*fileStore)
+	for seqNum := 1; seqNum <= 4; seqNum++ {
+		require.Nil(suite.T(), store.SaveMessage(seqNum, []byte(fmt.Sprintf("message %d", seqNum))))
+	}
+
+	require.Nil(suite.T(), store.PruneMessages(3))
+	suite.Len(store.segments, 2)
+	_, err := os.Stat(store.bodyFname)
+	suite.True(os.IsNotExist(err))
+
+	msgs, err := store.GetMessages(1, 4)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{[]byte("message 3"), []byte("message 4")}, msgs)
+
+	// New messages are still written after pruning.
+	require.Nil(suite.T(), store.SaveMessage(5, []byte("message 5")))
+	msgs, err = store.GetMessages(5, 5)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{[]byte("message 5")}, msgs)
+}
+
+func (suite *FileStoreSegmentTestSuite) TestCompact() {
+	store := suite.MsgStore.(*fileStore)
+	require.Nil(suite.T(), store.SaveMessage(1, []byte("first message 1")))
+	require.Nil(suite.T(), store.SaveMessage(2, []byte("first message 2")))
+	require.Nil(suite.T(), store.SaveMessage(1, []byte("second message 1")))
+	suite.Len(store.segments, 3)
+
+	store.compression = compression.Gzip
+	require.Nil(suite.T(), store.Compact())
+
+	// The superseded save of seqnum 1 is dropped and its segment removed.
+	suite.Len(store.segments, 2)
+	msgs, err := store.GetMessages(1, 2)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{[]byte("first message 2"), []byte("second message 1")}, msgs)
+
+	body, err := os.ReadFile(store.segments[0].bodyFname)
+	require.Nil(suite.T(), err)
+	suite.Equal([]byte{0x1f, 0x8b}, body[:2])
+}
+
+func (suite *FileStoreSegmentTestSuite) TestCompactCrashSafe() {
+	store := suite.MsgStore.(*fileStore)
+	require.Nil(suite.T(), store.SaveMessage(1, []byte("first message 1")))
+	require.Nil(suite.T(), store.SaveMessage(2, []byte("first message 2")))
+	require.Nil(suite.T(), store.SaveMessage(1, []byte("second message 1")))
+	replaced := *store.segments[0]
+
+	// A crash before the segments file is switched leaves files of the next generation, which are not read.
+	orphan := store.newSegment(replaced.index, replaced.generation+1)
+	require.Nil(suite.T(), os.WriteFile(orphan.bodyFname, []byte("orphan"), 0660))
+	require.Nil(suite.T(), os.WriteFile(orphan.headerFname, []byte("3,0,6\n"), 0660))
+	require.Nil(suite.T(), store.Refresh())
+	msgs, err := store.GetMessages(1, 3)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{[]byte("first message 1"), []byte("first message 2"), []byte("second message 1")}, msgs)
+
+	require.Nil(suite.T(), store.Compact())
+	suite.Equal(1, store.segments[0].index)
+	suite.Equal(1, store.segments[0].generation)
+	_, err = os.Stat(replaced.headerFname)
+	suite.True(os.IsNotExist(err))
+
+	// A crash after the switch leaves the files of the replaced segments, which are not read either.
+	require.Nil(suite.T(), os.WriteFile(replaced.bodyFname, []byte("first message 1"), 0660))
+	require.Nil(suite.T(), os.WriteFile(replaced.headerFname, []byte("1,0,15\n"), 0660))
+	require.Nil(suite.T(), store.Refresh())
+	msgs, err = store.GetMessages(1, 3)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{[]byte("first message 2"), []byte("second message 1")}, msgs)
+
+	// Reset removes the files of every generation.
+	require.Nil(suite.T(), store.Reset())
+	_, err = os.Stat(orphan.headerFname)
+	suite.True(os.IsNotExist(err))
+	_, err = os.Stat(store.segmentsFname)
+	suite.True(os.IsNotExist(err))
+}
+
+func TestFileStoreSegmentTestSuite(t *testing.T) {
+	suite.Run(t, new(FileStoreSegmentTestSuite))
+}
+
+func TestFileStoreCompressionChange(t *testing.T) {
+	fileStorePath := path.Join(os.TempDir(), fmt.Sprintf("FileStoreCompressionChange-%d", time.Now().UnixNano()))
+	defer os.RemoveAll(fileStorePath)
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	// Messages saved uncompressed, then with gzip, are all read back.
+	for i, alg := range []string{"none", "gzip"} {
+		store, err := newFileStoreWithCompression(sessionID, fileStorePath, alg)
+		require.Nil(t, err)
+		require.Nil(t, store.SaveMessage(i+1, []byte(fmt.Sprintf("message %d", i+1))))
+		require.Nil(t, store.Close())
+	}
+
+	store, err := newFileStoreWithCompression(sessionID, fileStorePath, "zstd")
+	require.Nil(t, err)
+	defer store.Close()
+	msgs, err := store.GetMessages(1, 2)
+	require.Nil(t, err)
+	assert2.Equal(t, [][]byte{[]byte("message 1"), []byte("message 2")}, msgs)
+}
+
+func TestFileStoreRecoversSeqNumAfterCrash(t *testing.T) {
+	fileStorePath := path.Join(os.TempDir(), fmt.Sprintf("FileStoreRecoversSeqNum-%d", time.Now().UnixNano()))
+	defer os.RemoveAll(fileStorePath)
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	store, err := newFileStoreWithCompression(sessionID, fileStorePath, "none")
+	require.Nil(t, err)
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("message 1")))
+
+	// Simulate a crash after the message is saved but before the seqnum is incremented.
+	require.Nil(t, store.SaveMessage(2, []byte("message 2")))
+	require.Nil(t, store.Close())
+
+	store, err = newFileStoreWithCompression(sessionID, fileStorePath, "none")
+	require.Nil(t, err)
+	defer store.Close()
+	assert2.Equal(t, 3, store.NextSenderMsgSeqNum())
+}
+
+func newFileStoreWithCompression(sessionID quickfix.SessionID, fileStorePath, alg string) (quickfix.MessageStore, error) {
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+FileStorePath=%s
+MessageStoreCompression=%s
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, fileStorePath, alg, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	if err != nil {
+		return nil, err
+	}
+	return NewStoreFactory(settings).Create(sessionID)
+}
+
 func TestStringParse(t *testing.T) {
 	assert := assert2.New(t)
 	i, err := strconv.Atoi(strings.Trim("00005\n", "\r\n"))
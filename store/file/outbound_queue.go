@@ -0,0 +1,100 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package file
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// QueueMessage appends msg to the outbound queue file. Each message is written after a line with the time it was
+// queued, in nanoseconds since the epoch, and its length.
+func (store *fileStore) QueueMessage(msg quickfix.QueuedMessage) error {
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+
+	f, err := os.OpenFile(store.queueFname, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0660)
+	if err != nil {
+		return fmt.Errorf("error opening or creating file: %s: %s", store.queueFname, err.Error())
+	}
+
+	if _, err = fmt.Fprintf(f, "%d,%d\n", msg.QueuedAt.UnixNano(), len(msg.Msg)); err == nil {
+		_, err = f.Write(msg.Msg)
+	}
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("unable to write to file: %s: %s", store.queueFname, err.Error())
+	}
+
+	if store.fileSync {
+		if err := f.Sync(); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("unable to flush file: %s: %s", store.queueFname, err.Error())
+		}
+	}
+	return f.Close()
+}
+
+// QueuedMessages returns the messages of the outbound queue file, oldest first. A message only partly written, by a
+// process stopped while queueing it, is ignored.
+func (store *fileStore) QueuedMessages() ([]quickfix.QueuedMessage, error) {
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+
+	f, err := os.Open(store.queueFname)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var msgs []quickfix.QueuedMessage
+	r := bufio.NewReader(f)
+	for {
+		var queuedAt int64
+		var size int
+		if _, err := fmt.Fscanf(r, "%d,%d\n", &queuedAt, &size); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return msgs, nil
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "read %v", store.queueFname)
+		}
+
+		msg := make([]byte, size)
+		if _, err := io.ReadFull(r, msg); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return msgs, nil
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "read %v", store.queueFname)
+		}
+
+		msgs = append(msgs, quickfix.QueuedMessage{QueuedAt: time.Unix(0, queuedAt), Msg: msg})
+	}
+}
+
+// ClearQueuedMessages removes the outbound queue file.
+func (store *fileStore) ClearQueuedMessages() error {
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+
+	return removeFile(store.queueFname)
+}
@@ -0,0 +1,420 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package file
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix/internal/compression"
+)
+
+// segment is a pair of body and header files holding part of the saved messages.
+// Segment 0 uses the body and header file names of an unsegmented store, so existing stores keep working
+// when segmentation is enabled. Later segments append their index to those names, and rewritten segments
+// their index and generation.
+//
+// The segments file lists the index and generation of each segment once the store has rotated or rewritten
+// segments. Rewrites write a new generation of files, switched to by a single rename of the segments file,
+// so that a crash leaves either all the old segments or all the rewritten ones in place.
+type segment struct {
+	index int
+
+	// generation is the number of times the segment was rewritten.
+	generation int
+
+	bodyFname   string
+	headerFname string
+
+	// minSeqNum and maxSeqNum are the lowest and highest seqnums in the segment, 0 if it is empty.
+	minSeqNum int
+	maxSeqNum int
+
+	// lastSeqNum is the seqnum of the last message written to the segment.
+	lastSeqNum int
+
+	// size is the size of the body file.
+	size int64
+}
+
+// headerEntry is one line of a header file, locating a message in the body file.
+type headerEntry struct {
+	seqNum int
+	offset int64
+	size   int
+}
+
+func (seg *segment) add(seqNum int, size int) {
+	if seg.minSeqNum == 0 || seqNum < seg.minSeqNum {
+		seg.minSeqNum = seqNum
+	}
+	if seqNum > seg.maxSeqNum {
+		seg.maxSeqNum = seqNum
+	}
+	seg.lastSeqNum = seqNum
+	seg.size += int64(size)
+}
+
+func (store *fileStore) newSegment(index, generation int) *segment {
+	switch {
+	case generation > 0:
+		return &segment{
+			index:       index,
+			generation:  generation,
+			bodyFname:   fmt.Sprintf("%s.%d.%d", store.bodyFname, index, generation),
+			headerFname: fmt.Sprintf("%s.%d.%d", store.headerFname, index, generation),
+		}
+	case index == 0:
+		return &segment{bodyFname: store.bodyFname, headerFname: store.headerFname}
+	}
+	return &segment{
+		index:       index,
+		bodyFname:   fmt.Sprintf("%s.%d", store.bodyFname, index),
+		headerFname: fmt.Sprintf("%s.%d", store.headerFname, index),
+	}
+}
+
+// activeSegment returns the segment new messages are written to.
+func (store *fileStore) activeSegment() *segment {
+	return store.segments[len(store.segments)-1]
+}
+
+// loadSegments reads the segments from the segments file, or discovers them on disk if there is none,
+// and indexes their seqnums.
+func (store *fileStore) loadSegments() error {
+	segments, err := store.readSegmentsFile()
+	if err != nil {
+		return err
+	} else if segments == nil {
+		if segments, err = store.discoverSegments(); err != nil {
+			return err
+		}
+	}
+
+	store.segments = segments
+	for _, seg := range store.segments {
+		entries, err := readHeaderFile(seg.headerFname)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			seg.add(entry.seqNum, 0)
+		}
+		if info, err := os.Stat(seg.bodyFname); err == nil {
+			seg.size = info.Size()
+		}
+	}
+	return nil
+}
+
+// discoverSegments returns the segments of a store without a segments file, from the names of its header files.
+func (store *fileStore) discoverSegments() ([]*segment, error) {
+	indexes := []int{}
+	if _, err := os.Stat(store.headerFname); err == nil {
+		indexes = append(indexes, 0)
+	}
+	fnames, err := filepath.Glob(store.headerFname + ".*")
+	if err != nil {
+		return nil, err
+	}
+	for _, fname := range fnames {
+		if index, err := strconv.Atoi(strings.TrimPrefix(fname, store.headerFname+".")); err == nil && index > 0 {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+	if len(indexes) == 0 {
+		indexes = append(indexes, 0)
+	}
+
+	segments := make([]*segment, 0, len(indexes))
+	for _, index := range indexes {
+		segments = append(segments, store.newSegment(index, 0))
+	}
+	return segments, nil
+}
+
+// readSegmentsFile returns the segments listed in the segments file, or nil if there is none.
+func (store *fileStore) readSegmentsFile() ([]*segment, error) {
+	segmentsFile, err := os.Open(store.segmentsFname)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer func() { _ = segmentsFile.Close() }()
+
+	var segments []*segment
+	for {
+		var index, generation int
+		if cnt, err := fmt.Fscanf(segmentsFile, "%d,%d\n", &index, &generation); err != nil || cnt < 2 {
+			break
+		}
+		segments = append(segments, store.newSegment(index, generation))
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no segments in file: %s", store.segmentsFname)
+	}
+	return segments, nil
+}
+
+// writeSegmentsFile replaces the segments file with one listing segments.
+func (store *fileStore) writeSegmentsFile(segments []*segment) error {
+	tmpFname := store.segmentsFname + ".tmp"
+	tmpFile, err := os.OpenFile(tmpFname, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0660)
+	if err != nil {
+		return err
+	}
+	for _, seg := range segments {
+		if _, err := fmt.Fprintf(tmpFile, "%d,%d\n", seg.index, seg.generation); err != nil {
+			_ = tmpFile.Close()
+			return fmt.Errorf("unable to write to file: %s: %s", tmpFname, err.Error())
+		}
+	}
+	if err := closeSyncFile(tmpFile); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFname, store.segmentsFname); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(store.segmentsFname))
+}
+
+// readHeaderFile reads the entries of a header file, stopping at a partially written last line.
+func readHeaderFile(fname string) ([]headerEntry, error) {
+	headerFile, err := os.Open(fname)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer func() { _ = headerFile.Close() }()
+
+	var entries []headerEntry
+	for {
+		var entry headerEntry
+		if cnt, err := fmt.Fscanf(headerFile, "%d,%d,%d\n", &entry.seqNum, &entry.offset, &entry.size); err != nil || cnt < 3 {
+			return entries, nil
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// rotateSegmentLocked closes the active segment and starts writing to a new one.
+func (store *fileStore) rotateSegmentLocked() (err error) {
+	if err = store.closeSegmentFilesLocked(); err != nil {
+		return err
+	}
+	seg := store.newSegment(store.activeSegment().index+1, 0)
+	segments := append(append(make([]*segment, 0, len(store.segments)+1), store.segments...), seg)
+	if err = store.writeSegmentsFile(segments); err != nil {
+		_ = store.openSegmentFilesLocked()
+		return err
+	}
+	store.segments = segments
+	return store.openSegmentFilesLocked()
+}
+
+func (store *fileStore) openSegmentFilesLocked() (err error) {
+	seg := store.activeSegment()
+	if store.bodyFile, err = openOrCreateFile(seg.bodyFname, 0660); err != nil {
+		return err
+	}
+	store.headerFile, err = openOrCreateFile(seg.headerFname, 0660)
+	return err
+}
+
+func (store *fileStore) closeSegmentFilesLocked() error {
+	if err := closeSyncFile(store.bodyFile); err != nil {
+		return err
+	}
+	if err := closeSyncFile(store.headerFile); err != nil {
+		return err
+	}
+	store.bodyFile = nil
+	store.headerFile = nil
+	return nil
+}
+
+// PruneMessages deletes the saved messages with a sequence number below beforeSeqNum.
+// Segments holding only such messages are removed, others are rewritten without them.
+func (store *fileStore) PruneMessages(beforeSeqNum int) error {
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+
+	return store.rewriteSegmentsLocked(func(_, _ int, entry headerEntry) bool {
+		return entry.seqNum >= beforeSeqNum
+	}, false)
+}
+
+// Compact rewrites the segments without messages superseded by a later save of the same seqnum,
+// compressing the remaining messages with the configured algorithm, and removes segments left empty.
+func (store *fileStore) Compact() error {
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+
+	if err := store.syncBodyAndHeaderFilesLocked(); err != nil {
+		return err
+	}
+
+	// The latest save of each seqnum, by segment position and then header line.
+	type location struct{ segment, line int }
+	latest := make(map[int]location)
+	for i, seg := range store.segments {
+		entries, err := readHeaderFile(seg.headerFname)
+		if err != nil {
+			return err
+		}
+		for line, entry := range entries {
+			latest[entry.seqNum] = location{i, line}
+		}
+	}
+
+	return store.rewriteSegmentsLocked(func(pos, line int, entry headerEntry) bool {
+		return latest[entry.seqNum] == location{pos, line}
+	}, true)
+}
+
+// rewriteSegmentsLocked rewrites every segment keeping only the messages accepted by keep, which is called
+// with the position of the segment and of the message in the segment header. Segments left empty are
+// removed, the active one is recreated empty. The files of the replaced segments are removed once the
+// segments file lists the rewritten ones.
+func (store *fileStore) rewriteSegmentsLocked(keep func(pos, line int, entry headerEntry) bool, recompress bool) error {
+	if err := store.closeSegmentFilesLocked(); err != nil {
+		return err
+	}
+
+	var created, replaced []string
+	abort := func(err error) error {
+		for _, fname := range created {
+			_ = removeFile(fname)
+		}
+		_ = store.openSegmentFilesLocked()
+		return err
+	}
+
+	segments := make([]*segment, 0, len(store.segments))
+	for i, seg := range store.segments {
+		active := i == len(store.segments)-1
+		rewritten, err := store.rewriteSegment(seg, func(line int, entry headerEntry) bool {
+			return keep(i, line, entry)
+		}, recompress)
+		if err != nil {
+			return abort(err)
+		}
+		if rewritten != seg {
+			replaced = append(replaced, seg.bodyFname, seg.headerFname)
+			created = append(created, rewritten.bodyFname, rewritten.headerFname)
+		}
+		if rewritten.maxSeqNum == 0 && !active {
+			if rewritten == seg {
+				replaced = append(replaced, seg.bodyFname, seg.headerFname)
+			}
+			continue
+		}
+		segments = append(segments, rewritten)
+	}
+	if len(replaced) == 0 {
+		return store.openSegmentFilesLocked()
+	}
+	if err := store.writeSegmentsFile(segments); err != nil {
+		return abort(err)
+	}
+	store.segments = segments
+
+	for _, fname := range replaced {
+		if err := removeFile(fname); err != nil {
+			return err
+		}
+	}
+	return store.openSegmentFilesLocked()
+}
+
+// rewriteSegment writes the messages of seg accepted by keep to the files of its next generation,
+// and returns seg itself if it is left unchanged.
+func (store *fileStore) rewriteSegment(seg *segment, keep func(line int, entry headerEntry) bool, recompress bool) (*segment, error) {
+	entries, err := readHeaderFile(seg.headerFname)
+	if err != nil {
+		return nil, err
+	}
+	kept := make([]headerEntry, 0, len(entries))
+	for line, entry := range entries {
+		if keep(line, entry) {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) == len(entries) && !recompress {
+		return seg, nil
+	}
+
+	rewritten := store.newSegment(seg.index, seg.generation+1)
+	if len(kept) == 0 {
+		return rewritten, nil
+	}
+
+	bodyFile, err := os.Open(seg.bodyFname)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = bodyFile.Close() }()
+
+	newBodyFile, err := os.OpenFile(rewritten.bodyFname, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0660)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = newBodyFile.Close() }()
+	newHeaderFile, err := os.OpenFile(rewritten.headerFname, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0660)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = newHeaderFile.Close() }()
+
+	for _, entry := range kept {
+		msg := make([]byte, entry.size)
+		if _, err := bodyFile.ReadAt(msg, entry.offset); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("unable to read from file: %s: %s", seg.bodyFname, err.Error())
+		}
+		if recompress {
+			if msg, err = compression.Decompress(msg); err != nil {
+				return nil, errors.Wrap(err, "decompress message")
+			} else if msg, err = store.compression.Compress(msg); err != nil {
+				return nil, errors.Wrap(err, "compress message")
+			}
+		}
+		if _, err := fmt.Fprintf(newHeaderFile, "%d,%d,%d\n", entry.seqNum, rewritten.size, len(msg)); err != nil {
+			return nil, fmt.Errorf("unable to write to file: %s: %s", rewritten.headerFname, err.Error())
+		}
+		if _, err := newBodyFile.Write(msg); err != nil {
+			return nil, fmt.Errorf("unable to write to file: %s: %s", rewritten.bodyFname, err.Error())
+		}
+		rewritten.add(entry.seqNum, len(msg))
+	}
+
+	if err := newBodyFile.Sync(); err != nil {
+		return nil, err
+	}
+	if err := newHeaderFile.Sync(); err != nil {
+		return nil, err
+	}
+	return rewritten, nil
+}
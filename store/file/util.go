@@ -73,6 +73,16 @@ func removeFile(fname string) error {
 	return nil
 }
 
+// syncDir flushes the entries of a directory, so that the renames in it survive a crash.
+func syncDir(dirname string) error {
+	dir, err := os.Open(dirname)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dir.Close() }()
+	return dir.Sync()
+}
+
 // openOrCreateFile opens a file for reading and writing, creating it if necessary.
 func openOrCreateFile(fname string, perm os.FileMode) (f *os.File, err error) {
 	if f, err = os.OpenFile(fname, os.O_RDWR, perm); err != nil {
@@ -0,0 +1,148 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Operations reported to StoreInstrumentation.ObserveError.
+const (
+	OperationSave    = "save"
+	OperationReplay  = "replay"
+	OperationSeqNum  = "seqnum"
+	OperationReset   = "reset"
+	OperationRefresh = "refresh"
+	OperationClose   = "close"
+)
+
+// StoreInstrumentation receives measurements of MessageStore operations, labelled with the store backend.
+type StoreInstrumentation interface {
+	// ObserveSave is called after a message is saved.
+	ObserveSave(backend string, duration time.Duration)
+
+	// ObserveReplay is called after messages are read back for a resend.
+	ObserveReplay(backend string, duration time.Duration, messages int)
+
+	// ObserveError is called when a store operation fails.
+	ObserveError(backend, operation string, err error)
+}
+
+type metricsStoreFactory struct {
+	inner           quickfix.MessageStoreFactory
+	backend         string
+	instrumentation StoreInstrumentation
+}
+
+// NewStoreFactory returns a MessageStoreFactory whose stores report the operations of the stores created by
+// inner to instrumentation, labelled with backend, e.g. "sql" or "file".
+func NewStoreFactory(inner quickfix.MessageStoreFactory, backend string, instrumentation StoreInstrumentation) quickfix.MessageStoreFactory {
+	return metricsStoreFactory{inner: inner, backend: backend, instrumentation: instrumentation}
+}
+
+// Create creates a new instrumented MessageStore wrapping a store created by the inner factory.
+func (f metricsStoreFactory) Create(sessionID quickfix.SessionID) (quickfix.MessageStore, error) {
+	inner, err := f.inner.Create(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsStore{MessageStore: inner, backend: f.backend, instrumentation: f.instrumentation}, nil
+}
+
+// metricsStore measures the operations of the embedded store. Reads of cached state are not measured.
+type metricsStore struct {
+	quickfix.MessageStore
+	backend         string
+	instrumentation StoreInstrumentation
+}
+
+func (store *metricsStore) observeError(operation string, err error) error {
+	if err != nil {
+		store.instrumentation.ObserveError(store.backend, operation, err)
+	}
+	return err
+}
+
+func (store *metricsStore) observeSave(start time.Time, err error) error {
+	if err != nil {
+		return store.observeError(OperationSave, err)
+	}
+	store.instrumentation.ObserveSave(store.backend, time.Since(start))
+	return nil
+}
+
+func (store *metricsStore) SaveMessage(seqNum int, msg []byte) error {
+	start := time.Now()
+	return store.observeSave(start, store.MessageStore.SaveMessage(seqNum, msg))
+}
+
+func (store *metricsStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	start := time.Now()
+	return store.observeSave(start, store.MessageStore.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, msg))
+}
+
+func (store *metricsStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	start := time.Now()
+	messages := 0
+	err := store.MessageStore.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
+		messages++
+		return cb(msg)
+	})
+	if err != nil {
+		return store.observeError(OperationReplay, err)
+	}
+	store.instrumentation.ObserveReplay(store.backend, time.Since(start), messages)
+	return nil
+}
+
+func (store *metricsStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	var msgs [][]byte
+	err := store.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	return msgs, err
+}
+
+func (store *metricsStore) IncrNextSenderMsgSeqNum() error {
+	return store.observeError(OperationSeqNum, store.MessageStore.IncrNextSenderMsgSeqNum())
+}
+
+func (store *metricsStore) IncrNextTargetMsgSeqNum() error {
+	return store.observeError(OperationSeqNum, store.MessageStore.IncrNextTargetMsgSeqNum())
+}
+
+func (store *metricsStore) SetNextSenderMsgSeqNum(next int) error {
+	return store.observeError(OperationSeqNum, store.MessageStore.SetNextSenderMsgSeqNum(next))
+}
+
+func (store *metricsStore) SetNextTargetMsgSeqNum(next int) error {
+	return store.observeError(OperationSeqNum, store.MessageStore.SetNextTargetMsgSeqNum(next))
+}
+
+func (store *metricsStore) Reset() error {
+	return store.observeError(OperationReset, store.MessageStore.Reset())
+}
+
+func (store *metricsStore) Refresh() error {
+	return store.observeError(OperationRefresh, store.MessageStore.Refresh())
+}
+
+func (store *metricsStore) Close() error {
+	return store.observeError(OperationClose, store.MessageStore.Close())
+}
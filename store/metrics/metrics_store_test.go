@@ -0,0 +1,127 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal/testsuite"
+)
+
+type recordingInstrumentation struct {
+	saves    int
+	replays  int
+	replayed int
+	errors   map[string]int
+}
+
+func (r *recordingInstrumentation) ObserveSave(_ string, _ time.Duration) {
+	r.saves++
+}
+
+func (r *recordingInstrumentation) ObserveReplay(_ string, _ time.Duration, messages int) {
+	r.replays++
+	r.replayed += messages
+}
+
+func (r *recordingInstrumentation) ObserveError(_, operation string, _ error) {
+	if r.errors == nil {
+		r.errors = make(map[string]int)
+	}
+	r.errors[operation]++
+}
+
+// MetricsStoreTestSuite runs all tests in the message.StoreTestSuite against the instrumented store.
+type MetricsStoreTestSuite struct {
+	testsuite.StoreTestSuite
+}
+
+func (suite *MetricsStoreTestSuite) SetupTest() {
+	var err error
+	factory := NewStoreFactory(quickfix.NewMemoryStoreFactory(), "memory", &recordingInstrumentation{})
+	suite.MsgStore, err = factory.Create(quickfix.SessionID{})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *MetricsStoreTestSuite) TearDownTest() {
+	if suite.MsgStore != nil {
+		suite.MsgStore.Close()
+	}
+}
+
+func TestMetricsStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsStoreTestSuite))
+}
+
+func TestMetricsStoreObservations(t *testing.T) {
+	inst := &recordingInstrumentation{}
+	store, err := NewStoreFactory(quickfix.NewMemoryStoreFactory(), "memory", inst).Create(quickfix.SessionID{})
+	require.Nil(t, err)
+
+	require.Nil(t, store.SaveMessage(1, []byte("msg1")))
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("msg2")))
+	msgs, err := store.GetMessages(1, 2)
+	require.Nil(t, err)
+	assert.Len(t, msgs, 2)
+
+	assert.Equal(t, 2, inst.saves)
+	assert.Equal(t, 1, inst.replays)
+	assert.Equal(t, 2, inst.replayed)
+	assert.Empty(t, inst.errors)
+}
+
+type failingStore struct {
+	quickfix.MessageStore
+}
+
+var errFailing = errors.New("failing")
+
+func (failingStore) SaveMessage(int, []byte) error { return errFailing }
+
+func (failingStore) IterateMessages(int, int, func([]byte) error) error { return errFailing }
+
+func (failingStore) Reset() error { return errFailing }
+
+type storeFactoryFunc func(quickfix.SessionID) (quickfix.MessageStore, error)
+
+func (f storeFactoryFunc) Create(sessionID quickfix.SessionID) (quickfix.MessageStore, error) {
+	return f(sessionID)
+}
+
+func TestMetricsStoreErrors(t *testing.T) {
+	inst := &recordingInstrumentation{}
+	factory := NewStoreFactory(storeFactoryFunc(func(quickfix.SessionID) (quickfix.MessageStore, error) {
+		return failingStore{}, nil
+	}), "failing", inst)
+	store, err := factory.Create(quickfix.SessionID{})
+	require.Nil(t, err)
+
+	assert.Equal(t, errFailing, store.SaveMessage(1, []byte("msg1")))
+	_, err = store.GetMessages(1, 1)
+	assert.Equal(t, errFailing, err)
+	assert.Equal(t, errFailing, store.Reset())
+
+	assert.Equal(t, 0, inst.saves)
+	assert.Equal(t, 0, inst.replays)
+	assert.Equal(t, map[string]int{OperationSave: 1, OperationReplay: 1, OperationReset: 1}, inst.errors)
+}
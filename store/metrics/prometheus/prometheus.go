@@ -0,0 +1,86 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/quickfixgo/quickfix/store/metrics"
+)
+
+const namespace = "quickfix"
+
+type instrumentation struct {
+	saveDuration     *prometheus.HistogramVec
+	replayDuration   *prometheus.HistogramVec
+	replayedMessages *prometheus.CounterVec
+	errors           *prometheus.CounterVec
+}
+
+// NewInstrumentation returns a metrics.StoreInstrumentation exporting store metrics to Prometheus,
+// registering its collectors with registerer.
+func NewInstrumentation(registerer prometheus.Registerer) (metrics.StoreInstrumentation, error) {
+	i := &instrumentation{
+		saveDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "store",
+			Name:      "save_duration_seconds",
+			Help:      "Time taken to save a message.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 16),
+		}, []string{"backend"}),
+		replayDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "store",
+			Name:      "replay_duration_seconds",
+			Help:      "Time taken to read back messages for a resend.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16),
+		}, []string{"backend"}),
+		replayedMessages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "store",
+			Name:      "replayed_messages_total",
+			Help:      "Number of messages read back for resends.",
+		}, []string{"backend"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "store",
+			Name:      "errors_total",
+			Help:      "Number of failed store operations.",
+		}, []string{"backend", "operation"}),
+	}
+
+	for _, c := range []prometheus.Collector{i.saveDuration, i.replayDuration, i.replayedMessages, i.errors} {
+		if err := registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
+}
+
+func (i *instrumentation) ObserveSave(backend string, duration time.Duration) {
+	i.saveDuration.WithLabelValues(backend).Observe(duration.Seconds())
+}
+
+func (i *instrumentation) ObserveReplay(backend string, duration time.Duration, messages int) {
+	i.replayDuration.WithLabelValues(backend).Observe(duration.Seconds())
+	i.replayedMessages.WithLabelValues(backend).Add(float64(messages))
+}
+
+func (i *instrumentation) ObserveError(backend, operation string, _ error) {
+	i.errors.WithLabelValues(backend, operation).Inc()
+}
@@ -0,0 +1,54 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package prometheus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentation(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	inst, err := NewInstrumentation(reg)
+	require.Nil(t, err)
+
+	inst.ObserveSave("sql", time.Millisecond)
+	inst.ObserveReplay("sql", 10*time.Millisecond, 5)
+	inst.ObserveReplay("sql", 10*time.Millisecond, 3)
+	inst.ObserveError("sql", "save", errors.New("failed"))
+
+	i := inst.(*instrumentation)
+	assert.Equal(t, 8.0, testutil.ToFloat64(i.replayedMessages.WithLabelValues("sql")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(i.errors.WithLabelValues("sql", "save")))
+
+	count, err := testutil.GatherAndCount(reg, "quickfix_store_save_duration_seconds", "quickfix_store_replay_duration_seconds")
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestInstrumentationDuplicateRegistration(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	_, err := NewInstrumentation(reg)
+	require.Nil(t, err)
+	_, err = NewInstrumentation(reg)
+	assert.NotNil(t, err)
+}
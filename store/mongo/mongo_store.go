@@ -18,12 +18,16 @@ package mongo
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 
 	"github.com/quickfixgo/quickfix"
 	"github.com/quickfixgo/quickfix/config"
@@ -38,7 +42,6 @@ type mongoStoreFactory struct {
 type mongoStore struct {
 	sessionID          quickfix.SessionID
 	cache              quickfix.MessageStore
-	mongoURL           string
 	mongoDatabase      string
 	db                 *mongo.Client
 	messagesCollection string
@@ -85,10 +88,61 @@ func (f mongoStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickf
 	// Optional.
 	mongoReplicaSet, _ := sessionSettings.Setting(config.MongoStoreReplicaSet)
 
-	return newMongoStore(sessionID, mongoConnectionURL, mongoDatabase, mongoReplicaSet, f.messagesCollection, f.sessionsCollection)
+	clientOptions := options.Client().ApplyURI(mongoConnectionURL).SetDirect(len(mongoReplicaSet) == 0).SetReplicaSet(mongoReplicaSet)
+	if sessionSettings.HasSetting(config.MongoStoreWriteConcern) {
+		var w string
+		if w, err = sessionSettings.Setting(config.MongoStoreWriteConcern); err != nil {
+			return nil, err
+		}
+		var wc *writeconcern.WriteConcern
+		if wc, err = parseWriteConcern(w); err != nil {
+			return nil, quickfix.IncorrectFormatForSetting{Setting: config.MongoStoreWriteConcern, Value: []byte(w), Err: err}
+		}
+		clientOptions.SetWriteConcern(wc)
+	}
+	if sessionSettings.HasSetting(config.MongoStoreReadPreference) {
+		var mode string
+		if mode, err = sessionSettings.Setting(config.MongoStoreReadPreference); err != nil {
+			return nil, err
+		}
+		var rp *readpref.ReadPref
+		if rp, err = parseReadPreference(mode); err != nil {
+			return nil, quickfix.IncorrectFormatForSetting{Setting: config.MongoStoreReadPreference, Value: []byte(mode), Err: err}
+		}
+		clientOptions.SetReadPreference(rp)
+	}
+
+	return newMongoStore(sessionID, clientOptions, mongoDatabase, mongoReplicaSet, f.messagesCollection, f.sessionsCollection)
 }
 
-func newMongoStore(sessionID quickfix.SessionID, mongoURL, mongoDatabase, mongoReplicaSet, messagesCollection, sessionsCollection string) (store *mongoStore, err error) {
+// parseWriteConcern converts a MongoStoreWriteConcern value into a write concern.
+// The value is either "majority", a non-negative number of acknowledging nodes or a custom tag name.
+func parseWriteConcern(w string) (*writeconcern.WriteConcern, error) {
+	if w == "" {
+		return nil, errors.New("empty write concern")
+	}
+	if strings.EqualFold(w, "majority") {
+		return writeconcern.Majority(), nil
+	}
+	if n, err := strconv.Atoi(w); err == nil {
+		if n < 0 {
+			return nil, fmt.Errorf("negative write concern: %d", n)
+		}
+		return &writeconcern.WriteConcern{W: n}, nil
+	}
+	return writeconcern.Custom(w), nil
+}
+
+// parseReadPreference converts a MongoStoreReadPreference value into a read preference.
+func parseReadPreference(mode string) (*readpref.ReadPref, error) {
+	m, err := readpref.ModeFromString(mode)
+	if err != nil {
+		return nil, err
+	}
+	return readpref.New(m)
+}
+
+func newMongoStore(sessionID quickfix.SessionID, clientOptions *options.ClientOptions, mongoDatabase, mongoReplicaSet, messagesCollection, sessionsCollection string) (store *mongoStore, err error) {
 
 	memStore, memErr := quickfix.NewMemoryStoreFactory().Create(sessionID)
 	if memErr != nil {
@@ -100,7 +154,6 @@ func newMongoStore(sessionID quickfix.SessionID, mongoURL, mongoDatabase, mongoR
 	store = &mongoStore{
 		sessionID:          sessionID,
 		cache:              memStore,
-		mongoURL:           mongoURL,
 		mongoDatabase:      mongoDatabase,
 		messagesCollection: messagesCollection,
 		sessionsCollection: sessionsCollection,
@@ -114,7 +167,7 @@ func newMongoStore(sessionID quickfix.SessionID, mongoURL, mongoDatabase, mongoR
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	store.db, err = mongo.Connect(ctx, options.Client().ApplyURI(mongoURL).SetDirect(len(mongoReplicaSet) == 0).SetReplicaSet(mongoReplicaSet))
+	store.db, err = mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return
 	}
@@ -209,6 +262,9 @@ func (store *mongoStore) populateCache() error {
 			return errors.Wrap(err, "cache set next sender")
 		}
 
+		if !store.allowTransactions {
+			return store.recoverNextSenderMsgSeqNum()
+		}
 		return nil
 	}
 
@@ -223,6 +279,21 @@ func (store *mongoStore) populateCache() error {
 	return nil
 }
 
+// recoverNextSenderMsgSeqNum moves past a message saved at the next sender seqnum, which is left by a crash
+// between saving a message and incrementing the seqnum when they cannot be written in one transaction.
+func (store *mongoStore) recoverNextSenderMsgSeqNum() error {
+	next := store.cache.NextSenderMsgSeqNum()
+	msgFilter := generateMessageFilter(&store.sessionID)
+	msgFilter.Msgseq = next
+	res := store.db.Database(store.mongoDatabase).Collection(store.messagesCollection).FindOne(context.Background(), msgFilter)
+	if res.Err() == mongo.ErrNoDocuments {
+		return nil
+	} else if res.Err() != nil {
+		return errors.Wrap(res.Err(), "query")
+	}
+	return errors.Wrap(store.cache.SetNextSenderMsgSeqNum(next+1), "cache set next sender")
+}
+
 // NextSenderMsgSeqNum returns the next MsgSeqNum that will be sent.
 func (store *mongoStore) NextSenderMsgSeqNum() int {
 	return store.cache.NextSenderMsgSeqNum()
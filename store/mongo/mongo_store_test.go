@@ -26,6 +26,7 @@ import (
 	"github.com/quickfixgo/quickfix/internal/testsuite"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // MongoStoreTestSuite runs all tests in the message.StoreTestSuite against the MongoStore implementation.
@@ -73,3 +74,47 @@ func (suite *MongoStoreTestSuite) TearDownTest() {
 func TestMongoStoreTestSuite(t *testing.T) {
 	suite.Run(t, new(MongoStoreTestSuite))
 }
+
+func TestParseWriteConcern(t *testing.T) {
+	wc, err := parseWriteConcern("majority")
+	require.Nil(t, err)
+	require.Equal(t, "majority", wc.W)
+
+	wc, err = parseWriteConcern("2")
+	require.Nil(t, err)
+	require.Equal(t, 2, wc.W)
+
+	wc, err = parseWriteConcern("dc1")
+	require.Nil(t, err)
+	require.Equal(t, "dc1", wc.W)
+
+	_, err = parseWriteConcern("-1")
+	require.NotNil(t, err)
+}
+
+func TestParseReadPreference(t *testing.T) {
+	rp, err := parseReadPreference("secondaryPreferred")
+	require.Nil(t, err)
+	require.Equal(t, readpref.SecondaryPreferredMode, rp.Mode())
+
+	_, err = parseReadPreference("fastest")
+	require.NotNil(t, err)
+}
+
+func TestStoreFactoryInvalidReadPreference(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(`
+[DEFAULT]
+MongoStoreConnection=mongodb://localhost:27017
+MongoStoreDatabase=automated_testing_database
+MongoStoreReadPreference=fastest
+
+[SESSION]
+BeginString=FIX.4.4
+SenderCompID=SENDER
+TargetCompID=TARGET`))
+	require.Nil(t, err)
+
+	_, err = NewStoreFactory(settings).Create(sessionID)
+	require.IsType(t, quickfix.IncorrectFormatForSetting{}, err)
+}
@@ -0,0 +1,395 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+const (
+	defaultKeyPrefix = "quickfix:"
+
+	fieldCreationTime   = "creation_time"
+	fieldIncomingSeqNum = "incoming_seq_num"
+	fieldOutgoingSeqNum = "outgoing_seq_num"
+)
+
+type redisStoreFactory struct {
+	settings *quickfix.Settings
+}
+
+type redisStore struct {
+	sessionID   quickfix.SessionID
+	cache       quickfix.MessageStore
+	db          redis.UniversalClient
+	ttl         time.Duration
+	sessionKey  string
+	messagesKey string
+	seqNumsKey  string
+}
+
+// NewStoreFactory returns a redis-based implementation of MessageStoreFactory.
+func NewStoreFactory(settings *quickfix.Settings) quickfix.MessageStoreFactory {
+	return redisStoreFactory{settings: settings}
+}
+
+// Create creates a new RedisStore implementation of the MessageStore interface.
+func (f redisStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfix.MessageStore, err error) {
+	globalSettings := f.settings.GlobalSettings()
+	dynamicSessions, _ := globalSettings.BoolSetting(config.DynamicSessions)
+
+	sessionSettings, ok := f.settings.SessionSettings()[sessionID]
+	if !ok {
+		if dynamicSessions {
+			sessionSettings = globalSettings
+		} else {
+			return nil, fmt.Errorf("unknown session: %v", sessionID)
+		}
+	}
+	redisConnection, err := sessionSettings.Setting(config.RedisStoreConnection)
+	if err != nil {
+		return nil, err
+	}
+
+	// Optional.
+	keyPrefix := defaultKeyPrefix
+	if sessionSettings.HasSetting(config.RedisStoreKeyPrefix) {
+		if keyPrefix, err = sessionSettings.Setting(config.RedisStoreKeyPrefix); err != nil {
+			return nil, err
+		}
+	}
+	var ttl time.Duration
+	if sessionSettings.HasSetting(config.RedisStoreTTL) {
+		if ttl, err = sessionSettings.DurationSetting(config.RedisStoreTTL); err != nil {
+			return nil, err
+		}
+	}
+	masterName, _ := sessionSettings.Setting(config.RedisStoreSentinelMasterName)
+
+	db, err := newRedisClient(redisConnection, masterName)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRedisStore(sessionID, db, keyPrefix, ttl)
+}
+
+// newRedisClient builds a client for a single node, a Redis Cluster or a Sentinel-monitored master
+// depending on the form of the connection URL. Additional nodes are given with repeated addr query
+// parameters, e.g. redis://node1:6379?addr=node2:6379&addr=node3:6379.
+func newRedisClient(connection, masterName string) (redis.UniversalClient, error) {
+	u, err := url.Parse(connection)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse redis connection")
+	}
+
+	if masterName == "" && !u.Query().Has("addr") {
+		opts, err := redis.ParseURL(connection)
+		if err != nil {
+			return nil, err
+		}
+		return redis.NewClient(opts), nil
+	}
+
+	opts, err := redis.ParseClusterURL(connection)
+	if err != nil {
+		return nil, err
+	}
+	if masterName == "" {
+		return redis.NewClusterClient(opts), nil
+	}
+
+	return redis.NewFailoverClient(&redis.FailoverOptions{
+		MasterName:       masterName,
+		SentinelAddrs:    opts.Addrs,
+		SentinelUsername: opts.Username,
+		SentinelPassword: opts.Password,
+		Username:         opts.Username,
+		Password:         opts.Password,
+		DialTimeout:      opts.DialTimeout,
+		ReadTimeout:      opts.ReadTimeout,
+		WriteTimeout:     opts.WriteTimeout,
+		PoolSize:         opts.PoolSize,
+		TLSConfig:        opts.TLSConfig,
+	}), nil
+}
+
+func newRedisStore(sessionID quickfix.SessionID, db redis.UniversalClient, keyPrefix string, ttl time.Duration) (store *redisStore, err error) {
+	memStore, memErr := quickfix.NewMemoryStoreFactory().Create(sessionID)
+	if memErr != nil {
+		err = errors.Wrap(memErr, "cache creation")
+		return
+	}
+
+	// The hash tag keeps all of a session's keys in the same cluster slot so they can be updated in one transaction.
+	baseKey := keyPrefix + "{" + sessionID.String() + "}"
+	store = &redisStore{
+		sessionID:   sessionID,
+		cache:       memStore,
+		db:          db,
+		ttl:         ttl,
+		sessionKey:  baseKey + ":session",
+		messagesKey: baseKey + ":messages",
+		seqNumsKey:  baseKey + ":seqnums",
+	}
+
+	if err = store.cache.Reset(); err != nil {
+		err = errors.Wrap(err, "cache reset")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err = store.db.Ping(ctx).Err(); err != nil {
+		err = errors.Wrap(err, "ping")
+		return
+	}
+	err = store.populateCache()
+
+	return
+}
+
+// expire refreshes the TTL of the session's keys, if configured.
+func (store *redisStore) expire(ctx context.Context, pipe redis.Pipeliner) {
+	if store.ttl <= 0 {
+		return
+	}
+	pipe.Expire(ctx, store.sessionKey, store.ttl)
+	pipe.Expire(ctx, store.messagesKey, store.ttl)
+	pipe.Expire(ctx, store.seqNumsKey, store.ttl)
+}
+
+func (store *redisStore) saveSession(ctx context.Context, pipe redis.Pipeliner, incoming, outgoing int) {
+	pipe.HSet(ctx, store.sessionKey,
+		fieldCreationTime, store.cache.CreationTime().UTC().Format(time.RFC3339Nano),
+		fieldIncomingSeqNum, incoming,
+		fieldOutgoingSeqNum, outgoing,
+	)
+	store.expire(ctx, pipe)
+}
+
+// Reset deletes the store records and sets the seqnums back to 1.
+func (store *redisStore) Reset() error {
+	if err := store.cache.Reset(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	_, err := store.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, store.messagesKey, store.seqNumsKey)
+		store.saveSession(ctx, pipe, store.cache.NextTargetMsgSeqNum(), store.cache.NextSenderMsgSeqNum())
+		return nil
+	})
+	return err
+}
+
+// Refresh reloads the store from the database.
+func (store *redisStore) Refresh() error {
+	if err := store.cache.Reset(); err != nil {
+		return err
+	}
+	return store.populateCache()
+}
+
+func (store *redisStore) populateCache() error {
+	ctx := context.Background()
+	sessionData, err := store.db.HGetAll(ctx, store.sessionKey).Result()
+	if err != nil {
+		return errors.Wrap(err, "query")
+	}
+
+	if len(sessionData) == 0 {
+		// session record not found, create it
+		_, err = store.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			store.saveSession(ctx, pipe, store.cache.NextTargetMsgSeqNum(), store.cache.NextSenderMsgSeqNum())
+			return nil
+		})
+		return errors.Wrap(err, "insert")
+	}
+
+	creationTime, err := time.Parse(time.RFC3339Nano, sessionData[fieldCreationTime])
+	if err != nil {
+		return errors.Wrap(err, "parse creation time")
+	}
+	incomingSeqNum, err := strconv.Atoi(sessionData[fieldIncomingSeqNum])
+	if err != nil {
+		return errors.Wrap(err, "parse incoming seqnum")
+	}
+	outgoingSeqNum, err := strconv.Atoi(sessionData[fieldOutgoingSeqNum])
+	if err != nil {
+		return errors.Wrap(err, "parse outgoing seqnum")
+	}
+
+	store.cache.SetCreationTime(creationTime)
+	if err := store.cache.SetNextTargetMsgSeqNum(incomingSeqNum); err != nil {
+		return errors.Wrap(err, "cache set next target")
+	}
+
+	if err := store.cache.SetNextSenderMsgSeqNum(outgoingSeqNum); err != nil {
+		return errors.Wrap(err, "cache set next sender")
+	}
+
+	return nil
+}
+
+// NextSenderMsgSeqNum returns the next MsgSeqNum that will be sent.
+func (store *redisStore) NextSenderMsgSeqNum() int {
+	return store.cache.NextSenderMsgSeqNum()
+}
+
+// NextTargetMsgSeqNum returns the next MsgSeqNum that should be received.
+func (store *redisStore) NextTargetMsgSeqNum() int {
+	return store.cache.NextTargetMsgSeqNum()
+}
+
+// SetNextSenderMsgSeqNum sets the next MsgSeqNum that will be sent.
+func (store *redisStore) SetNextSenderMsgSeqNum(next int) error {
+	ctx := context.Background()
+	if _, err := store.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		store.saveSession(ctx, pipe, store.cache.NextTargetMsgSeqNum(), next)
+		return nil
+	}); err != nil {
+		return err
+	}
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+// SetNextTargetMsgSeqNum sets the next MsgSeqNum that should be received.
+func (store *redisStore) SetNextTargetMsgSeqNum(next int) error {
+	ctx := context.Background()
+	if _, err := store.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		store.saveSession(ctx, pipe, next, store.cache.NextSenderMsgSeqNum())
+		return nil
+	}); err != nil {
+		return err
+	}
+	return store.cache.SetNextTargetMsgSeqNum(next)
+}
+
+// IncrNextSenderMsgSeqNum increments the next MsgSeqNum that will be sent.
+func (store *redisStore) IncrNextSenderMsgSeqNum() error {
+	if err := store.SetNextSenderMsgSeqNum(store.cache.NextSenderMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "save sequence number")
+	}
+	return nil
+}
+
+// IncrNextTargetMsgSeqNum increments the next MsgSeqNum that should be received.
+func (store *redisStore) IncrNextTargetMsgSeqNum() error {
+	if err := store.SetNextTargetMsgSeqNum(store.cache.NextTargetMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "save sequence number")
+	}
+	return nil
+}
+
+// CreationTime returns the creation time of the store.
+func (store *redisStore) CreationTime() time.Time {
+	return store.cache.CreationTime()
+}
+
+// SetCreationTime is a no-op for RedisStore.
+func (store *redisStore) SetCreationTime(_ time.Time) {
+}
+
+func (store *redisStore) saveMessage(ctx context.Context, pipe redis.Pipeliner, seqNum int, msg []byte) {
+	pipe.HSet(ctx, store.messagesKey, strconv.Itoa(seqNum), msg)
+	pipe.ZAdd(ctx, store.seqNumsKey, redis.Z{Score: float64(seqNum), Member: seqNum})
+	store.expire(ctx, pipe)
+}
+
+func (store *redisStore) SaveMessage(seqNum int, msg []byte) error {
+	ctx := context.Background()
+	_, err := store.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		store.saveMessage(ctx, pipe, seqNum, msg)
+		return nil
+	})
+	return err
+}
+
+func (store *redisStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	ctx := context.Background()
+	next := store.cache.NextSenderMsgSeqNum() + 1
+	if _, err := store.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		store.saveMessage(ctx, pipe, seqNum, msg)
+		store.saveSession(ctx, pipe, store.cache.NextTargetMsgSeqNum(), next)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+func (store *redisStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	ctx := context.Background()
+	seqNums, err := store.db.ZRangeByScore(ctx, store.seqNumsKey, &redis.ZRangeBy{
+		Min: strconv.Itoa(beginSeqNum),
+		Max: strconv.Itoa(endSeqNum),
+	}).Result()
+	if err != nil {
+		return err
+	}
+	if len(seqNums) == 0 {
+		return nil
+	}
+
+	msgs, err := store.db.HMGet(ctx, store.messagesKey, seqNums...).Result()
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		s, ok := msg.(string)
+		if !ok {
+			// The message body has expired or been removed since the index was read.
+			continue
+		}
+		if err = cb([]byte(s)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *redisStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	var msgs [][]byte
+	err := store.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	return msgs, err
+}
+
+// Close closes the store's database connection.
+func (store *redisStore) Close() error {
+	if store.db != nil {
+		err := store.db.Close()
+		if err != nil {
+			return errors.Wrap(err, "error disconnecting from database")
+		}
+		store.db = nil
+	}
+	return nil
+}
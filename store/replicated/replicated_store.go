@@ -0,0 +1,317 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package replicated
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Mode is how writes are mirrored to the secondary store.
+type Mode int
+
+const (
+	// Sync writes to the secondary store before returning. If the primary store fails a write that the
+	// secondary store accepts, the secondary store is promoted and used from then on, unless it is stale.
+	Sync Mode = iota
+
+	// Async queues writes to the secondary store and returns once the primary store has them.
+	// The secondary store is only promoted by calling Promote.
+	Async
+)
+
+// asyncQueueSize is the number of writes queued for the secondary store before writers block.
+const asyncQueueSize = 4096
+
+// ErrSecondaryBehind is returned by Promote when the secondary store is stale, having failed to apply a replicated write.
+var ErrSecondaryBehind = errors.New("secondary store is behind the primary store")
+
+type replicatedStoreFactory struct {
+	primary   quickfix.MessageStoreFactory
+	secondary quickfix.MessageStoreFactory
+	mode      Mode
+}
+
+// NewStoreFactory returns a MessageStoreFactory whose stores mirror every write to a store created by
+// primary to a standby store created by secondary, which can be promoted if the primary store fails.
+func NewStoreFactory(primary, secondary quickfix.MessageStoreFactory, mode Mode) quickfix.MessageStoreFactory {
+	return replicatedStoreFactory{primary: primary, secondary: secondary, mode: mode}
+}
+
+// Create creates a new replicated MessageStore from the primary and secondary factories.
+func (f replicatedStoreFactory) Create(sessionID quickfix.SessionID) (quickfix.MessageStore, error) {
+	primary, err := f.primary.Create(sessionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "primary")
+	}
+	secondary, err := f.secondary.Create(sessionID)
+	if err != nil {
+		_ = primary.Close()
+		return nil, errors.Wrap(err, "secondary")
+	}
+
+	store := &replicatedStore{primary: primary, secondary: secondary, mode: f.mode}
+	if f.mode == Async {
+		store.queue = make(chan func(quickfix.MessageStore) error, asyncQueueSize)
+		store.done = make(chan struct{})
+		go store.replicate()
+	}
+	return store, nil
+}
+
+type replicatedStore struct {
+	mu        sync.Mutex
+	primary   quickfix.MessageStore
+	secondary quickfix.MessageStore
+	mode      Mode
+	promoted  bool
+
+	// queue holds writes waiting to be applied to the secondary store in Async mode.
+	queue   chan func(quickfix.MessageStore) error
+	done    chan struct{}
+	pending sync.WaitGroup
+
+	// err is the write the secondary store failed, after which it is stale and no longer written to.
+	errMu sync.Mutex
+	err   error
+}
+
+func (store *replicatedStore) replicate() {
+	defer close(store.done)
+	for op := range store.queue {
+		store.replicateTo(op)
+		store.pending.Done()
+	}
+}
+
+// stale returns the write the secondary store failed, or nil if it has every write of the primary store.
+func (store *replicatedStore) stale() error {
+	store.errMu.Lock()
+	defer store.errMu.Unlock()
+	return store.err
+}
+
+// replicateTo applies op to the secondary store, and marks it stale if op fails.
+// Once a write is lost, later ones would leave the secondary store inconsistent, so a stale store is skipped.
+func (store *replicatedStore) replicateTo(op func(quickfix.MessageStore) error) {
+	if store.stale() != nil {
+		return
+	}
+	if err := op(store.secondary); err != nil {
+		store.errMu.Lock()
+		store.err = err
+		store.errMu.Unlock()
+	}
+}
+
+// active returns the store serving reads.
+func (store *replicatedStore) active() quickfix.MessageStore {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.promoted {
+		return store.secondary
+	}
+	return store.primary
+}
+
+// write applies op to the active store. Once the primary store has the write, the state it leaves is copied to the
+// secondary store by the op returned by replicate, read from the primary store so that a secondary store which
+// applied a write differently is corrected by the next one. A nil replicate copies op itself, for writes of
+// absolute state.
+func (store *replicatedStore) write(op func(quickfix.MessageStore) error, replicate func() func(quickfix.MessageStore) error) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.promoted {
+		return op(store.secondary)
+	}
+
+	if err := op(store.primary); err != nil {
+		// In Sync mode a secondary store that is not stale has the state of the primary store before op.
+		if store.mode == Sync && store.stale() == nil && op(store.secondary) == nil {
+			store.promoted = true
+			return nil
+		}
+		return err
+	}
+
+	copyState := op
+	if replicate != nil {
+		copyState = replicate()
+	}
+	if store.mode == Async {
+		if store.queue != nil {
+			store.pending.Add(1)
+			store.queue <- copyState
+		}
+		return nil
+	}
+
+	store.replicateTo(copyState)
+	return nil
+}
+
+// Promote makes the secondary store serve all reads and writes. In Async mode it waits for queued writes
+// to be applied first. It fails with ErrSecondaryBehind if the secondary store is stale.
+func (store *replicatedStore) Promote() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.promoted {
+		return nil
+	}
+	if store.mode == Async {
+		store.pending.Wait()
+	}
+	if err := store.stale(); err != nil {
+		return errors.Wrap(ErrSecondaryBehind, err.Error())
+	}
+	store.promoted = true
+	return nil
+}
+
+// Stale reports whether the secondary store failed a replicated write, and can no longer be promoted.
+func (store *replicatedStore) Stale() bool {
+	return store.stale() != nil
+}
+
+// Promoted reports whether the secondary store has been promoted.
+func (store *replicatedStore) Promoted() bool {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.promoted
+}
+
+// NextSenderMsgSeqNum returns the next MsgSeqNum that will be sent.
+func (store *replicatedStore) NextSenderMsgSeqNum() int {
+	return store.active().NextSenderMsgSeqNum()
+}
+
+// NextTargetMsgSeqNum returns the next MsgSeqNum that should be received.
+func (store *replicatedStore) NextTargetMsgSeqNum() int {
+	return store.active().NextTargetMsgSeqNum()
+}
+
+// copySenderMsgSeqNum returns a write setting the next MsgSeqNum that will be sent to that of the primary store.
+func (store *replicatedStore) copySenderMsgSeqNum() func(quickfix.MessageStore) error {
+	next := store.primary.NextSenderMsgSeqNum()
+	return func(s quickfix.MessageStore) error { return s.SetNextSenderMsgSeqNum(next) }
+}
+
+// copyTargetMsgSeqNum returns a write setting the next MsgSeqNum that should be received to that of the primary store.
+func (store *replicatedStore) copyTargetMsgSeqNum() func(quickfix.MessageStore) error {
+	next := store.primary.NextTargetMsgSeqNum()
+	return func(s quickfix.MessageStore) error { return s.SetNextTargetMsgSeqNum(next) }
+}
+
+// IncrNextSenderMsgSeqNum increments the next MsgSeqNum that will be sent.
+func (store *replicatedStore) IncrNextSenderMsgSeqNum() error {
+	return store.write(func(s quickfix.MessageStore) error { return s.IncrNextSenderMsgSeqNum() }, store.copySenderMsgSeqNum)
+}
+
+// IncrNextTargetMsgSeqNum increments the next MsgSeqNum that should be received.
+func (store *replicatedStore) IncrNextTargetMsgSeqNum() error {
+	return store.write(func(s quickfix.MessageStore) error { return s.IncrNextTargetMsgSeqNum() }, store.copyTargetMsgSeqNum)
+}
+
+// SetNextSenderMsgSeqNum sets the next MsgSeqNum that will be sent.
+func (store *replicatedStore) SetNextSenderMsgSeqNum(next int) error {
+	return store.write(func(s quickfix.MessageStore) error { return s.SetNextSenderMsgSeqNum(next) }, store.copySenderMsgSeqNum)
+}
+
+// SetNextTargetMsgSeqNum sets the next MsgSeqNum that should be received.
+func (store *replicatedStore) SetNextTargetMsgSeqNum(next int) error {
+	return store.write(func(s quickfix.MessageStore) error { return s.SetNextTargetMsgSeqNum(next) }, store.copyTargetMsgSeqNum)
+}
+
+// CreationTime returns the creation time of the store.
+func (store *replicatedStore) CreationTime() time.Time {
+	return store.active().CreationTime()
+}
+
+// SetCreationTime sets the creation time of both stores.
+func (store *replicatedStore) SetCreationTime(t time.Time) {
+	_ = store.write(func(s quickfix.MessageStore) error {
+		s.SetCreationTime(t)
+		return nil
+	}, nil)
+}
+
+func (store *replicatedStore) SaveMessage(seqNum int, msg []byte) error {
+	return store.write(func(s quickfix.MessageStore) error { return s.SaveMessage(seqNum, msg) }, nil)
+}
+
+func (store *replicatedStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	return store.write(func(s quickfix.MessageStore) error { return s.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, msg) },
+		func() func(quickfix.MessageStore) error {
+			copySeqNum := store.copySenderMsgSeqNum()
+			return func(s quickfix.MessageStore) error {
+				if err := s.SaveMessage(seqNum, msg); err != nil {
+					return err
+				}
+				return copySeqNum(s)
+			}
+		})
+}
+
+func (store *replicatedStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	return store.active().GetMessages(beginSeqNum, endSeqNum)
+}
+
+func (store *replicatedStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	return store.active().IterateMessages(beginSeqNum, endSeqNum, cb)
+}
+
+// Refresh reloads the active store from its backing store.
+func (store *replicatedStore) Refresh() error {
+	return store.active().Refresh()
+}
+
+// Reset deletes the records of both stores and sets the seqnums back to 1.
+func (store *replicatedStore) Reset() error {
+	return store.write(func(s quickfix.MessageStore) error { return s.Reset() }, func() func(quickfix.MessageStore) error {
+		creationTime := store.primary.CreationTime()
+		return func(s quickfix.MessageStore) error {
+			if err := s.Reset(); err != nil {
+				return err
+			}
+			s.SetCreationTime(creationTime)
+			return nil
+		}
+	})
+}
+
+// Close applies any queued writes to the secondary store and closes both stores.
+func (store *replicatedStore) Close() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.queue != nil {
+		close(store.queue)
+		<-store.done
+		store.queue = nil
+	}
+
+	primaryErr := store.primary.Close()
+	if err := store.secondary.Close(); err != nil {
+		return errors.Wrap(err, "secondary")
+	}
+	return errors.Wrap(primaryErr, "primary")
+}
@@ -0,0 +1,203 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package replicated
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal/testsuite"
+)
+
+// ReplicatedStoreTestSuite runs all tests in the message.StoreTestSuite against the replicated store.
+type ReplicatedStoreTestSuite struct {
+	testsuite.StoreTestSuite
+	mode Mode
+}
+
+func (suite *ReplicatedStoreTestSuite) SetupTest() {
+	var err error
+	factory := NewStoreFactory(quickfix.NewMemoryStoreFactory(), quickfix.NewMemoryStoreFactory(), suite.mode)
+	suite.MsgStore, err = factory.Create(quickfix.SessionID{})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *ReplicatedStoreTestSuite) TearDownTest() {
+	if suite.MsgStore != nil {
+		suite.MsgStore.Close()
+	}
+}
+
+func TestReplicatedStoreSyncTestSuite(t *testing.T) {
+	suite.Run(t, &ReplicatedStoreTestSuite{mode: Sync})
+}
+
+func TestReplicatedStoreAsyncTestSuite(t *testing.T) {
+	suite.Run(t, &ReplicatedStoreTestSuite{mode: Async})
+}
+
+// failingStore fails every write once failing is set.
+type failingStore struct {
+	quickfix.MessageStore
+	failing bool
+}
+
+var errFailing = errors.New("failing")
+
+func (s *failingStore) SaveMessage(seqNum int, msg []byte) error {
+	if s.failing {
+		return errFailing
+	}
+	return s.MessageStore.SaveMessage(seqNum, msg)
+}
+
+func (s *failingStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	if s.failing {
+		return errFailing
+	}
+	return s.MessageStore.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, msg)
+}
+
+func (s *failingStore) SetNextSenderMsgSeqNum(next int) error {
+	if s.failing {
+		return errFailing
+	}
+	return s.MessageStore.SetNextSenderMsgSeqNum(next)
+}
+
+type storeFactoryFunc func(quickfix.SessionID) (quickfix.MessageStore, error)
+
+func (f storeFactoryFunc) Create(sessionID quickfix.SessionID) (quickfix.MessageStore, error) {
+	return f(sessionID)
+}
+
+func newFailingStore(t *testing.T) *failingStore {
+	inner, err := quickfix.NewMemoryStoreFactory().Create(quickfix.SessionID{})
+	require.Nil(t, err)
+	return &failingStore{MessageStore: inner}
+}
+
+func newReplicatedStore(t *testing.T, primary, secondary quickfix.MessageStore, mode Mode) *replicatedStore {
+	factory := NewStoreFactory(
+		storeFactoryFunc(func(quickfix.SessionID) (quickfix.MessageStore, error) { return primary, nil }),
+		storeFactoryFunc(func(quickfix.SessionID) (quickfix.MessageStore, error) { return secondary, nil }),
+		mode,
+	)
+	store, err := factory.Create(quickfix.SessionID{})
+	require.Nil(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store.(*replicatedStore)
+}
+
+func TestSyncPromotesSecondaryOnPrimaryFailure(t *testing.T) {
+	primary, secondary := newFailingStore(t), newFailingStore(t)
+	store := newReplicatedStore(t, primary, secondary, Sync)
+
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("msg1")))
+	require.Equal(t, 2, secondary.NextSenderMsgSeqNum())
+	require.False(t, store.Promoted())
+
+	primary.failing = true
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("msg2")))
+	require.True(t, store.Promoted())
+	require.Equal(t, 3, store.NextSenderMsgSeqNum())
+
+	msgs, err := store.GetMessages(1, 2)
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{[]byte("msg1"), []byte("msg2")}, msgs)
+}
+
+func TestSyncFailsWhenBothStoresFail(t *testing.T) {
+	primary, secondary := newFailingStore(t), newFailingStore(t)
+	store := newReplicatedStore(t, primary, secondary, Sync)
+
+	primary.failing, secondary.failing = true, true
+	require.Equal(t, errFailing, store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("msg1")))
+	require.False(t, store.Promoted())
+}
+
+func TestSyncMarksSecondaryStale(t *testing.T) {
+	primary, secondary := newFailingStore(t), newFailingStore(t)
+	store := newReplicatedStore(t, primary, secondary, Sync)
+
+	secondary.failing = true
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("msg1")))
+	require.True(t, store.Stale())
+
+	// A stale secondary store is no longer written to, nor promoted when the primary store fails.
+	secondary.failing = false
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("msg2")))
+	require.Equal(t, 1, secondary.NextSenderMsgSeqNum())
+
+	primary.failing = true
+	require.Equal(t, errFailing, store.SaveMessageAndIncrNextSenderMsgSeqNum(3, []byte("msg3")))
+	require.False(t, store.Promoted())
+	require.True(t, errors.Is(store.Promote(), ErrSecondaryBehind))
+}
+
+func TestReplicatesPrimaryState(t *testing.T) {
+	for _, mode := range []Mode{Sync, Async} {
+		primary, secondary := newFailingStore(t), newFailingStore(t)
+		store := newReplicatedStore(t, primary, secondary, mode)
+
+		// The secondary store is corrected to the seqnums of the primary store, rather than incremented from its own.
+		require.Nil(t, secondary.SetNextSenderMsgSeqNum(10))
+		require.Nil(t, secondary.SetNextTargetMsgSeqNum(10))
+		require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("msg1")))
+		require.Nil(t, store.IncrNextTargetMsgSeqNum())
+		require.Nil(t, store.Promote())
+
+		require.Equal(t, 2, secondary.NextSenderMsgSeqNum(), mode)
+		require.Equal(t, 2, secondary.NextTargetMsgSeqNum(), mode)
+		msgs, err := secondary.GetMessages(1, 1)
+		require.Nil(t, err)
+		require.Equal(t, [][]byte{[]byte("msg1")}, msgs)
+	}
+}
+
+func TestAsyncPromote(t *testing.T) {
+	primary, secondary := newFailingStore(t), newFailingStore(t)
+	store := newReplicatedStore(t, primary, secondary, Async)
+
+	for seqNum := 1; seqNum <= 100; seqNum++ {
+		require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, []byte("msg")))
+	}
+
+	// Primary failures are not masked in Async mode, the secondary store may be behind.
+	primary.failing = true
+	require.Equal(t, errFailing, store.SaveMessageAndIncrNextSenderMsgSeqNum(101, []byte("msg")))
+	require.False(t, store.Promoted())
+
+	require.Nil(t, store.Promote())
+	require.Equal(t, 101, store.NextSenderMsgSeqNum())
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(101, []byte("msg")))
+	require.Equal(t, 102, secondary.NextSenderMsgSeqNum())
+}
+
+func TestAsyncPromoteSecondaryBehind(t *testing.T) {
+	primary, secondary := newFailingStore(t), newFailingStore(t)
+	secondary.failing = true
+	store := newReplicatedStore(t, primary, secondary, Async)
+
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("msg")))
+	require.True(t, errors.Is(store.Promote(), ErrSecondaryBehind))
+	require.True(t, store.Stale())
+	require.False(t, store.Promoted())
+}
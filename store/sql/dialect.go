@@ -0,0 +1,124 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sql
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Dialect describes the SQL syntax a database driver expects from the sql store.
+// Any nil field falls back to the ANSI behavior noted on that field.
+type Dialect struct {
+	// Placeholder returns the bind parameter for the zero-based argument index i.
+	// If nil, statements are sent with "?" placeholders.
+	Placeholder func(i int) string
+
+	// UpsertMessage returns a statement that saves a message, replacing any message already
+	// stored for the same session and sequence number. The statement must be written with "?"
+	// placeholders taking, in order: msgseqnum, message, beginstring, session_qualifier,
+	// sendercompid, sendersubid, senderlocid, targetcompid, targetsubid, targetlocid.
+	// If nil, messages are saved with a plain INSERT.
+	UpsertMessage func(messagesTable string) string
+
+	// CreateTables returns the DDL statements that create the messages and sessions tables.
+	// If nil, the store tables must be created by hand.
+	CreateTables func(messagesTable, sessionsTable string) []string
+}
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = map[string]Dialect{
+		"sqlite3":  sqlite3Dialect,
+		"mysql":    mysqlDialect,
+		"postgres": postgresDialect,
+		"pgx":      postgresDialect,
+	}
+)
+
+// RegisterDialect makes a Dialect available to sql stores opened with the named database/sql driver.
+// Registering a dialect for a driver that already has one replaces it.
+func RegisterDialect(driverName string, dialect Dialect) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects[driverName] = dialect
+}
+
+func lookupDialect(driverName string) Dialect {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	return dialects[driverName]
+}
+
+const (
+	idColumnsDDL = `beginstring CHAR(8) NOT NULL,
+  sendercompid VARCHAR(64) NOT NULL,
+  sendersubid VARCHAR(64) NOT NULL,
+  senderlocid VARCHAR(64) NOT NULL,
+  targetcompid VARCHAR(64) NOT NULL,
+  targetsubid VARCHAR(64) NOT NULL,
+  targetlocid VARCHAR(64) NOT NULL,
+  session_qualifier VARCHAR(64) NOT NULL`
+	idPrimaryKey = `beginstring, sendercompid, sendersubid, senderlocid,
+  targetcompid, targetsubid, targetlocid, session_qualifier`
+	insertMessage = `INSERT INTO %s (msgseqnum, message, beginstring, session_qualifier, sendercompid, sendersubid, senderlocid, targetcompid, targetsubid, targetlocid)
+  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+)
+
+func createTables(timestampType string) func(messagesTable, sessionsTable string) []string {
+	return func(messagesTable, sessionsTable string) []string {
+		return []string{
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  %s,
+  creation_time %s NOT NULL,
+  incoming_seqnum INTEGER NOT NULL,
+  outgoing_seqnum INTEGER NOT NULL,
+  PRIMARY KEY (%s)
+)`, sessionsTable, idColumnsDDL, timestampType, idPrimaryKey),
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  %s,
+  msgseqnum INTEGER NOT NULL,
+  message TEXT NOT NULL,
+  PRIMARY KEY (%s, msgseqnum)
+)`, messagesTable, idColumnsDDL, idPrimaryKey),
+		}
+	}
+}
+
+var sqlite3Dialect = Dialect{
+	UpsertMessage: func(messagesTable string) string {
+		return fmt.Sprintf(insertMessage+`
+  ON CONFLICT (%s, msgseqnum) DO UPDATE SET message=excluded.message`, messagesTable, idPrimaryKey)
+	},
+	CreateTables: createTables("DATETIME"),
+}
+
+var mysqlDialect = Dialect{
+	UpsertMessage: func(messagesTable string) string {
+		return fmt.Sprintf(insertMessage+`
+  ON DUPLICATE KEY UPDATE message=VALUES(message)`, messagesTable)
+	},
+	CreateTables: createTables("DATETIME"),
+}
+
+var postgresDialect = Dialect{
+	Placeholder: postgresPlaceholder,
+	UpsertMessage: func(messagesTable string) string {
+		return fmt.Sprintf(insertMessage+`
+  ON CONFLICT (%s, msgseqnum) DO UPDATE SET message=excluded.message`, messagesTable, idPrimaryKey)
+	},
+	CreateTables: createTables("TIMESTAMP WITH TIME ZONE"),
+}
@@ -0,0 +1,87 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// ErrFenced is returned by the writes of a fenced store made with a fencing token other than the current token of its
+// lease, such as by a failover node that lost its lease to another node.
+var ErrFenced = errors.New("sql store: stale fencing token")
+
+// Fence makes the writes of a store conditional on the fencing token of a lease held in the same database, such as
+// the lease of a failover.Node created by failover.NewSQLLease.
+type Fence struct {
+	// Table is the table of the lease, with name and token columns.
+	Table string
+
+	// Name is the name of the lease in Table.
+	Name string
+
+	// Token returns the fencing token the writes of the store are made with, such as failover.Node.Token. Tokens
+	// below 1 are never current.
+	Token func() int64
+}
+
+// NewFencedStoreFactory returns a MessageStoreFactory like NewStoreFactory whose stores fail writes with ErrFenced
+// unless they are made with the current token of the lease of fence. The token is checked in the transaction of
+// each write, which locks the lease, so the lease can not change owner before the write commits. Writes queued by
+// SQLStoreWriteBehind are checked with the token they were queued with.
+func NewFencedStoreFactory(settings *quickfix.Settings, fence Fence) quickfix.MessageStoreFactory {
+	return sqlStoreFactory{settings: settings, fence: &fence}
+}
+
+// fenced precedes stmts with the check of the fencing token of a fenced store.
+func (store *sqlStore) fenced(stmts ...pendingStatement) []pendingStatement {
+	if store.fence == nil {
+		return stmts
+	}
+	return append([]pendingStatement{store.fenceStatement()}, stmts...)
+}
+
+// fenceStatement returns the statement checking the token of fence, to be executed first in a transaction.
+func (store *sqlStore) fenceStatement() pendingStatement {
+	token := store.fence.Token()
+	lock := sqlString(fmt.Sprintf(`UPDATE %s SET token=token WHERE name=?`, store.fence.Table), store.placeholder)
+	query := sqlString(fmt.Sprintf(`SELECT token FROM %s WHERE name=?`, store.fence.Table), store.placeholder)
+
+	return pendingStatement{check: func(tx *sql.Tx) error {
+		if token < 1 {
+			return ErrFenced
+		}
+
+		// Locks the lease until the transaction ends.
+		if _, err := tx.Exec(lock, store.fence.Name); err != nil {
+			return err
+		}
+
+		var current int64
+		if err := tx.QueryRow(query, store.fence.Name).Scan(&current); err == sql.ErrNoRows {
+			return ErrFenced
+		} else if err != nil {
+			return err
+		}
+		if current != token {
+			return ErrFenced
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sql
+
+import "container/list"
+
+type cachedMessage struct {
+	seqNum int
+	msg    []byte
+}
+
+// messageCache is a bounded, least recently used cache of saved messages keyed by MsgSeqNum.
+type messageCache struct {
+	size    int
+	order   *list.List
+	entries map[int]*list.Element
+
+	// highest is the highest MsgSeqNum put in the cache since it was last reset.
+	highest int
+}
+
+func newMessageCache(size int) *messageCache {
+	return &messageCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[int]*list.Element),
+	}
+}
+
+func (c *messageCache) put(seqNum int, msg []byte) {
+	if seqNum > c.highest {
+		c.highest = seqNum
+	}
+	if e, ok := c.entries[seqNum]; ok {
+		e.Value.(*cachedMessage).msg = msg
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.entries[seqNum] = c.order.PushFront(&cachedMessage{seqNum: seqNum, msg: msg})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedMessage).seqNum)
+	}
+}
+
+// get returns the messages from beginSeqNum to endSeqNum only if all of them are in the cache.
+func (c *messageCache) get(beginSeqNum, endSeqNum int) ([][]byte, bool) {
+	if endSeqNum < beginSeqNum || endSeqNum-beginSeqNum+1 > len(c.entries) {
+		return nil, false
+	}
+
+	msgs := make([][]byte, 0, endSeqNum-beginSeqNum+1)
+	for seqNum := beginSeqNum; seqNum <= endSeqNum; seqNum++ {
+		e, ok := c.entries[seqNum]
+		if !ok {
+			return nil, false
+		}
+		msgs = append(msgs, e.Value.(*cachedMessage).msg)
+	}
+	for seqNum := beginSeqNum; seqNum <= endSeqNum; seqNum++ {
+		c.order.MoveToFront(c.entries[seqNum])
+	}
+	return msgs, true
+}
+
+// prune removes the messages with a MsgSeqNum below beforeSeqNum.
+func (c *messageCache) prune(beforeSeqNum int) {
+	for seqNum, e := range c.entries {
+		if seqNum < beforeSeqNum {
+			c.order.Remove(e)
+			delete(c.entries, seqNum)
+		}
+	}
+}
+
+func (c *messageCache) reset() {
+	c.order.Init()
+	c.entries = make(map[int]*list.Element)
+	c.highest = 0
+}
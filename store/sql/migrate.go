@@ -0,0 +1,88 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const migrationsTable = "quickfix_migrations"
+
+// migration returns the statements that upgrade the store schema to the next version.
+type migration func(dialect Dialect, messagesTable, sessionsTable string) []string
+
+// migrations lists the store schema versions in order; version n is migrations[n-1].
+// Append new versions, never edit released ones.
+var migrations = []migration{
+	func(dialect Dialect, messagesTable, sessionsTable string) []string {
+		return dialect.CreateTables(messagesTable, sessionsTable)
+	},
+}
+
+// migrate creates or upgrades the messages and sessions tables, recording each applied version in the migrations table.
+// Versions are recorded per pair of table names, so stores configured with different tables in one database are
+// migrated independently. Each version is applied in a transaction, although databases such as MySQL commit DDL
+// implicitly.
+func migrate(db *sql.DB, driver string, dialect Dialect, messagesTable, sessionsTable string) error {
+	if dialect.CreateTables == nil {
+		return fmt.Errorf("no table DDL for sql driver %q, register a Dialect with CreateTables", driver)
+	}
+
+	if _, err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  messages_table VARCHAR(255) NOT NULL,
+  sessions_table VARCHAR(255) NOT NULL,
+  version INTEGER NOT NULL,
+  PRIMARY KEY (messages_table, sessions_table, version)
+)`, migrationsTable)); err != nil {
+		return errors.Wrap(err, "create migrations table")
+	}
+
+	var current sql.NullInt64
+	queryVersion := sqlString(fmt.Sprintf(`SELECT MAX(version) FROM %s WHERE messages_table=? AND sessions_table=?`, migrationsTable), dialect.Placeholder)
+	if err := db.QueryRow(queryVersion, messagesTable, sessionsTable).Scan(&current); err != nil {
+		return errors.Wrap(err, "query schema version")
+	}
+
+	insertVersion := sqlString(fmt.Sprintf(`INSERT INTO %s (messages_table, sessions_table, version) VALUES (?, ?, ?)`, migrationsTable), dialect.Placeholder)
+	for version := int(current.Int64) + 1; version <= len(migrations); version++ {
+		if err := migrateTo(db, version, dialect, messagesTable, sessionsTable, insertVersion); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func migrateTo(db *sql.DB, version int, dialect Dialect, messagesTable, sessionsTable, insertVersion string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.Wrapf(err, "migrate to version %d", version)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, stmt := range migrations[version-1](dialect, messagesTable, sessionsTable) {
+		if _, err := tx.Exec(stmt); err != nil {
+			return errors.Wrapf(err, "migrate to version %d", version)
+		}
+	}
+	if _, err := tx.Exec(insertVersion, messagesTable, sessionsTable, version); err != nil {
+		return errors.Wrapf(err, "record version %d", version)
+	}
+	return errors.Wrapf(tx.Commit(), "commit version %d", version)
+}
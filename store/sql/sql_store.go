@@ -16,36 +16,57 @@
 package sql
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/base64"
 	"fmt"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/quickfixgo/quickfix"
 	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal/compression"
 )
 
 const (
 	defaultMessagesTable = "messages"
 	defaultSessionsTable = "sessions"
+
+	// defaultMaxIdleConns matches the database/sql default.
+	defaultMaxIdleConns = 2
 )
 
 type sqlStoreFactory struct {
 	settings *quickfix.Settings
+	fence    *Fence
+}
+
+// connPool holds the settings of the database connection pool.
+type connPool struct {
+	connMaxLifetime time.Duration
+	connMaxIdleTime time.Duration
+	maxOpenConns    int
+	maxIdleConns    int
 }
 
 type sqlStore struct {
-	sessionID          quickfix.SessionID
-	cache              quickfix.MessageStore
-	sqlDriver          string
-	sqlDataSourceName  string
-	sqlConnMaxLifetime time.Duration
-	db                 *sql.DB
-	placeholder        placeholderFunc
-	messagesTable      string
-	sessionsTable      string
+	sessionID         quickfix.SessionID
+	cache             quickfix.MessageStore
+	sqlDriver         string
+	sqlDataSourceName string
+	connPool          connPool
+	db                *sql.DB
+	dialect           Dialect
+	placeholder       placeholderFunc
+	compression       compression.Algorithm
+	messagesTable     string
+	sessionsTable     string
+	writeBehind       *writeBehind
+	messageCache      *messageCache
+	fence             *Fence
 
 	sqlUpdateSeqNums      string
 	sqlInsertSession      string
@@ -57,6 +78,7 @@ type sqlStore struct {
 	sqlUpdateSenderSeqNum string
 	sqlUpdateTargetSeqNum string
 	sqlDeleteMessages     string
+	sqlPruneMessages      string
 }
 
 type placeholderFunc func(int) string
@@ -79,7 +101,16 @@ func postgresPlaceholder(i int) string {
 	return fmt.Sprintf("$%d", i+1)
 }
 
+// Flusher is implemented by the stores created by NewStoreFactory, to write the statements queued by
+// SQLStoreWriteBehind to the database.
+type Flusher interface {
+	// Flush executes the queued statements. It returns the error of this flush, or else the error of statements
+	// dropped since the last call after failing to flush repeatedly in the background.
+	Flush() error
+}
+
 // NewStoreFactory returns a sql-based implementation of MessageStoreFactory.
+// The stores it creates implement Flusher.
 func NewStoreFactory(settings *quickfix.Settings) quickfix.MessageStoreFactory {
 	return sqlStoreFactory{settings: settings}
 }
@@ -117,18 +148,96 @@ func (f sqlStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfix
 		sessionsTableName = name
 	}
 
-	sqlConnMaxLifetime := 0 * time.Second
+	pool := connPool{maxIdleConns: defaultMaxIdleConns}
 	if sessionSettings.HasSetting(config.SQLStoreConnMaxLifetime) {
-		sqlConnMaxLifetime, err = sessionSettings.DurationSetting(config.SQLStoreConnMaxLifetime)
+		pool.connMaxLifetime, err = sessionSettings.DurationSetting(config.SQLStoreConnMaxLifetime)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sessionSettings.HasSetting(config.SQLStoreConnMaxIdleTime) {
+		pool.connMaxIdleTime, err = sessionSettings.DurationSetting(config.SQLStoreConnMaxIdleTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sessionSettings.HasSetting(config.SQLStoreMaxOpenConns) {
+		if pool.maxOpenConns, err = sessionSettings.IntSetting(config.SQLStoreMaxOpenConns); err != nil {
+			return nil, err
+		} else if pool.maxOpenConns < 0 {
+			return nil, quickfix.IncorrectFormatForSetting{Setting: config.SQLStoreMaxOpenConns, Value: []byte(strconv.Itoa(pool.maxOpenConns))}
+		}
+	}
+	if sessionSettings.HasSetting(config.SQLStoreMaxIdleConns) {
+		if pool.maxIdleConns, err = sessionSettings.IntSetting(config.SQLStoreMaxIdleConns); err != nil {
+			return nil, err
+		} else if pool.maxIdleConns < 0 {
+			return nil, quickfix.IncorrectFormatForSetting{Setting: config.SQLStoreMaxIdleConns, Value: []byte(strconv.Itoa(pool.maxIdleConns))}
+		}
+	}
+
+	autoMigrate := false
+	if sessionSettings.HasSetting(config.SQLStoreAutoMigrate) {
+		if autoMigrate, err = sessionSettings.BoolSetting(config.SQLStoreAutoMigrate); err != nil {
+			return nil, err
+		}
+	}
+
+	var flushInterval time.Duration
+	flushBatchSize := defaultFlushBatchSize
+	if sessionSettings.HasSetting(config.SQLStoreWriteBehind) {
+		writeBehind, err := sessionSettings.BoolSetting(config.SQLStoreWriteBehind)
+		if err != nil {
+			return nil, err
+		}
+		if writeBehind {
+			flushInterval = defaultFlushInterval
+			if sessionSettings.HasSetting(config.SQLStoreFlushInterval) {
+				if flushInterval, err = sessionSettings.DurationSetting(config.SQLStoreFlushInterval); err != nil {
+					return nil, err
+				} else if flushInterval <= 0 {
+					return nil, quickfix.IncorrectFormatForSetting{Setting: config.SQLStoreFlushInterval, Value: []byte(flushInterval.String())}
+				}
+			}
+			if sessionSettings.HasSetting(config.SQLStoreFlushBatchSize) {
+				if flushBatchSize, err = sessionSettings.IntSetting(config.SQLStoreFlushBatchSize); err != nil {
+					return nil, err
+				} else if flushBatchSize <= 0 {
+					return nil, quickfix.IncorrectFormatForSetting{Setting: config.SQLStoreFlushBatchSize, Value: []byte(strconv.Itoa(flushBatchSize))}
+				}
+			}
+		}
+	}
+
+	alg := compression.None
+	if sessionSettings.HasSetting(config.MessageStoreCompression) {
+		algStr, err := sessionSettings.Setting(config.MessageStoreCompression)
 		if err != nil {
 			return nil, err
 		}
+		if alg, err = compression.Parse(algStr); err != nil {
+			return nil, quickfix.IncorrectFormatForSetting{Setting: config.MessageStoreCompression, Value: []byte(algStr), Err: err}
+		}
+	}
+
+	cacheSize := 0
+	if sessionSettings.HasSetting(config.MessageStoreCacheSize) {
+		if cacheSize, err = sessionSettings.IntSetting(config.MessageStoreCacheSize); err != nil {
+			return nil, err
+		} else if cacheSize < 0 {
+			return nil, quickfix.IncorrectFormatForSetting{Setting: config.MessageStoreCacheSize, Value: []byte(strconv.Itoa(cacheSize))}
+		}
 	}
 
-	return newSQLStore(sessionID, sqlDriver, sqlDataSourceName, messagesTableName, sessionsTableName, sqlConnMaxLifetime)
+	store, err := newSQLStore(sessionID, sqlDriver, sqlDataSourceName, messagesTableName, sessionsTableName, pool, autoMigrate, flushInterval, flushBatchSize, alg, cacheSize)
+	if err != nil {
+		return nil, err
+	}
+	store.fence = f.fence
+	return store, nil
 }
 
-func newSQLStore(sessionID quickfix.SessionID, driver, dataSourceName, messagesTableName, sessionsTableName string, connMaxLifetime time.Duration) (store *sqlStore, err error) {
+func newSQLStore(sessionID quickfix.SessionID, driver, dataSourceName, messagesTableName, sessionsTableName string, pool connPool, autoMigrate bool, flushInterval time.Duration, flushBatchSize int, alg compression.Algorithm, cacheSize int) (store *sqlStore, err error) {
 
 	memStore, memErr := quickfix.NewMemoryStoreFactory().Create(sessionID)
 	if memErr != nil {
@@ -137,49 +246,108 @@ func newSQLStore(sessionID quickfix.SessionID, driver, dataSourceName, messagesT
 	}
 
 	store = &sqlStore{
-		sessionID:          sessionID,
-		cache:              memStore,
-		sqlDriver:          driver,
-		sqlDataSourceName:  dataSourceName,
-		sqlConnMaxLifetime: connMaxLifetime,
-		messagesTable:      messagesTableName,
-		sessionsTable:      sessionsTableName,
+		sessionID:         sessionID,
+		cache:             memStore,
+		sqlDriver:         driver,
+		sqlDataSourceName: dataSourceName,
+		connPool:          pool,
+		messagesTable:     messagesTableName,
+		sessionsTable:     sessionsTableName,
+		compression:       alg,
+	}
+	if cacheSize > 0 {
+		store.messageCache = newMessageCache(cacheSize)
 	}
 	if err = store.cache.Reset(); err != nil {
 		err = errors.Wrap(err, "cache reset")
 		return
 	}
 
-	if store.sqlDriver == "postgres" || store.sqlDriver == "pgx" {
-		store.placeholder = postgresPlaceholder
-	}
+	store.dialect = lookupDialect(store.sqlDriver)
+	store.placeholder = store.dialect.Placeholder
 
 	if store.db, err = sql.Open(store.sqlDriver, store.sqlDataSourceName); err != nil {
 		return nil, err
 	}
-	store.db.SetConnMaxLifetime(store.sqlConnMaxLifetime)
+	store.db.SetConnMaxLifetime(store.connPool.connMaxLifetime)
+	store.db.SetConnMaxIdleTime(store.connPool.connMaxIdleTime)
+	store.db.SetMaxOpenConns(store.connPool.maxOpenConns)
+	store.db.SetMaxIdleConns(store.connPool.maxIdleConns)
 
 	if err = store.db.Ping(); err != nil { // ensure immediate connection
 		return nil, err
 	}
 
+	if autoMigrate {
+		if err = migrate(store.db, store.sqlDriver, store.dialect, store.messagesTable, store.sessionsTable); err != nil {
+			return nil, err
+		}
+	}
+
 	store.setSQLStatements()
 
 	if err = store.populateCache(); err != nil {
 		return nil, err
 	}
 
+	if flushInterval > 0 {
+		store.writeBehind = newWriteBehind(store.db, flushInterval, flushBatchSize)
+	}
+
 	return store, nil
 }
 
+// exec runs write statements in a transaction, or queues them when write-behind is enabled.
+func (store *sqlStore) exec(stmts ...pendingStatement) error {
+	stmts = store.fenced(stmts...)
+	if store.writeBehind != nil {
+		return store.writeBehind.enqueue(stmts...)
+	}
+	return store.execNow(stmts...)
+}
+
+// execNow runs write statements in a transaction.
+func (store *sqlStore) execNow(stmts ...pendingStatement) error {
+	if len(stmts) == 1 && stmts[0].check == nil {
+		_, err := store.db.Exec(stmts[0].query, stmts[0].args...)
+		return err
+	}
+
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range stmts {
+		if err = stmt.execTx(tx); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Flush writes any queued statements to the database. It is a no-op unless write-behind is enabled.
+// Statements dropped by a failing background flush are reported by the next call.
+func (store *sqlStore) Flush() error {
+	if store.writeBehind == nil {
+		return nil
+	}
+	return store.writeBehind.flush()
+}
+
 func (store *sqlStore) setSQLStatements() {
 	idColumns := `beginstring, session_qualifier, sendercompid, sendersubid, senderlocid, targetcompid, targetsubid, targetlocid`
 	idPlaceholders := `?,?,?,?,?,?,?,?`
 	idWhereClause := `beginstring=? AND session_qualifier=? AND sendercompid=? AND sendersubid=? AND senderlocid=? AND targetcompid=? AND targetsubid=? AND targetlocid=?`
 
-	store.sqlInsertMessage = fmt.Sprintf(`INSERT INTO %s (
+	if store.dialect.UpsertMessage != nil {
+		store.sqlInsertMessage = store.dialect.UpsertMessage(store.messagesTable)
+	} else {
+		store.sqlInsertMessage = fmt.Sprintf(`INSERT INTO %s (
 		msgseqnum, message, %s) VALUES (?, ?, %s)`,
-		store.messagesTable, idColumns, idPlaceholders)
+			store.messagesTable, idColumns, idPlaceholders)
+	}
 
 	store.sqlUpdateMessage = fmt.Sprintf(`UPDATE %s SET message=? WHERE %s AND msgseqnum=?`,
 		store.messagesTable, idWhereClause)
@@ -190,6 +358,9 @@ func (store *sqlStore) setSQLStatements() {
 	store.sqlDeleteMessages = fmt.Sprintf(`DELETE FROM %s WHERE %s`,
 		store.messagesTable, idWhereClause)
 
+	store.sqlPruneMessages = fmt.Sprintf(`DELETE FROM %s WHERE %s AND msgseqnum<?`,
+		store.messagesTable, idWhereClause)
+
 	store.sqlInsertSession = fmt.Sprintf(`INSERT INTO %s (
 		creation_time, incoming_seqnum, outgoing_seqnum, %s) VALUES (?, ?, ?, %s)`,
 		store.sessionsTable, idColumns, idPlaceholders)
@@ -212,33 +383,43 @@ func (store *sqlStore) setSQLStatements() {
 
 // Reset deletes the store records and sets the seqnums back to 1.
 func (store *sqlStore) Reset() error {
-	s := store.sessionID
-	_, err := store.db.Exec(sqlString(store.sqlDeleteMessages, store.placeholder),
-		s.BeginString, s.Qualifier,
-		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
-		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
-	if err != nil {
+	if err := store.Flush(); err != nil {
 		return err
 	}
 
-	if err = store.cache.Reset(); err != nil {
+	if err := store.cache.Reset(); err != nil {
 		return err
 	}
+	if store.messageCache != nil {
+		store.messageCache.reset()
+	}
 
-	_, err = store.db.Exec(sqlString(store.sqlUpdateSession, store.placeholder),
-		store.cache.CreationTime(), store.cache.NextTargetMsgSeqNum(), store.cache.NextSenderMsgSeqNum(),
-		s.BeginString, s.Qualifier,
-		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
-		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
-
-	return err
+	s := store.sessionID
+	return store.execNow(store.fenced(pendingStatement{
+		query: sqlString(store.sqlDeleteMessages, store.placeholder),
+		args: []interface{}{s.BeginString, s.Qualifier,
+			s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+			s.TargetCompID, s.TargetSubID, s.TargetLocationID},
+	}, pendingStatement{
+		query: sqlString(store.sqlUpdateSession, store.placeholder),
+		args: []interface{}{store.cache.CreationTime(), store.cache.NextTargetMsgSeqNum(), store.cache.NextSenderMsgSeqNum(),
+			s.BeginString, s.Qualifier,
+			s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+			s.TargetCompID, s.TargetSubID, s.TargetLocationID},
+	})...)
 }
 
 // Refresh reloads the store from the database.
 func (store *sqlStore) Refresh() error {
+	if err := store.Flush(); err != nil {
+		return err
+	}
 	if err := store.cache.Reset(); err != nil {
 		return err
 	}
+	if store.messageCache != nil {
+		store.messageCache.reset()
+	}
 	return store.populateCache()
 }
 
@@ -294,12 +475,7 @@ func (store *sqlStore) NextTargetMsgSeqNum() int {
 
 // SetNextSenderMsgSeqNum sets the next MsgSeqNum that will be sent.
 func (store *sqlStore) SetNextSenderMsgSeqNum(next int) error {
-	s := store.sessionID
-	_, err := store.db.Exec(sqlString(store.sqlUpdateSenderSeqNum, store.placeholder),
-		next, s.BeginString, s.Qualifier,
-		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
-		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
-	if err != nil {
+	if err := store.exec(store.updateSenderSeqNumStatement(next)); err != nil {
 		return err
 	}
 	return store.cache.SetNextSenderMsgSeqNum(next)
@@ -307,12 +483,7 @@ func (store *sqlStore) SetNextSenderMsgSeqNum(next int) error {
 
 // SetNextTargetMsgSeqNum sets the next MsgSeqNum that should be received.
 func (store *sqlStore) SetNextTargetMsgSeqNum(next int) error {
-	s := store.sessionID
-	_, err := store.db.Exec(sqlString(store.sqlUpdateTargetSeqNum, store.placeholder),
-		next, s.BeginString, s.Qualifier,
-		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
-		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
-	if err != nil {
+	if err := store.exec(store.updateTargetSeqNumStatement(next)); err != nil {
 		return err
 	}
 	return store.cache.SetNextTargetMsgSeqNum(next)
@@ -343,54 +514,131 @@ func (store *sqlStore) CreationTime() time.Time {
 func (store *sqlStore) SetCreationTime(_ time.Time) {
 }
 
-func (store *sqlStore) SaveMessage(seqNum int, msg []byte) error {
+func (store *sqlStore) insertMessageStatement(seqNum int, msg []byte) (pendingStatement, error) {
+	message, err := store.encodeMessage(msg)
+	if err != nil {
+		return pendingStatement{}, err
+	}
+
 	s := store.sessionID
+	return pendingStatement{
+		query: sqlString(store.sqlInsertMessage, store.placeholder),
+		args: []interface{}{seqNum, message,
+			s.BeginString, s.Qualifier,
+			s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+			s.TargetCompID, s.TargetSubID, s.TargetLocationID},
+	}, nil
+}
 
-	_, err := store.db.Exec(sqlString(store.sqlInsertMessage, store.placeholder),
-		seqNum, string(msg),
-		s.BeginString, s.Qualifier,
-		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
-		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
+// Base64 prefixes of the gzip and zstd magic numbers, identifying compressed messages.
+var (
+	gzipBase64Prefix = []byte("H4sI")
+	zstdBase64Prefix = []byte("KLUv")
+)
 
-	return err
+// encodeMessage compresses msg as configured and base64 encodes the result to store it as text.
+func (store *sqlStore) encodeMessage(msg []byte) (string, error) {
+	if store.compression == compression.None {
+		return string(msg), nil
+	}
+	compressed, err := store.compression.Compress(msg)
+	if err != nil {
+		return "", errors.Wrap(err, "compress message")
+	}
+	return base64.StdEncoding.EncodeToString(compressed), nil
 }
 
-func (store *sqlStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+// decodeMessage reverses encodeMessage. Uncompressed messages are returned unchanged.
+func decodeMessage(message []byte) ([]byte, error) {
+	if !bytes.HasPrefix(message, gzipBase64Prefix) && !bytes.HasPrefix(message, zstdBase64Prefix) {
+		return message, nil
+	}
+	compressed := make([]byte, base64.StdEncoding.DecodedLen(len(message)))
+	n, err := base64.StdEncoding.Decode(compressed, message)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode message")
+	}
+	msg, err := compression.Decompress(compressed[:n])
+	return msg, errors.Wrap(err, "decompress message")
+}
+
+func (store *sqlStore) updateSenderSeqNumStatement(next int) pendingStatement {
 	s := store.sessionID
+	return pendingStatement{
+		query: sqlString(store.sqlUpdateSenderSeqNum, store.placeholder),
+		args: []interface{}{next, s.BeginString, s.Qualifier,
+			s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+			s.TargetCompID, s.TargetSubID, s.TargetLocationID},
+	}
+}
 
-	tx, err := store.db.Begin()
-	if err != nil {
-		return err
+func (store *sqlStore) updateTargetSeqNumStatement(next int) pendingStatement {
+	s := store.sessionID
+	return pendingStatement{
+		query: sqlString(store.sqlUpdateTargetSeqNum, store.placeholder),
+		args: []interface{}{next, s.BeginString, s.Qualifier,
+			s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+			s.TargetCompID, s.TargetSubID, s.TargetLocationID},
 	}
-	defer tx.Rollback()
+}
 
-	_, err = tx.Exec(sqlString(store.sqlInsertMessage, store.placeholder),
-		seqNum, string(msg),
-		s.BeginString, s.Qualifier,
-		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
-		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
+func (store *sqlStore) SaveMessage(seqNum int, msg []byte) error {
+	insert, err := store.insertMessageStatement(seqNum, msg)
 	if err != nil {
 		return err
 	}
+	if err = store.exec(insert); err != nil {
+		return err
+	}
+	store.cacheMessage(seqNum, msg)
+	return nil
+}
 
-	next := store.cache.NextSenderMsgSeqNum() + 1
-	_, err = tx.Exec(sqlString(store.sqlUpdateSenderSeqNum, store.placeholder),
-		next, s.BeginString, s.Qualifier,
-		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
-		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
+func (store *sqlStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	insert, err := store.insertMessageStatement(seqNum, msg)
 	if err != nil {
 		return err
 	}
 
-	err = tx.Commit()
-	if err != nil {
+	next := store.cache.NextSenderMsgSeqNum() + 1
+	if err := store.exec(insert, store.updateSenderSeqNumStatement(next)); err != nil {
 		return err
 	}
+	store.cacheMessage(seqNum, msg)
 
 	return store.cache.SetNextSenderMsgSeqNum(next)
 }
 
+func (store *sqlStore) cacheMessage(seqNum int, msg []byte) {
+	if store.messageCache != nil {
+		store.messageCache.put(seqNum, msg)
+	}
+}
+
 func (store *sqlStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	if store.messageCache != nil {
+		// Nothing is saved past the last sent message, unless saved without incrementing the seqnum.
+		last := store.cache.NextSenderMsgSeqNum() - 1
+		if store.messageCache.highest > last {
+			last = store.messageCache.highest
+		}
+		if last > endSeqNum {
+			last = endSeqNum
+		}
+		if msgs, ok := store.messageCache.get(beginSeqNum, last); ok {
+			for _, msg := range msgs {
+				if err := cb(msg); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	if err := store.Flush(); err != nil {
+		return err
+	}
+
 	s := store.sessionID
 	rows, err := store.db.Query(sqlString(store.sqlGetMessages, store.placeholder),
 		s.BeginString, s.Qualifier,
@@ -404,9 +652,12 @@ func (store *sqlStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byt
 
 	for rows.Next() {
 		var message string
+		var msg []byte
 		if err = rows.Scan(&message); err != nil {
 			return err
-		} else if err = cb([]byte(message)); err != nil {
+		} else if msg, err = decodeMessage([]byte(message)); err != nil {
+			return err
+		} else if err = cb(msg); err != nil {
 			return err
 		}
 	}
@@ -414,6 +665,22 @@ func (store *sqlStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byt
 	return rows.Err()
 }
 
+// PruneMessages deletes the saved messages with a sequence number below beforeSeqNum.
+func (store *sqlStore) PruneMessages(beforeSeqNum int) error {
+	if store.messageCache != nil {
+		store.messageCache.prune(beforeSeqNum)
+	}
+
+	s := store.sessionID
+	return store.exec(pendingStatement{
+		query: sqlString(store.sqlPruneMessages, store.placeholder),
+		args: []interface{}{s.BeginString, s.Qualifier,
+			s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+			s.TargetCompID, s.TargetSubID, s.TargetLocationID,
+			beforeSeqNum},
+	})
+}
+
 func (store *sqlStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
 	var msgs [][]byte
 	err := store.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
@@ -423,11 +690,18 @@ func (store *sqlStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error)
 	return msgs, err
 }
 
-// Close closes the store's database connection.
+// Close flushes any queued writes and closes the store's database connection.
 func (store *sqlStore) Close() error {
+	var err error
+	if store.writeBehind != nil {
+		if err = store.writeBehind.close(); err != nil {
+			err = errors.Wrap(err, "flush")
+		}
+		store.writeBehind = nil
+	}
 	if store.db != nil {
 		store.db.Close()
 		store.db = nil
 	}
-	return nil
+	return err
 }
@@ -22,16 +22,22 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 	"github.com/quickfixgo/quickfix"
 	"github.com/quickfixgo/quickfix/internal/testsuite"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
+func init() {
+	sql.Register("sqlite3_numbered", &sqlite3.SQLiteDriver{})
+	sql.Register("sqlite3_nodialect", &sqlite3.SQLiteDriver{})
+}
+
 // SqlStoreTestSuite runs all tests in the MessageStoreTestSuite against the SqlStore implementation.
 type SQLStoreTestSuite struct {
 	testsuite.StoreTestSuite
@@ -159,6 +165,265 @@ TargetCompID=%s
 	suite.Equal(1, nextTarget)
 }
 
+func (suite *SQLStoreTestSuite) TestStoreWriteBehindFlush() {
+	sqlDriver := "sqlite3"
+	sqlDsn := path.Join(suite.sqlStoreRootPath, fmt.Sprintf("write-behind-%d.db", time.Now().UnixNano()))
+
+	db, err := sql.Open(sqlDriver, sqlDsn)
+	require.NoError(suite.T(), err)
+	defer db.Close()
+
+	ddlFnames, err := filepath.Glob(fmt.Sprintf("../../_sql/%s/*.sql", sqlDriver))
+	require.NoError(suite.T(), err)
+	for _, fname := range ddlFnames {
+		sqlBytes, err := os.ReadFile(fname)
+		require.NoError(suite.T(), err)
+		_, err = db.Exec(string(sqlBytes))
+		require.NoError(suite.T(), err)
+	}
+
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=%s
+SQLStoreDataSourceName=%s
+SQLStoreWriteBehind=Y
+SQLStoreFlushInterval=1h
+SQLStoreFlushBatchSize=1000
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s
+`, sqlDriver, sqlDsn, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.NoError(suite.T(), err)
+
+	store, err := NewStoreFactory(settings).Create(sessionID)
+	require.NoError(suite.T(), err)
+
+	countMessages := func() (count int) {
+		require.NoError(suite.T(), db.QueryRow(`SELECT COUNT(*) FROM messages`).Scan(&count))
+		return
+	}
+
+	// Writes are queued until flushed.
+	msg := []byte("8=FIX.4.4\x019=12\x0135=0\x01")
+	require.NoError(suite.T(), store.SaveMessageAndIncrNextSenderMsgSeqNum(1, msg))
+	require.NoError(suite.T(), store.SaveMessageAndIncrNextSenderMsgSeqNum(2, msg))
+	suite.Equal(3, store.NextSenderMsgSeqNum())
+	suite.Equal(0, countMessages())
+
+	flusher, ok := store.(Flusher)
+	require.True(suite.T(), ok)
+	require.NoError(suite.T(), flusher.Flush())
+	suite.Equal(2, countMessages())
+
+	var outgoingSeqNum int
+	require.NoError(suite.T(), db.QueryRow(`SELECT outgoing_seqnum FROM sessions`).Scan(&outgoingSeqNum))
+	suite.Equal(3, outgoingSeqNum)
+
+	// Reads see queued writes.
+	require.NoError(suite.T(), store.SaveMessage(3, msg))
+	msgs, err := store.GetMessages(1, 3)
+	require.NoError(suite.T(), err)
+	suite.Len(msgs, 3)
+
+	// Close flushes remaining writes.
+	require.NoError(suite.T(), store.SaveMessage(4, msg))
+	require.NoError(suite.T(), store.Close())
+	suite.Equal(4, countMessages())
+}
+
+func (suite *SQLStoreTestSuite) TestWriteBehindDropsFailingBatch() {
+	db, err := sql.Open("sqlite3", path.Join(suite.sqlStoreRootPath, fmt.Sprintf("write-behind-drop-%d.db", time.Now().UnixNano())))
+	require.NoError(suite.T(), err)
+	defer db.Close()
+
+	w := newWriteBehind(db, time.Hour, 1000)
+	defer w.close()
+
+	// A failing batch is retried, then dropped without blocking later writes.
+	require.NoError(suite.T(), w.enqueue(pendingStatement{query: `INSERT INTO t (v) VALUES (?)`, args: []interface{}{1}}))
+	for range maxFlushAttempts - 1 {
+		suite.Error(w.flush())
+		suite.Len(w.pending, 1)
+	}
+	suite.ErrorContains(w.flush(), "dropped 1 statements")
+	suite.Empty(w.pending)
+
+	_, err = db.Exec(`CREATE TABLE t (v INTEGER)`)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), w.enqueue(pendingStatement{query: `INSERT INTO t (v) VALUES (?)`, args: []interface{}{2}}))
+	suite.NoError(w.flush())
+
+	// Statements dropped by background flushes are reported once by the next flush.
+	require.NoError(suite.T(), w.enqueue(pendingStatement{query: `INSERT INTO missing (v) VALUES (?)`, args: []interface{}{3}}))
+	for range maxFlushAttempts {
+		suite.Error(w.execPending())
+	}
+	suite.ErrorContains(w.flush(), "dropped 1 statements")
+	suite.NoError(w.flush())
+
+	var count int
+	require.NoError(suite.T(), db.QueryRow(`SELECT COUNT(*) FROM t`).Scan(&count))
+	suite.Equal(1, count)
+}
+
+func (suite *SQLStoreTestSuite) TestStorePruneMessages() {
+	for seqNum := 1; seqNum <= 3; seqNum++ {
+		require.NoError(suite.T(), suite.MsgStore.SaveMessage(seqNum, []byte(fmt.Sprintf("msg%d", seqNum))))
+	}
+
+	pruner, ok := suite.MsgStore.(interface{ PruneMessages(int) error })
+	require.True(suite.T(), ok)
+	require.NoError(suite.T(), pruner.PruneMessages(3))
+
+	msgs, err := suite.MsgStore.GetMessages(1, 3)
+	require.NoError(suite.T(), err)
+	suite.Equal([][]byte{[]byte("msg3")}, msgs)
+}
+
+func (suite *SQLStoreTestSuite) TestStoreRegisteredDialect() {
+	sqlDriver := "sqlite3_numbered"
+	sqlDsn := path.Join(suite.sqlStoreRootPath, fmt.Sprintf("dialect-%d.db", time.Now().UnixNano()))
+
+	var placeholders int
+	RegisterDialect(sqlDriver, Dialect{
+		Placeholder: func(i int) string {
+			placeholders++
+			return fmt.Sprintf("?%d", i+1)
+		},
+		UpsertMessage: sqlite3Dialect.UpsertMessage,
+		CreateTables:  sqlite3Dialect.CreateTables,
+	})
+
+	// Create the tables from the dialect DDL.
+	db, err := sql.Open(sqlDriver, sqlDsn)
+	require.NoError(suite.T(), err)
+	defer db.Close()
+	for _, ddl := range lookupDialect(sqlDriver).CreateTables(defaultMessagesTable, defaultSessionsTable) {
+		_, err = db.Exec(ddl)
+		require.NoError(suite.T(), err)
+	}
+
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=%s
+SQLStoreDataSourceName=%s
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s
+`, sqlDriver, sqlDsn, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.NoError(suite.T(), err)
+
+	store, err := NewStoreFactory(settings).Create(sessionID)
+	require.NoError(suite.T(), err)
+	defer store.Close()
+
+	// Saving the same sequence number twice replaces the message.
+	require.NoError(suite.T(), store.SaveMessage(1, []byte("first")))
+	require.NoError(suite.T(), store.SaveMessage(1, []byte("second")))
+	msgs, err := store.GetMessages(1, 1)
+	require.NoError(suite.T(), err)
+	suite.Equal([][]byte{[]byte("second")}, msgs)
+	suite.NotZero(placeholders)
+}
+
+func (suite *SQLStoreTestSuite) TestStoreAutoMigrate() {
+	sqlDriver := "sqlite3"
+	sqlDsn := path.Join(suite.sqlStoreRootPath, fmt.Sprintf("auto-migrate-%d.db", time.Now().UnixNano()))
+
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=%s
+SQLStoreDataSourceName=%s
+SQLStoreAutoMigrate=Y
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s
+`, sqlDriver, sqlDsn, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.NoError(suite.T(), err)
+
+	// Tables are created on an empty database.
+	store, err := NewStoreFactory(settings).Create(sessionID)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("hello")))
+	require.NoError(suite.T(), store.Close())
+
+	// Migrating an up to date schema is a no-op and keeps the stored data.
+	store, err = NewStoreFactory(settings).Create(sessionID)
+	require.NoError(suite.T(), err)
+	defer store.Close()
+	suite.Equal(2, store.NextSenderMsgSeqNum())
+
+	db, err := sql.Open(sqlDriver, sqlDsn)
+	require.NoError(suite.T(), err)
+	defer db.Close()
+	var versions, latest int
+	require.NoError(suite.T(), db.QueryRow(`SELECT COUNT(*), MAX(version) FROM quickfix_migrations`).Scan(&versions, &latest))
+	suite.Equal(len(migrations), versions)
+	suite.Equal(len(migrations), latest)
+}
+
+func (suite *SQLStoreTestSuite) TestStoreAutoMigrateTableNames() {
+	sqlDriver := "sqlite3"
+	sqlDsn := path.Join(suite.sqlStoreRootPath, fmt.Sprintf("migrate-tables-%d.db", time.Now().UnixNano()))
+	db, err := sql.Open(sqlDriver, sqlDsn)
+	require.NoError(suite.T(), err)
+	defer db.Close()
+
+	require.NoError(suite.T(), migrate(db, sqlDriver, sqlite3Dialect, "messages", "sessions"))
+
+	// Stores with other tables in the same database are migrated on their own.
+	require.NoError(suite.T(), migrate(db, sqlDriver, sqlite3Dialect, "other_messages", "other_sessions"))
+	var count int
+	require.NoError(suite.T(), db.QueryRow(`SELECT COUNT(*) FROM other_sessions`).Scan(&count))
+
+	// A failing version is rolled back and not recorded.
+	migrations = append(migrations, func(_ Dialect, messagesTable, _ string) []string {
+		return []string{
+			fmt.Sprintf(`ALTER TABLE %s ADD COLUMN added INTEGER`, messagesTable),
+			`NOT SQL`,
+		}
+	})
+	defer func() { migrations = migrations[:len(migrations)-1] }()
+	suite.ErrorContains(migrate(db, sqlDriver, sqlite3Dialect, "messages", "sessions"), fmt.Sprintf("migrate to version %d", len(migrations)))
+
+	var latest int
+	require.NoError(suite.T(), db.QueryRow(`SELECT MAX(version) FROM quickfix_migrations WHERE messages_table='messages'`).Scan(&latest))
+	suite.Equal(len(migrations)-1, latest)
+	_, err = db.Exec(`SELECT added FROM messages`)
+	suite.Error(err, "the column added by the failed version is rolled back")
+}
+
+func (suite *SQLStoreTestSuite) TestStoreAutoMigrateNoDialect() {
+	sqlDriver := "sqlite3_nodialect"
+	sqlDsn := path.Join(suite.sqlStoreRootPath, fmt.Sprintf("no-dialect-%d.db", time.Now().UnixNano()))
+
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=%s
+SQLStoreDataSourceName=%s
+SQLStoreAutoMigrate=Y
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s
+`, sqlDriver, sqlDsn, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.NoError(suite.T(), err)
+
+	_, err = NewStoreFactory(settings).Create(sessionID)
+	suite.Error(err)
+}
+
 func (suite *SQLStoreTestSuite) TearDownTest() {
 	suite.MsgStore.Close()
 	os.RemoveAll(suite.sqlStoreRootPath)
@@ -167,3 +432,302 @@ func (suite *SQLStoreTestSuite) TearDownTest() {
 func TestSqlStoreTestSuite(t *testing.T) {
 	suite.Run(t, new(SQLStoreTestSuite))
 }
+
+// SQLStoreWriteBehindTestSuite runs all tests in the MessageStoreTestSuite against the SqlStore implementation with write-behind enabled.
+type SQLStoreWriteBehindTestSuite struct {
+	SQLStoreTestSuite
+}
+
+func (suite *SQLStoreWriteBehindTestSuite) SetupTest() {
+	suite.sqlStoreRootPath = path.Join(os.TempDir(), fmt.Sprintf("SqlStoreWriteBehindTestSuite-%d", os.Getpid()))
+	err := os.MkdirAll(suite.sqlStoreRootPath, os.ModePerm)
+	require.Nil(suite.T(), err)
+	sqlDriver := "sqlite3"
+	sqlDsn := path.Join(suite.sqlStoreRootPath, fmt.Sprintf("%d.db", time.Now().UnixNano()))
+
+	// create tables
+	db, err := sql.Open(sqlDriver, sqlDsn)
+	require.Nil(suite.T(), err)
+	ddlFnames, err := filepath.Glob(fmt.Sprintf("../../_sql/%s/*.sql", sqlDriver))
+	require.Nil(suite.T(), err)
+	for _, fname := range ddlFnames {
+		sqlBytes, err := os.ReadFile(fname)
+		require.Nil(suite.T(), err)
+		_, err = db.Exec(string(sqlBytes))
+		require.Nil(suite.T(), err)
+	}
+
+	// create settings
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=%s
+SQLStoreDataSourceName=%s
+SQLStoreWriteBehind=Y
+SQLStoreFlushInterval=10ms
+SQLStoreFlushBatchSize=5
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, sqlDriver, sqlDsn, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	// create store
+	suite.MsgStore, err = NewStoreFactory(settings).Create(sessionID)
+	require.Nil(suite.T(), err)
+}
+
+func TestSqlStoreWriteBehindTestSuite(t *testing.T) {
+	suite.Run(t, new(SQLStoreWriteBehindTestSuite))
+}
+
+// SQLStoreFencedTestSuite runs all tests in the MessageStoreTestSuite against a fenced SqlStore holding the current token.
+type SQLStoreFencedTestSuite struct {
+	SQLStoreTestSuite
+	db    *sql.DB
+	token atomic.Int64
+}
+
+func (suite *SQLStoreFencedTestSuite) SetupTest() {
+	suite.sqlStoreRootPath = path.Join(os.TempDir(), fmt.Sprintf("SqlStoreFencedTestSuite-%d", os.Getpid()))
+	err := os.MkdirAll(suite.sqlStoreRootPath, os.ModePerm)
+	require.Nil(suite.T(), err)
+	sqlDriver := "sqlite3"
+	sqlDsn := path.Join(suite.sqlStoreRootPath, fmt.Sprintf("%d.db", time.Now().UnixNano()))
+
+	// create tables
+	suite.db, err = sql.Open(sqlDriver, sqlDsn)
+	require.Nil(suite.T(), err)
+	ddlFnames, err := filepath.Glob(fmt.Sprintf("../../_sql/%s/*.sql", sqlDriver))
+	require.Nil(suite.T(), err)
+	for _, fname := range ddlFnames {
+		sqlBytes, err := os.ReadFile(fname)
+		require.Nil(suite.T(), err)
+		_, err = suite.db.Exec(string(sqlBytes))
+		require.Nil(suite.T(), err)
+	}
+	_, err = suite.db.Exec(`CREATE TABLE leases (name VARCHAR(64) NOT NULL PRIMARY KEY, token BIGINT NOT NULL)`)
+	require.Nil(suite.T(), err)
+	_, err = suite.db.Exec(`INSERT INTO leases (name, token) VALUES ('fix', 1)`)
+	require.Nil(suite.T(), err)
+	suite.token.Store(1)
+
+	// create settings
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=%s
+SQLStoreDataSourceName=%s
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, sqlDriver, sqlDsn, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	// create store
+	suite.MsgStore, err = NewFencedStoreFactory(settings, Fence{Table: "leases", Name: "fix", Token: suite.token.Load}).Create(sessionID)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *SQLStoreFencedTestSuite) TearDownTest() {
+	suite.db.Close()
+	suite.SQLStoreTestSuite.TearDownTest()
+}
+
+func (suite *SQLStoreFencedTestSuite) TestStaleToken() {
+	require.NoError(suite.T(), suite.MsgStore.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("msg1")))
+
+	// Another node acquired the lease.
+	_, err := suite.db.Exec(`UPDATE leases SET token = 2 WHERE name = 'fix'`)
+	require.NoError(suite.T(), err)
+
+	suite.ErrorIs(suite.MsgStore.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("msg2")), ErrFenced)
+	suite.ErrorIs(suite.MsgStore.SetNextTargetMsgSeqNum(5), ErrFenced)
+	suite.ErrorIs(suite.MsgStore.Reset(), ErrFenced)
+
+	var count, outgoing, incoming int
+	require.NoError(suite.T(), suite.db.QueryRow(`SELECT COUNT(*) FROM messages`).Scan(&count))
+	require.NoError(suite.T(), suite.db.QueryRow(`SELECT outgoing_seqnum, incoming_seqnum FROM sessions`).Scan(&outgoing, &incoming))
+	suite.Equal(1, count)
+	suite.Equal(2, outgoing)
+	suite.Equal(1, incoming)
+
+	// Writes with the current token succeed, and tokens below 1 are never current.
+	suite.token.Store(2)
+	suite.NoError(suite.MsgStore.SetNextTargetMsgSeqNum(5))
+	suite.token.Store(0)
+	_, err = suite.db.Exec(`UPDATE leases SET token = 0 WHERE name = 'fix'`)
+	require.NoError(suite.T(), err)
+	suite.ErrorIs(suite.MsgStore.SetNextTargetMsgSeqNum(6), ErrFenced)
+}
+
+func (suite *SQLStoreFencedTestSuite) TestStaleTokenWriteBehind() {
+	store := suite.MsgStore.(*sqlStore)
+	store.writeBehind = newWriteBehind(store.db, time.Hour, 1000)
+
+	// Queued writes are checked with the token they were queued with.
+	require.NoError(suite.T(), store.SetNextTargetMsgSeqNum(5))
+	_, err := suite.db.Exec(`UPDATE leases SET token = 2 WHERE name = 'fix'`)
+	require.NoError(suite.T(), err)
+	suite.token.Store(2)
+	suite.ErrorIs(store.Flush(), ErrFenced)
+}
+
+func TestSqlStoreFencedTestSuite(t *testing.T) {
+	suite.Run(t, new(SQLStoreFencedTestSuite))
+}
+
+// SQLStoreCompressionTestSuite runs all tests in the MessageStoreTestSuite against a SqlStore compressing messages.
+type SQLStoreCompressionTestSuite struct {
+	SQLStoreTestSuite
+}
+
+func (suite *SQLStoreCompressionTestSuite) SetupTest() {
+	suite.sqlStoreRootPath = path.Join(os.TempDir(), fmt.Sprintf("SqlStoreCompressionTestSuite-%d", os.Getpid()))
+	err := os.MkdirAll(suite.sqlStoreRootPath, os.ModePerm)
+	require.Nil(suite.T(), err)
+	sqlDriver := "sqlite3"
+	sqlDsn := path.Join(suite.sqlStoreRootPath, fmt.Sprintf("%d.db", time.Now().UnixNano()))
+
+	// create settings
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=%s
+SQLStoreDataSourceName=%s
+SQLStoreAutoMigrate=Y
+MessageStoreCompression=gzip
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, sqlDriver, sqlDsn, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	// create store
+	suite.MsgStore, err = NewStoreFactory(settings).Create(sessionID)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *SQLStoreCompressionTestSuite) TestMessagesStoredAsText() {
+	msg := []byte("8=FIX.4.4\x019=12\x0135=0\x01")
+	require.Nil(suite.T(), suite.MsgStore.SaveMessage(1, msg))
+
+	store := suite.MsgStore.(*sqlStore)
+	var message string
+	require.Nil(suite.T(), store.db.QueryRow(`SELECT message FROM messages`).Scan(&message))
+	suite.True(strings.HasPrefix(message, "H4sI"), message)
+
+	msgs, err := suite.MsgStore.GetMessages(1, 1)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{msg}, msgs)
+}
+
+func TestSqlStoreCompressionTestSuite(t *testing.T) {
+	suite.Run(t, new(SQLStoreCompressionTestSuite))
+}
+
+// SQLStoreCacheTestSuite runs all tests in the MessageStoreTestSuite against a SqlStore caching recent messages.
+type SQLStoreCacheTestSuite struct {
+	SQLStoreTestSuite
+}
+
+func (suite *SQLStoreCacheTestSuite) SetupTest() {
+	suite.sqlStoreRootPath = path.Join(os.TempDir(), fmt.Sprintf("SqlStoreCacheTestSuite-%d", os.Getpid()))
+	err := os.MkdirAll(suite.sqlStoreRootPath, os.ModePerm)
+	require.Nil(suite.T(), err)
+	sqlDriver := "sqlite3"
+	sqlDsn := path.Join(suite.sqlStoreRootPath, fmt.Sprintf("%d.db", time.Now().UnixNano()))
+
+	// create settings
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=%s
+SQLStoreDataSourceName=%s
+SQLStoreAutoMigrate=Y
+MessageStoreCacheSize=2
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, sqlDriver, sqlDsn, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	// create store
+	suite.MsgStore, err = NewStoreFactory(settings).Create(sessionID)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *SQLStoreCacheTestSuite) TestCacheFallsBackToDatabase() {
+	for seqNum := 1; seqNum <= 3; seqNum++ {
+		msg := []byte(fmt.Sprintf("msg%d", seqNum))
+		require.Nil(suite.T(), suite.MsgStore.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, msg))
+	}
+
+	// Only the two most recent messages are cached, so they are served after the database rows are gone.
+	store := suite.MsgStore.(*sqlStore)
+	_, err := store.db.Exec(`DELETE FROM messages WHERE msgseqnum > 1`)
+	require.Nil(suite.T(), err)
+
+	msgs, err := suite.MsgStore.GetMessages(2, 10)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{[]byte("msg2"), []byte("msg3")}, msgs)
+
+	// The evicted message is read from the database.
+	msgs, err = suite.MsgStore.GetMessages(1, 3)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{[]byte("msg1")}, msgs)
+}
+
+func TestSqlStoreCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(SQLStoreCacheTestSuite))
+}
+
+func TestSqlStoreConnPoolSettings(t *testing.T) {
+	sqlStoreRootPath := t.TempDir()
+	sqlDsn := path.Join(sqlStoreRootPath, "pool.db")
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=sqlite3
+SQLStoreDataSourceName=%s
+SQLStoreAutoMigrate=Y
+SQLStoreMaxOpenConns=4
+SQLStoreMaxIdleConns=1
+SQLStoreConnMaxIdleTime=30s
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, sqlDsn, sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.Nil(t, err)
+
+	store, err := NewStoreFactory(settings).Create(sessionID)
+	require.Nil(t, err)
+	defer store.Close()
+
+	sqlStore := store.(*sqlStore)
+	require.Equal(t, 4, sqlStore.db.Stats().MaxOpenConnections)
+	require.Equal(t, connPool{connMaxIdleTime: 30 * time.Second, maxOpenConns: 4, maxIdleConns: 1}, sqlStore.connPool)
+}
+
+func TestSqlStoreInvalidMaxOpenConns(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+SQLStoreDriver=sqlite3
+SQLStoreDataSourceName=%s
+SQLStoreMaxOpenConns=-1
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, path.Join(t.TempDir(), "pool.db"), sessionID.BeginString, sessionID.SenderCompID, sessionID.TargetCompID)))
+	require.Nil(t, err)
+
+	_, err = NewStoreFactory(settings).Create(sessionID)
+	require.Equal(t, quickfix.IncorrectFormatForSetting{Setting: "SQLStoreMaxOpenConns", Value: []byte("-1")}, err)
+}
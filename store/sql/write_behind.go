@@ -0,0 +1,180 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package sql
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultFlushInterval  = 100 * time.Millisecond
+	defaultFlushBatchSize = 100
+
+	// maxFlushAttempts is the number of times a batch is executed before its statements are dropped.
+	maxFlushAttempts = 3
+)
+
+// pendingStatement is a write queued by the write-behind buffer.
+type pendingStatement struct {
+	query string
+	args  []interface{}
+
+	// check, if set, is called instead of executing query, and fails the transaction if it returns an error.
+	check func(tx *sql.Tx) error
+}
+
+// execTx executes stmt in tx.
+func (stmt pendingStatement) execTx(tx *sql.Tx) error {
+	if stmt.check != nil {
+		return stmt.check(tx)
+	}
+	_, err := tx.Exec(stmt.query, stmt.args...)
+	return err
+}
+
+// writeBehind queues store writes and executes them in batches, one transaction per batch.
+// Writes are flushed every flushInterval, or as soon as batchSize statements are queued.
+// A failing batch is retried by the following flushes and dropped after maxFlushAttempts.
+type writeBehind struct {
+	db        *sql.DB
+	batchSize int
+
+	mu       sync.Mutex
+	flushMu  sync.Mutex
+	pending  []pendingStatement
+	attempts int
+	dropped  error
+
+	full chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newWriteBehind(db *sql.DB, flushInterval time.Duration, batchSize int) *writeBehind {
+	w := &writeBehind{
+		db:        db,
+		batchSize: batchSize,
+		full:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go w.run(flushInterval)
+	return w
+}
+
+func (w *writeBehind) run(flushInterval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.full:
+		case <-w.stop:
+			return
+		}
+		_ = w.execPending()
+	}
+}
+
+// enqueue queues statements to be executed together in the next batch.
+func (w *writeBehind) enqueue(stmts ...pendingStatement) error {
+	w.mu.Lock()
+	w.pending = append(w.pending, stmts...)
+	full := len(w.pending) >= w.batchSize
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.full <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// flush executes all queued statements in a single transaction. It returns the error of this flush, or else the
+// error of statements dropped by a background flush since the last call.
+func (w *writeBehind) flush() error {
+	err := w.execPending()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err == nil || err == w.dropped {
+		err, w.dropped = w.dropped, nil
+	}
+	return err
+}
+
+// execPending executes all queued statements in a single transaction. On failure the statements are kept at the
+// head of the queue to be retried on the next flush, unless they failed maxFlushAttempts times and are dropped.
+func (w *writeBehind) execPending() error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	batch := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := w.exec(batch)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err == nil {
+		w.attempts = 0
+		return nil
+	}
+	if w.attempts++; w.attempts < maxFlushAttempts {
+		w.pending = append(batch, w.pending...)
+		return err
+	}
+	w.attempts = 0
+	w.dropped = errors.Wrapf(err, "write-behind dropped %d statements after %d attempts", len(batch), maxFlushAttempts)
+	return w.dropped
+}
+
+func (w *writeBehind) exec(batch []pendingStatement) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "write-behind begin")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, stmt := range batch {
+		if err = stmt.execTx(tx); err != nil {
+			return errors.Wrap(err, "write-behind exec")
+		}
+	}
+
+	return errors.Wrap(tx.Commit(), "write-behind commit")
+}
+
+// close stops the background flusher and flushes any remaining writes.
+func (w *writeBehind) close() error {
+	close(w.stop)
+	<-w.done
+	return w.flush()
+}
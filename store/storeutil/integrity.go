@@ -0,0 +1,122 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package storeutil provides tooling to inspect MessageStore implementations.
+package storeutil
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const tagMsgSeqNum quickfix.Tag = 34
+
+// GapKind is the kind of problem described by a Gap.
+type GapKind int
+
+const (
+	// Missing is a range of seqnums with no saved message.
+	Missing GapKind = iota
+
+	// Duplicate is a seqnum saved more than once.
+	Duplicate
+
+	// OutOfOrder is a seqnum returned by the store after a higher one, i.e. persisted out of order.
+	OutOfOrder
+)
+
+func (k GapKind) String() string {
+	switch k {
+	case Missing:
+		return "missing"
+	case Duplicate:
+		return "duplicate"
+	case OutOfOrder:
+		return "out of order"
+	}
+	return fmt.Sprintf("GapKind(%d)", int(k))
+}
+
+// Gap is a problem found in the saved messages of a store.
+type Gap struct {
+	Kind        GapKind
+	BeginSeqNum int
+	EndSeqNum   int
+}
+
+func (g Gap) String() string {
+	if g.BeginSeqNum == g.EndSeqNum {
+		return fmt.Sprintf("%v: %d", g.Kind, g.BeginSeqNum)
+	}
+	return fmt.Sprintf("%v: %d-%d", g.Kind, g.BeginSeqNum, g.EndSeqNum)
+}
+
+// VerifyIntegrity scans the messages saved in store from beginSeqNum to endSeqNum and returns the gaps found,
+// in seqnum order. An endSeqNum of 0 scans up to the last sent message.
+// Seqnums are read from the MsgSeqNum field of the saved messages. Stores that keep every save of a seqnum,
+// like the file store, report messages saved again after a sequence reset as Duplicate.
+func VerifyIntegrity(store quickfix.MessageStore, beginSeqNum, endSeqNum int) ([]Gap, error) {
+	if endSeqNum <= 0 {
+		endSeqNum = store.NextSenderMsgSeqNum() - 1
+	}
+
+	var gaps []Gap
+	seen := make(map[int]bool)
+	last := 0
+	err := store.IterateMessages(beginSeqNum, endSeqNum, func(raw []byte) error {
+		msg := quickfix.NewMessage()
+		if err := quickfix.ParseMessage(msg, bytes.NewBuffer(raw)); err != nil {
+			return errors.Wrapf(err, "parse message after seqnum %d", last)
+		}
+		seqNum, err := msg.Header.GetInt(tagMsgSeqNum)
+		if err != nil {
+			return errors.Wrapf(err, "msg seq num after seqnum %d", last)
+		}
+
+		switch {
+		case seen[seqNum]:
+			gaps = append(gaps, Gap{Kind: Duplicate, BeginSeqNum: seqNum, EndSeqNum: seqNum})
+		case seqNum < last:
+			gaps = append(gaps, Gap{Kind: OutOfOrder, BeginSeqNum: seqNum, EndSeqNum: seqNum})
+		}
+		seen[seqNum] = true
+		if seqNum > last {
+			last = seqNum
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for seqNum := beginSeqNum; seqNum <= endSeqNum; seqNum++ {
+		if seen[seqNum] {
+			continue
+		}
+		if n := len(gaps); n > 0 && gaps[n-1].Kind == Missing && gaps[n-1].EndSeqNum == seqNum-1 {
+			gaps[n-1].EndSeqNum = seqNum
+		} else {
+			gaps = append(gaps, Gap{Kind: Missing, BeginSeqNum: seqNum, EndSeqNum: seqNum})
+		}
+	}
+
+	sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].BeginSeqNum < gaps[j].BeginSeqNum })
+	return gaps, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package storeutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func message(seqNum int) []byte {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(8), "FIX.4.4")
+	msg.Header.SetString(quickfix.Tag(35), "0")
+	msg.Header.SetInt(tagMsgSeqNum, seqNum)
+	return []byte(msg.String())
+}
+
+// orderedStore returns its saved messages in the order they were saved, like the file store.
+type orderedStore struct {
+	quickfix.MessageStore
+	saved [][]byte
+}
+
+func (s *orderedStore) SaveMessage(_ int, msg []byte) error {
+	s.saved = append(s.saved, msg)
+	return nil
+}
+
+func (s *orderedStore) IterateMessages(_, _ int, cb func([]byte) error) error {
+	for _, msg := range s.saved {
+		if err := cb(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestVerifyIntegrity(t *testing.T) {
+	store, err := quickfix.NewMemoryStoreFactory().Create(quickfix.SessionID{})
+	require.Nil(t, err)
+	for _, seqNum := range []int{1, 2, 5, 8} {
+		require.Nil(t, store.SaveMessage(seqNum, message(seqNum)))
+	}
+	require.Nil(t, store.SetNextSenderMsgSeqNum(10))
+
+	gaps, err := VerifyIntegrity(store, 1, 0)
+	require.Nil(t, err)
+	assert.Equal(t, []Gap{
+		{Kind: Missing, BeginSeqNum: 3, EndSeqNum: 4},
+		{Kind: Missing, BeginSeqNum: 6, EndSeqNum: 7},
+		{Kind: Missing, BeginSeqNum: 9, EndSeqNum: 9},
+	}, gaps)
+	assert.Equal(t, "missing: 3-4", gaps[0].String())
+}
+
+func TestVerifyIntegrityDuplicateAndOutOfOrder(t *testing.T) {
+	inner, err := quickfix.NewMemoryStoreFactory().Create(quickfix.SessionID{})
+	require.Nil(t, err)
+	store := &orderedStore{MessageStore: inner}
+	for _, seqNum := range []int{1, 3, 2, 3} {
+		require.Nil(t, store.SaveMessage(seqNum, message(seqNum)))
+	}
+
+	gaps, err := VerifyIntegrity(store, 1, 3)
+	require.Nil(t, err)
+	assert.Equal(t, []Gap{
+		{Kind: OutOfOrder, BeginSeqNum: 2, EndSeqNum: 2},
+		{Kind: Duplicate, BeginSeqNum: 3, EndSeqNum: 3},
+	}, gaps)
+}
+
+func TestVerifyIntegrityCorruptMessage(t *testing.T) {
+	store, err := quickfix.NewMemoryStoreFactory().Create(quickfix.SessionID{})
+	require.Nil(t, err)
+	require.Nil(t, store.SaveMessage(1, []byte("garbage")))
+
+	_, err = VerifyIntegrity(store, 1, 1)
+	assert.NotNil(t, err)
+}
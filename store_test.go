@@ -0,0 +1,98 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// compactingStore is a MessageStore counting its compactions.
+type compactingStore struct {
+	MessageStore
+	compactions int
+	err         error
+}
+
+func (s *compactingStore) Compact() error {
+	s.compactions++
+	return s.err
+}
+
+type compactingStoreFactory struct {
+	stores map[SessionID]*compactingStore
+	err    error
+}
+
+func (f *compactingStoreFactory) Create(sessionID SessionID) (MessageStore, error) {
+	inner, err := NewMemoryStoreFactory().Create(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	store := &compactingStore{MessageStore: inner, err: f.err}
+	f.stores[sessionID] = store
+	return store, nil
+}
+
+func TestCompact(t *testing.T) {
+	settings := func() *Settings {
+		settings := NewSettings()
+		settings.GlobalSettings().Set(config.SocketAcceptPort, "5029")
+		for _, target := range []string{"A", "B"} {
+			sessionSettings := NewSessionSettings()
+			sessionSettings.Set(config.BeginString, BeginStringFIX42)
+			sessionSettings.Set(config.SenderCompID, "S")
+			sessionSettings.Set(config.TargetCompID, target)
+			sessionSettings.Set(config.SocketConnectHost, "localhost")
+			sessionSettings.Set(config.SocketConnectPort, "5029")
+			sessionSettings.Set(config.HeartBtInt, "30")
+			_, err := settings.AddSession(sessionSettings)
+			require.NoError(t, err)
+		}
+		return settings
+	}
+
+	factory := &compactingStoreFactory{stores: make(map[SessionID]*compactingStore)}
+	acceptor, err := NewAcceptor(&MockApp{}, factory, settings(), NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, acceptor.Compact())
+	require.Len(t, factory.stores, 2)
+	for _, store := range factory.stores {
+		assert.Equal(t, 1, store.compactions)
+	}
+	for sessionID := range factory.stores {
+		require.NoError(t, UnregisterSession(sessionID))
+	}
+
+	errFailing := errors.New("failing")
+	factory = &compactingStoreFactory{stores: make(map[SessionID]*compactingStore), err: errFailing}
+	initiator, err := NewInitiator(&MockApp{}, factory, settings(), NewNullLogFactory())
+	require.NoError(t, err)
+
+	// Every store is compacted even if one fails.
+	assert.ErrorIs(t, initiator.Compact(), errFailing)
+	for _, store := range factory.stores {
+		assert.Equal(t, 1, store.compactions)
+	}
+	for sessionID := range factory.stores {
+		require.NoError(t, UnregisterSession(sessionID))
+	}
+}
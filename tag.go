@@ -71,6 +71,13 @@ const (
 	tagNewSeqNo             Tag = 36
 	tagBeginSeqNo           Tag = 7
 	tagEndSeqNo             Tag = 16
+	tagUsername             Tag = 553
+	tagPassword             Tag = 554
+	tagRawData              Tag = 96
+	tagRawDataLength        Tag = 95
+	tagNewPassword          Tag = 925
+	tagClOrdID              Tag = 11
+	tagExecID               Tag = 17
 
 	tagSignatureLength Tag = 93
 	tagSignature       Tag = 89
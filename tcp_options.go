@@ -0,0 +1,183 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"syscall"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// tcpOptions are the socket options configured for TCP connections.
+type tcpOptions struct {
+	// noDelay is nil to keep the Go default, TCP_NODELAY enabled.
+	noDelay *bool
+
+	// keepAlive is nil to keep the Go default keep-alive.
+	keepAlive *net.KeepAliveConfig
+
+	sendBufferSize, receiveBufferSize int
+	reusePort                         bool
+}
+
+func loadTCPOptions(settings *SessionSettings) (options tcpOptions, err error) {
+	if settings.HasSetting(config.SocketNoDelay) {
+		var noDelay bool
+		if noDelay, err = settings.BoolSetting(config.SocketNoDelay); err != nil {
+			return
+		}
+		options.noDelay = &noDelay
+	}
+
+	if settings.HasSetting(config.SocketKeepAlive) || settings.HasSetting(config.SocketKeepAliveInterval) ||
+		settings.HasSetting(config.SocketKeepAliveCount) {
+		keepAlive := net.KeepAliveConfig{Enable: true, Idle: -1, Interval: -1, Count: -1}
+		if settings.HasSetting(config.SocketKeepAlive) {
+			if keepAlive.Enable, err = settings.BoolSetting(config.SocketKeepAlive); err != nil {
+				return
+			}
+		}
+
+		if settings.HasSetting(config.SocketKeepAliveInterval) {
+			if keepAlive.Interval, err = settings.DurationSetting(config.SocketKeepAliveInterval); err != nil {
+				return
+			}
+			if keepAlive.Interval <= 0 {
+				err = IncorrectFormatForSetting{Setting: config.SocketKeepAliveInterval, Value: []byte(keepAlive.Interval.String())}
+				return
+			}
+			// Probes start after an idle interval of the same length.
+			keepAlive.Idle = keepAlive.Interval
+		}
+
+		if settings.HasSetting(config.SocketKeepAliveCount) {
+			if keepAlive.Count, err = positiveIntSetting(settings, config.SocketKeepAliveCount); err != nil {
+				return
+			}
+		}
+		options.keepAlive = &keepAlive
+	}
+
+	if settings.HasSetting(config.SocketSendBufferSize) {
+		if options.sendBufferSize, err = positiveIntSetting(settings, config.SocketSendBufferSize); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SocketReceiveBufferSize) {
+		if options.receiveBufferSize, err = positiveIntSetting(settings, config.SocketReceiveBufferSize); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SocketReusePort) {
+		if options.reusePort, err = settings.BoolSetting(config.SocketReusePort); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func positiveIntSetting(settings *SessionSettings, setting string) (int, error) {
+	val, err := settings.IntSetting(setting)
+	if err != nil {
+		return 0, err
+	}
+	if val <= 0 {
+		return 0, IncorrectFormatForSetting{Setting: setting, Value: []byte(strconv.Itoa(val))}
+	}
+
+	return val, nil
+}
+
+// apply sets the options on conn if it is a TCP connection.
+func (o tcpOptions) apply(conn net.Conn) error {
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+
+	if o.noDelay != nil {
+		if err := tcpConn.SetNoDelay(*o.noDelay); err != nil {
+			return err
+		}
+	}
+
+	if o.keepAlive != nil {
+		if err := tcpConn.SetKeepAliveConfig(*o.keepAlive); err != nil {
+			return err
+		}
+	}
+
+	if o.sendBufferSize > 0 {
+		if err := tcpConn.SetWriteBuffer(o.sendBufferSize); err != nil {
+			return err
+		}
+	}
+
+	if o.receiveBufferSize > 0 {
+		if err := tcpConn.SetReadBuffer(o.receiveBufferSize); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listen listens on a TCP address, setting SO_REUSEPORT if configured.
+func (o tcpOptions) listen(address string) (net.Listener, error) {
+	var lc net.ListenConfig
+	if o.reusePort {
+		lc.Control = func(_, _ string, c syscall.RawConn) error {
+			return setReusePort(c)
+		}
+	}
+
+	listener, err := lc.Listen(context.Background(), "tcp", address)
+	if err != nil || !o.hasConnOptions() {
+		return listener, err
+	}
+
+	return tcpOptionsListener{Listener: listener, options: o}, nil
+}
+
+func (o tcpOptions) hasConnOptions() bool {
+	return o.noDelay != nil || o.keepAlive != nil || o.sendBufferSize > 0 || o.receiveBufferSize > 0
+}
+
+// tcpOptionsListener applies tcpOptions to the connections it accepts.
+type tcpOptionsListener struct {
+	net.Listener
+	options tcpOptions
+}
+
+func (l tcpOptionsListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.options.apply(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
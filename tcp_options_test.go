@@ -0,0 +1,105 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestLoadTCPOptions(t *testing.T) {
+	settings := NewSessionSettings()
+	options, err := loadTCPOptions(settings)
+	require.NoError(t, err)
+	assert.Nil(t, options.noDelay)
+	assert.Nil(t, options.keepAlive)
+
+	settings.Set(config.SocketNoDelay, "N")
+	settings.Set(config.SocketKeepAliveInterval, "15s")
+	settings.Set(config.SocketKeepAliveCount, "3")
+	settings.Set(config.SocketSendBufferSize, "65536")
+	settings.Set(config.SocketReceiveBufferSize, "131072")
+	settings.Set(config.SocketReusePort, "Y")
+	options, err = loadTCPOptions(settings)
+	require.NoError(t, err)
+	require.NotNil(t, options.noDelay)
+	assert.False(t, *options.noDelay)
+	assert.Equal(t, &net.KeepAliveConfig{Enable: true, Idle: 15 * time.Second, Interval: 15 * time.Second, Count: 3}, options.keepAlive)
+	assert.Equal(t, 65536, options.sendBufferSize)
+	assert.Equal(t, 131072, options.receiveBufferSize)
+	assert.True(t, options.reusePort)
+
+	for _, invalid := range []struct{ setting, value string }{
+		{config.SocketKeepAliveInterval, "0s"},
+		{config.SocketKeepAliveCount, "0"},
+		{config.SocketSendBufferSize, "-1"},
+		{config.SocketNoDelay, "maybe"},
+	} {
+		settings := NewSessionSettings()
+		settings.Set(invalid.setting, invalid.value)
+		_, err := loadTCPOptions(settings)
+		assert.Error(t, err, invalid.setting)
+	}
+}
+
+func TestTCPOptionsListen(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SO_REUSEPORT is not supported on windows")
+	}
+
+	noDelay := false
+	options := tcpOptions{noDelay: &noDelay, receiveBufferSize: 65536, reusePort: true}
+	listener, err := options.listen("127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// A second listener can share the port.
+	shared, err := options.listen(listener.Addr().String())
+	require.NoError(t, err)
+	defer shared.Close()
+
+	go func() {
+		if conn, err := net.Dial("tcp", listener.Addr().String()); err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	var conn net.Conn
+	accepted := make(chan net.Conn, 2)
+	for _, l := range []net.Listener{listener, shared} {
+		go func(l net.Listener) {
+			if conn, err := l.Accept(); err == nil {
+				accepted <- conn
+			}
+		}(l)
+	}
+
+	select {
+	case conn = <-accepted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for connection")
+	}
+	defer conn.Close()
+	assert.IsType(t, &net.TCPConn{}, conn)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"net"
+	"strings"
+
+	proxyproto "github.com/pires/go-proxyproto"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// loadTCPProxyPolicy returns the policy for PROXY protocol headers configured by the
+// RequireTCPProxyHeader and TCPProxyTrustedAddresses settings.
+func loadTCPProxyPolicy(settings *SessionSettings) (proxyproto.PolicyFunc, error) {
+	var requireHeader bool
+	if settings.HasSetting(config.RequireTCPProxyHeader) {
+		var err error
+		if requireHeader, err = settings.BoolSetting(config.RequireTCPProxyHeader); err != nil {
+			return nil, err
+		}
+	}
+
+	var trusted []*net.IPNet
+	if settings.HasSetting(config.TCPProxyTrustedAddresses) {
+		var err error
+		if trusted, err = parseAddressList(settings, config.TCPProxyTrustedAddresses); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(upstream net.Addr) (proxyproto.Policy, error) {
+		if len(trusted) > 0 && !addressInList(upstream, trusted) {
+			// Only trusted load balancers may supply the client address.
+			return proxyproto.REJECT, nil
+		}
+
+		if requireHeader {
+			return proxyproto.REQUIRE, nil
+		}
+		return proxyproto.USE, nil
+	}, nil
+}
+
+// parseAddressList parses a comma separated list of IP addresses and CIDR blocks.
+func parseAddressList(settings *SessionSettings, setting string) ([]*net.IPNet, error) {
+	list, err := settings.Setting(setting)
+	if err != nil {
+		return nil, err
+	}
+
+	var networks []*net.IPNet
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, IncorrectFormatForSetting{Setting: setting, Value: []byte(list)}
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, IncorrectFormatForSetting{Setting: setting, Value: []byte(list), Err: err}
+		}
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// addressInList returns true if addr is an IP address within one of networks.
+func addressInList(addr net.Addr, networks []*net.IPNet) bool {
+	var ip net.IP
+	switch addr := addr.(type) {
+	case *net.TCPAddr:
+		ip = addr.IP
+	case *net.UDPAddr:
+		ip = addr.IP
+	case *net.IPAddr:
+		ip = addr.IP
+	default:
+		return false
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
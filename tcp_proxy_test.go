@@ -0,0 +1,89 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	proxyproto "github.com/pires/go-proxyproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestTCPProxyPolicy(t *testing.T) {
+	settings := NewSessionSettings()
+	policy, err := loadTCPProxyPolicy(settings)
+	require.NoError(t, err)
+	loadBalancer := &net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 4000}
+	client := &net.TCPAddr{IP: net.ParseIP("192.168.1.20"), Port: 4000}
+
+	p, err := policy(client)
+	require.NoError(t, err)
+	assert.Equal(t, proxyproto.USE, p)
+
+	settings.Set(config.RequireTCPProxyHeader, "Y")
+	settings.Set(config.TCPProxyTrustedAddresses, "10.0.0.0/8, 172.16.0.1")
+	policy, err = loadTCPProxyPolicy(settings)
+	require.NoError(t, err)
+
+	p, err = policy(loadBalancer)
+	require.NoError(t, err)
+	assert.Equal(t, proxyproto.REQUIRE, p)
+
+	p, err = policy(client)
+	require.NoError(t, err)
+	assert.Equal(t, proxyproto.REJECT, p, "untrusted upstreams may not send a header")
+
+	settings.Set(config.TCPProxyTrustedAddresses, "10.0.0.0/33")
+	_, err = loadTCPProxyPolicy(settings)
+	assert.Error(t, err)
+}
+
+func TestTCPProxyHeaderClientAddress(t *testing.T) {
+	settings := NewSessionSettings()
+	settings.Set(config.RequireTCPProxyHeader, "Y")
+	policy, err := loadTCPProxyPolicy(settings)
+	require.NoError(t, err)
+
+	base, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	listener := &proxyproto.Listener{Listener: base, Policy: policy}
+	defer listener.Close()
+
+	go func() {
+		conn, err := net.Dial("tcp", base.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("PROXY TCP4 203.0.113.7 127.0.0.1 40000 5001\r\n8=FIX.4.2"))
+		time.Sleep(time.Second)
+	}()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	buf := make([]byte, 9)
+	_, err = conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "8=FIX.4.2", string(buf))
+	assert.Equal(t, "203.0.113.7:40000", conn.RemoteAddr().String())
+}
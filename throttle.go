@@ -0,0 +1,142 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+// ThrottleMode determines what happens to application messages sent faster than the session's throttle allows.
+type ThrottleMode int
+
+const (
+	// ThrottleBlock blocks the sender until the message can be sent.
+	ThrottleBlock ThrottleMode = iota
+
+	// ThrottleQueue queues the message, and the session sends queued messages as the throttle allows.
+	// Queued messages are prepared for sending when they leave the queue,
+	// so errors from ToApp are logged rather than returned to the sender.
+	ThrottleQueue
+)
+
+// ThrottleListener may be implemented by an Application to be notified when a session starts throttling
+// outbound application messages.
+type ThrottleListener interface {
+	// OnThrottle is called when a message is delayed after messages had been sent without delay.
+	OnThrottle(sessionID SessionID)
+}
+
+type throttle struct {
+	mode   ThrottleMode
+	bucket *internal.TokenBucket
+
+	// Guarded by the session's sendMutex.
+	queue        []*Message
+	drainPending bool
+	engaged      bool
+}
+
+func (s *session) onThrottle() {
+	if listener, ok := s.application.(ThrottleListener); ok {
+		listener.OnThrottle(s.sessionID)
+	}
+}
+
+// throttleSend applies the throttle to an application message. It returns true if the message was queued.
+func (s *session) throttleSend(msg *Message) (bool, error) {
+	if s.throttle.mode == ThrottleBlock {
+		wait := s.throttle.bucket.Reserve()
+
+		s.sendMutex.Lock()
+		engage := wait > 0 && !s.throttle.engaged
+		s.throttle.engaged = wait > 0
+		s.sendMutex.Unlock()
+
+		if engage {
+			s.log.OnEvent("Throttling, delaying messages")
+			s.onThrottle()
+		}
+		if wait > 0 {
+			timer := s.clock.NewTimer(wait)
+			<-timer.C()
+		}
+		return false, nil
+	}
+
+	s.sendMutex.Lock()
+	if len(s.throttle.queue) == 0 {
+		if ok, _ := s.throttle.bucket.TryTake(); ok {
+			s.throttle.engaged = false
+			s.sendMutex.Unlock()
+			return false, nil
+		}
+	}
+
+	if err := s.reserveQueueSpace(msg); err != nil {
+		s.sendMutex.Unlock()
+		return false, err
+	}
+
+	s.throttle.queue = append(s.throttle.queue, msg)
+	engage := !s.throttle.engaged
+	s.throttle.engaged = true
+	s.notifyMessageOut()
+	s.sendMutex.Unlock()
+
+	if engage {
+		s.log.OnEvent("Throttling, queueing messages")
+		s.onThrottle()
+	}
+
+	return true, nil
+}
+
+// drainThrottleQueue moves messages that the throttle allows from the throttle queue to the send queue.
+// Must be called with the sendMutex held.
+func (s *session) drainThrottleQueue() {
+	if s.throttle == nil {
+		return
+	}
+
+	for len(s.throttle.queue) > 0 {
+		if s.IsLoggedOn() {
+			ok, wait := s.throttle.bucket.TryTake()
+			if !ok {
+				if !s.throttle.drainPending {
+					s.throttle.drainPending = true
+					s.afterFunc(wait, func() {
+						s.sendMutex.Lock()
+						s.throttle.drainPending = false
+						s.sendMutex.Unlock()
+						s.notifyMessageOut()
+					})
+				}
+				return
+			}
+		}
+
+		msg := s.throttle.queue[0]
+		s.throttle.queue[0] = nil
+		s.throttle.queue = s.throttle.queue[1:]
+
+		queued, err := s.prepMessageForSend(msg, nil)
+		if err != nil {
+			s.logError(err)
+			continue
+		}
+		s.toSend = append(s.toSend, queued)
+	}
+}
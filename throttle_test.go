@@ -0,0 +1,149 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+type throttleApp struct {
+	*MockApp
+	session   *session
+	throttled int
+	locked    bool
+}
+
+func (a *throttleApp) OnThrottle(SessionID) {
+	a.throttled++
+	if a.session.sendMutex.TryLock() {
+		a.session.sendMutex.Unlock()
+	} else {
+		a.locked = true
+	}
+}
+
+type ThrottleTestSuite struct {
+	SessionSuiteRig
+	app *throttleApp
+}
+
+func TestThrottleTestSuite(t *testing.T) {
+	suite.Run(t, new(ThrottleTestSuite))
+}
+
+func (s *ThrottleTestSuite) SetupTest() {
+	s.Init()
+	s.app = &throttleApp{MockApp: &s.MockApp, session: s.session}
+	s.session.application = s.app
+	s.session.State = inSession{}
+	s.MockApp.On("ToApp").Return(nil)
+}
+
+func (s *ThrottleTestSuite) sentSeqNum() int {
+	msgBytes, _ := s.Receiver.LastMessage()
+	s.Require().NotNil(msgBytes, "a message should have been sent")
+	msg := NewMessage()
+	s.Require().Nil(ParseMessage(msg, bytes.NewBuffer(msgBytes)))
+	seqNum, err := msg.Header.GetInt(tagMsgSeqNum)
+	s.Require().Nil(err)
+	return seqNum
+}
+
+func (s *ThrottleTestSuite) TestQueue() {
+	s.session.throttle = &throttle{mode: ThrottleQueue, bucket: internal.NewTokenBucket(100, 1, s.session.clock.Now)}
+
+	for i := 0; i < 3; i++ {
+		s.Require().Nil(s.session.queueForSend(s.NewOrderSingle()))
+	}
+	s.Equal(1, s.app.throttled)
+	s.False(s.app.locked, "OnThrottle should be called without the sendMutex held")
+	s.MockApp.AssertNumberOfCalls(s.T(), "ToApp", 1)
+	s.NextSenderMsgSeqNum(2)
+
+	s.session.SendAppMessages(s.session)
+	s.Equal(1, s.sentSeqNum())
+	s.NoMessageSent()
+
+	// Queued messages are given their MsgSeqNum as the throttle allows.
+	for _, expected := range []int{2, 3} {
+		time.Sleep(15 * time.Millisecond)
+		s.session.SendAppMessages(s.session)
+		s.Equal(expected, s.sentSeqNum())
+		s.NoMessageSent()
+	}
+
+	s.Equal(1, s.app.throttled)
+	s.NextSenderMsgSeqNum(4)
+}
+
+func (s *ThrottleTestSuite) TestQueueNotLoggedOn() {
+	s.session.throttle = &throttle{mode: ThrottleQueue, bucket: internal.NewTokenBucket(1, 1, s.session.clock.Now)}
+	s.session.State = latentState{}
+
+	for i := 0; i < 3; i++ {
+		s.Require().Nil(s.session.queueForSend(s.NewOrderSingle()))
+	}
+
+	// Messages are persisted, to be resent after logon.
+	s.session.SendAppMessages(s.session)
+	s.NoMessageSent()
+	s.Empty(s.session.throttle.queue)
+	s.NextSenderMsgSeqNum(4)
+}
+
+func (s *ThrottleTestSuite) TestBlock() {
+	clock := &fakeClock{now: time.Date(2024, time.March, 4, 9, 30, 0, 0, time.UTC)}
+	s.session.clock = clock
+	s.session.throttle = &throttle{mode: ThrottleBlock, bucket: internal.NewTokenBucket(50, 1, clock.Now)}
+
+	done := make(chan error)
+	go func() {
+		for i := 0; i < 3; i++ {
+			if err := s.session.queueForSend(s.NewOrderSingle()); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	// Senders wait on the session's clock.
+	for _, expected := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond} {
+		s.Eventually(func() bool {
+			clock.mu.Lock()
+			defer clock.mu.Unlock()
+			return len(clock.timers) > 0
+		}, time.Second, time.Millisecond)
+
+		clock.mu.Lock()
+		timer := clock.timers[0]
+		clock.timers = clock.timers[1:]
+		clock.mu.Unlock()
+
+		s.Equal(expected, timer.d)
+		timer.c <- clock.Now()
+	}
+
+	s.Require().Nil(<-done)
+	s.Equal(1, s.app.throttled)
+	s.NextSenderMsgSeqNum(4)
+}
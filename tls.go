@@ -21,6 +21,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/quickfixgo/quickfix/config"
 )
@@ -65,6 +68,12 @@ func loadTLSConfig(settings *SessionSettings) (*tls.Config, error) {
 	tlsConfig.InsecureSkipVerify = insecureSkipVerify
 	setMinVersionExplicit(settings, tlsConfig)
 
+	if settings.HasSetting(config.SocketCipherSuites) {
+		if tlsConfig.CipherSuites, err = parseCipherSuites(settings); err != nil {
+			return nil, err
+		}
+	}
+
 	if settings.HasSetting(config.SocketPrivateKeyFile) || settings.HasSetting(config.SocketCertificateFile) {
 
 		var privateKeyFile string
@@ -80,10 +89,27 @@ func loadTLSConfig(settings *SessionSettings) (*tls.Config, error) {
 			return nil, err
 		}
 
-		tlsConfig.Certificates = make([]tls.Certificate, 1)
+		reload := false
+		if settings.HasSetting(config.SocketCertificateReload) {
+			if reload, err = settings.BoolSetting(config.SocketCertificateReload); err != nil {
+				return nil, err
+			}
+		}
+
+		if reload {
+			reloader, err := newCertificateReloader(certificateFile, privateKeyFile)
+			if err != nil {
+				return nil, err
+			}
 
-		if tlsConfig.Certificates[0], err = tls.LoadX509KeyPair(certificateFile, privateKeyFile); err != nil {
-			return nil, fmt.Errorf("failed to load key pair: %w", err)
+			tlsConfig.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return reloader.load() }
+			tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return reloader.load() }
+		} else {
+			tlsConfig.Certificates = make([]tls.Certificate, 1)
+
+			if tlsConfig.Certificates[0], err = tls.LoadX509KeyPair(certificateFile, privateKeyFile); err != nil {
+				return nil, fmt.Errorf("failed to load key pair: %w", err)
+			}
 		}
 	} else if settings.HasSetting(config.SocketPrivateKeyBytes) || settings.HasSetting(config.SocketCertificateBytes) {
 		privateKeyBytes, err := settings.RawSetting(config.SocketPrivateKeyBytes)
@@ -110,6 +136,12 @@ func loadTLSConfig(settings *SessionSettings) (*tls.Config, error) {
 		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
 	}
 
+	if settings.HasSetting(config.SocketClientAuth) {
+		if tlsConfig.ClientAuth, err = parseClientAuth(settings); err != nil {
+			return nil, err
+		}
+	}
+
 	if !settings.HasSetting(config.SocketCAFile) && !settings.HasSetting(config.SocketCABytes) {
 		return tlsConfig, nil
 	}
@@ -180,6 +212,114 @@ func setMinVersionExplicit(settings *SessionSettings, tlsConfig *tls.Config) {
 			tlsConfig.MinVersion = tls.VersionTLS11
 		case "TLS12":
 			tlsConfig.MinVersion = tls.VersionTLS12
+		case "TLS13":
+			tlsConfig.MinVersion = tls.VersionTLS13
+		}
+	}
+}
+
+// parseCipherSuites returns the IDs of the comma separated cipher suite names of the SocketCipherSuites setting.
+func parseCipherSuites(settings *SessionSettings) ([]uint16, error) {
+	names, err := settings.Setting(config.SocketCipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	var ids []uint16
+	for _, name := range strings.Split(names, ",") {
+		id, ok := known[strings.TrimSpace(name)]
+		if !ok {
+			return nil, IncorrectFormatForSetting{Setting: config.SocketCipherSuites, Value: []byte(names), Err: fmt.Errorf("unknown or insecure cipher suite %q", name)}
 		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func parseClientAuth(settings *SessionSettings) (tls.ClientAuthType, error) {
+	clientAuth, err := settings.Setting(config.SocketClientAuth)
+	if err != nil {
+		return tls.NoClientCert, err
+	}
+
+	switch clientAuth {
+	case "NoClientCert":
+		return tls.NoClientCert, nil
+	case "RequestClientCert":
+		return tls.RequestClientCert, nil
+	case "RequireAnyClientCert":
+		return tls.RequireAnyClientCert, nil
+	case "VerifyClientCertIfGiven":
+		return tls.VerifyClientCertIfGiven, nil
+	case "RequireAndVerifyClientCert":
+		return tls.RequireAndVerifyClientCert, nil
 	}
+
+	return tls.NoClientCert, IncorrectFormatForSetting{Setting: config.SocketClientAuth, Value: []byte(clientAuth)}
+}
+
+// certificateReloader serves a key pair loaded from files, loading it again once either file is modified.
+type certificateReloader struct {
+	certificateFile, privateKeyFile string
+
+	mu          sync.Mutex
+	certificate *tls.Certificate
+	modTime     time.Time
+}
+
+func newCertificateReloader(certificateFile, privateKeyFile string) (*certificateReloader, error) {
+	r := &certificateReloader{certificateFile: certificateFile, privateKeyFile: privateKeyFile}
+	if _, err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// load returns the current key pair. If the files cannot be loaded, e.g. while they are being replaced,
+// the previously loaded key pair is returned.
+func (r *certificateReloader) load() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTime, err := latestModTime(r.certificateFile, r.privateKeyFile)
+	if err == nil && r.certificate != nil && !modTime.After(r.modTime) {
+		return r.certificate, nil
+	}
+
+	var certificate tls.Certificate
+	if err == nil {
+		certificate, err = tls.LoadX509KeyPair(r.certificateFile, r.privateKeyFile)
+	}
+	if err != nil {
+		if r.certificate != nil {
+			return r.certificate, nil
+		}
+		return nil, fmt.Errorf("failed to load key pair: %w", err)
+	}
+
+	r.certificate = &certificate
+	r.modTime = modTime
+	return r.certificate, nil
+}
+
+func latestModTime(files ...string) (latest time.Time, err error) {
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return
 }
@@ -18,7 +18,9 @@ package quickfix
 import (
 	"crypto/tls"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -240,6 +242,84 @@ func (s *TLSTestSuite) TestMinimumTLSVersion() {
 	s.Nil(err)
 	s.NotNil(tlsConfig)
 	s.Equal(tlsConfig.MinVersion, uint16(tls.VersionTLS12))
+
+	// TLS13
+	s.settings.GlobalSettings().Set(config.SocketMinimumTLSVersion, "TLS13")
+	tlsConfig, err = loadTLSConfig(s.settings.GlobalSettings())
+
+	s.Nil(err)
+	s.NotNil(tlsConfig)
+	s.Equal(tlsConfig.MinVersion, uint16(tls.VersionTLS13))
+}
+
+func (s *TLSTestSuite) TestCipherSuites() {
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, s.PrivateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, s.CertificateFile)
+	s.settings.GlobalSettings().Set(config.SocketCipherSuites, "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384")
+
+	tlsConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.Nil(err)
+	s.Require().NotNil(tlsConfig)
+	s.Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, tlsConfig.CipherSuites)
+
+	s.settings.GlobalSettings().Set(config.SocketCipherSuites, "TLS_RSA_WITH_RC4_128_SHA")
+	_, err = loadTLSConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+}
+
+func (s *TLSTestSuite) TestClientAuth() {
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, s.PrivateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, s.CertificateFile)
+	s.settings.GlobalSettings().Set(config.SocketClientAuth, "VerifyClientCertIfGiven")
+
+	tlsConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.Nil(err)
+	s.Require().NotNil(tlsConfig)
+	s.Equal(tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+
+	s.settings.GlobalSettings().Set(config.SocketClientAuth, "Always")
+	_, err = loadTLSConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+}
+
+func (s *TLSTestSuite) TestCertificateReload() {
+	dir := s.T().TempDir()
+	privateKeyFile := filepath.Join(dir, "localhost.key")
+	certificateFile := filepath.Join(dir, "localhost.crt")
+	s.Require().NoError(os.WriteFile(privateKeyFile, s.PrivateKeyBytes, 0600))
+	s.Require().NoError(os.WriteFile(certificateFile, s.CertificateBytes, 0600))
+
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, privateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, certificateFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateReload, "Y")
+
+	tlsConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.Nil(err)
+	s.Require().NotNil(tlsConfig)
+	s.Empty(tlsConfig.Certificates)
+
+	certificate, err := tlsConfig.GetCertificate(nil)
+	s.Nil(err)
+	s.Require().NotNil(certificate)
+
+	unchanged, err := tlsConfig.GetClientCertificate(nil)
+	s.Nil(err)
+	s.Same(certificate, unchanged)
+
+	// A broken key pair is ignored until it is replaced.
+	modTime := time.Now().Add(time.Minute)
+	s.Require().NoError(os.WriteFile(certificateFile, []byte("renewing"), 0600))
+	s.Require().NoError(os.Chtimes(certificateFile, modTime, modTime))
+	unchanged, err = tlsConfig.GetCertificate(nil)
+	s.Nil(err)
+	s.Same(certificate, unchanged)
+
+	s.Require().NoError(os.WriteFile(certificateFile, s.CertificateBytes, 0600))
+	s.Require().NoError(os.Chtimes(certificateFile, modTime, modTime))
+	reloaded, err := tlsConfig.GetCertificate(nil)
+	s.Nil(err)
+	s.NotSame(certificate, reloaded)
+	s.Equal(certificate.Certificate, reloaded.Certificate)
 }
 
 func (s *TLSTestSuite) TestLoadTLSBytesMissingKeyOrCert() {
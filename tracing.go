@@ -0,0 +1,101 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "context"
+
+// Span names reported to a Tracer. Inbound messages are traced by a SpanInbound span with the children
+// SpanParse, SpanValidate, SpanCallback and SpanStore, outbound messages by a SpanOutbound span with the children
+// SpanToApp, SpanSerialize, SpanStore and SpanWrite.
+const (
+	SpanInbound   = "fix.inbound"
+	SpanParse     = "fix.parse"
+	SpanValidate  = "fix.validate"
+	SpanCallback  = "fix.callback"
+	SpanStore     = "fix.store"
+	SpanOutbound  = "fix.outbound"
+	SpanToApp     = "fix.to_app"
+	SpanSerialize = "fix.serialize"
+	SpanWrite     = "fix.write"
+)
+
+// Span is a stage of the lifecycle of a message started by a Tracer.
+type Span interface {
+	// SetMessage records the MsgType and MsgSeqNum of the message, once they are known.
+	SetMessage(msgType string, msgSeqNum int)
+
+	// End ends the span, err is the error the stage failed with and may be nil.
+	End(err error)
+}
+
+// Tracer traces the lifecycle of messages, e.g. with OpenTelemetry.
+// It is called from the goroutines of sessions and should not block.
+type Tracer interface {
+	// Start starts a span that is a child of any span of ctx, returning a context carrying the new span.
+	Start(ctx context.Context, name string, sessionID SessionID) (context.Context, Span)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetMessage(string, int) {}
+func (noopSpan) End(error)              {}
+
+// Context returns the context of the message. For inbound messages passed to FromApp and FromAdmin it carries the
+// SpanInbound span of the message. For outbound messages it is the context set with SetContext, and
+// carries the SpanOutbound span when passed to ToApp and ToAdmin.
+func (m *Message) Context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// SetContext sets the context of an outbound message, the spans of sending the message are children of any span of ctx.
+func (m *Message) SetContext(ctx context.Context) {
+	m.ctx = ctx
+}
+
+func (s *session) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if s.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return s.tracer.Start(ctx, name, s.sessionID)
+}
+
+// startMessageSpan starts a span for a stage of msg, recording its MsgType and MsgSeqNum.
+func (s *session) startMessageSpan(msg *Message, name string) Span {
+	if s.tracer == nil {
+		return noopSpan{}
+	}
+
+	_, span := s.tracer.Start(msg.Context(), name, s.sessionID)
+	setSpanMessage(span, msg)
+	return span
+}
+
+func setSpanMessage(span Span, msg *Message) {
+	msgType, _ := msg.Header.GetString(tagMsgType)
+	seqNum, _ := msg.Header.GetInt(tagMsgSeqNum)
+	span.SetMessage(msgType, seqNum)
+}
+
+// incrNextTargetMsgSeqNum increments the next expected MsgSeqNum after processing msg.
+func (s *session) incrNextTargetMsgSeqNum(msg *Message) error {
+	span := s.startMessageSpan(msg, SpanStore)
+	err := s.store.IncrNextTargetMsgSeqNum()
+	span.End(err)
+	return err
+}
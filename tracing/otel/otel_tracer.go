@@ -0,0 +1,83 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package otel provides a quickfix.Tracer recording the lifecycle of messages as OpenTelemetry spans.
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const instrumentationName = "github.com/quickfixgo/quickfix"
+
+// Attributes of the spans.
+const (
+	AttributeSessionID    = attribute.Key("fix.session_id")
+	AttributeBeginString  = attribute.Key("fix.begin_string")
+	AttributeSenderCompID = attribute.Key("fix.sender_comp_id")
+	AttributeTargetCompID = attribute.Key("fix.target_comp_id")
+	AttributeMsgType      = attribute.Key("fix.msg_type")
+	AttributeMsgSeqNum    = attribute.Key("fix.msg_seq_num")
+)
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer returns a quickfix.Tracer starting spans with a tracer of provider.
+// Inbound messages are traced with consumer spans and outbound messages with producer spans.
+func NewTracer(provider trace.TracerProvider) quickfix.Tracer {
+	return tracer{tracer: provider.Tracer(instrumentationName)}
+}
+
+func (t tracer) Start(ctx context.Context, name string, sessionID quickfix.SessionID) (context.Context, quickfix.Span) {
+	kind := trace.SpanKindInternal
+	switch name {
+	case quickfix.SpanInbound:
+		kind = trace.SpanKindConsumer
+	case quickfix.SpanOutbound:
+		kind = trace.SpanKindProducer
+	}
+
+	ctx, s := t.tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(
+		AttributeSessionID.String(sessionID.String()),
+		AttributeBeginString.String(sessionID.BeginString),
+		AttributeSenderCompID.String(sessionID.SenderCompID),
+		AttributeTargetCompID.String(sessionID.TargetCompID),
+	))
+	return ctx, span{span: s}
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s span) SetMessage(msgType string, msgSeqNum int) {
+	s.span.SetAttributes(AttributeMsgType.String(msgType), AttributeMsgSeqNum.Int(msgSeqNum))
+}
+
+func (s span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
@@ -0,0 +1,65 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	ctx, inbound := tracer.Start(context.Background(), quickfix.SpanInbound, sessionID)
+	_, callback := tracer.Start(ctx, quickfix.SpanCallback, sessionID)
+	callback.SetMessage("D", 5)
+	callback.End(errors.New("rejected"))
+	inbound.End(nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, quickfix.SpanCallback, spans[0].Name())
+	assert.Equal(t, trace.SpanKindInternal, spans[0].SpanKind())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "rejected", spans[0].Status().Description)
+	assert.Contains(t, spans[0].Attributes(), AttributeMsgType.String("D"))
+	assert.Contains(t, spans[0].Attributes(), AttributeMsgSeqNum.Int(5))
+
+	assert.Equal(t, quickfix.SpanInbound, spans[1].Name())
+	assert.Equal(t, trace.SpanKindConsumer, spans[1].SpanKind())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		AttributeSessionID.String("FIX.4.2:SENDER->TARGET"),
+		AttributeBeginString.String("FIX.4.2"),
+		AttributeSenderCompID.String("SENDER"),
+		AttributeTargetCompID.String("TARGET"),
+	}, spans[1].Attributes())
+}
@@ -0,0 +1,187 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type spanKey struct{}
+
+type recordedSpan struct {
+	name      string
+	parent    *recordedSpan
+	msgType   string
+	msgSeqNum int
+	err       error
+	ended     bool
+}
+
+func (s *recordedSpan) SetMessage(msgType string, msgSeqNum int) {
+	s.msgType, s.msgSeqNum = msgType, msgSeqNum
+}
+
+func (s *recordedSpan) End(err error) {
+	s.err, s.ended = err, true
+}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, _ SessionID) (context.Context, Span) {
+	span := &recordedSpan{name: name}
+	span.parent, _ = ctx.Value(spanKey{}).(*recordedSpan)
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (t *recordingTracer) names() (names []string) {
+	for _, span := range t.spans {
+		names = append(names, span.name)
+	}
+	return
+}
+
+// contextApp records the context of the messages passed to FromApp.
+type contextApp struct {
+	*MockApp
+	fromAppCtx context.Context
+}
+
+func (a *contextApp) FromApp(msg *Message, sessionID SessionID) MessageRejectError {
+	a.fromAppCtx = msg.Context()
+	return a.MockApp.FromApp(msg, sessionID)
+}
+
+type TracingTestSuite struct {
+	SessionSuiteRig
+	tracer *recordingTracer
+}
+
+func TestTracingTestSuite(t *testing.T) {
+	suite.Run(t, new(TracingTestSuite))
+}
+
+func (s *TracingTestSuite) SetupTest() {
+	s.Init()
+	s.tracer = &recordingTracer{}
+	s.session.tracer = s.tracer
+	s.session.State = inSession{}
+}
+
+func (s *TracingTestSuite) TestInbound() {
+	app := &contextApp{MockApp: &s.MockApp}
+	s.session.application = app
+	s.MockApp.On("FromApp").Return(nil)
+
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(s.NewOrderSingle().build())})
+	s.MockApp.AssertExpectations(s.T())
+
+	s.Equal([]string{SpanInbound, SpanParse, SpanCallback, SpanStore}, s.tracer.names())
+	inbound := s.tracer.spans[0]
+	s.Nil(inbound.parent)
+	s.Equal("D", inbound.msgType)
+	s.Equal(1, inbound.msgSeqNum)
+	for _, span := range s.tracer.spans {
+		s.True(span.ended, span.name)
+		s.Nil(span.err, span.name)
+	}
+	for _, span := range s.tracer.spans[1:] {
+		s.Equal(inbound, span.parent, span.name)
+	}
+	s.Equal(inbound, app.fromAppCtx.Value(spanKey{}))
+}
+
+func (s *TracingTestSuite) TestInboundReject() {
+	s.MockApp.On("FromApp").Return(ConditionallyRequiredFieldMissing(Tag(11)))
+	s.MockApp.On("ToApp").Return(nil)
+
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(s.NewOrderSingle().build())})
+	s.MockApp.AssertExpectations(s.T())
+
+	callback := s.tracer.spans[2]
+	s.Equal(SpanCallback, callback.name)
+	s.NotNil(callback.err)
+}
+
+func (s *TracingTestSuite) TestInboundParseError() {
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBufferString("9=5\x018=FIX.4.2\x01")})
+
+	s.Equal([]string{SpanInbound, SpanParse}, s.tracer.names())
+	s.NotNil(s.tracer.spans[0].err)
+	s.NotNil(s.tracer.spans[1].err)
+}
+
+func (s *TracingTestSuite) TestOutbound() {
+	s.MockApp.On("ToApp").Return(nil)
+
+	parent := &recordedSpan{name: "order"}
+	msg := s.NewOrderSingle()
+	msg.SetContext(context.WithValue(context.Background(), spanKey{}, parent))
+	s.Require().Nil(s.session.send(msg))
+	s.MockApp.AssertExpectations(s.T())
+	s.LastToAppMessageSent()
+
+	s.Equal([]string{SpanOutbound, SpanToApp, SpanSerialize, SpanStore, SpanWrite}, s.tracer.names())
+	outbound := s.tracer.spans[0]
+	s.Equal(parent, outbound.parent)
+	s.Equal("D", outbound.msgType)
+	s.Equal(1, outbound.msgSeqNum)
+	for _, span := range s.tracer.spans {
+		s.True(span.ended, span.name)
+		s.Nil(span.err, span.name)
+	}
+	for _, span := range s.tracer.spans[1:] {
+		s.Equal(outbound, span.parent, span.name)
+	}
+	s.Equal(parent, msg.Context().Value(spanKey{}))
+}
+
+func (s *TracingTestSuite) TestOutboundToAdminContext() {
+	var toAdminCtx context.Context
+	s.MockApp.decorateToAdmin = func(msg *Message) { toAdminCtx = msg.Context() }
+	s.MockApp.On("ToAdmin")
+
+	s.Require().Nil(s.session.send(s.Heartbeat()))
+	s.Equal(s.tracer.spans[0], toAdminCtx.Value(spanKey{}))
+}
+
+func (s *TracingTestSuite) TestOutboundDoNotSend() {
+	s.MockApp.On("ToApp").Return(ErrDoNotSend)
+
+	s.Equal(ErrDoNotSend, s.session.send(s.NewOrderSingle()))
+	s.Equal([]string{SpanOutbound, SpanToApp}, s.tracer.names())
+	s.Equal(ErrDoNotSend, s.tracer.spans[0].err)
+	s.Equal(ErrDoNotSend, s.tracer.spans[1].err)
+}
+
+func (s *TracingTestSuite) TestOutboundDropped() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.session.State = latentState{}
+
+	s.Require().Nil(s.session.send(s.NewOrderSingle()))
+	s.NoMessageSent()
+	s.False(s.tracer.spans[0].ended)
+
+	s.session.dropQueued()
+	s.True(s.tracer.spans[0].ended)
+	s.Equal(errDroppedFromQueue, s.tracer.spans[0].err)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package transform
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const tagPossDupFlag quickfix.Tag = 43
+
+// Engine applies Rules to the messages of sessions as middleware. Its rules may be replaced while sessions run.
+type Engine struct {
+	rules atomic.Pointer[Rules]
+
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+}
+
+// NewEngine returns an Engine applying rules.
+func NewEngine(rules *Rules) *Engine {
+	e := &Engine{}
+	e.SetRules(rules)
+	return e
+}
+
+// NewFileEngine returns an Engine applying the rules of the YAML rules document at path, reloaded by Reload and
+// Watch.
+func NewFileEngine(path string) (*Engine, error) {
+	e := &Engine{path: path}
+	if _, err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// SetRules replaces the rules of the engine. Messages being rewritten keep the previous rules.
+func (e *Engine) SetRules(rules *Rules) {
+	if rules == nil {
+		rules = &Rules{}
+	}
+	e.rules.Store(rules)
+}
+
+// Rules returns the rules of the engine.
+func (e *Engine) Rules() *Rules {
+	return e.rules.Load()
+}
+
+// Reload replaces the rules of an engine created by NewFileEngine with the rules of its file, if it was modified
+// since it was last loaded, and returns true if it did. Invalid rules are returned as an error, keeping the rules.
+func (e *Engine) Reload() (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return false, err
+	}
+	if e.Rules() != nil && !info.ModTime().After(e.modTime) {
+		return false, nil
+	}
+
+	rules, err := LoadRules(e.path)
+	if err != nil {
+		return false, err
+	}
+
+	e.SetRules(rules)
+	e.modTime = info.ModTime()
+	return true, nil
+}
+
+// Watch calls Reload every interval until ctx is done, passing its errors to onError, which may be nil.
+func (e *Engine) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := e.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Inbound returns the InboundMiddleware rewriting the messages received, to add with UseInbound.
+func (e *Engine) Inbound() quickfix.InboundMiddleware {
+	return func(next quickfix.InboundHandler) quickfix.InboundHandler {
+		return func(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+			e.Rules().Apply(msg, sessionID, Inbound)
+			return next(msg, sessionID)
+		}
+	}
+}
+
+// Outbound returns the OutboundMiddleware rewriting the messages sent, to add with UseOutbound. Messages being
+// resent, with PossDupFlag set, were rewritten when first sent and are left as they are.
+func (e *Engine) Outbound() quickfix.OutboundMiddleware {
+	return func(next quickfix.OutboundHandler) quickfix.OutboundHandler {
+		return func(msg *quickfix.Message, sessionID quickfix.SessionID) error {
+			if possDup, err := msg.Header.GetBool(tagPossDupFlag); err != nil || !possDup {
+				e.Rules().Apply(msg, sessionID, Outbound)
+			}
+			return next(msg, sessionID)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package transform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func writeRules(t *testing.T, path, doc string, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, []byte(doc), 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestFileEngine_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	start := time.Now().Add(-time.Hour)
+	writeRules(t, path, "rules:\n  - actions: [{set: {tag: 1, value: A}}]\n", start)
+
+	engine, err := NewFileEngine(path)
+	require.NoError(t, err)
+
+	account := func() string {
+		msg := newOrder(nil)
+		engine.Rules().Apply(msg, venueB, Outbound)
+		value, _ := msg.Body.GetString(quickfix.Tag(1))
+		return value
+	}
+	assert.Equal(t, "A", account())
+
+	reloaded, err := engine.Reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+
+	// Invalid rules keep the previous rules.
+	writeRules(t, path, "rules:\n  - actions: [{}]\n", start.Add(time.Minute))
+	_, err = engine.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, "A", account())
+
+	writeRules(t, path, "rules:\n  - actions: [{set: {tag: 1, value: B}}]\n", start.Add(2*time.Minute))
+	reloaded, err = engine.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, "B", account())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go engine.Watch(ctx, 10*time.Millisecond, nil)
+	writeRules(t, path, "rules:\n  - actions: [{set: {tag: 1, value: C}}]\n", start.Add(3*time.Minute))
+	assert.Eventually(t, func() bool { return account() == "C" }, time.Second, 10*time.Millisecond)
+}
+
+func TestEngine_Middleware(t *testing.T) {
+	engine := NewEngine(nil)
+	rules, err := ParseRules(strings.NewReader("rules:\n  - direction: inbound\n    actions: [{set: {tag: 1, value: IN}}]\n  - direction: outbound\n    actions: [{set: {tag: 1, value: OUT}}]\n"))
+	require.NoError(t, err)
+	engine.SetRules(rules)
+
+	var delivered string
+	inbound := engine.Inbound()(func(msg *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
+		delivered, _ = msg.Body.GetString(quickfix.Tag(1))
+		return nil
+	})
+	assert.Nil(t, inbound(newOrder(nil), venueB))
+	assert.Equal(t, "IN", delivered)
+
+	outbound := engine.Outbound()(func(msg *quickfix.Message, _ quickfix.SessionID) error {
+		delivered, _ = msg.Body.GetString(quickfix.Tag(1))
+		return nil
+	})
+	assert.NoError(t, outbound(newOrder(nil), venueB))
+	assert.Equal(t, "OUT", delivered)
+
+	// Resent messages were rewritten when first sent.
+	resent := newOrder(map[quickfix.Tag]string{1: "SENT"})
+	resent.Header.SetBool(tagPossDupFlag, true)
+	assert.NoError(t, outbound(resent, venueB))
+	assert.Equal(t, "SENT", delivered)
+}
@@ -0,0 +1,310 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package transform provides middleware rewriting the messages of sessions with declarative rules loaded from YAML,
+// for the per counterparty tag surgery of hubs bridging venues:
+//
+//	rules:
+//	  - name: venue b accounts
+//	    direction: outbound
+//	    when:
+//	      session: {TargetCompID: VENUE_B}
+//	      msgType: [D, F]
+//	      fields: {1: ACC1}
+//	    actions:
+//	      - set: {tag: 1, value: B-ACC1}
+//	      - copy: {from: 11, to: 526}
+//	      - delete: 58
+//	      - remap: {tag: 54, values: {"5": "2"}}
+//	      - set: {tag: 56, value: VENUE_B_GW}
+//
+// Rules apply in order to the messages they match, in the direction they apply to: inbound, outbound or both, the
+// default. A rule matches the messages of the sessions with all of its session fields, named by their settings, of
+// one of its message types, and with all of its field values. A rule without conditions matches all messages.
+//
+// Actions apply to the fields of the header and body, not those of repeating groups, and set, copy to and delete
+// header fields by their tag, so setting SenderCompID or TargetCompID rewrites the comp IDs of a message. Rewriting
+// an inbound message changes its fields, not the raw message returned by its Bytes and String.
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// Direction is the direction of the messages a rule applies to.
+type Direction int
+
+const (
+	// Both applies a rule to the messages received and sent.
+	Both Direction = iota
+
+	// Inbound applies a rule to the messages received.
+	Inbound
+
+	// Outbound applies a rule to the messages sent.
+	Outbound
+)
+
+// Rules is a list of transformation rules.
+type Rules struct {
+	rules []rule
+}
+
+type rule struct {
+	name      string
+	direction Direction
+	session   map[string]string
+	msgTypes  map[string]bool
+	fields    map[quickfix.Tag]string
+	actions   []action
+}
+
+// action rewrites a message.
+type action func(msg *quickfix.Message)
+
+// sessionFields are the fields of a SessionID, by the name of their setting.
+var sessionFields = map[string]func(quickfix.SessionID) string{
+	config.BeginString:      func(id quickfix.SessionID) string { return id.BeginString },
+	config.SenderCompID:     func(id quickfix.SessionID) string { return id.SenderCompID },
+	config.SenderSubID:      func(id quickfix.SessionID) string { return id.SenderSubID },
+	config.SenderLocationID: func(id quickfix.SessionID) string { return id.SenderLocationID },
+	config.TargetCompID:     func(id quickfix.SessionID) string { return id.TargetCompID },
+	config.TargetSubID:      func(id quickfix.SessionID) string { return id.TargetSubID },
+	config.TargetLocationID: func(id quickfix.SessionID) string { return id.TargetLocationID },
+	config.SessionQualifier: func(id quickfix.SessionID) string { return id.Qualifier },
+}
+
+type rulesDocument struct {
+	Rules []ruleDocument `yaml:"rules"`
+}
+
+type ruleDocument struct {
+	Name      string `yaml:"name"`
+	Direction string `yaml:"direction"`
+	When      struct {
+		Session map[string]string `yaml:"session"`
+		MsgType []string          `yaml:"msgType"`
+		Fields  map[int]string    `yaml:"fields"`
+	} `yaml:"when"`
+	Actions []actionDocument `yaml:"actions"`
+}
+
+type actionDocument struct {
+	Set *struct {
+		Tag   int    `yaml:"tag"`
+		Value string `yaml:"value"`
+	} `yaml:"set"`
+	Copy *struct {
+		From int `yaml:"from"`
+		To   int `yaml:"to"`
+	} `yaml:"copy"`
+	Delete *int `yaml:"delete"`
+	Remap  *struct {
+		Tag    int               `yaml:"tag"`
+		Values map[string]string `yaml:"values"`
+	} `yaml:"remap"`
+}
+
+// ParseRules parses the rules of a YAML rules document.
+func ParseRules(reader io.Reader) (*Rules, error) {
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+
+	var doc rulesDocument
+	if err := decoder.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	rules := &Rules{}
+	for i, ruleDoc := range doc.Rules {
+		r, err := ruleDoc.rule()
+		if err != nil {
+			name := ruleDoc.Name
+			if name == "" {
+				name = strconv.Itoa(i + 1)
+			}
+			return nil, fmt.Errorf("rule %v: %w", name, err)
+		}
+		rules.rules = append(rules.rules, r)
+	}
+
+	return rules, nil
+}
+
+// LoadRules parses the rules of the YAML rules document at path.
+func LoadRules(path string) (*Rules, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseRules(file)
+}
+
+func (d ruleDocument) rule() (r rule, err error) {
+	r = rule{name: d.Name, session: d.When.Session}
+
+	switch d.Direction {
+	case "", "both":
+		r.direction = Both
+	case "inbound":
+		r.direction = Inbound
+	case "outbound":
+		r.direction = Outbound
+	default:
+		return r, fmt.Errorf("invalid direction %v", d.Direction)
+	}
+
+	for field := range d.When.Session {
+		if _, ok := sessionFields[field]; !ok {
+			return r, fmt.Errorf("invalid session field %v", field)
+		}
+	}
+
+	if len(d.When.MsgType) > 0 {
+		r.msgTypes = make(map[string]bool)
+		for _, msgType := range d.When.MsgType {
+			r.msgTypes[msgType] = true
+		}
+	}
+
+	if len(d.When.Fields) > 0 {
+		r.fields = make(map[quickfix.Tag]string)
+		for tag, value := range d.When.Fields {
+			if tag <= 0 {
+				return r, fmt.Errorf("invalid tag %v", tag)
+			}
+			r.fields[quickfix.Tag(tag)] = value
+		}
+	}
+
+	if len(d.Actions) == 0 {
+		return r, errors.New("no actions")
+	}
+	for i, actionDoc := range d.Actions {
+		a, err := actionDoc.action()
+		if err != nil {
+			return r, fmt.Errorf("action %v: %w", i+1, err)
+		}
+		r.actions = append(r.actions, a)
+	}
+
+	return r, nil
+}
+
+func (d actionDocument) action() (action, error) {
+	var actions []action
+	var tags []int
+
+	if d.Set != nil {
+		tag, value := quickfix.Tag(d.Set.Tag), d.Set.Value
+		actions = append(actions, func(msg *quickfix.Message) { fieldMap(msg, tag).SetString(tag, value) })
+		tags = append(tags, d.Set.Tag)
+	}
+	if d.Copy != nil {
+		from, to := quickfix.Tag(d.Copy.From), quickfix.Tag(d.Copy.To)
+		actions = append(actions, func(msg *quickfix.Message) {
+			if value, err := fieldMap(msg, from).GetBytes(from); err == nil {
+				fieldMap(msg, to).SetBytes(to, append([]byte(nil), value...))
+			}
+		})
+		tags = append(tags, d.Copy.From, d.Copy.To)
+	}
+	if d.Delete != nil {
+		tag := quickfix.Tag(*d.Delete)
+		actions = append(actions, func(msg *quickfix.Message) { fieldMap(msg, tag).Remove(tag) })
+		tags = append(tags, *d.Delete)
+	}
+	if d.Remap != nil {
+		tag, values := quickfix.Tag(d.Remap.Tag), d.Remap.Values
+		actions = append(actions, func(msg *quickfix.Message) {
+			if value, err := fieldMap(msg, tag).GetString(tag); err == nil {
+				if remapped, ok := values[value]; ok {
+					fieldMap(msg, tag).SetString(tag, remapped)
+				}
+			}
+		})
+		tags = append(tags, d.Remap.Tag)
+	}
+
+	if len(actions) != 1 {
+		return nil, errors.New("expected one of set, copy, delete or remap")
+	}
+	for _, tag := range tags {
+		if tag <= 0 {
+			return nil, fmt.Errorf("invalid tag %v", tag)
+		}
+	}
+
+	return actions[0], nil
+}
+
+// fieldMap returns the header or body of msg holding tag.
+func fieldMap(msg *quickfix.Message, tag quickfix.Tag) *quickfix.FieldMap {
+	if tag.IsHeader() {
+		return &msg.Header.FieldMap
+	}
+	return &msg.Body.FieldMap
+}
+
+// matches returns true if the rule applies to msg of sessionID, sent or received as direction.
+func (r *rule) matches(msg *quickfix.Message, sessionID quickfix.SessionID, direction Direction) bool {
+	if r.direction != Both && r.direction != direction {
+		return false
+	}
+
+	for field, value := range r.session {
+		if sessionFields[field](sessionID) != value {
+			return false
+		}
+	}
+
+	if r.msgTypes != nil {
+		if msgType, err := msg.MsgType(); err != nil || !r.msgTypes[msgType] {
+			return false
+		}
+	}
+
+	for tag, value := range r.fields {
+		if actual, err := fieldMap(msg, tag).GetString(tag); err != nil || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Apply rewrites msg of sessionID, sent or received as direction, with the rules matching it.
+func (rules *Rules) Apply(msg *quickfix.Message, sessionID quickfix.SessionID, direction Direction) {
+	for i := range rules.rules {
+		r := &rules.rules[i]
+		if !r.matches(msg, sessionID, direction) {
+			continue
+		}
+		for _, a := range r.actions {
+			a(msg)
+		}
+	}
+}
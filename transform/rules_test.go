@@ -0,0 +1,128 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+var venueB = quickfix.SessionID{BeginString: quickfix.BeginStringFIX42, SenderCompID: "HUB", TargetCompID: "VENUE_B"}
+
+func newOrder(fields map[quickfix.Tag]string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(35), "D")
+	msg.Header.SetString(quickfix.Tag(49), "HUB")
+	msg.Header.SetString(quickfix.Tag(56), "VENUE_B")
+	for tag, value := range fields {
+		msg.Body.SetString(tag, value)
+	}
+	return msg
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	var tests = []struct {
+		name string
+		doc  string
+	}{
+		{name: "unknown key", doc: "rules:\n  - actions: [{delete: 58}]\n    unknown: 1\n"},
+		{name: "invalid direction", doc: "rules:\n  - direction: sideways\n    actions: [{delete: 58}]\n"},
+		{name: "invalid session field", doc: "rules:\n  - when: {session: {CompID: A}}\n    actions: [{delete: 58}]\n"},
+		{name: "invalid field tag", doc: "rules:\n  - when: {fields: {0: A}}\n    actions: [{delete: 58}]\n"},
+		{name: "no actions", doc: "rules:\n  - name: empty\n"},
+		{name: "two actions in one", doc: "rules:\n  - actions: [{delete: 58, set: {tag: 1, value: A}}]\n"},
+		{name: "empty action", doc: "rules:\n  - actions: [{}]\n"},
+		{name: "invalid action tag", doc: "rules:\n  - actions: [{copy: {from: 11}}]\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseRules(strings.NewReader(test.doc))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRules_Apply(t *testing.T) {
+	var tests = []struct {
+		name      string
+		doc       string
+		sessionID quickfix.SessionID
+		direction Direction
+		fields    map[quickfix.Tag]string
+		expected  map[quickfix.Tag]string
+	}{
+		{
+			name:      "set copy delete remap",
+			doc:       "rules:\n  - actions:\n      - set: {tag: 1, value: B-ACC1}\n      - copy: {from: 11, to: 526}\n      - delete: 58\n      - remap: {tag: 54, values: {\"5\": \"2\"}}\n",
+			sessionID: venueB, direction: Outbound,
+			fields:   map[quickfix.Tag]string{1: "ACC1", 11: "ORDER-1", 54: "5", 58: "note"},
+			expected: map[quickfix.Tag]string{1: "B-ACC1", 11: "ORDER-1", 526: "ORDER-1", 54: "2", 58: ""},
+		},
+		{
+			name:      "rewrite comp ids",
+			doc:       "rules:\n  - when: {session: {TargetCompID: VENUE_B}}\n    actions: [{set: {tag: 56, value: VENUE_B_GW}}, {set: {tag: 49, value: HUB_B}}]\n",
+			sessionID: venueB, direction: Outbound,
+			expected: map[quickfix.Tag]string{56: "VENUE_B_GW", 49: "HUB_B"},
+		},
+		{
+			name:      "other session",
+			doc:       "rules:\n  - when: {session: {TargetCompID: VENUE_A}}\n    actions: [{set: {tag: 56, value: VENUE_A_GW}}]\n",
+			sessionID: venueB, direction: Outbound,
+			expected: map[quickfix.Tag]string{56: "VENUE_B"},
+		},
+		{
+			name:      "other direction",
+			doc:       "rules:\n  - direction: inbound\n    actions: [{set: {tag: 1, value: B}}]\n",
+			sessionID: venueB, direction: Outbound,
+			fields:   map[quickfix.Tag]string{1: "A"},
+			expected: map[quickfix.Tag]string{1: "A"},
+		},
+		{
+			name:      "msg type and fields",
+			doc:       "rules:\n  - when: {msgType: [D], fields: {55: MSFT}}\n    actions: [{set: {tag: 100, value: XNAS}}]\n  - when: {msgType: [F]}\n    actions: [{set: {tag: 100, value: XNYS}}]\n",
+			sessionID: venueB, direction: Inbound,
+			fields:   map[quickfix.Tag]string{55: "MSFT"},
+			expected: map[quickfix.Tag]string{100: "XNAS"},
+		},
+		{
+			name:      "rules apply in order",
+			doc:       "rules:\n  - actions: [{set: {tag: 1, value: B}}]\n  - when: {fields: {1: B}}\n    actions: [{set: {tag: 1, value: C}}]\n",
+			sessionID: venueB, direction: Inbound,
+			fields:   map[quickfix.Tag]string{1: "A"},
+			expected: map[quickfix.Tag]string{1: "C"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rules, err := ParseRules(strings.NewReader(test.doc))
+			require.NoError(t, err)
+
+			msg := newOrder(test.fields)
+			rules.Apply(msg, test.sessionID, test.direction)
+			for tag, expected := range test.expected {
+				actual, _ := fieldMap(msg, tag).GetString(tag)
+				assert.Equal(t, expected, actual, "tag %v", tag)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/proxy"
+)
+
+const (
+	unixScheme = "unix://"
+	pipeScheme = "pipe://"
+)
+
+// isLocalTransport returns true if host names a unix domain socket, unix:///path.sock,
+// or an in-process pipe, pipe://name, rather than a TCP host.
+func isLocalTransport(host string) bool {
+	return strings.HasPrefix(host, unixScheme) || strings.HasPrefix(host, pipeScheme)
+}
+
+// splitNetworkAddress returns the network and address to dial or listen on for a socket connect or accept address.
+func splitNetworkAddress(address string) (network, addr string) {
+	switch {
+	case strings.HasPrefix(address, unixScheme):
+		return "unix", strings.TrimPrefix(address, unixScheme)
+	case strings.HasPrefix(address, pipeScheme):
+		return "pipe", strings.TrimPrefix(address, pipeScheme)
+	}
+
+	return "tcp", address
+}
+
+func dialNetwork(ctx context.Context, dialer proxy.ContextDialer, address string) (net.Conn, error) {
+	network, addr := splitNetworkAddress(address)
+	if network == "pipe" {
+		return dialPipe(ctx, addr)
+	}
+
+	return dialer.DialContext(ctx, network, addr)
+}
+
+func listenNetwork(address string, tcpOptions tcpOptions) (net.Listener, error) {
+	network, addr := splitNetworkAddress(address)
+	switch network {
+	case "tcp":
+		return tcpOptions.listen(addr)
+	case "pipe":
+		return listenPipe(addr)
+	case "unix":
+		// Remove a socket left behind by a previous process, net.Listen fails if the path exists.
+		if info, err := os.Stat(addr); err == nil && info.Mode()&os.ModeSocket != 0 {
+			if err := os.Remove(addr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return net.Listen(network, addr)
+}
+
+// pipeListeners are the in-process pipe listeners by name.
+var pipeListeners = struct {
+	sync.Mutex
+	byName map[string]*pipeListener
+}{byName: make(map[string]*pipeListener)}
+
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
+
+// pipeListener accepts in-process connections made with net.Pipe, so that an initiator and an acceptor
+// in the same process can connect without a socket.
+type pipeListener struct {
+	name      string
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func listenPipe(name string) (*pipeListener, error) {
+	pipeListeners.Lock()
+	defer pipeListeners.Unlock()
+
+	if _, ok := pipeListeners.byName[name]; ok {
+		return nil, fmt.Errorf("pipe %v already in use", name)
+	}
+
+	l := &pipeListener{name: name, conns: make(chan net.Conn), done: make(chan struct{})}
+	pipeListeners.byName[name] = l
+	return l, nil
+}
+
+func dialPipe(ctx context.Context, name string) (net.Conn, error) {
+	pipeListeners.Lock()
+	l, ok := pipeListeners.byName[name]
+	pipeListeners.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no pipe listener for %v", name)
+	}
+
+	client, server := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+	case <-ctx.Done():
+	}
+
+	_ = client.Close()
+	_ = server.Close()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return nil, fmt.Errorf("pipe listener for %v closed", name)
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+
+		pipeListeners.Lock()
+		delete(pipeListeners.byName, l.name)
+		pipeListeners.Unlock()
+	})
+
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr { return pipeAddr(l.name) }
@@ -0,0 +1,139 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+type logonApp struct {
+	loggedOn chan SessionID
+}
+
+func (a logonApp) OnCreate(SessionID)                               {}
+func (a logonApp) OnLogon(sessionID SessionID)                      { a.loggedOn <- sessionID }
+func (a logonApp) OnLogout(SessionID)                               {}
+func (a logonApp) ToAdmin(*Message, SessionID)                      {}
+func (a logonApp) ToApp(*Message, SessionID) error                  { return nil }
+func (a logonApp) FromAdmin(*Message, SessionID) MessageRejectError { return nil }
+func (a logonApp) FromApp(*Message, SessionID) MessageRejectError   { return nil }
+
+// testTransportLogon logs on an initiator and an acceptor connected over host, with optional setup before they start.
+func testTransportLogon(t *testing.T, host, senderCompID string, setupAcceptor func(*Acceptor), setupInitiator func(SessionID)) {
+	acceptorSettings := NewSettings()
+	acceptorSettings.GlobalSettings().Set(config.SocketAcceptHost, host)
+	acceptorSettings.GlobalSettings().Set(config.SocketAcceptPort, "5010")
+	acceptorSession := NewSessionSettings()
+	acceptorSession.Set(config.BeginString, BeginStringFIX42)
+	acceptorSession.Set(config.SenderCompID, senderCompID+"_ACCEPTOR")
+	acceptorSession.Set(config.TargetCompID, senderCompID)
+	_, err := acceptorSettings.AddSession(acceptorSession)
+	require.NoError(t, err)
+
+	acceptorApp := logonApp{loggedOn: make(chan SessionID, 1)}
+	acceptor, err := NewAcceptor(acceptorApp, NewMemoryStoreFactory(), acceptorSettings, NewNullLogFactory())
+	require.NoError(t, err)
+	if setupAcceptor != nil {
+		setupAcceptor(acceptor)
+	}
+	require.NoError(t, acceptor.Start())
+	defer acceptor.Stop()
+
+	initiatorSettings := NewSettings()
+	initiatorSession := NewSessionSettings()
+	initiatorSession.Set(config.BeginString, BeginStringFIX42)
+	initiatorSession.Set(config.SenderCompID, senderCompID)
+	initiatorSession.Set(config.TargetCompID, senderCompID+"_ACCEPTOR")
+	initiatorSession.Set(config.SocketConnectHost, host)
+	initiatorSession.Set(config.SocketConnectPort, "5010")
+	initiatorSession.Set(config.HeartBtInt, "30")
+	initiatorSessionID, err := initiatorSettings.AddSession(initiatorSession)
+	require.NoError(t, err)
+
+	initiatorApp := logonApp{loggedOn: make(chan SessionID, 1)}
+	initiator, err := NewInitiator(initiatorApp, NewMemoryStoreFactory(), initiatorSettings, NewNullLogFactory())
+	require.NoError(t, err)
+	if setupInitiator != nil {
+		setupInitiator(initiatorSessionID)
+	}
+	require.NoError(t, initiator.Start())
+	defer initiator.Stop()
+
+	for _, loggedOn := range []chan SessionID{acceptorApp.loggedOn, initiatorApp.loggedOn} {
+		select {
+		case <-loggedOn:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for logon")
+		}
+	}
+}
+
+func TestPipeTransport(t *testing.T) {
+	testTransportLogon(t, "pipe://quickfix_test", "PIPE", nil, nil)
+}
+
+func TestUnixTransport(t *testing.T) {
+	testTransportLogon(t, "unix://"+filepath.Join(t.TempDir(), "quickfix.sock"), "UNIX", nil, nil)
+}
+
+type pipeListenerFunc func(address string) (net.Listener, error)
+
+func (f pipeListenerFunc) Listen(address string) (net.Listener, error) { return f(address) }
+
+func TestCustomDialerAndListener(t *testing.T) {
+	var listenedOn string
+	setupAcceptor := func(a *Acceptor) {
+		a.SetListener(pipeListenerFunc(func(address string) (net.Listener, error) {
+			listenedOn = address
+			return listenPipe("custom")
+		}))
+	}
+	setupInitiator := func(sessionID SessionID) {
+		require.NoError(t, SetDialer(sessionID, func(ctx context.Context) (net.Conn, error) {
+			return dialPipe(ctx, "custom")
+		}))
+	}
+
+	testTransportLogon(t, "127.0.0.1", "CUSTOM", setupAcceptor, setupInitiator)
+	assert.Equal(t, "127.0.0.1:5010", listenedOn)
+}
+
+func TestPipeListener(t *testing.T) {
+	_, err := dialPipe(context.Background(), "missing")
+	assert.Error(t, err)
+
+	l, err := listenPipe("listener_test")
+	require.NoError(t, err)
+	_, err = listenPipe("listener_test")
+	assert.Error(t, err, "pipe names must be unique")
+
+	require.NoError(t, l.Close())
+	_, err = l.Accept()
+	assert.Error(t, err)
+
+	l, err = listenPipe("listener_test")
+	require.NoError(t, err, "closing releases the name")
+	require.NoError(t, l.Close())
+}
@@ -34,6 +34,9 @@ type ValidatorSettings struct {
 	RejectInvalidMessage      bool
 	AllowUnknownMessageFields bool
 	CheckUserDefinedFields    bool
+
+	// Policy disables checks for all messages, messages of a MsgType or fields, and may be nil.
+	Policy *ValidationPolicy
 }
 
 // Default configuration for message validation.
@@ -102,26 +105,30 @@ func (v *fixtValidator) Validate(msg *Message) MessageRejectError {
 }
 
 func validateFIX(d *datadictionary.DataDictionary, settings ValidatorSettings, msgType string, msg *Message) MessageRejectError {
+	scope := validationScope{policy: settings.Policy, msgType: msgType}
+
 	if err := validateMsgType(d, msgType, msg); err != nil {
 		return err
 	}
 
-	if err := validateRequired(d, d, msgType, msg); err != nil {
-		return err
+	if scope.checks(CheckRequiredFields) {
+		if err := validateRequired(scope, d, d, msgType, msg); err != nil {
+			return err
+		}
 	}
 
-	if settings.CheckFieldsOutOfOrder {
-		if err := validateOrder(msg); err != nil {
+	if settings.CheckFieldsOutOfOrder && scope.checks(CheckFieldOrder) {
+		if err := validateOrder(scope, msg); err != nil {
 			return err
 		}
 	}
 
 	if settings.RejectInvalidMessage {
-		if err := validateFields(d, d, settings, msgType, msg); err != nil {
+		if err := validateFields(scope, d, d, settings, msgType, msg); err != nil {
 			return err
 		}
 
-		if err := validateWalk(d, d, settings, msgType, msg); err != nil {
+		if err := validateWalk(scope, d, d, settings, msgType, msg); err != nil {
 			return err
 		}
 	}
@@ -130,26 +137,30 @@ func validateFIX(d *datadictionary.DataDictionary, settings ValidatorSettings, m
 }
 
 func validateFIXT(transportDD, appDD *datadictionary.DataDictionary, settings ValidatorSettings, msgType string, msg *Message) MessageRejectError {
+	scope := validationScope{policy: settings.Policy, msgType: msgType}
+
 	if err := validateMsgType(appDD, msgType, msg); err != nil {
 		return err
 	}
 
-	if err := validateRequired(transportDD, appDD, msgType, msg); err != nil {
-		return err
+	if scope.checks(CheckRequiredFields) {
+		if err := validateRequired(scope, transportDD, appDD, msgType, msg); err != nil {
+			return err
+		}
 	}
 
-	if settings.CheckFieldsOutOfOrder {
-		if err := validateOrder(msg); err != nil {
+	if settings.CheckFieldsOutOfOrder && scope.checks(CheckFieldOrder) {
+		if err := validateOrder(scope, msg); err != nil {
 			return err
 		}
 	}
 
 	if settings.RejectInvalidMessage {
-		if err := validateFields(transportDD, appDD, settings, msgType, msg); err != nil {
+		if err := validateFields(scope, transportDD, appDD, settings, msgType, msg); err != nil {
 			return err
 		}
 
-		if err := validateWalk(transportDD, appDD, settings, msgType, msg); err != nil {
+		if err := validateWalk(scope, transportDD, appDD, settings, msgType, msg); err != nil {
 			return err
 		}
 	}
@@ -164,9 +175,10 @@ func validateMsgType(d *datadictionary.DataDictionary, msgType string, _ *Messag
 	return nil
 }
 
-func validateWalk(transportDD *datadictionary.DataDictionary, appDD *datadictionary.DataDictionary, settings ValidatorSettings, msgType string, msg *Message) MessageRejectError {
+func validateWalk(scope validationScope, transportDD *datadictionary.DataDictionary, appDD *datadictionary.DataDictionary, settings ValidatorSettings, msgType string, msg *Message) MessageRejectError {
 	remainingFields := msg.fields
-	iteratedTags := make(datadictionary.TagSet)
+	iteratedTags := getTagSet()
+	defer putTagSet(iteratedTags)
 
 	var messageDef *datadictionary.MessageDef
 	var fieldDef *datadictionary.FieldDef
@@ -192,14 +204,14 @@ func validateWalk(transportDD *datadictionary.DataDictionary, appDD *datadiction
 		iteratedTags.Add(int(tag))
 
 		if fieldDef, ok = messageDef.Fields[int(tag)]; !ok {
-			if !checkFieldNotDefined(settings, tag) {
+			if !checkFieldNotDefined(settings, tag) && scope.checksTag(CheckUnknownFields, tag) {
 				return TagNotDefinedForThisMessageType(tag)
 			}
 			remainingFields = remainingFields[1:]
 			continue
 		}
 
-		if remainingFields, err = validateVisitField(fieldDef, remainingFields); err != nil {
+		if remainingFields, err = validateVisitField(scope, fieldDef, remainingFields); err != nil {
 			return err
 		}
 	}
@@ -211,10 +223,10 @@ func validateWalk(transportDD *datadictionary.DataDictionary, appDD *datadiction
 	return nil
 }
 
-func validateVisitField(fieldDef *datadictionary.FieldDef, fields []TagValue) ([]TagValue, MessageRejectError) {
+func validateVisitField(scope validationScope, fieldDef *datadictionary.FieldDef, fields []TagValue) ([]TagValue, MessageRejectError) {
 	if fieldDef.IsGroup() {
 		var err MessageRejectError
-		if fields, err = validateVisitGroupField(fieldDef, fields); err != nil {
+		if fields, err = validateVisitGroupField(scope, fieldDef, fields); err != nil {
 			return nil, err
 		}
 		return fields, nil
@@ -223,7 +235,7 @@ func validateVisitField(fieldDef *datadictionary.FieldDef, fields []TagValue) ([
 	return fields[1:], nil
 }
 
-func validateVisitGroupField(fieldDef *datadictionary.FieldDef, fieldStack []TagValue) ([]TagValue, MessageRejectError) {
+func validateVisitGroupField(scope validationScope, fieldDef *datadictionary.FieldDef, fieldStack []TagValue) ([]TagValue, MessageRejectError) {
 	numInGroupTag := fieldStack[0].tag
 	var numInGroup FIXInt
 
@@ -251,11 +263,11 @@ func validateVisitGroupField(fieldDef *datadictionary.FieldDef, fieldStack []Tag
 
 		if int(fieldStack[0].tag) == childDefs[0].Tag() {
 			var err MessageRejectError
-			if fieldStack, err = validateVisitField(childDefs[0], fieldStack); err != nil {
+			if fieldStack, err = validateVisitField(scope, childDefs[0], fieldStack); err != nil {
 				return fieldStack, err
 			}
 		} else {
-			if childDefs[0].Required() {
+			if childDefs[0].Required() && scope.checks(CheckRequiredFields) && scope.checksTag(CheckRequiredFields, Tag(childDefs[0].Tag())) {
 				return fieldStack, RequiredTagMissing(Tag(childDefs[0].Tag()))
 			}
 		}
@@ -270,11 +282,15 @@ func validateVisitGroupField(fieldDef *datadictionary.FieldDef, fieldStack []Tag
 	return fieldStack, nil
 }
 
-func validateOrder(msg *Message) MessageRejectError {
+func validateOrder(scope validationScope, msg *Message) MessageRejectError {
 	inHeader := true
 	inTrailer := false
 	for _, field := range msg.fields {
 		t := field.tag
+		if !scope.checksTag(CheckFieldOrder, t) {
+			continue
+		}
+
 		switch {
 		case inHeader && t.IsHeader():
 		case inHeader && !t.IsHeader():
@@ -291,26 +307,26 @@ func validateOrder(msg *Message) MessageRejectError {
 	return nil
 }
 
-func validateRequired(transportDD *datadictionary.DataDictionary, appDD *datadictionary.DataDictionary, msgType string, message *Message) MessageRejectError {
-	if err := validateRequiredFieldMap(message, transportDD.Header.RequiredTags, message.Header.FieldMap); err != nil {
+func validateRequired(scope validationScope, transportDD *datadictionary.DataDictionary, appDD *datadictionary.DataDictionary, msgType string, message *Message) MessageRejectError {
+	if err := validateRequiredFieldMap(scope, transportDD.Header.RequiredTags, message.Header.FieldMap); err != nil {
 		return err
 	}
 
-	if err := validateRequiredFieldMap(message, appDD.Messages[msgType].RequiredTags, message.Body.FieldMap); err != nil {
+	if err := validateRequiredFieldMap(scope, appDD.Messages[msgType].RequiredTags, message.Body.FieldMap); err != nil {
 		return err
 	}
 
-	if err := validateRequiredFieldMap(message, transportDD.Trailer.RequiredTags, message.Trailer.FieldMap); err != nil {
+	if err := validateRequiredFieldMap(scope, transportDD.Trailer.RequiredTags, message.Trailer.FieldMap); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func validateRequiredFieldMap(_ *Message, requiredTags map[int]struct{}, fieldMap FieldMap) MessageRejectError {
+func validateRequiredFieldMap(scope validationScope, requiredTags map[int]struct{}, fieldMap FieldMap) MessageRejectError {
 	for required := range requiredTags {
 		requiredTag := Tag(required)
-		if !fieldMap.Has(requiredTag) {
+		if !fieldMap.Has(requiredTag) && scope.checksTag(CheckRequiredFields, requiredTag) {
 			return RequiredTagMissing(requiredTag)
 		}
 	}
@@ -318,7 +334,8 @@ func validateRequiredFieldMap(_ *Message, requiredTags map[int]struct{}, fieldMa
 	return nil
 }
 
-func validateFields(transportDD *datadictionary.DataDictionary,
+func validateFields(scope validationScope,
+	transportDD *datadictionary.DataDictionary,
 	appDD *datadictionary.DataDictionary,
 	settings ValidatorSettings,
 	msgType string,
@@ -327,15 +344,15 @@ func validateFields(transportDD *datadictionary.DataDictionary,
 	for _, field := range message.fields {
 		switch {
 		case field.tag.IsHeader():
-			if err := validateField(transportDD, settings, transportDD.Header.Tags, field); err != nil {
+			if err := validateField(scope, transportDD, settings, transportDD.Header.Tags, field); err != nil {
 				return err
 			}
 		case field.tag.IsTrailer():
-			if err := validateField(transportDD, settings, transportDD.Trailer.Tags, field); err != nil {
+			if err := validateField(scope, transportDD, settings, transportDD.Trailer.Tags, field); err != nil {
 				return err
 			}
 		default:
-			if err := validateField(appDD, settings, appDD.Messages[msgType].Tags, field); err != nil {
+			if err := validateField(scope, appDD, settings, appDD.Messages[msgType].Tags, field); err != nil {
 				return err
 			}
 		}
@@ -359,7 +376,8 @@ func checkFieldNotDefined(settings ValidatorSettings, field Tag) bool {
 	return !fail
 }
 
-func validateField(d *datadictionary.DataDictionary,
+func validateField(scope validationScope,
+	d *datadictionary.DataDictionary,
 	settings ValidatorSettings,
 	_ datadictionary.TagSet,
 	field TagValue,
@@ -369,7 +387,7 @@ func validateField(d *datadictionary.DataDictionary,
 	}
 
 	fieldType, isMessageField := getFieldType(d, int(field.tag))
-	if !isMessageField && !checkFieldNotDefined(settings, field.tag) {
+	if !isMessageField && !checkFieldNotDefined(settings, field.tag) && scope.checksTag(CheckUnknownFields, field.tag) {
 		return InvalidTagNumber(field.tag)
 	}
 
@@ -378,8 +396,8 @@ func validateField(d *datadictionary.DataDictionary,
 	}
 
 	allowedValues := d.FieldTypeByTag[int(field.tag)].Enums
-	if len(allowedValues) != 0 {
-		if _, validValue := allowedValues[string(field.value)]; !validValue {
+	if len(allowedValues) != 0 && scope.checksTag(CheckFieldValues, field.tag) {
+		if _, validValue := allowedValues[string(field.value)]; !validValue && !scope.acceptsEnumValue(field.tag, field.value) {
 			return ValueIsIncorrect(field.tag)
 		}
 	}
@@ -412,10 +430,6 @@ func validateField(d *datadictionary.DataDictionary,
 		fallthrough
 	case "UTCDATEONLY", "UTCDATE":
 		fallthrough
-	case "TZTIMEONLY":
-		fallthrough
-	case "TZTIMESTAMP":
-		fallthrough
 	case "STRING":
 		prototype = new(FIXString)
 
@@ -436,6 +450,12 @@ func validateField(d *datadictionary.DataDictionary,
 	case "UTCTIMESTAMP", "TIME":
 		prototype = new(FIXUTCTimestamp)
 
+	case "TZTIMESTAMP":
+		prototype = new(FIXTZTimestamp)
+
+	case "TZTIMEONLY":
+		prototype = new(FIXTZTimeOnly)
+
 	case "QTY", "QUANTITY":
 		fallthrough
 	case "AMT":
@@ -0,0 +1,355 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ValidationCheck is a check made by the Validator that a ValidationPolicy may disable.
+type ValidationCheck uint8
+
+const (
+	// CheckRequiredFields rejects messages missing fields required by the DataDictionary.
+	CheckRequiredFields ValidationCheck = 1 << iota
+
+	// CheckFieldOrder rejects header fields after the body, and body fields after the trailer, if
+	// ValidatorSettings.CheckFieldsOutOfOrder is set.
+	CheckFieldOrder
+
+	// CheckFieldValues rejects values not among the enumerated values of a field in the DataDictionary.
+	CheckFieldValues
+
+	// CheckUnknownFields rejects tags not defined in the DataDictionary, or not defined for the message, according
+	// to ValidatorSettings.AllowUnknownMessageFields and ValidatorSettings.CheckUserDefinedFields.
+	CheckUnknownFields
+)
+
+var validationCheckNames = map[string]ValidationCheck{
+	"Required": CheckRequiredFields,
+	"Order":    CheckFieldOrder,
+	"Values":   CheckFieldValues,
+	"Unknown":  CheckUnknownFields,
+}
+
+// EnumValidation is how the Validator treats a value that is not among the enumerated values of a field.
+type EnumValidation uint8
+
+const (
+	// EnumStrict rejects the message.
+	EnumStrict EnumValidation = iota
+
+	// EnumWarn accepts the message, and logs and publishes an EventValidationWarning of the session.
+	EnumWarn
+
+	// EnumLenient accepts the message.
+	EnumLenient
+)
+
+var enumValidationNames = map[string]EnumValidation{
+	"Strict":  EnumStrict,
+	"Warn":    EnumWarn,
+	"Lenient": EnumLenient,
+}
+
+// ValidationPolicy disables checks of the Validator for all messages, for messages of a MsgType, or for a tag.
+// It also holds the enumerated values of fields added to those of the DataDictionary, and how values that are not
+// enumerated are treated. A nil ValidationPolicy disables no checks.
+type ValidationPolicy struct {
+	disabled         ValidationCheck
+	disabledMsgTypes map[string]ValidationCheck
+	disabledTags     map[Tag]ValidationCheck
+
+	enumValidation     EnumValidation
+	enumValidationTags map[Tag]EnumValidation
+
+	// enumValues are the added enumerated values, which may be added while sessions validate messages.
+	enumValuesMu sync.RWMutex
+	enumValues   map[Tag]map[string]struct{}
+
+	// inherited are the policies merged into this one, whose enumerated values are valid as well.
+	inherited []*ValidationPolicy
+
+	// onUnknownEnum is called for values accepted by EnumWarn.
+	onUnknownEnum func(msgType string, tag Tag, value []byte)
+}
+
+// NewValidationPolicy returns a ValidationPolicy that disables no checks.
+func NewValidationPolicy() *ValidationPolicy {
+	return &ValidationPolicy{
+		disabledMsgTypes:   make(map[string]ValidationCheck),
+		disabledTags:       make(map[Tag]ValidationCheck),
+		enumValidationTags: make(map[Tag]EnumValidation),
+		enumValues:         make(map[Tag]map[string]struct{}),
+	}
+}
+
+// Disable disables checks for all messages.
+func (p *ValidationPolicy) Disable(checks ...ValidationCheck) *ValidationPolicy {
+	for _, check := range checks {
+		p.disabled |= check
+	}
+
+	return p
+}
+
+// DisableForMsgType disables checks for messages of msgType.
+func (p *ValidationPolicy) DisableForMsgType(msgType string, checks ...ValidationCheck) *ValidationPolicy {
+	for _, check := range checks {
+		p.disabledMsgTypes[msgType] |= check
+	}
+
+	return p
+}
+
+// DisableForTag disables checks of the field with tag in all messages. CheckFieldOrder is disabled for the field,
+// CheckRequiredFields stops the field from being required, CheckFieldValues accepts any value of the field, and
+// CheckUnknownFields accepts the field if it is not defined.
+func (p *ValidationPolicy) DisableForTag(tag Tag, checks ...ValidationCheck) *ValidationPolicy {
+	for _, check := range checks {
+		p.disabledTags[tag] |= check
+	}
+
+	return p
+}
+
+// SetEnumValidation sets how values that are not among the enumerated values of a field are treated, EnumStrict by
+// default. The CheckFieldValues check must be enabled for values to be checked at all.
+func (p *ValidationPolicy) SetEnumValidation(enumValidation EnumValidation) *ValidationPolicy {
+	p.enumValidation = enumValidation
+	return p
+}
+
+// SetEnumValidationForTag sets how values of the field with tag that are not among its enumerated values are treated,
+// in place of the EnumValidation of all fields.
+func (p *ValidationPolicy) SetEnumValidationForTag(tag Tag, enumValidation EnumValidation) *ValidationPolicy {
+	p.enumValidationTags[tag] = enumValidation
+	return p
+}
+
+// AddEnumValues adds values to the enumerated values of the field with tag in the DataDictionary, e.g. the values a
+// venue adds to the standard ones. Unlike the other methods it may be called while sessions are running, also on the
+// ValidationPolicy of an Acceptor or Initiator.
+func (p *ValidationPolicy) AddEnumValues(tag Tag, values ...string) *ValidationPolicy {
+	p.enumValuesMu.Lock()
+	defer p.enumValuesMu.Unlock()
+
+	if p.enumValues[tag] == nil {
+		p.enumValues[tag] = make(map[string]struct{}, len(values))
+	}
+	for _, value := range values {
+		p.enumValues[tag][value] = struct{}{}
+	}
+
+	return p
+}
+
+// merge disables the checks disabled by other as well, and treats values that are not enumerated as leniently as
+// other. The enumerated values added to other are valid, including those added later.
+func (p *ValidationPolicy) merge(other *ValidationPolicy) {
+	if other == nil {
+		return
+	}
+
+	p.disabled |= other.disabled
+	for msgType, checks := range other.disabledMsgTypes {
+		p.disabledMsgTypes[msgType] |= checks
+	}
+	for tag, checks := range other.disabledTags {
+		p.disabledTags[tag] |= checks
+	}
+
+	if other.enumValidation > p.enumValidation {
+		p.enumValidation = other.enumValidation
+	}
+	for tag, enumValidation := range other.enumValidationTags {
+		if current, ok := p.enumValidationTags[tag]; !ok || enumValidation > current {
+			p.enumValidationTags[tag] = enumValidation
+		}
+	}
+	p.inherited = append(p.inherited, other)
+}
+
+// hasEnumValue returns true if value was added to the enumerated values of the field with tag.
+func (p *ValidationPolicy) hasEnumValue(tag Tag, value []byte) bool {
+	p.enumValuesMu.RLock()
+	_, ok := p.enumValues[tag][string(value)]
+	p.enumValuesMu.RUnlock()
+	if ok {
+		return true
+	}
+
+	for _, inherited := range p.inherited {
+		if inherited.hasEnumValue(tag, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// acceptsEnumValue returns true if value, which is not among the enumerated values of the field with tag in the
+// DataDictionary, is accepted in messages of msgType.
+func (p *ValidationPolicy) acceptsEnumValue(msgType string, tag Tag, value []byte) bool {
+	if p == nil {
+		return false
+	}
+
+	if p.hasEnumValue(tag, value) {
+		return true
+	}
+
+	enumValidation, ok := p.enumValidationTags[tag]
+	if !ok {
+		enumValidation = p.enumValidation
+	}
+
+	switch enumValidation {
+	case EnumWarn:
+		if p.onUnknownEnum != nil {
+			p.onUnknownEnum(msgType, tag, value)
+		}
+		return true
+	case EnumLenient:
+		return true
+	}
+
+	return false
+}
+
+// checks returns true if check is made for messages of msgType.
+func (p *ValidationPolicy) checks(check ValidationCheck, msgType string) bool {
+	if p == nil {
+		return true
+	}
+
+	return (p.disabled|p.disabledMsgTypes[msgType])&check == 0
+}
+
+// checksTag returns true if check is made for the field with tag in messages of msgType.
+func (p *ValidationPolicy) checksTag(check ValidationCheck, msgType string, tag Tag) bool {
+	if p == nil {
+		return true
+	}
+
+	return p.checks(check, msgType) && p.disabledTags[tag]&check == 0
+}
+
+// validationScope is a ValidationPolicy as it applies to messages of a MsgType.
+type validationScope struct {
+	policy  *ValidationPolicy
+	msgType string
+}
+
+func (s validationScope) checks(check ValidationCheck) bool {
+	return s.policy.checks(check, s.msgType)
+}
+
+func (s validationScope) checksTag(check ValidationCheck, tag Tag) bool {
+	return s.policy.checksTag(check, s.msgType, tag)
+}
+
+func (s validationScope) acceptsEnumValue(tag Tag, value []byte) bool {
+	return s.policy.acceptsEnumValue(s.msgType, tag, value)
+}
+
+// parseValidationChecks parses a comma separated list of the names of ValidationChecks.
+func parseValidationChecks(str string) (ValidationCheck, error) {
+	var checks ValidationCheck
+	for _, name := range strings.Split(str, ",") {
+		check, ok := validationCheckNames[strings.TrimSpace(name)]
+		if !ok {
+			return 0, fmt.Errorf("unknown validation check %q", strings.TrimSpace(name))
+		}
+		checks |= check
+	}
+
+	return checks, nil
+}
+
+// parseValidationOverrides parses semicolon separated overrides of the form key=Check,Check, calling add for each.
+func parseValidationOverrides(str string, add func(key string, checks ValidationCheck) error) error {
+	for _, override := range strings.Split(str, ";") {
+		if override = strings.TrimSpace(override); override == "" {
+			continue
+		}
+
+		key, names, ok := strings.Cut(override, "=")
+		if !ok {
+			return fmt.Errorf("validation override %q must be of the form key=Check,Check", override)
+		}
+
+		checks, err := parseValidationChecks(names)
+		if err != nil {
+			return err
+		}
+
+		if err := add(strings.TrimSpace(key), checks); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseMsgTypeOverrides disables the checks of each MsgType in str, e.g. "D=Required,Values;8=Unknown".
+func (p *ValidationPolicy) parseMsgTypeOverrides(str string) error {
+	return parseValidationOverrides(str, func(msgType string, checks ValidationCheck) error {
+		p.disabledMsgTypes[msgType] |= checks
+		return nil
+	})
+}
+
+// parseTagOverrides disables the checks of each tag in str, e.g. "9001=Unknown;58=Values".
+func (p *ValidationPolicy) parseTagOverrides(str string) error {
+	return parseValidationOverrides(str, func(key string, checks ValidationCheck) error {
+		tag, err := strconv.Atoi(key)
+		if err != nil {
+			return fmt.Errorf("validation override tag %q is not a number", key)
+		}
+
+		p.disabledTags[Tag(tag)] |= checks
+		return nil
+	})
+}
+
+// parseEnumValues adds the enumerated values of each tag in str, e.g. "40=X,Y;59=Z".
+func (p *ValidationPolicy) parseEnumValues(str string) error {
+	for _, entry := range strings.Split(str, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		key, values, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("enum values %q must be of the form tag=value,value", entry)
+		}
+
+		tag, err := strconv.Atoi(strings.TrimSpace(key))
+		if err != nil {
+			return fmt.Errorf("enum values tag %q is not a number", key)
+		}
+
+		for _, value := range strings.Split(values, ",") {
+			p.AddEnumValues(Tag(tag), strings.TrimSpace(value))
+		}
+	}
+
+	return nil
+}
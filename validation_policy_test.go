@@ -0,0 +1,125 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationPolicyNil(t *testing.T) {
+	var policy *ValidationPolicy
+	assert.True(t, policy.checks(CheckRequiredFields, "D"))
+	assert.True(t, policy.checksTag(CheckFieldValues, "D", Tag(21)))
+}
+
+func TestValidationPolicyDisable(t *testing.T) {
+	policy := NewValidationPolicy().
+		Disable(CheckFieldOrder).
+		DisableForMsgType("D", CheckRequiredFields, CheckFieldValues).
+		DisableForTag(Tag(58), CheckUnknownFields)
+
+	assert.False(t, policy.checks(CheckFieldOrder, "8"))
+	assert.False(t, policy.checks(CheckRequiredFields, "D"))
+	assert.False(t, policy.checks(CheckFieldValues, "D"))
+	assert.True(t, policy.checks(CheckRequiredFields, "8"))
+	assert.True(t, policy.checks(CheckUnknownFields, "D"))
+	assert.False(t, policy.checksTag(CheckUnknownFields, "8", Tag(58)))
+	assert.True(t, policy.checksTag(CheckUnknownFields, "8", Tag(59)))
+	assert.False(t, policy.checksTag(CheckFieldValues, "D", Tag(59)))
+}
+
+func TestValidationPolicyMerge(t *testing.T) {
+	policy := NewValidationPolicy().DisableForTag(Tag(58), CheckFieldValues)
+	policy.merge(NewValidationPolicy().
+		Disable(CheckFieldOrder).
+		DisableForMsgType("D", CheckRequiredFields).
+		DisableForTag(Tag(58), CheckUnknownFields))
+	policy.merge(nil)
+
+	assert.False(t, policy.checks(CheckFieldOrder, "8"))
+	assert.False(t, policy.checks(CheckRequiredFields, "D"))
+	assert.False(t, policy.checksTag(CheckFieldValues, "8", Tag(58)))
+	assert.False(t, policy.checksTag(CheckUnknownFields, "8", Tag(58)))
+	assert.True(t, policy.checksTag(CheckRequiredFields, "8", Tag(58)))
+}
+
+func TestValidationPolicyParseOverrides(t *testing.T) {
+	policy := NewValidationPolicy()
+	require.Nil(t, policy.parseMsgTypeOverrides("D=Required, Values; 8=Unknown;"))
+	require.Nil(t, policy.parseTagOverrides("9001=Unknown;58=Order"))
+
+	assert.Equal(t, CheckRequiredFields|CheckFieldValues, policy.disabledMsgTypes["D"])
+	assert.Equal(t, CheckUnknownFields, policy.disabledMsgTypes["8"])
+	assert.Equal(t, CheckUnknownFields, policy.disabledTags[Tag(9001)])
+	assert.Equal(t, CheckFieldOrder, policy.disabledTags[Tag(58)])
+
+	assert.NotNil(t, policy.parseMsgTypeOverrides("D"))
+	assert.NotNil(t, policy.parseMsgTypeOverrides("D=Everything"))
+	assert.NotNil(t, policy.parseTagOverrides("Text=Values"))
+}
+
+func TestValidationPolicyEnumValues(t *testing.T) {
+	var warnings []string
+	policy := NewValidationPolicy().AddEnumValues(Tag(40), "X", "Y").SetEnumValidationForTag(Tag(59), EnumWarn)
+	policy.onUnknownEnum = func(msgType string, tag Tag, value []byte) {
+		warnings = append(warnings, fmt.Sprintf("%v:%v=%s", msgType, tag, value))
+	}
+
+	assert.True(t, policy.acceptsEnumValue("D", Tag(40), []byte("X")))
+	assert.False(t, policy.acceptsEnumValue("D", Tag(40), []byte("Z")))
+	assert.False(t, policy.acceptsEnumValue("D", Tag(54), []byte("X")))
+	assert.True(t, policy.acceptsEnumValue("D", Tag(59), []byte("Z")))
+	assert.Equal(t, []string{"D:59=Z"}, warnings)
+
+	policy.SetEnumValidation(EnumLenient)
+	assert.True(t, policy.acceptsEnumValue("D", Tag(54), []byte("X")))
+	assert.Len(t, warnings, 1)
+
+	var nilPolicy *ValidationPolicy
+	assert.False(t, nilPolicy.acceptsEnumValue("D", Tag(40), []byte("X")))
+}
+
+func TestValidationPolicyMergeEnumValues(t *testing.T) {
+	engine := NewValidationPolicy().SetEnumValidationForTag(Tag(59), EnumLenient)
+	policy := NewValidationPolicy().SetEnumValidation(EnumWarn)
+	policy.merge(engine.SetEnumValidation(EnumStrict))
+
+	assert.Equal(t, EnumWarn, policy.enumValidation)
+	assert.Equal(t, EnumLenient, policy.enumValidationTags[Tag(59)])
+
+	// Values added to a merged policy later are valid as well.
+	policy.SetEnumValidation(EnumStrict)
+	assert.False(t, policy.acceptsEnumValue("D", Tag(40), []byte("X")))
+	engine.AddEnumValues(Tag(40), "X")
+	assert.True(t, policy.acceptsEnumValue("D", Tag(40), []byte("X")))
+}
+
+func TestValidationPolicyParseEnumValues(t *testing.T) {
+	policy := NewValidationPolicy()
+	require.Nil(t, policy.parseEnumValues("40=X, Y; 59=Z;"))
+
+	assert.True(t, policy.hasEnumValue(Tag(40), []byte("X")))
+	assert.True(t, policy.hasEnumValue(Tag(40), []byte("Y")))
+	assert.True(t, policy.hasEnumValue(Tag(59), []byte("Z")))
+	assert.False(t, policy.hasEnumValue(Tag(59), []byte("X")))
+
+	assert.NotNil(t, policy.parseEnumValues("40"))
+	assert.NotNil(t, policy.parseEnumValues("OrdType=X"))
+}
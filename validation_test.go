@@ -50,6 +50,7 @@ func TestValidate(t *testing.T) {
 		tcValueIsIncorrectFixT(),
 		tcIncorrectDataFormatForValue(),
 		tcIncorrectDataFormatForValueFixT(),
+		tcIncorrectDataFormatForTZTimeOnlyFixT(),
 		tcTagSpecifiedOutOfRequiredOrderHeader(),
 		tcTagSpecifiedOutOfRequiredOrderHeaderFixT(),
 		tcTagSpecifiedOutOfRequiredOrderTrailer(),
@@ -83,6 +84,15 @@ func TestValidate(t *testing.T) {
 		tcCheckUserDefinedFieldsDisabled(),
 		tcCheckUserDefinedFieldsDisabledFixT(),
 		tcMultipleRepeatingGroupFields(),
+		tcValueIsIncorrectDisabledForTag(),
+		tcValueIsIncorrectDisabledForOtherTag(),
+		tcValueIsIncorrectAddedEnumValue(),
+		tcValueIsIncorrectEnumWarn(),
+		tcValueIsIncorrectEnumLenientForOtherTag(),
+		tcFieldNotFoundBodyDisabledForMsgType(),
+		tcFieldNotFoundBodyDisabledForOtherMsgType(),
+		tcTagSpecifiedOutOfRequiredOrderDisabledByPolicy(),
+		tcInvalidTagCheckDisabledForTag(),
 	}
 
 	msg := NewMessage()
@@ -634,6 +644,24 @@ func tcIncorrectDataFormatForValueFixT() validateTest {
 	}
 }
 
+func tcIncorrectDataFormatForTZTimeOnlyFixT() validateTest {
+	tDict, _ := datadictionary.Parse("spec/FIXT11.xml")
+	appDict, _ := datadictionary.Parse("spec/FIX50SP2.xml")
+	validator := NewValidator(defaultValidatorSettings, appDict, tDict)
+	builder := createFIX50SP2NewOrderSingle()
+	tag := Tag(1079) // MaturityTime
+	builder.Body.SetField(tag, FIXString("07:39"))
+	msgBytes := builder.build()
+
+	return validateTest{
+		TestName:             "IncorrectDataFormatForTZTimeOnly FIXT",
+		Validator:            validator,
+		MessageBytes:         msgBytes,
+		ExpectedRejectReason: rejectReasonIncorrectDataFormatForValue,
+		ExpectedRefTagID:     &tag,
+	}
+}
+
 func tcTagSpecifiedOutOfRequiredOrderHeader() validateTest {
 	dict, _ := datadictionary.Parse("spec/FIX40.xml")
 	validator := NewValidator(defaultValidatorSettings, dict, nil)
@@ -1105,6 +1133,193 @@ func tcMultipleRepeatingGroupFields() validateTest {
 	}
 }
 
+func tcValueIsIncorrectDisabledForTag() validateTest {
+	dict, _ := datadictionary.Parse("spec/FIX40.xml")
+	customValidatorSettings := defaultValidatorSettings
+	customValidatorSettings.Policy = NewValidationPolicy().DisableForTag(Tag(21), CheckFieldValues)
+	validator := NewValidator(customValidatorSettings, dict, nil)
+
+	builder := createFIX40NewOrderSingle()
+	builder.Body.SetField(Tag(21), FIXString("4"))
+	msgBytes := builder.build()
+
+	return validateTest{
+		TestName:          "ValueIsIncorrect - Disabled for tag",
+		Validator:         validator,
+		MessageBytes:      msgBytes,
+		DoNotExpectReject: true,
+	}
+}
+
+func tcValueIsIncorrectDisabledForOtherTag() validateTest {
+	dict, _ := datadictionary.Parse("spec/FIX40.xml")
+	customValidatorSettings := defaultValidatorSettings
+	customValidatorSettings.Policy = NewValidationPolicy().DisableForTag(Tag(21), CheckUnknownFields).
+		DisableForTag(Tag(54), CheckFieldValues)
+	validator := NewValidator(customValidatorSettings, dict, nil)
+
+	tag := Tag(21)
+	builder := createFIX40NewOrderSingle()
+	builder.Body.SetField(tag, FIXString("4"))
+	msgBytes := builder.build()
+
+	return validateTest{
+		TestName:             "ValueIsIncorrect - Disabled for other tag",
+		Validator:            validator,
+		MessageBytes:         msgBytes,
+		ExpectedRejectReason: rejectReasonValueIsIncorrect,
+		ExpectedRefTagID:     &tag,
+	}
+}
+
+func tcValueIsIncorrectAddedEnumValue() validateTest {
+	dict, _ := datadictionary.Parse("spec/FIX40.xml")
+	customValidatorSettings := defaultValidatorSettings
+	customValidatorSettings.Policy = NewValidationPolicy().AddEnumValues(Tag(21), "4", "5")
+	validator := NewValidator(customValidatorSettings, dict, nil)
+
+	builder := createFIX40NewOrderSingle()
+	builder.Body.SetField(Tag(21), FIXString("4"))
+	msgBytes := builder.build()
+
+	return validateTest{
+		TestName:          "ValueIsIncorrect - Added enum value",
+		Validator:         validator,
+		MessageBytes:      msgBytes,
+		DoNotExpectReject: true,
+	}
+}
+
+func tcValueIsIncorrectEnumWarn() validateTest {
+	dict, _ := datadictionary.Parse("spec/FIX40.xml")
+	customValidatorSettings := defaultValidatorSettings
+	customValidatorSettings.Policy = NewValidationPolicy().SetEnumValidation(EnumWarn)
+	validator := NewValidator(customValidatorSettings, dict, nil)
+
+	builder := createFIX40NewOrderSingle()
+	builder.Body.SetField(Tag(21), FIXString("4"))
+	msgBytes := builder.build()
+
+	return validateTest{
+		TestName:          "ValueIsIncorrect - Enum warn",
+		Validator:         validator,
+		MessageBytes:      msgBytes,
+		DoNotExpectReject: true,
+	}
+}
+
+func tcValueIsIncorrectEnumLenientForOtherTag() validateTest {
+	dict, _ := datadictionary.Parse("spec/FIX40.xml")
+	customValidatorSettings := defaultValidatorSettings
+	customValidatorSettings.Policy = NewValidationPolicy().SetEnumValidationForTag(Tag(54), EnumLenient).
+		AddEnumValues(Tag(54), "4")
+	validator := NewValidator(customValidatorSettings, dict, nil)
+
+	tag := Tag(21)
+	builder := createFIX40NewOrderSingle()
+	builder.Body.SetField(tag, FIXString("4"))
+	msgBytes := builder.build()
+
+	return validateTest{
+		TestName:             "ValueIsIncorrect - Enum lenient for other tag",
+		Validator:            validator,
+		MessageBytes:         msgBytes,
+		ExpectedRejectReason: rejectReasonValueIsIncorrect,
+		ExpectedRefTagID:     &tag,
+	}
+}
+
+func createFIX40NewOrderSingleWithoutOrdType() *Message {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString("D")).
+		SetField(tagBeginString, FIXString("FIX.4.0")).
+		SetField(tagBodyLength, FIXString("0")).
+		SetField(tagSenderCompID, FIXString("0")).
+		SetField(tagTargetCompID, FIXString("0")).
+		SetField(tagMsgSeqNum, FIXString("0")).
+		SetField(tagSendingTime, FIXUTCTimestamp{Time: time.Now()})
+	msg.Trailer.SetField(tagCheckSum, FIXString("000"))
+
+	msg.Body.SetField(Tag(11), FIXString("A")).
+		SetField(Tag(21), FIXString("1")).
+		SetField(Tag(55), FIXString("A")).
+		SetField(Tag(54), FIXString("1")).
+		SetField(Tag(38), FIXString("5"))
+
+	return msg
+}
+
+func tcFieldNotFoundBodyDisabledForMsgType() validateTest {
+	dict, _ := datadictionary.Parse("spec/FIX40.xml")
+	customValidatorSettings := defaultValidatorSettings
+	customValidatorSettings.Policy = NewValidationPolicy().DisableForMsgType("D", CheckRequiredFields)
+	validator := NewValidator(customValidatorSettings, dict, nil)
+
+	msgBytes := createFIX40NewOrderSingleWithoutOrdType().build()
+
+	return validateTest{
+		TestName:          "FieldNotFoundBody - Disabled for MsgType",
+		Validator:         validator,
+		MessageBytes:      msgBytes,
+		DoNotExpectReject: true,
+	}
+}
+
+func tcFieldNotFoundBodyDisabledForOtherMsgType() validateTest {
+	dict, _ := datadictionary.Parse("spec/FIX40.xml")
+	customValidatorSettings := defaultValidatorSettings
+	customValidatorSettings.Policy = NewValidationPolicy().DisableForMsgType("8", CheckRequiredFields)
+	validator := NewValidator(customValidatorSettings, dict, nil)
+
+	tag := Tag(40)
+	msgBytes := createFIX40NewOrderSingleWithoutOrdType().build()
+
+	return validateTest{
+		TestName:             "FieldNotFoundBody - Disabled for other MsgType",
+		Validator:            validator,
+		MessageBytes:         msgBytes,
+		ExpectedRejectReason: rejectReasonRequiredTagMissing,
+		ExpectedRefTagID:     &tag,
+	}
+}
+
+func tcTagSpecifiedOutOfRequiredOrderDisabledByPolicy() validateTest {
+	dict, _ := datadictionary.Parse("spec/FIX40.xml")
+	customValidatorSettings := defaultValidatorSettings
+	customValidatorSettings.Policy = NewValidationPolicy().Disable(CheckFieldOrder)
+	validator := NewValidator(customValidatorSettings, dict, nil)
+
+	builder := createFIX40NewOrderSingle()
+	// Should be in header.
+	builder.Body.SetField(tagOnBehalfOfCompID, FIXString("CWB"))
+	msgBytes := builder.build()
+
+	return validateTest{
+		TestName:          "Tag specified out of required order - Disabled by policy",
+		Validator:         validator,
+		MessageBytes:      msgBytes,
+		DoNotExpectReject: true,
+	}
+}
+
+func tcInvalidTagCheckDisabledForTag() validateTest {
+	dict, _ := datadictionary.Parse("spec/FIX40.xml")
+	customValidatorSettings := defaultValidatorSettings
+	customValidatorSettings.Policy = NewValidationPolicy().DisableForTag(Tag(9999), CheckUnknownFields)
+	validator := NewValidator(customValidatorSettings, dict, nil)
+
+	builder := createFIX40NewOrderSingle()
+	builder.Body.SetField(Tag(9999), FIXString("hello"))
+	msgBytes := builder.build()
+
+	return validateTest{
+		TestName:          "Invalid Tag Check - Disabled for tag",
+		Validator:         validator,
+		MessageBytes:      msgBytes,
+		DoNotExpectReject: true,
+	}
+}
+
 func TestValidateVisitField(t *testing.T) {
 	fieldType0 := datadictionary.NewFieldType("myfield", 11, "STRING")
 	fieldDef0 := &datadictionary.FieldDef{FieldType: fieldType0}
@@ -1182,7 +1397,7 @@ func TestValidateVisitField(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		remFields, reject := validateVisitField(test.fieldDef, test.fields)
+		remFields, reject := validateVisitField(validationScope{}, test.fieldDef, test.fields)
 
 		if test.expectReject {
 			if reject == nil {
@@ -0,0 +1,97 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+// zeroAllocParser parses inbound messages into Messages taken from the message pool of NewMessage. Once a message
+// has been processed it is released, so that its Message, field maps and field slice are reused for a later message
+// and steady state parsing does not allocate. Field values are byte slice views of the raw message.
+type zeroAllocParser struct{}
+
+func newZeroAllocParser() *zeroAllocParser {
+	return &zeroAllocParser{}
+}
+
+// parse parses rawMessage into a pooled Message, which must be given back with release once processed.
+func (p *zeroAllocParser) parse(
+	rawMessage *bytes.Buffer,
+	transportDataDictionary *datadictionary.DataDictionary,
+	appDataDictionary *datadictionary.DataDictionary,
+	appDataDictionaries map[string]*datadictionary.DataDictionary,
+	preserveOrder bool,
+) (*Message, error) {
+	msg := NewMessage()
+	msg.reuse = true
+
+	mp := msgParser{
+		msg:                     msg,
+		transportDataDictionary: transportDataDictionary,
+		appDataDictionary:       appDataDictionary,
+		appDataDictionaries:     appDataDictionaries,
+		rawBytes:                rawMessage.Bytes(),
+		preserveOrder:           preserveOrder,
+	}
+	msg.rawMessage = rawMessage
+
+	if err := doParsing(&mp); err != nil {
+		p.release(msg)
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// release returns msg to the message pool, unless it was detached to be retained.
+func (p *zeroAllocParser) release(msg *Message) {
+	if msg.reuse {
+		msg.Release()
+	}
+}
+
+// detach takes the message out of reuse by the zero allocation parser, for messages retained after they are
+// processed.
+func (m *Message) detach() *Message {
+	m.reuse = false
+	return m
+}
+
+// parseIncoming parses an inbound message, with the zero allocation parser if ZeroAllocParser is set.
+func (s *session) parseIncoming(rawMessage *bytes.Buffer) (*Message, error) {
+	appDataDictionary := s.appDataDictionaryFor(s.inboundApplVerID())
+	if s.zeroAllocParser != nil {
+		return s.zeroAllocParser.parse(rawMessage, s.transportDataDictionary, appDataDictionary, s.appDataDictionaries, s.PreserveMessageFieldsOrder)
+	}
+
+	msg := NewMessage()
+	if err := parseMessage(msg, rawMessage, s.transportDataDictionary, appDataDictionary, s.appDataDictionaries, s.PreserveMessageFieldsOrder); err != nil {
+		msg.Release()
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// releaseIncoming releases an inbound message once processed, for reuse by the zero allocation parser.
+func (s *session) releaseIncoming(msg *Message) {
+	if s.zeroAllocParser != nil {
+		s.zeroAllocParser.release(msg)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const zeroAllocTestMsg = "8=FIX.4.2\x019=104\x0135=D\x0134=2\x0149=TW\x0152=20140515-19:49:56.659\x0156=ISLD\x0111=100\x0121=1\x0140=1\x0154=1\x0155=TSLA\x0160=00010101-00:00:00.000\x0110=039\x01"
+
+func BenchmarkParseNewMessage(b *testing.B) {
+	rawMsg := bytes.NewBufferString(zeroAllocTestMsg)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg := NewMessage()
+		_ = ParseMessage(msg, rawMsg)
+	}
+}
+
+func BenchmarkZeroAllocParser(b *testing.B) {
+	rawMsg := bytes.NewBufferString(zeroAllocTestMsg)
+	p := newZeroAllocParser()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg, err := p.parse(rawMsg, nil, nil, nil, false)
+		if err == nil {
+			p.release(msg)
+		}
+	}
+}
+
+func TestZeroAllocParserParse(t *testing.T) {
+	p := newZeroAllocParser()
+
+	msg, err := p.parse(bytes.NewBufferString(zeroAllocTestMsg), nil, nil, nil, false)
+	require.Nil(t, err)
+	assert.True(t, msg.reuse)
+
+	msgType, ferr := msg.MsgType()
+	require.Nil(t, ferr)
+	assert.Equal(t, "D", msgType)
+
+	symbol, ferr := msg.Body.GetBytes(Tag(55))
+	require.Nil(t, ferr)
+	assert.Equal(t, "TSLA", string(symbol))
+	assert.Equal(t, zeroAllocTestMsg, msg.String())
+
+	p.release(msg)
+	assert.Nil(t, msg.rawMessage)
+	assert.Nil(t, msg.ctx)
+}
+
+func TestZeroAllocParserParseError(t *testing.T) {
+	p := newZeroAllocParser()
+
+	msg, err := p.parse(bytes.NewBufferString("8=FIX.4.2"), nil, nil, nil, false)
+	assert.NotNil(t, err)
+	assert.Nil(t, msg)
+}
+
+func TestZeroAllocParserDetach(t *testing.T) {
+	p := newZeroAllocParser()
+
+	msg, err := p.parse(bytes.NewBufferString(zeroAllocTestMsg), nil, nil, nil, false)
+	require.Nil(t, err)
+
+	p.release(msg.detach())
+	assert.False(t, msg.reuse)
+	assert.Equal(t, zeroAllocTestMsg, msg.String())
+}
+
+func TestZeroAllocParserAllocations(t *testing.T) {
+	rawMsg := bytes.NewBufferString(zeroAllocTestMsg)
+	p := newZeroAllocParser()
+
+	msg, err := p.parse(rawMsg, nil, nil, nil, false)
+	require.Nil(t, err)
+	p.release(msg)
+
+	pooled := testing.AllocsPerRun(100, func() {
+		if msg, err := p.parse(rawMsg, nil, nil, nil, false); err == nil {
+			p.release(msg)
+		}
+	})
+	unpooled := testing.AllocsPerRun(100, func() {
+		_ = ParseMessage(NewMessage(), rawMsg)
+	})
+	assert.Less(t, pooled, unpooled)
+}
+
+type ZeroAllocParserTestSuite struct {
+	SessionSuiteRig
+	received *Message
+	reused   bool
+}
+
+func TestZeroAllocParserTestSuite(t *testing.T) {
+	suite.Run(t, new(ZeroAllocParserTestSuite))
+}
+
+func (s *ZeroAllocParserTestSuite) SetupTest() {
+	s.Init()
+	s.session.zeroAllocParser = newZeroAllocParser()
+	s.session.useMiddleware([]InboundMiddleware{func(next InboundHandler) InboundHandler {
+		return func(msg *Message, sessionID SessionID) MessageRejectError {
+			s.received, s.reused = msg, msg.reuse
+			return next(msg, sessionID)
+		}
+	}}, nil)
+	s.session.State = inSession{}
+}
+
+func (s *ZeroAllocParserTestSuite) TestIncomingReleasesMessage() {
+	s.MockApp.On("FromApp").Return(nil)
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(s.NewOrderSingle().build())})
+	s.MockApp.AssertExpectations(s.T())
+
+	s.Require().NotNil(s.received)
+	s.True(s.reused)
+	s.Nil(s.received.rawMessage, "message should be released once processed")
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *ZeroAllocParserTestSuite) TestIncomingStashedMessageDetached() {
+	s.MessageFactory.seqNum = 5
+	s.MockApp.On("ToAdmin")
+
+	raw := s.NewOrderSingle().build()
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(raw)})
+	s.MockApp.AssertExpectations(s.T())
+
+	resendState, ok := s.session.State.(resendState)
+	s.Require().True(ok)
+	stashedMsg, ok := resendState.messageStash[6]
+	s.Require().True(ok)
+	s.False(stashedMsg.reuse)
+	s.Equal(string(raw), stashedMsg.String())
+}